@@ -7,6 +7,13 @@ SCRIPT_DIR=$(dirname "$(readlink -f "$0")")
 DEFAULT_CSMS_DIR="${SCRIPT_DIR}"/..
 CSMS_DIR="${1:-$DEFAULT_CSMS_DIR}"
 
+# Set CSMS_MODE=standalone to run the gateway and manager in a single process without an MQTT broker
+if [ "${CSMS_MODE}" = "standalone" ]; then
+    CSMS_COMPOSE_FILE="docker-compose-standalone.yml"
+else
+    CSMS_COMPOSE_FILE="docker-compose.yml"
+fi
+
 # Define paths relative to the script's location
 EVEREST_DIR="$CSMS_DIR/e2e_tests"
 TEST_DIR="$CSMS_DIR/e2e_tests/test_driver"
@@ -19,7 +26,7 @@ start_docker_compose_for_maeve_csms() {
     chmod 755 $CSMS_DIR/config/certificates/csms.key
     export UID=$(id -u)
     export GID=$(id -g)
-    docker-compose up -d
+    docker-compose -f "$CSMS_COMPOSE_FILE" up -d
     if [ $? -eq 0 ]; then
         echo "Docker Compose started successfully"
     else
@@ -51,7 +58,7 @@ stop_docker_compose_for_everest() {
 }
 
 stop_docker_compose_for_maeve_csms() {
-    cd "$CSMS_DIR" && docker-compose down
+    cd "$CSMS_DIR" && docker-compose -f "$CSMS_COMPOSE_FILE" down
 }
 
 # Function to check health endpoint
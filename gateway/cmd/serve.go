@@ -4,8 +4,6 @@ package cmd
 
 import (
 	"context"
-	"crypto/tls"
-	"crypto/x509"
 	"fmt"
 	"github.com/spf13/cobra"
 	"github.com/subnova/slog-exporter/slogtrace"
@@ -23,11 +21,15 @@ import (
 	"google.golang.org/grpc/credentials/insecure"
 	"net/url"
 	"os"
+	"os/signal"
+	"syscall"
 	"time"
 )
 
 var (
+	transportType     string
 	mqttAddr          string
+	natsAddr          string
 	wsAddr            string
 	wssAddr           string
 	statusAddr        string
@@ -36,7 +38,32 @@ var (
 	tlsTrustCert      []string
 	orgNames          []string
 	managerApiAddr    string
+	managerApiKey     string
 	trustProxyHeaders bool
+	gatewayId         string
+	registryCacheTtl  time.Duration
+	registryNegTtl    time.Duration
+	registryStaleTtl  time.Duration
+	registryTimeout   time.Duration
+	maxConnections    int
+	sourceRate        float64
+	sourceBurst       int
+	reconnectRate     float64
+	reconnectBurst    int
+	messageRate       float64
+	messageBurst      int
+	authLockout       bool
+	revocationCheck   string
+	crlFiles          []string
+	revocationOCSP    bool
+	revocationCRLDP   bool
+	revocationTtl     time.Duration
+	revocationRecheck time.Duration
+	drainTimeout      time.Duration
+	drainBatchSize    int
+	drainInterval     time.Duration
+	drainJitter       time.Duration
+	drainCallTimeout  time.Duration
 	otelCollectorAddr string
 	logFormat         string
 )
@@ -130,65 +157,108 @@ var serveCmd = &cobra.Command{
 
 		tracer := otel.Tracer("gateway")
 
-		brokerUrl, err := url.Parse(mqttAddr)
-		if err != nil {
-			return fmt.Errorf("parsing mqtt broker url: %v", err)
+		var bus server.Bus
+		switch transportType {
+		case "mqtt":
+			brokerUrl, err := url.Parse(mqttAddr)
+			if err != nil {
+				return fmt.Errorf("parsing mqtt broker url: %v", err)
+			}
+			bus = server.NewMqttBus([]*url.URL{brokerUrl}, "cs", 1*time.Second, 10*time.Second, tracer)
+		case "nats":
+			natsBus := server.NewNatsBus([]string{natsAddr}, "cs", tracer)
+			defer func() {
+				err := natsBus.Close()
+				if err != nil {
+					slog.Error("closing nats connection", "error", err)
+				}
+			}()
+			bus = natsBus
+		default:
+			return fmt.Errorf("unknown transport type: %s", transportType)
 		}
 
-		remoteRegistry := registry.RemoteRegistry{
+		managerRegistry := registry.RemoteRegistry{
 			ManagerApiAddr: managerApiAddr,
+			ManagerApiKey:  managerApiKey,
 		}
-		statusServer := server.New("status", statusAddr, nil, server.NewStatusHandler())
-		websocketHandler := server.NewWebsocketHandler(
-			server.WithMqttBrokerUrl(brokerUrl),
-			server.WithMqttTopicPrefix("cs"),
+		remoteRegistry := registry.NewCachingRegistry(
+			managerRegistry,
+			registry.WithCacheTtl(registryCacheTtl),
+			registry.WithNegativeCacheTtl(registryNegTtl),
+			registry.WithStaleTtl(registryStaleTtl),
+			registry.WithLookupTimeout(registryTimeout))
+		// the CSMS sends a control message when a charge station's details change, which may be
+		// cached by this gateway even when the charge station is connected to another gateway
+		if listener, ok := bus.(server.ControlListener); ok {
+			listenerId := gatewayId
+			if listenerId == "" {
+				listenerId, err = os.Hostname()
+				if err != nil {
+					listenerId = "gateway"
+				}
+			}
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			err = listener.ListenForControl(ctx, listenerId, func(clientId string, msg *server.ControlMessage) {
+				if msg.Type == server.ControlDisconnect {
+					remoteRegistry.Invalidate(clientId)
+				}
+			})
+			if err != nil {
+				return fmt.Errorf("listening for control messages: %w", err)
+			}
+		}
+		drainer := server.NewDrainer(
+			server.WithDrainBatchSize(drainBatchSize),
+			server.WithDrainBatchInterval(drainInterval),
+			server.WithDrainJitter(drainJitter),
+			server.WithDrainCallTimeout(drainCallTimeout))
+		statusServer := server.New("status", statusAddr, nil, server.NewStatusHandler(drainer))
+		websocketOpts := []server.WebsocketOpt{
+			server.WithBus(bus),
 			server.WithDeviceRegistry(remoteRegistry),
 			server.WithOrgNames(orgNames),
 			server.WithTrustProxyHeaders(trustProxyHeaders),
-			server.WithOtelTracer(tracer))
+			server.WithGatewayId(gatewayId),
+			server.WithMaxConnections(maxConnections),
+			server.WithSourceRateLimit(server.RateLimit{Rate: sourceRate, Burst: sourceBurst}),
+			server.WithReconnectRateLimit(server.RateLimit{Rate: reconnectRate, Burst: reconnectBurst}),
+			server.WithMessageRateLimit(server.RateLimit{Rate: messageRate, Burst: messageBurst}),
+			server.WithDrainer(drainer),
+			server.WithOtelTracer(tracer),
+		}
+		if authLockout {
+			websocketOpts = append(websocketOpts, server.WithAuthLockoutRegistry(managerRegistry))
+		}
+		revocationPolicy, err := server.ParseRevocationPolicy(revocationCheck)
+		if err != nil {
+			return err
+		}
+		revocationChecker, err := server.NewRevocationChecker(
+			server.WithRevocationPolicy(revocationPolicy),
+			server.WithCRLFiles(crlFiles),
+			server.WithOCSP(revocationOCSP),
+			server.WithCRLDistributionPoints(revocationCRLDP),
+			server.WithRevocationRegistry(remoteRegistry),
+			server.WithRevocationCacheTtl(revocationTtl),
+			server.WithRevocationRecheckInterval(revocationRecheck))
+		if err != nil {
+			return err
+		}
+		websocketOpts = append(websocketOpts, server.WithRevocationChecker(revocationChecker))
+		websocketHandler := server.NewWebsocketHandler(websocketOpts...)
 		wsServer := server.New("ws", wsAddr, nil, websocketHandler)
 		var wssServer *server.Server
 
 		if wssAddr != "" {
-			if tlsServerCert == "" {
-				return fmt.Errorf("no tls server cert specified for wss connection")
-			}
-			if tlsServerKey == "" {
-				return fmt.Errorf("no tls server key specified for wss connection")
-			}
-
-			//#nosec G304 - only files specified by the person running the application will be loaded
-			cb, err := os.ReadFile(tlsServerCert)
-			if err != nil {
-				return fmt.Errorf("reading tls cert from %s: %v", tlsServerCert, err)
-			}
-			//#nosec G304 - only files specified by the person running the application will be loaded
-			kb, err := os.ReadFile(tlsServerKey)
-			if err != nil {
-				return fmt.Errorf("reading tls key from %s: %v", tlsServerKey, err)
-			}
-			tlsCert, err := tls.X509KeyPair(cb, kb)
+			tlsConfig, err := server.LoadTLSConfig(tlsServerCert, tlsServerKey, tlsTrustCert)
 			if err != nil {
-				return fmt.Errorf("processing tls key pair: %v", err)
-			}
-			trustedCerts := x509.NewCertPool()
-			for _, tc := range tlsTrustCert {
-				//#nosec G304 - only files specified by the person running the application will be loaded
-				tcb, err := os.ReadFile(tc)
-				if err != nil {
-					return fmt.Errorf("reading trusted certs from %s: %v", tc, err)
-				}
-				if ok := trustedCerts.AppendCertsFromPEM(tcb); !ok {
-					return fmt.Errorf("processing trusted certs from %s: no certificate found", tc)
-				}
-			}
-			tlsConfig := &tls.Config{
-				Certificates: []tls.Certificate{tlsCert},
-				ClientCAs:    trustedCerts,
-				ClientAuth:   tls.VerifyClientCertIfGiven,
-				MinVersion:   tls.VersionTLS12,
+				return err
 			}
 
+			tlsConfig.VerifyConnection = revocationChecker.VerifyConnection
+
 			wssServer = server.New("wss", wssAddr, tlsConfig, websocketHandler)
 		}
 
@@ -199,16 +269,35 @@ var serveCmd = &cobra.Command{
 		}
 		statusServer.Start(errCh)
 
-		err = <-errCh
-		return err
+		sigCh := make(chan os.Signal, 1)
+		signal.Notify(sigCh, syscall.SIGTERM)
+		defer signal.Stop(sigCh)
+
+		select {
+		case err = <-errCh:
+			return err
+		case sig := <-sigCh:
+			slog.Info("draining connections before shutdown", "signal", sig)
+			ctx, cancel := context.WithTimeout(context.Background(), drainTimeout)
+			defer cancel()
+			err = drainer.Drain(ctx)
+			if err != nil {
+				slog.Warn("shutting down before all connections were drained", "err", err)
+			}
+			return nil
+		}
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(serveCmd)
 
+	serveCmd.Flags().StringVar(&transportType, "transport", "mqtt",
+		"The message bus used to communicate with the manager, either mqtt or nats")
 	serveCmd.Flags().StringVarP(&mqttAddr, "mqtt-addr", "m", "mqtt://127.0.0.1:1883",
 		"The address of the MQTT broker, e.g. mqtt://127.0.0.1:1883")
+	serveCmd.Flags().StringVar(&natsAddr, "nats-addr", "nats://127.0.0.1:4222",
+		"The address of the NATS server, e.g. nats://127.0.0.1:4222")
 	serveCmd.Flags().StringVarP(&wsAddr, "ws-addr", "a", "127.0.0.1:9310",
 		"The address that the insecure websocket server will listen on for connections, e.g. 127.0.0.1:9310")
 	serveCmd.Flags().StringVarP(&wssAddr, "wss-addr", "w", "",
@@ -225,8 +314,58 @@ func init() {
 		"A comma-separated list of organisation names that are valid in client certificates")
 	serveCmd.Flags().StringVarP(&managerApiAddr, "manager-api-addr", "r", "http://127.0.0.1:9410",
 		"The address of the CSMS manager API, e.g. http://127.0.0.1:9410")
+	serveCmd.Flags().StringVar(&managerApiKey, "manager-api-key", os.Getenv("MANAGER_API_KEY"),
+		"The API key, with the gateway role, used to authenticate with the CSMS manager API, defaults to the MANAGER_API_KEY environment variable")
+	serveCmd.Flags().DurationVar(&registryCacheTtl, "registry-cache-ttl", 1*time.Minute,
+		"How long charge station details and certificates read from the manager API are cached for")
+	serveCmd.Flags().DurationVar(&registryNegTtl, "registry-negative-cache-ttl", 10*time.Second,
+		"How long unknown charge stations and certificates are cached for")
+	serveCmd.Flags().DurationVar(&registryStaleTtl, "registry-stale-ttl", 1*time.Hour,
+		"How long after expiry cached details (other than certificate revocations) will be used if the manager API is unavailable")
+	serveCmd.Flags().DurationVar(&registryTimeout, "registry-timeout", 5*time.Second,
+		"How long to wait for the manager API when looking up charge station details")
+	serveCmd.Flags().IntVar(&maxConnections, "max-connections", 0,
+		"The maximum number of concurrent websocket connections, 0 means unlimited")
+	serveCmd.Flags().Float64Var(&sourceRate, "source-rate", 0,
+		"The number of connection attempts per second allowed from each source address, 0 means unlimited")
+	serveCmd.Flags().IntVar(&sourceBurst, "source-burst", 10,
+		"The number of connection attempts allowed from each source address in a burst above the source rate")
+	serveCmd.Flags().Float64Var(&reconnectRate, "reconnect-rate", 0,
+		"The number of connections per second allowed from each charge station, 0 means unlimited")
+	serveCmd.Flags().IntVar(&reconnectBurst, "reconnect-burst", 3,
+		"The number of connections allowed from each charge station in a burst above the reconnect rate")
+	serveCmd.Flags().Float64Var(&messageRate, "message-rate", 0,
+		"The number of calls per second allowed from each charge station, 0 means unlimited")
+	serveCmd.Flags().IntVar(&messageBurst, "message-burst", 20,
+		"The number of calls allowed from each charge station in a burst above the message rate")
+	serveCmd.Flags().BoolVar(&authLockout, "auth-lockout", true,
+		"Lock out charge stations and source addresses that repeatedly fail basic auth")
+	serveCmd.Flags().StringVar(&revocationCheck, "revocation-check", "soft-fail",
+		"What happens when the revocation status of a client certificate cannot be determined, one of [off, soft-fail, hard-fail]")
+	serveCmd.Flags().StringArrayVar(&crlFiles, "crl-file", []string{},
+		"A file that contains a DER or PEM encoded CRL to check client certificates against, reloaded when it changes")
+	serveCmd.Flags().BoolVar(&revocationOCSP, "revocation-ocsp", true,
+		"Check client certificates using the OCSP responder identified by the certificate")
+	serveCmd.Flags().BoolVar(&revocationCRLDP, "revocation-crl-distribution-points", true,
+		"Check client certificates against the CRLs published at the certificate's distribution points")
+	serveCmd.Flags().DurationVar(&revocationTtl, "revocation-cache-ttl", 1*time.Hour,
+		"How long OCSP responses and CRLs that do not specify their next update are cached for")
+	serveCmd.Flags().DurationVar(&revocationRecheck, "revocation-recheck-interval", 1*time.Minute,
+		"How often the client certificates of connected charge stations are rechecked for revocation")
+	serveCmd.Flags().DurationVar(&drainTimeout, "drain-timeout", 2*time.Minute,
+		"How long to spend draining connections after receiving SIGTERM before exiting")
+	serveCmd.Flags().IntVar(&drainBatchSize, "drain-batch-size", 100,
+		"The number of connections that are closed in each batch when draining")
+	serveCmd.Flags().DurationVar(&drainInterval, "drain-batch-interval", 1*time.Second,
+		"The delay between closing one batch of connections and starting the next when draining")
+	serveCmd.Flags().DurationVar(&drainJitter, "drain-jitter", 1*time.Second,
+		"The maximum random delay before each connection in a batch is closed when draining")
+	serveCmd.Flags().DurationVar(&drainCallTimeout, "drain-call-timeout", 10*time.Second,
+		"How long to wait for a call that is in progress to complete before closing a connection when draining")
 	serveCmd.Flags().BoolVar(&trustProxyHeaders, "trust-proxy", false,
 		"Trust proxy headers when determining the client's TLS status")
+	serveCmd.Flags().StringVar(&gatewayId, "gateway-id", "",
+		"The identifier of this gateway instance that is included in presence events, defaults to the hostname")
 	serveCmd.Flags().StringVar(&otelCollectorAddr, "otel-collector-addr", "",
 		"The address of the open telemetry collector that will receive traces, e.g. localhost:4317")
 	serveCmd.Flags().StringVar(&logFormat, "log-format", "text",
@@ -22,6 +22,8 @@ type Pipe struct {
 	CSMSTx chan *GatewayMessage
 	// halt in a signal channel used to stop the pipe
 	halt chan struct{}
+	// drain is used to request that the pipe stops sending CSMS calls to the charge station
+	drain chan chan struct{}
 	// csmsRxCallBuf is a channel that buffers CSMS calls whilst waiting for a CallResult
 	csmsRxCallBuf chan *GatewayMessage
 	// responseTimeout is the duration that the pipe will wait for a response to a call
@@ -51,6 +53,7 @@ func NewPipe(opts ...Opt) *Pipe {
 	pipe.CSMSRx = make(chan *GatewayMessage, pipe.csmsMessageQueueLen)
 	pipe.CSMSTx = make(chan *GatewayMessage, 1)
 	pipe.halt = make(chan struct{}, 1)
+	pipe.drain = make(chan chan struct{}, 1)
 	pipe.csmsRxCallBuf = make(chan *GatewayMessage, pipe.csmsCallQueueLen)
 
 	return pipe
@@ -119,12 +122,28 @@ func (p Pipe) Start() {
 
 		status := StatusWaiting
 
+		// once draining, no further calls from the CSMS are sent to the charge station
+		// and idle is closed when there is no call in progress
+		draining := false
+		var idle chan struct{}
+
 		for {
 			var currentMsg *GatewayMessage
 			if processedCSMSCalls.Value != nil {
 				currentMsg = processedCSMSCalls.Value.(*GatewayMessage)
 			}
 
+			if idle != nil && status == StatusWaiting {
+				close(idle)
+				idle = nil
+			}
+
+			// a nil channel is never selected
+			csmsRxCallBuf := p.csmsRxCallBuf
+			if draining {
+				csmsRxCallBuf = nil
+			}
+
 			switch status {
 			case StatusWaiting:
 				// prioritise pending calls from the ChargeStation
@@ -156,6 +175,8 @@ func (p Pipe) Start() {
 						slog.Error("CS call response has no corresponding CSMS call", slog.String("messageId", msg.MessageId))
 						continue
 					}
+				case idle = <-p.drain:
+					draining = true
 				case <-p.halt:
 					return
 				default:
@@ -197,6 +218,8 @@ func (p Pipe) Start() {
 							} else {
 								slog.Error("CSMS message is not a call", slog.String("messageId", msg.MessageId))
 							}
+						} else if draining {
+							slog.Warn("pipe draining - dropping CSMS call", slog.String("messageId", msg.MessageId))
 						} else {
 							// call from CSMS
 							if csmsCall := findCSMSCall(processedCSMSCalls, msg.MessageId); csmsCall != nil {
@@ -207,7 +230,7 @@ func (p Pipe) Start() {
 							status = StatusCSMSCall
 							p.ChargeStationTx <- msg
 						}
-					case msg := <-p.csmsRxCallBuf:
+					case msg := <-csmsRxCallBuf:
 						// buffered call from CSMS
 						if csmsCall := findCSMSCall(processedCSMSCalls, msg.MessageId); csmsCall != nil {
 							slog.Warn("CSMS call with duplicate message", slog.String("messageId", msg.MessageId))
@@ -216,6 +239,8 @@ func (p Pipe) Start() {
 						processedCSMSCalls.Value = msg
 						status = StatusCSMSCall
 						p.ChargeStationTx <- msg
+					case idle = <-p.drain:
+						draining = true
 					case <-p.halt:
 						return
 					}
@@ -246,6 +271,8 @@ func (p Pipe) Start() {
 				case <-time.After(p.responseTimeout):
 					slog.Warn("CSMS did not respond before timeout", slog.String("messageId", processedMessageIds.Value.(string)))
 					status = StatusWaiting
+				case idle = <-p.drain:
+					draining = true
 				case <-p.halt:
 					return
 				}
@@ -282,6 +309,8 @@ func (p Pipe) Start() {
 				case <-time.After(p.responseTimeout):
 					slog.Warn("CS did not respond before timeout", slog.String("messageId", currentMsg.MessageId))
 					status = StatusWaiting
+				case idle = <-p.drain:
+					draining = true
 				case <-p.halt:
 					return
 				}
@@ -290,6 +319,19 @@ func (p Pipe) Start() {
 	}()
 }
 
+// Drain stops the pipe from sending any further calls from the CSMS to the charge station:
+// calls received from the CSMS are dropped. The returned channel is closed once the pipe
+// has no call in progress in either direction. Drain should only be called once.
+func (p Pipe) Drain() <-chan struct{} {
+	idle := make(chan struct{})
+	select {
+	case p.drain <- idle:
+	default:
+		// the pipe is already draining
+	}
+	return idle
+}
+
 func (p Pipe) Close() {
 	p.halt <- struct{}{}
 }
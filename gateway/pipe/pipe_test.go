@@ -1039,3 +1039,103 @@ func TestChargeStationResponseForAPreviousCSMSCall(t *testing.T) {
 		t.Fatal("timeout waiting for test to complete")
 	}
 }
+
+func TestDrainWaitsForCSMSCallToComplete(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	p := pipe.NewPipe()
+	p.Start()
+	defer p.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+
+	callMessage := &pipe.GatewayMessage{
+		MessageType:    ocpp.MessageTypeCall,
+		Action:         "CSMSCall",
+		MessageId:      "4321",
+		RequestPayload: json.RawMessage(`{"call":true}`),
+	}
+
+	// make call from CSMS
+	p.CSMSRx <- callMessage
+
+	select {
+	case msg := <-p.ChargeStationTx:
+		assert.Equal(t, callMessage, msg)
+	case <-ctx.Done():
+		t.Fatal("timeout waiting for CSMS call")
+	}
+
+	idle := p.Drain()
+
+	select {
+	case <-idle:
+		t.Fatal("pipe idle whilst CSMS call in progress")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	p.ChargeStationRx <- &pipe.GatewayMessage{
+		MessageType:     ocpp.MessageTypeCallResult,
+		MessageId:       "4321",
+		ResponsePayload: json.RawMessage(`{"call":false}`),
+	}
+
+	select {
+	case msg := <-p.CSMSTx:
+		assert.Equal(t, "4321", msg.MessageId)
+	case <-ctx.Done():
+		t.Fatal("timeout waiting for CS call response")
+	}
+
+	select {
+	case <-idle:
+		// complete
+	case <-ctx.Done():
+		t.Fatal("timeout waiting for pipe to be idle")
+	}
+}
+
+func TestDrainDropsNewCSMSCalls(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	p := pipe.NewPipe()
+	p.Start()
+	defer p.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+
+	select {
+	case <-p.Drain():
+		// idle immediately
+	case <-ctx.Done():
+		t.Fatal("timeout waiting for pipe to be idle")
+	}
+
+	p.CSMSRx <- &pipe.GatewayMessage{
+		MessageType: ocpp.MessageTypeCall,
+		Action:      "CSMSCall",
+		MessageId:   "4321",
+	}
+
+	select {
+	case msg := <-p.ChargeStationTx:
+		t.Fatalf("CSMS call %s sent to charge station whilst draining", msg.MessageId)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// calls from the charge station are still handled
+	p.ChargeStationRx <- &pipe.GatewayMessage{
+		MessageType: ocpp.MessageTypeCall,
+		Action:      "CSCall",
+		MessageId:   "1234",
+	}
+
+	select {
+	case msg := <-p.CSMSTx:
+		assert.Equal(t, "1234", msg.MessageId)
+	case <-ctx.Done():
+		t.Fatal("timeout waiting for CS call")
+	}
+}
@@ -2,7 +2,10 @@
 
 package registry
 
-import "crypto/x509"
+import (
+	"crypto/x509"
+	"time"
+)
 
 type SecurityProfile int
 
@@ -23,3 +26,47 @@ type DeviceRegistry interface {
 	LookupChargeStation(clientId string) (*ChargeStation, error)
 	LookupCertificate(certHash string) (*x509.Certificate, error)
 }
+
+// Invalidator is implemented by registries that cache charge station details, so that
+// the details can be discarded when the CSMS reports that they have changed
+type Invalidator interface {
+	// Invalidate discards any cached details for the charge station
+	Invalidate(clientId string)
+}
+
+// AuthLockout reports when the lockouts that apply to a basic auth attempt expire:
+// a zero time means that no lockout is in force
+type AuthLockout struct {
+	ChargeStationLockedUntil time.Time
+	RemoteAddrLockedUntil    time.Time
+}
+
+// LockedUntil returns the time at which both lockouts will have expired
+func (l *AuthLockout) LockedUntil() time.Time {
+	if l.RemoteAddrLockedUntil.After(l.ChargeStationLockedUntil) {
+		return l.RemoteAddrLockedUntil
+	}
+	return l.ChargeStationLockedUntil
+}
+
+// AuthLockoutRegistry tracks failed basic auth attempts so that repeated failures for a
+// charge station, or from a source address, are locked out
+type AuthLockoutRegistry interface {
+	RecordAuthFailure(clientId, remoteAddr string) (*AuthLockout, error)
+	LookupAuthLockout(clientId, remoteAddr string) (*AuthLockout, error)
+}
+
+// CertificateRevocation records that a charge station client certificate has been
+// revoked by the CSMS
+type CertificateRevocation struct {
+	ChargeStationId string
+	Reason          string
+	RevokedAt       time.Time
+}
+
+// CertificateRevocationRegistry reports whether a client certificate, identified by the
+// base64 encoded SHA-256 hash of its DER bytes, has been revoked
+type CertificateRevocationRegistry interface {
+	// LookupCertificateRevocation returns nil if the certificate has not been revoked
+	LookupCertificateRevocation(certHash string) (*CertificateRevocation, error)
+}
@@ -0,0 +1,221 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"crypto/x509"
+	"errors"
+	"golang.org/x/exp/slog"
+	"golang.org/x/sync/singleflight"
+	"k8s.io/utils/clock"
+	"sync"
+	"time"
+)
+
+// ErrLookupTimeout is returned when a lookup takes longer than the configured
+// timeout and there is no stale value that can be used instead.
+var ErrLookupTimeout = errors.New("registry lookup timed out")
+
+// CachingRegistry is a DeviceRegistry that caches the results from another
+// DeviceRegistry (typically the RemoteRegistry) so that a large number of charge
+// stations reconnecting at the same time does not overload the manager:
+//
+//   - results are cached for the TTL, with unknown charge stations and certificates
+//     cached for the (shorter) negative TTL
+//   - concurrent lookups for the same key share a single request
+//   - lookups that take longer than the timeout fail, but the request continues
+//     in the background and will populate the cache when it completes
+//   - if a lookup fails, a cached value that has expired less than the stale TTL
+//     ago is returned instead of the error, except for certificate revocations:
+//     an out-of-date answer could let a revoked certificate connect, so the error
+//     is returned and the revocation policy decides whether to allow the connection
+type CachingRegistry struct {
+	registry       DeviceRegistry
+	clock          clock.Clock
+	ttl            time.Duration
+	negativeTtl    time.Duration
+	staleTtl       time.Duration
+	timeout        time.Duration
+	group          singleflight.Group
+	mu             sync.Mutex
+	chargeStations map[string]cacheEntry[*ChargeStation]
+	certificates   map[string]cacheEntry[*x509.Certificate]
+	revocations    map[string]cacheEntry[*CertificateRevocation]
+	generation     uint64
+	lastSweep      time.Time
+}
+
+type cacheEntry[T any] struct {
+	value     T
+	found     bool
+	fetchedAt time.Time
+}
+
+type CachingRegistryOpt func(*CachingRegistry)
+
+// WithCacheTtl sets how long a charge station or certificate is cached for.
+func WithCacheTtl(ttl time.Duration) CachingRegistryOpt {
+	return func(r *CachingRegistry) {
+		r.ttl = ttl
+	}
+}
+
+// WithNegativeCacheTtl sets how long an unknown charge station or certificate is cached for.
+func WithNegativeCacheTtl(negativeTtl time.Duration) CachingRegistryOpt {
+	return func(r *CachingRegistry) {
+		r.negativeTtl = negativeTtl
+	}
+}
+
+// WithStaleTtl sets how long after expiry a cached value will be used if the lookup fails.
+func WithStaleTtl(staleTtl time.Duration) CachingRegistryOpt {
+	return func(r *CachingRegistry) {
+		r.staleTtl = staleTtl
+	}
+}
+
+// WithLookupTimeout sets how long to wait for the underlying registry.
+func WithLookupTimeout(timeout time.Duration) CachingRegistryOpt {
+	return func(r *CachingRegistry) {
+		r.timeout = timeout
+	}
+}
+
+func WithClock(clock clock.Clock) CachingRegistryOpt {
+	return func(r *CachingRegistry) {
+		r.clock = clock
+	}
+}
+
+func NewCachingRegistry(registry DeviceRegistry, opts ...CachingRegistryOpt) *CachingRegistry {
+	r := &CachingRegistry{
+		registry:       registry,
+		clock:          clock.RealClock{},
+		ttl:            1 * time.Minute,
+		negativeTtl:    10 * time.Second,
+		staleTtl:       1 * time.Hour,
+		timeout:        5 * time.Second,
+		chargeStations: make(map[string]cacheEntry[*ChargeStation]),
+		certificates:   make(map[string]cacheEntry[*x509.Certificate]),
+		revocations:    make(map[string]cacheEntry[*CertificateRevocation]),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+func (r *CachingRegistry) LookupChargeStation(clientId string) (*ChargeStation, error) {
+	return lookup(r, r.chargeStations, "cs:", clientId, true, func() (*ChargeStation, bool, error) {
+		cs, err := r.registry.LookupChargeStation(clientId)
+		return cs, cs != nil, err
+	})
+}
+
+// Invalidate discards the cached details for the charge station so that the next
+// lookup fetches them from the underlying registry. Lookups that are already in
+// progress do not update the cache.
+func (r *CachingRegistry) Invalidate(clientId string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.chargeStations, clientId)
+	r.generation++
+	r.group.Forget("cs:" + clientId)
+}
+
+func (r *CachingRegistry) LookupCertificate(certHash string) (*x509.Certificate, error) {
+	return lookup(r, r.certificates, "cert:", certHash, true, func() (*x509.Certificate, bool, error) {
+		cert, err := r.registry.LookupCertificate(certHash)
+		return cert, cert != nil, err
+	})
+}
+
+// LookupCertificateRevocation returns the revocation of the certificate from the underlying
+// registry. If the underlying registry does not track revocations then no certificate is revoked.
+func (r *CachingRegistry) LookupCertificateRevocation(certHash string) (*CertificateRevocation, error) {
+	revocationRegistry, ok := r.registry.(CertificateRevocationRegistry)
+	if !ok {
+		return nil, nil
+	}
+	return lookup(r, r.revocations, "revoked:", certHash, false, func() (*CertificateRevocation, bool, error) {
+		revocation, err := revocationRegistry.LookupCertificateRevocation(certHash)
+		return revocation, revocation != nil, err
+	})
+}
+
+func lookup[T any](r *CachingRegistry, cache map[string]cacheEntry[T], prefix, key string, allowStale bool, fetch func() (T, bool, error)) (T, error) {
+	r.mu.Lock()
+	entry, cached := cache[key]
+	generation := r.generation
+	r.mu.Unlock()
+
+	now := r.clock.Now()
+	if cached && now.Before(entry.fetchedAt.Add(r.entryTtl(entry.found))) {
+		return entry.value, nil
+	}
+
+	ch := r.group.DoChan(prefix+key, func() (any, error) {
+		value, found, err := fetch()
+		if err != nil {
+			return value, err
+		}
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		// the value may be out of date if the cache was invalidated during the lookup
+		if r.generation == generation {
+			cache[key] = cacheEntry[T]{value: value, found: found, fetchedAt: r.clock.Now()}
+		}
+		r.sweep()
+		return value, nil
+	})
+
+	timer := r.clock.NewTimer(r.timeout)
+	defer timer.Stop()
+
+	var err error
+	select {
+	case res := <-ch:
+		if res.Err == nil {
+			return res.Val.(T), nil
+		}
+		err = res.Err
+	case <-timer.C():
+		err = ErrLookupTimeout
+	}
+
+	if allowStale && cached && now.Before(entry.fetchedAt.Add(r.entryTtl(entry.found)+r.staleTtl)) {
+		slog.Warn("registry lookup failed: using stale value", "key", key, "err", err)
+		return entry.value, nil
+	}
+
+	var zero T
+	return zero, err
+}
+
+func (r *CachingRegistry) entryTtl(found bool) time.Duration {
+	if found {
+		return r.ttl
+	}
+	return r.negativeTtl
+}
+
+// sweep removes the entries that can no longer be used, at most once per TTL.
+// It must be called with the lock held.
+func (r *CachingRegistry) sweep() {
+	now := r.clock.Now()
+	if now.Before(r.lastSweep.Add(r.ttl)) {
+		return
+	}
+	r.lastSweep = now
+	sweepCache(r.chargeStations, now, r)
+	sweepCache(r.certificates, now, r)
+	sweepCache(r.revocations, now, r)
+}
+
+func sweepCache[T any](cache map[string]cacheEntry[T], now time.Time, r *CachingRegistry) {
+	for key, entry := range cache {
+		if !now.Before(entry.fetchedAt.Add(r.entryTtl(entry.found) + r.staleTtl)) {
+			delete(cache, key)
+		}
+	}
+}
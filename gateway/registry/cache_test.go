@@ -0,0 +1,274 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package registry_test
+
+import (
+	"crypto/x509"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thoughtworks/maeve-csms/gateway/registry"
+	clockTest "k8s.io/utils/clock/testing"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type stubRegistry struct {
+	chargeStations map[string]*registry.ChargeStation
+	certificates   map[string]*x509.Certificate
+	err            error
+	block          chan struct{}
+	calls          atomic.Int32
+}
+
+func (s *stubRegistry) LookupChargeStation(clientId string) (*registry.ChargeStation, error) {
+	s.calls.Add(1)
+	if s.block != nil {
+		<-s.block
+	}
+	if s.err != nil {
+		return nil, s.err
+	}
+	return s.chargeStations[clientId], nil
+}
+
+func (s *stubRegistry) LookupCertificate(certHash string) (*x509.Certificate, error) {
+	s.calls.Add(1)
+	if s.err != nil {
+		return nil, s.err
+	}
+	return s.certificates[certHash], nil
+}
+
+type stubRevocationRegistry struct {
+	*stubRegistry
+	revocations map[string]*registry.CertificateRevocation
+}
+
+func (s *stubRevocationRegistry) LookupCertificateRevocation(certHash string) (*registry.CertificateRevocation, error) {
+	s.calls.Add(1)
+	if s.err != nil {
+		return nil, s.err
+	}
+	return s.revocations[certHash], nil
+}
+
+func newStubRegistry() *stubRegistry {
+	return &stubRegistry{
+		chargeStations: map[string]*registry.ChargeStation{
+			"cs001": {ClientId: "cs001", SecurityProfile: registry.TLSWithBasicAuth},
+		},
+		certificates: map[string]*x509.Certificate{
+			"hash": {Raw: []byte("certificate")},
+		},
+	}
+}
+
+func TestCachingRegistryCachesChargeStationForTtl(t *testing.T) {
+	clock := clockTest.NewFakeClock(time.Now())
+	stub := newStubRegistry()
+	reg := registry.NewCachingRegistry(stub, registry.WithClock(clock), registry.WithCacheTtl(time.Minute))
+
+	for i := 0; i < 3; i++ {
+		cs, err := reg.LookupChargeStation("cs001")
+		require.NoError(t, err)
+		require.NotNil(t, cs)
+		assert.Equal(t, registry.TLSWithBasicAuth, cs.SecurityProfile)
+	}
+	assert.Equal(t, int32(1), stub.calls.Load())
+
+	clock.Step(time.Minute)
+
+	_, err := reg.LookupChargeStation("cs001")
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), stub.calls.Load())
+}
+
+func TestCachingRegistryCachesUnknownChargeStationForNegativeTtl(t *testing.T) {
+	clock := clockTest.NewFakeClock(time.Now())
+	stub := newStubRegistry()
+	reg := registry.NewCachingRegistry(stub, registry.WithClock(clock),
+		registry.WithCacheTtl(time.Minute), registry.WithNegativeCacheTtl(10*time.Second))
+
+	cs, err := reg.LookupChargeStation("unknown")
+	require.NoError(t, err)
+	assert.Nil(t, cs)
+
+	clock.Step(5 * time.Second)
+	cs, err = reg.LookupChargeStation("unknown")
+	require.NoError(t, err)
+	assert.Nil(t, cs)
+	assert.Equal(t, int32(1), stub.calls.Load())
+
+	// the charge station has now been registered
+	stub.chargeStations["unknown"] = &registry.ChargeStation{ClientId: "unknown"}
+	clock.Step(5 * time.Second)
+
+	cs, err = reg.LookupChargeStation("unknown")
+	require.NoError(t, err)
+	assert.NotNil(t, cs)
+	assert.Equal(t, int32(2), stub.calls.Load())
+}
+
+func TestCachingRegistryCoalescesConcurrentLookups(t *testing.T) {
+	stub := newStubRegistry()
+	stub.block = make(chan struct{})
+	reg := registry.NewCachingRegistry(stub)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			cs, err := reg.LookupChargeStation("cs001")
+			assert.NoError(t, err)
+			assert.NotNil(t, cs)
+		}()
+	}
+
+	assert.Eventually(t, func() bool { return stub.calls.Load() == 1 }, time.Second, time.Millisecond)
+	// give the other goroutines a chance to join the in-flight lookup
+	time.Sleep(50 * time.Millisecond)
+	close(stub.block)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), stub.calls.Load())
+}
+
+func TestCachingRegistryTimesOutSlowLookup(t *testing.T) {
+	clock := clockTest.NewFakeClock(time.Now())
+	stub := newStubRegistry()
+	stub.block = make(chan struct{})
+	reg := registry.NewCachingRegistry(stub, registry.WithClock(clock), registry.WithLookupTimeout(5*time.Second))
+
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := reg.LookupChargeStation("cs001")
+		errCh <- err
+	}()
+
+	assert.Eventually(t, clock.HasWaiters, time.Second, time.Millisecond)
+	clock.Step(5 * time.Second)
+	assert.ErrorIs(t, <-errCh, registry.ErrLookupTimeout)
+
+	// the lookup completes in the background and populates the cache
+	close(stub.block)
+	assert.Eventually(t, func() bool {
+		cs, err := reg.LookupChargeStation("cs001")
+		return err == nil && cs != nil
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, int32(1), stub.calls.Load())
+}
+
+func TestCachingRegistryReturnsStaleValueOnError(t *testing.T) {
+	clock := clockTest.NewFakeClock(time.Now())
+	stub := newStubRegistry()
+	reg := registry.NewCachingRegistry(stub, registry.WithClock(clock),
+		registry.WithCacheTtl(time.Minute), registry.WithStaleTtl(time.Hour))
+
+	_, err := reg.LookupChargeStation("cs001")
+	require.NoError(t, err)
+
+	stub.err = errors.New("manager unavailable")
+
+	clock.Step(30 * time.Minute)
+	cs, err := reg.LookupChargeStation("cs001")
+	require.NoError(t, err)
+	assert.NotNil(t, cs)
+
+	clock.Step(31 * time.Minute)
+	_, err = reg.LookupChargeStation("cs001")
+	assert.Error(t, err)
+}
+
+func TestCachingRegistryReturnsErrorWhenNothingCached(t *testing.T) {
+	stub := newStubRegistry()
+	stub.err = errors.New("manager unavailable")
+	reg := registry.NewCachingRegistry(stub)
+
+	_, err := reg.LookupChargeStation("cs001")
+	assert.ErrorIs(t, err, stub.err)
+
+	// errors are not cached
+	stub.err = nil
+	cs, err := reg.LookupChargeStation("cs001")
+	require.NoError(t, err)
+	assert.NotNil(t, cs)
+}
+
+func TestCachingRegistryCachesCertificate(t *testing.T) {
+	stub := newStubRegistry()
+	reg := registry.NewCachingRegistry(stub)
+
+	for i := 0; i < 3; i++ {
+		cert, err := reg.LookupCertificate("hash")
+		require.NoError(t, err)
+		require.NotNil(t, cert)
+		assert.Equal(t, []byte("certificate"), cert.Raw)
+	}
+	assert.Equal(t, int32(1), stub.calls.Load())
+}
+
+func TestCachingRegistryCertificateRevocation(t *testing.T) {
+	clock := clockTest.NewFakeClock(time.Now())
+	reg := registry.NewCachingRegistry(registry.MockRegistry{
+		CertificateRevocations: map[string]*registry.CertificateRevocation{
+			"revoked": {ChargeStationId: "cs001"},
+		},
+	}, registry.WithClock(clock))
+
+	revocation, err := reg.LookupCertificateRevocation("revoked")
+	require.NoError(t, err)
+	require.NotNil(t, revocation)
+	assert.Equal(t, "cs001", revocation.ChargeStationId)
+
+	revocation, err = reg.LookupCertificateRevocation("hash")
+	require.NoError(t, err)
+	assert.Nil(t, revocation)
+}
+
+func TestCachingRegistryCertificateRevocationNotSupported(t *testing.T) {
+	stub := newStubRegistry()
+	reg := registry.NewCachingRegistry(stub)
+
+	revocation, err := reg.LookupCertificateRevocation("hash")
+	require.NoError(t, err)
+	assert.Nil(t, revocation)
+	assert.Equal(t, int32(0), stub.calls.Load())
+}
+
+func TestCachingRegistryDoesNotReturnStaleCertificateRevocation(t *testing.T) {
+	clock := clockTest.NewFakeClock(time.Now())
+	stub := &stubRevocationRegistry{stubRegistry: newStubRegistry()}
+	reg := registry.NewCachingRegistry(stub, registry.WithClock(clock),
+		registry.WithNegativeCacheTtl(10*time.Second), registry.WithStaleTtl(time.Hour))
+
+	revocation, err := reg.LookupCertificateRevocation("hash")
+	require.NoError(t, err)
+	assert.Nil(t, revocation)
+
+	stub.err = errors.New("manager unavailable")
+
+	clock.Step(time.Minute)
+	_, err = reg.LookupCertificateRevocation("hash")
+	assert.ErrorIs(t, err, stub.err)
+}
+
+func TestCachingRegistryInvalidate(t *testing.T) {
+	stub := newStubRegistry()
+	reg := registry.NewCachingRegistry(stub)
+
+	_, err := reg.LookupChargeStation("cs001")
+	require.NoError(t, err)
+
+	stub.chargeStations["cs001"] = &registry.ChargeStation{ClientId: "cs001", SecurityProfile: registry.TLSWithClientSideCertificates}
+	reg.Invalidate("cs001")
+
+	cs, err := reg.LookupChargeStation("cs001")
+	require.NoError(t, err)
+	require.NotNil(t, cs)
+	assert.Equal(t, registry.TLSWithClientSideCertificates, cs.SecurityProfile)
+	assert.Equal(t, int32(2), stub.calls.Load())
+}
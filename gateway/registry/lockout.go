@@ -0,0 +1,87 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+type AuthFailureRequest struct {
+	RemoteAddr string `json:"remoteAddr,omitempty"`
+}
+
+type AuthLockoutStatusResponse struct {
+	ChargeStationLockedUntil *time.Time `json:"chargeStationLockedUntil,omitempty"`
+	RemoteAddrLockedUntil    *time.Time `json:"remoteAddrLockedUntil,omitempty"`
+}
+
+func (r RemoteRegistry) RecordAuthFailure(clientId, remoteAddr string) (*AuthLockout, error) {
+	body, err := json.Marshal(AuthFailureRequest{RemoteAddr: remoteAddr})
+	if err != nil {
+		return nil, fmt.Errorf("marshaling request: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/api/v0/cs/%s/auth/failure", r.ManagerApiAddr, clientId), bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("creating http request: %w", err)
+	}
+	req.Header.Set("content-type", "application/json")
+
+	return r.doAuthLockoutRequest(req)
+}
+
+func (r RemoteRegistry) LookupAuthLockout(clientId, remoteAddr string) (*AuthLockout, error) {
+	u := fmt.Sprintf("%s/api/v0/cs/%s/auth/lockout", r.ManagerApiAddr, clientId)
+	if remoteAddr != "" {
+		u += "?remoteAddr=" + url.QueryEscape(remoteAddr)
+	}
+
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating http request: %w", err)
+	}
+
+	return r.doAuthLockoutRequest(req)
+}
+
+func (r RemoteRegistry) doAuthLockoutRequest(req *http.Request) (*AuthLockout, error) {
+	req.Header.Set("accept", "application/json")
+	r.setAuthHeader(req)
+
+	resp, err := r.httpClient().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("making http request: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected http status: %d", resp.StatusCode)
+	}
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading http body: %w", err)
+	}
+	var status AuthLockoutStatusResponse
+	err = json.Unmarshal(b, &status)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshaling data: %w", err)
+	}
+
+	lockout := new(AuthLockout)
+	if status.ChargeStationLockedUntil != nil {
+		lockout.ChargeStationLockedUntil = *status.ChargeStationLockedUntil
+	}
+	if status.RemoteAddrLockedUntil != nil {
+		lockout.RemoteAddrLockedUntil = *status.RemoteAddrLockedUntil
+	}
+	return lockout, nil
+}
@@ -0,0 +1,73 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package registry_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thoughtworks/maeve-csms/gateway/registry"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRecordAuthFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/api/v0/cs/cs001/auth/failure", r.URL.Path)
+		assert.Equal(t, "gateway-key", r.Header.Get("X-API-Key"))
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"remoteAddr":"192.168.1.1"}`, string(body))
+		_, _ = w.Write([]byte(`{"chargeStationLockedUntil":"2023-06-15T15:01:00Z"}`))
+	}))
+	defer server.Close()
+
+	reg := registry.RemoteRegistry{
+		ManagerApiAddr: server.URL,
+		ManagerApiKey:  "gateway-key",
+	}
+
+	got, err := reg.RecordAuthFailure("cs001", "192.168.1.1")
+	require.NoError(t, err)
+
+	want := &registry.AuthLockout{
+		ChargeStationLockedUntil: time.Date(2023, 6, 15, 15, 1, 0, 0, time.UTC),
+	}
+	assert.Equal(t, want, got)
+	assert.Equal(t, want.ChargeStationLockedUntil, got.LockedUntil())
+}
+
+func TestLookupAuthLockout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "/api/v0/cs/cs001/auth/lockout", r.URL.Path)
+		assert.Equal(t, "::1", r.URL.Query().Get("remoteAddr"))
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	reg := registry.RemoteRegistry{
+		ManagerApiAddr: server.URL,
+	}
+
+	got, err := reg.LookupAuthLockout("cs001", "::1")
+	require.NoError(t, err)
+	assert.True(t, got.LockedUntil().IsZero())
+}
+
+func TestLookupAuthLockoutWithServerError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	reg := registry.RemoteRegistry{
+		ManagerApiAddr: server.URL,
+	}
+
+	_, err := reg.LookupAuthLockout("cs001", "")
+	assert.Error(t, err)
+}
@@ -7,12 +7,21 @@ import "crypto/x509"
 type MockRegistry struct {
 	ChargeStations map[string]*ChargeStation
 	Certificates   map[string]*x509.Certificate
+	// AuthFailures counts the failed auth attempts recorded for each charge station
+	AuthFailures map[string]int
+	// AuthLockouts holds the lockouts returned for each charge station
+	AuthLockouts map[string]*AuthLockout
+	// CertificateRevocations holds the revoked certificates by certificate hash
+	CertificateRevocations map[string]*CertificateRevocation
 }
 
 func NewMockRegistry() *MockRegistry {
 	return &MockRegistry{
-		ChargeStations: make(map[string]*ChargeStation),
-		Certificates:   make(map[string]*x509.Certificate),
+		ChargeStations:         make(map[string]*ChargeStation),
+		Certificates:           make(map[string]*x509.Certificate),
+		AuthFailures:           make(map[string]int),
+		AuthLockouts:           make(map[string]*AuthLockout),
+		CertificateRevocations: make(map[string]*CertificateRevocation),
 	}
 }
 
@@ -23,3 +32,20 @@ func (m MockRegistry) LookupChargeStation(clientId string) (*ChargeStation, erro
 func (m MockRegistry) LookupCertificate(certHash string) (*x509.Certificate, error) {
 	return m.Certificates[certHash], nil
 }
+
+func (m MockRegistry) RecordAuthFailure(clientId, _ string) (*AuthLockout, error) {
+	m.AuthFailures[clientId]++
+	return m.LookupAuthLockout(clientId, "")
+}
+
+func (m MockRegistry) LookupAuthLockout(clientId, _ string) (*AuthLockout, error) {
+	if lockout, ok := m.AuthLockouts[clientId]; ok {
+		l := *lockout
+		return &l, nil
+	}
+	return new(AuthLockout), nil
+}
+
+func (m MockRegistry) LookupCertificateRevocation(certHash string) (*CertificateRevocation, error) {
+	return m.CertificateRevocations[certHash], nil
+}
@@ -8,12 +8,20 @@ import (
 	"io"
 	"net/http"
 	"strings"
+	"time"
 )
 
 type RemoteRegistry struct {
 	ManagerApiAddr string
+	// ManagerApiKey is the service credential used to authenticate with the manager API
+	ManagerApiKey string
+	// HttpClient is used to make requests to the manager API, if not set a client with
+	// a 10 second timeout is used
+	HttpClient *http.Client
 }
 
+var defaultHttpClient = &http.Client{Timeout: 10 * time.Second}
+
 type ChargeStationAuthDetailsResponse struct {
 	SecurityProfile        int    `json:"securityProfile"`
 	Base64SHA256Password   string `json:"base64SHA256Password,omitempty"`
@@ -26,11 +34,15 @@ func (r RemoteRegistry) LookupChargeStation(clientId string) (*ChargeStation, er
 		return nil, fmt.Errorf("creating http request: %w", err)
 	}
 	req.Header.Set("accept", "application/json")
+	r.setAuthHeader(req)
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := r.httpClient().Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("making http request: %w", err)
 	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
 
 	if resp.StatusCode == http.StatusOK {
 		var b []byte
@@ -38,9 +50,6 @@ func (r RemoteRegistry) LookupChargeStation(clientId string) (*ChargeStation, er
 		if err != nil {
 			return nil, fmt.Errorf("reading http body: %w", err)
 		}
-		defer func() {
-			_ = resp.Body.Close()
-		}()
 		var chargeStationAuthDetails ChargeStationAuthDetailsResponse
 		err = json.Unmarshal(b, &chargeStationAuthDetails)
 		if err != nil {
@@ -54,7 +63,7 @@ func (r RemoteRegistry) LookupChargeStation(clientId string) (*ChargeStation, er
 		}, nil
 	}
 
-	return nil, nil
+	return nil, checkNotFound(resp)
 }
 
 type CertificateResponse struct {
@@ -70,11 +79,15 @@ func (r RemoteRegistry) LookupCertificate(certHash string) (*x509.Certificate, e
 		return nil, fmt.Errorf("creating http request: %w", err)
 	}
 	req.Header.Set("accept", "application/json")
+	r.setAuthHeader(req)
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := r.httpClient().Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("making http request: %w", err)
 	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
 
 	if resp.StatusCode == http.StatusOK {
 		var b []byte
@@ -82,9 +95,6 @@ func (r RemoteRegistry) LookupCertificate(certHash string) (*x509.Certificate, e
 		if err != nil {
 			return nil, fmt.Errorf("reading http body: %w", err)
 		}
-		defer func() {
-			_ = resp.Body.Close()
-		}()
 
 		var certificateResponse CertificateResponse
 		err = json.Unmarshal(b, &certificateResponse)
@@ -103,5 +113,27 @@ func (r RemoteRegistry) LookupCertificate(certHash string) (*x509.Certificate, e
 		}
 	}
 
-	return nil, nil
+	return nil, checkNotFound(resp)
+}
+
+// checkNotFound returns nil if the response indicates that the requested item does not exist,
+// otherwise it returns an error so that failures are not mistaken for an unknown item
+func checkNotFound(resp *http.Response) error {
+	if resp.StatusCode == http.StatusNotFound {
+		return nil
+	}
+	return fmt.Errorf("unexpected http status: %d", resp.StatusCode)
+}
+
+func (r RemoteRegistry) httpClient() *http.Client {
+	if r.HttpClient != nil {
+		return r.HttpClient
+	}
+	return defaultHttpClient
+}
+
+func (r RemoteRegistry) setAuthHeader(req *http.Request) {
+	if r.ManagerApiKey != "" {
+		req.Header.Set("X-API-Key", r.ManagerApiKey)
+	}
 }
@@ -43,6 +43,72 @@ func TestLookupChargeStation(t *testing.T) {
 	assert.Equal(t, want, got)
 }
 
+func TestLookupChargeStationWithApiKey(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-API-Key") != "gateway-key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"securityProfile":1,"base64SHA256Password":"DEADBEEF"}`))
+	}))
+	defer server.Close()
+
+	reg := registry.RemoteRegistry{
+		ManagerApiAddr: server.URL,
+		ManagerApiKey:  "gateway-key",
+	}
+
+	got, err := reg.LookupChargeStation("cs001")
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, "DEADBEEF", got.Base64SHA256Password)
+}
+
+func TestLookupUnknownChargeStation(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	reg := registry.RemoteRegistry{
+		ManagerApiAddr: server.URL,
+	}
+
+	got, err := reg.LookupChargeStation("cs001")
+	require.NoError(t, err)
+	assert.Nil(t, got)
+}
+
+func TestLookupChargeStationWithServerError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	reg := registry.RemoteRegistry{
+		ManagerApiAddr: server.URL,
+	}
+
+	_, err := reg.LookupChargeStation("cs001")
+	assert.Error(t, err)
+}
+
+func TestLookupChargeStationWithTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		_, _ = w.Write([]byte(`{"securityProfile":1,"base64SHA256Password":"DEADBEEF"}`))
+	}))
+	defer server.Close()
+
+	reg := registry.RemoteRegistry{
+		ManagerApiAddr: server.URL,
+		HttpClient:     &http.Client{Timeout: 50 * time.Millisecond},
+	}
+
+	_, err := reg.LookupChargeStation("cs001")
+	assert.Error(t, err)
+}
+
 func TestLookupCertificate(t *testing.T) {
 	want := generateCertificate(t)
 
@@ -0,0 +1,59 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type CertificateRevocationResponse struct {
+	CertificateHash string    `json:"certificateHash"`
+	ChargeStationId string    `json:"csId"`
+	Reason          string    `json:"reason,omitempty"`
+	RevokedAt       time.Time `json:"revokedAt"`
+}
+
+func (r RemoteRegistry) LookupCertificateRevocation(certHash string) (*CertificateRevocation, error) {
+	certHash = strings.TrimRight(certHash, "=")
+	certHash = strings.Replace(certHash, "/", "_", -1)
+	certHash = strings.Replace(certHash, "+", "-", -1)
+
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/v0/certificate/%s/revocation", r.ManagerApiAddr, certHash), nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating http request: %w", err)
+	}
+	req.Header.Set("accept", "application/json")
+	r.setAuthHeader(req)
+
+	resp, err := r.httpClient().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("making http request: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode == http.StatusOK {
+		b, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("reading http body: %w", err)
+		}
+		var revocation CertificateRevocationResponse
+		err = json.Unmarshal(b, &revocation)
+		if err != nil {
+			return nil, fmt.Errorf("unmarshaling data: %w", err)
+		}
+		return &CertificateRevocation{
+			ChargeStationId: revocation.ChargeStationId,
+			Reason:          revocation.Reason,
+			RevokedAt:       revocation.RevokedAt,
+		}, nil
+	}
+
+	return nil, checkNotFound(resp)
+}
@@ -0,0 +1,67 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package registry_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thoughtworks/maeve-csms/gateway/registry"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestLookupCertificateRevocation(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "/api/v0/certificate/ab-c_d/revocation", r.URL.Path)
+		assert.Equal(t, "gateway-key", r.Header.Get("X-API-Key"))
+		_, _ = w.Write([]byte(`{"certificateHash":"ab-c_d","csId":"cs001","reason":"key compromised","revokedAt":"2023-06-15T15:00:00Z"}`))
+	}))
+	defer server.Close()
+
+	reg := registry.RemoteRegistry{
+		ManagerApiAddr: server.URL,
+		ManagerApiKey:  "gateway-key",
+	}
+
+	got, err := reg.LookupCertificateRevocation("ab+c/d=")
+	require.NoError(t, err)
+
+	want := &registry.CertificateRevocation{
+		ChargeStationId: "cs001",
+		Reason:          "key compromised",
+		RevokedAt:       time.Date(2023, 6, 15, 15, 0, 0, 0, time.UTC),
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestLookupCertificateRevocationNotRevoked(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	reg := registry.RemoteRegistry{
+		ManagerApiAddr: server.URL,
+	}
+
+	got, err := reg.LookupCertificateRevocation("hash")
+	require.NoError(t, err)
+	assert.Nil(t, got)
+}
+
+func TestLookupCertificateRevocationFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	reg := registry.RemoteRegistry{
+		ManagerApiAddr: server.URL,
+	}
+
+	_, err := reg.LookupCertificateRevocation("hash")
+	assert.Error(t, err)
+}
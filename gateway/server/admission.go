@@ -0,0 +1,255 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"golang.org/x/time/rate"
+	"k8s.io/utils/clock"
+)
+
+var (
+	connectionsRejected = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "gateway_connections_rejected_total",
+		Help: "The number of websocket connections rejected by admission control",
+	}, []string{"reason"})
+	messagesRejected = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "gateway_messages_rejected_total",
+		Help: "The number of charge station messages rejected because the station exceeded its message rate",
+	})
+	activeConnections = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "gateway_active_connections",
+		Help: "The number of websocket connections currently admitted by the gateway",
+	})
+)
+
+const (
+	rejectMaxConnections = "max_connections"
+	rejectReconnectRate  = "reconnect_rate"
+	rejectSourceRate     = "source_rate"
+)
+
+// RateLimit describes a token bucket: events are allowed at Rate per second with
+// bursts of up to Burst events. A zero Rate means that no limit is applied.
+type RateLimit struct {
+	Rate  float64
+	Burst int
+}
+
+func (l RateLimit) enabled() bool {
+	return l.Rate > 0
+}
+
+func (l RateLimit) newLimiter() *rate.Limiter {
+	burst := l.Burst
+	if burst < 1 {
+		burst = 1
+	}
+	return rate.NewLimiter(rate.Limit(l.Rate), burst)
+}
+
+// refillTime is how long an unused limiter takes to return to a full bucket, after
+// which it is indistinguishable from a new limiter
+func (l RateLimit) refillTime() time.Duration {
+	burst := l.Burst
+	if burst < 1 {
+		burst = 1
+	}
+	return time.Duration(float64(burst) / l.Rate * float64(time.Second))
+}
+
+// retryAfter is the number of whole seconds until another event will be allowed
+func (l RateLimit) retryAfter() int {
+	return int(math.Ceil(1 / l.Rate))
+}
+
+// admissionController decides whether new connections and inbound messages are
+// accepted. Connections are admitted in two stages: before authentication the
+// connection is charged to its source address, and only once the charge station
+// has authenticated is it charged to the station. This stops an unauthenticated
+// client from exhausting the reconnect allowance of a station it does not own.
+// The state for each charge station is kept between connections so that a
+// station cannot reset its message allowance by reconnecting.
+type admissionController struct {
+	sync.Mutex
+	clock          clock.PassiveClock
+	maxConnections int
+	sourceLimit    RateLimit
+	reconnectLimit RateLimit
+	messageLimit   RateLimit
+	connections    int
+	sources        map[string]*sourceAdmission
+	stations       map[string]*stationAdmission
+	lastSweep      time.Time
+}
+
+type sourceAdmission struct {
+	connect  *rate.Limiter
+	lastSeen time.Time
+}
+
+type stationAdmission struct {
+	reconnect   *rate.Limiter
+	messages    *rate.Limiter
+	connections int
+	lastSeen    time.Time
+}
+
+func newAdmissionController(clock clock.PassiveClock, maxConnections int, sourceLimit, reconnectLimit, messageLimit RateLimit) *admissionController {
+	return &admissionController{
+		clock:          clock,
+		maxConnections: maxConnections,
+		sourceLimit:    sourceLimit,
+		reconnectLimit: reconnectLimit,
+		messageLimit:   messageLimit,
+		sources:        make(map[string]*sourceAdmission),
+		stations:       make(map[string]*stationAdmission),
+	}
+}
+
+// admit is called when a client attempts to connect, before it has been
+// authenticated. If the connection is rejected then the reason and the number of
+// seconds after which the client should retry are returned. Otherwise, the caller
+// must call release once the connection has finished.
+func (a *admissionController) admit(source string) (reason string, retryAfter int) {
+	a.Lock()
+	defer a.Unlock()
+
+	now := a.clock.Now()
+	a.sweep(now)
+
+	if a.sourceLimit.enabled() {
+		src, ok := a.sources[source]
+		if !ok {
+			src = &sourceAdmission{connect: a.sourceLimit.newLimiter()}
+			a.sources[source] = src
+		}
+		src.lastSeen = now
+		if !src.connect.AllowN(now, 1) {
+			connectionsRejected.WithLabelValues(rejectSourceRate).Inc()
+			return rejectSourceRate, a.sourceLimit.retryAfter()
+		}
+	}
+
+	if a.maxConnections > 0 && a.connections >= a.maxConnections {
+		connectionsRejected.WithLabelValues(rejectMaxConnections).Inc()
+		return rejectMaxConnections, 1
+	}
+
+	a.connections++
+	activeConnections.Inc()
+
+	return "", 0
+}
+
+// release is called when a connection accepted by admit has finished
+func (a *admissionController) release() {
+	a.Lock()
+	defer a.Unlock()
+
+	a.connections--
+	activeConnections.Dec()
+}
+
+// admitStation is called once a charge station has authenticated. If the
+// connection is rejected then the reason and the number of seconds after which
+// the station should retry are returned. Otherwise, the caller must call
+// releaseStation once the connection has finished.
+func (a *admissionController) admitStation(clientId string) (reason string, retryAfter int) {
+	a.Lock()
+	defer a.Unlock()
+
+	now := a.clock.Now()
+	station := a.station(clientId, now)
+	if station.reconnect != nil && !station.reconnect.AllowN(now, 1) {
+		connectionsRejected.WithLabelValues(rejectReconnectRate).Inc()
+		return rejectReconnectRate, a.reconnectLimit.retryAfter()
+	}
+
+	station.connections++
+
+	return "", 0
+}
+
+// releaseStation is called when a connection accepted by admitStation has finished
+func (a *admissionController) releaseStation(clientId string) {
+	a.Lock()
+	defer a.Unlock()
+
+	if station, ok := a.stations[clientId]; ok {
+		station.connections--
+		station.lastSeen = a.clock.Now()
+	}
+}
+
+// allowMessage reports whether the charge station may send another message
+func (a *admissionController) allowMessage(clientId string) bool {
+	if !a.messageLimit.enabled() {
+		return true
+	}
+
+	a.Lock()
+	defer a.Unlock()
+
+	now := a.clock.Now()
+	station := a.station(clientId, now)
+	if station.messages.AllowN(now, 1) {
+		return true
+	}
+
+	messagesRejected.Inc()
+	return false
+}
+
+func (a *admissionController) station(clientId string, now time.Time) *stationAdmission {
+	station, ok := a.stations[clientId]
+	if !ok {
+		station = new(stationAdmission)
+		if a.reconnectLimit.enabled() {
+			station.reconnect = a.reconnectLimit.newLimiter()
+		}
+		if a.messageLimit.enabled() {
+			station.messages = a.messageLimit.newLimiter()
+		}
+		a.stations[clientId] = station
+	}
+	station.lastSeen = now
+	return station
+}
+
+// sweep removes the state for sources and disconnected stations whose limiters
+// have refilled: this is run at most once a minute
+func (a *admissionController) sweep(now time.Time) {
+	if now.Sub(a.lastSweep) < time.Minute {
+		return
+	}
+	a.lastSweep = now
+
+	var idle time.Duration
+	if a.reconnectLimit.enabled() {
+		idle = a.reconnectLimit.refillTime()
+	}
+	if a.messageLimit.enabled() && a.messageLimit.refillTime() > idle {
+		idle = a.messageLimit.refillTime()
+	}
+
+	if a.sourceLimit.enabled() {
+		refill := a.sourceLimit.refillTime()
+		for source, src := range a.sources {
+			if now.Sub(src.lastSeen) >= refill {
+				delete(a.sources, source)
+			}
+		}
+	}
+
+	for clientId, station := range a.stations {
+		if station.connections <= 0 && now.Sub(station.lastSeen) >= idle {
+			delete(a.stations, clientId)
+		}
+	}
+}
@@ -0,0 +1,227 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package server_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thoughtworks/maeve-csms/gateway/ocpp"
+	"github.com/thoughtworks/maeve-csms/gateway/pipe"
+	"github.com/thoughtworks/maeve-csms/gateway/registry"
+	"github.com/thoughtworks/maeve-csms/gateway/server"
+	"go.opentelemetry.io/otel/trace"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"nhooyr.io/websocket"
+	"testing"
+	"time"
+)
+
+func newAdmissionTestServer(t *testing.T, opts ...server.WebsocketOpt) (*httptest.Server, *websocket.DialOptions) {
+	natsUrl := server.NewNatsServer(t)
+
+	// simulate manager connection
+	nc, err := nats.Connect(natsUrl)
+	require.NoError(t, err)
+	t.Cleanup(nc.Close)
+
+	_, err = nc.Subscribe("cs.in.ocpp201.*", func(natsMsg *nats.Msg) {
+		var reqMsg pipe.GatewayMessage
+		err := json.Unmarshal(natsMsg.Data, &reqMsg)
+		require.NoError(t, err)
+
+		respMsg := pipe.GatewayMessage{
+			MessageType:     ocpp.MessageTypeCallResult,
+			MessageId:       reqMsg.MessageId,
+			ResponsePayload: reqMsg.RequestPayload,
+		}
+
+		b, err := json.Marshal(respMsg)
+		require.NoError(t, err)
+		err = nc.Publish("cs.out.ocpp201.cs1", b)
+		require.NoError(t, err)
+	})
+	require.NoError(t, err)
+	require.NoError(t, nc.Flush())
+
+	mockRegistry := registry.NewMockRegistry()
+	mockRegistry.ChargeStations["cs1"] = &registry.ChargeStation{
+		ClientId:             "cs1",
+		SecurityProfile:      registry.UnsecuredTransportWithBasicAuth,
+		Base64SHA256Password: "XohImNooBHFR0OVvjcYpJ3NgPQ1qq73WKhHvch0VQtg=", // password
+	}
+
+	bus := server.NewNatsBus([]string{natsUrl}, "cs", trace.NewNoopTracerProvider().Tracer(""))
+	t.Cleanup(func() {
+		_ = bus.Close()
+	})
+
+	srv := httptest.NewServer(server.NewWebsocketHandler(append([]server.WebsocketOpt{
+		server.WithBus(bus),
+		server.WithDeviceRegistry(mockRegistry),
+	}, opts...)...))
+	t.Cleanup(srv.Close)
+
+	authHeader := "Basic " + base64.StdEncoding.EncodeToString([]byte("cs1:password"))
+	dialOptions := &websocket.DialOptions{
+		Subprotocols: []string{"ocpp2.0.1"},
+		HTTPHeader: http.Header{
+			"authorization": []string{authHeader},
+		},
+	}
+
+	return srv, dialOptions
+}
+
+func TestConnectionRejectedWhenMaxConnectionsReached(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	srv, dialOptions := newAdmissionTestServer(t, server.WithMaxConnections(1))
+
+	conn, _, err := websocket.Dial(ctx, fmt.Sprintf("%s/ws/cs1", srv.URL), dialOptions)
+	require.NoError(t, err)
+	defer func() {
+		_ = conn.Close(websocket.StatusNormalClosure, "OK")
+	}()
+
+	_, resp, err := websocket.Dial(ctx, fmt.Sprintf("%s/ws/cs1", srv.URL), dialOptions)
+	require.Error(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	assert.Equal(t, "1", resp.Header.Get("Retry-After"))
+
+	metrics := readMetrics(t)
+	assert.Contains(t, metrics, `gateway_connections_rejected_total{reason="max_connections"}`)
+	assert.Contains(t, metrics, "gateway_active_connections 1")
+}
+
+func TestReconnectRejectedWhenRateExceeded(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	srv, dialOptions := newAdmissionTestServer(t,
+		server.WithReconnectRateLimit(server.RateLimit{Rate: 0.01, Burst: 1}))
+
+	conn, _, err := websocket.Dial(ctx, fmt.Sprintf("%s/ws/cs1", srv.URL), dialOptions)
+	require.NoError(t, err)
+	err = conn.Close(websocket.StatusNormalClosure, "OK")
+	require.NoError(t, err)
+
+	_, resp, err := websocket.Dial(ctx, fmt.Sprintf("%s/ws/cs1", srv.URL), dialOptions)
+	require.Error(t, err)
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(t, "100", resp.Header.Get("Retry-After"))
+
+	assert.Contains(t, readMetrics(t), `gateway_connections_rejected_total{reason="reconnect_rate"}`)
+}
+
+func TestUnauthenticatedConnectionsDoNotUseReconnectAllowance(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	srv, dialOptions := newAdmissionTestServer(t,
+		server.WithReconnectRateLimit(server.RateLimit{Rate: 0.01, Burst: 1}))
+
+	badAuthHeader := "Basic " + base64.StdEncoding.EncodeToString([]byte("cs1:wrong"))
+	for i := 0; i < 3; i++ {
+		_, resp, err := websocket.Dial(ctx, fmt.Sprintf("%s/ws/cs1", srv.URL), &websocket.DialOptions{
+			Subprotocols: []string{"ocpp2.0.1"},
+			HTTPHeader: http.Header{
+				"authorization": []string{badAuthHeader},
+			},
+		})
+		require.Error(t, err)
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	}
+
+	conn, _, err := websocket.Dial(ctx, fmt.Sprintf("%s/ws/cs1", srv.URL), dialOptions)
+	require.NoError(t, err)
+	_ = conn.Close(websocket.StatusNormalClosure, "OK")
+}
+
+func TestConnectionRejectedWhenSourceRateExceeded(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	srv, dialOptions := newAdmissionTestServer(t,
+		server.WithSourceRateLimit(server.RateLimit{Rate: 0.01, Burst: 1}))
+
+	_, resp, err := websocket.Dial(ctx, fmt.Sprintf("%s/ws/unknown", srv.URL), dialOptions)
+	require.Error(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	_, resp, err = websocket.Dial(ctx, fmt.Sprintf("%s/ws/cs1", srv.URL), dialOptions)
+	require.Error(t, err)
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(t, "100", resp.Header.Get("Retry-After"))
+
+	assert.Contains(t, readMetrics(t), `gateway_connections_rejected_total{reason="source_rate"}`)
+}
+
+func TestCallRejectedWhenMessageRateExceeded(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	srv, dialOptions := newAdmissionTestServer(t,
+		server.WithMessageRateLimit(server.RateLimit{Rate: 0.01, Burst: 1}))
+
+	conn, _, err := websocket.Dial(ctx, fmt.Sprintf("%s/ws/cs1", srv.URL), dialOptions)
+	require.NoError(t, err)
+	defer func() {
+		_ = conn.Close(websocket.StatusNormalClosure, "OK")
+	}()
+
+	for _, messageId := range []string{"1", "2"} {
+		call := ocpp.Message{
+			MessageTypeId: ocpp.MessageTypeCall,
+			MessageId:     messageId,
+			Data: []json.RawMessage{
+				json.RawMessage(`"EchoRequest"`),
+				json.RawMessage(`"Payload"`),
+			},
+		}
+		data, err := json.Marshal(call)
+		require.NoError(t, err)
+		err = conn.Write(ctx, websocket.MessageText, data)
+		require.NoError(t, err)
+	}
+
+	// the rejection may be written before the result of the first call
+	received := make(map[string]ocpp.Message)
+	for len(received) < 2 {
+		_, b, err := conn.Read(ctx)
+		require.NoError(t, err)
+
+		var msg ocpp.Message
+		err = json.Unmarshal(b, &msg)
+		require.NoError(t, err)
+		received[msg.MessageId] = msg
+	}
+
+	assert.Equal(t, ocpp.MessageTypeCallResult, received["1"].MessageTypeId)
+	assert.Equal(t, ocpp.MessageTypeCallError, received["2"].MessageTypeId)
+	assert.Equal(t, `"GenericError"`, string(received["2"].Data[0]))
+	assert.Equal(t, `"message rate exceeded"`, string(received["2"].Data[1]))
+
+	assert.Contains(t, readMetrics(t), "gateway_messages_rejected_total")
+}
+
+func readMetrics(t *testing.T) string {
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	w := httptest.NewRecorder()
+	server.NewStatusHandler(nil).ServeHTTP(w, req)
+	res := w.Result()
+	defer func() {
+		_ = res.Body.Close()
+	}()
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	b, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+	return string(b)
+}
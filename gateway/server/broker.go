@@ -7,9 +7,11 @@ import (
 	"github.com/mochi-co/mqtt/v2"
 	"github.com/mochi-co/mqtt/v2/hooks/auth"
 	"github.com/mochi-co/mqtt/v2/listeners"
+	natsServer "github.com/nats-io/nats-server/v2/server"
 	"net"
 	"net/url"
 	"testing"
+	"time"
 )
 
 func getFreePort() (port int, err error) {
@@ -50,3 +52,25 @@ func NewBroker(t *testing.T) (*mqtt.Server, *url.URL) {
 
 	return server, addr
 }
+
+// NewNatsServer starts an embedded NATS server, listening on a random port, that
+// can be used for testing. The server is shutdown when the test completes.
+func NewNatsServer(t *testing.T) string {
+	ns, err := natsServer.NewServer(&natsServer.Options{
+		Host:   "127.0.0.1",
+		Port:   natsServer.RANDOM_PORT,
+		NoLog:  true,
+		NoSigs: true,
+	})
+	if err != nil {
+		t.Fatalf("creating nats server: %v", err)
+	}
+
+	go ns.Start()
+	if !ns.ReadyForConnections(10 * time.Second) {
+		t.Fatalf("nats server not ready for connections")
+	}
+	t.Cleanup(ns.Shutdown)
+
+	return ns.ClientURL()
+}
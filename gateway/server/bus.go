@@ -0,0 +1,47 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"github.com/thoughtworks/maeve-csms/gateway/pipe"
+)
+
+// Bus is the message bus that links the gateway to the CSMS. Each websocket
+// connection from a charge station has its own BusConnection.
+type Bus interface {
+	// Connect establishes a connection to the bus for the charge station identified by
+	// clientId. Messages from the CSMS for the charge station are delivered to the
+	// BusHandler. The presence event is used by implementations that can ask the bus
+	// to publish a disconnection if the gateway goes away unexpectedly.
+	Connect(ctx context.Context, clientId, protocol string, presence PresenceEvent, handler BusHandler) (BusConnection, error)
+}
+
+// BusHandler receives the messages sent to a charge station by the CSMS.
+type BusHandler interface {
+	// HandleMessage is called for each message received from the CSMS.
+	HandleMessage(msg *pipe.GatewayMessage)
+	// HandleControl is called for each control message received from the CSMS.
+	HandleControl(msg *ControlMessage)
+	// HandleError is called when the connection to the bus can no longer be used.
+	HandleError(err error)
+}
+
+// BusConnection is used to send messages from a charge station to the CSMS.
+type BusConnection interface {
+	// Publish sends a message from the charge station to the CSMS.
+	Publish(ctx context.Context, msg *pipe.GatewayMessage) error
+	// PublishPresence sends a presence event for the charge station.
+	PublishPresence(ctx context.Context, event PresenceEvent) error
+	// Disconnect closes the connection to the bus.
+	Disconnect(ctx context.Context) error
+}
+
+// ControlListener is implemented by a Bus that can deliver the control messages that the
+// CSMS sends for every charge station, not just those connected to this gateway.
+type ControlListener interface {
+	// ListenForControl calls the handler with the charge station id and the control message
+	// for each control message sent by the CSMS until the context is done. The listenerId
+	// identifies the gateway to the bus.
+	ListenForControl(ctx context.Context, listenerId string, handler func(clientId string, msg *ControlMessage)) error
+}
@@ -0,0 +1,127 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package server_test
+
+import (
+	"context"
+	"github.com/eclipse/paho.golang/autopaho"
+	"github.com/eclipse/paho.golang/paho"
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/require"
+	"github.com/thoughtworks/maeve-csms/gateway/server"
+	"go.opentelemetry.io/otel/trace"
+	"net/url"
+	"testing"
+	"time"
+)
+
+type receivedControlMessage struct {
+	clientId string
+	msg      server.ControlMessage
+}
+
+func TestMqttBusListensForControlMessagesForAllChargeStations(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	broker, addr := server.NewBroker(t)
+	err := broker.Serve()
+	if err != nil {
+		t.Fatalf("starting broker: %v", err)
+	}
+	defer func() {
+		err := broker.Close()
+		if err != nil {
+			t.Logf("WARN: broker close: %v", err)
+		}
+	}()
+
+	bus := server.NewMqttBus([]*url.URL{addr}, "cs", 1*time.Second, 10*time.Second, trace.NewNoopTracerProvider().Tracer(""))
+
+	receivedCh := make(chan receivedControlMessage, 10)
+	err = bus.ListenForControl(ctx, "gateway1", func(clientId string, msg *server.ControlMessage) {
+		receivedCh <- receivedControlMessage{clientId: clientId, msg: *msg}
+	})
+	require.NoError(t, err)
+
+	// simulate manager connection
+	client, err := autopaho.NewConnection(ctx, autopaho.ClientConfig{
+		BrokerUrls:        []*url.URL{addr},
+		KeepAlive:         10,
+		ConnectRetryDelay: 2 * time.Second,
+		ClientConfig: paho.ClientConfig{
+			ClientID: "test",
+		},
+	})
+	require.NoError(t, err)
+	defer func() {
+		err := client.Disconnect(ctx)
+		if err != nil {
+			t.Logf("WARN: mqtt client disconnect: %v", err)
+		}
+	}()
+	err = client.AwaitConnection(ctx)
+	require.NoError(t, err)
+
+	// the listener subscribes asynchronously, so publish until the message is received
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		_, err = client.Publish(ctx, &paho.Publish{
+			Topic:   "cs/control/cs1",
+			QoS:     1,
+			Payload: []byte(`{"type":"Disconnect","reason":"credentials changed"}`),
+		})
+		require.NoError(t, err)
+
+		select {
+		case received := <-receivedCh:
+			require.Equal(t, "cs1", received.clientId)
+			require.Equal(t, server.ControlMessage{Type: server.ControlDisconnect, Reason: "credentials changed"}, received.msg)
+			return
+		case <-ticker.C:
+		case <-ctx.Done():
+			t.Fatal("timeout waiting for control message")
+		}
+	}
+}
+
+func TestNatsBusListensForControlMessagesForAllChargeStations(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	natsUrl := server.NewNatsServer(t)
+
+	bus := server.NewNatsBus([]string{natsUrl}, "cs", trace.NewNoopTracerProvider().Tracer(""))
+	defer func() {
+		_ = bus.Close()
+	}()
+
+	receivedCh := make(chan receivedControlMessage, 10)
+	err := bus.ListenForControl(ctx, "gateway1", func(clientId string, msg *server.ControlMessage) {
+		receivedCh <- receivedControlMessage{clientId: clientId, msg: *msg}
+	})
+	require.NoError(t, err)
+
+	// simulate manager connection
+	nc, err := nats.Connect(natsUrl)
+	require.NoError(t, err)
+	defer nc.Close()
+
+	err = nc.Publish("cs.control.cs1", []byte(`{"type":"Disconnect","reason":"charge station deleted"}`))
+	require.NoError(t, err)
+	err = nc.Publish("cs.control.cs2", []byte(`{"type":"Disconnect"}`))
+	require.NoError(t, err)
+
+	for _, want := range []receivedControlMessage{
+		{clientId: "cs1", msg: server.ControlMessage{Type: server.ControlDisconnect, Reason: "charge station deleted"}},
+		{clientId: "cs2", msg: server.ControlMessage{Type: server.ControlDisconnect}},
+	} {
+		select {
+		case received := <-receivedCh:
+			require.Equal(t, want, received)
+		case <-ctx.Done():
+			t.Fatal("timeout waiting for control message")
+		}
+	}
+}
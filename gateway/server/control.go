@@ -0,0 +1,25 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import "fmt"
+
+// ControlMessageType identifies the action that the gateway should take in
+// response to a ControlMessage
+type ControlMessageType string
+
+const (
+	// ControlDisconnect requests that the gateway closes the charge station's websocket
+	ControlDisconnect ControlMessageType = "Disconnect"
+)
+
+// ControlMessage is sent by the CSMS on the control topic (or subject) to manage the
+// connection of a charge station rather than to deliver an OCPP message to it.
+type ControlMessage struct {
+	Type   ControlMessageType `json:"type"`
+	Reason string             `json:"reason,omitempty"`
+}
+
+func controlTopic(topicPrefix, clientId string) string {
+	return fmt.Sprintf("%s/control/%s", topicPrefix, clientId)
+}
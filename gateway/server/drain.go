@@ -0,0 +1,213 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"golang.org/x/exp/slog"
+)
+
+var (
+	drainingGauge = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "gateway_draining",
+		Help: "Whether the gateway is draining its websocket connections (1) or not (0)",
+	})
+	drainedConnections = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "gateway_drained_connections_total",
+		Help: "The number of websocket connections closed by draining the gateway",
+	})
+)
+
+// Drainer gracefully closes the websocket connections when the gateway is shutting
+// down so that the charge stations do not all reconnect (to another gateway) at once.
+//
+// Once draining has started new connections are rejected, the pipe of each existing
+// connection stops sending calls from the CSMS to the charge station and the
+// connections are closed in batches: each connection is closed once any call that
+// is in progress has completed (or the call timeout has elapsed) after a random
+// delay of up to the jitter, and each batch starts batch interval after the
+// previous one has been closed.
+type Drainer struct {
+	sync.Mutex
+	batchSize     int
+	batchInterval time.Duration
+	jitter        time.Duration
+	callTimeout   time.Duration
+	draining      bool
+	done          chan struct{}
+	conns         map[*drainConn]struct{}
+	active        sync.WaitGroup
+}
+
+type drainConn struct {
+	drainPipe func() <-chan struct{}
+	close     func()
+}
+
+type DrainOpt func(*Drainer)
+
+// WithDrainBatchSize sets the number of connections that are closed in each batch
+func WithDrainBatchSize(batchSize int) DrainOpt {
+	return func(d *Drainer) {
+		d.batchSize = batchSize
+	}
+}
+
+// WithDrainBatchInterval sets the delay between closing one batch of connections and starting the next
+func WithDrainBatchInterval(interval time.Duration) DrainOpt {
+	return func(d *Drainer) {
+		d.batchInterval = interval
+	}
+}
+
+// WithDrainJitter sets the maximum random delay before each connection in a batch is closed
+func WithDrainJitter(jitter time.Duration) DrainOpt {
+	return func(d *Drainer) {
+		d.jitter = jitter
+	}
+}
+
+// WithDrainCallTimeout sets how long to wait for a call that is in progress to complete
+// before the connection is closed anyway
+func WithDrainCallTimeout(timeout time.Duration) DrainOpt {
+	return func(d *Drainer) {
+		d.callTimeout = timeout
+	}
+}
+
+func NewDrainer(opts ...DrainOpt) *Drainer {
+	d := &Drainer{
+		batchSize:     100,
+		batchInterval: 1 * time.Second,
+		jitter:        1 * time.Second,
+		callTimeout:   10 * time.Second,
+		done:          make(chan struct{}),
+		conns:         make(map[*drainConn]struct{}),
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	if d.batchSize < 1 {
+		d.batchSize = 1
+	}
+	return d
+}
+
+// Draining returns true once draining has started
+func (d *Drainer) Draining() bool {
+	d.Lock()
+	defer d.Unlock()
+	return d.draining
+}
+
+// Start begins draining the connections, if draining has not already started,
+// and returns immediately
+func (d *Drainer) Start() {
+	d.Lock()
+	defer d.Unlock()
+	if d.draining {
+		return
+	}
+	d.draining = true
+	drainingGauge.Set(1)
+
+	conns := make([]*drainConn, 0, len(d.conns))
+	for c := range d.conns {
+		conns = append(conns, c)
+	}
+	go d.drain(conns)
+}
+
+// Drain starts draining the connections, if draining has not already started, and waits
+// until all the connections have been closed or the context is done
+func (d *Drainer) Drain(ctx context.Context) error {
+	d.Start()
+	select {
+	case <-d.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Done returns a channel that is closed once draining has completed
+func (d *Drainer) Done() <-chan struct{} {
+	return d.done
+}
+
+// register adds a connection that will be closed when draining. The drainPipe function
+// must stop new calls being sent to the charge station and return a channel that is closed
+// when there is no call in progress. It returns false if the gateway is already draining
+// in which case the connection should be closed immediately.
+func (d *Drainer) register(drainPipe func() <-chan struct{}, closeConn func()) (unregister func(), ok bool) {
+	d.Lock()
+	defer d.Unlock()
+	if d.draining {
+		return nil, false
+	}
+	c := &drainConn{
+		drainPipe: drainPipe,
+		close:     closeConn,
+	}
+	d.conns[c] = struct{}{}
+	d.active.Add(1)
+	return func() {
+		d.Lock()
+		defer d.Unlock()
+		if _, ok := d.conns[c]; ok {
+			delete(d.conns, c)
+			d.active.Done()
+		}
+	}, true
+}
+
+func (d *Drainer) drain(conns []*drainConn) {
+	defer close(d.done)
+
+	slog.Info("draining websocket connections", "connections", len(conns))
+
+	// stop sending calls from the CSMS to every charge station straight away
+	idle := make([]<-chan struct{}, len(conns))
+	for i, c := range conns {
+		idle[i] = c.drainPipe()
+	}
+
+	for start := 0; start < len(conns); start += d.batchSize {
+		if start > 0 {
+			time.Sleep(d.batchInterval)
+		}
+		end := start + d.batchSize
+		if end > len(conns) {
+			end = len(conns)
+		}
+
+		var wg sync.WaitGroup
+		for i := start; i < end; i++ {
+			wg.Add(1)
+			go func(c *drainConn, idle <-chan struct{}) {
+				defer wg.Done()
+				if d.jitter > 0 {
+					time.Sleep(time.Duration(rand.Int63n(int64(d.jitter))))
+				}
+				select {
+				case <-idle:
+				case <-time.After(d.callTimeout):
+					slog.Warn("closing connection with call in progress")
+				}
+				c.close()
+				drainedConnections.Inc()
+			}(conns[i], idle[i])
+		}
+		wg.Wait()
+	}
+
+	// wait for the connections to finish closing (and publish their presence events)
+	d.active.Wait()
+	slog.Info("drained websocket connections", "connections", len(conns))
+}
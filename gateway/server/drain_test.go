@@ -0,0 +1,159 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package server_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/require"
+	"github.com/thoughtworks/maeve-csms/gateway/ocpp"
+	"github.com/thoughtworks/maeve-csms/gateway/pipe"
+	"github.com/thoughtworks/maeve-csms/gateway/registry"
+	"github.com/thoughtworks/maeve-csms/gateway/server"
+	"go.opentelemetry.io/otel/trace"
+	"net/http"
+	"net/http/httptest"
+	"nhooyr.io/websocket"
+	"testing"
+	"time"
+)
+
+func TestDrainClosesConnectionsOnceCallsComplete(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	natsUrl := server.NewNatsServer(t)
+
+	// simulate manager connection
+	nc, err := nats.Connect(natsUrl)
+	require.NoError(t, err)
+	defer nc.Close()
+
+	connectedCh := make(chan string, 2)
+	_, err = nc.Subscribe("cs.presence.*", func(natsMsg *nats.Msg) {
+		var event server.PresenceEvent
+		err := json.Unmarshal(natsMsg.Data, &event)
+		require.NoError(t, err)
+		if event.Type == server.PresenceConnected {
+			connectedCh <- natsMsg.Subject
+		}
+	})
+	require.NoError(t, err)
+	responseCh := make(chan *pipe.GatewayMessage, 1)
+	_, err = nc.Subscribe("cs.in.ocpp201.cs1", func(natsMsg *nats.Msg) {
+		var msg pipe.GatewayMessage
+		err := json.Unmarshal(natsMsg.Data, &msg)
+		require.NoError(t, err)
+		responseCh <- &msg
+	})
+	require.NoError(t, err)
+	require.NoError(t, nc.Flush())
+
+	mockRegistry := registry.NewMockRegistry()
+	for _, csId := range []string{"cs1", "cs2", "cs3"} {
+		mockRegistry.ChargeStations[csId] = &registry.ChargeStation{
+			ClientId:             csId,
+			SecurityProfile:      registry.UnsecuredTransportWithBasicAuth,
+			Base64SHA256Password: "XohImNooBHFR0OVvjcYpJ3NgPQ1qq73WKhHvch0VQtg=", // password
+		}
+	}
+
+	bus := server.NewNatsBus([]string{natsUrl}, "cs", trace.NewNoopTracerProvider().Tracer(""))
+	defer func() {
+		_ = bus.Close()
+	}()
+
+	drainer := server.NewDrainer(
+		server.WithDrainBatchSize(2),
+		server.WithDrainBatchInterval(10*time.Millisecond),
+		server.WithDrainJitter(0),
+		server.WithDrainCallTimeout(10*time.Second))
+
+	srv := httptest.NewServer(server.NewWebsocketHandler(
+		server.WithBus(bus),
+		server.WithDeviceRegistry(mockRegistry),
+		server.WithDrainer(drainer)))
+	defer srv.Close()
+
+	dial := func(csId string) (*websocket.Conn, *http.Response, error) {
+		authHeader := "Basic " + base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%s:%s", csId, "password")))
+		return websocket.Dial(ctx, fmt.Sprintf("%s/ws/%s", srv.URL, csId), &websocket.DialOptions{
+			Subprotocols: []string{"ocpp2.0.1"},
+			HTTPHeader: http.Header{
+				"authorization": []string{authHeader},
+			},
+		})
+	}
+
+	cs1, _, err := dial("cs1")
+	require.NoError(t, err)
+	defer func() {
+		_ = cs1.Close(websocket.StatusNormalClosure, "OK")
+	}()
+	cs2, _, err := dial("cs2")
+	require.NoError(t, err)
+	defer func() {
+		_ = cs2.Close(websocket.StatusNormalClosure, "OK")
+	}()
+
+	for i := 0; i < 2; i++ {
+		select {
+		case <-connectedCh:
+		case <-ctx.Done():
+			t.Fatal("timeout waiting for connected event")
+		}
+	}
+
+	// the CSMS makes a call to cs1
+	b, err := json.Marshal(pipe.GatewayMessage{
+		MessageType:    ocpp.MessageTypeCall,
+		Action:         "Reset",
+		MessageId:      "1",
+		RequestPayload: []byte(`{"type":"Immediate"}`),
+	})
+	require.NoError(t, err)
+	require.NoError(t, nc.Publish("cs.out.ocpp201.cs1", b))
+
+	_, _, err = cs1.Read(ctx)
+	require.NoError(t, err)
+
+	drainer.Start()
+
+	// cs2 has no call in progress so is closed
+	_, _, err = cs2.Read(ctx)
+	var closeErr websocket.CloseError
+	require.ErrorAs(t, err, &closeErr)
+	require.Equal(t, websocket.StatusGoingAway, closeErr.Code)
+
+	// new connections are rejected
+	_, resp, err := dial("cs3")
+	require.Error(t, err)
+	require.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+
+	// cs1 is not closed until it has responded to the CSMS call
+	select {
+	case <-drainer.Done():
+		t.Fatal("drain completed with call in progress")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	err = cs1.Write(ctx, websocket.MessageText, []byte(`[3,"1",{"status":"Accepted"}]`))
+	require.NoError(t, err)
+
+	select {
+	case msg := <-responseCh:
+		require.Equal(t, ocpp.MessageTypeCallResult, msg.MessageType)
+		require.Equal(t, "Reset", msg.Action)
+	case <-ctx.Done():
+		t.Fatal("timeout waiting for call result")
+	}
+
+	_, _, err = cs1.Read(ctx)
+	require.ErrorAs(t, err, &closeErr)
+	require.Equal(t, websocket.StatusGoingAway, closeErr.Code)
+
+	require.NoError(t, drainer.Drain(ctx))
+}
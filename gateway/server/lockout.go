@@ -0,0 +1,158 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/thoughtworks/maeve-csms/gateway/registry"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/exp/slog"
+	"k8s.io/utils/clock"
+)
+
+// authGuard refuses basic auth attempts from charge stations or source addresses that
+// have been locked out after repeated failures. The failures are recorded by the
+// AuthLockoutRegistry so that they are shared by all gateways.
+//
+// The lockouts (and the absence of a lockout) are cached so that a client cannot generate
+// a registry request for every attempt, but they are rechecked every recheckInterval so that
+// a lockout that has been cleared stops being enforced and a lockout that has been recorded
+// by another gateway starts being enforced.
+type authGuard struct {
+	sync.Mutex
+	registry        registry.AuthLockoutRegistry
+	clock           clock.PassiveClock
+	recheckInterval time.Duration
+	lockouts        map[string]*cachedLockout
+}
+
+type cachedLockout struct {
+	lockedUntil time.Time
+	checkedAt   time.Time
+}
+
+func newAuthGuard(lockoutRegistry registry.AuthLockoutRegistry, clock clock.PassiveClock, recheckInterval time.Duration) *authGuard {
+	return &authGuard{
+		registry:        lockoutRegistry,
+		clock:           clock,
+		recheckInterval: recheckInterval,
+		lockouts:        make(map[string]*cachedLockout),
+	}
+}
+
+func chargeStationLockoutKey(clientId string) string {
+	return "cs/" + clientId
+}
+
+func remoteAddrLockoutKey(remoteAddr string) string {
+	return "addr/" + remoteAddr
+}
+
+// lockedUntil returns when the lockout that applies to an attempt by the charge station
+// from the source address expires, or a zero time if there is no lockout
+func (g *authGuard) lockedUntil(ctx context.Context, clientId, remoteAddr string) time.Time {
+	now := g.clock.Now()
+	keys := []string{chargeStationLockoutKey(clientId), remoteAddrLockoutKey(remoteAddr)}
+
+	g.Lock()
+	var lockedUntil time.Time
+	recheck := false
+	for _, key := range keys {
+		lockout, ok := g.lockouts[key]
+		if !ok || now.Sub(lockout.checkedAt) >= g.recheckInterval {
+			recheck = true
+		}
+		if ok && lockout.lockedUntil.After(now) && lockout.lockedUntil.After(lockedUntil) {
+			lockedUntil = lockout.lockedUntil
+		}
+	}
+	g.Unlock()
+
+	if !recheck {
+		return lockedUntil
+	}
+
+	lockout, err := g.registry.LookupAuthLockout(clientId, remoteAddr)
+	if err != nil {
+		// continue to enforce the cached lockout until it can be checked
+		slog.Warn("looking up auth lockout", "err", err, "csId", clientId)
+		trace.SpanFromContext(ctx).RecordError(err)
+		return lockedUntil
+	}
+	g.update(clientId, remoteAddr, lockout)
+
+	lockedUntil = lockout.LockedUntil()
+	if !lockedUntil.After(now) {
+		return time.Time{}
+	}
+	return lockedUntil
+}
+
+// recordFailure records a failed attempt by the charge station from the source address
+// and returns when the resulting lockout expires, or a zero time if there is no lockout
+func (g *authGuard) recordFailure(ctx context.Context, clientId, remoteAddr string) time.Time {
+	span := trace.SpanFromContext(ctx)
+
+	slog.Warn("security event: basic auth failed", "csId", clientId, "remoteAddr", remoteAddr)
+	span.AddEvent("security_event", trace.WithAttributes(
+		attribute.String("security_event.type", "AuthFailure"),
+		attribute.String("auth.remote_addr", remoteAddr)))
+
+	lockout, err := g.registry.RecordAuthFailure(clientId, remoteAddr)
+	if err != nil {
+		slog.Warn("recording auth failure", "err", err, "csId", clientId)
+		span.RecordError(err)
+		return time.Time{}
+	}
+	g.update(clientId, remoteAddr, lockout)
+
+	lockedUntil := lockout.LockedUntil()
+	if !lockedUntil.After(g.clock.Now()) {
+		return time.Time{}
+	}
+
+	slog.Warn("security event: basic auth locked out", "csId", clientId, "remoteAddr", remoteAddr,
+		"lockedUntil", lockedUntil)
+	span.AddEvent("security_event", trace.WithAttributes(
+		attribute.String("security_event.type", "AuthLockout"),
+		attribute.String("auth.locked_until", lockedUntil.Format(time.RFC3339))))
+
+	return lockedUntil
+}
+
+func (g *authGuard) update(clientId, remoteAddr string, lockout *registry.AuthLockout) {
+	g.Lock()
+	defer g.Unlock()
+
+	now := g.clock.Now()
+	g.set(chargeStationLockoutKey(clientId), lockout.ChargeStationLockedUntil, now)
+	g.set(remoteAddrLockoutKey(remoteAddr), lockout.RemoteAddrLockedUntil, now)
+
+	for key, cached := range g.lockouts {
+		if !cached.lockedUntil.After(now) && now.Sub(cached.checkedAt) >= g.recheckInterval {
+			delete(g.lockouts, key)
+		}
+	}
+}
+
+func (g *authGuard) set(key string, lockedUntil, now time.Time) {
+	g.lockouts[key] = &cachedLockout{
+		lockedUntil: lockedUntil,
+		checkedAt:   now,
+	}
+}
+
+// remoteHost returns the host part of a remote address so that all connections from
+// the same source are counted together
+func remoteHost(remoteAddr string) string {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		return remoteAddr
+	}
+	return host
+}
@@ -0,0 +1,137 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package server_test
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thoughtworks/maeve-csms/gateway/registry"
+	"github.com/thoughtworks/maeve-csms/gateway/server"
+	"net/http"
+	"net/http/httptest"
+	"nhooyr.io/websocket"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func basicAuthDialOptions(clientId, password string) *websocket.DialOptions {
+	authHeader := "Basic " + base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%s:%s", clientId, password)))
+	return &websocket.DialOptions{
+		Subprotocols: []string{"ocpp1.6", "ocpp2.0.1"},
+		HTTPHeader: http.Header{
+			"authorization": []string{authHeader},
+		},
+	}
+}
+
+func TestHttpConnectionWithBasicAuthWrongPasswordRecordsFailure(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cs := &registry.ChargeStation{
+		ClientId:             "cs001",
+		SecurityProfile:      registry.UnsecuredTransportWithBasicAuth,
+		Base64SHA256Password: "XohImNooBHFR0OVvjcYpJ3NgPQ1qq73WKhHvch0VQtg=", // password
+	}
+
+	mockRegistry := registry.NewMockRegistry()
+	mockRegistry.ChargeStations[cs.ClientId] = cs
+
+	srv := httptest.NewServer(server.NewWebsocketHandler(
+		server.WithDeviceRegistry(mockRegistry),
+		server.WithAuthLockoutRegistry(mockRegistry)))
+	defer srv.Close()
+
+	_, resp, err := websocket.Dial(ctx, fmt.Sprintf("%s/ws/%s", srv.URL, cs.ClientId),
+		basicAuthDialOptions(cs.ClientId, "wrong"))
+	require.Error(t, err)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	assert.Equal(t, 1, mockRegistry.AuthFailures[cs.ClientId])
+
+	// an attempt without credentials is not a failed guess
+	_, resp, err = websocket.Dial(ctx, fmt.Sprintf("%s/ws/%s", srv.URL, cs.ClientId), nil)
+	require.Error(t, err)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	assert.Equal(t, 1, mockRegistry.AuthFailures[cs.ClientId])
+}
+
+func TestHttpConnectionWithBasicAuthLockedOut(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cs := &registry.ChargeStation{
+		ClientId:             "cs001",
+		SecurityProfile:      registry.UnsecuredTransportWithBasicAuth,
+		Base64SHA256Password: "XohImNooBHFR0OVvjcYpJ3NgPQ1qq73WKhHvch0VQtg=", // password
+	}
+
+	mockRegistry := registry.NewMockRegistry()
+	mockRegistry.ChargeStations[cs.ClientId] = cs
+	mockRegistry.AuthLockouts[cs.ClientId] = &registry.AuthLockout{
+		ChargeStationLockedUntil: time.Now().Add(time.Minute),
+	}
+
+	srv := httptest.NewServer(server.NewWebsocketHandler(
+		server.WithDeviceRegistry(mockRegistry),
+		server.WithAuthLockoutRegistry(mockRegistry)))
+	defer srv.Close()
+
+	// the lockout is enforced without checking the password, so no failure is recorded
+	_, resp, err := websocket.Dial(ctx, fmt.Sprintf("%s/ws/%s", srv.URL, cs.ClientId),
+		basicAuthDialOptions(cs.ClientId, "password"))
+	require.Error(t, err)
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	retryAfter, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	require.NoError(t, err)
+	assert.InDelta(t, 60, retryAfter, 1)
+
+	_, resp, err = websocket.Dial(ctx, fmt.Sprintf("%s/ws/%s", srv.URL, cs.ClientId),
+		basicAuthDialOptions(cs.ClientId, "wrong"))
+	require.Error(t, err)
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(t, 0, mockRegistry.AuthFailures[cs.ClientId])
+}
+
+func TestHttpConnectionWithBasicAuthLockedOutByAnotherGateway(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cs := &registry.ChargeStation{
+		ClientId:             "cs001",
+		SecurityProfile:      registry.UnsecuredTransportWithBasicAuth,
+		Base64SHA256Password: "XohImNooBHFR0OVvjcYpJ3NgPQ1qq73WKhHvch0VQtg=", // password
+	}
+
+	mockRegistry := registry.NewMockRegistry()
+	mockRegistry.ChargeStations[cs.ClientId] = cs
+
+	srv1 := httptest.NewServer(server.NewWebsocketHandler(
+		server.WithDeviceRegistry(mockRegistry),
+		server.WithAuthLockoutRegistry(mockRegistry)))
+	defer srv1.Close()
+	srv2 := httptest.NewServer(server.NewWebsocketHandler(
+		server.WithDeviceRegistry(mockRegistry),
+		server.WithAuthLockoutRegistry(mockRegistry)))
+	defer srv2.Close()
+
+	_, resp, err := websocket.Dial(ctx, fmt.Sprintf("%s/ws/%s", srv1.URL, cs.ClientId),
+		basicAuthDialOptions(cs.ClientId, "wrong"))
+	require.Error(t, err)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	assert.Equal(t, 1, mockRegistry.AuthFailures[cs.ClientId])
+
+	// the manager locks out the charge station after the failures reported by the first gateway
+	mockRegistry.AuthLockouts[cs.ClientId] = &registry.AuthLockout{
+		ChargeStationLockedUntil: time.Now().Add(time.Minute),
+	}
+
+	// the lockout is enforced by the second gateway, which has nothing cached for the charge station
+	_, resp, err = websocket.Dial(ctx, fmt.Sprintf("%s/ws/%s", srv2.URL, cs.ClientId),
+		basicAuthDialOptions(cs.ClientId, "password"))
+	require.Error(t, err)
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+}
@@ -0,0 +1,270 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/eclipse/paho.golang/autopaho"
+	"github.com/eclipse/paho.golang/paho"
+	"github.com/thoughtworks/maeve-csms/gateway/pipe"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/exp/slog"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// MqttBus is an implementation of Bus that uses MQTT. Each charge station
+// has its own MQTT connection that uses the charge station id as the client id.
+//
+// Messages to the CSMS are published on <prefix>/in/<protocol>/<cs-id> and messages
+// from the CSMS are received on <prefix>/out/<protocol>/<cs-id>. Control messages
+// from the CSMS are received on <prefix>/control/<cs-id>. The trace context is
+// propagated in the MQTT correlation data.
+type MqttBus struct {
+	brokerUrls        []*url.URL
+	topicPrefix       string
+	connectRetryDelay time.Duration
+	keepAliveInterval uint16
+	tracer            trace.Tracer
+}
+
+func NewMqttBus(brokerUrls []*url.URL, topicPrefix string, connectRetryDelay, keepAliveInterval time.Duration, tracer trace.Tracer) *MqttBus {
+	return &MqttBus{
+		brokerUrls:        brokerUrls,
+		topicPrefix:       topicPrefix,
+		connectRetryDelay: connectRetryDelay,
+		keepAliveInterval: uint16(keepAliveInterval.Round(time.Second).Seconds()),
+		tracer:            tracer,
+	}
+}
+
+func (b *MqttBus) Connect(ctx context.Context, clientId, protocol string, presence PresenceEvent, handler BusHandler) (BusConnection, error) {
+	span := trace.SpanFromContext(ctx)
+
+	mqttBrokerURLStrings := make([]string, len(b.brokerUrls))
+	for i, u := range b.brokerUrls {
+		mqttBrokerURLStrings[i] = u.String()
+	}
+	span.SetAttributes(attribute.StringSlice("mqtt.broker_urls", mqttBrokerURLStrings))
+
+	controlTopicName := controlTopic(b.topicPrefix, clientId)
+
+	// the broker publishes the presence will when the connection to it is lost, so the
+	// charge station must be reported as connected again each time the connection is restored
+	var reconnecting atomic.Bool
+
+	mqttConfig := autopaho.ClientConfig{
+		BrokerUrls:        b.brokerUrls,
+		KeepAlive:         b.keepAliveInterval,
+		ConnectRetryDelay: b.connectRetryDelay,
+		OnConnectionUp: func(manager *autopaho.ConnectionManager, connack *paho.Connack) {
+			topicName := fmt.Sprintf("%s/out/%s/%s", b.topicPrefix, protocol, clientId)
+			span.SetAttributes(attribute.String("mqtt.topic", topicName))
+			_, err := manager.Subscribe(ctx, &paho.Subscribe{
+				Subscriptions: map[string]paho.SubscribeOptions{
+					topicName:        {},
+					controlTopicName: {QoS: 1},
+				},
+			})
+			if err != nil {
+				span.SetStatus(codes.Error, "subscribing to mqtt topic failed")
+				span.RecordError(err)
+				handler.HandleError(fmt.Errorf("subscribing to %s: %w", topicName, err))
+				return
+			}
+
+			if reconnecting.Swap(true) {
+				connected := presence
+				connected.Type = PresenceConnected
+				err = publishPresence(ctx, manager, b.topicPrefix, clientId, connected)
+				if err != nil {
+					slog.Warn("publishing presence after reconnecting to mqtt", "err", err, "csId", clientId)
+				}
+			}
+		},
+		ClientConfig: paho.ClientConfig{
+			ClientID: clientId,
+			Router: paho.NewSingleHandlerRouter(func(mqttMsg *paho.Publish) {
+				if mqttMsg.Topic == controlTopicName {
+					var msg ControlMessage
+					err := json.Unmarshal(mqttMsg.Payload, &msg)
+					if err != nil {
+						slog.Error("unmarshalling CSMS control message", "err", err)
+						return
+					}
+					handler.HandleControl(&msg)
+					return
+				}
+
+				// route requests from the CSMS
+				var msg pipe.GatewayMessage
+				err := json.Unmarshal(mqttMsg.Payload, &msg)
+				if err != nil {
+					slog.Error("unmarshalling CSMS message", "err", err)
+					return
+				}
+
+				correlationMap := make(map[string]string)
+				err = json.Unmarshal(mqttMsg.Properties.CorrelationData, &correlationMap)
+				if err != nil {
+					slog.Warn("unmarshalling correlation map", "err", err)
+				}
+				requestContext := otel.GetTextMapPropagator().Extract(context.Background(), propagation.MapCarrier(correlationMap))
+
+				newCtx, span := b.tracer.Start(requestContext, fmt.Sprintf("%s/out/%s/# receive", b.topicPrefix, protocol),
+					trace.WithSpanKind(trace.SpanKindConsumer),
+					trace.WithAttributes(
+						semconv.MessagingSystem("mqtt"),
+						semconv.MessagingMessagePayloadSizeBytes(len(mqttMsg.Payload)),
+						semconv.MessagingMessageConversationID(msg.MessageId),
+						semconv.MessagingOperationKey.String("receive"),
+						attribute.String("csId", clientId),
+					))
+				defer span.End()
+
+				msg.Context = newCtx
+
+				handler.HandleMessage(&msg)
+			}),
+			OnServerDisconnect: func(disconnect *paho.Disconnect) {
+				span.SetAttributes(attribute.String("mqtt.disconnect_reason", disconnect.Properties.ReasonString))
+			},
+		},
+	}
+	err := setPresenceWill(&mqttConfig, b.topicPrefix, clientId, presence)
+	if err != nil {
+		slog.Warn("setting presence will message", "err", err)
+	}
+
+	mqttConn, err := autopaho.NewConnection(ctx, mqttConfig)
+	if err != nil {
+		slog.Error("connecting to mqtt", "mqttBrokerURLs", b.brokerUrls, "err", err)
+		return nil, fmt.Errorf("connecting to mqtt: %w", err)
+	}
+	err = mqttConn.AwaitConnection(ctx)
+	if err != nil {
+		slog.Error("waiting for mqtt", "mqttBrokerURLs", b.brokerUrls, "err", err)
+		_ = mqttConn.Disconnect(context.Background())
+		return nil, fmt.Errorf("waiting for mqtt: %w", err)
+	}
+
+	return &mqttBusConnection{
+		mqttConn:    mqttConn,
+		tracer:      b.tracer,
+		topicPrefix: b.topicPrefix,
+		protocol:    protocol,
+		clientId:    clientId,
+	}, nil
+}
+
+// ListenForControl subscribes to <prefix>/control/+ using its own MQTT connection, with the
+// client id <listenerId>-control, which is closed when the context is done.
+func (b *MqttBus) ListenForControl(ctx context.Context, listenerId string, handler func(clientId string, msg *ControlMessage)) error {
+	topicName := controlTopic(b.topicPrefix, "+")
+	topicNamePrefix := controlTopic(b.topicPrefix, "")
+
+	mqttConfig := autopaho.ClientConfig{
+		BrokerUrls:        b.brokerUrls,
+		KeepAlive:         b.keepAliveInterval,
+		ConnectRetryDelay: b.connectRetryDelay,
+		OnConnectionUp: func(manager *autopaho.ConnectionManager, connack *paho.Connack) {
+			_, err := manager.Subscribe(ctx, &paho.Subscribe{
+				Subscriptions: map[string]paho.SubscribeOptions{
+					topicName: {QoS: 1},
+				},
+			})
+			if err != nil {
+				slog.Error("subscribing to mqtt topic", "topic", topicName, "err", err)
+			}
+		},
+		ClientConfig: paho.ClientConfig{
+			ClientID: fmt.Sprintf("%s-control", listenerId),
+			Router: paho.NewSingleHandlerRouter(func(mqttMsg *paho.Publish) {
+				var msg ControlMessage
+				err := json.Unmarshal(mqttMsg.Payload, &msg)
+				if err != nil {
+					slog.Error("unmarshalling CSMS control message", "err", err)
+					return
+				}
+				handler(strings.TrimPrefix(mqttMsg.Topic, topicNamePrefix), &msg)
+			}),
+		},
+	}
+
+	mqttConn, err := autopaho.NewConnection(ctx, mqttConfig)
+	if err != nil {
+		return fmt.Errorf("connecting to mqtt: %w", err)
+	}
+	go func() {
+		<-ctx.Done()
+		_ = mqttConn.Disconnect(context.Background())
+	}()
+
+	return nil
+}
+
+type mqttBusConnection struct {
+	mqttConn    *autopaho.ConnectionManager
+	tracer      trace.Tracer
+	topicPrefix string
+	protocol    string
+	clientId    string
+}
+
+func (c *mqttBusConnection) Publish(ctx context.Context, msg *pipe.GatewayMessage) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("marshaling message for publication: %w", err)
+	}
+
+	topic := fmt.Sprintf("%s/in/%s/%s", c.topicPrefix, c.protocol, c.clientId)
+
+	newCtx, span := c.tracer.Start(ctx,
+		fmt.Sprintf("%s/in/%s/# publish", c.topicPrefix, c.protocol),
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			semconv.MessagingSystem("mqtt"),
+			semconv.MessagingMessagePayloadSizeBytes(len(data)),
+			semconv.MessagingOperationKey.String("publish"),
+			semconv.MessagingMessageConversationID(msg.MessageId),
+			attribute.String("csId", c.clientId),
+		))
+	defer span.End()
+
+	correlationMap := make(map[string]string)
+	otel.GetTextMapPropagator().Inject(newCtx, propagation.MapCarrier(correlationMap))
+
+	correlationData, err := json.Marshal(correlationMap)
+	if err != nil {
+		slog.Warn("marshalling correlation map: %v", err)
+	}
+
+	_, err = c.mqttConn.Publish(newCtx, &paho.Publish{
+		Topic:   topic,
+		Payload: data,
+		Properties: &paho.PublishProperties{
+			ContentType:     "application/json",
+			ResponseTopic:   fmt.Sprintf("%s/out/%s/%s", c.topicPrefix, c.protocol, c.clientId),
+			CorrelationData: correlationData,
+		},
+	})
+
+	return err
+}
+
+func (c *mqttBusConnection) PublishPresence(ctx context.Context, event PresenceEvent) error {
+	return publishPresence(ctx, c.mqttConn, c.topicPrefix, c.clientId, event)
+}
+
+func (c *mqttBusConnection) Disconnect(ctx context.Context) error {
+	return c.mqttConn.Disconnect(ctx)
+}
@@ -0,0 +1,257 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/nats-io/nats.go"
+	"github.com/thoughtworks/maeve-csms/gateway/pipe"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/exp/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// NatsBus is an implementation of Bus that uses NATS. All the charge stations
+// connected to the gateway share a single NATS connection.
+//
+// Messages to the CSMS are published on <prefix>.in.<protocol>.<cs-id> and messages
+// from the CSMS are received on <prefix>.out.<protocol>.<cs-id>, where the protocol
+// has the '.' characters removed (e.g. ocpp16). Control messages from the CSMS are
+// received on <prefix>.control.<cs-id>. Presence events are published on
+// <prefix>.presence.<cs-id>. The trace context is propagated in the message headers.
+//
+// NATS has no equivalent of an MQTT will message so no disconnection event will be
+// published for the connected charge stations if the gateway exits unexpectedly.
+type NatsBus struct {
+	sync.Mutex
+	natsUrls      []string
+	subjectPrefix string
+	tracer        trace.Tracer
+	conn          *nats.Conn
+}
+
+func NewNatsBus(natsUrls []string, subjectPrefix string, tracer trace.Tracer) *NatsBus {
+	return &NatsBus{
+		natsUrls:      natsUrls,
+		subjectPrefix: subjectPrefix,
+		tracer:        tracer,
+	}
+}
+
+func (b *NatsBus) Connect(ctx context.Context, clientId, protocol string, _ PresenceEvent, handler BusHandler) (BusConnection, error) {
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(attribute.StringSlice("nats.urls", b.natsUrls))
+
+	if clientId == "" || strings.ContainsAny(clientId, ".*> \t\r\n") {
+		return nil, fmt.Errorf("charge station id %q cannot be used in a nats subject", clientId)
+	}
+
+	conn, err := b.ensureConnection()
+	if err != nil {
+		return nil, fmt.Errorf("connecting to nats: %w", err)
+	}
+
+	protocolToken := strings.ReplaceAll(protocol, ".", "")
+	subject := fmt.Sprintf("%s.out.%s.%s", b.subjectPrefix, protocolToken, clientId)
+	span.SetAttributes(attribute.String("nats.subject", subject))
+
+	sub, err := conn.Subscribe(subject, func(natsMsg *nats.Msg) {
+		// route requests from the CSMS
+		var msg pipe.GatewayMessage
+		err := json.Unmarshal(natsMsg.Data, &msg)
+		if err != nil {
+			slog.Error("unmarshalling CSMS message", "err", err)
+			return
+		}
+
+		requestContext := context.Background()
+		if natsMsg.Header != nil {
+			requestContext = otel.GetTextMapPropagator().Extract(requestContext, propagation.HeaderCarrier(http.Header(natsMsg.Header)))
+		}
+
+		newCtx, span := b.tracer.Start(requestContext, fmt.Sprintf("%s.out.%s.* receive", b.subjectPrefix, protocolToken),
+			trace.WithSpanKind(trace.SpanKindConsumer),
+			trace.WithAttributes(
+				semconv.MessagingSystem("nats"),
+				semconv.MessagingMessagePayloadSizeBytes(len(natsMsg.Data)),
+				semconv.MessagingMessageConversationID(msg.MessageId),
+				semconv.MessagingOperationKey.String("receive"),
+				attribute.String("csId", clientId),
+			))
+		defer span.End()
+
+		msg.Context = newCtx
+
+		handler.HandleMessage(&msg)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("subscribing to %s: %w", subject, err)
+	}
+
+	controlSubject := fmt.Sprintf("%s.control.%s", b.subjectPrefix, clientId)
+	controlSub, err := conn.Subscribe(controlSubject, func(natsMsg *nats.Msg) {
+		var msg ControlMessage
+		err := json.Unmarshal(natsMsg.Data, &msg)
+		if err != nil {
+			slog.Error("unmarshalling CSMS control message", "err", err)
+			return
+		}
+		handler.HandleControl(&msg)
+	})
+	if err != nil {
+		_ = sub.Unsubscribe()
+		return nil, fmt.Errorf("subscribing to %s: %w", controlSubject, err)
+	}
+
+	// ensure the subscriptions have been processed by the server before returning
+	err = conn.Flush()
+	if err != nil {
+		_ = sub.Unsubscribe()
+		_ = controlSub.Unsubscribe()
+		return nil, fmt.Errorf("subscribing to %s: %w", subject, err)
+	}
+
+	return &natsBusConnection{
+		conn:          conn,
+		sub:           sub,
+		controlSub:    controlSub,
+		tracer:        b.tracer,
+		subjectPrefix: b.subjectPrefix,
+		protocolToken: protocolToken,
+		clientId:      clientId,
+	}, nil
+}
+
+// ListenForControl subscribes to <prefix>.control.* using the shared NATS connection until
+// the context is done.
+func (b *NatsBus) ListenForControl(ctx context.Context, _ string, handler func(clientId string, msg *ControlMessage)) error {
+	conn, err := b.ensureConnection()
+	if err != nil {
+		return fmt.Errorf("connecting to nats: %w", err)
+	}
+
+	subject := fmt.Sprintf("%s.control.*", b.subjectPrefix)
+	subjectPrefix := fmt.Sprintf("%s.control.", b.subjectPrefix)
+	sub, err := conn.Subscribe(subject, func(natsMsg *nats.Msg) {
+		var msg ControlMessage
+		err := json.Unmarshal(natsMsg.Data, &msg)
+		if err != nil {
+			slog.Error("unmarshalling CSMS control message", "err", err)
+			return
+		}
+		handler(strings.TrimPrefix(natsMsg.Subject, subjectPrefix), &msg)
+	})
+	if err != nil {
+		return fmt.Errorf("subscribing to %s: %w", subject, err)
+	}
+
+	// ensure the subscription has been processed by the server before returning
+	err = conn.Flush()
+	if err != nil {
+		_ = sub.Unsubscribe()
+		return fmt.Errorf("subscribing to %s: %w", subject, err)
+	}
+
+	go func() {
+		<-ctx.Done()
+		_ = sub.Unsubscribe()
+	}()
+
+	return nil
+}
+
+// Close drains the shared NATS connection.
+func (b *NatsBus) Close() error {
+	b.Lock()
+	defer b.Unlock()
+	if b.conn != nil {
+		err := b.conn.Drain()
+		b.conn = nil
+		return err
+	}
+	return nil
+}
+
+func (b *NatsBus) ensureConnection() (*nats.Conn, error) {
+	b.Lock()
+	defer b.Unlock()
+	if b.conn == nil {
+		conn, err := nats.Connect(strings.Join(b.natsUrls, ","),
+			nats.Name("gateway"),
+			nats.MaxReconnects(-1),
+			nats.ReconnectWait(1*time.Second))
+		if err != nil {
+			return nil, err
+		}
+		b.conn = conn
+	}
+	return b.conn, nil
+}
+
+type natsBusConnection struct {
+	conn          *nats.Conn
+	sub           *nats.Subscription
+	controlSub    *nats.Subscription
+	tracer        trace.Tracer
+	subjectPrefix string
+	protocolToken string
+	clientId      string
+}
+
+func (c *natsBusConnection) Publish(ctx context.Context, msg *pipe.GatewayMessage) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("marshaling message for publication: %w", err)
+	}
+
+	newCtx, span := c.tracer.Start(ctx,
+		fmt.Sprintf("%s.in.%s.* publish", c.subjectPrefix, c.protocolToken),
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			semconv.MessagingSystem("nats"),
+			semconv.MessagingMessagePayloadSizeBytes(len(data)),
+			semconv.MessagingOperationKey.String("publish"),
+			semconv.MessagingMessageConversationID(msg.MessageId),
+			attribute.String("csId", c.clientId),
+		))
+	defer span.End()
+
+	header := make(nats.Header)
+	otel.GetTextMapPropagator().Inject(newCtx, propagation.HeaderCarrier(http.Header(header)))
+
+	return c.conn.PublishMsg(&nats.Msg{
+		Subject: fmt.Sprintf("%s.in.%s.%s", c.subjectPrefix, c.protocolToken, c.clientId),
+		Header:  header,
+		Data:    data,
+	})
+}
+
+func (c *natsBusConnection) PublishPresence(_ context.Context, event PresenceEvent) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return c.conn.Publish(fmt.Sprintf("%s.presence.%s", c.subjectPrefix, c.clientId), payload)
+}
+
+func (c *natsBusConnection) Disconnect(_ context.Context) error {
+	for _, sub := range []*nats.Subscription{c.sub, c.controlSub} {
+		err := sub.Unsubscribe()
+		// the shared connection may already have been closed by NatsBus.Close
+		if err != nil && !errors.Is(err, nats.ErrConnectionClosed) && !errors.Is(err, nats.ErrConnectionDraining) &&
+			!errors.Is(err, nats.ErrBadSubscription) {
+			return err
+		}
+	}
+	return nil
+}
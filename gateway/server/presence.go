@@ -0,0 +1,89 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/eclipse/paho.golang/autopaho"
+	"github.com/eclipse/paho.golang/paho"
+	"net/http"
+	"strings"
+)
+
+// PresenceEventType identifies whether a charge station has connected to, or
+// disconnected from, the gateway
+type PresenceEventType string
+
+const (
+	PresenceConnected    PresenceEventType = "Connected"
+	PresenceDisconnected PresenceEventType = "Disconnected"
+)
+
+// PresenceEvent is published on the presence topic (or subject) when a charge
+// station connects to or disconnects from the gateway. The ConnectionId is unique
+// for each websocket connection so that a consumer can ignore a disconnection
+// that has been superseded by a newer connection (possibly to another gateway).
+type PresenceEvent struct {
+	Type            PresenceEventType `json:"type"`
+	GatewayId       string            `json:"gateway_id"`
+	ConnectionId    string            `json:"connection_id"`
+	Protocol        string            `json:"protocol"`
+	RemoteAddr      string            `json:"remote_addr"`
+	SecurityProfile int               `json:"security_profile"`
+}
+
+func presenceTopic(topicPrefix, clientId string) string {
+	return fmt.Sprintf("%s/presence/%s", topicPrefix, clientId)
+}
+
+// setPresenceWill configures the MQTT connection so that the broker will publish a
+// disconnection event if the gateway goes away without disconnecting cleanly
+func setPresenceWill(cfg *autopaho.ClientConfig, topicPrefix, clientId string, event PresenceEvent) error {
+	event.Type = PresenceDisconnected
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	cfg.SetWillMessage(presenceTopic(topicPrefix, clientId), payload, 1, false)
+	return nil
+}
+
+func publishPresence(ctx context.Context, mqttConn *autopaho.ConnectionManager, topicPrefix, clientId string, event PresenceEvent) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	_, err = mqttConn.Publish(ctx, &paho.Publish{
+		Topic:   presenceTopic(topicPrefix, clientId),
+		QoS:     1,
+		Payload: payload,
+		Properties: &paho.PublishProperties{
+			ContentType: "application/json",
+		},
+	})
+	return err
+}
+
+func newConnectionId() string {
+	b := make([]byte, 8)
+	_, err := rand.Read(b)
+	if err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
+
+func getRemoteAddr(r *http.Request, trustProxyHeaders bool) string {
+	if trustProxyHeaders {
+		if forwardedFor := r.Header.Get("X-Forwarded-For"); forwardedFor != "" {
+			addr, _, _ := strings.Cut(forwardedFor, ",")
+			return strings.TrimSpace(addr)
+		}
+	}
+	return r.RemoteAddr
+}
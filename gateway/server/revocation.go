@@ -0,0 +1,645 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/thoughtworks/maeve-csms/gateway/registry"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/crypto/ocsp"
+	"golang.org/x/exp/slog"
+	"k8s.io/utils/clock"
+)
+
+// RevocationPolicy determines what happens when the revocation status of a client
+// certificate cannot be determined
+type RevocationPolicy string
+
+const (
+	// RevocationPolicyOff disables CRL and OCSP checks
+	RevocationPolicyOff RevocationPolicy = "off"
+	// RevocationPolicySoftFail accepts a certificate when its status cannot be determined
+	RevocationPolicySoftFail RevocationPolicy = "soft-fail"
+	// RevocationPolicyHardFail rejects a certificate when its status cannot be determined
+	RevocationPolicyHardFail RevocationPolicy = "hard-fail"
+)
+
+func ParseRevocationPolicy(policy string) (RevocationPolicy, error) {
+	switch RevocationPolicy(policy) {
+	case RevocationPolicyOff, RevocationPolicySoftFail, RevocationPolicyHardFail:
+		return RevocationPolicy(policy), nil
+	}
+	return "", fmt.Errorf("unknown revocation policy: %s", policy)
+}
+
+// ErrCertificateRevoked is returned when a client certificate has been revoked
+var ErrCertificateRevoked = errors.New("certificate revoked")
+
+var revocationChecks = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "gateway_certificate_revocation_checks_total",
+	Help: "The number of client certificate revocation checks by source and result",
+}, []string{"source", "result"})
+
+type revocationStatus int
+
+const (
+	revocationStatusUnknown revocationStatus = iota
+	revocationStatusGood
+	revocationStatusRevoked
+)
+
+func (s revocationStatus) String() string {
+	switch s {
+	case revocationStatusGood:
+		return "good"
+	case revocationStatusRevoked:
+		return "revoked"
+	}
+	return "unknown"
+}
+
+// RevocationChecker checks whether charge station client certificates have been revoked.
+//
+// During the TLS handshake the leaf certificate is checked against the local CRL files,
+// then using OCSP and finally against the CRLs published at the certificate's
+// distribution points. The first source that can determine the status is used. OCSP
+// responses and CRLs are cached until their next update, and local CRL files are
+// reloaded when they change. If no source can determine the status then the policy
+// decides whether the certificate is accepted.
+//
+// When a charge station connects using security profile 3 the certificate is also
+// checked against the revocations held by the CSMS, and the checks are repeated every
+// recheck interval for as long as the charge station remains connected.
+type RevocationChecker struct {
+	policy                  RevocationPolicy
+	crlFiles                []string
+	checkOCSP               bool
+	checkDistributionPoints bool
+	registry                registry.CertificateRevocationRegistry
+	httpClient              *http.Client
+	clock                   clock.PassiveClock
+	cacheTtl                time.Duration
+	failureTtl              time.Duration
+	recheckInterval         time.Duration
+
+	mu        sync.Mutex
+	localCRLs []*localCRL
+	crlCache  map[string]*cachedCRL
+	ocspCache map[string]*cachedOCSPResponse
+}
+
+type localCRL struct {
+	path    string
+	modTime time.Time
+	lists   []*x509.RevocationList
+}
+
+type cachedCRL struct {
+	list      *x509.RevocationList
+	expiresAt time.Time
+}
+
+type cachedOCSPResponse struct {
+	status    revocationStatus
+	err       error
+	expiresAt time.Time
+}
+
+type RevocationOpt func(*RevocationChecker)
+
+// WithRevocationPolicy sets what happens when the status of a certificate cannot be
+// determined (defaults to soft-fail)
+func WithRevocationPolicy(policy RevocationPolicy) RevocationOpt {
+	return func(c *RevocationChecker) {
+		c.policy = policy
+	}
+}
+
+// WithCRLFiles checks certificates against CRLs loaded from local files. Each file may
+// contain a DER encoded CRL or one or more PEM encoded CRLs.
+func WithCRLFiles(crlFiles []string) RevocationOpt {
+	return func(c *RevocationChecker) {
+		c.crlFiles = append(c.crlFiles, crlFiles...)
+	}
+}
+
+// WithOCSP enables (or disables) checking certificates using the OCSP responder
+// identified by the certificate (defaults to enabled)
+func WithOCSP(enabled bool) RevocationOpt {
+	return func(c *RevocationChecker) {
+		c.checkOCSP = enabled
+	}
+}
+
+// WithCRLDistributionPoints enables (or disables) checking certificates against the CRLs
+// published at the certificate's distribution points (defaults to enabled)
+func WithCRLDistributionPoints(enabled bool) RevocationOpt {
+	return func(c *RevocationChecker) {
+		c.checkDistributionPoints = enabled
+	}
+}
+
+// WithRevocationRegistry checks certificates against the revocations held by the CSMS
+func WithRevocationRegistry(revocationRegistry registry.CertificateRevocationRegistry) RevocationOpt {
+	return func(c *RevocationChecker) {
+		c.registry = revocationRegistry
+	}
+}
+
+// WithRevocationHttpClient sets the client used to make OCSP requests and to fetch CRLs
+func WithRevocationHttpClient(httpClient *http.Client) RevocationOpt {
+	return func(c *RevocationChecker) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithRevocationCacheTtl sets how long an OCSP response or CRL that does not specify
+// its next update is cached for (defaults to 1 hour)
+func WithRevocationCacheTtl(ttl time.Duration) RevocationOpt {
+	return func(c *RevocationChecker) {
+		c.cacheTtl = ttl
+	}
+}
+
+// WithRevocationRecheckInterval sets how often the certificates of connected charge
+// stations are rechecked (defaults to 1 minute)
+func WithRevocationRecheckInterval(interval time.Duration) RevocationOpt {
+	return func(c *RevocationChecker) {
+		c.recheckInterval = interval
+	}
+}
+
+func WithRevocationClock(clock clock.PassiveClock) RevocationOpt {
+	return func(c *RevocationChecker) {
+		c.clock = clock
+	}
+}
+
+func NewRevocationChecker(opts ...RevocationOpt) (*RevocationChecker, error) {
+	c := &RevocationChecker{
+		policy:                  RevocationPolicySoftFail,
+		checkOCSP:               true,
+		checkDistributionPoints: true,
+		httpClient:              &http.Client{Timeout: 5 * time.Second},
+		clock:                   clock.RealClock{},
+		cacheTtl:                time.Hour,
+		failureTtl:              time.Minute,
+		recheckInterval:         time.Minute,
+		crlCache:                make(map[string]*cachedCRL),
+		ocspCache:               make(map[string]*cachedOCSPResponse),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	for _, path := range c.crlFiles {
+		crl := &localCRL{path: path}
+		if err := crl.reload(); err != nil {
+			return nil, err
+		}
+		c.localCRLs = append(c.localCRLs, crl)
+	}
+
+	return c, nil
+}
+
+// VerifyConnection checks the revocation status of the client certificate during the
+// TLS handshake: it is intended to be used as the tls.Config VerifyConnection function
+func (c *RevocationChecker) VerifyConnection(state tls.ConnectionState) error {
+	if c.policy == RevocationPolicyOff || len(state.VerifiedChains) == 0 {
+		return nil
+	}
+	leaf, issuer := chainLeafAndIssuer(state.VerifiedChains[0])
+	status, err := c.checkCertificate(leaf, issuer)
+	return c.apply(context.Background(), leaf, status, err)
+}
+
+// checkConnection checks the client certificate presented by a charge station that is
+// connecting with security profile 3
+func (c *RevocationChecker) checkConnection(ctx context.Context, state *tls.ConnectionState) error {
+	if len(state.PeerCertificates) == 0 {
+		return nil
+	}
+
+	leaf := state.PeerCertificates[0]
+	status, err := c.checkRegistry(leaf)
+	if status == revocationStatusRevoked {
+		return c.apply(ctx, leaf, status, err)
+	}
+
+	// the checks have already been made during the handshake unless TLS is offloaded
+	if len(state.VerifiedChains) == 0 && c.policy != RevocationPolicyOff {
+		status, err = c.checkCertificate(leaf, nil)
+	}
+	return c.apply(ctx, leaf, status, err)
+}
+
+// goRecheckConnection repeats the checks for a connected charge station until the context
+// is done, calling onRevoked if the certificate is found to have been revoked. A status
+// that cannot be determined does not disconnect the charge station.
+func (c *RevocationChecker) goRecheckConnection(ctx context.Context, state *tls.ConnectionState, onRevoked func(err error)) {
+	if len(state.PeerCertificates) == 0 {
+		return
+	}
+
+	leaf := state.PeerCertificates[0]
+	var issuer *x509.Certificate
+	if len(state.VerifiedChains) > 0 {
+		leaf, issuer = chainLeafAndIssuer(state.VerifiedChains[0])
+	}
+
+	go func() {
+		ticker := time.NewTicker(c.recheckInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				status, err := c.checkRegistry(leaf)
+				if status != revocationStatusRevoked && c.policy != RevocationPolicyOff {
+					status, err = c.checkCertificate(leaf, issuer)
+				}
+				if status == revocationStatusRevoked {
+					onRevoked(c.apply(ctx, leaf, status, err))
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// apply converts the result of a check into an error according to the policy
+func (c *RevocationChecker) apply(ctx context.Context, leaf *x509.Certificate, status revocationStatus, err error) error {
+	span := trace.SpanFromContext(ctx)
+
+	switch status {
+	case revocationStatusGood:
+		return nil
+	case revocationStatusRevoked:
+		slog.Warn("security event: revoked client certificate", "serialNumber", leaf.SerialNumber,
+			"subject", leaf.Subject.String(), "err", err)
+		span.AddEvent("security_event", trace.WithAttributes(
+			attribute.String("security_event.type", "CertificateRevoked"),
+			attribute.String("cert.serial_number", leaf.SerialNumber.String())))
+		return err
+	}
+
+	if err == nil || c.policy != RevocationPolicyHardFail {
+		if err != nil {
+			slog.Warn("unable to determine client certificate revocation status", "serialNumber", leaf.SerialNumber,
+				"subject", leaf.Subject.String(), "err", err)
+			span.RecordError(err)
+		}
+		return nil
+	}
+
+	span.AddEvent("security_event", trace.WithAttributes(
+		attribute.String("security_event.type", "CertificateRevocationUnknown"),
+		attribute.String("cert.serial_number", leaf.SerialNumber.String())))
+	return fmt.Errorf("unable to determine revocation status: %w", err)
+}
+
+func (c *RevocationChecker) checkRegistry(leaf *x509.Certificate) (revocationStatus, error) {
+	if c.registry == nil {
+		return revocationStatusGood, nil
+	}
+	revocation, err := c.registry.LookupCertificateRevocation(certificateHash(leaf))
+	if err != nil {
+		revocationChecks.WithLabelValues("registry", revocationStatusUnknown.String()).Inc()
+		return revocationStatusUnknown, fmt.Errorf("lookup certificate revocation: %w", err)
+	}
+	if revocation != nil {
+		revocationChecks.WithLabelValues("registry", revocationStatusRevoked.String()).Inc()
+		return revocationStatusRevoked, fmt.Errorf("%w by csms at %s", ErrCertificateRevoked,
+			revocation.RevokedAt.Format(time.RFC3339))
+	}
+	revocationChecks.WithLabelValues("registry", revocationStatusGood.String()).Inc()
+	return revocationStatusGood, nil
+}
+
+// checkCertificate checks the certificate against the local CRLs, then using OCSP and then
+// against the CRLs at the distribution points. The OCSP and distribution point checks are
+// only possible when the issuer is known. If no source can determine the status then the
+// status is unknown and the returned error (if any) explains why.
+func (c *RevocationChecker) checkCertificate(leaf, issuer *x509.Certificate) (revocationStatus, error) {
+	if status, err := c.checkLocalCRLs(leaf); status != revocationStatusUnknown || err != nil {
+		revocationChecks.WithLabelValues("crl_file", status.String()).Inc()
+		return status, err
+	}
+	if issuer == nil {
+		return revocationStatusUnknown, errors.New("no local crl for certificate issuer")
+	}
+
+	var errs []error
+	if c.checkOCSP && len(leaf.OCSPServer) > 0 {
+		status, err := c.checkOCSPResponder(leaf, issuer)
+		revocationChecks.WithLabelValues("ocsp", status.String()).Inc()
+		if status != revocationStatusUnknown {
+			return status, err
+		}
+		errs = append(errs, err)
+	}
+	if c.checkDistributionPoints && len(leaf.CRLDistributionPoints) > 0 {
+		status, err := c.checkCRLDistributionPoints(leaf, issuer)
+		revocationChecks.WithLabelValues("crl", status.String()).Inc()
+		if status != revocationStatusUnknown {
+			return status, err
+		}
+		errs = append(errs, err)
+	}
+
+	if len(errs) == 0 {
+		return revocationStatusUnknown, errors.New("certificate has no ocsp responder or crl distribution point")
+	}
+	return revocationStatusUnknown, errors.Join(errs...)
+}
+
+func (c *RevocationChecker) checkLocalCRLs(leaf *x509.Certificate) (revocationStatus, error) {
+	if len(c.localCRLs) == 0 {
+		return revocationStatusUnknown, nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.clock.Now()
+	status := revocationStatusUnknown
+	for _, crl := range c.localCRLs {
+		if err := crl.reload(); err != nil {
+			// continue to use the CRLs that were loaded previously
+			slog.Warn("reloading crl file", "path", crl.path, "err", err)
+		}
+		for _, list := range crl.lists {
+			if !bytes.Equal(list.RawIssuer, leaf.RawIssuer) {
+				continue
+			}
+			if isRevoked(list, leaf) {
+				return revocationStatusRevoked, fmt.Errorf("%w by crl file %s", ErrCertificateRevoked, crl.path)
+			}
+			if list.NextUpdate.IsZero() || now.Before(list.NextUpdate) {
+				status = revocationStatusGood
+			} else {
+				slog.Warn("crl file has expired", "path", crl.path, "nextUpdate", list.NextUpdate)
+			}
+		}
+	}
+	return status, nil
+}
+
+func (c *RevocationChecker) checkOCSPResponder(leaf, issuer *x509.Certificate) (revocationStatus, error) {
+	issuerHash := sha256.Sum256(issuer.RawSubjectPublicKeyInfo)
+	key := fmt.Sprintf("%x/%s", issuerHash, leaf.SerialNumber)
+
+	now := c.clock.Now()
+	c.mu.Lock()
+	cached, ok := c.ocspCache[key]
+	c.mu.Unlock()
+	if ok && now.Before(cached.expiresAt) {
+		return cached.status, cached.err
+	}
+
+	status, nextUpdate, err := c.queryOCSPResponder(leaf, issuer)
+	expiresAt := now.Add(c.failureTtl)
+	if status != revocationStatusUnknown {
+		expiresAt = nextUpdate
+		if nextUpdate.IsZero() {
+			expiresAt = now.Add(c.cacheTtl)
+		}
+	}
+
+	c.mu.Lock()
+	c.ocspCache[key] = &cachedOCSPResponse{status: status, err: err, expiresAt: expiresAt}
+	for k, v := range c.ocspCache {
+		if !now.Before(v.expiresAt) {
+			delete(c.ocspCache, k)
+		}
+	}
+	c.mu.Unlock()
+
+	return status, err
+}
+
+func (c *RevocationChecker) queryOCSPResponder(leaf, issuer *x509.Certificate) (revocationStatus, time.Time, error) {
+	req, err := ocsp.CreateRequest(leaf, issuer, nil)
+	if err != nil {
+		return revocationStatusUnknown, time.Time{}, fmt.Errorf("creating ocsp request: %w", err)
+	}
+
+	var errs []error
+	for _, server := range leaf.OCSPServer {
+		resp, err := c.httpClient.Post(server, "application/ocsp-request", bytes.NewReader(req))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("making ocsp request to %s: %w", server, err))
+			continue
+		}
+		body, err := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("reading ocsp response from %s: %w", server, err))
+			continue
+		}
+		if resp.StatusCode != http.StatusOK {
+			errs = append(errs, fmt.Errorf("ocsp responder %s returned http status %d", server, resp.StatusCode))
+			continue
+		}
+
+		ocspResp, err := ocsp.ParseResponseForCert(body, leaf, issuer)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("parsing ocsp response from %s: %w", server, err))
+			continue
+		}
+
+		switch ocspResp.Status {
+		case ocsp.Good:
+			return revocationStatusGood, ocspResp.NextUpdate, nil
+		case ocsp.Revoked:
+			return revocationStatusRevoked, ocspResp.NextUpdate,
+				fmt.Errorf("%w by ocsp responder %s at %s", ErrCertificateRevoked, server,
+					ocspResp.RevokedAt.Format(time.RFC3339))
+		default:
+			errs = append(errs, fmt.Errorf("ocsp responder %s does not know the certificate", server))
+		}
+	}
+
+	return revocationStatusUnknown, time.Time{}, errors.Join(errs...)
+}
+
+func (c *RevocationChecker) checkCRLDistributionPoints(leaf, issuer *x509.Certificate) (revocationStatus, error) {
+	var errs []error
+	for _, dp := range leaf.CRLDistributionPoints {
+		if !strings.HasPrefix(dp, "http://") && !strings.HasPrefix(dp, "https://") {
+			continue
+		}
+		list, err := c.fetchCRL(dp, issuer)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if isRevoked(list, leaf) {
+			return revocationStatusRevoked, fmt.Errorf("%w by crl %s", ErrCertificateRevoked, dp)
+		}
+		return revocationStatusGood, nil
+	}
+	if len(errs) == 0 {
+		return revocationStatusUnknown, errors.New("no http crl distribution point")
+	}
+	return revocationStatusUnknown, errors.Join(errs...)
+}
+
+func (c *RevocationChecker) fetchCRL(url string, issuer *x509.Certificate) (*x509.RevocationList, error) {
+	now := c.clock.Now()
+	c.mu.Lock()
+	cached, ok := c.crlCache[url]
+	c.mu.Unlock()
+	if ok && now.Before(cached.expiresAt) {
+		return cached.list, nil
+	}
+
+	list, err := c.downloadCRL(url, issuer)
+	if err != nil {
+		if ok && (cached.list.NextUpdate.IsZero() || now.Before(cached.list.NextUpdate)) {
+			slog.Warn("fetching crl failed: using cached crl", "url", url, "err", err)
+			return cached.list, nil
+		}
+		return nil, err
+	}
+
+	expiresAt := list.NextUpdate
+	if expiresAt.IsZero() || expiresAt.After(now.Add(c.cacheTtl)) {
+		expiresAt = now.Add(c.cacheTtl)
+	}
+
+	c.mu.Lock()
+	c.crlCache[url] = &cachedCRL{list: list, expiresAt: expiresAt}
+	c.mu.Unlock()
+
+	return list, nil
+}
+
+func (c *RevocationChecker) downloadCRL(url string, issuer *x509.Certificate) (*x509.RevocationList, error) {
+	resp, err := c.httpClient.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("fetching crl from %s: %w", url, err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching crl from %s: http status %d", url, resp.StatusCode)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading crl from %s: %w", url, err)
+	}
+	lists, err := parseCRLs(body)
+	if err != nil {
+		return nil, fmt.Errorf("parsing crl from %s: %w", url, err)
+	}
+	list := lists[0]
+	if err = list.CheckSignatureFrom(issuer); err != nil {
+		return nil, fmt.Errorf("checking crl signature from %s: %w", url, err)
+	}
+	if !list.NextUpdate.IsZero() && !c.clock.Now().Before(list.NextUpdate) {
+		return nil, fmt.Errorf("crl from %s has expired", url)
+	}
+	return list, nil
+}
+
+// reload loads the CRL file if it has been modified since it was last loaded
+func (l *localCRL) reload() error {
+	info, err := os.Stat(l.path)
+	if err != nil {
+		return fmt.Errorf("reading crl file %s: %w", l.path, err)
+	}
+	if info.ModTime().Equal(l.modTime) {
+		return nil
+	}
+
+	//#nosec G304 - only files specified by the person running the application will be loaded
+	b, err := os.ReadFile(l.path)
+	if err != nil {
+		return fmt.Errorf("reading crl file %s: %w", l.path, err)
+	}
+	lists, err := parseCRLs(b)
+	if err != nil {
+		return fmt.Errorf("parsing crl file %s: %w", l.path, err)
+	}
+
+	l.lists = lists
+	l.modTime = info.ModTime()
+	slog.Info("loaded crl file", "path", l.path, "crls", len(lists))
+	return nil
+}
+
+// parseCRLs parses either a DER encoded CRL or one or more PEM encoded CRLs
+func parseCRLs(b []byte) ([]*x509.RevocationList, error) {
+	var lists []*x509.RevocationList
+	rest := b
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "X509 CRL" {
+			continue
+		}
+		list, err := x509.ParseRevocationList(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		lists = append(lists, list)
+	}
+	if len(lists) > 0 {
+		return lists, nil
+	}
+
+	list, err := x509.ParseRevocationList(b)
+	if err != nil {
+		return nil, err
+	}
+	return []*x509.RevocationList{list}, nil
+}
+
+func isRevoked(list *x509.RevocationList, cert *x509.Certificate) bool {
+	for _, revoked := range list.RevokedCertificates {
+		if revoked.SerialNumber.Cmp(cert.SerialNumber) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func chainLeafAndIssuer(chain []*x509.Certificate) (leaf, issuer *x509.Certificate) {
+	leaf = chain[0]
+	if len(chain) > 1 {
+		issuer = chain[1]
+	}
+	return leaf, issuer
+}
+
+// certificateHash returns the base64 encoded SHA-256 hash of the DER bytes of the
+// certificate: this is how certificates are identified by the CSMS
+func certificateHash(cert *x509.Certificate) string {
+	hash := sha256.Sum256(cert.Raw)
+	return base64.RawURLEncoding.EncodeToString(hash[:])
+}
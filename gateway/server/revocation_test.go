@@ -0,0 +1,393 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package server_test
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thoughtworks/maeve-csms/gateway/registry"
+	"github.com/thoughtworks/maeve-csms/gateway/server"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/crypto/ocsp"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"nhooyr.io/websocket"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type revocationTestCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+func newRevocationTestCA(t *testing.T) *revocationTestCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject: pkix.Name{
+			CommonName:   "Test CA",
+			Organization: []string{"Thoughtworks"},
+		},
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		NotBefore:             time.Now().Add(-time.Minute),
+		NotAfter:              time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return &revocationTestCA{cert: cert, key: key}
+}
+
+func (ca *revocationTestCA) issue(t *testing.T, serialNumber int64, ocspServer, crlDistributionPoint string) *x509.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := x509.Certificate{
+		SerialNumber: big.NewInt(serialNumber),
+		Subject: pkix.Name{
+			CommonName:   "cs001",
+			Organization: []string{"Thoughtworks"},
+		},
+		KeyUsage:    x509.KeyUsageDigitalSignature,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		NotBefore:   time.Now().Add(-time.Minute),
+		NotAfter:    time.Now().Add(time.Hour),
+	}
+	if ocspServer != "" {
+		template.OCSPServer = []string{ocspServer}
+	}
+	if crlDistributionPoint != "" {
+		template.CRLDistributionPoints = []string{crlDistributionPoint}
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &template, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return cert
+}
+
+func (ca *revocationTestCA) crl(t *testing.T, revokedSerialNumbers ...int64) []byte {
+	var revoked []pkix.RevokedCertificate
+	for _, serialNumber := range revokedSerialNumbers {
+		revoked = append(revoked, pkix.RevokedCertificate{
+			SerialNumber:   big.NewInt(serialNumber),
+			RevocationTime: time.Now(),
+		})
+	}
+	der, err := x509.CreateRevocationList(rand.Reader, &x509.RevocationList{
+		Number:              big.NewInt(time.Now().UnixNano()),
+		ThisUpdate:          time.Now().Add(-time.Minute),
+		NextUpdate:          time.Now().Add(time.Hour),
+		RevokedCertificates: revoked,
+	}, ca.cert, ca.key)
+	require.NoError(t, err)
+	return der
+}
+
+func (ca *revocationTestCA) ocspResponder(t *testing.T, status int, calls *atomic.Int32) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		req, err := ocsp.ParseRequest(body)
+		require.NoError(t, err)
+
+		resp, err := ocsp.CreateResponse(ca.cert, ca.cert, ocsp.Response{
+			Status:       status,
+			SerialNumber: req.SerialNumber,
+			ThisUpdate:   time.Now().Add(-time.Minute),
+			NextUpdate:   time.Now().Add(time.Hour),
+			RevokedAt:    time.Now().Add(-time.Minute),
+		}, crypto.Signer(ca.key))
+		require.NoError(t, err)
+
+		w.Header().Set("content-type", "application/ocsp-response")
+		_, _ = w.Write(resp)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func connectionState(leaf, issuer *x509.Certificate) tls.ConnectionState {
+	return tls.ConnectionState{
+		PeerCertificates: []*x509.Certificate{leaf},
+		VerifiedChains:   [][]*x509.Certificate{{leaf, issuer}},
+	}
+}
+
+func TestRevocationCheckerWithCRLFile(t *testing.T) {
+	ca := newRevocationTestCA(t)
+
+	crlFile := filepath.Join(t.TempDir(), "ca.crl")
+	err := os.WriteFile(crlFile, pem.EncodeToMemory(&pem.Block{Type: "X509 CRL", Bytes: ca.crl(t, 2)}), 0600)
+	require.NoError(t, err)
+
+	checker, err := server.NewRevocationChecker(
+		server.WithRevocationPolicy(server.RevocationPolicyHardFail),
+		server.WithCRLFiles([]string{crlFile}))
+	require.NoError(t, err)
+
+	err = checker.VerifyConnection(connectionState(ca.issue(t, 1, "", ""), ca.cert))
+	assert.NoError(t, err)
+
+	err = checker.VerifyConnection(connectionState(ca.issue(t, 2, "", ""), ca.cert))
+	assert.ErrorIs(t, err, server.ErrCertificateRevoked)
+}
+
+func TestRevocationCheckerReloadsCRLFile(t *testing.T) {
+	ca := newRevocationTestCA(t)
+
+	crlFile := filepath.Join(t.TempDir(), "ca.crl")
+	err := os.WriteFile(crlFile, ca.crl(t), 0600)
+	require.NoError(t, err)
+
+	checker, err := server.NewRevocationChecker(server.WithCRLFiles([]string{crlFile}))
+	require.NoError(t, err)
+
+	leaf := ca.issue(t, 1, "", "")
+	err = checker.VerifyConnection(connectionState(leaf, ca.cert))
+	assert.NoError(t, err)
+
+	err = os.WriteFile(crlFile, ca.crl(t, 1), 0600)
+	require.NoError(t, err)
+	modTime := time.Now().Add(time.Second)
+	require.NoError(t, os.Chtimes(crlFile, modTime, modTime))
+
+	err = checker.VerifyConnection(connectionState(leaf, ca.cert))
+	assert.ErrorIs(t, err, server.ErrCertificateRevoked)
+}
+
+func TestRevocationCheckerWithOCSP(t *testing.T) {
+	ca := newRevocationTestCA(t)
+
+	var goodCalls, revokedCalls atomic.Int32
+	goodResponder := ca.ocspResponder(t, ocsp.Good, &goodCalls)
+	revokedResponder := ca.ocspResponder(t, ocsp.Revoked, &revokedCalls)
+
+	checker, err := server.NewRevocationChecker(server.WithRevocationPolicy(server.RevocationPolicyHardFail))
+	require.NoError(t, err)
+
+	goodLeaf := ca.issue(t, 1, goodResponder.URL, "")
+	for i := 0; i < 2; i++ {
+		err = checker.VerifyConnection(connectionState(goodLeaf, ca.cert))
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, int32(1), goodCalls.Load())
+
+	err = checker.VerifyConnection(connectionState(ca.issue(t, 2, revokedResponder.URL, ""), ca.cert))
+	assert.ErrorIs(t, err, server.ErrCertificateRevoked)
+	assert.Equal(t, int32(1), revokedCalls.Load())
+}
+
+func TestRevocationCheckerWithCRLDistributionPoint(t *testing.T) {
+	ca := newRevocationTestCA(t)
+
+	var calls atomic.Int32
+	crl := ca.crl(t, 2)
+	crlServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		_, _ = w.Write(crl)
+	}))
+	defer crlServer.Close()
+
+	checker, err := server.NewRevocationChecker(server.WithRevocationPolicy(server.RevocationPolicyHardFail))
+	require.NoError(t, err)
+
+	err = checker.VerifyConnection(connectionState(ca.issue(t, 1, "", crlServer.URL), ca.cert))
+	assert.NoError(t, err)
+
+	err = checker.VerifyConnection(connectionState(ca.issue(t, 2, "", crlServer.URL), ca.cert))
+	assert.ErrorIs(t, err, server.ErrCertificateRevoked)
+
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestRevocationCheckerPolicyWhenStatusUnknown(t *testing.T) {
+	ca := newRevocationTestCA(t)
+
+	failingResponder := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failingResponder.Close()
+
+	leaf := ca.issue(t, 1, failingResponder.URL, "")
+
+	tests := []struct {
+		policy  server.RevocationPolicy
+		wantErr bool
+	}{
+		{server.RevocationPolicyOff, false},
+		{server.RevocationPolicySoftFail, false},
+		{server.RevocationPolicyHardFail, true},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.policy), func(t *testing.T) {
+			checker, err := server.NewRevocationChecker(server.WithRevocationPolicy(tt.policy))
+			require.NoError(t, err)
+
+			err = checker.VerifyConnection(connectionState(leaf, ca.cert))
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.False(t, errors.Is(err, server.ErrCertificateRevoked))
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestParseRevocationPolicy(t *testing.T) {
+	policy, err := server.ParseRevocationPolicy("hard-fail")
+	require.NoError(t, err)
+	assert.Equal(t, server.RevocationPolicyHardFail, policy)
+
+	_, err = server.ParseRevocationPolicy("sometimes")
+	assert.Error(t, err)
+}
+
+// revokedCertificates is a CertificateRevocationRegistry that can be safely updated
+// while connections are being rechecked
+type revokedCertificates struct {
+	revoked atomic.Pointer[string]
+}
+
+func (r *revokedCertificates) revoke(cert *x509.Certificate) {
+	hash := sha256.Sum256(cert.Raw)
+	certHash := base64.RawURLEncoding.EncodeToString(hash[:])
+	r.revoked.Store(&certHash)
+}
+
+func (r *revokedCertificates) LookupCertificateRevocation(certHash string) (*registry.CertificateRevocation, error) {
+	if revoked := r.revoked.Load(); revoked != nil && *revoked == certHash {
+		return &registry.CertificateRevocation{RevokedAt: time.Now()}, nil
+	}
+	return nil, nil
+}
+
+func newRevokedCertificateTestServer(t *testing.T, mockRegistry *registry.MockRegistry, revocations *revokedCertificates, caCert *x509.Certificate) *httptest.Server {
+	checker, err := server.NewRevocationChecker(
+		server.WithRevocationRegistry(revocations),
+		server.WithRevocationRecheckInterval(50*time.Millisecond))
+	require.NoError(t, err)
+
+	bus := server.NewNatsBus([]string{server.NewNatsServer(t)}, "cs", trace.NewNoopTracerProvider().Tracer(""))
+	t.Cleanup(func() {
+		_ = bus.Close()
+	})
+
+	srv := httptest.NewUnstartedServer(server.NewWebsocketHandler(
+		server.WithBus(bus),
+		server.WithDeviceRegistry(mockRegistry),
+		server.WithOrgName("Thoughtworks"),
+		server.WithRevocationChecker(checker)))
+	clientCAPool := x509.NewCertPool()
+	clientCAPool.AddCert(caCert)
+	srv.TLS = &tls.Config{
+		ClientCAs:        clientCAPool,
+		ClientAuth:       tls.VerifyClientCertIfGiven,
+		VerifyConnection: checker.VerifyConnection,
+	}
+	srv.StartTLS()
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+func revokedCertificateDialOptions(srv *httptest.Server, caCert, clientCert *x509.Certificate, clientKeyPair *ecdsa.PrivateKey) *websocket.DialOptions {
+	rootCAPool := x509.NewCertPool()
+	rootCAPool.AddCert(srv.Certificate())
+
+	return &websocket.DialOptions{
+		HTTPClient: &http.Client{
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{
+					RootCAs: rootCAPool,
+					Certificates: []tls.Certificate{{
+						Certificate: [][]byte{clientCert.Raw, caCert.Raw},
+						PrivateKey:  clientKeyPair,
+					}},
+				},
+			},
+		},
+		Subprotocols: []string{"ocpp2.0.1"},
+	}
+}
+
+func TestTlsConnectionWithRevokedCertificate(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cs := &registry.ChargeStation{
+		ClientId:        "cs001",
+		SecurityProfile: registry.TLSWithClientSideCertificates,
+	}
+	caCert, _, clientCert, clientKeyPair := createTestKeyPairs(t, cs.ClientId)
+
+	mockRegistry := registry.NewMockRegistry()
+	mockRegistry.ChargeStations[cs.ClientId] = cs
+	revocations := new(revokedCertificates)
+	revocations.revoke(clientCert)
+
+	srv := newRevokedCertificateTestServer(t, mockRegistry, revocations, caCert)
+
+	_, resp, err := websocket.Dial(ctx, fmt.Sprintf("%s/ws/%s", srv.URL, cs.ClientId),
+		revokedCertificateDialOptions(srv, caCert, clientCert, clientKeyPair))
+	require.Error(t, err)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
+
+func TestTlsConnectionClosedWhenCertificateRevoked(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cs := &registry.ChargeStation{
+		ClientId:        "cs001",
+		SecurityProfile: registry.TLSWithClientSideCertificates,
+	}
+	caCert, _, clientCert, clientKeyPair := createTestKeyPairs(t, cs.ClientId)
+
+	mockRegistry := registry.NewMockRegistry()
+	mockRegistry.ChargeStations[cs.ClientId] = cs
+
+	revocations := new(revokedCertificates)
+	srv := newRevokedCertificateTestServer(t, mockRegistry, revocations, caCert)
+
+	conn, _, err := websocket.Dial(ctx, fmt.Sprintf("%s/ws/%s", srv.URL, cs.ClientId),
+		revokedCertificateDialOptions(srv, caCert, clientCert, clientKeyPair))
+	require.NoError(t, err)
+	defer func() {
+		_ = conn.Close(websocket.StatusNormalClosure, "OK")
+	}()
+
+	revocations.revoke(clientCert)
+
+	_, _, err = conn.Read(ctx)
+	require.Error(t, err)
+	assert.Equal(t, websocket.StatusPolicyViolation, websocket.CloseStatus(err))
+}
@@ -6,18 +6,41 @@ import (
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"golang.org/x/exp/slog"
 	"net/http"
 )
 
-func NewStatusHandler() http.Handler {
+// NewStatusHandler creates the handler for the status server. If a drainer is provided
+// then the health check fails once the gateway is draining and draining can be started
+// with a POST to /drain.
+func NewStatusHandler(drainer *Drainer) http.Handler {
 	r := chi.NewRouter()
 	r.Use(middleware.Recoverer)
-	r.Get("/health", health)
+	r.Get("/health", health(drainer))
 	r.Handle("/metrics", promhttp.Handler())
+	if drainer != nil {
+		r.Post("/drain", drain(drainer))
+	}
 	return r
 }
 
-func health(w http.ResponseWriter, r *http.Request) {
-	w.WriteHeader(http.StatusOK)
-	_, _ = w.Write([]byte(`{"status":"OK"}`))
+func health(drainer *Drainer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if drainer != nil && drainer.Draining() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte(`{"status":"Draining"}`))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"status":"OK"}`))
+	}
+}
+
+func drain(drainer *Drainer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		slog.Info("draining requested", "remoteAddr", r.RemoteAddr)
+		drainer.Start()
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = w.Write([]byte(`{"status":"Draining"}`))
+	}
 }
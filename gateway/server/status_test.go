@@ -10,7 +10,7 @@ import (
 )
 
 func TestHealthHandler(t *testing.T) {
-	handler := server.NewStatusHandler()
+	handler := server.NewStatusHandler(nil)
 
 	req := httptest.NewRequest(http.MethodGet, "/health", nil)
 	w := httptest.NewRecorder()
@@ -27,3 +27,26 @@ func TestHealthHandler(t *testing.T) {
 		t.Errorf("status code: want %d, got %d", http.StatusOK, res.StatusCode)
 	}
 }
+
+func TestHealthHandlerWhenDraining(t *testing.T) {
+	drainer := server.NewDrainer()
+	handler := server.NewStatusHandler(drainer)
+
+	req := httptest.NewRequest(http.MethodPost, "/drain", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if w.Result().StatusCode != http.StatusAccepted {
+		t.Errorf("drain status code: want %d, got %d", http.StatusAccepted, w.Result().StatusCode)
+	}
+
+	if !drainer.Draining() {
+		t.Error("drainer has not started")
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/health", nil)
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if w.Result().StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("health status code: want %d, got %d", http.StatusServiceUnavailable, w.Result().StatusCode)
+	}
+}
@@ -0,0 +1,54 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+)
+
+// LoadTLSConfig creates the TLS configuration for the wss server from the server
+// certificate and key files and the certificates that are trusted to sign client
+// certificates.
+func LoadTLSConfig(tlsServerCert, tlsServerKey string, tlsTrustCert []string) (*tls.Config, error) {
+	if tlsServerCert == "" {
+		return nil, fmt.Errorf("no tls server cert specified for wss connection")
+	}
+	if tlsServerKey == "" {
+		return nil, fmt.Errorf("no tls server key specified for wss connection")
+	}
+
+	//#nosec G304 - only files specified by the person running the application will be loaded
+	cb, err := os.ReadFile(tlsServerCert)
+	if err != nil {
+		return nil, fmt.Errorf("reading tls cert from %s: %v", tlsServerCert, err)
+	}
+	//#nosec G304 - only files specified by the person running the application will be loaded
+	kb, err := os.ReadFile(tlsServerKey)
+	if err != nil {
+		return nil, fmt.Errorf("reading tls key from %s: %v", tlsServerKey, err)
+	}
+	tlsCert, err := tls.X509KeyPair(cb, kb)
+	if err != nil {
+		return nil, fmt.Errorf("processing tls key pair: %v", err)
+	}
+	trustedCerts := x509.NewCertPool()
+	for _, tc := range tlsTrustCert {
+		//#nosec G304 - only files specified by the person running the application will be loaded
+		tcb, err := os.ReadFile(tc)
+		if err != nil {
+			return nil, fmt.Errorf("reading trusted certs from %s: %v", tc, err)
+		}
+		if ok := trustedCerts.AppendCertsFromPEM(tcb); !ok {
+			return nil, fmt.Errorf("processing trusted certs from %s: no certificate found", tc)
+		}
+	}
+	return &tls.Config{
+		Certificates: []tls.Certificate{tlsCert},
+		ClientCAs:    trustedCerts,
+		ClientAuth:   tls.VerifyClientCertIfGiven,
+		MinVersion:   tls.VersionTLS12,
+	}, nil
+}
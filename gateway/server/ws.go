@@ -9,19 +9,18 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
-	"go.opentelemetry.io/otel/propagation"
 	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
 	"go.opentelemetry.io/otel/trace"
 	"io"
+	"math"
 	"net/http"
 	"net/url"
+	"os"
+	"strconv"
 	"time"
 
-	"github.com/eclipse/paho.golang/autopaho"
-	"github.com/eclipse/paho.golang/paho"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/thoughtworks/maeve-csms/gateway/ocpp"
@@ -29,6 +28,7 @@ import (
 	"github.com/thoughtworks/maeve-csms/gateway/registry"
 	"golang.org/x/exp/slices"
 	"golang.org/x/exp/slog"
+	"k8s.io/utils/clock"
 	"nhooyr.io/websocket"
 )
 
@@ -43,6 +43,17 @@ type WebsocketHandler struct {
 	pipeOptions           []pipe.Opt
 	trustProxyHeaders     bool
 	tracer                trace.Tracer
+	gatewayId             string
+	bus                   Bus
+	maxConnections        int
+	sourceLimit           RateLimit
+	reconnectLimit        RateLimit
+	messageLimit          RateLimit
+	admission             *admissionController
+	authLockoutRegistry   registry.AuthLockoutRegistry
+	authGuard             *authGuard
+	revocationChecker     *RevocationChecker
+	drainer               *Drainer
 }
 
 type WebsocketOpt func(handler *WebsocketHandler)
@@ -125,6 +136,79 @@ func WithOtelTracer(tracer trace.Tracer) WebsocketOpt {
 	}
 }
 
+func WithGatewayId(gatewayId string) WebsocketOpt {
+	return func(handler *WebsocketHandler) {
+		handler.gatewayId = gatewayId
+	}
+}
+
+// WithBus sets the message bus used to communicate with the CSMS. If no bus
+// is provided then an MqttBus is created using the MQTT settings.
+func WithBus(bus Bus) WebsocketOpt {
+	return func(handler *WebsocketHandler) {
+		handler.bus = bus
+	}
+}
+
+// WithMaxConnections limits the number of concurrent websocket connections that
+// the gateway will accept. Zero means that the number of connections is unlimited.
+func WithMaxConnections(maxConnections int) WebsocketOpt {
+	return func(handler *WebsocketHandler) {
+		handler.maxConnections = maxConnections
+	}
+}
+
+// WithSourceRateLimit limits how often each source address may attempt to connect.
+// This is applied before the charge station is authenticated and connections
+// that exceed the limit are rejected with a 429 status code.
+func WithSourceRateLimit(limit RateLimit) WebsocketOpt {
+	return func(handler *WebsocketHandler) {
+		handler.sourceLimit = limit
+	}
+}
+
+// WithReconnectRateLimit limits how often each charge station may (re)connect.
+// This is applied once the charge station has authenticated and connections
+// that exceed the limit are rejected with a 429 status code.
+func WithReconnectRateLimit(limit RateLimit) WebsocketOpt {
+	return func(handler *WebsocketHandler) {
+		handler.reconnectLimit = limit
+	}
+}
+
+// WithMessageRateLimit limits the rate at which each charge station may send calls.
+// Calls that exceed the limit are answered with a CallError and are not sent to the CSMS.
+func WithMessageRateLimit(limit RateLimit) WebsocketOpt {
+	return func(handler *WebsocketHandler) {
+		handler.messageLimit = limit
+	}
+}
+
+// WithAuthLockoutRegistry enables the lockout of charge stations, and source addresses,
+// that repeatedly fail basic auth. Failed attempts are recorded in the registry.
+func WithAuthLockoutRegistry(lockoutRegistry registry.AuthLockoutRegistry) WebsocketOpt {
+	return func(handler *WebsocketHandler) {
+		handler.authLockoutRegistry = lockoutRegistry
+	}
+}
+
+// WithRevocationChecker checks whether the client certificates presented by charge
+// stations using security profile 3 have been revoked, both when they connect and
+// periodically while they remain connected
+func WithRevocationChecker(checker *RevocationChecker) WebsocketOpt {
+	return func(handler *WebsocketHandler) {
+		handler.revocationChecker = checker
+	}
+}
+
+// WithDrainer allows the websocket connections to be drained gracefully: once the
+// drainer has started new connections are rejected with a 503 status code
+func WithDrainer(drainer *Drainer) WebsocketOpt {
+	return func(handler *WebsocketHandler) {
+		handler.drainer = drainer
+	}
+}
+
 func NewWebsocketHandler(opts ...WebsocketOpt) http.Handler {
 	s := new(WebsocketHandler)
 
@@ -172,6 +256,31 @@ func ensureDefaults(handler *WebsocketHandler) {
 	if handler.tracer == nil {
 		handler.tracer = trace.NewNoopTracerProvider().Tracer("")
 	}
+
+	if handler.gatewayId == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			hostname = "gateway"
+		}
+		handler.gatewayId = hostname
+	}
+
+	if handler.bus == nil {
+		handler.bus = &MqttBus{
+			brokerUrls:        handler.mqttBrokerURLs,
+			topicPrefix:       handler.mqttTopicPrefix,
+			connectRetryDelay: handler.mqttConnectRetryDelay,
+			keepAliveInterval: handler.mqttKeepAliveInterval,
+			tracer:            handler.tracer,
+		}
+	}
+
+	handler.admission = newAdmissionController(clock.RealClock{}, handler.maxConnections,
+		handler.sourceLimit, handler.reconnectLimit, handler.messageLimit)
+
+	if handler.authLockoutRegistry != nil {
+		handler.authGuard = newAuthGuard(handler.authLockoutRegistry, clock.RealClock{}, 10*time.Second)
+	}
 }
 
 func (s *WebsocketHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
@@ -188,6 +297,25 @@ func (s *WebsocketHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	span.SetAttributes(attribute.String("csId", clientId))
 
+	if s.drainer != nil && s.drainer.Draining() {
+		slog.Warn("rejecting connection", "csId", clientId, "reason", "draining")
+		span.SetStatus(codes.Error, "connection rejected")
+		span.SetAttributes(
+			attribute.String("admission.rejection_reason", "draining"),
+			semconv.HTTPStatusCode(http.StatusServiceUnavailable))
+		w.Header().Set("Retry-After", "1")
+		http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+		return
+	}
+
+	remoteAddr := getRemoteAddr(r, s.trustProxyHeaders)
+
+	if reason, retryAfter := s.admission.admit(remoteHost(remoteAddr)); reason != "" {
+		rejectConnection(w, span, clientId, reason, retryAfter)
+		return
+	}
+	defer s.admission.release()
+
 	cs, err := s.deviceRegistry.LookupChargeStation(clientId)
 	if err != nil {
 		span.SetStatus(codes.Error, "lookup charge station failed")
@@ -206,9 +334,23 @@ func (s *WebsocketHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	span.SetAttributes(attribute.Int("ocpp.security_profile", int(cs.SecurityProfile)))
 
+	if s.authGuard != nil &&
+		(cs.SecurityProfile == registry.UnsecuredTransportWithBasicAuth || cs.SecurityProfile == registry.TLSWithBasicAuth) {
+		if lockedUntil := s.authGuard.lockedUntil(r.Context(), clientId, remoteHost(remoteAddr)); !lockedUntil.IsZero() {
+			retryAfter := int(math.Ceil(time.Until(lockedUntil).Seconds()))
+			span.SetStatus(codes.Error, "locked out")
+			span.SetAttributes(
+				attribute.String("auth.failure_reason", "locked out"),
+				semconv.HTTPStatusCode(http.StatusTooManyRequests))
+			w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+			http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+			return
+		}
+	}
+
 	switch cs.SecurityProfile {
 	case registry.UnsecuredTransportWithBasicAuth:
-		if r.TLS != nil || !checkAuthorization(r.Context(), r, cs) {
+		if r.TLS != nil || !s.checkBasicAuth(r.Context(), r, cs, remoteAddr) {
 			if r.TLS != nil {
 				span.SetAttributes(attribute.String("auth.failure_reason", "tls for unsecured transport"))
 			}
@@ -218,7 +360,7 @@ func (s *WebsocketHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 	case registry.TLSWithBasicAuth:
-		if r.TLS == nil || !checkAuthorization(r.Context(), r, cs) {
+		if r.TLS == nil || !s.checkBasicAuth(r.Context(), r, cs, remoteAddr) {
 			if r.TLS == nil {
 				span.SetAttributes(attribute.String("auth.failure_reason", "no tls for secured transport"))
 			}
@@ -228,7 +370,7 @@ func (s *WebsocketHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 	case registry.TLSWithClientSideCertificates:
-		if r.TLS == nil || !checkCertificate(r.Context(), r, s.orgNames, cs) {
+		if r.TLS == nil || !checkCertificate(r.Context(), r, s.orgNames, cs) || !s.checkRevocation(r.Context(), r) {
 			if r.TLS == nil {
 				span.SetAttributes(attribute.String("auth.failure_reason", "no tls for secured transport"))
 			}
@@ -242,7 +384,13 @@ func (s *WebsocketHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	wsConn, err := websocket.Accept(w, r, &websocket.AcceptOptions{Subprotocols: []string{"ocpp2.0.1", "ocpp1.6"}, InsecureSkipVerify: true})
+	if reason, retryAfter := s.admission.admitStation(clientId); reason != "" {
+		rejectConnection(w, span, clientId, reason, retryAfter)
+		return
+	}
+	defer s.admission.releaseStation(clientId)
+
+	wsConn, err := websocket.Accept(w, r, &websocket.AcceptOptions{Subprotocols: []string{"ocpp2.1", "ocpp2.0.1", "ocpp1.6"}, InsecureSkipVerify: true})
 	if err != nil {
 		span.SetAttributes(attribute.String("websocket.accept_failure_reason", err.Error()))
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
@@ -263,103 +411,94 @@ func (s *WebsocketHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithCancel(r.Context())
 	defer cancel()
 
-	mqttBrokerURLStrings := make([]string, len(s.mqttBrokerURLs))
-	for i, u := range s.mqttBrokerURLs {
-		mqttBrokerURLStrings[i] = u.String()
-	}
-	span.SetAttributes(attribute.StringSlice("mqtt.broker_urls", mqttBrokerURLStrings))
-
-	var mqttConn *autopaho.ConnectionManager
-	mqttConn, err = autopaho.NewConnection(ctx, autopaho.ClientConfig{
-		BrokerUrls:        s.mqttBrokerURLs,
-		KeepAlive:         s.mqttKeepAliveInterval,
-		ConnectRetryDelay: s.mqttConnectRetryDelay,
-		OnConnectionUp: func(manager *autopaho.ConnectionManager, connack *paho.Connack) {
-			topicName := fmt.Sprintf("%s/out/%s/%s", s.mqttTopicPrefix, protocol, clientId)
-			span.SetAttributes(attribute.String("mqtt.topic", topicName))
-			_, err = manager.Subscribe(ctx, &paho.Subscribe{
-				Subscriptions: map[string]paho.SubscribeOptions{
-					topicName: {},
-				},
-			})
-			if err != nil {
-				span.SetStatus(codes.Error, "subscribing to mqtt topic failed")
-				span.RecordError(err)
-				span.SetAttributes(semconv.HTTPStatusCode(http.StatusInternalServerError))
-
-				_ = wsConn.Close(websocket.StatusProtocolError, http.StatusText(http.StatusInternalServerError))
-				return
-			}
-		},
-		ClientConfig: paho.ClientConfig{
-			ClientID: clientId,
-			Router: paho.NewSingleHandlerRouter(func(mqttMsg *paho.Publish) {
-				// route requests from the CSMS
-				var msg pipe.GatewayMessage
-				err := json.Unmarshal(mqttMsg.Payload, &msg)
-				if err != nil {
-					slog.Error("unmarshalling CSMS message", "err", err)
-					return
-				}
+	presence := PresenceEvent{
+		GatewayId:       s.gatewayId,
+		ConnectionId:    newConnectionId(),
+		Protocol:        protocol,
+		RemoteAddr:      remoteAddr,
+		SecurityProfile: int(cs.SecurityProfile),
+	}
 
-				correlationMap := make(map[string]string)
-				err = json.Unmarshal(mqttMsg.Properties.CorrelationData, &correlationMap)
-				if err != nil {
-					slog.Warn("unmarshalling correlation map", "err", err)
-				}
-				requestContext := otel.GetTextMapPropagator().Extract(context.Background(), propagation.MapCarrier(correlationMap))
-
-				newCtx, span := s.tracer.Start(requestContext, fmt.Sprintf("%s/out/%s/# receive", s.mqttTopicPrefix, protocol),
-					trace.WithSpanKind(trace.SpanKindConsumer),
-					trace.WithAttributes(
-						semconv.MessagingSystem("mqtt"),
-						semconv.MessagingMessagePayloadSizeBytes(len(mqttMsg.Payload)),
-						semconv.MessagingMessageConversationID(msg.MessageId),
-						semconv.MessagingOperationKey.String("receive"),
-						attribute.String("csId", clientId),
-					))
-				defer span.End()
-
-				msg.Context = newCtx
-
-				p.CSMSRx <- &msg
-			}),
-			OnServerDisconnect: func(disconnect *paho.Disconnect) {
-				span.SetAttributes(attribute.String("mqtt.disconnect_reason", disconnect.Properties.ReasonString))
-			},
-		},
+	busConn, err := s.bus.Connect(ctx, clientId, protocol, presence, &wsBusHandler{
+		clientId:       clientId,
+		csmsRx:         p.CSMSRx,
+		wsConn:         wsConn,
+		deviceRegistry: s.deviceRegistry,
 	})
 	if err != nil {
-		span.SetStatus(codes.Error, "connecting to mqtt")
+		slog.Error("connecting to bus", "err", err, "csId", clientId)
+		span.SetStatus(codes.Error, "connecting to bus")
 		span.RecordError(err)
 		span.SetAttributes(semconv.HTTPStatusCode(http.StatusInternalServerError))
-		slog.Error("connecting to mqtt", "mqttBrokerURLs", s.mqttBrokerURLs, "err", err)
 		_ = wsConn.Close(websocket.StatusProtocolError, http.StatusText(http.StatusInternalServerError))
 		return
 	}
 	defer func() {
-		err := mqttConn.Disconnect(context.Background())
+		err := busConn.Disconnect(context.Background())
 		if err != nil {
-			slog.Error("disconnecting from mqtt", "err", err)
+			slog.Error("disconnecting from bus", "err", err)
 		}
 	}()
-	err = mqttConn.AwaitConnection(ctx)
+
+	if s.drainer != nil {
+		unregister, ok := s.drainer.register(p.Drain, func() {
+			_ = wsConn.Close(websocket.StatusGoingAway, "gateway draining")
+		})
+		if !ok {
+			_ = wsConn.Close(websocket.StatusGoingAway, "gateway draining")
+			return
+		}
+		defer unregister()
+	}
+
+	presence.Type = PresenceConnected
+	err = busConn.PublishPresence(ctx, presence)
 	if err != nil {
-		slog.Error("waiting for mqtt", "mqttBrokerURLs", s.mqttBrokerURLs, "err", err)
-		return
+		slog.Warn("publishing presence", "err", err, "csId", clientId)
+	}
+	defer func() {
+		// the request context may already be done, but the bus connection is still open
+		disconnectCtx, cancel := context.WithTimeout(context.Background(), s.mqttConnectTimeout)
+		defer cancel()
+		presence.Type = PresenceDisconnected
+		err := busConn.PublishPresence(disconnectCtx, presence)
+		if err != nil {
+			slog.Warn("publishing presence", "err", err, "csId", clientId)
+		}
+	}()
+
+	if s.revocationChecker != nil && cs.SecurityProfile == registry.TLSWithClientSideCertificates {
+		s.revocationChecker.goRecheckConnection(ctx, r.TLS, func(err error) {
+			slog.Warn("disconnecting charge station", "csId", clientId, "err", err)
+			_ = wsConn.Close(websocket.StatusPolicyViolation, "certificate revoked")
+		})
 	}
 
 	// we've finished connecting... complete this span so we get to see the details in the trace
 	span.End()
 
 	// listen on the CSMS Tx channel and publish those messages on the inbound topic
-	goPublishToCSMS(ctx, s.tracer, p.CSMSTx, mqttConn, s.mqttTopicPrefix, protocol, clientId)
+	goPublishToCSMS(ctx, p.CSMSTx, busConn)
 
 	// listen the CS Tx channel and write those messages to the websocket
 	goWriteToChargeStation(ctx, s.tracer, p.ChargeStationTx, wsConn, protocol, clientId)
 
 	// read from the websocket and send to the CS Rx channel (CS Tx used for error)
-	readFromChargeStation(ctx, s.tracer, wsConn, p.ChargeStationRx, p.ChargeStationTx, protocol, clientId)
+	readFromChargeStation(ctx, s.tracer, wsConn, p.ChargeStationRx, p.ChargeStationTx, protocol, clientId, s.admission)
+}
+
+func rejectConnection(w http.ResponseWriter, span trace.Span, clientId, reason string, retryAfter int) {
+	status := http.StatusServiceUnavailable
+	if reason == rejectReconnectRate || reason == rejectSourceRate {
+		status = http.StatusTooManyRequests
+	}
+	slog.Warn("rejecting connection", "csId", clientId, "reason", reason)
+	span.SetStatus(codes.Error, "connection rejected")
+	span.SetAttributes(
+		attribute.String("admission.rejection_reason", reason),
+		semconv.HTTPStatusCode(status))
+	w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+	http.Error(w, http.StatusText(status), status)
 }
 
 func getScheme(r *http.Request) string {
@@ -369,6 +508,18 @@ func getScheme(r *http.Request) string {
 	return "ws"
 }
 
+// checkBasicAuth checks the credentials provided by the charge station and records a failed
+// attempt if they are incorrect
+func (s *WebsocketHandler) checkBasicAuth(ctx context.Context, r *http.Request, cs *registry.ChargeStation, remoteAddr string) bool {
+	if checkAuthorization(ctx, r, cs) {
+		return true
+	}
+	if _, _, ok := r.BasicAuth(); ok && s.authGuard != nil {
+		s.authGuard.recordFailure(ctx, cs.ClientId, remoteHost(remoteAddr))
+	}
+	return false
+}
+
 func checkAuthorization(ctx context.Context, r *http.Request, cs *registry.ChargeStation) bool {
 	span := trace.SpanFromContext(ctx)
 
@@ -431,17 +582,29 @@ func checkCertificate(ctx context.Context, r *http.Request, orgNames []string, c
 	return foundOrg
 }
 
-func goPublishToCSMS(ctx context.Context, tracer trace.Tracer, csmsTx chan *pipe.GatewayMessage, mqttConn *autopaho.ConnectionManager, topicPrefix, protocol, clientId string) {
+// checkRevocation checks that the client certificate presented by the charge station
+// has not been revoked
+func (s *WebsocketHandler) checkRevocation(ctx context.Context, r *http.Request) bool {
+	if s.revocationChecker == nil {
+		return true
+	}
+	if err := s.revocationChecker.checkConnection(ctx, r.TLS); err != nil {
+		reason := "certificate revocation status unknown"
+		if errors.Is(err, ErrCertificateRevoked) {
+			reason = "certificate revoked"
+		}
+		trace.SpanFromContext(ctx).SetAttributes(attribute.String("auth.failure_reason", reason))
+		return false
+	}
+	return true
+}
+
+func goPublishToCSMS(ctx context.Context, csmsTx chan *pipe.GatewayMessage, busConn BusConnection) {
 	go func() {
 		for {
 			select {
 			case msg := <-csmsTx:
-				data, err := json.Marshal(msg)
-				if err != nil {
-					slog.Error("marshaling message for publication", "err", err)
-					continue
-				}
-				err = publish(msg.Context, tracer, mqttConn, topicPrefix, protocol, clientId, msg.MessageId, data)
+				err := busConn.Publish(msg.Context, msg)
 				if err != nil {
 					slog.Error("publishing message", "err", err)
 				}
@@ -452,40 +615,44 @@ func goPublishToCSMS(ctx context.Context, tracer trace.Tracer, csmsTx chan *pipe
 	}()
 }
 
-func publish(ctx context.Context, tracer trace.Tracer, mqttConn *autopaho.ConnectionManager, topicPrefix, protocol, clientId, messageId string, data []byte) error {
-	topic := fmt.Sprintf("%s/in/%s/%s", topicPrefix, protocol, clientId)
-
-	newCtx, span := tracer.Start(ctx,
-		fmt.Sprintf("%s/in/%s/# publish", topicPrefix, protocol),
-		trace.WithSpanKind(trace.SpanKindProducer),
-		trace.WithAttributes(
-			semconv.MessagingSystem("mqtt"),
-			semconv.MessagingMessagePayloadSizeBytes(len(data)),
-			semconv.MessagingOperationKey.String("publish"),
-			semconv.MessagingMessageConversationID(messageId),
-			attribute.String("csId", clientId),
-		))
-	defer span.End()
+// wsBusHandler routes the messages received from the bus to the pipe
+type wsBusHandler struct {
+	clientId       string
+	csmsRx         chan *pipe.GatewayMessage
+	wsConn         *websocket.Conn
+	deviceRegistry registry.DeviceRegistry
+}
 
-	correlationMap := make(map[string]string)
-	otel.GetTextMapPropagator().Inject(newCtx, propagation.MapCarrier(correlationMap))
+func (h *wsBusHandler) HandleMessage(msg *pipe.GatewayMessage) {
+	h.csmsRx <- msg
+}
 
-	correlationData, err := json.Marshal(correlationMap)
-	if err != nil {
-		slog.Warn("marshalling correlation map: %v", err)
+func (h *wsBusHandler) HandleControl(msg *ControlMessage) {
+	switch msg.Type {
+	case ControlDisconnect:
+		reason := msg.Reason
+		if reason == "" {
+			reason = "disconnect requested"
+		}
+		// the websocket close reason is limited to 123 bytes
+		if len(reason) > 123 {
+			reason = reason[:123]
+		}
+		slog.Warn("disconnecting charge station at the request of the CSMS", "csId", h.clientId, "reason", reason)
+		// the CSMS disconnects a charge station when its credentials change, so the
+		// cached credentials must not be used to authenticate it when it reconnects
+		if invalidator, ok := h.deviceRegistry.(registry.Invalidator); ok {
+			invalidator.Invalidate(h.clientId)
+		}
+		_ = h.wsConn.Close(websocket.StatusPolicyViolation, reason)
+	default:
+		slog.Warn("ignoring unknown control message", "csId", h.clientId, "type", msg.Type)
 	}
+}
 
-	_, err = mqttConn.Publish(newCtx, &paho.Publish{
-		Topic:   topic,
-		Payload: data,
-		Properties: &paho.PublishProperties{
-			ContentType:     "application/json",
-			ResponseTopic:   fmt.Sprintf("%s/out/%s/%s", topicPrefix, protocol, clientId),
-			CorrelationData: correlationData,
-		},
-	})
-
-	return err
+func (h *wsBusHandler) HandleError(err error) {
+	slog.Error("bus connection failed", "err", err)
+	_ = h.wsConn.Close(websocket.StatusProtocolError, http.StatusText(http.StatusInternalServerError))
 }
 
 func goWriteToChargeStation(ctx context.Context, tracer trace.Tracer, chargeStationTx chan *pipe.GatewayMessage, wsConn *websocket.Conn, protocol, clientId string) {
@@ -523,7 +690,7 @@ func write(ctx context.Context, tracer trace.Tracer, wsConn *websocket.Conn, pro
 	return wsConn.Write(newCtx, websocket.MessageText, data)
 }
 
-func readFromChargeStation(ctx context.Context, tracer trace.Tracer, wsConn *websocket.Conn, csRx, csTx chan *pipe.GatewayMessage, protocol, clientId string) {
+func readFromChargeStation(ctx context.Context, tracer trace.Tracer, wsConn *websocket.Conn, csRx, csTx chan *pipe.GatewayMessage, protocol, clientId string, admission *admissionController) {
 	for {
 		msg, err := read(ctx, tracer, wsConn, protocol, clientId)
 		if err != nil {
@@ -535,6 +702,20 @@ func readFromChargeStation(ctx context.Context, tracer trace.Tracer, wsConn *web
 			// connection closed
 			break
 		} else if msg != nil {
+			// only calls are limited: responses complete calls made by the CSMS
+			if msg.MessageType == ocpp.MessageTypeCall && !admission.allowMessage(clientId) {
+				slog.Warn("rejecting call: message rate exceeded", "csId", clientId, "action", msg.Action)
+				span := trace.SpanFromContext(msg.Context)
+				span.SetStatus(codes.Error, "message rate exceeded")
+				csTx <- &pipe.GatewayMessage{
+					Context:          msg.Context,
+					MessageType:      ocpp.MessageTypeCallError,
+					MessageId:        msg.MessageId,
+					ErrorCode:        ocpp.ErrorGenericError,
+					ErrorDescription: "message rate exceeded",
+				}
+				continue
+			}
 			csRx <- msg
 		} else {
 			// deadline exceeded
@@ -12,14 +12,17 @@ import (
 	"crypto/x509/pkix"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/eclipse/paho.golang/autopaho"
 	"github.com/eclipse/paho.golang/paho"
+	"github.com/nats-io/nats.go"
 	"github.com/stretchr/testify/require"
 	"github.com/thoughtworks/maeve-csms/gateway/ocpp"
 	"github.com/thoughtworks/maeve-csms/gateway/pipe"
 	"github.com/thoughtworks/maeve-csms/gateway/registry"
 	"github.com/thoughtworks/maeve-csms/gateway/server"
+	"go.opentelemetry.io/otel/trace"
 	"math"
 	"math/big"
 	"net/http"
@@ -179,6 +182,781 @@ func TestWebSocketHandler(t *testing.T) {
 	}
 }
 
+func TestWebSocketHandlerPublishesPresence(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	broker, addr := server.NewBroker(t)
+	err := broker.Serve()
+	if err != nil {
+		t.Fatalf("starting broker: %v", err)
+	}
+	defer func() {
+		err := broker.Close()
+		if err != nil {
+			t.Logf("WARN: broker close: %v", err)
+		}
+	}()
+
+	// simulate manager connection
+	eventCh := make(chan server.PresenceEvent, 2)
+	client, err := autopaho.NewConnection(ctx, autopaho.ClientConfig{
+		BrokerUrls:        []*url.URL{addr},
+		KeepAlive:         10,
+		ConnectRetryDelay: 2 * time.Second,
+		OnConnectionUp: func(manager *autopaho.ConnectionManager, connack *paho.Connack) {
+			_, err = manager.Subscribe(ctx, &paho.Subscribe{
+				Subscriptions: map[string]paho.SubscribeOptions{
+					"cs/presence/+": {},
+				},
+			})
+			require.NoError(t, err)
+		},
+		ClientConfig: paho.ClientConfig{
+			ClientID: "test",
+			Router: paho.NewSingleHandlerRouter(func(publish *paho.Publish) {
+				require.Equal(t, "cs/presence/cs1", publish.Topic)
+				var event server.PresenceEvent
+				err := json.Unmarshal(publish.Payload, &event)
+				require.NoError(t, err)
+				eventCh <- event
+			}),
+		},
+	})
+	defer func() {
+		err := client.Disconnect(ctx)
+		if err != nil {
+			t.Logf("WARN: mqtt client disconnect: %v", err)
+		}
+	}()
+
+	err = client.AwaitConnection(ctx)
+	require.NoError(t, err)
+
+	cs := &registry.ChargeStation{
+		ClientId:             "cs1",
+		SecurityProfile:      registry.UnsecuredTransportWithBasicAuth,
+		Base64SHA256Password: "XohImNooBHFR0OVvjcYpJ3NgPQ1qq73WKhHvch0VQtg=", // password
+	}
+
+	mockRegistry := registry.NewMockRegistry()
+	mockRegistry.ChargeStations["cs1"] = cs
+
+	srv := httptest.NewServer(server.NewWebsocketHandler(
+		server.WithMqttBrokerUrl(addr),
+		server.WithMqttTopicPrefix("cs"),
+		server.WithDeviceRegistry(mockRegistry),
+		server.WithGatewayId("gateway1"),
+		server.WithMqttConnectSettings(15*time.Second, 15*time.Second, 5*time.Second)))
+	defer srv.Close()
+
+	authHeader := "Basic " + base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%s:%s", cs.ClientId, "password")))
+	dialOptions := &websocket.DialOptions{
+		Subprotocols: []string{"ocpp1.6"},
+		HTTPHeader: http.Header{
+			"authorization": []string{authHeader},
+		},
+	}
+
+	conn, _, err := websocket.Dial(ctx, fmt.Sprintf("%s/ws/cs1", srv.URL), dialOptions)
+	if err != nil {
+		t.Fatalf("dialing server: %v", err)
+	}
+
+	var connected server.PresenceEvent
+	select {
+	case connected = <-eventCh:
+	case <-ctx.Done():
+		t.Fatal("timeout waiting for connected event")
+	}
+	require.Equal(t, server.PresenceConnected, connected.Type)
+	require.Equal(t, "gateway1", connected.GatewayId)
+	require.Equal(t, "ocpp1.6", connected.Protocol)
+	require.Equal(t, 0, connected.SecurityProfile)
+	require.NotEmpty(t, connected.ConnectionId)
+	require.NotEmpty(t, connected.RemoteAddr)
+
+	err = conn.Close(websocket.StatusNormalClosure, "OK")
+	require.NoError(t, err)
+
+	var disconnected server.PresenceEvent
+	select {
+	case disconnected = <-eventCh:
+	case <-ctx.Done():
+		t.Fatal("timeout waiting for disconnected event")
+	}
+	require.Equal(t, server.PresenceDisconnected, disconnected.Type)
+	require.Equal(t, connected.ConnectionId, disconnected.ConnectionId)
+}
+
+func TestWebSocketHandlerRepublishesPresenceAfterMqttReconnect(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	broker, addr := server.NewBroker(t)
+	err := broker.Serve()
+	if err != nil {
+		t.Fatalf("starting broker: %v", err)
+	}
+	defer func() {
+		err := broker.Close()
+		if err != nil {
+			t.Logf("WARN: broker close: %v", err)
+		}
+	}()
+
+	// simulate manager connection
+	eventCh := make(chan server.PresenceEvent, 3)
+	client, err := autopaho.NewConnection(ctx, autopaho.ClientConfig{
+		BrokerUrls:        []*url.URL{addr},
+		KeepAlive:         10,
+		ConnectRetryDelay: 2 * time.Second,
+		OnConnectionUp: func(manager *autopaho.ConnectionManager, connack *paho.Connack) {
+			_, err = manager.Subscribe(ctx, &paho.Subscribe{
+				Subscriptions: map[string]paho.SubscribeOptions{
+					"cs/presence/+": {},
+				},
+			})
+			require.NoError(t, err)
+		},
+		ClientConfig: paho.ClientConfig{
+			ClientID: "test",
+			Router: paho.NewSingleHandlerRouter(func(publish *paho.Publish) {
+				var event server.PresenceEvent
+				err := json.Unmarshal(publish.Payload, &event)
+				require.NoError(t, err)
+				eventCh <- event
+			}),
+		},
+	})
+	defer func() {
+		err := client.Disconnect(ctx)
+		if err != nil {
+			t.Logf("WARN: mqtt client disconnect: %v", err)
+		}
+	}()
+
+	err = client.AwaitConnection(ctx)
+	require.NoError(t, err)
+
+	cs := &registry.ChargeStation{
+		ClientId:             "cs1",
+		SecurityProfile:      registry.UnsecuredTransportWithBasicAuth,
+		Base64SHA256Password: "XohImNooBHFR0OVvjcYpJ3NgPQ1qq73WKhHvch0VQtg=", // password
+	}
+
+	mockRegistry := registry.NewMockRegistry()
+	mockRegistry.ChargeStations["cs1"] = cs
+
+	srv := httptest.NewServer(server.NewWebsocketHandler(
+		server.WithMqttBrokerUrl(addr),
+		server.WithMqttTopicPrefix("cs"),
+		server.WithDeviceRegistry(mockRegistry),
+		server.WithGatewayId("gateway1"),
+		server.WithMqttConnectSettings(15*time.Second, 1*time.Second, 5*time.Second)))
+	defer srv.Close()
+
+	authHeader := "Basic " + base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%s:%s", cs.ClientId, "password")))
+	dialOptions := &websocket.DialOptions{
+		Subprotocols: []string{"ocpp1.6"},
+		HTTPHeader: http.Header{
+			"authorization": []string{authHeader},
+		},
+	}
+
+	conn, _, err := websocket.Dial(ctx, fmt.Sprintf("%s/ws/cs1", srv.URL), dialOptions)
+	if err != nil {
+		t.Fatalf("dialing server: %v", err)
+	}
+	defer func() {
+		_ = conn.Close(websocket.StatusNormalClosure, "OK")
+	}()
+
+	nextEvent := func() server.PresenceEvent {
+		select {
+		case event := <-eventCh:
+			return event
+		case <-ctx.Done():
+			t.Fatal("timeout waiting for presence event")
+		}
+		return server.PresenceEvent{}
+	}
+
+	connected := nextEvent()
+	require.Equal(t, server.PresenceConnected, connected.Type)
+
+	// drop the gateway's connection to the broker: the broker may publish the will
+	gatewayClient, ok := broker.Clients.Get("cs1")
+	require.True(t, ok)
+	gatewayClient.Stop(errors.New("connection lost"))
+
+	reconnected := nextEvent()
+	for reconnected.Type == server.PresenceDisconnected {
+		reconnected = nextEvent()
+	}
+	require.Equal(t, server.PresenceConnected, reconnected.Type)
+	require.Equal(t, connected.ConnectionId, reconnected.ConnectionId)
+	require.Equal(t, "gateway1", reconnected.GatewayId)
+}
+
+func TestWebSocketHandlerWithNatsBus(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	natsUrl := server.NewNatsServer(t)
+
+	// simulate manager connection
+	nc, err := nats.Connect(natsUrl)
+	require.NoError(t, err)
+	defer nc.Close()
+
+	_, err = nc.Subscribe("cs.in.ocpp201.*", func(natsMsg *nats.Msg) {
+		require.Equal(t, "cs.in.ocpp201.cs1", natsMsg.Subject)
+		var reqMsg pipe.GatewayMessage
+		err := json.Unmarshal(natsMsg.Data, &reqMsg)
+		require.NoError(t, err)
+
+		respMsg := pipe.GatewayMessage{
+			MessageType:     ocpp.MessageTypeCallResult,
+			MessageId:       reqMsg.MessageId,
+			ResponsePayload: reqMsg.RequestPayload,
+		}
+
+		b, err := json.Marshal(respMsg)
+		require.NoError(t, err)
+		err = nc.Publish("cs.out.ocpp201.cs1", b)
+		require.NoError(t, err)
+	})
+	require.NoError(t, err)
+
+	eventCh := make(chan server.PresenceEvent, 2)
+	_, err = nc.Subscribe("cs.presence.*", func(natsMsg *nats.Msg) {
+		require.Equal(t, "cs.presence.cs1", natsMsg.Subject)
+		var event server.PresenceEvent
+		err := json.Unmarshal(natsMsg.Data, &event)
+		require.NoError(t, err)
+		eventCh <- event
+	})
+	require.NoError(t, err)
+	require.NoError(t, nc.Flush())
+
+	cs := &registry.ChargeStation{
+		ClientId:             "cs1",
+		SecurityProfile:      registry.UnsecuredTransportWithBasicAuth,
+		Base64SHA256Password: "XohImNooBHFR0OVvjcYpJ3NgPQ1qq73WKhHvch0VQtg=", // password
+	}
+
+	mockRegistry := registry.NewMockRegistry()
+	mockRegistry.ChargeStations["cs1"] = cs
+
+	bus := server.NewNatsBus([]string{natsUrl}, "cs", trace.NewNoopTracerProvider().Tracer(""))
+	defer func() {
+		_ = bus.Close()
+	}()
+
+	srv := httptest.NewServer(server.NewWebsocketHandler(
+		server.WithBus(bus),
+		server.WithDeviceRegistry(mockRegistry),
+		server.WithGatewayId("gateway1")))
+	defer srv.Close()
+
+	authHeader := "Basic " + base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%s:%s", cs.ClientId, "password")))
+	dialOptions := &websocket.DialOptions{
+		Subprotocols: []string{"ocpp2.0.1"},
+		HTTPHeader: http.Header{
+			"authorization": []string{authHeader},
+		},
+	}
+
+	conn, _, err := websocket.Dial(ctx, fmt.Sprintf("%s/ws/cs1", srv.URL), dialOptions)
+	if err != nil {
+		t.Fatalf("dialing server: %v", err)
+	}
+
+	var connected server.PresenceEvent
+	select {
+	case connected = <-eventCh:
+	case <-ctx.Done():
+		t.Fatal("timeout waiting for connected event")
+	}
+	require.Equal(t, server.PresenceConnected, connected.Type)
+	require.Equal(t, "gateway1", connected.GatewayId)
+	require.Equal(t, "ocpp2.0.1", connected.Protocol)
+
+	call := ocpp.Message{
+		MessageTypeId: ocpp.MessageTypeCall,
+		MessageId:     "1",
+		Data: []json.RawMessage{
+			json.RawMessage(`"EchoRequest"`),
+			json.RawMessage(`"Payload"`),
+		},
+	}
+	data, err := json.Marshal(call)
+	require.NoError(t, err)
+
+	err = conn.Write(ctx, websocket.MessageText, data)
+	require.NoError(t, err)
+
+	typ, b, err := conn.Read(ctx)
+	require.NoError(t, err)
+	require.Equal(t, websocket.MessageText, typ)
+
+	var msg ocpp.Message
+	err = json.Unmarshal(b, &msg)
+	require.NoError(t, err)
+	require.Equal(t, ocpp.MessageTypeCallResult, msg.MessageTypeId)
+	require.Equal(t, `"Payload"`, string(msg.Data[0]))
+
+	err = conn.Close(websocket.StatusNormalClosure, "OK")
+	require.NoError(t, err)
+
+	var disconnected server.PresenceEvent
+	select {
+	case disconnected = <-eventCh:
+	case <-ctx.Done():
+		t.Fatal("timeout waiting for disconnected event")
+	}
+	require.Equal(t, server.PresenceDisconnected, disconnected.Type)
+	require.Equal(t, connected.ConnectionId, disconnected.ConnectionId)
+}
+
+func TestWebSocketHandlerNegotiatesOcpp21WithNatsBus(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	natsUrl := server.NewNatsServer(t)
+
+	// simulate manager connection
+	nc, err := nats.Connect(natsUrl)
+	require.NoError(t, err)
+	defer nc.Close()
+
+	_, err = nc.Subscribe("cs.in.ocpp21.*", func(natsMsg *nats.Msg) {
+		require.Equal(t, "cs.in.ocpp21.cs1", natsMsg.Subject)
+		var reqMsg pipe.GatewayMessage
+		err := json.Unmarshal(natsMsg.Data, &reqMsg)
+		require.NoError(t, err)
+
+		respMsg := pipe.GatewayMessage{
+			MessageType:     ocpp.MessageTypeCallResult,
+			MessageId:       reqMsg.MessageId,
+			ResponsePayload: reqMsg.RequestPayload,
+		}
+
+		b, err := json.Marshal(respMsg)
+		require.NoError(t, err)
+		err = nc.Publish("cs.out.ocpp21.cs1", b)
+		require.NoError(t, err)
+	})
+	require.NoError(t, err)
+	require.NoError(t, nc.Flush())
+
+	cs := &registry.ChargeStation{
+		ClientId:             "cs1",
+		SecurityProfile:      registry.UnsecuredTransportWithBasicAuth,
+		Base64SHA256Password: "XohImNooBHFR0OVvjcYpJ3NgPQ1qq73WKhHvch0VQtg=", // password
+	}
+
+	mockRegistry := registry.NewMockRegistry()
+	mockRegistry.ChargeStations["cs1"] = cs
+
+	bus := server.NewNatsBus([]string{natsUrl}, "cs", trace.NewNoopTracerProvider().Tracer(""))
+	defer func() {
+		_ = bus.Close()
+	}()
+
+	srv := httptest.NewServer(server.NewWebsocketHandler(
+		server.WithBus(bus),
+		server.WithDeviceRegistry(mockRegistry),
+		server.WithGatewayId("gateway1")))
+	defer srv.Close()
+
+	authHeader := "Basic " + base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%s:%s", cs.ClientId, "password")))
+	dialOptions := &websocket.DialOptions{
+		Subprotocols: []string{"ocpp2.1"},
+		HTTPHeader: http.Header{
+			"authorization": []string{authHeader},
+		},
+	}
+
+	conn, _, err := websocket.Dial(ctx, fmt.Sprintf("%s/ws/cs1", srv.URL), dialOptions)
+	require.NoError(t, err)
+	defer func() {
+		_ = conn.Close(websocket.StatusNormalClosure, "OK")
+	}()
+	require.Equal(t, "ocpp2.1", conn.Subprotocol())
+
+	call := ocpp.Message{
+		MessageTypeId: ocpp.MessageTypeCall,
+		MessageId:     "1",
+		Data: []json.RawMessage{
+			json.RawMessage(`"EchoRequest"`),
+			json.RawMessage(`"Payload"`),
+		},
+	}
+	data, err := json.Marshal(call)
+	require.NoError(t, err)
+
+	err = conn.Write(ctx, websocket.MessageText, data)
+	require.NoError(t, err)
+
+	typ, b, err := conn.Read(ctx)
+	require.NoError(t, err)
+	require.Equal(t, websocket.MessageText, typ)
+
+	var msg ocpp.Message
+	err = json.Unmarshal(b, &msg)
+	require.NoError(t, err)
+	require.Equal(t, ocpp.MessageTypeCallResult, msg.MessageTypeId)
+	require.Equal(t, `"Payload"`, string(msg.Data[0]))
+}
+
+func TestWebSocketHandlerPrefersOcpp21WithNatsBus(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	natsUrl := server.NewNatsServer(t)
+
+	// simulate manager connection
+	nc, err := nats.Connect(natsUrl)
+	require.NoError(t, err)
+	defer nc.Close()
+
+	_, err = nc.Subscribe("cs.in.ocpp21.*", func(natsMsg *nats.Msg) {
+		require.Equal(t, "cs.in.ocpp21.cs1", natsMsg.Subject)
+		var reqMsg pipe.GatewayMessage
+		err := json.Unmarshal(natsMsg.Data, &reqMsg)
+		require.NoError(t, err)
+
+		respMsg := pipe.GatewayMessage{
+			MessageType:     ocpp.MessageTypeCallResult,
+			MessageId:       reqMsg.MessageId,
+			ResponsePayload: reqMsg.RequestPayload,
+		}
+
+		b, err := json.Marshal(respMsg)
+		require.NoError(t, err)
+		err = nc.Publish("cs.out.ocpp21.cs1", b)
+		require.NoError(t, err)
+	})
+	require.NoError(t, err)
+	require.NoError(t, nc.Flush())
+
+	cs := &registry.ChargeStation{
+		ClientId:             "cs1",
+		SecurityProfile:      registry.UnsecuredTransportWithBasicAuth,
+		Base64SHA256Password: "XohImNooBHFR0OVvjcYpJ3NgPQ1qq73WKhHvch0VQtg=", // password
+	}
+
+	mockRegistry := registry.NewMockRegistry()
+	mockRegistry.ChargeStations["cs1"] = cs
+
+	bus := server.NewNatsBus([]string{natsUrl}, "cs", trace.NewNoopTracerProvider().Tracer(""))
+	defer func() {
+		_ = bus.Close()
+	}()
+
+	srv := httptest.NewServer(server.NewWebsocketHandler(
+		server.WithBus(bus),
+		server.WithDeviceRegistry(mockRegistry),
+		server.WithGatewayId("gateway1")))
+	defer srv.Close()
+
+	authHeader := "Basic " + base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%s:%s", cs.ClientId, "password")))
+	dialOptions := &websocket.DialOptions{
+		Subprotocols: []string{"ocpp2.0.1", "ocpp2.1"},
+		HTTPHeader: http.Header{
+			"authorization": []string{authHeader},
+		},
+	}
+
+	conn, _, err := websocket.Dial(ctx, fmt.Sprintf("%s/ws/cs1", srv.URL), dialOptions)
+	require.NoError(t, err)
+	defer func() {
+		_ = conn.Close(websocket.StatusNormalClosure, "OK")
+	}()
+	require.Equal(t, "ocpp2.1", conn.Subprotocol())
+
+	call := ocpp.Message{
+		MessageTypeId: ocpp.MessageTypeCall,
+		MessageId:     "1",
+		Data: []json.RawMessage{
+			json.RawMessage(`"EchoRequest"`),
+			json.RawMessage(`"Payload"`),
+		},
+	}
+	data, err := json.Marshal(call)
+	require.NoError(t, err)
+
+	err = conn.Write(ctx, websocket.MessageText, data)
+	require.NoError(t, err)
+
+	typ, b, err := conn.Read(ctx)
+	require.NoError(t, err)
+	require.Equal(t, websocket.MessageText, typ)
+
+	var msg ocpp.Message
+	err = json.Unmarshal(b, &msg)
+	require.NoError(t, err)
+	require.Equal(t, ocpp.MessageTypeCallResult, msg.MessageTypeId)
+	require.Equal(t, `"Payload"`, string(msg.Data[0]))
+}
+
+func TestWebSocketHandlerDisconnectsOnControlMessageWithNatsBus(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	natsUrl := server.NewNatsServer(t)
+
+	// simulate manager connection
+	nc, err := nats.Connect(natsUrl)
+	require.NoError(t, err)
+	defer nc.Close()
+
+	eventCh := make(chan server.PresenceEvent, 2)
+	_, err = nc.Subscribe("cs.presence.*", func(natsMsg *nats.Msg) {
+		var event server.PresenceEvent
+		err := json.Unmarshal(natsMsg.Data, &event)
+		require.NoError(t, err)
+		eventCh <- event
+	})
+	require.NoError(t, err)
+	require.NoError(t, nc.Flush())
+
+	cs := &registry.ChargeStation{
+		ClientId:             "cs1",
+		SecurityProfile:      registry.UnsecuredTransportWithBasicAuth,
+		Base64SHA256Password: "XohImNooBHFR0OVvjcYpJ3NgPQ1qq73WKhHvch0VQtg=", // password
+	}
+
+	mockRegistry := registry.NewMockRegistry()
+	mockRegistry.ChargeStations["cs1"] = cs
+
+	bus := server.NewNatsBus([]string{natsUrl}, "cs", trace.NewNoopTracerProvider().Tracer(""))
+	defer func() {
+		_ = bus.Close()
+	}()
+
+	srv := httptest.NewServer(server.NewWebsocketHandler(
+		server.WithBus(bus),
+		server.WithDeviceRegistry(mockRegistry),
+		server.WithGatewayId("gateway1")))
+	defer srv.Close()
+
+	authHeader := "Basic " + base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%s:%s", cs.ClientId, "password")))
+	dialOptions := &websocket.DialOptions{
+		Subprotocols: []string{"ocpp2.0.1"},
+		HTTPHeader: http.Header{
+			"authorization": []string{authHeader},
+		},
+	}
+
+	conn, _, err := websocket.Dial(ctx, fmt.Sprintf("%s/ws/cs1", srv.URL), dialOptions)
+	if err != nil {
+		t.Fatalf("dialing server: %v", err)
+	}
+	defer func() {
+		_ = conn.Close(websocket.StatusNormalClosure, "OK")
+	}()
+
+	select {
+	case connected := <-eventCh:
+		require.Equal(t, server.PresenceConnected, connected.Type)
+	case <-ctx.Done():
+		t.Fatal("timeout waiting for connected event")
+	}
+
+	err = nc.Publish("cs.control.cs1", []byte(`{"type":"Disconnect","reason":"credentials changed"}`))
+	require.NoError(t, err)
+
+	_, _, err = conn.Read(ctx)
+	require.Error(t, err)
+	var closeErr websocket.CloseError
+	require.ErrorAs(t, err, &closeErr)
+	require.Equal(t, websocket.StatusPolicyViolation, closeErr.Code)
+	require.Equal(t, "credentials changed", closeErr.Reason)
+
+	select {
+	case disconnected := <-eventCh:
+		require.Equal(t, server.PresenceDisconnected, disconnected.Type)
+	case <-ctx.Done():
+		t.Fatal("timeout waiting for disconnected event")
+	}
+}
+
+func TestWebSocketHandlerAuthenticatesWithNewCredentialsAfterControlMessage(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	natsUrl := server.NewNatsServer(t)
+
+	// simulate manager connection
+	nc, err := nats.Connect(natsUrl)
+	require.NoError(t, err)
+	defer nc.Close()
+
+	mockRegistry := registry.NewMockRegistry()
+	mockRegistry.ChargeStations["cs1"] = &registry.ChargeStation{
+		ClientId:             "cs1",
+		SecurityProfile:      registry.UnsecuredTransportWithBasicAuth,
+		Base64SHA256Password: "XohImNooBHFR0OVvjcYpJ3NgPQ1qq73WKhHvch0VQtg=", // password
+	}
+
+	bus := server.NewNatsBus([]string{natsUrl}, "cs", trace.NewNoopTracerProvider().Tracer(""))
+	defer func() {
+		_ = bus.Close()
+	}()
+
+	srv := httptest.NewServer(server.NewWebsocketHandler(
+		server.WithBus(bus),
+		server.WithDeviceRegistry(registry.NewCachingRegistry(mockRegistry, registry.WithCacheTtl(time.Hour))),
+		server.WithGatewayId("gateway1")))
+	defer srv.Close()
+
+	dialOptions := func(password string) *websocket.DialOptions {
+		authHeader := "Basic " + base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("cs1:%s", password)))
+		return &websocket.DialOptions{
+			Subprotocols: []string{"ocpp2.0.1"},
+			HTTPHeader: http.Header{
+				"authorization": []string{authHeader},
+			},
+		}
+	}
+
+	conn, _, err := websocket.Dial(ctx, fmt.Sprintf("%s/ws/cs1", srv.URL), dialOptions("password"))
+	require.NoError(t, err)
+
+	// the manager rotates the password and disconnects the charge station
+	mockRegistry.ChargeStations["cs1"] = &registry.ChargeStation{
+		ClientId:             "cs1",
+		SecurityProfile:      registry.UnsecuredTransportWithBasicAuth,
+		Base64SHA256Password: "9CVG1ezdRSUJgIstbQQTtac4xwp5O5nM+O1vQjqsg9M=", // rotated
+	}
+	// wait for the bus subscription before sending the control message
+	require.Eventually(t, func() bool {
+		err = nc.Publish("cs.control.cs1", []byte(`{"type":"Disconnect","reason":"credentials changed"}`))
+		require.NoError(t, err)
+		readCtx, readCancel := context.WithTimeout(ctx, 100*time.Millisecond)
+		defer readCancel()
+		_, _, err = conn.Read(readCtx)
+		var closeErr websocket.CloseError
+		return errors.As(err, &closeErr)
+	}, 10*time.Second, 10*time.Millisecond)
+
+	_, resp, err := websocket.Dial(ctx, fmt.Sprintf("%s/ws/cs1", srv.URL), dialOptions("password"))
+	require.Error(t, err)
+	require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	conn, _, err = websocket.Dial(ctx, fmt.Sprintf("%s/ws/cs1", srv.URL), dialOptions("rotated"))
+	require.NoError(t, err)
+	_ = conn.Close(websocket.StatusNormalClosure, "OK")
+}
+
+func TestWebSocketHandlerDisconnectsOnControlMessageWithMqttBus(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	broker, addr := server.NewBroker(t)
+	err := broker.Serve()
+	if err != nil {
+		t.Fatalf("starting broker: %v", err)
+	}
+	defer func() {
+		err := broker.Close()
+		if err != nil {
+			t.Logf("WARN: broker close: %v", err)
+		}
+	}()
+
+	// simulate manager connection
+	eventCh := make(chan server.PresenceEvent, 2)
+	client, err := autopaho.NewConnection(ctx, autopaho.ClientConfig{
+		BrokerUrls:        []*url.URL{addr},
+		KeepAlive:         10,
+		ConnectRetryDelay: 2 * time.Second,
+		OnConnectionUp: func(manager *autopaho.ConnectionManager, connack *paho.Connack) {
+			_, err := manager.Subscribe(ctx, &paho.Subscribe{
+				Subscriptions: map[string]paho.SubscribeOptions{
+					"cs/presence/+": {},
+				},
+			})
+			require.NoError(t, err)
+		},
+		ClientConfig: paho.ClientConfig{
+			ClientID: "test",
+			Router: paho.NewSingleHandlerRouter(func(publish *paho.Publish) {
+				var event server.PresenceEvent
+				err := json.Unmarshal(publish.Payload, &event)
+				require.NoError(t, err)
+				eventCh <- event
+			}),
+		},
+	})
+	defer func() {
+		err := client.Disconnect(ctx)
+		if err != nil {
+			t.Logf("WARN: mqtt client disconnect: %v", err)
+		}
+	}()
+
+	err = client.AwaitConnection(ctx)
+	require.NoError(t, err)
+
+	cs := &registry.ChargeStation{
+		ClientId:             "cs1",
+		SecurityProfile:      registry.UnsecuredTransportWithBasicAuth,
+		Base64SHA256Password: "XohImNooBHFR0OVvjcYpJ3NgPQ1qq73WKhHvch0VQtg=", // password
+	}
+
+	mockRegistry := registry.NewMockRegistry()
+	mockRegistry.ChargeStations["cs1"] = cs
+
+	srv := httptest.NewServer(server.NewWebsocketHandler(
+		server.WithMqttBrokerUrl(addr),
+		server.WithMqttTopicPrefix("cs"),
+		server.WithDeviceRegistry(mockRegistry),
+		server.WithGatewayId("gateway1"),
+		server.WithMqttConnectSettings(15*time.Second, 15*time.Second, 5*time.Second)))
+	defer srv.Close()
+
+	authHeader := "Basic " + base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%s:%s", cs.ClientId, "password")))
+	dialOptions := &websocket.DialOptions{
+		Subprotocols: []string{"ocpp1.6"},
+		HTTPHeader: http.Header{
+			"authorization": []string{authHeader},
+		},
+	}
+
+	conn, _, err := websocket.Dial(ctx, fmt.Sprintf("%s/ws/cs1", srv.URL), dialOptions)
+	if err != nil {
+		t.Fatalf("dialing server: %v", err)
+	}
+	defer func() {
+		_ = conn.Close(websocket.StatusNormalClosure, "OK")
+	}()
+
+	select {
+	case connected := <-eventCh:
+		require.Equal(t, server.PresenceConnected, connected.Type)
+	case <-ctx.Done():
+		t.Fatal("timeout waiting for connected event")
+	}
+
+	_, err = client.Publish(ctx, &paho.Publish{
+		Topic:   "cs/control/cs1",
+		QoS:     1,
+		Payload: []byte(`{"type":"Disconnect"}`),
+	})
+	require.NoError(t, err)
+
+	_, _, err = conn.Read(ctx)
+	require.Error(t, err)
+	var closeErr websocket.CloseError
+	require.ErrorAs(t, err, &closeErr)
+	require.Equal(t, websocket.StatusPolicyViolation, closeErr.Code)
+	require.Equal(t, "disconnect requested", closeErr.Reason)
+}
+
 func TestConnectionFromUnknownChargeStation(t *testing.T) {
 	//defer goleak.VerifyNone(t)
 
@@ -6,7 +6,9 @@ package api
 import (
 	"bytes"
 	"compress/gzip"
+	"context"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -19,19 +21,81 @@ import (
 	"github.com/go-chi/chi/v5"
 )
 
+const (
+	ApiKeyAuthScopes = "ApiKeyAuth.Scopes"
+	BearerAuthScopes = "BearerAuth.Scopes"
+)
+
+// Defines values for AuthLockoutType.
+const (
+	AuthLockoutTypeChargeStation AuthLockoutType = "ChargeStation"
+	AuthLockoutTypeRemoteAddr    AuthLockoutType = "RemoteAddr"
+)
+
+// Defines values for CertificateCampaignCertificateType.
+const (
+	CertificateCampaignCertificateTypeCSMS CertificateCampaignCertificateType = "CSMS"
+	CertificateCampaignCertificateTypeMF   CertificateCampaignCertificateType = "MF"
+	CertificateCampaignCertificateTypeMO   CertificateCampaignCertificateType = "MO"
+	CertificateCampaignCertificateTypeV2G  CertificateCampaignCertificateType = "V2G"
+)
+
+// Defines values for CertificateCampaignStatus.
+const (
+	CertificateCampaignStatusCancelled  CertificateCampaignStatus = "Cancelled"
+	CertificateCampaignStatusCompleted  CertificateCampaignStatus = "Completed"
+	CertificateCampaignStatusHalted     CertificateCampaignStatus = "Halted"
+	CertificateCampaignStatusInProgress CertificateCampaignStatus = "InProgress"
+)
+
+// Defines values for CertificateCampaignRequestCertificateType.
+const (
+	CertificateCampaignRequestCertificateTypeCSMS CertificateCampaignRequestCertificateType = "CSMS"
+	CertificateCampaignRequestCertificateTypeMF   CertificateCampaignRequestCertificateType = "MF"
+	CertificateCampaignRequestCertificateTypeMO   CertificateCampaignRequestCertificateType = "MO"
+	CertificateCampaignRequestCertificateTypeV2G  CertificateCampaignRequestCertificateType = "V2G"
+)
+
+// Defines values for CertificateCampaignStationStatus.
+const (
+	CertificateCampaignStationStatusAccepted CertificateCampaignStationStatus = "Accepted"
+	CertificateCampaignStationStatusFailed   CertificateCampaignStationStatus = "Failed"
+	CertificateCampaignStationStatusPending  CertificateCampaignStationStatus = "Pending"
+	CertificateCampaignStationStatusRejected CertificateCampaignStationStatus = "Rejected"
+	CertificateCampaignStationStatusWaiting  CertificateCampaignStationStatus = "Waiting"
+)
+
 // Defines values for ChargeStationInstallCertificatesCertificatesStatus.
 const (
-	Accepted ChargeStationInstallCertificatesCertificatesStatus = "Accepted"
-	Pending  ChargeStationInstallCertificatesCertificatesStatus = "Pending"
-	Rejected ChargeStationInstallCertificatesCertificatesStatus = "Rejected"
+	ChargeStationInstallCertificatesCertificatesStatusAccepted ChargeStationInstallCertificatesCertificatesStatus = "Accepted"
+	ChargeStationInstallCertificatesCertificatesStatusPending  ChargeStationInstallCertificatesCertificatesStatus = "Pending"
+	ChargeStationInstallCertificatesCertificatesStatusRejected ChargeStationInstallCertificatesCertificatesStatus = "Rejected"
 )
 
 // Defines values for ChargeStationInstallCertificatesCertificatesType.
 const (
-	CSMS ChargeStationInstallCertificatesCertificatesType = "CSMS"
-	MF   ChargeStationInstallCertificatesCertificatesType = "MF"
-	MO   ChargeStationInstallCertificatesCertificatesType = "MO"
-	V2G  ChargeStationInstallCertificatesCertificatesType = "V2G"
+	ChargeStationInstallCertificatesCertificatesTypeCSMS ChargeStationInstallCertificatesCertificatesType = "CSMS"
+	ChargeStationInstallCertificatesCertificatesTypeMF   ChargeStationInstallCertificatesCertificatesType = "MF"
+	ChargeStationInstallCertificatesCertificatesTypeMO   ChargeStationInstallCertificatesCertificatesType = "MO"
+	ChargeStationInstallCertificatesCertificatesTypeV2G  ChargeStationInstallCertificatesCertificatesType = "V2G"
+)
+
+// Defines values for ChargeStationOperationEvent.
+const (
+	ChargeStationOperationEventAttempted ChargeStationOperationEvent = "Attempted"
+	ChargeStationOperationEventFailed    ChargeStationOperationEvent = "Failed"
+	ChargeStationOperationEventRearmed   ChargeStationOperationEvent = "Rearmed"
+	ChargeStationOperationEventRequested ChargeStationOperationEvent = "Requested"
+	ChargeStationOperationEventResponded ChargeStationOperationEvent = "Responded"
+	ChargeStationOperationEventSent      ChargeStationOperationEvent = "Sent"
+)
+
+// Defines values for ChargeStationOperationType.
+const (
+	ChargeStationOperationTypeCertificate         ChargeStationOperationType = "Certificate"
+	ChargeStationOperationTypeCertificateDeletion ChargeStationOperationType = "CertificateDeletion"
+	ChargeStationOperationTypeSetting             ChargeStationOperationType = "Setting"
+	ChargeStationOperationTypeTrigger             ChargeStationOperationType = "Trigger"
 )
 
 // Defines values for ChargeStationTriggerTrigger.
@@ -98,6 +162,34 @@ const (
 	UNKNOWN            ConnectorStandard = "UNKNOWN"
 )
 
+// Defines values for FailedOperationType.
+const (
+	FailedOperationTypeCertificate FailedOperationType = "Certificate"
+	FailedOperationTypeSetting     FailedOperationType = "Setting"
+	FailedOperationTypeTrigger     FailedOperationType = "Trigger"
+)
+
+// Defines values for InstalledCertificateCertificateType.
+const (
+	InstalledCertificateCertificateTypeCSMS InstalledCertificateCertificateType = "CSMS"
+	InstalledCertificateCertificateTypeEVCC InstalledCertificateCertificateType = "EVCC"
+	InstalledCertificateCertificateTypeMF   InstalledCertificateCertificateType = "MF"
+	InstalledCertificateCertificateTypeMO   InstalledCertificateCertificateType = "MO"
+	InstalledCertificateCertificateTypeV2G  InstalledCertificateCertificateType = "V2G"
+)
+
+// Defines values for InstalledCertificateDeletionStatus.
+const (
+	InstalledCertificateDeletionStatusFailed  InstalledCertificateDeletionStatus = "Failed"
+	InstalledCertificateDeletionStatusPending InstalledCertificateDeletionStatus = "Pending"
+)
+
+// Defines values for IssuedCertificateCertificateType.
+const (
+	IssuedCertificateCertificateTypeChargeStation IssuedCertificateCertificateType = "ChargeStation"
+	IssuedCertificateCertificateTypeV2G           IssuedCertificateCertificateType = "V2G"
+)
+
 // Defines values for LocationParkingType.
 const (
 	ALONGMOTORWAY     LocationParkingType = "ALONG_MOTORWAY"
@@ -108,12 +200,26 @@ const (
 	UNDERGROUNDGARAGE LocationParkingType = "UNDERGROUND_GARAGE"
 )
 
+// Defines values for OcpiDeliveryStatus.
+const (
+	OcpiDeliveryStatusFailed  OcpiDeliveryStatus = "Failed"
+	OcpiDeliveryStatusPending OcpiDeliveryStatus = "Pending"
+)
+
 // Defines values for RegistrationStatus.
 const (
 	PENDING    RegistrationStatus = "PENDING"
 	REGISTERED RegistrationStatus = "REGISTERED"
 )
 
+// Defines values for RootCertificateCertificateType.
+const (
+	CSMS RootCertificateCertificateType = "CSMS"
+	MF   RootCertificateCertificateType = "MF"
+	MO   RootCertificateCertificateType = "MO"
+	V2G  RootCertificateCertificateType = "V2G"
+)
+
 // Defines values for TokenCacheMode.
 const (
 	ALLOWED        TokenCacheMode = "ALLOWED"
@@ -130,12 +236,219 @@ const (
 	RFID      TokenType = "RFID"
 )
 
+// Defines values for ListCertificateCampaignsParamsStatus.
+const (
+	ListCertificateCampaignsParamsStatusCancelled  ListCertificateCampaignsParamsStatus = "Cancelled"
+	ListCertificateCampaignsParamsStatusCompleted  ListCertificateCampaignsParamsStatus = "Completed"
+	ListCertificateCampaignsParamsStatusHalted     ListCertificateCampaignsParamsStatus = "Halted"
+	ListCertificateCampaignsParamsStatusInProgress ListCertificateCampaignsParamsStatus = "InProgress"
+)
+
+// Defines values for ListOcpiDeliveriesParamsStatus.
+const (
+	ListOcpiDeliveriesParamsStatusFailed  ListOcpiDeliveriesParamsStatus = "Failed"
+	ListOcpiDeliveriesParamsStatusPending ListOcpiDeliveriesParamsStatus = "Pending"
+)
+
+// AuthFailure A failed authentication attempt
+type AuthFailure struct {
+	// RemoteAddr The source address of the attempt
+	RemoteAddr *string `json:"remoteAddr,omitempty"`
+}
+
+// AuthLockout A lockout of a charge station or source address after repeated authentication failures
+type AuthLockout struct {
+	// Id The charge station identifier or source address
+	Id string `json:"id"`
+
+	// LastFailureAt When the most recent failed attempt was made
+	LastFailureAt *time.Time `json:"lastFailureAt,omitempty"`
+
+	// LockedUntil When the lockout expires
+	LockedUntil time.Time `json:"lockedUntil"`
+
+	// Lockouts The number of consecutive lockouts, which determines the duration of the next lockout
+	Lockouts int             `json:"lockouts"`
+	Type     AuthLockoutType `json:"type"`
+}
+
+// AuthLockoutStatus Whether authentication attempts are locked out: a lockout that is not in force is not included
+type AuthLockoutStatus struct {
+	// ChargeStationLockedUntil When the lockout of the charge station expires
+	ChargeStationLockedUntil *time.Time `json:"chargeStationLockedUntil,omitempty"`
+
+	// RemoteAddrLockedUntil When the lockout of the source address expires
+	RemoteAddrLockedUntil *time.Time `json:"remoteAddrLockedUntil,omitempty"`
+}
+
+// AuthLockoutType defines model for AuthLockoutType.
+type AuthLockoutType string
+
 // Certificate A client certificate
 type Certificate struct {
 	// Certificate The PEM encoded certificate with newlines replaced by `\n`
 	Certificate string `json:"certificate"`
 }
 
+// CertificateCampaign The progress of installing a root certificate on a set of charge stations
+type CertificateCampaign struct {
+	// CertificateId The hex encoded SHA-256 hash of the DER bytes of the certificate
+	CertificateId   string                             `json:"certificateId"`
+	CertificateType CertificateCampaignCertificateType `json:"certificateType"`
+	CreatedAt       time.Time                          `json:"createdAt"`
+
+	// Id The campaign identifier
+	Id          string `json:"id"`
+	MaxFailures int    `json:"maxFailures"`
+
+	// NextWaveAt When the next wave will be queued, present while the campaign is in progress
+	NextWaveAt *time.Time `json:"nextWaveAt,omitempty"`
+
+	// OcppVersion The OCPP version that the charge stations were targeted by
+	OcppVersion *string `json:"ocppVersion,omitempty"`
+
+	// Progress The number of charge stations in a certificate campaign with each status
+	Progress CertificateCampaignProgress `json:"progress"`
+
+	// Stations The status of each charge station (only included when looking up a single campaign)
+	Stations *[]CertificateCampaignStation `json:"stations,omitempty"`
+	Status   CertificateCampaignStatus     `json:"status"`
+
+	// Tag The tag that the charge stations were targeted by
+	Tag                 *string `json:"tag,omitempty"`
+	WaveIntervalMinutes int     `json:"waveIntervalMinutes"`
+	WaveSize            int     `json:"waveSize"`
+
+	// Waves The number of waves that have been queued
+	Waves int `json:"waves"`
+}
+
+// CertificateCampaignCertificateType defines model for CertificateCampaign.CertificateType.
+type CertificateCampaignCertificateType string
+
+// CertificateCampaignStatus defines model for CertificateCampaign.Status.
+type CertificateCampaignStatus string
+
+// CertificateCampaignProgress The number of charge stations in a certificate campaign with each status
+type CertificateCampaignProgress struct {
+	// Accepted Charge stations that have installed the certificate
+	Accepted int `json:"accepted"`
+
+	// Failed Charge stations that did not install the certificate before the retries were exhausted
+	Failed int `json:"failed"`
+
+	// Pending Charge stations that have the certificate queued for installation
+	Pending int `json:"pending"`
+
+	// Rejected Charge stations that rejected the certificate, the installation is retried
+	Rejected int `json:"rejected"`
+	Total    int `json:"total"`
+
+	// Waiting Charge stations that have not yet been included in a wave
+	Waiting int `json:"waiting"`
+}
+
+// CertificateCampaignRequest A request to install a root certificate on a set of charge stations
+type CertificateCampaignRequest struct {
+	// Certificate The PEM encoded certificate with newlines replaced by `\n`
+	Certificate     string                                    `json:"certificate"`
+	CertificateType CertificateCampaignRequestCertificateType `json:"certificateType"`
+
+	// MaxFailures The number of failed charge stations that halts the campaign, defaults to 0 (never halt)
+	MaxFailures *int `json:"maxFailures,omitempty"`
+
+	// OcppVersion Only target the charge stations that last connected with this OCPP version
+	OcppVersion *string `json:"ocppVersion,omitempty"`
+
+	// Tag Only target the charge stations with this tag
+	Tag *string `json:"tag,omitempty"`
+
+	// WaveIntervalMinutes The number of minutes between waves, defaults to 60
+	WaveIntervalMinutes *int `json:"waveIntervalMinutes,omitempty"`
+
+	// WaveSize The number of charge stations in each wave, defaults to 10
+	WaveSize *int `json:"waveSize,omitempty"`
+}
+
+// CertificateCampaignRequestCertificateType defines model for CertificateCampaignRequest.CertificateType.
+type CertificateCampaignRequestCertificateType string
+
+// CertificateCampaignStation The progress of a certificate campaign for a single charge station
+type CertificateCampaignStation struct {
+	// CsId The charge station identifier
+	CsId   string                           `json:"csId"`
+	Status CertificateCampaignStationStatus `json:"status"`
+
+	// UpdatedAt When the status last changed
+	UpdatedAt *time.Time `json:"updatedAt,omitempty"`
+
+	// Wave The wave that the charge station was included in
+	Wave *int `json:"wave,omitempty"`
+}
+
+// CertificateCampaignStationStatus defines model for CertificateCampaignStation.Status.
+type CertificateCampaignStationStatus string
+
+// CertificateRevocation A revoked charge station client certificate
+type CertificateRevocation struct {
+	// CertificateHash The base64 encoded SHA-256 hash of the DER bytes of the certificate
+	CertificateHash string `json:"certificateHash"`
+
+	// CsId The identifier of the charge station that the certificate was issued to
+	CsId string `json:"csId"`
+
+	// Reason Why the certificate has been revoked
+	Reason *string `json:"reason,omitempty"`
+
+	// RevokedAt When the certificate was revoked
+	RevokedAt time.Time `json:"revokedAt"`
+}
+
+// CertificateRevocationRequest Identifies a charge station client certificate to revoke: either the certificate or its hash must be provided
+type CertificateRevocationRequest struct {
+	// Certificate The PEM encoded certificate with newlines replaced by `\n`
+	Certificate *string `json:"certificate,omitempty"`
+
+	// CertificateHash The base64 encoded SHA-256 hash of the DER bytes of the certificate
+	CertificateHash *string `json:"certificateHash,omitempty"`
+
+	// Reason Why the certificate has been revoked
+	Reason *string `json:"reason,omitempty"`
+}
+
+// ChargeStation A registered charge station
+type ChargeStation struct {
+	// FirmwareVersion The firmware version reported in the charge station's most recent boot notification
+	FirmwareVersion *string `json:"firmwareVersion,omitempty"`
+
+	// Id The charge station identifier
+	Id string `json:"id"`
+
+	// LastSeen When the charge station last sent a boot notification or heartbeat
+	LastSeen *time.Time `json:"lastSeen,omitempty"`
+
+	// Model The model reported in the charge station's most recent boot notification
+	Model *string `json:"model,omitempty"`
+
+	// OcppVersion The OCPP version the charge station last connected with (only included when looking up a single charge station)
+	OcppVersion *string `json:"ocppVersion,omitempty"`
+
+	// RegisteredAt When the charge station was registered
+	RegisteredAt *time.Time `json:"registeredAt,omitempty"`
+
+	// SecurityProfile The security profile the charge station is registered with (only included when looking up a single charge station)
+	SecurityProfile *int `json:"securityProfile,omitempty"`
+
+	// SerialNumber The serial number reported in the charge station's most recent boot notification
+	SerialNumber *string `json:"serialNumber,omitempty"`
+
+	// Tags The tags that are used to group the charge station
+	Tags *[]string `json:"tags,omitempty"`
+
+	// Vendor The vendor reported in the charge station's most recent boot notification
+	Vendor *string `json:"vendor,omitempty"`
+}
+
 // ChargeStationAuth Connection details for a charge station
 type ChargeStationAuth struct {
 	// Base64SHA256Password The base64 encoded, SHA-256 hash of the charge station password
@@ -148,6 +461,39 @@ type ChargeStationAuth struct {
 	SecurityProfile int `json:"securityProfile"`
 }
 
+// ChargeStationConnection The connection status of a charge station
+type ChargeStationConnection struct {
+	// Connected Whether the charge station is currently connected
+	Connected bool `json:"connected"`
+
+	// ConnectedAt When the charge station connected
+	ConnectedAt *time.Time `json:"connectedAt,omitempty"`
+
+	// DisconnectedAt When the charge station disconnected (not included while connected)
+	DisconnectedAt *time.Time `json:"disconnectedAt,omitempty"`
+
+	// GatewayId The gateway instance that the charge station is, or was last, connected to
+	GatewayId *string `json:"gatewayId,omitempty"`
+
+	// Id The charge station identifier
+	Id string `json:"id"`
+
+	// OcppVersion The OCPP version negotiated for the connection
+	OcppVersion *string `json:"ocppVersion,omitempty"`
+
+	// RemoteAddr The network address that the charge station connected from
+	RemoteAddr *string `json:"remoteAddr,omitempty"`
+
+	// SecurityProfile The security profile used for the connection
+	SecurityProfile *int `json:"securityProfile,omitempty"`
+}
+
+// ChargeStationDisconnect Disconnect a charge station
+type ChargeStationDisconnect struct {
+	// Reason The reason provided to the charge station when its websocket is closed
+	Reason *string `json:"reason,omitempty"`
+}
+
 // ChargeStationInstallCertificates The set of certificates to install on the charge station. The certificates will be sent
 // to the charge station asynchronously.
 type ChargeStationInstallCertificates struct {
@@ -167,17 +513,94 @@ type ChargeStationInstallCertificatesCertificatesStatus string
 // ChargeStationInstallCertificatesCertificatesType defines model for ChargeStationInstallCertificates.Certificates.Type.
 type ChargeStationInstallCertificatesCertificatesType string
 
+// ChargeStationInstalledCertificates The certificates that a charge station last reported as installed
+type ChargeStationInstalledCertificates struct {
+	Certificates []InstalledCertificate `json:"certificates"`
+
+	// CsId The charge station identifier
+	CsId string `json:"csId"`
+
+	// MissingRootCertificates The identifiers of the current root certificates that are not installed
+	MissingRootCertificates []string `json:"missingRootCertificates"`
+
+	// ReportedAt When the charge station last reported its installed certificates
+	ReportedAt *time.Time `json:"reportedAt,omitempty"`
+
+	// RequestedAt When the charge station was last asked for its installed certificates
+	RequestedAt *time.Time `json:"requestedAt,omitempty"`
+
+	// RevokedRootCertificates The identifiers of the revoked root certificates that are still installed
+	RevokedRootCertificates []string `json:"revokedRootCertificates"`
+}
+
+// ChargeStationOperation An entry in the log of operations that the CSMS has initiated with a charge station
+type ChargeStationOperation struct {
+	// Action The OCPP action of the call that the event relates to
+	Action *string `json:"action,omitempty"`
+
+	// Attempt The number of the attempt to deliver the change
+	Attempt *int `json:"attempt,omitempty"`
+
+	// Event What happened to the operation
+	Event ChargeStationOperationEvent `json:"event"`
+
+	// MessageId The message identifier of the call that the event relates to
+	MessageId *string `json:"messageId,omitempty"`
+
+	// Payload The JSON payload of the call or response with any secrets redacted
+	Payload *string `json:"payload,omitempty"`
+
+	// RequestedBy The API caller that requested the change
+	RequestedBy *string `json:"requestedBy,omitempty"`
+
+	// Result The status returned by the charge station or the error that occurred
+	Result *string `json:"result,omitempty"`
+
+	// Subject The setting name, certificate identifier, trigger message or serial number of the deleted certificate that the event relates to
+	Subject *string `json:"subject,omitempty"`
+
+	// Timestamp When the event happened
+	Timestamp time.Time `json:"timestamp"`
+
+	// Type The type of change that the event relates to
+	Type *ChargeStationOperationType `json:"type,omitempty"`
+}
+
+// ChargeStationOperationEvent What happened to the operation
+type ChargeStationOperationEvent string
+
+// ChargeStationOperationType The type of change that the event relates to
+type ChargeStationOperationType string
+
 // ChargeStationSettings Settings for a charge station
 type ChargeStationSettings map[string]string
 
+// ChargeStationTags The tags that are used to group a charge station
+type ChargeStationTags struct {
+	Tags []string `json:"tags"`
+}
+
 // ChargeStationTrigger Trigger a charge station action
 type ChargeStationTrigger struct {
+	// ConnectorId The connector on the EVSE that the message is requested for (OCPP 2.0.1 only): requires evseId
+	ConnectorId *int `json:"connectorId,omitempty"`
+
+	// EvseId The EVSE that the message is requested for (OCPP 2.0.1 only)
+	EvseId  *int                        `json:"evseId,omitempty"`
 	Trigger ChargeStationTriggerTrigger `json:"trigger"`
 }
 
 // ChargeStationTriggerTrigger defines model for ChargeStationTrigger.Trigger.
 type ChargeStationTriggerTrigger string
 
+// ChargeStationTriggerRequest Either a single trigger or a sequence of triggers
+type ChargeStationTriggerRequest struct {
+	union json.RawMessage
+}
+
+// ChargeStationTriggers Trigger a sequence of charge station actions
+type ChargeStationTriggers = []ChargeStationTrigger
+
 // Connector defines model for Connector.
 type Connector struct {
 	Format      ConnectorFormat    `json:"format"`
@@ -197,6 +620,15 @@ type ConnectorPowerType string
 // ConnectorStandard defines model for Connector.Standard.
 type ConnectorStandard string
 
+// Contract A Plug & Charge contract that associates an eMAID with a provisioned vehicle
+type Contract struct {
+	// Emaid The contract ID (eMAID) (with optional component separators)
+	Emaid string `json:"emaid"`
+
+	// ProvisioningCertificateId The provisioning certificate ID (PCID) of the vehicle: the common name of its OEM provisioning certificate
+	ProvisioningCertificateId string `json:"provisioningCertificateId"`
+}
+
 // Evse defines model for Evse.
 type Evse struct {
 	Connectors []Connector `json:"connectors"`
@@ -207,12 +639,90 @@ type Evse struct {
 	Uid string `json:"uid"`
 }
 
+// FailedOperation An operation that could not be delivered to a charge station
+type FailedOperation struct {
+	// Attempts The number of times the operation was sent to the charge station
+	Attempts int `json:"attempts"`
+
+	// Id The setting name, certificate identifier or trigger message
+	Id string `json:"id"`
+
+	// Type The type of operation
+	Type FailedOperationType `json:"type"`
+}
+
+// FailedOperationType The type of operation
+type FailedOperationType string
+
 // GeoLocation defines model for GeoLocation.
 type GeoLocation struct {
 	Latitude  string `json:"latitude"`
 	Longitude string `json:"longitude"`
 }
 
+// InstalledCertificate A certificate that a charge station has reported as installed
+type InstalledCertificate struct {
+	CertificateType InstalledCertificateCertificateType `json:"certificateType"`
+
+	// DeletionStatus Present if the certificate has been queued for deletion
+	DeletionStatus *InstalledCertificateDeletionStatus `json:"deletionStatus,omitempty"`
+
+	// HashAlgorithm The hash algorithm used for the issuer name and key hashes
+	HashAlgorithm string `json:"hashAlgorithm"`
+
+	// IssuerKeyHash The hex encoded hash of the issuer's public key
+	IssuerKeyHash string `json:"issuerKeyHash"`
+
+	// IssuerNameHash The hex encoded hash of the issuer's distinguished name
+	IssuerNameHash string `json:"issuerNameHash"`
+
+	// SerialNumber The hex encoded serial number of the certificate
+	SerialNumber string `json:"serialNumber"`
+}
+
+// InstalledCertificateCertificateType defines model for InstalledCertificate.CertificateType.
+type InstalledCertificateCertificateType string
+
+// InstalledCertificateDeletionStatus Present if the certificate has been queued for deletion
+type InstalledCertificateDeletionStatus string
+
+// InstalledCertificateCompliance The root certificates that are missing from, or revoked but still installed on, a charge station
+type InstalledCertificateCompliance struct {
+	// CsId The charge station identifier
+	CsId string `json:"csId"`
+
+	// MissingRootCertificates The identifiers of the current root certificates that are not installed
+	MissingRootCertificates []string `json:"missingRootCertificates"`
+
+	// RevokedRootCertificates The identifiers of the revoked root certificates that are still installed
+	RevokedRootCertificates []string `json:"revokedRootCertificates"`
+}
+
+// IssuedCertificate A certificate that has been issued to a charge station
+type IssuedCertificate struct {
+	// CertificateId The hex encoded SHA-256 hash of the DER bytes of the certificate
+	CertificateId   string                           `json:"certificateId"`
+	CertificateType IssuedCertificateCertificateType `json:"certificateType"`
+
+	// CsId The identifier of the charge station that the certificate was issued to
+	CsId string `json:"csId"`
+
+	// IssuedAt When the certificate was issued
+	IssuedAt time.Time `json:"issuedAt"`
+
+	// NotAfter When the certificate expires
+	NotAfter time.Time `json:"notAfter"`
+
+	// RenewalRequestedAt When the charge station was last asked to renew the certificate
+	RenewalRequestedAt *time.Time `json:"renewalRequestedAt,omitempty"`
+
+	// SerialNumber The hex encoded serial number of the certificate
+	SerialNumber *string `json:"serialNumber,omitempty"`
+}
+
+// IssuedCertificateCertificateType defines model for IssuedCertificate.CertificateType.
+type IssuedCertificateCertificateType string
+
 // Location A charge station location
 type Location struct {
 	Address     string               `json:"address"`
@@ -230,6 +740,48 @@ type Location struct {
 // LocationParkingType defines model for Location.ParkingType.
 type LocationParkingType string
 
+// OcpiDelivery An outbound OCPI request that has not been delivered
+type OcpiDelivery struct {
+	// Attempts The number of failed attempts
+	Attempts int `json:"attempts"`
+
+	// Body The request body
+	Body *string `json:"body,omitempty"`
+
+	// CountryCode The country code of the receiving party
+	CountryCode string `json:"countryCode"`
+
+	// Created When the delivery was created
+	Created time.Time `json:"created"`
+
+	// Id The delivery identifier
+	Id string `json:"id"`
+
+	// LastError The error from the last failed attempt
+	LastError *string `json:"lastError,omitempty"`
+
+	// Method The HTTP method
+	Method string `json:"method"`
+
+	// Module The OCPI module the request is for, when set the URL is relative to the party's endpoint for the module
+	Module *string `json:"module,omitempty"`
+
+	// NextAttempt When the delivery will next be attempted
+	NextAttempt time.Time `json:"nextAttempt"`
+
+	// PartyId The party id of the receiving party
+	PartyId string `json:"partyId"`
+
+	// Status Whether the delivery will be retried (`Pending`) or has exhausted its retries (`Failed`)
+	Status OcpiDeliveryStatus `json:"status"`
+
+	// Url The URL the request is sent to
+	Url string `json:"url"`
+}
+
+// OcpiDeliveryStatus Whether the delivery will be retried (`Pending`) or has exhausted its retries (`Failed`)
+type OcpiDeliveryStatus string
+
 // Registration Defines the initial connection details for the OCPI registration process
 type Registration struct {
 	// Status The status of the registration request. If the request is marked as `REGISTERED` then the token will be allowed to
@@ -251,6 +803,22 @@ type Registration struct {
 // endpoints.
 type RegistrationStatus string
 
+// RootCertificate A root certificate that charge stations are expected to have installed (or removed, once revoked)
+type RootCertificate struct {
+	// Certificate The PEM encoded certificate with newlines replaced by `\n`
+	Certificate string `json:"certificate"`
+
+	// CertificateId The hex encoded SHA-256 hash of the DER bytes of the certificate, ignored when setting the certificate
+	CertificateId   *string                        `json:"certificateId,omitempty"`
+	CertificateType RootCertificateCertificateType `json:"certificateType"`
+
+	// Revoked Whether the certificate must be removed from charge stations, defaults to false
+	Revoked *bool `json:"revoked,omitempty"`
+}
+
+// RootCertificateCertificateType defines model for RootCertificate.CertificateType.
+type RootCertificateCertificateType string
+
 // Status HTTP status
 type Status struct {
 	// Error The error details
@@ -305,6 +873,83 @@ type TokenCacheMode string
 // TokenType The type of token
 type TokenType string
 
+// ListAuthLockoutsParams defines parameters for ListAuthLockouts.
+type ListAuthLockoutsParams struct {
+	Offset *int `form:"offset,omitempty" json:"offset,omitempty"`
+	Limit  *int `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
+// ListCertificateCampaignsParams defines parameters for ListCertificateCampaigns.
+type ListCertificateCampaignsParams struct {
+	// Status Only list campaigns with this status
+	Status *ListCertificateCampaignsParamsStatus `form:"status,omitempty" json:"status,omitempty"`
+	Offset *int                                  `form:"offset,omitempty" json:"offset,omitempty"`
+	Limit  *int                                  `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
+// ListCertificateCampaignsParamsStatus defines parameters for ListCertificateCampaigns.
+type ListCertificateCampaignsParamsStatus string
+
+// ListConnectedChargeStationsParams defines parameters for ListConnectedChargeStations.
+type ListConnectedChargeStationsParams struct {
+	Offset *int `form:"offset,omitempty" json:"offset,omitempty"`
+	Limit  *int `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
+// ListChargeStationsParams defines parameters for ListChargeStations.
+type ListChargeStationsParams struct {
+	// Vendor Only list charge stations from this vendor
+	Vendor *string `form:"vendor,omitempty" json:"vendor,omitempty"`
+
+	// Model Only list charge stations of this model
+	Model *string `form:"model,omitempty" json:"model,omitempty"`
+
+	// FirmwareVersion Only list charge stations running this firmware version
+	FirmwareVersion *string `form:"firmwareVersion,omitempty" json:"firmwareVersion,omitempty"`
+
+	// Tag Only list charge stations with this tag
+	Tag    *string `form:"tag,omitempty" json:"tag,omitempty"`
+	Offset *int    `form:"offset,omitempty" json:"offset,omitempty"`
+	Limit  *int    `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
+// LookupChargeStationAuthLockoutParams defines parameters for LookupChargeStationAuthLockout.
+type LookupChargeStationAuthLockoutParams struct {
+	// RemoteAddr The source address of the authentication attempt
+	RemoteAddr *string `form:"remoteAddr,omitempty" json:"remoteAddr,omitempty"`
+}
+
+// ListChargeStationOperationsParams defines parameters for ListChargeStationOperations.
+type ListChargeStationOperationsParams struct {
+	Offset *int `form:"offset,omitempty" json:"offset,omitempty"`
+	Limit  *int `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
+// ListExpiringCertificatesParams defines parameters for ListExpiringCertificates.
+type ListExpiringCertificatesParams struct {
+	// Days The number of days to look ahead, defaults to 30
+	Days   *int `form:"days,omitempty" json:"days,omitempty"`
+	Offset *int `form:"offset,omitempty" json:"offset,omitempty"`
+	Limit  *int `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
+// ListNonCompliantCertificatesParams defines parameters for ListNonCompliantCertificates.
+type ListNonCompliantCertificatesParams struct {
+	Offset *int `form:"offset,omitempty" json:"offset,omitempty"`
+	Limit  *int `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
+// ListOcpiDeliveriesParams defines parameters for ListOcpiDeliveries.
+type ListOcpiDeliveriesParams struct {
+	// Status The status of the deliveries to list, defaults to `Failed`
+	Status *ListOcpiDeliveriesParamsStatus `form:"status,omitempty" json:"status,omitempty"`
+	Offset *int                            `form:"offset,omitempty" json:"offset,omitempty"`
+	Limit  *int                            `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
+// ListOcpiDeliveriesParamsStatus defines parameters for ListOcpiDeliveries.
+type ListOcpiDeliveriesParamsStatus string
+
 // ListTokensParams defines parameters for ListTokens.
 type ListTokensParams struct {
 	Offset *int `form:"offset,omitempty" json:"offset,omitempty"`
@@ -314,17 +959,35 @@ type ListTokensParams struct {
 // UploadCertificateJSONRequestBody defines body for UploadCertificate for application/json ContentType.
 type UploadCertificateJSONRequestBody = Certificate
 
+// CreateCertificateCampaignJSONRequestBody defines body for CreateCertificateCampaign for application/json ContentType.
+type CreateCertificateCampaignJSONRequestBody = CertificateCampaignRequest
+
+// SetContractJSONRequestBody defines body for SetContract for application/json ContentType.
+type SetContractJSONRequestBody = Contract
+
 // RegisterChargeStationJSONRequestBody defines body for RegisterChargeStation for application/json ContentType.
 type RegisterChargeStationJSONRequestBody = ChargeStationAuth
 
+// RecordChargeStationAuthFailureJSONRequestBody defines body for RecordChargeStationAuthFailure for application/json ContentType.
+type RecordChargeStationAuthFailureJSONRequestBody = AuthFailure
+
+// RevokeChargeStationCertificateJSONRequestBody defines body for RevokeChargeStationCertificate for application/json ContentType.
+type RevokeChargeStationCertificateJSONRequestBody = CertificateRevocationRequest
+
 // InstallChargeStationCertificatesJSONRequestBody defines body for InstallChargeStationCertificates for application/json ContentType.
 type InstallChargeStationCertificatesJSONRequestBody = ChargeStationInstallCertificates
 
+// DisconnectChargeStationJSONRequestBody defines body for DisconnectChargeStation for application/json ContentType.
+type DisconnectChargeStationJSONRequestBody = ChargeStationDisconnect
+
 // ReconfigureChargeStationJSONRequestBody defines body for ReconfigureChargeStation for application/json ContentType.
 type ReconfigureChargeStationJSONRequestBody = ChargeStationSettings
 
+// SetChargeStationTagsJSONRequestBody defines body for SetChargeStationTags for application/json ContentType.
+type SetChargeStationTagsJSONRequestBody = ChargeStationTags
+
 // TriggerChargeStationJSONRequestBody defines body for TriggerChargeStation for application/json ContentType.
-type TriggerChargeStationJSONRequestBody = ChargeStationTrigger
+type TriggerChargeStationJSONRequestBody = ChargeStationTriggerRequest
 
 // RegisterLocationJSONRequestBody defines body for RegisterLocation for application/json ContentType.
 type RegisterLocationJSONRequestBody = Location
@@ -332,41 +995,211 @@ type RegisterLocationJSONRequestBody = Location
 // RegisterPartyJSONRequestBody defines body for RegisterParty for application/json ContentType.
 type RegisterPartyJSONRequestBody = Registration
 
+// SetRootCertificateJSONRequestBody defines body for SetRootCertificate for application/json ContentType.
+type SetRootCertificateJSONRequestBody = RootCertificate
+
 // SetTokenJSONRequestBody defines body for SetToken for application/json ContentType.
 type SetTokenJSONRequestBody = Token
 
-// ServerInterface represents all server handlers.
-type ServerInterface interface {
-	// Upload a certificate
-	// (POST /certificate)
-	UploadCertificate(w http.ResponseWriter, r *http.Request)
-	// Delete a certificate
-	// (DELETE /certificate/{certificateHash})
-	DeleteCertificate(w http.ResponseWriter, r *http.Request, certificateHash string)
-	// Lookup a certificate
-	// (GET /certificate/{certificateHash})
-	LookupCertificate(w http.ResponseWriter, r *http.Request, certificateHash string)
-	// Register a new charge station
-	// (POST /cs/{csId})
-	RegisterChargeStation(w http.ResponseWriter, r *http.Request, csId string)
-	// Returns the authentication details
-	// (GET /cs/{csId}/auth)
+// AsChargeStationTrigger returns the union data inside the ChargeStationTriggerRequest as a ChargeStationTrigger
+func (t ChargeStationTriggerRequest) AsChargeStationTrigger() (ChargeStationTrigger, error) {
+	var body ChargeStationTrigger
+	err := json.Unmarshal(t.union, &body)
+	return body, err
+}
+
+// FromChargeStationTrigger overwrites any union data inside the ChargeStationTriggerRequest as the provided ChargeStationTrigger
+func (t *ChargeStationTriggerRequest) FromChargeStationTrigger(v ChargeStationTrigger) error {
+	b, err := json.Marshal(v)
+	t.union = b
+	return err
+}
+
+// MergeChargeStationTrigger performs a merge with any union data inside the ChargeStationTriggerRequest, using the provided ChargeStationTrigger
+func (t *ChargeStationTriggerRequest) MergeChargeStationTrigger(v ChargeStationTrigger) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	merged, err := runtime.JsonMerge(t.union, b)
+	t.union = merged
+	return err
+}
+
+// AsChargeStationTriggers returns the union data inside the ChargeStationTriggerRequest as a ChargeStationTriggers
+func (t ChargeStationTriggerRequest) AsChargeStationTriggers() (ChargeStationTriggers, error) {
+	var body ChargeStationTriggers
+	err := json.Unmarshal(t.union, &body)
+	return body, err
+}
+
+// FromChargeStationTriggers overwrites any union data inside the ChargeStationTriggerRequest as the provided ChargeStationTriggers
+func (t *ChargeStationTriggerRequest) FromChargeStationTriggers(v ChargeStationTriggers) error {
+	b, err := json.Marshal(v)
+	t.union = b
+	return err
+}
+
+// MergeChargeStationTriggers performs a merge with any union data inside the ChargeStationTriggerRequest, using the provided ChargeStationTriggers
+func (t *ChargeStationTriggerRequest) MergeChargeStationTriggers(v ChargeStationTriggers) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	merged, err := runtime.JsonMerge(t.union, b)
+	t.union = merged
+	return err
+}
+
+func (t ChargeStationTriggerRequest) MarshalJSON() ([]byte, error) {
+	b, err := t.union.MarshalJSON()
+	return b, err
+}
+
+func (t *ChargeStationTriggerRequest) UnmarshalJSON(b []byte) error {
+	err := t.union.UnmarshalJSON(b)
+	return err
+}
+
+// ServerInterface represents all server handlers.
+type ServerInterface interface {
+	// List auth lockouts
+	// (GET /auth/lockout)
+	ListAuthLockouts(w http.ResponseWriter, r *http.Request, params ListAuthLockoutsParams)
+	// Clear an auth lockout
+	// (DELETE /auth/lockout/{lockoutType}/{lockoutId})
+	DeleteAuthLockout(w http.ResponseWriter, r *http.Request, lockoutType AuthLockoutType, lockoutId string)
+	// Upload a certificate
+	// (POST /certificate)
+	UploadCertificate(w http.ResponseWriter, r *http.Request)
+	// List certificate campaigns
+	// (GET /certificate-campaign)
+	ListCertificateCampaigns(w http.ResponseWriter, r *http.Request, params ListCertificateCampaignsParams)
+	// Start a certificate campaign
+	// (POST /certificate-campaign)
+	CreateCertificateCampaign(w http.ResponseWriter, r *http.Request)
+	// Lookup a certificate campaign
+	// (GET /certificate-campaign/{campaignId})
+	LookupCertificateCampaign(w http.ResponseWriter, r *http.Request, campaignId string)
+	// Cancel a certificate campaign
+	// (POST /certificate-campaign/{campaignId}/cancel)
+	CancelCertificateCampaign(w http.ResponseWriter, r *http.Request, campaignId string)
+	// Delete a certificate
+	// (DELETE /certificate/{certificateHash})
+	DeleteCertificate(w http.ResponseWriter, r *http.Request, certificateHash string)
+	// Lookup a certificate
+	// (GET /certificate/{certificateHash})
+	LookupCertificate(w http.ResponseWriter, r *http.Request, certificateHash string)
+	// Delete a certificate revocation
+	// (DELETE /certificate/{certificateHash}/revocation)
+	DeleteCertificateRevocation(w http.ResponseWriter, r *http.Request, certificateHash string)
+	// Lookup a certificate revocation
+	// (GET /certificate/{certificateHash}/revocation)
+	LookupCertificateRevocation(w http.ResponseWriter, r *http.Request, certificateHash string)
+	// List connected charge stations
+	// (GET /connection)
+	ListConnectedChargeStations(w http.ResponseWriter, r *http.Request, params ListConnectedChargeStationsParams)
+	// Create/update a Plug & Charge contract
+	// (POST /contract)
+	SetContract(w http.ResponseWriter, r *http.Request)
+	// Delete a Plug & Charge contract
+	// (DELETE /contract/{emaid})
+	DeleteContract(w http.ResponseWriter, r *http.Request, emaid string)
+	// Lookup a Plug & Charge contract
+	// (GET /contract/{emaid})
+	LookupContract(w http.ResponseWriter, r *http.Request, emaid string)
+	// List charge stations
+	// (GET /cs)
+	ListChargeStations(w http.ResponseWriter, r *http.Request, params ListChargeStationsParams)
+	// Delete a charge station
+	// (DELETE /cs/{csId})
+	DeleteChargeStation(w http.ResponseWriter, r *http.Request, csId string)
+	// Lookup a charge station
+	// (GET /cs/{csId})
+	LookupChargeStation(w http.ResponseWriter, r *http.Request, csId string)
+	// Register a new charge station
+	// (POST /cs/{csId})
+	RegisterChargeStation(w http.ResponseWriter, r *http.Request, csId string)
+	// Returns the authentication details
+	// (GET /cs/{csId}/auth)
 	LookupChargeStationAuth(w http.ResponseWriter, r *http.Request, csId string)
+	// Record a failed authentication attempt
+	// (POST /cs/{csId}/auth/failure)
+	RecordChargeStationAuthFailure(w http.ResponseWriter, r *http.Request, csId string)
+	// Returns the lockout status
+	// (GET /cs/{csId}/auth/lockout)
+	LookupChargeStationAuthLockout(w http.ResponseWriter, r *http.Request, csId string, params LookupChargeStationAuthLockoutParams)
+	// Revoke a charge station client certificate
+	// (POST /cs/{csId}/certificate/revocation)
+	RevokeChargeStationCertificate(w http.ResponseWriter, r *http.Request, csId string)
 	// Install certificates on the charge station
 	// (POST /cs/{csId}/certificates)
 	InstallChargeStationCertificates(w http.ResponseWriter, r *http.Request, csId string)
+	// Returns the connection status
+	// (GET /cs/{csId}/connection)
+	LookupChargeStationConnection(w http.ResponseWriter, r *http.Request, csId string)
+	// Disconnect a charge station
+	// (POST /cs/{csId}/disconnect)
+	DisconnectChargeStation(w http.ResponseWriter, r *http.Request, csId string)
+	// List failed operations
+	// (GET /cs/{csId}/failed-operations)
+	ListFailedChargeStationOperations(w http.ResponseWriter, r *http.Request, csId string)
+	// Re-arm failed operations
+	// (POST /cs/{csId}/failed-operations/rearm)
+	RearmFailedChargeStationOperations(w http.ResponseWriter, r *http.Request, csId string)
+	// Lookup the certificates installed on a charge station
+	// (GET /cs/{csId}/installed-certificates)
+	LookupInstalledCertificates(w http.ResponseWriter, r *http.Request, csId string)
+	// Remediate the certificates installed on a charge station
+	// (POST /cs/{csId}/installed-certificates/remediate)
+	RemediateInstalledCertificates(w http.ResponseWriter, r *http.Request, csId string)
+	// List the operations log
+	// (GET /cs/{csId}/operations)
+	ListChargeStationOperations(w http.ResponseWriter, r *http.Request, csId string, params ListChargeStationOperationsParams)
 	// Reconfigure the charge station
 	// (POST /cs/{csId}/reconfigure)
 	ReconfigureChargeStation(w http.ResponseWriter, r *http.Request, csId string)
-
+	// Set the tags of a charge station
+	// (PUT /cs/{csId}/tags)
+	SetChargeStationTags(w http.ResponseWriter, r *http.Request, csId string)
+	// Trigger charge station messages
 	// (POST /cs/{csId}/trigger)
 	TriggerChargeStation(w http.ResponseWriter, r *http.Request, csId string)
+	// List expiring certificates
+	// (GET /expiring-certificates)
+	ListExpiringCertificates(w http.ResponseWriter, r *http.Request, params ListExpiringCertificatesParams)
 	// Registers a location with the CSMS
 	// (POST /location/{locationId})
 	RegisterLocation(w http.ResponseWriter, r *http.Request, locationId string)
+	// List charge stations with noncompliant certificates
+	// (GET /noncompliant-certificates)
+	ListNonCompliantCertificates(w http.ResponseWriter, r *http.Request, params ListNonCompliantCertificatesParams)
+	// List outbound OCPI deliveries
+	// (GET /ocpi/delivery)
+	ListOcpiDeliveries(w http.ResponseWriter, r *http.Request, params ListOcpiDeliveriesParams)
+	// Delete an outbound OCPI delivery
+	// (DELETE /ocpi/delivery/{deliveryId})
+	DeleteOcpiDelivery(w http.ResponseWriter, r *http.Request, deliveryId string)
+	// Lookup an outbound OCPI delivery
+	// (GET /ocpi/delivery/{deliveryId})
+	LookupOcpiDelivery(w http.ResponseWriter, r *http.Request, deliveryId string)
+	// Replay an outbound OCPI delivery
+	// (POST /ocpi/delivery/{deliveryId}/replay)
+	ReplayOcpiDelivery(w http.ResponseWriter, r *http.Request, deliveryId string)
 	// Registers an OCPI party with the CSMS
 	// (POST /register)
 	RegisterParty(w http.ResponseWriter, r *http.Request)
+	// List the expected root certificates
+	// (GET /root-certificate)
+	ListRootCertificates(w http.ResponseWriter, r *http.Request)
+	// Set an expected root certificate
+	// (POST /root-certificate)
+	SetRootCertificate(w http.ResponseWriter, r *http.Request)
+	// Delete an expected root certificate
+	// (DELETE /root-certificate/{certificateId})
+	DeleteRootCertificate(w http.ResponseWriter, r *http.Request, certificateId string)
 	// List authorization tokens
 	// (GET /token)
 	ListTokens(w http.ResponseWriter, r *http.Request, params ListTokensParams)
@@ -385,14 +1218,1105 @@ type ServerInterfaceWrapper struct {
 	ErrorHandlerFunc   func(w http.ResponseWriter, r *http.Request, err error)
 }
 
-type MiddlewareFunc func(http.Handler) http.Handler
+type MiddlewareFunc func(http.Handler) http.Handler
+
+// ListAuthLockouts operation middleware
+func (siw *ServerInterfaceWrapper) ListAuthLockouts(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{"read-only"})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"read-only"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ListAuthLockoutsParams
+
+	// ------------- Optional query parameter "offset" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "offset", r.URL.Query(), &params.Offset)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "offset", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListAuthLockouts(w, r, params)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// DeleteAuthLockout operation middleware
+func (siw *ServerInterfaceWrapper) DeleteAuthLockout(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "lockoutType" -------------
+	var lockoutType AuthLockoutType
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "lockoutType", runtime.ParamLocationPath, chi.URLParam(r, "lockoutType"), &lockoutType)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "lockoutType", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "lockoutId" -------------
+	var lockoutId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "lockoutId", runtime.ParamLocationPath, chi.URLParam(r, "lockoutId"), &lockoutId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "lockoutId", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{"operator"})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"operator"})
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteAuthLockout(w, r, lockoutType, lockoutId)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// UploadCertificate operation middleware
+func (siw *ServerInterfaceWrapper) UploadCertificate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{"admin"})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"admin"})
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.UploadCertificate(w, r)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// ListCertificateCampaigns operation middleware
+func (siw *ServerInterfaceWrapper) ListCertificateCampaigns(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{"read-only"})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"read-only"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ListCertificateCampaignsParams
+
+	// ------------- Optional query parameter "status" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "status", r.URL.Query(), &params.Status)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "status", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "offset" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "offset", r.URL.Query(), &params.Offset)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "offset", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListCertificateCampaigns(w, r, params)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// CreateCertificateCampaign operation middleware
+func (siw *ServerInterfaceWrapper) CreateCertificateCampaign(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{"admin"})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"admin"})
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.CreateCertificateCampaign(w, r)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// LookupCertificateCampaign operation middleware
+func (siw *ServerInterfaceWrapper) LookupCertificateCampaign(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "campaignId" -------------
+	var campaignId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "campaignId", runtime.ParamLocationPath, chi.URLParam(r, "campaignId"), &campaignId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "campaignId", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{"read-only"})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"read-only"})
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.LookupCertificateCampaign(w, r, campaignId)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// CancelCertificateCampaign operation middleware
+func (siw *ServerInterfaceWrapper) CancelCertificateCampaign(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "campaignId" -------------
+	var campaignId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "campaignId", runtime.ParamLocationPath, chi.URLParam(r, "campaignId"), &campaignId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "campaignId", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{"admin"})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"admin"})
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.CancelCertificateCampaign(w, r, campaignId)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// DeleteCertificate operation middleware
+func (siw *ServerInterfaceWrapper) DeleteCertificate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "certificateHash" -------------
+	var certificateHash string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "certificateHash", runtime.ParamLocationPath, chi.URLParam(r, "certificateHash"), &certificateHash)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "certificateHash", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{"admin"})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"admin"})
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteCertificate(w, r, certificateHash)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// LookupCertificate operation middleware
+func (siw *ServerInterfaceWrapper) LookupCertificate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "certificateHash" -------------
+	var certificateHash string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "certificateHash", runtime.ParamLocationPath, chi.URLParam(r, "certificateHash"), &certificateHash)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "certificateHash", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{"read-only"})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"read-only"})
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.LookupCertificate(w, r, certificateHash)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// DeleteCertificateRevocation operation middleware
+func (siw *ServerInterfaceWrapper) DeleteCertificateRevocation(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "certificateHash" -------------
+	var certificateHash string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "certificateHash", runtime.ParamLocationPath, chi.URLParam(r, "certificateHash"), &certificateHash)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "certificateHash", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{"admin"})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"admin"})
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteCertificateRevocation(w, r, certificateHash)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// LookupCertificateRevocation operation middleware
+func (siw *ServerInterfaceWrapper) LookupCertificateRevocation(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "certificateHash" -------------
+	var certificateHash string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "certificateHash", runtime.ParamLocationPath, chi.URLParam(r, "certificateHash"), &certificateHash)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "certificateHash", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{"read-only"})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"read-only"})
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.LookupCertificateRevocation(w, r, certificateHash)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// ListConnectedChargeStations operation middleware
+func (siw *ServerInterfaceWrapper) ListConnectedChargeStations(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{"read-only"})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"read-only"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ListConnectedChargeStationsParams
+
+	// ------------- Optional query parameter "offset" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "offset", r.URL.Query(), &params.Offset)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "offset", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListConnectedChargeStations(w, r, params)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// SetContract operation middleware
+func (siw *ServerInterfaceWrapper) SetContract(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{"admin"})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"admin"})
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.SetContract(w, r)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// DeleteContract operation middleware
+func (siw *ServerInterfaceWrapper) DeleteContract(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "emaid" -------------
+	var emaid string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "emaid", runtime.ParamLocationPath, chi.URLParam(r, "emaid"), &emaid)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "emaid", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{"admin"})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"admin"})
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteContract(w, r, emaid)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// LookupContract operation middleware
+func (siw *ServerInterfaceWrapper) LookupContract(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "emaid" -------------
+	var emaid string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "emaid", runtime.ParamLocationPath, chi.URLParam(r, "emaid"), &emaid)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "emaid", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{"read-only"})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"read-only"})
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.LookupContract(w, r, emaid)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// ListChargeStations operation middleware
+func (siw *ServerInterfaceWrapper) ListChargeStations(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{"read-only"})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"read-only"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ListChargeStationsParams
+
+	// ------------- Optional query parameter "vendor" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "vendor", r.URL.Query(), &params.Vendor)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "vendor", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "model" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "model", r.URL.Query(), &params.Model)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "model", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "firmwareVersion" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "firmwareVersion", r.URL.Query(), &params.FirmwareVersion)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "firmwareVersion", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "tag" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "tag", r.URL.Query(), &params.Tag)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "tag", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "offset" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "offset", r.URL.Query(), &params.Offset)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "offset", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListChargeStations(w, r, params)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// DeleteChargeStation operation middleware
+func (siw *ServerInterfaceWrapper) DeleteChargeStation(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "csId" -------------
+	var csId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "csId", runtime.ParamLocationPath, chi.URLParam(r, "csId"), &csId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "csId", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{"admin"})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"admin"})
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteChargeStation(w, r, csId)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// LookupChargeStation operation middleware
+func (siw *ServerInterfaceWrapper) LookupChargeStation(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "csId" -------------
+	var csId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "csId", runtime.ParamLocationPath, chi.URLParam(r, "csId"), &csId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "csId", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{"read-only"})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"read-only"})
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.LookupChargeStation(w, r, csId)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// RegisterChargeStation operation middleware
+func (siw *ServerInterfaceWrapper) RegisterChargeStation(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "csId" -------------
+	var csId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "csId", runtime.ParamLocationPath, chi.URLParam(r, "csId"), &csId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "csId", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{"admin"})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"admin"})
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.RegisterChargeStation(w, r, csId)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// LookupChargeStationAuth operation middleware
+func (siw *ServerInterfaceWrapper) LookupChargeStationAuth(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "csId" -------------
+	var csId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "csId", runtime.ParamLocationPath, chi.URLParam(r, "csId"), &csId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "csId", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{"gateway"})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"gateway"})
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.LookupChargeStationAuth(w, r, csId)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// RecordChargeStationAuthFailure operation middleware
+func (siw *ServerInterfaceWrapper) RecordChargeStationAuthFailure(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "csId" -------------
+	var csId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "csId", runtime.ParamLocationPath, chi.URLParam(r, "csId"), &csId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "csId", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{"gateway"})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"gateway"})
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.RecordChargeStationAuthFailure(w, r, csId)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// LookupChargeStationAuthLockout operation middleware
+func (siw *ServerInterfaceWrapper) LookupChargeStationAuthLockout(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "csId" -------------
+	var csId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "csId", runtime.ParamLocationPath, chi.URLParam(r, "csId"), &csId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "csId", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{"read-only"})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"read-only"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params LookupChargeStationAuthLockoutParams
+
+	// ------------- Optional query parameter "remoteAddr" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "remoteAddr", r.URL.Query(), &params.RemoteAddr)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "remoteAddr", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.LookupChargeStationAuthLockout(w, r, csId, params)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// RevokeChargeStationCertificate operation middleware
+func (siw *ServerInterfaceWrapper) RevokeChargeStationCertificate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "csId" -------------
+	var csId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "csId", runtime.ParamLocationPath, chi.URLParam(r, "csId"), &csId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "csId", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{"admin"})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"admin"})
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.RevokeChargeStationCertificate(w, r, csId)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// InstallChargeStationCertificates operation middleware
+func (siw *ServerInterfaceWrapper) InstallChargeStationCertificates(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "csId" -------------
+	var csId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "csId", runtime.ParamLocationPath, chi.URLParam(r, "csId"), &csId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "csId", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{"operator"})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"operator"})
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.InstallChargeStationCertificates(w, r, csId)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// LookupChargeStationConnection operation middleware
+func (siw *ServerInterfaceWrapper) LookupChargeStationConnection(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "csId" -------------
+	var csId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "csId", runtime.ParamLocationPath, chi.URLParam(r, "csId"), &csId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "csId", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{"read-only"})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"read-only"})
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.LookupChargeStationConnection(w, r, csId)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// DisconnectChargeStation operation middleware
+func (siw *ServerInterfaceWrapper) DisconnectChargeStation(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "csId" -------------
+	var csId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "csId", runtime.ParamLocationPath, chi.URLParam(r, "csId"), &csId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "csId", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{"operator"})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"operator"})
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DisconnectChargeStation(w, r, csId)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// ListFailedChargeStationOperations operation middleware
+func (siw *ServerInterfaceWrapper) ListFailedChargeStationOperations(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "csId" -------------
+	var csId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "csId", runtime.ParamLocationPath, chi.URLParam(r, "csId"), &csId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "csId", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{"read-only"})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"read-only"})
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListFailedChargeStationOperations(w, r, csId)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// RearmFailedChargeStationOperations operation middleware
+func (siw *ServerInterfaceWrapper) RearmFailedChargeStationOperations(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "csId" -------------
+	var csId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "csId", runtime.ParamLocationPath, chi.URLParam(r, "csId"), &csId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "csId", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{"operator"})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"operator"})
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.RearmFailedChargeStationOperations(w, r, csId)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// LookupInstalledCertificates operation middleware
+func (siw *ServerInterfaceWrapper) LookupInstalledCertificates(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "csId" -------------
+	var csId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "csId", runtime.ParamLocationPath, chi.URLParam(r, "csId"), &csId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "csId", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{"read-only"})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"read-only"})
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.LookupInstalledCertificates(w, r, csId)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// RemediateInstalledCertificates operation middleware
+func (siw *ServerInterfaceWrapper) RemediateInstalledCertificates(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "csId" -------------
+	var csId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "csId", runtime.ParamLocationPath, chi.URLParam(r, "csId"), &csId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "csId", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{"operator"})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"operator"})
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.RemediateInstalledCertificates(w, r, csId)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// ListChargeStationOperations operation middleware
+func (siw *ServerInterfaceWrapper) ListChargeStationOperations(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "csId" -------------
+	var csId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "csId", runtime.ParamLocationPath, chi.URLParam(r, "csId"), &csId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "csId", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{"operator"})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"operator"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ListChargeStationOperationsParams
+
+	// ------------- Optional query parameter "offset" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "offset", r.URL.Query(), &params.Offset)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "offset", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListChargeStationOperations(w, r, csId, params)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// ReconfigureChargeStation operation middleware
+func (siw *ServerInterfaceWrapper) ReconfigureChargeStation(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "csId" -------------
+	var csId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "csId", runtime.ParamLocationPath, chi.URLParam(r, "csId"), &csId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "csId", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{"operator"})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"operator"})
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ReconfigureChargeStation(w, r, csId)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// SetChargeStationTags operation middleware
+func (siw *ServerInterfaceWrapper) SetChargeStationTags(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "csId" -------------
+	var csId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "csId", runtime.ParamLocationPath, chi.URLParam(r, "csId"), &csId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "csId", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{"admin"})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"admin"})
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.SetChargeStationTags(w, r, csId)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// TriggerChargeStation operation middleware
+func (siw *ServerInterfaceWrapper) TriggerChargeStation(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "csId" -------------
+	var csId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "csId", runtime.ParamLocationPath, chi.URLParam(r, "csId"), &csId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "csId", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{"operator"})
 
-// UploadCertificate operation middleware
-func (siw *ServerInterfaceWrapper) UploadCertificate(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"operator"})
 
 	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.UploadCertificate(w, r)
+		siw.Handler.TriggerChargeStation(w, r, csId)
 	})
 
 	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
@@ -402,23 +2326,45 @@ func (siw *ServerInterfaceWrapper) UploadCertificate(w http.ResponseWriter, r *h
 	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-// DeleteCertificate operation middleware
-func (siw *ServerInterfaceWrapper) DeleteCertificate(w http.ResponseWriter, r *http.Request) {
+// ListExpiringCertificates operation middleware
+func (siw *ServerInterfaceWrapper) ListExpiringCertificates(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	var err error
 
-	// ------------- Path parameter "certificateHash" -------------
-	var certificateHash string
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{"read-only"})
 
-	err = runtime.BindStyledParameterWithLocation("simple", false, "certificateHash", runtime.ParamLocationPath, chi.URLParam(r, "certificateHash"), &certificateHash)
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"read-only"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ListExpiringCertificatesParams
+
+	// ------------- Optional query parameter "days" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "days", r.URL.Query(), &params.Days)
 	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "certificateHash", Err: err})
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "days", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "offset" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "offset", r.URL.Query(), &params.Offset)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "offset", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
 		return
 	}
 
 	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.DeleteCertificate(w, r, certificateHash)
+		siw.Handler.ListExpiringCertificates(w, r, params)
 	})
 
 	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
@@ -428,23 +2374,27 @@ func (siw *ServerInterfaceWrapper) DeleteCertificate(w http.ResponseWriter, r *h
 	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-// LookupCertificate operation middleware
-func (siw *ServerInterfaceWrapper) LookupCertificate(w http.ResponseWriter, r *http.Request) {
+// RegisterLocation operation middleware
+func (siw *ServerInterfaceWrapper) RegisterLocation(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	var err error
 
-	// ------------- Path parameter "certificateHash" -------------
-	var certificateHash string
+	// ------------- Path parameter "locationId" -------------
+	var locationId string
 
-	err = runtime.BindStyledParameterWithLocation("simple", false, "certificateHash", runtime.ParamLocationPath, chi.URLParam(r, "certificateHash"), &certificateHash)
+	err = runtime.BindStyledParameterWithLocation("simple", false, "locationId", runtime.ParamLocationPath, chi.URLParam(r, "locationId"), &locationId)
 	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "certificateHash", Err: err})
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "locationId", Err: err})
 		return
 	}
 
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{"admin"})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"admin"})
+
 	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.LookupCertificate(w, r, certificateHash)
+		siw.Handler.RegisterLocation(w, r, locationId)
 	})
 
 	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
@@ -454,23 +2404,37 @@ func (siw *ServerInterfaceWrapper) LookupCertificate(w http.ResponseWriter, r *h
 	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-// RegisterChargeStation operation middleware
-func (siw *ServerInterfaceWrapper) RegisterChargeStation(w http.ResponseWriter, r *http.Request) {
+// ListNonCompliantCertificates operation middleware
+func (siw *ServerInterfaceWrapper) ListNonCompliantCertificates(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	var err error
 
-	// ------------- Path parameter "csId" -------------
-	var csId string
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{"read-only"})
 
-	err = runtime.BindStyledParameterWithLocation("simple", false, "csId", runtime.ParamLocationPath, chi.URLParam(r, "csId"), &csId)
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"read-only"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ListNonCompliantCertificatesParams
+
+	// ------------- Optional query parameter "offset" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "offset", r.URL.Query(), &params.Offset)
 	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "csId", Err: err})
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "offset", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
 		return
 	}
 
 	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.RegisterChargeStation(w, r, csId)
+		siw.Handler.ListNonCompliantCertificates(w, r, params)
 	})
 
 	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
@@ -480,23 +2444,45 @@ func (siw *ServerInterfaceWrapper) RegisterChargeStation(w http.ResponseWriter,
 	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-// LookupChargeStationAuth operation middleware
-func (siw *ServerInterfaceWrapper) LookupChargeStationAuth(w http.ResponseWriter, r *http.Request) {
+// ListOcpiDeliveries operation middleware
+func (siw *ServerInterfaceWrapper) ListOcpiDeliveries(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	var err error
 
-	// ------------- Path parameter "csId" -------------
-	var csId string
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{"read-only"})
 
-	err = runtime.BindStyledParameterWithLocation("simple", false, "csId", runtime.ParamLocationPath, chi.URLParam(r, "csId"), &csId)
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"read-only"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ListOcpiDeliveriesParams
+
+	// ------------- Optional query parameter "status" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "status", r.URL.Query(), &params.Status)
 	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "csId", Err: err})
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "status", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "offset" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "offset", r.URL.Query(), &params.Offset)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "offset", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
 		return
 	}
 
 	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.LookupChargeStationAuth(w, r, csId)
+		siw.Handler.ListOcpiDeliveries(w, r, params)
 	})
 
 	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
@@ -506,23 +2492,27 @@ func (siw *ServerInterfaceWrapper) LookupChargeStationAuth(w http.ResponseWriter
 	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-// InstallChargeStationCertificates operation middleware
-func (siw *ServerInterfaceWrapper) InstallChargeStationCertificates(w http.ResponseWriter, r *http.Request) {
+// DeleteOcpiDelivery operation middleware
+func (siw *ServerInterfaceWrapper) DeleteOcpiDelivery(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	var err error
 
-	// ------------- Path parameter "csId" -------------
-	var csId string
+	// ------------- Path parameter "deliveryId" -------------
+	var deliveryId string
 
-	err = runtime.BindStyledParameterWithLocation("simple", false, "csId", runtime.ParamLocationPath, chi.URLParam(r, "csId"), &csId)
+	err = runtime.BindStyledParameterWithLocation("simple", false, "deliveryId", runtime.ParamLocationPath, chi.URLParam(r, "deliveryId"), &deliveryId)
 	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "csId", Err: err})
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "deliveryId", Err: err})
 		return
 	}
 
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{"operator"})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"operator"})
+
 	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.InstallChargeStationCertificates(w, r, csId)
+		siw.Handler.DeleteOcpiDelivery(w, r, deliveryId)
 	})
 
 	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
@@ -532,23 +2522,27 @@ func (siw *ServerInterfaceWrapper) InstallChargeStationCertificates(w http.Respo
 	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-// ReconfigureChargeStation operation middleware
-func (siw *ServerInterfaceWrapper) ReconfigureChargeStation(w http.ResponseWriter, r *http.Request) {
+// LookupOcpiDelivery operation middleware
+func (siw *ServerInterfaceWrapper) LookupOcpiDelivery(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	var err error
 
-	// ------------- Path parameter "csId" -------------
-	var csId string
+	// ------------- Path parameter "deliveryId" -------------
+	var deliveryId string
 
-	err = runtime.BindStyledParameterWithLocation("simple", false, "csId", runtime.ParamLocationPath, chi.URLParam(r, "csId"), &csId)
+	err = runtime.BindStyledParameterWithLocation("simple", false, "deliveryId", runtime.ParamLocationPath, chi.URLParam(r, "deliveryId"), &deliveryId)
 	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "csId", Err: err})
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "deliveryId", Err: err})
 		return
 	}
 
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{"read-only"})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"read-only"})
+
 	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.ReconfigureChargeStation(w, r, csId)
+		siw.Handler.LookupOcpiDelivery(w, r, deliveryId)
 	})
 
 	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
@@ -558,23 +2552,46 @@ func (siw *ServerInterfaceWrapper) ReconfigureChargeStation(w http.ResponseWrite
 	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-// TriggerChargeStation operation middleware
-func (siw *ServerInterfaceWrapper) TriggerChargeStation(w http.ResponseWriter, r *http.Request) {
+// ReplayOcpiDelivery operation middleware
+func (siw *ServerInterfaceWrapper) ReplayOcpiDelivery(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	var err error
 
-	// ------------- Path parameter "csId" -------------
-	var csId string
+	// ------------- Path parameter "deliveryId" -------------
+	var deliveryId string
 
-	err = runtime.BindStyledParameterWithLocation("simple", false, "csId", runtime.ParamLocationPath, chi.URLParam(r, "csId"), &csId)
+	err = runtime.BindStyledParameterWithLocation("simple", false, "deliveryId", runtime.ParamLocationPath, chi.URLParam(r, "deliveryId"), &deliveryId)
 	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "csId", Err: err})
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "deliveryId", Err: err})
 		return
 	}
 
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{"operator"})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"operator"})
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ReplayOcpiDelivery(w, r, deliveryId)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// RegisterParty operation middleware
+func (siw *ServerInterfaceWrapper) RegisterParty(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{"admin"})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"admin"})
+
 	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.TriggerChargeStation(w, r, csId)
+		siw.Handler.RegisterParty(w, r)
 	})
 
 	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
@@ -584,23 +2601,35 @@ func (siw *ServerInterfaceWrapper) TriggerChargeStation(w http.ResponseWriter, r
 	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-// RegisterLocation operation middleware
-func (siw *ServerInterfaceWrapper) RegisterLocation(w http.ResponseWriter, r *http.Request) {
+// ListRootCertificates operation middleware
+func (siw *ServerInterfaceWrapper) ListRootCertificates(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	var err error
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{"read-only"})
 
-	// ------------- Path parameter "locationId" -------------
-	var locationId string
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"read-only"})
 
-	err = runtime.BindStyledParameterWithLocation("simple", false, "locationId", runtime.ParamLocationPath, chi.URLParam(r, "locationId"), &locationId)
-	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "locationId", Err: err})
-		return
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListRootCertificates(w, r)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
 	}
 
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// SetRootCertificate operation middleware
+func (siw *ServerInterfaceWrapper) SetRootCertificate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{"admin"})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"admin"})
+
 	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.RegisterLocation(w, r, locationId)
+		siw.Handler.SetRootCertificate(w, r)
 	})
 
 	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
@@ -610,12 +2639,27 @@ func (siw *ServerInterfaceWrapper) RegisterLocation(w http.ResponseWriter, r *ht
 	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-// RegisterParty operation middleware
-func (siw *ServerInterfaceWrapper) RegisterParty(w http.ResponseWriter, r *http.Request) {
+// DeleteRootCertificate operation middleware
+func (siw *ServerInterfaceWrapper) DeleteRootCertificate(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
+	var err error
+
+	// ------------- Path parameter "certificateId" -------------
+	var certificateId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "certificateId", runtime.ParamLocationPath, chi.URLParam(r, "certificateId"), &certificateId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "certificateId", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{"admin"})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"admin"})
+
 	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.RegisterParty(w, r)
+		siw.Handler.DeleteRootCertificate(w, r, certificateId)
 	})
 
 	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
@@ -631,6 +2675,10 @@ func (siw *ServerInterfaceWrapper) ListTokens(w http.ResponseWriter, r *http.Req
 
 	var err error
 
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{"read-only"})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"read-only"})
+
 	// Parameter object where we will unmarshal all parameters from the context
 	var params ListTokensParams
 
@@ -665,6 +2713,10 @@ func (siw *ServerInterfaceWrapper) ListTokens(w http.ResponseWriter, r *http.Req
 func (siw *ServerInterfaceWrapper) SetToken(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{"operator"})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"operator"})
+
 	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		siw.Handler.SetToken(w, r)
 	})
@@ -691,6 +2743,10 @@ func (siw *ServerInterfaceWrapper) LookupToken(w http.ResponseWriter, r *http.Re
 		return
 	}
 
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{"read-only"})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"read-only"})
+
 	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		siw.Handler.LookupToken(w, r, tokenUid)
 	})
@@ -815,36 +2871,141 @@ func HandlerWithOptions(si ServerInterface, options ChiServerOptions) http.Handl
 		ErrorHandlerFunc:   options.ErrorHandlerFunc,
 	}
 
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/auth/lockout", wrapper.ListAuthLockouts)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/auth/lockout/{lockoutType}/{lockoutId}", wrapper.DeleteAuthLockout)
+	})
 	r.Group(func(r chi.Router) {
 		r.Post(options.BaseURL+"/certificate", wrapper.UploadCertificate)
 	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/certificate-campaign", wrapper.ListCertificateCampaigns)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/certificate-campaign", wrapper.CreateCertificateCampaign)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/certificate-campaign/{campaignId}", wrapper.LookupCertificateCampaign)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/certificate-campaign/{campaignId}/cancel", wrapper.CancelCertificateCampaign)
+	})
 	r.Group(func(r chi.Router) {
 		r.Delete(options.BaseURL+"/certificate/{certificateHash}", wrapper.DeleteCertificate)
 	})
 	r.Group(func(r chi.Router) {
 		r.Get(options.BaseURL+"/certificate/{certificateHash}", wrapper.LookupCertificate)
 	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/certificate/{certificateHash}/revocation", wrapper.DeleteCertificateRevocation)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/certificate/{certificateHash}/revocation", wrapper.LookupCertificateRevocation)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/connection", wrapper.ListConnectedChargeStations)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/contract", wrapper.SetContract)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/contract/{emaid}", wrapper.DeleteContract)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/contract/{emaid}", wrapper.LookupContract)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/cs", wrapper.ListChargeStations)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/cs/{csId}", wrapper.DeleteChargeStation)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/cs/{csId}", wrapper.LookupChargeStation)
+	})
 	r.Group(func(r chi.Router) {
 		r.Post(options.BaseURL+"/cs/{csId}", wrapper.RegisterChargeStation)
 	})
 	r.Group(func(r chi.Router) {
 		r.Get(options.BaseURL+"/cs/{csId}/auth", wrapper.LookupChargeStationAuth)
 	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/cs/{csId}/auth/failure", wrapper.RecordChargeStationAuthFailure)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/cs/{csId}/auth/lockout", wrapper.LookupChargeStationAuthLockout)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/cs/{csId}/certificate/revocation", wrapper.RevokeChargeStationCertificate)
+	})
 	r.Group(func(r chi.Router) {
 		r.Post(options.BaseURL+"/cs/{csId}/certificates", wrapper.InstallChargeStationCertificates)
 	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/cs/{csId}/connection", wrapper.LookupChargeStationConnection)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/cs/{csId}/disconnect", wrapper.DisconnectChargeStation)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/cs/{csId}/failed-operations", wrapper.ListFailedChargeStationOperations)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/cs/{csId}/failed-operations/rearm", wrapper.RearmFailedChargeStationOperations)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/cs/{csId}/installed-certificates", wrapper.LookupInstalledCertificates)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/cs/{csId}/installed-certificates/remediate", wrapper.RemediateInstalledCertificates)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/cs/{csId}/operations", wrapper.ListChargeStationOperations)
+	})
 	r.Group(func(r chi.Router) {
 		r.Post(options.BaseURL+"/cs/{csId}/reconfigure", wrapper.ReconfigureChargeStation)
 	})
+	r.Group(func(r chi.Router) {
+		r.Put(options.BaseURL+"/cs/{csId}/tags", wrapper.SetChargeStationTags)
+	})
 	r.Group(func(r chi.Router) {
 		r.Post(options.BaseURL+"/cs/{csId}/trigger", wrapper.TriggerChargeStation)
 	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/expiring-certificates", wrapper.ListExpiringCertificates)
+	})
 	r.Group(func(r chi.Router) {
 		r.Post(options.BaseURL+"/location/{locationId}", wrapper.RegisterLocation)
 	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/noncompliant-certificates", wrapper.ListNonCompliantCertificates)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/ocpi/delivery", wrapper.ListOcpiDeliveries)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/ocpi/delivery/{deliveryId}", wrapper.DeleteOcpiDelivery)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/ocpi/delivery/{deliveryId}", wrapper.LookupOcpiDelivery)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/ocpi/delivery/{deliveryId}/replay", wrapper.ReplayOcpiDelivery)
+	})
 	r.Group(func(r chi.Router) {
 		r.Post(options.BaseURL+"/register", wrapper.RegisterParty)
 	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/root-certificate", wrapper.ListRootCertificates)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/root-certificate", wrapper.SetRootCertificate)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/root-certificate/{certificateId}", wrapper.DeleteRootCertificate)
+	})
 	r.Group(func(r chi.Router) {
 		r.Get(options.BaseURL+"/token", wrapper.ListTokens)
 	})
@@ -861,68 +3022,176 @@ func HandlerWithOptions(si ServerInterface, options ChiServerOptions) http.Handl
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/+xbX3MaO5b/KqrefUi22gbbiWvjl1kCxGZiAwU4t2YvKSy6D6Bxt9RXUtthXP7uW0dq",
-	"QTctjOfOZCebuy926/+R9Dt/dXgKIpFmggPXKrh4ClS0gpSazzZIzRYsohqwGIOKJMs0Ezy4CFokShhw",
-	"TaJSrzDIpMiwAswM0UszTFZAht0bAjwSMcTlicgj0yvC4TFhHBSRkCU0gpjM1+RuOuV3QRjodQbBRaC0",
-	"ZHwZPD+HgYTfciYhDi5+rSz8ddNZzP8KkQ6ew6C9onIJY02RllauV3Xy2oJziLBAYtCUJYoshCSURGYs",
-	"UXZwbc9zquD83fiqdfr+fEiVehQy9m/e9nT7D8n4qnV0+v6crKhaEbEgegU7i5HMTRgGKf12DXyJpJ+/",
-	"q51HGDD+QBMW3yqQnKbQShLxCB5KeguiQBMtiJY54KKcUE6K4SQvxpNHliSEC00yCQ948R7youLM+HJ7",
-	"Q3MhEqAcSVIQ5ZLp9VCKBUv2QMJ1IpnthZTlCszh15e8IP9B7pp35Ijk3IyEmGhJucqE1BZGc6pYRGiu",
-	"V9j3BPtOrse+ttNKWx3fU77dFuMaliBryNvd40H09bjSNElKzKb2HYxGVJToUXg2zI4ngnuO55jgyMoQ",
-	"c49znI7rKcdrr98jVWseraTgIlfJ+njKX+JsU2Ya0t9F979QZoQB7jffR7ZpC0kMC5on2tA8BB5bcAPP",
-	"U7zuVhRBpgEZcgR4v+bT9fvqWdNWPG1m+HJ6GYTBzQD/fArCoD2+GXsG7sDMtIYH5VxRQaWk65eEpDqM",
-	"0zFoZGxzWjSOGdbRZFi5u/op3sOaMGUwZqRIIdaUneyYfBKSDNrDITk9bh6fbPuplciTmKzogxFJZCFQ",
-	"fjG+JBnVGiS/mPJp3myeRRv1ZYrQsLUPVDI6T8BWFmzgetolIiPloiSPAQWeyOyOSt0MRHlUkER5TOBB",
-	"AWHxlCvIqKTa4ktByo4ikQiu7Epu9ZcX2vSqr0O1lmyeo8jBWyEvL5fSbyzNU5IYfWAkpTnTk+NzPPz3",
-	"zaZhcBppkMpyc0l7nDSbTQ9Oq3fpbn+fDnwZOxPJligs6xCxDbUZCY28ylVvJ3L881EI3RcFkO2YsWHd",
-	"3Uq25F9OL9sVcwUrDaWMLwtaPR1EOmcc4raX2fYxaEGpl6+smhRmH9UNLoRMqS7vbzxof+5OUDC0Pl53",
-	"vSKFGbVeq07ptxlNM5B0CeW5UXednXpUmR3yIBL9+hGZeAQ52xVqrfbsZDa8ao27QYiFs02h0/ZuARkg",
-	"ptZScpO0r1qdrhGM7avW4M89HD246Y4nvfasVS58LBfa5UKnXOiWC5/Khcty4apcqCz653Lhc7lwHYTB",
-	"5cfJrNUuPjr40eu2Z+fNs+aH2elMMb5MYHZyvlOvVxL2Vpszr1efv3PVpycfzmeTk53irD24+TioVp7u",
-	"FH19zlo7ZdxEv3vTmr2fnTbd9/nsrPT9fvN90iw1nDTLLe/KLe9sy7DVnwwuR63h1ezjYDIZ3Mxuh9Xq",
-	"yWA46wx+6QdhMOmOr1uz0eZrHITBbf9zH1sPsmKBYsMnO1xRRXwFzSVM+ni4+6Cgzr6R4+yqUfTvEhbB",
-	"RfBvja2v1SgcrcZWGNT0dRigvplZ9uZ5kqC2CC7QTvewUM481v0tZ7/lkKwJi4Gj9AKrjLtfxl1jMTFr",
-	"NraHA0WyhGo8LPKGctRx+Rz3RrWQmyb19vig85Wbcy5sq7B8Jr6DvARxLQopXTvPhGqm8xi88i0RfLmv",
-	"dYekzTzlUT5qyqTUHN6qikpE5PcAaRxLUMpLc8T02t8ghIwZd/b0S4gpn5gZmXMt981q2mZoLXs7IMBe",
-	"j1UD+udwHxY3sEU75lWYzai8Z3xZ1x/Xg/7l7GYwGYx+af3FiIXR517/cnbZGrUuu6WK6wHqxkF/1hn1",
-	"vnRt50F/Np6MukZr3vY73dHlaHDb77jBX8NXEabXsz2KNRPoumwO9cBkO1B06CiwsL2/nduqQqJEkQ+2",
-	"I1gypeUe6HZgYXwiZHTGmWbGyvWGN7DLoD3sEVmaEV3xyNJcRfph/8kZ/JXp8DhA6WPSc42mjLZqSuU9",
-	"xIQqcjfqXvbGk+6o27mzUQnsqsU98I0PS21Qg2gx5XMguTLfhEZILbYS4HEmGNeK0AfB0CmzPgZAfHi/",
-	"LxM45XfDbr/T61/66RM8WVeJdIRhx7uGiDLWeACpmODqLnQ1p8end8YH2JYbkQQjvmmi7qZ8sydryjue",
-	"KYhBT3Rzcn4PFGn0X5olvxRxiUSa5txY0XxpXWykHm7GQ/KmPep2uv1Jr3U9nk0Gn7v9Wcuoh0OhqVwm",
-	"/uVvR9cOMGYFdzqbazQ3kknxwND7N4prfDO2500jjddi3Useg3RTbWZxuAvCrWWbS3ZQodkD8/HdeA8D",
-	"XE0mQ7LRgFWmASmF9O/fNDl+/H0BC1JuOLSxYjrfziZ+kLS4iZYJyf5mWcWeTS2IQ6MV3BTycSfeyGMX",
-	"iVpRTYx7ixdlkIfjEGhMObYpx1quf2n9BS2/1vX14JduZ/s1G3z6dN3rd42N+aU78sI+ElyjD9zbE451",
-	"7aTXIW/gptXrvCVUKREx43hvsG8pfWPKnqBB4aoLqd4aqW2iFcFF8ObX1tF/06O/fX06fX775uhPb7cV",
-	"Z9WK5tGHr08f6nVv/xSEe1V823vYdl+mA0Gt4liCKZXjOSOXVRn2NAxSxkul2oJLKfLMf4hMERYT00Gh",
-	"Ty/yLNnergmhpfQeiH4UREiSCgmu6VHIe2RfwaFK0Nm5z+NVKvfFE3rFvvA6KF+HJBVKu01rlkI9FFV0",
-	"JZlErzZ2cdTRp16HRFTGoQl6c0DJTSVL1hvx5LuNhPJlTpew/zoyCQuQEmLi+jp564KaVJHeeEDOzz4c",
-	"nWw7FUbB33VVCVX6NosRv35isKnQcJGQMXmkiuAgkttR5A1bciHtsUQSqIaGbXpbFqJYcYTHG+wzpfYx",
-	"nWlE0BwE5lllt2cvRFY9aq0sZMoSpTO7GrRnt+Muupat4dB9DiZX5j+iwCtMvM4WLpUbh6sQEix+BZbN",
-	"O4sPykQjQ9mZbCffo8oDUzlN+nk6hz1axfZoSKCxDUqavg3nEUbO5tngn/It/A8/tZXkz/ayQxehts5g",
-	"SfZumNftPCxpi7omejZPWQtReNeaRjY6llKWBBdBSuEBjjTQ9L/0SuTLlUZBoo4jkQbODwluaPcLEOxU",
-	"D2z2OMpnmpDWsGefJTQYLbCR93Y02hkhgW9Fb/s4pFycGtkXzUg0LRIWAbfBgWL9VoYbJKfHTQNTppMt",
-	"VSbcHwaFjRJcBM3jpu0nMuA0Y8FFcGaqjDJZGfXa2HkkQYfE4/tniaCxEcS1pyxSPPzg8jaIbAypiHKz",
-	"F119NTK2a45Gri4ewjyPRrlCxk1zndPEvqI5oxgLNpJgzDAqgcwBO4vFAkksjGOC30dzmlAegbTG7WYY",
-	"CpBiR9UIbWHUfRTx2mEEuDkNmmVJge7GX5V1jKwnezAmU1rhuQp4dPFMhcoEL3zn0+aJ5/3YSMvYIs48",
-	"If3TyCusTkPZbrgHvmXmFcrakoZbVZ6mVK4354eAqGwwrACq8VQqXFG1erabS8D3Itcx9ftAhhbeiioy",
-	"B+Akz7aXvTHdLWrozmN45S18ygvl0OmOyHytQfmwYQmpYgMtsRQ0SBVc/PoUMCQYmWgrGna2GuxedVi6",
-	"kpfdmuevNVS8qx9XXxAHgecweGe7fGdQ9IUmC5HzHwuL9r52sRgGS/CIsmsh7vPsXw8yS8cPBbLm95N6",
-	"OwKtdObORf2DY3gLy5o8VY2nSPXi5/3q2cbsQKLs5PDozdxQa6UhLeIbSuVpAfe6+p1yZAF0VdagLSuY",
-	"OAlaFehT8NjOYrIifDkfjBsdnNnUBVMNU64EYdqYBWbKSPAFW5osG6PdmTaxFtzCXKCbVHpz9fGP23Pl",
-	"dbjOQx4ndodY95qBZqSP45SxNL1sdfqfe9jqO9gRtTSzn8macJfpxe8OGzRokWTnFe8j0Lnk1jd30Wh3",
-	"SGS+3gryJdXwSNco3GOES8o4kJV4fI2Bul+c127pBwHk95LzflTuAG4nHyPXK+Io+t8T+7f8notHXsPW",
-	"D8UFW+yWIFh6WNllhd3cOaceqth0eYHly6okCf4xpKYvPfJVQrRZFzODzz8UcoqtVTMjvWmcuwiSsFHE",
-	"++2LcY5bAmWFc9G/CNqjDVGELuZAzN63JnPdEGGKMGNcTDnDEyvsa/fkRlH/L4GDpEktY9kZISY4ANGK",
-	"cqbSEC0HnLWYbcoXQhLBbZAW5+BLKOmAOJfm6Q6UTRtsLVDvbI/BrBV6zSL3VigBLRSIiRLFE+DuqaCV",
-	"o+k9EFgsINKELUxmnszNDWrhN2g2N/FHtGk2SaE/CUuWrvMVbFjKRvTL8CK98Y+IDJfy+X8YGOa2XZZP",
-	"48l9vdqpcwO2oWQTbX3BLboupRQdwshm9kPo2NId/L2Bhn8+RraJSz+hI7T/0q3kkEW/V8GH27wU+0BW",
-	"RRDpgHPTnblQUWT+BIkpB6ZXIIsUIBN7q2S9bBaxaxbZMY6AJCGPlOlN1oytNy8mxXRTvm/CQ7gf4lzf",
-	"KZZfSY36SVG3HysWeJuMH39wlSlts6XcMz2ahzbms82pKrI+YJOr7/OnmdImcUTtiYv+loPJdCtEk1gs",
-	"FOiqWGKcpXkaXDR9P7XyT5OwlOld4WZnOWk2w+2cJ545/1FP+1WJkzabpv6DnNqd4wluEyZ+rGAnkuZJ",
-	"/lEuJXIf2ygUJTZrACVkkWP2ezE2Bgux7yQuipv6ieREu5y2gbLCl8C1FRONJ/Pvllkz5+XnmH/wLu08",
-	"7joPP6Q4yl77guLJt/iukbUSeHbS5upH/v9vKNU3lH24NL8blg8vG8IJieEBEpGlNgUP+wdFommw0jq7",
-	"aBhLPlkJpS8+vDtpNmjGGg/N4Pnr8/8EAAD//5Lxm9kDPwAA",
+	"H4sIAAAAAAAC/+y9a1PcuL8g/FVUfp6qP2x1gJCZ1A774mwPdBJmCHCAJHt2OgXCVnfrxC15JBnS/xTf",
+	"fUs3S7Yl201gJpPwCtqWdf3d9Lt+SVK6LChBRPBk70vC0wVaQvXvuBSLVxDnJUPyZ4Z4ynAhMCXJXjIG",
+	"M4hzlAFYigUiAqdQvgFQCLQsRDJKCkYLxARGqjOGllSgcZaxdl8XCwQ4LVmKAMwyhjgHdAbEAnm9iVWB",
+	"kr2EC4bJPLm7q57Q6/9GqUjuRmq+RzT9REsRmm+uX8muIUgXkM0R4ELPmrLmBOBMIAYYKhAU7VXO9Lbw",
+	"1jJxFl5eYzycyc5mGLH20O3FjpIccmGOYhxY3IcFImq/lpQLwFCKiKjOR28huIUcLGGGklEyo2wJRbKX",
+	"ZFCgZwIvUXBMmn5C2TsicN4xot1V9LnAekOG905Lwdtdy+0i5fJa7s0MpJRwlJYC31Rj8RG4XeB0ATIk",
+	"EFtigriaSlYyc5oaeAj6LOw3bgqYCDRHLKkg6Evy/zM0S/aS/2/bocK2wYNtD6guZHMJeQz9WWKGsmTv",
+	"D93HSJ67t6b65n3shtVzAUXJg1ssFohFEIwDyPSOoAzQUuwBWJ2FWEABMAeECoAJmFEJXdXvNC8zlLUg",
+	"V0PouQbQo7XO3ux3A8bXhQhHIe41egN/1xu9h5xcGEBBpFzKQ9/39yoZJWfV1JOPrb5Hyb7c5Zk8wCAh",
+	"TXMs8TX1WrUOp6sHiTCnk7cAkZRmKPM7ArdYLABBt7nCEoaKHKYoA9crcDWdkqvgTvjg7Q8cgmNvaftw",
+	"WUA8J+EJFozOLWXHhAuY55jMAQSM0tragYRxwJE62DpI8a59OYxQ3gX6XO3M+Zvxs92fX4IF5AsLNweT",
+	"M3C9EqjiOfVzaB2m97oJFu93Xyej5O1JMkr2z9+ey/9fBQEiZYqraFI+DDuijMXsusdSQp8v4edXlmft",
+	"fQnQQkksP8CbbvYiG4FbeCPhKs/BNQJ/lqhE2QgUDHEJxLcLnCMgahPjkgjZ8x9MDmhaFO8R42oKoZWf",
+	"7J+eghvdQpO8NhHi4BYxBIR8JhTch4aq5tbDCwLAfmo/vRsldsyIgKPIvAQyBNNFk1huUJKvKuIMbuWO",
+	"55R+kjhSFhIhMJnnblc3k1GCBVreZ86WbjmiBxmDK7uEkvswfeiWOErewFwo1rFPl0WOzP+QpCjPURYE",
+	"dQHn4f0QcP51pybh8JAIxG5g/haTUsRAWzY8x/9G8be9UohqpKe7kOB/jRAxsB+QLBo0VMkGTbIxatAu",
+	"b5rhpdVxuDoqn5jYxXgQPZBmn3oY0CmNNc4JS1rtE+8K6xXjUZBeTbROumGaokJCUGvI/cYgbtsN40BZ",
+	"nFB7J6sF4IH9Zzgz8pEaojkAuEYzyjRlY0gwjAyQos8LWHIRhINRUiCSSXBdY43NgTWUSSHOzs1KHe3h",
+	"GJJnPHjNtnlzzJF64I8mibhednidggqYx/ALizW3QJ7DCgmNZRVNVLAmAbwf4/R03NjuIEYO7rztqmBl",
+	"ILqcoT9LxIO3TKZfAUErUHo4GeeRZb+HEW4aokYXOTFX1DQMCbngNUliBDI0g6V6TMEO2CDoBjHVULLD",
+	"JSZ4Kee6E4LQTnHiRDJfzW6C7EhNSN7B5XWUaJxRuysWmNcEkWQoC+wb0nUvPx/O/rq2e6mbgWskbhEi",
+	"mqvVd/XlTu9O+ux0TV6h+IH8vj7o89qgz3vRu5OXDsVhKwX13lYiDE4SZCeW1dbaRmF+uK5eKHTkbens",
+	"Q0XgTisCN3YE7swRuFdNAue6LYvM3UYikr8e2uDAApI5ygZL8opmB1d/qxleUAhUCiuP+PdTfbXL1S71",
+	"gMEZuqFpBAIkFb+hn1qUac2b+hvIF+F1X0OOXv70sNfSKIj5usagosYdgM885O5zLkUPQcPqGshDm/dh",
+	"sWp1toBcM3Ozr+H+1KtOKGxO0HU3UMkToSLqpEYWgNxMBsNQVCA4tJvPAeyHJkkM9eh7AGGl/WsumzKA",
+	"Bdegsiy5FJIktbrBQZ3e3yM6PCbgL+HnI0TmYpHsvfzp4cHS637355eD9IR1ZWCQmMwxF4i16EnrvGaY",
+	"LW8hQ516D9uo0n0wVFAmtITcRu9/8ZpV4FqKooSaPYhILPghuJXkFecIkS58rvcnvwBKjQTb85SQv0CQ",
+	"iWsExWDes6QZysOLUa8eYfPWVVyFt6EhaA7WD9W62gwTWguO3bS2zY3dl4P3X9puGBarU0ZnOI9QIdsI",
+	"FLpVaHzsD/9Ae+IJtRwxDPNjJbzGJilbWPn24cFGwDmPasrMFURifckVSwZzRssiMLavGGwP0tD33SCS",
+	"0ciC9bsHX2lbOfaxj6hKS0xAf6AxRAJHhgTEOTcyeQ+R1Tzo/M149+eXp5DzW8qyIdxqFGRXDTAtbIf9",
+	"nAqTG5jj7B1HjMAlGuc5vQ2pbg5nSlMgKBCsVLhBACTAfA5K871WyRMqQMHQjTyMwPQMVdEXBTOja0pz",
+	"BMlXYCuVk1Cb3x5yD/wPcLVzBZ6BUhlyJfYKBgmXUKXx+BpynCozp2z7XLa9ODoPvdutvWvLT9MBt4Tm",
+	"Gnuhz8FZhClW7z0dfy8UVvQ9bvgNU8G0ZAwRka8ciwieZfV2HSrvdzmMwGeY32sk/zuw4VumjQ2pers5",
+	"eC5zKNAtXMUuQua11suRNH7zxHwEKFMsT/LikZtL5C70IPLSenIDQXMqsPJNqRDPAWqneT3cO0HilrJP",
+	"lQE9tjluL2aMLh+O55e8Zynd5pUKcHvx+aCCvPbM3Lt+DI7dNC6UiUC+qy5mingHZCqJG1hIS8I1lz4P",
+	"ymsjzSlv3kWe775Y/y5yqPXP3oU1Zpo0imivoa+/DkqpW+CifpvilU2YIyKmJLxkyFckXTBKaMnz1daU",
+	"dN1Z1e9KmFl33n/rbZhHnHqcJbiuAnXqO6vaC6vxbLugqTWusX/7yqrtP/YJZGKYRrUuScZVKzz5OBBO",
+	"UdYPqfWjViJx8PZUCa1Kh2i6Hw5qXQb10GxDovUD6XyXmMtrzBmlon97XFdOe6LFhZYJyrtReGZPtUnD",
+	"rw92m9dh/PXzkdSvGrs2vzX8xpTubf3rrJoJ5J+sdfUhpqL0SPc+LPN912FxIansfY4rrC1vrDMGbvG1",
+	"9aL3SYFYTDdGACKCrezdMqdzuRXUfuFJIZJ4KYUdJtjIPYos97JpmIpuiUo3qPBFG//NqPoixVBueEvo",
+	"0K2Pco85zHNnlgQ/Qzm+cUI+mYd9GNQEQmCtLKRFgYiTLqpt87jImUUOaRjSo6v/z2W3krPwgpLMtxDJ",
+	"h5AtI7aiJeIczqPOduZ1yOaw9r4WcJVTGBnot/OTY2Ba1IZQGgu5KG74NiQrwFHKkOCAoQzWL0wBMvLr",
+	"Kjzk+PRQDYGY9Z4wHwTP0O+Yl7nodApjSJSMaJEiQKyMUIwYo2Zsmiq6HlwILzUexkQmefsHUmMwqgk5",
+	"7shGQDA8nyNWHSdlDfWX2fEMKSewWj9rnbEkpFzAZdFBuXU/FtgH02IrDbX3QL4xBmoy756wRaNzvW1J",
+	"3Zd3lFzojao/P5C7IofrlbXUiIm/Db3U1MxEk7Ysw/IZzE9rJK+95E9oBbD2ppAnb8/PQMMWeEWZJoa7",
+	"Wztbz107vqBlnjnHpBmVWioJQAUUAjGyNyXTcmfnRVpJSuon2tZPbyDD8DpH+qG5M9iWeogUVj4+ABJA",
+	"C70ir1l1X1ftIckAuuESYKeEowIyqH0EAUdL/CylOSVcj2RH7x6oatUeBwrB8HUpFUsKaLqHW8LP0oMB",
+	"5OrSBmZ2T59vvZSb//POjkJsSYEY11cf/4q3s7MT0q/UztKefkzT2Q07F/dSMfdyWKu5vqcgoj7vhXuL",
+	"ae3p6xetaRq+HlO8URYVz20De/GdvD+fOCpRsTjucQB5HBseAknLxOYeMMvkCmAPsx4vl1FimgWndd9Z",
+	"9PrzCLezltz9Sqk4rmvxdZRK8yGek/e7r+s0UT5UR4fJ3KpQ2w3o8hqT+iWq/2pqZjoUWKJW+Ym2rVcW",
+	"ItMx0C498iuSaiKpX/BklFCCTmbJ3h897tYhmL0brf8RT+4+RpbFu5DAn30QIfhgz/HwUiTNOtTf7+4o",
+	"2DK/nrfvh/sWleRYDVO3YeIO6M5P9n+fXEhWOv71aJJ8jOpZQx6Hl3BZIAbnyO9bQvmL3aBcLT+5obkY",
+	"/kVBbxG7bOpYxvuXzy9P34zPJ1LA3r98Uf042A8uQbKYDLLM72T/zfhgoj0r34xPfjuUX5+8nZxfHO5f",
+	"jv0fv/o/9v0fB/6Pif/jlf/jtf/jjf+jNuhv/o/f/R9HySh5/evF5Xjf/HMg/zmc7F++3Hmx88vl7qVG",
+	"qcvnLxvPxYKh6OMXu8HHL3+yj3ef//Ly8uJ54+fl/snbX0/qD3cbP0NtXowbv+Uijidvx5c/X+7u2P9f",
+	"Xr7w/v+5+v/5jvfi+Y7/5if/zU/6zen4+OLk9dn49M3lrycXFydvL9+d1h9fnJxeHpx8OJYC5eT8aHx5",
+	"Vv13noySd8e/H8u3vfTRQLGJRqxhRR3ia9DswWSQsFIipMwS8mw5zcs5kLLd7ktg3LlT09xIE5zTFCt5",
+	"GhKA3o4PD+y1XenGpSkDZeAGLXCat53p0BLiOJfW4xwegA3V8SbYUF0HJEgjt1HGJTs0omuyl2z8MX72",
+	"f+Gzf3/8snu3ufHsPzbtg51nv3z88iLw7Jf2s83/iMQU6eVhMt/vj1Lzm9euU3J5p/tydUZmN3u1Z+wk",
+	"yyUlWmClM6XCOpm8jfbWa43XG941+xCITG44alP4So4arlt1/CKgbJTC0aUGB1LmuRTZkz3BShRyaQ2B",
+	"zTuC/yxRvnJXXe7kOwk6Rgu1f3rCQZFDIfEJbEAiLxrltVavUFa94ptbvRta4qwWq+P2JLSRWgXTrTCr",
+	"tDwaw1J1QSNUOQIapZKW3vs1YyaSuFdzJW+ndQ2T0p8qT62gmSfIQGOYPEQloVQgdaXE/e78IR1Z3+V+",
+	"qMlEHXW1qaHzfY3oked1XD+OHAosygwFRZycknnsbWM2VT/+V6HZBC0ZofjkpnanddVaQO5U+kNNLoMC",
+	"TEbJ5P1+WJLKjJolFj9/akJS8Szu+OkFV9nuPMBwRrkO73npDDTO55RhsVhGQpClvxC0beqWbuVjzZzK",
+	"QWpqZHsUzAOhW/+OVnEXWz/e2XdU0p/+i4OivM5xKgeKj3AMl+grhsgwlxhVYr5AmVpb2E+gz+fOHyio",
+	"gFyHt7WjRupH11p7c7sbMx6KUipWFkOSRkhTh7HHWGOUp8VIq7a1gei6FE1LEKBkNMDt6IewSv7jrHAP",
+	"Y3Y7VPEa61LzihhW4R4DwOibyrvQTMchOcnHbyFGRr9cK6RFfzLYykGoGM8EYgNHWD8nC0G3MD97ABO7",
+	"Cm0h6DZw1kNdyf8udqEN5S17ueEi1RF45x3CzqOOeLOmjwRNIyK7SREVojMpFqvwC0pZhomlgV0XMF9A",
+	"VV+W0jwf6VW9u5TbHWwg72vDr37qDnk3il3tKuKuhIkhV8ACMhkJ0NbYHZ0cv758e3JxcvZh/F9KEXP2",
+	"++Hx68vX47Px64n34OhEaiNPji8Pzg7fT3Tjk+PL84uzidJTvjs+mJy9Pjt5d3xgP/44GjQxsbqMqDIL",
+	"KllKtak9nTVA1iUQU7Dgzq9xWnWQ8GYUAtuTtMAH+l65Cl9IS3FNS5JJY9ehi3+3rEVfTRFxl9N7X0Tr",
+	"Gc148JZ5TbOI/d7OTLUYRQF632x9SOWkGgC5h05WSBG+0QZRJsL96vwcHYTT7MxKkUzbfPSViYGqTvuj",
+	"tCaMxYJAtM+BlD/VTGXrxjEExUMkFjQyrzcXF6fANAgHbJUxn2EFYLoBEN6JYmUPHWl/Wm4C6t+dHQFl",
+	"HZP34RtkFRXqmP7FASJZQTER1UXMDBziseizGMecfAKHqOIw0GelkYGez82w81QTjKoJ5UuAszXAj/ek",
+	"l2vP/dqmOMnAxpW5A19tqvg7yF3CE6Vt1A052LjSV+SrzXXvzyWLxOfJA2ycstE4DQkqquOz29YKOPXI",
+	"nm7OIyv+iTsEDlHHMxWTFlPXHaBZlZ1Q+63lIA2HLQkL38zrERSMppqi1ylmv3uxgxCvO7OVW+Bw1tza",
+	"JWSftPLm6mzy+vD8YnI2ObjS0UayqaCfEKngA+pgJSDolFw7hwGYpip3Zp5X+MUBvKFYgoHqhiCU9a+3",
+	"e4JTcnU6OT44PH4dnp8KC6xN0k5MNrzapmmBt00cBb8a2Se7W7tXSgXjfm+nDCnqCXN+NSXVmrTzRgXm",
+	"ejLSc67auSCoqzmGD01P34ukkpr9kihTO5nbbCAIoLfnp2Bj/2xyMDm+OBwfnV9enPw+Ob4cb27V3UmC",
+	"IWeduEZnbgS7O9UxqhNx4QzWIVPtN0yljK8eckQyJ25XvVi484lgyXAvHusNC+Jd/T4cDLtuJt3R+vJG",
+	"VhKocjgVNrqnmWdqQyldlvQGZSNASVppBza/lWD7x7h/jwCeE8psUK3V0P9F2RHNDvcExnkbaPMgmIPS",
+	"4krjoOvhFjOYcxQIm/vqRDMxZbSSeiKZ0FCf+GX4xP3iTID/og/hOnKnXISJ15io6EzK8L+NpkS1ayEH",
+	"TBfobVC0PiSZDSBSShZjqVH9APmdBD3MLTn3Q2SOPoz/SwLS+Ojo5MPkwP13efLq1dHh8UTZ4t9PzsLq",
+	"IGNCPlzDxFwZtDNHk/VMH874/JWW53vdY6TmQu6zpP6NNBjKx8f71RpQuQmGNxFzKayqBioBCi2L3J2u",
+	"QsUl/ISAuKWAMrCkDNlXOhiRA0oaaT9evIyaLQLAZdYljwOS1UhHq5tFO8Om7xRrmoKCYSKUXl09Pnt1",
+	"eABSyLKRutISJCUKyHC+qthm+H5F5iWco/hxFAzNEJPE1ra1coAl35CDw/MT8PLFL8+eu0bmKr/WUcn7",
+	"2zud7ilya9SMUt0sKMucEs8kiQIbljNQYq6q2/rV5uPccOKA+aK22hf3MgdbYlVRlIPLNyf7l+/OJ9IF",
+	"Z3x6av89uXij/kooCF9jYvfwUnkd6JEAzgbAsorrD4GyyQenetKNQoHfN5iX3XpS3WKbIZhp92jVdtsq",
+	"ClIri1fwD4kD/37FaeTeZazkpY3XtbS3Ql678pHHLdqcyAswPpe6Q81fxgX+Ha3CiSLGWghIVQTHJ7TS",
+	"3to8pQWyTvHGQxYwmnsBAdIWq2QLl/MVco7nBGV7U3Il9++ZvGzIO4QJLpf/WkeRK0nSrmC2xORK3xiw",
+	"nM4CwUytVqsyk//zbHx6+Ox33xwL1WrkYf6KIEPMruta/Xplsey3D1IN2Vzsbx8ugG6oQWXwclXrf3H9",
+	"NM0hXurVy7OCmNxvyUq/qyBUTcmtcSFEkdzdqUQYM2ochoRxNENLiPNkL1lCdIOeCQSX/1ssaDlfqBh1",
+	"vpXSpdvAt3DyHgHZqO0wrzIkSn4sz17FBgukeHrFvfXXUiIdAfTZtNapJbiNfyi5vqzKC0yOU0S0v5MZ",
+	"f1xIcJU+1zqkReRuVkbSvbFx/cnO1o5uRwtEYIGTveSFeqREg4UC5m0pUW3nrpTFHAU0T0eY2wSZzTsN",
+	"yRrp8H3bpUsg4aoHqKOqfGMOM9O9lwLf6InhEgnlgfzHFw3Nf5ZIKZjNXtDZjCNhzx3KWXd5n9+Nwt3k",
+	"eIkbvejgChUn0ZMs8uMosSFgajt3d3YseJlgOlgUuSFz2/9tgvjdUIPsFd7WBIy8LTiUuykZTlUiQrVQ",
+	"F5K15tY1JXP3CIz+jlRXXH3Z8KmoOkuffv6RVGiefJQn5BOh+suPo4SXyyVkK7tGCbreKu9GdWje/pK7",
+	"kgp31a/D7E4DeI5Ct+f9HEHGvQoXUHo2ORw2wgqdqdw3mJa8qtAyApy2qoFovYWNapoSvqBMIC6qEiJb",
+	"4LUmblLvpDKtCExKk59vJslBVYZDR+LM0C3gKKUks6Vj/BIVKquD3MRsK4BpKl4M+QDVQrX4PdgbIhQT",
+	"ZEIamgVmFM5JeuOhnDuXxGfo2vA0DAIDxVK+vhhOfK6HWedMm2JKmy781Aa1YwosMn7TKGo5bghDvXc1",
+	"BFVoJIU5H0k1jjaUV9IMGXCgLWS4rbrIBVNXWrWgljjkfyroTqJLU2UjqF/XRsbXteGiVL5Py1KUMNdZ",
+	"n6yyV/7wgsQhk9KZbExnMzlF68Ui/392DXNIUsRCqKdXVHf7NMrKX40h8UHO3R/h7u6uCbV3Lch8HiCC",
+	"xgzxTYOlEv1CMGlf1ABSb389w3ILHp+lXmGZPkkokKmZ2+wClGU2dlss0Ern7+cCMqGUu0Zzk6/ADOc6",
+	"zeD1akq0SsyErkZSWtuhPMc1nctqKyJYBfJR8z6qrzKG55LHupW5LOGVbjEkTlUvHTg8TH2Pu9EPLgYG",
+	"DnIdcTAIr9+tbBhdbZjdnEvUVNzGtNW4a4wzPFJXIZq/fwlFahS28q2Ykg1pqGy2xTNAqGkCMK+MXput",
+	"jFfybaQ6B8BkSnTRGDoDVzZl/lVrNKTM7leBZP5XNl3/FjimWjOqO4TMFgWZEmWRUu/EAhJw5dVeaI+l",
+	"5F3jvOGl92rQzRDJ0qwnBOuPzi6bJTeGc8/HmkkIib5D3qywL1L8IM6jt7/Y/8yVLsywKf1kktcOqLSg",
+	"mak1QHbW0QryWzVYGHg7GW68vFrgSuKW/ZV3kp2/GnYvInKTBOafdn56sOnEAfmYCjCTLoTfJefTsP5V",
+	"iLSdKhksfjM7F7SocUplAldcQuKNNGc5DrIFDj1W5RdegrlcxqoWGwWZVJCoeId4wB/fAuMGm5a+YqkV",
+	"I231UVM6JMhj1BKf0PQJTf9SNqfBbih6bn9plLnoVFtqxV5MX1KLvSkLp7eovKu0AgR2Vc2YkqY7T1zD",
+	"WFdzhJT5DVxpFUiJI0y359l9VG9PMH1vmNbH3VSrjDplsb8dRlti2t8PozuPp/9rXB/c68rZ6wkFHkv6",
+	"GkDVt1mjPFg3gbdRo2lVf35IsSdOK2VkU61gi1ROCZxDTAbRdK+i2bdP3b9nUuuBQh/VvR/g3I/mgjOV",
+	"ZVQL4grhY6kRqpAt44k7iFx/S+D3KITbW2APCfdO9ImaP/pd2sc2Rdhr9VrWdJ0JeMn4xT8AtFVEYoYd",
+	"27gWk/7kPNORWtCrr7OO5aQ6lcYRfr/Wk/iKDdhXedrCSiKtpOaAMuPNy22tlSEZ27zcY7qltY2YHG7G",
+	"ZxmLrSm50I4pMHed+xhrTCpMJzvQjuGBZjZMzEzCGv/NJKbEWB547SPfChPiW+dIVBntHsl4Ybt/MvQ7",
+	"LYvvKQ5gV+LAOjRvf1Ep6YaqWcxXlWhNjOUsBF/akmbyd9RgbQvsh9pPibkPNzWlpg9r/O+QmozQ7gCw",
+	"V7Opgb8KTag+DIhUNnnfD+oRdU/BvRMUK+k9KPx+u8e480jUrFWBUb17knIfR8rtp5L8viKui2hwVVVj",
+	"PlAmRYA6YkMDQ/02CoVi1l0ZFFCm+obzqJNUjwgddY9qTMxkr8DclDSNOElVL+OINho+qEJ1zHWV4ciI",
+	"9t2DDMhKQrSNHPNWjejIBJr1ph9oKs4rTcB5ZGj9pnO4pyuSBf61LkY/ynUoeAni219koq6h8mIjQb6L",
+	"KsCC+w7KnipnZMPh+QgUOsFJvQQtrhnXZSxOI3Nt0JkUYF6riToCeAawDh1wz1QuYlPoF8jZIqYyXVzr",
+	"MrymZYfk2ciU129Y78gKGdLq8cNsqCpv938+2Qm/FeV17ZSHKK0NMmiNtRMhhrtj/eMA8QFF6jppD8jV",
+	"9bU+SdePo0NuAX1YbXZmoFsyDIJug3Vw+YoLtDTpcDgvl9Hi0lNiLSwrZKwsvhZNsgvVi0oCE/geYKLU",
+	"0Jb1yMfSQYsCLFS0jeoypWSG52WVwQULlZpHLqEliIeQ1K75L0LT9dDyEVR2/jIVBD3p7iqEsbAQBP+G",
+	"2KViPaN3UmuA9BmI3WN7z1ROJbZSu6qTKRBbYoLAgt4OCRsbxnLUir8ReP4r2IwD6k5WIze3qp/51zGc",
+	"d+QTobckQJK/XSQyMBpCI/eqgUgO/MN3ixA2bZtI5rhd50xFP8fqYZuAj2vIcaqG3QLveATfrNqcoUKR",
+	"sSqKWurHNbJxVUqxNoTKTF+lCFUN6kG8IxVrCmgpwKxkuvSaCZ/eAhPpxc9LlaAP36ApsRHNOeSC20uO",
+	"Cm/R8QImwJsSFOZccjNasG+iYr5VSfPhWZq/6EHMbOdBhzZR4HF00xl5ZWVcKcXYMzeBit8d4kuYBNDi",
+	"YgP5DS4Ekb8vCYilKbcmJUC4a5ditIm5Ut70tb4Si5v5AkZTsk7ekDCzHZja4G9AwGCKgvoOVPW7wwcX",
+	"1nAytKQCjbOsW6f88VvAxH8S/t3v2ucz3+Zq63jnO4bW3UBj7Feae3XPQWe9WF1vfXW0vFfd+kxyEZnf",
+	"wLkx8SnZsPshL4sznCPwYlOz6sJUOYJV2RJ/8Oo66VSLbQlB9jMlvqLR1ugLdNmOgtWXToemGUC6vOr1",
+	"ynh6mNC9KYllQNWmICx4l0tjmNvLCdbdibo8yL9Xbh90U1w/ZPYHuMtKeBniZNtBFbhPC+rwaALrYgDJ",
+	"fxAVit0Gf+XDhdCGmfH37y8Rj9mfujdSMHdBCwz7nVubIqEI2pr6/FtHzRxblQOAqTPqG/fdrIYJhJ7L",
+	"5w+s+vcdX3s1M26L/wb9TOAUlBYb3SDm4Od7Fxu9IwhLjk7I6pIWjdNqZR+wAmCaU27EyFt0zeUdS4Sr",
+	"kWkZzNVGmJJa4Ve/U8hXJF0wSmjJ89VehBjMGDK57cz8RwAKcLvA6WJKdJkULFzxB08Dm5lgFCwqkgK8",
+	"ogkxSzfMOZ0S396t7BQKnlRXrgvtjR+PGz+oOvlH2BQfmfO63ZA402awu4H0uCbW7PtjswfxS08TdbVm",
+	"5lkFWkMc65wfSMwBPez+0VW4eUqC+RUU4nKki4JWhVD0pHVZCkOgrNWPIcFWoKA5TldVBO+UuOI9cmLO",
+	"YVlX+ymJwLnOjSZfMfQMsmU8iZku6lMDvxO3f98fYx/kt9Us4b2G55Y9TreH36vvVmClPei4zRBkyy62",
+	"ypFBy1bnsZKe1BnRqZcWUIK+VqnI0NuRygDIhI64JCrNK8M6r5fGG8PkvPFUX7fIQyGDT1KWCOPTmVze",
+	"E0I9LEJVu/9PQan7MLkztch+lKpqGj1rqjJ6bfXtGsgBdFJVIoJF4IGgc30VtZfJKRlY9Do0kGKpvWWa",
+	"tc2iUad5C+wHyj8ViGGa4VRZQHSxWmMuwcxbhT9E3LEtVAGc/8i33PCGRC6Z4d1+8nx7SM+3Fkb7JeR7",
+	"BeUwGdlmaIky3Jk9+j9LVFa1EJ2MbLnz11MEdQ1GtT7XruReXVjJHHEnCmihfEqaA9du1y7Dgli4ScvJ",
+	"9cxDiQo63VpYNjB7+0Rb+HZoD1S2YgxJiuKeBmYLnwjLIwggZm+/krKsdfduStuVK9MCclNqtVajreVE",
+	"G8oIPiVGaJfuGiirtGWKFjBbAx+IBZOlaNRbWVZmQ1lZF7TWBC03RzpDqfESULVSzT1fvR+Z5L9S7uhI",
+	"rejJOxqlZDOTdTzPt8A5ShkSXOdpZCiD6rQrUlnAlU7aL0ODdNpkklV96V+I6CK+kCFdPJUhATExchD0",
+	"9QpaVhoUsfdNX12ewstCJ7XO5ep7vlOpNTbITE7nTYrFUIUZHalZS7kJiGvnadPeOV7YElNSr64aZmEy",
+	"sAV0NUUVOyCDzAQiJhefLcAMuTTfIYIYbKY1dzEGqqQGkkQN8+XIRJjZ3qZEIjwlujRijfRJLANZKdEH",
+	"CMSlPmQLjFWlG7cNxqks5JTtKovLAASUubI8zV1JIQFCFoVEsxlKhYqDI1yw0tgoY16f5iR+xJCFc6OJ",
+	"fjKyN7wuDUwMMKwLOFfbU5RBZYgKx+GmesDcuz/Y0ueqxmnQxVKiFPoMl4WO0TSVBeCUhDLOxlLW+Kd9",
+	"Iaf6Y5q31NK/Dsif5P37lwRAwmFAIFdhC6e04SvOG8dZphz5Xe3flqlMcwlEqoKw6p4c8WfeAhfNDnT6",
+	"ckREZxUgffk2AvstxErdHx5D28klrFWc2sYkyHocaAv8Z4lKbS5oLKfqUcVImIcjMHl/PlFyuDFWSplb",
+	"b4vNs2WzDdmyH8zSI6wLWdLSpFa3pviCcuxfdNSHIdpi9utHZJpm6Wt5Sf4grNNiUeOULVJpPEefCyyP",
+	"a5g9IZaPRgH++93XAbWYy1FjEmwJGix1qtX9ajpIoYMB+jm+QcQUN5bUI4MrPtL4r12y1SerLbDfMbRB",
+	"NV0LTBkA5b64D9olv4I2Bm1WUKRDSrzaml9PLgZLQZdQaDtE7IY9MZu+rgawvgtyIjmlnwBcIJiNgIFe",
+	"9fzFTiSeQX4YvtO+ePnzzuheF+Uf5r59qCB4v56PfehV22JaS3f5XToHRFYrSU5uPNu3v9j/TKqX/sh9",
+	"+4FTCapKlR2x70fRdMbB+JlhjNDNO1k3w/HDs8OjWj7jpwiB1SCY0aBIKEmN2UF8HQf0WY6XQy1kCG2b",
+	"xCBZGcF4Sqz/ZdvKRI3Aq41daiQYNUq5sX1m2bJ9OUiP8apj6eJstqibX/3YrKHHojWcT/gw+aPmAdO4",
+	"Wt+JFh+haYG3jS1m1YOwtBTXKln+yf7pIahyDm9UxMHkUh6BlC6XxriihCkk0q1ND7ltWv2AuzZDKcI3",
+	"EqELyMRqC5wazzTTEiMOnAZVe2vW5MX/ZRyApsT7Qo3qXD61Q4v+nLvQQLmzfr945vzglPS7iruAnqQF",
+	"PqgGHMIpXfk+HVRSTVaKpJiLujB6pb2/rtava2s2MBkluoun4rVt/PVOb7UOnXGn9t1SlTrW+wtu0Y/t",
+	"L/a/oXkHSZioVG6oNtai6Rwez+tXO8kh5TfclIeqfF+8fErX900ENZikfSQMo6veQl8x6KuKfVk+BXrZ",
+	"VNQL8puBx4dzPKoTy/bZ23dP6foeJV1fHNy7CfK2FmE67PPpAmVl3kWXtY3dYYMKDACQA06VE6BUt3N8",
+	"nevkCFPilG1V/hXMAUMcCWv2tl23hDrVtU79KiU6HtZQyCV9MzjWE1P2hARfbciWx92HAjYJ6yB1GNE9",
+	"KCre0IiBA2TDYmxEes09w9bDr75QMDwlJumH9b2r0sKyhgpFj0mZ90MhgbS6gVntuaCuuymJddinxzuV",
+	"fT1SrZczb0ZPWrSgFi0OagZuKa0p0LzrePu+eUZpU5X0+NekxqBrRSA1VXHf7YVJHmo1WHDZHS4AsPWF",
+	"xP0Nr1AVDKkpN20YbnCrTZitUg+pID5njasmKqhWknj+/+MOxSivfanY9pLeGHfbpoZUuz8jmEV8eppg",
+	"9UgUqgm8T0TKd2eBJA61YfpUKxbbc+M/U+ARhF6XLzMGvlYbkAbMyVX2/ylpw7Jw4KxwyADpZlx90AbF",
+	"Xj3eAn0OZukCzbqj9kFa67+7IujhUxmrNVP494KxoJ9QX0FMGYig2lnaqTO7WRdHmfeDMvxvlz8rohW+",
+	"UH08mXlqEKI2ZR3ZQZ/EdyswWGiyTnV2sYNrV0L90f1B9RxpSH0kzmsO/Hvmt/e5zTZKQZIQIHgka/uL",
+	"+vPO1IDs1Gx+LUDofixM9CtU7My+VZWlB4ENobu95U9qy0dSW4bBW43Jbro9inKQoRuU02KJiAC6fTJK",
+	"SpYne8lCiGJvW7lE5QvKxd4vPz3f2YYF3r7ZSe4+3v2/AQDX23kJ2QEBAA==",
 }
 
 // GetSwagger returns the content of the embedded swagger specification file
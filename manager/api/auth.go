@@ -0,0 +1,259 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"github.com/go-chi/render"
+	"github.com/lestrrat-go/jwx/jwa"
+	"github.com/lestrrat-go/jwx/jwt"
+	"golang.org/x/exp/slog"
+	"k8s.io/utils/clock"
+	"net/http"
+	"strings"
+)
+
+// Role identifies the set of operations that a caller is permitted to perform. Each
+// role is permitted to perform all the operations of the roles that precede it.
+type Role string
+
+const (
+	RoleReadOnly Role = "read-only"
+	RoleGateway  Role = "gateway"
+	RoleOperator Role = "operator"
+	RoleAdmin    Role = "admin"
+)
+
+var roleRanks = map[Role]int{
+	RoleReadOnly: 1,
+	RoleGateway:  2,
+	RoleOperator: 3,
+	RoleAdmin:    4,
+}
+
+// Permits returns true if the role is at least as privileged as the required role
+func (r Role) Permits(required Role) bool {
+	rank, ok := roleRanks[r]
+	if !ok {
+		return false
+	}
+	requiredRank, ok := roleRanks[required]
+	if !ok {
+		return false
+	}
+	return rank >= requiredRank
+}
+
+// ParseRole converts a string to a Role, returning an error if the role is unknown
+func ParseRole(role string) (Role, error) {
+	r := Role(role)
+	if _, ok := roleRanks[r]; !ok {
+		return "", fmt.Errorf("unknown role: %s", role)
+	}
+	return r, nil
+}
+
+// Principal is the authenticated caller of the API
+type Principal struct {
+	Subject string
+	Role    Role
+}
+
+type principalContextKey struct{}
+
+// PrincipalFromContext returns the authenticated caller of the API request that the
+// context belongs to, or nil if the caller was not authenticated
+func PrincipalFromContext(ctx context.Context) *Principal {
+	principal, _ := ctx.Value(principalContextKey{}).(*Principal)
+	return principal
+}
+
+// ErrInvalidCredentials is returned by an Authenticator when credentials that it
+// understands are present in the request but cannot be verified
+var ErrInvalidCredentials = errors.New("invalid credentials")
+
+// Authenticator identifies the caller of an API request. If the request does not contain
+// any credentials that the Authenticator understands it returns a nil Principal and nil error.
+type Authenticator interface {
+	Authenticate(r *http.Request) (*Principal, error)
+}
+
+// ApiKeyAuthenticator authenticates requests that provide a static API key in the X-API-Key header
+type ApiKeyAuthenticator struct {
+	keys map[[sha256.Size]byte]Principal
+}
+
+// NewApiKeyAuthenticator creates an ApiKeyAuthenticator from a map of API key to Principal
+func NewApiKeyAuthenticator(keys map[string]Principal) *ApiKeyAuthenticator {
+	hashedKeys := make(map[[sha256.Size]byte]Principal)
+	for key, principal := range keys {
+		hashedKeys[sha256.Sum256([]byte(key))] = principal
+	}
+	return &ApiKeyAuthenticator{
+		keys: hashedKeys,
+	}
+}
+
+func (a *ApiKeyAuthenticator) Authenticate(r *http.Request) (*Principal, error) {
+	key := r.Header.Get("X-API-Key")
+	if key == "" {
+		return nil, nil
+	}
+
+	// compare hashes in constant time so the time taken does not reveal how much of a key matched
+	hash := sha256.Sum256([]byte(key))
+	var match *Principal
+	for keyHash, principal := range a.keys {
+		principal := principal
+		if subtle.ConstantTimeCompare(hash[:], keyHash[:]) == 1 {
+			match = &principal
+		}
+	}
+	if match == nil {
+		return nil, ErrInvalidCredentials
+	}
+
+	return match, nil
+}
+
+// JwtAuthenticator authenticates requests that provide a JWT as a bearer token in the Authorization header.
+// The role is read from a claim in the token.
+type JwtAuthenticator struct {
+	parseOptions []jwt.ParseOption
+	roleClaim    string
+}
+
+// NewJwtAuthenticator creates a JwtAuthenticator. Tokens must be signed using the algorithm, e.g. HS256 or
+// RS256, and the key is either a []byte (for HMAC signed tokens) or a public key.
+func NewJwtAuthenticator(algorithm string, key any, issuer, audience, roleClaim string, clock clock.PassiveClock) (*JwtAuthenticator, error) {
+	var alg jwa.SignatureAlgorithm
+	err := alg.Accept(algorithm)
+	if err != nil {
+		return nil, fmt.Errorf("jwt algorithm: %w", err)
+	}
+	if alg == jwa.NoSignature {
+		return nil, errors.New("jwt algorithm: tokens must be signed")
+	}
+	if key == nil {
+		return nil, errors.New("no jwt verification key")
+	}
+
+	if roleClaim == "" {
+		roleClaim = "role"
+	}
+
+	opts := []jwt.ParseOption{
+		jwt.WithVerify(alg, key),
+		jwt.WithValidate(true),
+		jwt.WithClock(jwt.ClockFunc(clock.Now)),
+		jwt.WithRequiredClaim(jwt.ExpirationKey),
+		jwt.WithRequiredClaim(roleClaim),
+	}
+	if issuer != "" {
+		opts = append(opts, jwt.WithIssuer(issuer))
+	}
+	if audience != "" {
+		opts = append(opts, jwt.WithAudience(audience))
+	}
+
+	return &JwtAuthenticator{
+		parseOptions: opts,
+		roleClaim:    roleClaim,
+	}, nil
+}
+
+func (a *JwtAuthenticator) Authenticate(r *http.Request) (*Principal, error) {
+	authz := r.Header.Get("Authorization")
+	tokenString, found := strings.CutPrefix(authz, "Bearer ")
+	if !found {
+		return nil, nil
+	}
+
+	token, err := jwt.ParseString(tokenString, a.parseOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCredentials, err)
+	}
+
+	claim, _ := token.Get(a.roleClaim)
+	roleName, ok := claim.(string)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s claim is not a string", ErrInvalidCredentials, a.roleClaim)
+	}
+	role, err := ParseRole(roleName)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCredentials, err)
+	}
+
+	return &Principal{
+		Subject: token.Subject(),
+		Role:    role,
+	}, nil
+}
+
+// NewAuthMiddleware creates a middleware that ensures that the caller has been authenticated by one of
+// the authenticators and has the role required by the operation. The required role is taken from the
+// security scopes defined for the operation in the OpenAPI specification, so the middleware must be
+// registered as one of the handler middlewares (ChiServerOptions.Middlewares). An operation that does
+// not define any scopes requires the admin role.
+func NewAuthMiddleware(authenticators ...Authenticator) MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return authenticate(next, requiredRole, authenticators)
+	}
+}
+
+// RequireRole creates a middleware that ensures that the caller has been authenticated by one of the
+// authenticators and has at least the required role. It protects handlers that are not part of the
+// OpenAPI specification.
+func RequireRole(required Role, authenticators ...Authenticator) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return authenticate(next, func(*http.Request) Role { return required }, authenticators)
+	}
+}
+
+func authenticate(next http.Handler, requiredRole func(r *http.Request) Role, authenticators []Authenticator) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		required := requiredRole(r)
+
+		var principal *Principal
+		for _, authenticator := range authenticators {
+			var err error
+			principal, err = authenticator.Authenticate(r)
+			if err != nil {
+				slog.Warn("api authentication failed", "err", err, "path", r.URL.Path)
+				_ = render.Render(w, r, ErrUnauthorized)
+				return
+			}
+			if principal != nil {
+				break
+			}
+		}
+
+		if principal == nil {
+			_ = render.Render(w, r, ErrUnauthorized)
+			return
+		}
+		if !principal.Role.Permits(required) {
+			slog.Warn("api caller not permitted", "subject", principal.Subject, "role", principal.Role,
+				"required", required, "path", r.URL.Path)
+			_ = render.Render(w, r, ErrForbidden)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), principalContextKey{}, principal)))
+	})
+}
+
+func requiredRole(r *http.Request) Role {
+	for _, key := range []string{ApiKeyAuthScopes, BearerAuthScopes} {
+		scopes, ok := r.Context().Value(key).([]string)
+		if ok && len(scopes) > 0 {
+			return Role(scopes[0])
+		}
+	}
+	// fail closed: an operation that has not been assigned a role is restricted to admins
+	return RoleAdmin
+}
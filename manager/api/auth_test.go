@@ -0,0 +1,258 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package api_test
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/lestrrat-go/jwx/jwa"
+	"github.com/lestrrat-go/jwx/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thoughtworks/maeve-csms/manager/api"
+	"github.com/thoughtworks/maeve-csms/manager/ocpi"
+	"github.com/thoughtworks/maeve-csms/manager/store/inmemory"
+	"k8s.io/utils/clock"
+	clockTest "k8s.io/utils/clock/testing"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+var jwtSecret = []byte("a-very-secret-secret-for-testing")
+
+func TestRolePermits(t *testing.T) {
+	assert.True(t, api.RoleAdmin.Permits(api.RoleReadOnly))
+	assert.True(t, api.RoleOperator.Permits(api.RoleOperator))
+	assert.True(t, api.RoleOperator.Permits(api.RoleGateway))
+	assert.False(t, api.RoleGateway.Permits(api.RoleOperator))
+	assert.False(t, api.RoleOperator.Permits(api.RoleAdmin))
+	assert.False(t, api.Role("unknown").Permits(api.RoleReadOnly))
+	assert.False(t, api.RoleAdmin.Permits(api.Role("unknown")))
+}
+
+func TestApiKeyAuthenticator(t *testing.T) {
+	authenticator := api.NewApiKeyAuthenticator(map[string]api.Principal{
+		"secret-key": {Subject: "gateway", Role: api.RoleReadOnly},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/token", nil)
+	got, err := authenticator.Authenticate(req)
+	require.NoError(t, err)
+	assert.Nil(t, got)
+
+	req.Header.Set("X-API-Key", "secret-key")
+	got, err = authenticator.Authenticate(req)
+	require.NoError(t, err)
+	assert.Equal(t, &api.Principal{Subject: "gateway", Role: api.RoleReadOnly}, got)
+
+	req.Header.Set("X-API-Key", "wrong-key")
+	_, err = authenticator.Authenticate(req)
+	assert.ErrorIs(t, err, api.ErrInvalidCredentials)
+}
+
+func TestJwtAuthenticator(t *testing.T) {
+	now := time.Date(2023, 6, 15, 15, 0, 0, 0, time.UTC)
+	authenticator, err := api.NewJwtAuthenticator("HS256", jwtSecret, "https://auth.example.com", "maeve-csms", "",
+		clockTest.NewFakePassiveClock(now))
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "/token", nil)
+	req.Header.Set("Authorization", "Bearer "+signJwt(t, "alice", "operator", now.Add(time.Hour)))
+	got, err := authenticator.Authenticate(req)
+	require.NoError(t, err)
+	assert.Equal(t, &api.Principal{Subject: "alice", Role: api.RoleOperator}, got)
+
+	req.Header.Set("Authorization", "Bearer "+signJwt(t, "alice", "operator", now.Add(-time.Minute)))
+	_, err = authenticator.Authenticate(req)
+	assert.ErrorIs(t, err, api.ErrInvalidCredentials)
+
+	req.Header.Set("Authorization", "Bearer "+signJwt(t, "alice", "superuser", now.Add(time.Hour)))
+	_, err = authenticator.Authenticate(req)
+	assert.ErrorIs(t, err, api.ErrInvalidCredentials)
+}
+
+func TestNewJwtAuthenticatorRejectsUnsignedTokens(t *testing.T) {
+	_, err := api.NewJwtAuthenticator("none", jwtSecret, "", "", "", clock.RealClock{})
+	assert.Error(t, err)
+}
+
+func TestAuthMiddleware(t *testing.T) {
+	engine := inmemory.NewStore(clock.RealClock{})
+	srv, err := api.NewServer(engine, clock.RealClock{}, ocpi.NewOCPI(engine, nil, "GB", "TWK"), nil)
+	require.NoError(t, err)
+
+	r := chi.NewRouter()
+	r.Mount("/", api.HandlerWithOptions(srv, api.ChiServerOptions{
+		Middlewares: []api.MiddlewareFunc{api.NewAuthMiddleware(api.NewApiKeyAuthenticator(map[string]api.Principal{
+			"read-key":    {Subject: "reader", Role: api.RoleReadOnly},
+			"gateway-key": {Subject: "gateway", Role: api.RoleGateway},
+			"admin-key":   {Subject: "admin", Role: api.RoleAdmin},
+		}))},
+	}))
+
+	tests := map[string]struct {
+		method string
+		path   string
+		body   string
+		key    string
+		want   int
+	}{
+		"no credentials": {
+			method: http.MethodGet,
+			path:   "/token",
+			want:   http.StatusUnauthorized,
+		},
+		"invalid credentials": {
+			method: http.MethodGet,
+			path:   "/token",
+			key:    "wrong-key",
+			want:   http.StatusUnauthorized,
+		},
+		"read with read-only role": {
+			method: http.MethodGet,
+			path:   "/token",
+			key:    "read-key",
+			want:   http.StatusOK,
+		},
+		"operations log with read-only role": {
+			method: http.MethodGet,
+			path:   "/cs/cs001/operations",
+			key:    "read-key",
+			want:   http.StatusForbidden,
+		},
+		"operations log with admin role": {
+			method: http.MethodGet,
+			path:   "/cs/cs001/operations",
+			key:    "admin-key",
+			want:   http.StatusOK,
+		},
+		"charge station auth lookup with read-only role": {
+			method: http.MethodGet,
+			path:   "/cs/cs002/auth",
+			key:    "read-key",
+			want:   http.StatusForbidden,
+		},
+		"charge station auth lookup with gateway role": {
+			method: http.MethodGet,
+			path:   "/cs/cs002/auth",
+			key:    "gateway-key",
+			want:   http.StatusNotFound,
+		},
+		"gateway operation with read-only role": {
+			method: http.MethodPost,
+			path:   "/cs/cs001/auth/failure",
+			body:   `{}`,
+			key:    "read-key",
+			want:   http.StatusForbidden,
+		},
+		"gateway operation with gateway role": {
+			method: http.MethodPost,
+			path:   "/cs/cs001/auth/failure",
+			body:   `{}`,
+			key:    "gateway-key",
+			want:   http.StatusOK,
+		},
+		"admin operation with gateway role": {
+			method: http.MethodPost,
+			path:   "/cs/cs001",
+			body:   `{"securityProfile":0}`,
+			key:    "gateway-key",
+			want:   http.StatusForbidden,
+		},
+		"admin operation with read-only role": {
+			method: http.MethodPost,
+			path:   "/cs/cs001",
+			body:   `{"securityProfile":0}`,
+			key:    "read-key",
+			want:   http.StatusForbidden,
+		},
+		"admin operation with admin role": {
+			method: http.MethodPost,
+			path:   "/cs/cs001",
+			body:   `{"securityProfile":0}`,
+			key:    "admin-key",
+			want:   http.StatusCreated,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, tc.path, strings.NewReader(tc.body))
+			req.Header.Set("content-type", "application/json")
+			if tc.key != "" {
+				req.Header.Set("X-API-Key", tc.key)
+			}
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.want, rr.Result().StatusCode)
+		})
+	}
+}
+
+func TestAuthMiddlewareRequiresAdminForOperationWithoutScopes(t *testing.T) {
+	authenticator := api.NewApiKeyAuthenticator(map[string]api.Principal{
+		"operator-key": {Subject: "operator", Role: api.RoleOperator},
+		"admin-key":    {Subject: "admin", Role: api.RoleAdmin},
+	})
+	handler := api.NewAuthMiddleware(authenticator)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	for key, want := range map[string]int{
+		"":             http.StatusUnauthorized,
+		"operator-key": http.StatusForbidden,
+		"admin-key":    http.StatusOK,
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/unscoped", nil)
+		if key != "" {
+			req.Header.Set("X-API-Key", key)
+		}
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		assert.Equal(t, want, rr.Result().StatusCode, key)
+	}
+}
+
+func TestRequireRole(t *testing.T) {
+	authenticator := api.NewApiKeyAuthenticator(map[string]api.Principal{
+		"read-key":     {Subject: "reader", Role: api.RoleReadOnly},
+		"operator-key": {Subject: "operator", Role: api.RoleOperator},
+	})
+	handler := api.RequireRole(api.RoleOperator, authenticator)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "operator", api.PrincipalFromContext(r.Context()).Subject)
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	for key, want := range map[string]int{
+		"":             http.StatusUnauthorized,
+		"read-key":     http.StatusForbidden,
+		"operator-key": http.StatusOK,
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/adminui", nil)
+		if key != "" {
+			req.Header.Set("X-API-Key", key)
+		}
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		assert.Equal(t, want, rr.Result().StatusCode, key)
+	}
+}
+
+func signJwt(t *testing.T, subject, role string, expiry time.Time) string {
+	token := jwt.New()
+	require.NoError(t, token.Set(jwt.SubjectKey, subject))
+	require.NoError(t, token.Set(jwt.IssuerKey, "https://auth.example.com"))
+	require.NoError(t, token.Set(jwt.AudienceKey, "maeve-csms"))
+	require.NoError(t, token.Set(jwt.ExpirationKey, expiry))
+	require.NoError(t, token.Set("role", role))
+
+	signed, err := jwt.Sign(token, jwa.HS256, jwtSecret)
+	require.NoError(t, err)
+
+	return string(signed)
+}
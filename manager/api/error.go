@@ -43,3 +43,13 @@ var ErrNotFound = &ErrResponse{
 	HTTPStatusCode: http.StatusNotFound,
 	StatusText:     http.StatusText(http.StatusNotFound),
 }
+
+var ErrUnauthorized = &ErrResponse{
+	HTTPStatusCode: http.StatusUnauthorized,
+	StatusText:     http.StatusText(http.StatusUnauthorized),
+}
+
+var ErrForbidden = &ErrResponse{
+	HTTPStatusCode: http.StatusForbidden,
+	StatusText:     http.StatusText(http.StatusForbidden),
+}
@@ -2,7 +2,11 @@
 
 package api
 
-import "net/http"
+import (
+	"bytes"
+	"errors"
+	"net/http"
+)
 
 func (c ChargeStationAuth) Bind(r *http.Request) error {
 	return nil
@@ -21,6 +25,87 @@ func (c ChargeStationInstallCertificates) Bind(r *http.Request) error {
 }
 
 func (c ChargeStationTrigger) Bind(r *http.Request) error {
+	if c.ConnectorId != nil && c.EvseId == nil {
+		return errors.New("connectorId requires evseId")
+	}
+	return nil
+}
+
+func (c ChargeStationTriggerRequest) Bind(r *http.Request) error {
+	triggers, err := c.triggers()
+	if err != nil {
+		return err
+	}
+	for _, trigger := range triggers {
+		if err := trigger.Bind(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// triggers returns the requested triggers whether a single trigger or a sequence of
+// triggers was provided
+func (c ChargeStationTriggerRequest) triggers() (ChargeStationTriggers, error) {
+	raw, err := c.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	if trimmed := bytes.TrimSpace(raw); len(trimmed) > 0 && trimmed[0] == '[' {
+		return c.AsChargeStationTriggers()
+	}
+	trigger, err := c.AsChargeStationTrigger()
+	if err != nil {
+		return nil, err
+	}
+	return ChargeStationTriggers{trigger}, nil
+}
+
+func (c ChargeStationDisconnect) Bind(r *http.Request) error {
+	return nil
+}
+
+func (c ChargeStationOperation) Render(w http.ResponseWriter, r *http.Request) error {
+	return nil
+}
+
+func (f FailedOperation) Render(w http.ResponseWriter, r *http.Request) error {
+	return nil
+}
+
+func (c IssuedCertificate) Render(w http.ResponseWriter, r *http.Request) error {
+	return nil
+}
+
+func (c RootCertificate) Bind(r *http.Request) error {
+	return nil
+}
+
+func (c RootCertificate) Render(w http.ResponseWriter, r *http.Request) error {
+	return nil
+}
+
+func (c ChargeStationInstalledCertificates) Render(w http.ResponseWriter, r *http.Request) error {
+	return nil
+}
+
+func (c InstalledCertificateCompliance) Render(w http.ResponseWriter, r *http.Request) error {
+	return nil
+}
+
+func (c CertificateCampaignRequest) Bind(r *http.Request) error {
+	return nil
+}
+
+func (c CertificateCampaign) Render(w http.ResponseWriter, r *http.Request) error {
+	return nil
+}
+
+func (c Contract) Bind(r *http.Request) error {
+	return nil
+}
+
+func (c Contract) Render(w http.ResponseWriter, r *http.Request) error {
 	return nil
 }
 
@@ -40,6 +125,17 @@ func (t Certificate) Render(w http.ResponseWriter, r *http.Request) error {
 	return nil
 }
 
+func (c CertificateRevocationRequest) Bind(r *http.Request) error {
+	if c.Certificate == nil && c.CertificateHash == nil {
+		return errors.New("either certificate or certificateHash must be provided")
+	}
+	return nil
+}
+
+func (c CertificateRevocation) Render(w http.ResponseWriter, r *http.Request) error {
+	return nil
+}
+
 func (r Registration) Bind(req *http.Request) error {
 	return nil
 }
@@ -47,3 +143,31 @@ func (r Registration) Bind(req *http.Request) error {
 func (r Location) Bind(req *http.Request) error {
 	return nil
 }
+
+func (d OcpiDelivery) Render(w http.ResponseWriter, r *http.Request) error {
+	return nil
+}
+
+func (c ChargeStation) Render(w http.ResponseWriter, r *http.Request) error {
+	return nil
+}
+
+func (c ChargeStationTags) Bind(r *http.Request) error {
+	return nil
+}
+
+func (c ChargeStationConnection) Render(w http.ResponseWriter, r *http.Request) error {
+	return nil
+}
+
+func (a AuthFailure) Bind(r *http.Request) error {
+	return nil
+}
+
+func (a AuthLockoutStatus) Render(w http.ResponseWriter, r *http.Request) error {
+	return nil
+}
+
+func (a AuthLockout) Render(w http.ResponseWriter, r *http.Request) error {
+	return nil
+}
@@ -3,39 +3,102 @@
 package api
 
 import (
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
 	"fmt"
+	"github.com/google/uuid"
 	handlers "github.com/thoughtworks/maeve-csms/manager/handlers/ocpp201"
 	"github.com/thoughtworks/maeve-csms/manager/ocpi"
+	"io"
 	"net/http"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/go-chi/render"
 	"github.com/thoughtworks/maeve-csms/manager/ocpp"
+	"github.com/thoughtworks/maeve-csms/manager/ocpp/ocpp201"
+	"github.com/thoughtworks/maeve-csms/manager/services"
 	"github.com/thoughtworks/maeve-csms/manager/store"
+	"github.com/thoughtworks/maeve-csms/manager/transport"
+	"golang.org/x/exp/slog"
 	"k8s.io/utils/clock"
 )
 
+// SyncNotifier is told when changes have been queued for a charge station so that
+// they can be delivered without waiting for the next poll of the store.
+type SyncNotifier interface {
+	NotifyPending(ctx context.Context, chargeStationId string)
+}
+
 type Server struct {
-	store   store.Engine
-	clock   clock.PassiveClock
-	swagger *openapi3.T
-	ocpi    ocpi.Api
+	store          store.Engine
+	clock          clock.PassiveClock
+	swagger        *openapi3.T
+	ocpi           ocpi.Api
+	controlEmitter transport.ControlEmitter
+	authLockout    services.AuthLockoutService
+	syncNotifier   SyncNotifier
 }
 
-func NewServer(engine store.Engine, clock clock.PassiveClock, ocpi ocpi.Api) (*Server, error) {
+// NewServer creates the API server. The controlEmitter is used to request that
+// the gateway disconnects charge stations: it may be nil if the transport does not
+// support control messages.
+func NewServer(engine store.Engine, clock clock.PassiveClock, ocpi ocpi.Api, controlEmitter transport.ControlEmitter) (*Server, error) {
 	swagger, err := GetSwagger()
 	if err != nil {
 		return nil, err
 	}
 	return &Server{
-		store:   engine,
-		clock:   clock,
-		ocpi:    ocpi,
-		swagger: swagger,
+		store:          engine,
+		clock:          clock,
+		ocpi:           ocpi,
+		controlEmitter: controlEmitter,
+		swagger:        swagger,
+		authLockout: &services.StoreAuthLockoutService{
+			Store: engine,
+			Clock: clock,
+		},
 	}, nil
 }
 
+// SetSyncNotifier sets the notifier that is told when settings, certificates or
+// trigger messages are queued for a charge station.
+func (s *Server) SetSyncNotifier(syncNotifier SyncNotifier) {
+	s.syncNotifier = syncNotifier
+}
+
+func (s *Server) notifyPending(ctx context.Context, csId string) {
+	if s.syncNotifier != nil {
+		s.syncNotifier.NotifyPending(ctx, csId)
+	}
+}
+
+// recordRequest records a change that has been requested through the API in the charge
+// station's operation log along with the caller that requested it.
+func (s *Server) recordRequest(r *http.Request, csId string, event store.ChargeStationOperationEvent, operationType store.ChargeStationOperationType, subject string) {
+	var requestedBy string
+	if principal := PrincipalFromContext(r.Context()); principal != nil {
+		requestedBy = principal.Subject
+	}
+	err := s.store.AppendChargeStationOperation(r.Context(), &store.ChargeStationOperation{
+		ChargeStationId: csId,
+		Timestamp:       s.clock.Now().UTC(),
+		Event:           event,
+		Type:            operationType,
+		Subject:         subject,
+		RequestedBy:     requestedBy,
+	})
+	if err != nil {
+		slog.Warn("unable to record charge station operation", slog.String("chargeStationId", csId), "err", err)
+	}
+}
+
 func (s *Server) RegisterChargeStation(w http.ResponseWriter, r *http.Request, csId string) {
 	req := new(ChargeStationAuth)
 	if err := render.Bind(r, req); err != nil {
@@ -51,19 +114,220 @@ func (s *Server) RegisterChargeStation(w http.ResponseWriter, r *http.Request, c
 	if req.InvalidUsernameAllowed != nil {
 		invalidUsernameAllowed = *req.InvalidUsernameAllowed
 	}
-	err := s.store.SetChargeStationAuth(r.Context(), csId, &store.ChargeStationAuth{
+	previousAuth, err := s.store.LookupChargeStationAuth(r.Context(), csId)
+	if err != nil {
+		_ = render.Render(w, r, ErrInternalError(err))
+		return
+	}
+	auth := &store.ChargeStationAuth{
 		SecurityProfile:        store.SecurityProfile(req.SecurityProfile),
 		Base64SHA256Password:   pwd,
 		InvalidUsernameAllowed: invalidUsernameAllowed,
-	})
+	}
+	err = s.store.SetChargeStationAuth(r.Context(), csId, auth)
+	if err != nil {
+		_ = render.Render(w, r, ErrInternalError(err))
+		return
+	}
+
+	// a charge station that is connected using the old credentials must reconnect
+	// so that it is authenticated with the new ones
+	if previousAuth != nil && *previousAuth != *auth && s.controlEmitter != nil {
+		err = s.controlEmitter.EmitControl(r.Context(), csId, &transport.ControlMessage{
+			Type:   transport.ControlDisconnect,
+			Reason: "credentials changed",
+		})
+		if err != nil {
+			slog.Error("requesting disconnect after credentials changed", "csId", csId, "err", err)
+		}
+	}
+
+	details, err := s.store.LookupChargeStationDetails(r.Context(), csId)
 	if err != nil {
 		_ = render.Render(w, r, ErrInternalError(err))
 		return
 	}
+	if details == nil {
+		err = s.store.SetChargeStationDetails(r.Context(), csId, &store.ChargeStationDetails{
+			RegisteredAt: s.clock.Now().UTC(),
+		})
+		if err != nil {
+			_ = render.Render(w, r, ErrInternalError(err))
+			return
+		}
+	}
 
 	w.WriteHeader(http.StatusCreated)
 }
 
+func newChargeStation(csId string, details *store.ChargeStationDetails) *ChargeStation {
+	resp := &ChargeStation{
+		Id: csId,
+	}
+	if details == nil {
+		return resp
+	}
+	if details.Vendor != "" {
+		resp.Vendor = &details.Vendor
+	}
+	if details.Model != "" {
+		resp.Model = &details.Model
+	}
+	if details.SerialNumber != "" {
+		resp.SerialNumber = &details.SerialNumber
+	}
+	if details.FirmwareVersion != "" {
+		resp.FirmwareVersion = &details.FirmwareVersion
+	}
+	if len(details.Tags) > 0 {
+		resp.Tags = &details.Tags
+	}
+	if !details.RegisteredAt.IsZero() {
+		resp.RegisteredAt = &details.RegisteredAt
+	}
+	if !details.LastSeen.IsZero() {
+		resp.LastSeen = &details.LastSeen
+	}
+	return resp
+}
+
+func (s *Server) ListChargeStations(w http.ResponseWriter, r *http.Request, params ListChargeStationsParams) {
+	var filter store.ChargeStationFilter
+	offset := 0
+	limit := 20
+
+	if params.Vendor != nil {
+		filter.Vendor = *params.Vendor
+	}
+	if params.Model != nil {
+		filter.Model = *params.Model
+	}
+	if params.FirmwareVersion != nil {
+		filter.FirmwareVersion = *params.FirmwareVersion
+	}
+	if params.Tag != nil {
+		filter.Tag = *params.Tag
+	}
+	if params.Offset != nil {
+		offset = *params.Offset
+	}
+	if params.Limit != nil {
+		limit = *params.Limit
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	chargeStations, err := s.store.ListChargeStationDetails(r.Context(), filter, offset, limit)
+	if err != nil {
+		_ = render.Render(w, r, ErrInternalError(err))
+		return
+	}
+
+	var resp = make([]render.Renderer, len(chargeStations))
+	for i, details := range chargeStations {
+		resp[i] = newChargeStation(details.ChargeStationId, details)
+	}
+	_ = render.RenderList(w, r, resp)
+}
+
+func (s *Server) LookupChargeStation(w http.ResponseWriter, r *http.Request, csId string) {
+	details, err := s.store.LookupChargeStationDetails(r.Context(), csId)
+	if err != nil {
+		_ = render.Render(w, r, ErrInternalError(err))
+		return
+	}
+	auth, err := s.store.LookupChargeStationAuth(r.Context(), csId)
+	if err != nil {
+		_ = render.Render(w, r, ErrInternalError(err))
+		return
+	}
+	if details == nil && auth == nil {
+		_ = render.Render(w, r, ErrNotFound)
+		return
+	}
+
+	resp := newChargeStation(csId, details)
+	if auth != nil {
+		securityProfile := int(auth.SecurityProfile)
+		resp.SecurityProfile = &securityProfile
+	}
+
+	runtimeDetails, err := s.store.LookupChargeStationRuntimeDetails(r.Context(), csId)
+	if err != nil {
+		_ = render.Render(w, r, ErrInternalError(err))
+		return
+	}
+	if runtimeDetails != nil && runtimeDetails.OcppVersion != "" {
+		resp.OcppVersion = &runtimeDetails.OcppVersion
+	}
+
+	_ = render.Render(w, r, resp)
+}
+
+func (s *Server) DeleteChargeStation(w http.ResponseWriter, r *http.Request, csId string) {
+	details, err := s.store.LookupChargeStationDetails(r.Context(), csId)
+	if err != nil {
+		_ = render.Render(w, r, ErrInternalError(err))
+		return
+	}
+	auth, err := s.store.LookupChargeStationAuth(r.Context(), csId)
+	if err != nil {
+		_ = render.Render(w, r, ErrInternalError(err))
+		return
+	}
+	if details == nil && auth == nil {
+		_ = render.Render(w, r, ErrNotFound)
+		return
+	}
+
+	err = s.store.DeleteChargeStation(r.Context(), csId)
+	if err != nil {
+		_ = render.Render(w, r, ErrInternalError(err))
+		return
+	}
+
+	// a charge station that is connected must not remain connected once it has been deleted
+	if s.controlEmitter != nil {
+		err = s.controlEmitter.EmitControl(r.Context(), csId, &transport.ControlMessage{
+			Type:   transport.ControlDisconnect,
+			Reason: "charge station deleted",
+		})
+		if err != nil {
+			slog.Error("requesting disconnect after charge station deleted", "csId", csId, "err", err)
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) SetChargeStationTags(w http.ResponseWriter, r *http.Request, csId string) {
+	req := new(ChargeStationTags)
+	if err := render.Bind(r, req); err != nil {
+		_ = render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	details, err := s.store.LookupChargeStationDetails(r.Context(), csId)
+	if err != nil {
+		_ = render.Render(w, r, ErrInternalError(err))
+		return
+	}
+	if details == nil {
+		_ = render.Render(w, r, ErrNotFound)
+		return
+	}
+
+	details.Tags = req.Tags
+	err = s.store.SetChargeStationDetails(r.Context(), csId, details)
+	if err != nil {
+		_ = render.Render(w, r, ErrInternalError(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
 func (s *Server) ReconfigureChargeStation(w http.ResponseWriter, r *http.Request, csId string) {
 	req := new(ChargeStationSettings)
 	if err := render.Bind(r, req); err != nil {
@@ -86,6 +350,17 @@ func (s *Server) ReconfigureChargeStation(w http.ResponseWriter, r *http.Request
 		_ = render.Render(w, r, ErrInternalError(err))
 		return
 	}
+
+	names := make([]string, 0, len(chargeStationSettings))
+	for name := range chargeStationSettings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		s.recordRequest(r, csId, store.ChargeStationOperationRequested, store.ChargeStationOperationTypeSetting, name)
+	}
+
+	s.notifyPending(r.Context(), csId)
 }
 
 func (s *Server) InstallChargeStationCertificates(w http.ResponseWriter, r *http.Request, csId string) {
@@ -124,6 +399,14 @@ func (s *Server) InstallChargeStationCertificates(w http.ResponseWriter, r *http
 		_ = render.Render(w, r, ErrInternalError(err))
 		return
 	}
+
+	for _, cert := range certs {
+		if cert.CertificateInstallationStatus == store.CertificateInstallationPending {
+			s.recordRequest(r, csId, store.ChargeStationOperationRequested, store.ChargeStationOperationTypeCertificate, cert.CertificateId)
+		}
+	}
+
+	s.notifyPending(r.Context(), csId)
 }
 
 func (s *Server) LookupChargeStationAuth(w http.ResponseWriter, r *http.Request, csId string) {
@@ -148,106 +431,1117 @@ func (s *Server) LookupChargeStationAuth(w http.ResponseWriter, r *http.Request,
 	_ = render.Render(w, r, resp)
 }
 
-func (s *Server) TriggerChargeStation(w http.ResponseWriter, r *http.Request, csId string) {
-	req := new(ChargeStationTrigger)
+func newChargeStationConnection(connection *store.ChargeStationConnection) *ChargeStationConnection {
+	securityProfile := int(connection.SecurityProfile)
+	resp := &ChargeStationConnection{
+		Id:              connection.ChargeStationId,
+		Connected:       connection.Connected,
+		SecurityProfile: &securityProfile,
+	}
+	if connection.GatewayId != "" {
+		resp.GatewayId = &connection.GatewayId
+	}
+	if connection.OcppVersion != "" {
+		resp.OcppVersion = &connection.OcppVersion
+	}
+	if connection.RemoteAddr != "" {
+		resp.RemoteAddr = &connection.RemoteAddr
+	}
+	if !connection.ConnectedAt.IsZero() {
+		resp.ConnectedAt = &connection.ConnectedAt
+	}
+	if !connection.Connected && !connection.DisconnectedAt.IsZero() {
+		resp.DisconnectedAt = &connection.DisconnectedAt
+	}
+	return resp
+}
+
+func (s *Server) LookupChargeStationConnection(w http.ResponseWriter, r *http.Request, csId string) {
+	connection, err := s.store.LookupChargeStationConnection(r.Context(), csId)
+	if err != nil {
+		_ = render.Render(w, r, ErrInternalError(err))
+		return
+	}
+	if connection == nil {
+		_ = render.Render(w, r, ErrNotFound)
+		return
+	}
+
+	_ = render.Render(w, r, newChargeStationConnection(connection))
+}
+
+func (s *Server) ListConnectedChargeStations(w http.ResponseWriter, r *http.Request, params ListConnectedChargeStationsParams) {
+	offset := 0
+	limit := 20
+
+	if params.Offset != nil {
+		offset = *params.Offset
+	}
+	if params.Limit != nil {
+		limit = *params.Limit
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	connections, err := s.store.ListConnectedChargeStations(r.Context(), offset, limit)
+	if err != nil {
+		_ = render.Render(w, r, ErrInternalError(err))
+		return
+	}
+
+	var resp = make([]render.Renderer, len(connections))
+	for i, connection := range connections {
+		resp[i] = newChargeStationConnection(connection)
+	}
+	_ = render.RenderList(w, r, resp)
+}
+
+func newAuthLockoutStatus(status *services.AuthLockoutStatus) *AuthLockoutStatus {
+	resp := new(AuthLockoutStatus)
+	if !status.ChargeStationLockedUntil.IsZero() {
+		resp.ChargeStationLockedUntil = &status.ChargeStationLockedUntil
+	}
+	if !status.RemoteAddrLockedUntil.IsZero() {
+		resp.RemoteAddrLockedUntil = &status.RemoteAddrLockedUntil
+	}
+	return resp
+}
+
+func (s *Server) RecordChargeStationAuthFailure(w http.ResponseWriter, r *http.Request, csId string) {
+	req := new(AuthFailure)
 	if err := render.Bind(r, req); err != nil {
 		_ = render.Render(w, r, ErrInvalidRequest(err))
 		return
 	}
 
-	err := s.store.SetChargeStationTriggerMessage(r.Context(), csId, &store.ChargeStationTriggerMessage{
-		TriggerMessage: store.TriggerMessage(req.Trigger),
-		TriggerStatus:  store.TriggerStatusPending,
-	})
+	var remoteAddr string
+	if req.RemoteAddr != nil {
+		remoteAddr = *req.RemoteAddr
+	}
+
+	status, err := s.authLockout.RecordFailure(r.Context(), csId, remoteAddr)
+	if err != nil {
+		_ = render.Render(w, r, ErrInternalError(err))
+		return
+	}
+
+	_ = render.Render(w, r, newAuthLockoutStatus(status))
+}
+
+func (s *Server) LookupChargeStationAuthLockout(w http.ResponseWriter, r *http.Request, csId string, params LookupChargeStationAuthLockoutParams) {
+	var remoteAddr string
+	if params.RemoteAddr != nil {
+		remoteAddr = *params.RemoteAddr
+	}
+
+	status, err := s.authLockout.LookupStatus(r.Context(), csId, remoteAddr)
+	if err != nil {
+		_ = render.Render(w, r, ErrInternalError(err))
+		return
+	}
+
+	_ = render.Render(w, r, newAuthLockoutStatus(status))
+}
+
+func (s *Server) ListAuthLockouts(w http.ResponseWriter, r *http.Request, params ListAuthLockoutsParams) {
+	offset := 0
+	limit := 20
+
+	if params.Offset != nil {
+		offset = *params.Offset
+	}
+	if params.Limit != nil {
+		limit = *params.Limit
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	lockouts, err := s.store.ListAuthLockouts(r.Context(), s.clock.Now(), offset, limit)
+	if err != nil {
+		_ = render.Render(w, r, ErrInternalError(err))
+		return
+	}
+
+	var resp = make([]render.Renderer, len(lockouts))
+	for i, lockout := range lockouts {
+		l := &AuthLockout{
+			Type:        AuthLockoutType(lockout.Type),
+			Id:          lockout.Id,
+			Lockouts:    lockout.Lockouts,
+			LockedUntil: lockout.LockedUntil,
+		}
+		if !lockout.LastFailureAt.IsZero() {
+			l.LastFailureAt = &lockout.LastFailureAt
+		}
+		resp[i] = l
+	}
+	_ = render.RenderList(w, r, resp)
+}
+
+func (s *Server) DeleteAuthLockout(w http.ResponseWriter, r *http.Request, lockoutType AuthLockoutType, lockoutId string) {
+	err := s.store.DeleteAuthLockout(r.Context(), store.AuthLockoutType(lockoutType), lockoutId)
+	if err != nil {
+		_ = render.Render(w, r, ErrInternalError(err))
+		return
+	}
+
+	slog.Info("auth lockout cleared", "type", lockoutType, "id", lockoutId)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) DisconnectChargeStation(w http.ResponseWriter, r *http.Request, csId string) {
+	// the request body is optional
+	req := new(ChargeStationDisconnect)
+	if r.ContentLength != 0 {
+		if err := render.Bind(r, req); err != nil && !errors.Is(err, io.EOF) {
+			_ = render.Render(w, r, ErrInvalidRequest(err))
+			return
+		}
+	}
+
+	if s.controlEmitter == nil {
+		_ = render.Render(w, r, ErrInternalError(errors.New("the transport does not support disconnecting charge stations")))
+		return
+	}
+
+	msg := &transport.ControlMessage{
+		Type: transport.ControlDisconnect,
+	}
+	if req.Reason != nil {
+		msg.Reason = *req.Reason
+	}
+	err := s.controlEmitter.EmitControl(r.Context(), csId, msg)
+	if err != nil {
+		_ = render.Render(w, r, ErrInternalError(err))
+		return
+	}
+
+	slog.Info("requested charge station disconnect", "csId", csId, "reason", msg.Reason)
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (s *Server) TriggerChargeStation(w http.ResponseWriter, r *http.Request, csId string) {
+	req := new(ChargeStationTriggerRequest)
+	if err := render.Bind(r, req); err != nil {
+		_ = render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	triggers, err := req.triggers()
+	if err != nil {
+		_ = render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	var triggerMessages []*store.ChargeStationTriggerMessage
+	for _, trigger := range triggers {
+		triggerMessages = append(triggerMessages, &store.ChargeStationTriggerMessage{
+			TriggerMessage: store.TriggerMessage(trigger.Trigger),
+			EvseId:         trigger.EvseId,
+			ConnectorId:    trigger.ConnectorId,
+			TriggerStatus:  store.TriggerStatusPending,
+		})
+	}
+
+	err = s.store.UpdateChargeStationTriggerMessages(r.Context(), csId, &store.ChargeStationTriggerMessages{
+		ChargeStationId: csId,
+		TriggerMessages: triggerMessages,
+	})
+	if err != nil {
+		_ = render.Render(w, r, ErrInternalError(err))
+		return
+	}
+
+	for _, triggerMessage := range triggerMessages {
+		s.recordRequest(r, csId, store.ChargeStationOperationRequested, store.ChargeStationOperationTypeTrigger, triggerMessage.Name())
+	}
+
+	s.notifyPending(r.Context(), csId)
+
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (s *Server) ListFailedChargeStationOperations(w http.ResponseWriter, r *http.Request, csId string) {
+	failed, err := s.failedOperations(r.Context(), csId)
+	if err != nil {
+		_ = render.Render(w, r, ErrInternalError(err))
+		return
+	}
+
+	var resp = make([]render.Renderer, len(failed.operations))
+	for i, operation := range failed.operations {
+		resp[i] = operation
+	}
+	_ = render.RenderList(w, r, resp)
+}
+
+func (s *Server) RearmFailedChargeStationOperations(w http.ResponseWriter, r *http.Request, csId string) {
+	failed, err := s.failedOperations(r.Context(), csId)
+	if err != nil {
+		_ = render.Render(w, r, ErrInternalError(err))
+		return
+	}
+
+	if len(failed.settings) > 0 {
+		settings := make(map[string]*store.ChargeStationSetting, len(failed.settings))
+		for name, setting := range failed.settings {
+			settings[name] = &store.ChargeStationSetting{
+				Value:  setting.Value,
+				Status: store.ChargeStationSettingStatusPending,
+			}
+		}
+		err = s.store.UpdateChargeStationSettings(r.Context(), csId, &store.ChargeStationSettings{
+			Settings: settings,
+		})
+		if err != nil {
+			_ = render.Render(w, r, ErrInternalError(err))
+			return
+		}
+	}
+
+	if len(failed.certificates) > 0 {
+		var certs []*store.ChargeStationInstallCertificate
+		for _, cert := range failed.certificates {
+			certs = append(certs, &store.ChargeStationInstallCertificate{
+				CertificateType:               cert.CertificateType,
+				CertificateId:                 cert.CertificateId,
+				CertificateData:               cert.CertificateData,
+				CertificateInstallationStatus: store.CertificateInstallationPending,
+			})
+		}
+		err = s.store.UpdateChargeStationInstallCertificates(r.Context(), csId, &store.ChargeStationInstallCertificates{
+			ChargeStationId: csId,
+			Certificates:    certs,
+		})
+		if err != nil {
+			_ = render.Render(w, r, ErrInternalError(err))
+			return
+		}
+	}
+
+	if len(failed.triggers) > 0 {
+		var triggerMessages []*store.ChargeStationTriggerMessage
+		for _, trigger := range failed.triggers {
+			triggerMessages = append(triggerMessages, &store.ChargeStationTriggerMessage{
+				TriggerMessage: trigger.TriggerMessage,
+				EvseId:         trigger.EvseId,
+				ConnectorId:    trigger.ConnectorId,
+				TriggerStatus:  store.TriggerStatusPending,
+			})
+		}
+		err = s.store.UpdateChargeStationTriggerMessages(r.Context(), csId, &store.ChargeStationTriggerMessages{
+			ChargeStationId: csId,
+			TriggerMessages: triggerMessages,
+		})
+		if err != nil {
+			_ = render.Render(w, r, ErrInternalError(err))
+			return
+		}
+	}
+
+	for _, operation := range failed.operations {
+		s.recordRequest(r, csId, store.ChargeStationOperationRearmed, store.ChargeStationOperationType(operation.Type), operation.Id)
+	}
+	if len(failed.operations) > 0 {
+		s.notifyPending(r.Context(), csId)
+	}
+
+	var resp = make([]render.Renderer, len(failed.operations))
+	for i, operation := range failed.operations {
+		resp[i] = operation
+	}
+	_ = render.RenderList(w, r, resp)
+}
+
+func (s *Server) ListChargeStationOperations(w http.ResponseWriter, r *http.Request, csId string, params ListChargeStationOperationsParams) {
+	offset := 0
+	limit := 20
+
+	if params.Offset != nil {
+		offset = *params.Offset
+	}
+	if params.Limit != nil {
+		limit = *params.Limit
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	operations, err := s.store.ListChargeStationOperations(r.Context(), csId, offset, limit)
+	if err != nil {
+		_ = render.Render(w, r, ErrInternalError(err))
+		return
+	}
+
+	var resp = make([]render.Renderer, len(operations))
+	for i, operation := range operations {
+		resp[i] = newChargeStationOperation(operation)
+	}
+	_ = render.RenderList(w, r, resp)
+}
+
+func newChargeStationOperation(operation *store.ChargeStationOperation) *ChargeStationOperation {
+	resp := &ChargeStationOperation{
+		Event:     ChargeStationOperationEvent(operation.Event),
+		Timestamp: operation.Timestamp,
+	}
+	if operation.Type != "" {
+		operationType := ChargeStationOperationType(operation.Type)
+		resp.Type = &operationType
+	}
+	if operation.Subject != "" {
+		resp.Subject = &operation.Subject
+	}
+	if operation.Action != "" {
+		resp.Action = &operation.Action
+	}
+	if operation.MessageId != "" {
+		resp.MessageId = &operation.MessageId
+	}
+	if operation.Attempt != 0 {
+		resp.Attempt = &operation.Attempt
+	}
+	if operation.RequestedBy != "" {
+		resp.RequestedBy = &operation.RequestedBy
+	}
+	if operation.Result != "" {
+		resp.Result = &operation.Result
+	}
+	if operation.Payload != "" {
+		resp.Payload = &operation.Payload
+	}
+	return resp
+}
+
+type failedOperations struct {
+	operations   []*FailedOperation
+	settings     map[string]*store.ChargeStationSetting
+	certificates []*store.ChargeStationInstallCertificate
+	triggers     []*store.ChargeStationTriggerMessage
+}
+
+// failedOperations finds the settings, certificates and trigger messages for the charge
+// station that have been given up on by the sync process
+func (s *Server) failedOperations(ctx context.Context, csId string) (*failedOperations, error) {
+	failed := &failedOperations{
+		operations: []*FailedOperation{},
+		settings:   make(map[string]*store.ChargeStationSetting),
+	}
+
+	settings, err := s.store.LookupChargeStationSettings(ctx, csId)
+	if err != nil {
+		return nil, err
+	}
+	if settings != nil {
+		names := make([]string, 0, len(settings.Settings))
+		for name, setting := range settings.Settings {
+			if setting.Status == store.ChargeStationSettingStatusFailed {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			setting := settings.Settings[name]
+			failed.settings[name] = setting
+			failed.operations = append(failed.operations, &FailedOperation{
+				Type:     FailedOperationTypeSetting,
+				Id:       name,
+				Attempts: setting.Attempts,
+			})
+		}
+	}
+
+	certificates, err := s.store.LookupChargeStationInstallCertificates(ctx, csId)
+	if err != nil {
+		return nil, err
+	}
+	if certificates != nil {
+		for _, cert := range certificates.Certificates {
+			if cert.CertificateInstallationStatus == store.CertificateInstallationFailed {
+				failed.certificates = append(failed.certificates, cert)
+				failed.operations = append(failed.operations, &FailedOperation{
+					Type:     FailedOperationTypeCertificate,
+					Id:       cert.CertificateId,
+					Attempts: cert.Attempts,
+				})
+			}
+		}
+	}
+
+	triggerMessages, err := s.store.LookupChargeStationTriggerMessages(ctx, csId)
+	if err != nil {
+		return nil, err
+	}
+	if triggerMessages != nil {
+		for _, trigger := range triggerMessages.TriggerMessages {
+			if trigger.TriggerStatus == store.TriggerStatusFailed {
+				failed.triggers = append(failed.triggers, trigger)
+				failed.operations = append(failed.operations, &FailedOperation{
+					Type:     FailedOperationTypeTrigger,
+					Id:       trigger.Name(),
+					Attempts: trigger.Attempts,
+				})
+			}
+		}
+	}
+
+	return failed, nil
+}
+
+func (s *Server) SetToken(w http.ResponseWriter, r *http.Request) {
+	req := new(Token)
+	if err := render.Bind(r, req); err != nil {
+		_ = render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	normContractId, err := ocpp.NormalizeEmaid(req.ContractId)
+	if err != nil {
+		_ = render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	err = s.store.SetToken(r.Context(), &store.Token{
+		CountryCode:  req.CountryCode,
+		PartyId:      req.PartyId,
+		Type:         string(req.Type),
+		Uid:          req.Uid,
+		ContractId:   normContractId,
+		VisualNumber: req.VisualNumber,
+		Issuer:       req.Issuer,
+		GroupId:      req.GroupId,
+		Valid:        req.Valid,
+		LanguageCode: req.LanguageCode,
+		CacheMode:    string(req.CacheMode),
+		LastUpdated:  s.clock.Now().Format(time.RFC3339),
+	})
+	if err != nil {
+		_ = render.Render(w, r, ErrInternalError(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+}
+
+func newToken(tok *store.Token) (*Token, error) {
+	lastUpdated, err := time.Parse(time.RFC3339, tok.LastUpdated)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Token{
+		CountryCode:  tok.CountryCode,
+		PartyId:      tok.PartyId,
+		Type:         TokenType(tok.Type),
+		Uid:          tok.Uid,
+		ContractId:   tok.ContractId,
+		VisualNumber: tok.VisualNumber,
+		Issuer:       tok.Issuer,
+		GroupId:      tok.GroupId,
+		Valid:        tok.Valid,
+		LanguageCode: tok.LanguageCode,
+		CacheMode:    TokenCacheMode(tok.CacheMode),
+		LastUpdated:  &lastUpdated,
+	}, nil
+}
+
+func (s *Server) LookupToken(w http.ResponseWriter, r *http.Request, tokenUid string) {
+	tok, err := s.store.LookupToken(r.Context(), tokenUid)
+	if err != nil {
+		_ = render.Render(w, r, ErrInternalError(err))
+		return
+	}
+	if tok == nil {
+		_ = render.Render(w, r, ErrNotFound)
+		return
+	}
+
+	resp, err := newToken(tok)
+	if err != nil {
+		_ = render.Render(w, r, ErrInternalError(err))
+		return
+	}
+
+	_ = render.Render(w, r, resp)
+}
+
+func (s *Server) ListTokens(w http.ResponseWriter, r *http.Request, params ListTokensParams) {
+	offset := 0
+	limit := 20
+
+	if params.Offset != nil {
+		offset = *params.Offset
+	}
+	if params.Limit != nil {
+		limit = *params.Limit
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	tokens, err := s.store.ListTokens(r.Context(), offset, limit)
+	if err != nil {
+		_ = render.Render(w, r, ErrInternalError(err))
+		return
+	}
+
+	var resp = make([]render.Renderer, len(tokens))
+	for i, tok := range tokens {
+		resp[i], err = newToken(tok)
+		if err != nil {
+			_ = render.Render(w, r, ErrInternalError(err))
+			return
+		}
+	}
+	_ = render.RenderList(w, r, resp)
+}
+
+func (s *Server) UploadCertificate(w http.ResponseWriter, r *http.Request) {
+	req := new(Certificate)
+	if err := render.Bind(r, req); err != nil {
+		_ = render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	err := s.store.SetCertificate(r.Context(), req.Certificate)
+	if err != nil {
+		_ = render.Render(w, r, ErrInternalError(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (s *Server) DeleteCertificate(w http.ResponseWriter, r *http.Request, certificateHash string) {
+	err := s.store.DeleteCertificate(r.Context(), certificateHash)
+	if err != nil {
+		_ = render.Render(w, r, ErrInternalError(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) LookupCertificate(w http.ResponseWriter, r *http.Request, certificateHash string) {
+	cert, err := s.store.LookupCertificate(r.Context(), certificateHash)
+	if err != nil {
+		_ = render.Render(w, r, ErrInternalError(err))
+		return
+	}
+	if cert == "" {
+		_ = render.Render(w, r, ErrNotFound)
+		return
+	}
+
+	resp := &Certificate{
+		Certificate: cert,
+	}
+	_ = render.Render(w, r, resp)
+}
+
+func (s *Server) RevokeChargeStationCertificate(w http.ResponseWriter, r *http.Request, csId string) {
+	req := new(CertificateRevocationRequest)
+	if err := render.Bind(r, req); err != nil {
+		_ = render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	var certificateHash string
+	if req.Certificate != nil {
+		var err error
+		certificateHash, err = getPEMCertificateHash(*req.Certificate)
+		if err != nil {
+			_ = render.Render(w, r, ErrInvalidRequest(err))
+			return
+		}
+	} else {
+		certificateHash = normalizeCertificateHash(*req.CertificateHash)
+	}
+
+	var reason string
+	if req.Reason != nil {
+		reason = *req.Reason
+	}
+
+	err := s.store.SetCertificateRevocation(r.Context(), &store.CertificateRevocation{
+		CertificateHash: certificateHash,
+		ChargeStationId: csId,
+		Reason:          reason,
+		RevokedAt:       s.clock.Now().UTC(),
+	})
+	if err != nil {
+		_ = render.Render(w, r, ErrInternalError(err))
+		return
+	}
+
+	slog.Warn("security event: charge station certificate revoked", "csId", csId,
+		"certificateHash", certificateHash, "reason", reason)
+
+	// a charge station that is connected using the revoked certificate must reconnect so
+	// that the revocation is checked
+	if s.controlEmitter != nil {
+		err = s.controlEmitter.EmitControl(r.Context(), csId, &transport.ControlMessage{
+			Type:   transport.ControlDisconnect,
+			Reason: "certificate revoked",
+		})
+		if err != nil {
+			slog.Error("requesting disconnect after certificate revoked", "csId", csId, "err", err)
+		}
+	}
+
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (s *Server) LookupCertificateRevocation(w http.ResponseWriter, r *http.Request, certificateHash string) {
+	revocation, err := s.store.LookupCertificateRevocation(r.Context(), normalizeCertificateHash(certificateHash))
+	if err != nil {
+		_ = render.Render(w, r, ErrInternalError(err))
+		return
+	}
+	if revocation == nil {
+		_ = render.Render(w, r, ErrNotFound)
+		return
+	}
+
+	resp := &CertificateRevocation{
+		CertificateHash: revocation.CertificateHash,
+		CsId:            revocation.ChargeStationId,
+		RevokedAt:       revocation.RevokedAt,
+	}
+	if revocation.Reason != "" {
+		resp.Reason = &revocation.Reason
+	}
+	_ = render.Render(w, r, resp)
+}
+
+func (s *Server) DeleteCertificateRevocation(w http.ResponseWriter, r *http.Request, certificateHash string) {
+	certificateHash = normalizeCertificateHash(certificateHash)
+	err := s.store.DeleteCertificateRevocation(r.Context(), certificateHash)
+	if err != nil {
+		_ = render.Render(w, r, ErrInternalError(err))
+		return
+	}
+
+	slog.Info("certificate revocation deleted", "certificateHash", certificateHash)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) ListExpiringCertificates(w http.ResponseWriter, r *http.Request, params ListExpiringCertificatesParams) {
+	days := 30
+	offset := 0
+	limit := 20
+
+	if params.Days != nil {
+		days = *params.Days
+	}
+	if params.Offset != nil {
+		offset = *params.Offset
+	}
+	if params.Limit != nil {
+		limit = *params.Limit
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	expiresBefore := s.clock.Now().Add(time.Duration(days) * 24 * time.Hour)
+	certificates, err := s.store.ListExpiringIssuedCertificates(r.Context(), expiresBefore, offset, limit)
+	if err != nil {
+		_ = render.Render(w, r, ErrInternalError(err))
+		return
+	}
+
+	var resp = make([]render.Renderer, len(certificates))
+	for i, certificate := range certificates {
+		resp[i] = newIssuedCertificate(certificate)
+	}
+	_ = render.RenderList(w, r, resp)
+}
+
+func newIssuedCertificate(certificate *store.IssuedCertificate) *IssuedCertificate {
+	resp := &IssuedCertificate{
+		CertificateId:   certificate.CertificateId,
+		CsId:            certificate.ChargeStationId,
+		CertificateType: IssuedCertificateCertificateTypeV2G,
+		IssuedAt:        certificate.IssuedAt,
+		NotAfter:        certificate.NotAfter,
+	}
+	if certificate.CertificateType == store.CertificateTypeChargeStation {
+		resp.CertificateType = IssuedCertificateCertificateTypeChargeStation
+	}
+	if certificate.SerialNumber != "" {
+		resp.SerialNumber = &certificate.SerialNumber
+	}
+	if !certificate.RenewalRequestedAt.IsZero() {
+		resp.RenewalRequestedAt = &certificate.RenewalRequestedAt
+	}
+	return resp
+}
+
+func (s *Server) SetRootCertificate(w http.ResponseWriter, r *http.Request) {
+	req := new(RootCertificate)
+	if err := render.Bind(r, req); err != nil {
+		_ = render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	certificateId, err := handlers.GetCertificateId(req.Certificate)
+	if err == nil {
+		// root certificates are matched with installed certificates using their hash data
+		_, err = handlers.GetCertificateHashData(req.Certificate, ocpp201.HashAlgorithmEnumTypeSHA256)
+	}
+	if err != nil {
+		_ = render.Render(w, r, ErrInvalidRequest(fmt.Errorf("invalid certificate: %w", err)))
+		return
+	}
+
+	err = s.store.SetRootCertificate(r.Context(), &store.RootCertificate{
+		CertificateId:   certificateId,
+		CertificateType: store.CertificateType(req.CertificateType),
+		CertificateData: req.Certificate,
+		Revoked:         req.Revoked != nil && *req.Revoked,
+	})
+	if err != nil {
+		_ = render.Render(w, r, ErrInternalError(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (s *Server) ListRootCertificates(w http.ResponseWriter, r *http.Request) {
+	certificates, err := s.store.ListRootCertificates(r.Context())
+	if err != nil {
+		_ = render.Render(w, r, ErrInternalError(err))
+		return
+	}
+
+	var resp = make([]render.Renderer, len(certificates))
+	for i, certificate := range certificates {
+		rootCertificate := &RootCertificate{
+			CertificateId:   &certificate.CertificateId,
+			CertificateType: RootCertificateCertificateType(certificate.CertificateType),
+			Certificate:     certificate.CertificateData,
+		}
+		if certificate.Revoked {
+			rootCertificate.Revoked = &certificate.Revoked
+		}
+		resp[i] = rootCertificate
+	}
+	_ = render.RenderList(w, r, resp)
+}
+
+func (s *Server) DeleteRootCertificate(w http.ResponseWriter, r *http.Request, certificateId string) {
+	err := s.store.DeleteRootCertificate(r.Context(), certificateId)
+	if err != nil {
+		_ = render.Render(w, r, ErrInternalError(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) LookupInstalledCertificates(w http.ResponseWriter, r *http.Request, csId string) {
+	installed, err := s.store.LookupChargeStationInstalledCertificates(r.Context(), csId)
+	if err != nil {
+		_ = render.Render(w, r, ErrInternalError(err))
+		return
+	}
+	if installed == nil {
+		_ = render.Render(w, r, ErrNotFound)
+		return
+	}
+
+	resp := &ChargeStationInstalledCertificates{
+		CsId:                    csId,
+		Certificates:            make([]InstalledCertificate, len(installed.Certificates)),
+		MissingRootCertificates: []string{},
+		RevokedRootCertificates: []string{},
+	}
+	for i, certificate := range installed.Certificates {
+		resp.Certificates[i] = InstalledCertificate{
+			CertificateType: InstalledCertificateCertificateType(certificate.CertificateType),
+			HashAlgorithm:   certificate.HashAlgorithm,
+			IssuerNameHash:  certificate.IssuerNameHash,
+			IssuerKeyHash:   certificate.IssuerKeyHash,
+			SerialNumber:    certificate.SerialNumber,
+		}
+		if certificate.DeletionStatus != "" {
+			deletionStatus := InstalledCertificateDeletionStatus(certificate.DeletionStatus)
+			resp.Certificates[i].DeletionStatus = &deletionStatus
+		}
+	}
+	if !installed.RequestedAt.IsZero() {
+		resp.RequestedAt = &installed.RequestedAt
+	}
+	if !installed.ReportedAt.IsZero() {
+		resp.ReportedAt = &installed.ReportedAt
+
+		roots, err := s.store.ListRootCertificates(r.Context())
+		if err != nil {
+			_ = render.Render(w, r, ErrInternalError(err))
+			return
+		}
+		compliance, err := handlers.CheckInstalledCertificates(installed, roots)
+		if err != nil {
+			_ = render.Render(w, r, ErrInternalError(err))
+			return
+		}
+		summary := newInstalledCertificateCompliance(compliance)
+		resp.MissingRootCertificates = summary.MissingRootCertificates
+		resp.RevokedRootCertificates = summary.RevokedRootCertificates
+	}
+
+	_ = render.Render(w, r, resp)
+}
+
+func (s *Server) ListNonCompliantCertificates(w http.ResponseWriter, r *http.Request, params ListNonCompliantCertificatesParams) {
+	offset := 0
+	limit := 20
+
+	if params.Offset != nil {
+		offset = *params.Offset
+	}
+	if params.Limit != nil {
+		limit = *params.Limit
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	roots, err := s.store.ListRootCertificates(r.Context())
+	if err != nil {
+		_ = render.Render(w, r, ErrInternalError(err))
+		return
+	}
+
+	var resp = make([]render.Renderer, 0, limit)
+	var skipped int
+	var previousCsId string
+	for len(resp) < limit {
+		page, err := s.store.ListChargeStationInstalledCertificates(r.Context(), 50, previousCsId)
+		if err != nil {
+			_ = render.Render(w, r, ErrInternalError(err))
+			return
+		}
+		for _, installed := range page {
+			if len(resp) == limit {
+				break
+			}
+			if installed.ReportedAt.IsZero() {
+				continue
+			}
+			compliance, err := handlers.CheckInstalledCertificates(installed, roots)
+			if err != nil {
+				_ = render.Render(w, r, ErrInternalError(err))
+				return
+			}
+			if compliance.Compliant() {
+				continue
+			}
+			if skipped < offset {
+				skipped++
+				continue
+			}
+			resp = append(resp, newInstalledCertificateCompliance(compliance))
+		}
+		if len(page) < 50 {
+			break
+		}
+		previousCsId = page[len(page)-1].ChargeStationId
+	}
+
+	_ = render.RenderList(w, r, resp)
+}
+
+func (s *Server) RemediateInstalledCertificates(w http.ResponseWriter, r *http.Request, csId string) {
+	installed, err := s.store.LookupChargeStationInstalledCertificates(r.Context(), csId)
 	if err != nil {
 		_ = render.Render(w, r, ErrInternalError(err))
 		return
 	}
-
-	w.WriteHeader(http.StatusCreated)
-}
-
-func (s *Server) SetToken(w http.ResponseWriter, r *http.Request) {
-	req := new(Token)
-	if err := render.Bind(r, req); err != nil {
-		_ = render.Render(w, r, ErrInvalidRequest(err))
+	if installed == nil || installed.ReportedAt.IsZero() {
+		// there is nothing to compare until the charge station has reported its installed certificates
+		_ = render.Render(w, r, ErrNotFound)
 		return
 	}
 
-	normContractId, err := ocpp.NormalizeEmaid(req.ContractId)
+	roots, err := s.store.ListRootCertificates(r.Context())
 	if err != nil {
-		_ = render.Render(w, r, ErrInvalidRequest(err))
+		_ = render.Render(w, r, ErrInternalError(err))
 		return
 	}
-
-	err = s.store.SetToken(r.Context(), &store.Token{
-		CountryCode:  req.CountryCode,
-		PartyId:      req.PartyId,
-		Type:         string(req.Type),
-		Uid:          req.Uid,
-		ContractId:   normContractId,
-		VisualNumber: req.VisualNumber,
-		Issuer:       req.Issuer,
-		GroupId:      req.GroupId,
-		Valid:        req.Valid,
-		LanguageCode: req.LanguageCode,
-		CacheMode:    string(req.CacheMode),
-		LastUpdated:  s.clock.Now().Format(time.RFC3339),
-	})
+	compliance, err := handlers.CheckInstalledCertificates(installed, roots)
 	if err != nil {
 		_ = render.Render(w, r, ErrInternalError(err))
 		return
 	}
 
-	w.WriteHeader(http.StatusCreated)
-}
+	if len(compliance.Missing) > 0 {
+		var certs []*store.ChargeStationInstallCertificate
+		for _, root := range compliance.Missing {
+			certs = append(certs, &store.ChargeStationInstallCertificate{
+				CertificateType:               root.CertificateType,
+				CertificateId:                 root.CertificateId,
+				CertificateData:               root.CertificateData,
+				CertificateInstallationStatus: store.CertificateInstallationPending,
+			})
+		}
+		err = s.store.UpdateChargeStationInstallCertificates(r.Context(), csId, &store.ChargeStationInstallCertificates{
+			ChargeStationId: csId,
+			Certificates:    certs,
+		})
+		if err != nil {
+			_ = render.Render(w, r, ErrInternalError(err))
+			return
+		}
+		for _, cert := range certs {
+			s.recordRequest(r, csId, store.ChargeStationOperationRequested, store.ChargeStationOperationTypeCertificate, cert.CertificateId)
+		}
+	}
 
-func newToken(tok *store.Token) (*Token, error) {
-	lastUpdated, err := time.Parse(time.RFC3339, tok.LastUpdated)
-	if err != nil {
-		return nil, err
+	if len(compliance.Revoked) > 0 {
+		// the deletions are queued against the stored installed certificates in a transaction so
+		// that a concurrent report or deletion result is not overwritten
+		_, err = s.store.UpdateChargeStationInstalledCertificates(r.Context(), csId, func(installed *store.ChargeStationInstalledCertificates) bool {
+			changed := false
+			for _, revoked := range compliance.Revoked {
+				for _, certificate := range installed.Certificates {
+					if certificate.SameCertificate(revoked.InstalledCertificate) {
+						certificate.DeletionStatus = store.CertificateDeletionPending
+						certificate.SendAfter = time.Time{}
+						certificate.Attempts = 0
+						changed = true
+					}
+				}
+			}
+			return changed
+		})
+		if err != nil {
+			_ = render.Render(w, r, ErrInternalError(err))
+			return
+		}
+		for _, revoked := range compliance.Revoked {
+			s.recordRequest(r, csId, store.ChargeStationOperationRequested, store.ChargeStationOperationTypeCertificateDeletion,
+				revoked.InstalledCertificate.SerialNumber)
+		}
 	}
 
-	return &Token{
-		CountryCode:  tok.CountryCode,
-		PartyId:      tok.PartyId,
-		Type:         TokenType(tok.Type),
-		Uid:          tok.Uid,
-		ContractId:   tok.ContractId,
-		VisualNumber: tok.VisualNumber,
-		Issuer:       tok.Issuer,
-		GroupId:      tok.GroupId,
-		Valid:        tok.Valid,
-		LanguageCode: tok.LanguageCode,
-		CacheMode:    TokenCacheMode(tok.CacheMode),
-		LastUpdated:  &lastUpdated,
-	}, nil
+	s.notifyPending(r.Context(), csId)
+
+	_ = render.Render(w, r, newInstalledCertificateCompliance(compliance))
 }
 
-func (s *Server) LookupToken(w http.ResponseWriter, r *http.Request, tokenUid string) {
-	tok, err := s.store.LookupToken(r.Context(), tokenUid)
+func newInstalledCertificateCompliance(compliance *handlers.InstalledCertificateCompliance) *InstalledCertificateCompliance {
+	resp := &InstalledCertificateCompliance{
+		CsId:                    compliance.ChargeStationId,
+		MissingRootCertificates: make([]string, len(compliance.Missing)),
+		RevokedRootCertificates: make([]string, len(compliance.Revoked)),
+	}
+	for i, root := range compliance.Missing {
+		resp.MissingRootCertificates[i] = root.CertificateId
+	}
+	for i, revoked := range compliance.Revoked {
+		resp.RevokedRootCertificates[i] = revoked.RootCertificate.CertificateId
+	}
+	return resp
+}
+
+func (s *Server) CreateCertificateCampaign(w http.ResponseWriter, r *http.Request) {
+	req := new(CertificateCampaignRequest)
+	if err := render.Bind(r, req); err != nil {
+		_ = render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	certId, err := handlers.GetCertificateId(req.Certificate)
 	if err != nil {
-		_ = render.Render(w, r, ErrInternalError(err))
+		_ = render.Render(w, r, ErrInvalidRequest(fmt.Errorf("invalid certificate: %w", err)))
 		return
 	}
-	if tok == nil {
-		_ = render.Render(w, r, ErrNotFound)
+
+	now := s.clock.Now().UTC()
+	campaign := &store.CertificateCampaign{
+		CampaignId:      uuid.New().String(),
+		CertificateType: store.CertificateType(req.CertificateType),
+		CertificateId:   certId,
+		CertificateData: req.Certificate,
+		WaveSize:        10,
+		WaveInterval:    time.Hour,
+		Status:          store.CertificateCampaignInProgress,
+		CreatedAt:       now,
+		NextWaveAt:      now,
+	}
+	if req.Tag != nil {
+		campaign.Tag = *req.Tag
+	}
+	if req.OcppVersion != nil {
+		campaign.OcppVersion = *req.OcppVersion
+	}
+	if req.WaveSize != nil {
+		campaign.WaveSize = *req.WaveSize
+	}
+	if req.WaveIntervalMinutes != nil {
+		campaign.WaveInterval = time.Duration(*req.WaveIntervalMinutes) * time.Minute
+	}
+	if req.MaxFailures != nil {
+		campaign.MaxFailures = *req.MaxFailures
+	}
+
+	// the charge stations are fixed when the campaign starts: charge stations that are
+	// registered (or tagged) later must be targeted by another campaign
+	var stations []*store.CertificateCampaignStation
+	for offset := 0; ; offset += 50 {
+		page, err := s.store.ListChargeStationDetails(r.Context(), store.ChargeStationFilter{Tag: campaign.Tag}, offset, 50)
+		if err != nil {
+			_ = render.Render(w, r, ErrInternalError(err))
+			return
+		}
+		for _, details := range page {
+			if campaign.OcppVersion != "" {
+				runtimeDetails, err := s.store.LookupChargeStationRuntimeDetails(r.Context(), details.ChargeStationId)
+				if err != nil {
+					_ = render.Render(w, r, ErrInternalError(err))
+					return
+				}
+				if runtimeDetails == nil || runtimeDetails.OcppVersion != campaign.OcppVersion {
+					continue
+				}
+			}
+			stations = append(stations, &store.CertificateCampaignStation{
+				ChargeStationId: details.ChargeStationId,
+				Status:          store.CertificateCampaignStationWaiting,
+			})
+		}
+		if len(page) < 50 {
+			break
+		}
+	}
+	if len(stations) == 0 {
+		_ = render.Render(w, r, ErrInvalidRequest(errors.New("no charge stations match the campaign target")))
 		return
 	}
 
-	resp, err := newToken(tok)
+	err = s.store.CreateCertificateCampaign(r.Context(), campaign, stations)
 	if err != nil {
 		_ = render.Render(w, r, ErrInternalError(err))
 		return
 	}
 
-	_ = render.Render(w, r, resp)
+	render.Status(r, http.StatusCreated)
+	_ = render.Render(w, r, newCertificateCampaign(campaign, nil))
 }
 
-func (s *Server) ListTokens(w http.ResponseWriter, r *http.Request, params ListTokensParams) {
+func (s *Server) ListCertificateCampaigns(w http.ResponseWriter, r *http.Request, params ListCertificateCampaignsParams) {
+	var status store.CertificateCampaignStatus
 	offset := 0
 	limit := 20
 
+	if params.Status != nil {
+		status = store.CertificateCampaignStatus(*params.Status)
+	}
 	if params.Offset != nil {
 		offset = *params.Offset
 	}
@@ -258,31 +1552,137 @@ func (s *Server) ListTokens(w http.ResponseWriter, r *http.Request, params ListT
 		limit = 100
 	}
 
-	tokens, err := s.store.ListTokens(r.Context(), offset, limit)
+	campaigns, err := s.store.ListCertificateCampaigns(r.Context(), status, offset, limit)
 	if err != nil {
 		_ = render.Render(w, r, ErrInternalError(err))
 		return
 	}
 
-	var resp = make([]render.Renderer, len(tokens))
-	for i, tok := range tokens {
-		resp[i], err = newToken(tok)
+	var resp = make([]render.Renderer, len(campaigns))
+	for i, campaign := range campaigns {
+		resp[i] = newCertificateCampaign(campaign, nil)
+	}
+	_ = render.RenderList(w, r, resp)
+}
+
+func (s *Server) LookupCertificateCampaign(w http.ResponseWriter, r *http.Request, campaignId string) {
+	campaign, err := s.store.LookupCertificateCampaign(r.Context(), campaignId)
+	if err != nil {
+		_ = render.Render(w, r, ErrInternalError(err))
+		return
+	}
+	if campaign == nil {
+		_ = render.Render(w, r, ErrNotFound)
+		return
+	}
+
+	stations := make([]*store.CertificateCampaignStation, 0, campaign.TotalStations())
+	for offset := 0; ; offset += 50 {
+		page, err := s.store.ListCertificateCampaignStations(r.Context(), campaignId, "", offset, 50)
 		if err != nil {
 			_ = render.Render(w, r, ErrInternalError(err))
 			return
 		}
+		stations = append(stations, page...)
+		if len(page) < 50 {
+			break
+		}
 	}
-	_ = render.RenderList(w, r, resp)
+
+	_ = render.Render(w, r, newCertificateCampaign(campaign, stations))
 }
 
-func (s *Server) UploadCertificate(w http.ResponseWriter, r *http.Request) {
-	req := new(Certificate)
+func (s *Server) CancelCertificateCampaign(w http.ResponseWriter, r *http.Request, campaignId string) {
+	// the campaign is cancelled in a transaction so that it cannot race with the sync
+	// completing (or halting) it
+	campaign, err := s.store.UpdateCertificateCampaign(r.Context(), campaignId, func(campaign *store.CertificateCampaign) bool {
+		if campaign.Status != store.CertificateCampaignInProgress && campaign.Status != store.CertificateCampaignHalted {
+			return false
+		}
+		campaign.Status = store.CertificateCampaignCancelled
+		return true
+	})
+	if err != nil {
+		_ = render.Render(w, r, ErrInternalError(err))
+		return
+	}
+	if campaign == nil {
+		_ = render.Render(w, r, ErrNotFound)
+		return
+	}
+
+	_ = render.Render(w, r, newCertificateCampaign(campaign, nil))
+}
+
+// newCertificateCampaign converts the campaign to its API representation: the charge stations
+// are only included when they are provided
+func newCertificateCampaign(campaign *store.CertificateCampaign, stations []*store.CertificateCampaignStation) *CertificateCampaign {
+	resp := &CertificateCampaign{
+		Id:                  campaign.CampaignId,
+		CertificateType:     CertificateCampaignCertificateType(campaign.CertificateType),
+		CertificateId:       campaign.CertificateId,
+		WaveSize:            campaign.WaveSize,
+		WaveIntervalMinutes: int(campaign.WaveInterval / time.Minute),
+		MaxFailures:         campaign.MaxFailures,
+		Status:              CertificateCampaignStatus(campaign.Status),
+		CreatedAt:           campaign.CreatedAt,
+		Waves:               campaign.Waves,
+		Progress: CertificateCampaignProgress{
+			Total:    campaign.TotalStations(),
+			Waiting:  campaign.CountStations(store.CertificateCampaignStationWaiting),
+			Pending:  campaign.CountStations(store.CertificateCampaignStationPending),
+			Accepted: campaign.CountStations(store.CertificateCampaignStationAccepted),
+			Rejected: campaign.CountStations(store.CertificateCampaignStationRejected),
+			Failed:   campaign.CountStations(store.CertificateCampaignStationFailed),
+		},
+	}
+	if campaign.Tag != "" {
+		resp.Tag = &campaign.Tag
+	}
+	if campaign.OcppVersion != "" {
+		resp.OcppVersion = &campaign.OcppVersion
+	}
+	if campaign.Status == store.CertificateCampaignInProgress && !campaign.NextWaveAt.IsZero() {
+		resp.NextWaveAt = &campaign.NextWaveAt
+	}
+	if stations != nil {
+		respStations := make([]CertificateCampaignStation, len(stations))
+		for i, station := range stations {
+			respStations[i] = CertificateCampaignStation{
+				CsId:   station.ChargeStationId,
+				Status: CertificateCampaignStationStatus(station.Status),
+			}
+			if station.Wave > 0 {
+				wave := station.Wave
+				respStations[i].Wave = &wave
+			}
+			if !station.UpdatedAt.IsZero() {
+				updatedAt := station.UpdatedAt
+				respStations[i].UpdatedAt = &updatedAt
+			}
+		}
+		resp.Stations = &respStations
+	}
+	return resp
+}
+
+func (s *Server) SetContract(w http.ResponseWriter, r *http.Request) {
+	req := new(Contract)
 	if err := render.Bind(r, req); err != nil {
 		_ = render.Render(w, r, ErrInvalidRequest(err))
 		return
 	}
 
-	err := s.store.SetCertificate(r.Context(), req.Certificate)
+	emaid, err := ocpp.NormalizeEmaid(req.Emaid)
+	if err != nil {
+		_ = render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	err = s.store.SetContract(r.Context(), &store.Contract{
+		EMAID:                     emaid,
+		ProvisioningCertificateId: req.ProvisioningCertificateId,
+	})
 	if err != nil {
 		_ = render.Render(w, r, ErrInternalError(err))
 		return
@@ -291,31 +1691,69 @@ func (s *Server) UploadCertificate(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusCreated)
 }
 
-func (s *Server) DeleteCertificate(w http.ResponseWriter, r *http.Request, certificateHash string) {
-	err := s.store.DeleteCertificate(r.Context(), certificateHash)
+func (s *Server) LookupContract(w http.ResponseWriter, r *http.Request, emaid string) {
+	emaid, err := ocpp.NormalizeEmaid(emaid)
+	if err != nil {
+		_ = render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	contract, err := s.store.LookupContract(r.Context(), emaid)
 	if err != nil {
 		_ = render.Render(w, r, ErrInternalError(err))
 		return
 	}
+	if contract == nil {
+		_ = render.Render(w, r, ErrNotFound)
+		return
+	}
 
-	w.WriteHeader(http.StatusNoContent)
+	_ = render.Render(w, r, &Contract{
+		Emaid:                     contract.EMAID,
+		ProvisioningCertificateId: contract.ProvisioningCertificateId,
+	})
 }
 
-func (s *Server) LookupCertificate(w http.ResponseWriter, r *http.Request, certificateHash string) {
-	cert, err := s.store.LookupCertificate(r.Context(), certificateHash)
+func (s *Server) DeleteContract(w http.ResponseWriter, r *http.Request, emaid string) {
+	emaid, err := ocpp.NormalizeEmaid(emaid)
 	if err != nil {
-		_ = render.Render(w, r, ErrInternalError(err))
+		_ = render.Render(w, r, ErrInvalidRequest(err))
 		return
 	}
-	if cert == "" {
-		_ = render.Render(w, r, ErrNotFound)
+
+	err = s.store.DeleteContract(r.Context(), emaid)
+	if err != nil {
+		_ = render.Render(w, r, ErrInternalError(err))
 		return
 	}
 
-	resp := &Certificate{
-		Certificate: cert,
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// getPEMCertificateHash returns the base64 URL encoded SHA-256 hash of the DER bytes of
+// the certificate: this is how certificates are identified in the store
+func getPEMCertificateHash(pemCertificate string) (string, error) {
+	block, _ := pem.Decode([]byte(pemCertificate))
+	if block == nil {
+		return "", errors.New("pem block not found")
 	}
-	_ = render.Render(w, r, resp)
+	if block.Type != "CERTIFICATE" {
+		return "", fmt.Errorf("pem block does not contain certificate, but %s", block.Type)
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.Sum256(cert.Raw)
+	return base64.RawURLEncoding.EncodeToString(hash[:]), nil
+}
+
+// normalizeCertificateHash converts a hash that uses the standard base64 encoding into
+// the unpadded URL encoding used by the store
+func normalizeCertificateHash(certificateHash string) string {
+	certificateHash = strings.TrimRight(certificateHash, "=")
+	certificateHash = strings.ReplaceAll(certificateHash, "+", "-")
+	return strings.ReplaceAll(certificateHash, "/", "_")
 }
 
 func (s *Server) RegisterParty(w http.ResponseWriter, r *http.Request) {
@@ -465,3 +1903,119 @@ func (s *Server) RegisterLocation(w http.ResponseWriter, r *http.Request, locati
 
 	w.WriteHeader(http.StatusCreated)
 }
+
+func newOcpiDelivery(delivery *store.OcpiDelivery) *OcpiDelivery {
+	var module, body, lastError *string
+	if delivery.Module != "" {
+		module = &delivery.Module
+	}
+	if delivery.Body != "" {
+		body = &delivery.Body
+	}
+	if delivery.LastError != "" {
+		lastError = &delivery.LastError
+	}
+
+	return &OcpiDelivery{
+		Id:          delivery.Id,
+		CountryCode: delivery.CountryCode,
+		PartyId:     delivery.PartyId,
+		Module:      module,
+		Method:      delivery.Method,
+		Url:         delivery.Url,
+		Body:        body,
+		Status:      OcpiDeliveryStatus(delivery.Status),
+		Attempts:    delivery.Attempts,
+		LastError:   lastError,
+		NextAttempt: delivery.NextAttempt,
+		Created:     delivery.Created,
+	}
+}
+
+func (s *Server) ListOcpiDeliveries(w http.ResponseWriter, r *http.Request, params ListOcpiDeliveriesParams) {
+	status := store.OcpiDeliveryStatusFailed
+	offset := 0
+	limit := 20
+
+	if params.Status != nil {
+		status = store.OcpiDeliveryStatus(*params.Status)
+	}
+	if params.Offset != nil {
+		offset = *params.Offset
+	}
+	if params.Limit != nil {
+		limit = *params.Limit
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	deliveries, err := s.store.ListDeliveries(r.Context(), status, offset, limit)
+	if err != nil {
+		_ = render.Render(w, r, ErrInternalError(err))
+		return
+	}
+
+	var resp = make([]render.Renderer, len(deliveries))
+	for i, delivery := range deliveries {
+		resp[i] = newOcpiDelivery(delivery)
+	}
+	_ = render.RenderList(w, r, resp)
+}
+
+func (s *Server) LookupOcpiDelivery(w http.ResponseWriter, r *http.Request, deliveryId string) {
+	delivery, err := s.store.LookupDelivery(r.Context(), deliveryId)
+	if err != nil {
+		_ = render.Render(w, r, ErrInternalError(err))
+		return
+	}
+	if delivery == nil {
+		_ = render.Render(w, r, ErrNotFound)
+		return
+	}
+
+	_ = render.Render(w, r, newOcpiDelivery(delivery))
+}
+
+func (s *Server) DeleteOcpiDelivery(w http.ResponseWriter, r *http.Request, deliveryId string) {
+	delivery, err := s.store.LookupDelivery(r.Context(), deliveryId)
+	if err != nil {
+		_ = render.Render(w, r, ErrInternalError(err))
+		return
+	}
+	if delivery == nil {
+		_ = render.Render(w, r, ErrNotFound)
+		return
+	}
+
+	err = s.store.DeleteDelivery(r.Context(), deliveryId)
+	if err != nil {
+		_ = render.Render(w, r, ErrInternalError(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) ReplayOcpiDelivery(w http.ResponseWriter, r *http.Request, deliveryId string) {
+	delivery, err := s.store.LookupDelivery(r.Context(), deliveryId)
+	if err != nil {
+		_ = render.Render(w, r, ErrInternalError(err))
+		return
+	}
+	if delivery == nil {
+		_ = render.Render(w, r, ErrNotFound)
+		return
+	}
+
+	delivery.Status = store.OcpiDeliveryStatusPending
+	delivery.Attempts = 0
+	delivery.NextAttempt = s.clock.Now().UTC()
+	err = s.store.SetDelivery(r.Context(), delivery)
+	if err != nil {
+		_ = render.Render(w, r, ErrInternalError(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
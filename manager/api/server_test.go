@@ -12,6 +12,7 @@ import (
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"encoding/pem"
 	"fmt"
@@ -19,9 +20,12 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/thoughtworks/maeve-csms/manager/api"
+	handlers "github.com/thoughtworks/maeve-csms/manager/handlers/ocpp201"
 	"github.com/thoughtworks/maeve-csms/manager/ocpi"
+	"github.com/thoughtworks/maeve-csms/manager/ocpp/ocpp201"
 	"github.com/thoughtworks/maeve-csms/manager/store"
 	"github.com/thoughtworks/maeve-csms/manager/store/inmemory"
+	"github.com/thoughtworks/maeve-csms/manager/transport"
 	"io"
 	"k8s.io/utils/clock"
 	clockTest "k8s.io/utils/clock/testing"
@@ -34,7 +38,7 @@ import (
 )
 
 func TestRegisterChargeStation(t *testing.T) {
-	server, r, _, _ := setupServer(t)
+	server, r, engine, c := setupServer(t)
 	defer server.Close()
 
 	req := httptest.NewRequest(http.MethodPost, "/cs/cs001", strings.NewReader(`{"securityProfile":0}`))
@@ -46,6 +50,311 @@ func TestRegisterChargeStation(t *testing.T) {
 	b, err := io.ReadAll(rr.Result().Body)
 	require.NoError(t, err)
 	assert.Equal(t, "", string(b))
+
+	details, err := engine.LookupChargeStationDetails(context.Background(), "cs001")
+	require.NoError(t, err)
+	require.NotNil(t, details)
+	assert.Equal(t, c.Now().UTC(), details.RegisteredAt)
+}
+
+func TestRegisterChargeStationDisconnectsWhenCredentialsChange(t *testing.T) {
+	server, r, engine, emitted := setupServerWithControlEmitter(t)
+	defer server.Close()
+
+	err := engine.SetChargeStationAuth(context.Background(), "cs001", &store.ChargeStationAuth{
+		SecurityProfile:      1,
+		Base64SHA256Password: "DEADBEEF",
+	})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/cs/cs001", strings.NewReader(`{"securityProfile":1,"base64SHA256Password":"DEADBEEF"}`))
+	req.Header.Set("content-type", "application/json")
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Result().StatusCode)
+	assert.Empty(t, *emitted)
+
+	req = httptest.NewRequest(http.MethodPost, "/cs/cs001", strings.NewReader(`{"securityProfile":1,"base64SHA256Password":"C0FFEE"}`))
+	req.Header.Set("content-type", "application/json")
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Result().StatusCode)
+	require.Len(t, *emitted, 1)
+	assert.Equal(t, "cs001", (*emitted)[0].csId)
+	assert.Equal(t, transport.ControlMessage{Type: transport.ControlDisconnect, Reason: "credentials changed"}, (*emitted)[0].msg)
+}
+
+func TestDisconnectChargeStation(t *testing.T) {
+	server, r, _, emitted := setupServerWithControlEmitter(t)
+	defer server.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/cs/cs001/disconnect", strings.NewReader(`{"reason":"banned"}`))
+	req.Header.Set("content-type", "application/json")
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusAccepted, rr.Result().StatusCode)
+	require.Len(t, *emitted, 1)
+	assert.Equal(t, "cs001", (*emitted)[0].csId)
+	assert.Equal(t, transport.ControlMessage{Type: transport.ControlDisconnect, Reason: "banned"}, (*emitted)[0].msg)
+}
+
+func TestDisconnectChargeStationWithoutBody(t *testing.T) {
+	server, r, _, emitted := setupServerWithControlEmitter(t)
+	defer server.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/cs/cs001/disconnect", nil)
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusAccepted, rr.Result().StatusCode)
+	require.Len(t, *emitted, 1)
+	assert.Equal(t, transport.ControlMessage{Type: transport.ControlDisconnect}, (*emitted)[0].msg)
+}
+
+func TestDisconnectChargeStationWithoutControlEmitter(t *testing.T) {
+	server, r, _, _ := setupServer(t)
+	defer server.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/cs/cs001/disconnect", nil)
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rr.Result().StatusCode)
+}
+
+type recordingSyncNotifier struct {
+	pending []string
+}
+
+func (n *recordingSyncNotifier) NotifyPending(_ context.Context, chargeStationId string) {
+	n.pending = append(n.pending, chargeStationId)
+}
+
+func TestChangesForChargeStationNotifySync(t *testing.T) {
+	engine := inmemory.NewStore(clock.RealClock{})
+	srv, err := api.NewServer(engine, clockTest.NewFakePassiveClock(time.Now()), nil, nil)
+	require.NoError(t, err)
+	notifier := &recordingSyncNotifier{}
+	srv.SetSyncNotifier(notifier)
+
+	r := chi.NewRouter()
+	r.Use(api.ValidationMiddleware)
+	r.Mount("/", api.Handler(srv))
+
+	req := httptest.NewRequest(http.MethodPost, "/cs/cs001/reconfigure", strings.NewReader(`{"HeartbeatInterval":"60"}`))
+	req.Header.Set("content-type", "application/json")
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Result().StatusCode)
+
+	req = httptest.NewRequest(http.MethodPost, "/cs/cs002/trigger", strings.NewReader(`{"trigger":"BootNotification"}`))
+	req.Header.Set("content-type", "application/json")
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusCreated, rr.Result().StatusCode)
+
+	assert.Equal(t, []string{"cs001", "cs002"}, notifier.pending)
+}
+
+func TestTriggerChargeStationWithMultipleTriggers(t *testing.T) {
+	engine := inmemory.NewStore(clock.RealClock{})
+	srv, err := api.NewServer(engine, clockTest.NewFakePassiveClock(time.Now()), nil, nil)
+	require.NoError(t, err)
+
+	r := chi.NewRouter()
+	r.Use(api.ValidationMiddleware)
+	r.Mount("/", api.Handler(srv))
+
+	req := httptest.NewRequest(http.MethodPost, "/cs/cs001/trigger",
+		strings.NewReader(`[{"trigger":"BootNotification"},{"trigger":"StatusNotification","evseId":1,"connectorId":2}]`))
+	req.Header.Set("content-type", "application/json")
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusCreated, rr.Result().StatusCode)
+
+	req = httptest.NewRequest(http.MethodPost, "/cs/cs001/trigger", strings.NewReader(`{"trigger":"SignV2GCertificate"}`))
+	req.Header.Set("content-type", "application/json")
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusCreated, rr.Result().StatusCode)
+
+	evseId, connectorId := 1, 2
+	want := &store.ChargeStationTriggerMessages{
+		ChargeStationId: "cs001",
+		TriggerMessages: []*store.ChargeStationTriggerMessage{
+			{ChargeStationId: "cs001", TriggerMessage: store.TriggerMessageBootNotification, TriggerStatus: store.TriggerStatusPending},
+			{ChargeStationId: "cs001", TriggerMessage: store.TriggerMessageStatusNotification, EvseId: &evseId, ConnectorId: &connectorId, TriggerStatus: store.TriggerStatusPending},
+			{ChargeStationId: "cs001", TriggerMessage: store.TriggerMessageSignV2GCertificate, TriggerStatus: store.TriggerStatusPending},
+		},
+	}
+
+	got, err := engine.LookupChargeStationTriggerMessages(context.Background(), "cs001")
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+}
+
+func TestTriggerChargeStationRejectsConnectorWithoutEvse(t *testing.T) {
+	engine := inmemory.NewStore(clock.RealClock{})
+	srv, err := api.NewServer(engine, clockTest.NewFakePassiveClock(time.Now()), nil, nil)
+	require.NoError(t, err)
+
+	r := chi.NewRouter()
+	r.Use(api.ValidationMiddleware)
+	r.Mount("/", api.Handler(srv))
+
+	req := httptest.NewRequest(http.MethodPost, "/cs/cs001/trigger", strings.NewReader(`{"trigger":"StatusNotification","connectorId":1}`))
+	req.Header.Set("content-type", "application/json")
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Result().StatusCode)
+
+	got, err := engine.LookupChargeStationTriggerMessages(context.Background(), "cs001")
+	require.NoError(t, err)
+	assert.Nil(t, got)
+}
+
+func TestListAndRearmFailedChargeStationOperations(t *testing.T) {
+	engine := inmemory.NewStore(clock.RealClock{})
+	srv, err := api.NewServer(engine, clockTest.NewFakePassiveClock(time.Now()), nil, nil)
+	require.NoError(t, err)
+	notifier := &recordingSyncNotifier{}
+	srv.SetSyncNotifier(notifier)
+
+	r := chi.NewRouter()
+	r.Use(api.ValidationMiddleware)
+	r.Mount("/", api.Handler(srv))
+
+	ctx := context.Background()
+	err = engine.UpdateChargeStationSettings(ctx, "cs001", &store.ChargeStationSettings{
+		Settings: map[string]*store.ChargeStationSetting{
+			"foo": {Value: "bar", Status: store.ChargeStationSettingStatusFailed, Attempts: 10},
+			"baz": {Value: "qux", Status: store.ChargeStationSettingStatusAccepted},
+		},
+	})
+	require.NoError(t, err)
+	err = engine.UpdateChargeStationTriggerMessages(ctx, "cs001", &store.ChargeStationTriggerMessages{
+		TriggerMessages: []*store.ChargeStationTriggerMessage{
+			{TriggerMessage: store.TriggerMessageBootNotification, TriggerStatus: store.TriggerStatusFailed, Attempts: 3},
+		},
+	})
+	require.NoError(t, err)
+
+	want := []api.FailedOperation{
+		{Type: api.FailedOperationTypeSetting, Id: "foo", Attempts: 10},
+		{Type: api.FailedOperationTypeTrigger, Id: "BootNotification", Attempts: 3},
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/cs/cs001/failed-operations", nil)
+	req.Header.Set("accept", "application/json")
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Result().StatusCode)
+
+	var got []api.FailedOperation
+	err = json.Unmarshal(rr.Body.Bytes(), &got)
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+
+	req = httptest.NewRequest(http.MethodPost, "/cs/cs001/failed-operations/rearm", nil)
+	req.Header.Set("accept", "application/json")
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Result().StatusCode)
+
+	got = nil
+	err = json.Unmarshal(rr.Body.Bytes(), &got)
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+	assert.Equal(t, []string{"cs001"}, notifier.pending)
+
+	settings, err := engine.LookupChargeStationSettings(ctx, "cs001")
+	require.NoError(t, err)
+	assert.Equal(t, store.ChargeStationSettingStatusPending, settings.Settings["foo"].Status)
+	assert.Equal(t, 0, settings.Settings["foo"].Attempts)
+	assert.Equal(t, store.ChargeStationSettingStatusAccepted, settings.Settings["baz"].Status)
+
+	triggers, err := engine.LookupChargeStationTriggerMessages(ctx, "cs001")
+	require.NoError(t, err)
+	require.Len(t, triggers.TriggerMessages, 1)
+	assert.Equal(t, store.TriggerStatusPending, triggers.TriggerMessages[0].TriggerStatus)
+	assert.Equal(t, 0, triggers.TriggerMessages[0].Attempts)
+
+	req = httptest.NewRequest(http.MethodGet, "/cs/cs001/failed-operations", nil)
+	req.Header.Set("accept", "application/json")
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Result().StatusCode)
+	assert.JSONEq(t, "[]", rr.Body.String())
+}
+
+func TestListChargeStationOperations(t *testing.T) {
+	now := time.Date(2023, 6, 15, 15, 0, 0, 0, time.UTC)
+	engine := inmemory.NewStore(clock.RealClock{})
+	srv, err := api.NewServer(engine, clockTest.NewFakePassiveClock(now), nil, nil)
+	require.NoError(t, err)
+
+	r := chi.NewRouter()
+	r.Use(api.ValidationMiddleware)
+	r.Mount("/", api.HandlerWithOptions(srv, api.ChiServerOptions{
+		Middlewares: []api.MiddlewareFunc{api.NewAuthMiddleware(api.NewApiKeyAuthenticator(map[string]api.Principal{
+			"operator-key": {Subject: "operator", Role: api.RoleOperator},
+		}))},
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/cs/cs001/trigger", strings.NewReader(`{"trigger":"BootNotification"}`))
+	req.Header.Set("content-type", "application/json")
+	req.Header.Set("X-API-Key", "operator-key")
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusCreated, rr.Result().StatusCode)
+
+	err = engine.AppendChargeStationOperation(context.Background(), &store.ChargeStationOperation{
+		ChargeStationId: "cs001",
+		Timestamp:       now.Add(time.Second),
+		Event:           store.ChargeStationOperationSent,
+		Action:          "TriggerMessage",
+		MessageId:       "1234",
+		Payload:         `{"requestedMessage":"BootNotification"}`,
+	})
+	require.NoError(t, err)
+
+	req = httptest.NewRequest(http.MethodGet, "/cs/cs001/operations", nil)
+	req.Header.Set("accept", "application/json")
+	req.Header.Set("X-API-Key", "operator-key")
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Result().StatusCode)
+
+	var got []api.ChargeStationOperation
+	err = json.Unmarshal(rr.Body.Bytes(), &got)
+	require.NoError(t, err)
+
+	triggerType := api.ChargeStationOperationTypeTrigger
+	subject := "BootNotification"
+	requestedBy := "operator"
+	action := "TriggerMessage"
+	messageId := "1234"
+	payload := `{"requestedMessage":"BootNotification"}`
+	want := []api.ChargeStationOperation{
+		{
+			Event:       api.ChargeStationOperationEventRequested,
+			Timestamp:   now,
+			Type:        &triggerType,
+			Subject:     &subject,
+			RequestedBy: &requestedBy,
+		},
+		{
+			Event:     api.ChargeStationOperationEventSent,
+			Timestamp: now.Add(time.Second),
+			Action:    &action,
+			MessageId: &messageId,
+			Payload:   &payload,
+		},
+	}
+	assert.Equal(t, want, got)
 }
 
 func TestLookupChargeStationAuth(t *testing.T) {
@@ -91,220 +400,1168 @@ func TestLookupChargeStationAuthThatDoesNotExist(t *testing.T) {
 	assert.Equal(t, http.StatusNotFound, rr.Result().StatusCode)
 }
 
-func TestSetToken(t *testing.T) {
-	server, r, engine, _ := setupServer(t)
+func setChargeStationDetails(t *testing.T, engine store.Engine, csId, vendor, model string, now time.Time) {
+	err := engine.SetChargeStationAuth(context.Background(), csId, &store.ChargeStationAuth{
+		SecurityProfile: store.TLSWithClientSideCertificates,
+	})
+	require.NoError(t, err)
+	err = engine.SetChargeStationDetails(context.Background(), csId, &store.ChargeStationDetails{
+		Vendor:          vendor,
+		Model:           model,
+		FirmwareVersion: "1.0.0",
+		RegisteredAt:    now,
+		LastSeen:        now,
+	})
+	require.NoError(t, err)
+}
+
+func TestListChargeStations(t *testing.T) {
+	server, r, engine, c := setupServer(t)
 	defer server.Close()
 
-	token := api.Token{
-		CacheMode:   "ALWAYS",
-		ContractId:  "GB-TWK-012345678-V",
-		CountryCode: "GB",
-		Issuer:      "Thoughtworks",
-		PartyId:     "TWK",
-		Type:        "RFID",
-		Uid:         "012345678",
-		Valid:       true,
+	now := c.Now().UTC()
+	setChargeStationDetails(t, engine, "cs001", "Acme", "Wallbox", now)
+	setChargeStationDetails(t, engine, "cs002", "Acme", "Rapid", now)
+	setChargeStationDetails(t, engine, "cs003", "Other", "Wallbox", now)
+
+	req := httptest.NewRequest(http.MethodGet, "/cs?vendor=Acme", nil)
+	req.Header.Set("accept", "application/json")
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Result().StatusCode)
+	var got []api.ChargeStation
+	err := json.NewDecoder(rr.Result().Body).Decode(&got)
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.Equal(t, "cs001", got[0].Id)
+	assert.Equal(t, "Wallbox", *got[0].Model)
+	assert.Equal(t, "cs002", got[1].Id)
+
+	req = httptest.NewRequest(http.MethodGet, "/cs?offset=1&limit=1", nil)
+	req.Header.Set("accept", "application/json")
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Result().StatusCode)
+	err = json.NewDecoder(rr.Result().Body).Decode(&got)
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, "cs002", got[0].Id)
+}
+
+func TestLookupChargeStation(t *testing.T) {
+	server, r, engine, c := setupServer(t)
+	defer server.Close()
+
+	now := c.Now().UTC()
+	setChargeStationDetails(t, engine, "cs001", "Acme", "Wallbox", now)
+	err := engine.SetChargeStationRuntimeDetails(context.Background(), "cs001", &store.ChargeStationRuntimeDetails{
+		OcppVersion: "2.0.1",
+	})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "/cs/cs001", nil)
+	req.Header.Set("accept", "application/json")
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Result().StatusCode)
+
+	vendor, model, firmwareVersion, ocppVersion, securityProfile := "Acme", "Wallbox", "1.0.0", "2.0.1", 2
+	want := api.ChargeStation{
+		Id:              "cs001",
+		Vendor:          &vendor,
+		Model:           &model,
+		FirmwareVersion: &firmwareVersion,
+		OcppVersion:     &ocppVersion,
+		SecurityProfile: &securityProfile,
+		RegisteredAt:    &now,
+		LastSeen:        &now,
 	}
-	tokenPayload, err := json.Marshal(token)
+
+	var got api.ChargeStation
+	err = json.NewDecoder(rr.Result().Body).Decode(&got)
 	require.NoError(t, err)
+	assert.Equal(t, want, got)
+}
 
-	req := httptest.NewRequest(http.MethodPost, "/token", bytes.NewReader(tokenPayload))
+func TestLookupChargeStationThatDoesNotExist(t *testing.T) {
+	server, r, _, _ := setupServer(t)
+	defer server.Close()
+
+	req := httptest.NewRequest(http.MethodGet, "/cs/unknown", nil)
+	req.Header.Set("accept", "application/json")
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Result().StatusCode)
+}
+
+func TestSetChargeStationTags(t *testing.T) {
+	server, r, engine, c := setupServer(t)
+	defer server.Close()
+
+	now := c.Now().UTC()
+	setChargeStationDetails(t, engine, "cs001", "Acme", "Wallbox", now)
+	setChargeStationDetails(t, engine, "cs002", "Acme", "Rapid", now)
+
+	req := httptest.NewRequest(http.MethodPut, "/cs/cs002/tags", strings.NewReader(`{"tags":["depot","fleet"]}`))
 	req.Header.Set("content-type", "application/json")
 	rr := httptest.NewRecorder()
 	r.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Result().StatusCode)
+	assert.Empty(t, rr.Body.Bytes())
 
-	assert.Equal(t, http.StatusCreated, rr.Result().StatusCode)
-	b, err := io.ReadAll(rr.Result().Body)
+	details, err := engine.LookupChargeStationDetails(context.Background(), "cs002")
 	require.NoError(t, err)
-	assert.Equal(t, "", string(b))
+	assert.Equal(t, []string{"depot", "fleet"}, details.Tags)
+	assert.Equal(t, "Rapid", details.Model)
+
+	req = httptest.NewRequest(http.MethodGet, "/cs?tag=fleet", nil)
+	req.Header.Set("accept", "application/json")
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Result().StatusCode)
+
+	var got []api.ChargeStation
+	err = json.NewDecoder(rr.Result().Body).Decode(&got)
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, "cs002", got[0].Id)
+	require.NotNil(t, got[0].Tags)
+	assert.Equal(t, []string{"depot", "fleet"}, *got[0].Tags)
+
+	req = httptest.NewRequest(http.MethodPut, "/cs/cs002/tags", strings.NewReader(`{"tags":[]}`))
+	req.Header.Set("content-type", "application/json")
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Result().StatusCode)
+	assert.Empty(t, rr.Body.Bytes())
+
+	details, err = engine.LookupChargeStationDetails(context.Background(), "cs002")
+	require.NoError(t, err)
+	assert.Empty(t, details.Tags)
+
+	req = httptest.NewRequest(http.MethodPut, "/cs/unknown/tags", strings.NewReader(`{"tags":["fleet"]}`))
+	req.Header.Set("content-type", "application/json")
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusNotFound, rr.Result().StatusCode)
+}
+
+func TestDeleteChargeStation(t *testing.T) {
+	server, r, engine, c := setupServer(t)
+	defer server.Close()
+
+	ctx := context.Background()
+	setChargeStationDetails(t, engine, "cs001", "Acme", "Wallbox", c.Now().UTC())
+	err := engine.UpdateChargeStationSettings(ctx, "cs001", &store.ChargeStationSettings{
+		Settings: map[string]*store.ChargeStationSetting{
+			"SomeSetting": {Value: "value", Status: store.ChargeStationSettingStatusPending},
+		},
+	})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodDelete, "/cs/cs001", nil)
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNoContent, rr.Result().StatusCode)
+
+	auth, err := engine.LookupChargeStationAuth(ctx, "cs001")
+	require.NoError(t, err)
+	assert.Nil(t, auth)
+	details, err := engine.LookupChargeStationDetails(ctx, "cs001")
+	require.NoError(t, err)
+	assert.Nil(t, details)
+	settings, err := engine.LookupChargeStationSettings(ctx, "cs001")
+	require.NoError(t, err)
+	assert.Nil(t, settings)
+
+	req = httptest.NewRequest(http.MethodDelete, "/cs/cs001", nil)
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Result().StatusCode)
+}
+
+func TestDeleteChargeStationDisconnectsChargeStation(t *testing.T) {
+	server, r, engine, emitted := setupServerWithControlEmitter(t)
+	defer server.Close()
+
+	err := engine.SetChargeStationAuth(context.Background(), "cs001", &store.ChargeStationAuth{
+		SecurityProfile:      1,
+		Base64SHA256Password: "DEADBEEF",
+	})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodDelete, "/cs/cs001", nil)
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNoContent, rr.Result().StatusCode)
+	require.Len(t, *emitted, 1)
+	assert.Equal(t, "cs001", (*emitted)[0].csId)
+	assert.Equal(t, transport.ControlMessage{Type: transport.ControlDisconnect, Reason: "charge station deleted"}, (*emitted)[0].msg)
+
+	req = httptest.NewRequest(http.MethodDelete, "/cs/cs002", nil)
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Result().StatusCode)
+	assert.Len(t, *emitted, 1)
+}
+
+func TestLookupChargeStationConnection(t *testing.T) {
+	server, r, engine, c := setupServer(t)
+	defer server.Close()
+
+	connectedAt := c.Now().UTC()
+	err := engine.SetChargeStationConnection(context.Background(), "cs001", &store.ChargeStationConnection{
+		Connected:       true,
+		GatewayId:       "gateway1",
+		ConnectionId:    "abc123",
+		OcppVersion:     "ocpp2.0.1",
+		RemoteAddr:      "127.0.0.1:1234",
+		SecurityProfile: store.TLSWithBasicAuth,
+		ConnectedAt:     connectedAt,
+	})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "/cs/cs001/connection", nil)
+	req.Header.Set("accept", "application/json")
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Result().StatusCode)
+
+	gatewayId, ocppVersion, remoteAddr, securityProfile := "gateway1", "ocpp2.0.1", "127.0.0.1:1234", 1
+	want := api.ChargeStationConnection{
+		Id:              "cs001",
+		Connected:       true,
+		GatewayId:       &gatewayId,
+		OcppVersion:     &ocppVersion,
+		RemoteAddr:      &remoteAddr,
+		SecurityProfile: &securityProfile,
+		ConnectedAt:     &connectedAt,
+	}
+
+	var got api.ChargeStationConnection
+	err = json.NewDecoder(rr.Result().Body).Decode(&got)
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+
+	req = httptest.NewRequest(http.MethodGet, "/cs/unknown/connection", nil)
+	req.Header.Set("accept", "application/json")
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Result().StatusCode)
+}
+
+func TestListConnectedChargeStations(t *testing.T) {
+	server, r, engine, c := setupServer(t)
+	defer server.Close()
+
+	for _, csId := range []string{"cs001", "cs002", "cs003"} {
+		err := engine.SetChargeStationConnection(context.Background(), csId, &store.ChargeStationConnection{
+			Connected:      csId != "cs002",
+			GatewayId:      "gateway1",
+			ConnectedAt:    c.Now().UTC(),
+			DisconnectedAt: c.Now().UTC(),
+		})
+		require.NoError(t, err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/connection", nil)
+	req.Header.Set("accept", "application/json")
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Result().StatusCode)
+	var got []api.ChargeStationConnection
+	err := json.NewDecoder(rr.Result().Body).Decode(&got)
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.Equal(t, "cs001", got[0].Id)
+	assert.True(t, got[0].Connected)
+	assert.Nil(t, got[0].DisconnectedAt)
+	assert.Equal(t, "cs003", got[1].Id)
+}
+
+func TestRecordChargeStationAuthFailureLocksOutChargeStation(t *testing.T) {
+	server, r, _, c := setupServer(t)
+	defer server.Close()
+
+	var got api.AuthLockoutStatus
+	for i := 0; i < 5; i++ {
+		req := httptest.NewRequest(http.MethodPost, "/cs/cs001/auth/failure", strings.NewReader(`{"remoteAddr":"192.168.1.1"}`))
+		req.Header.Set("content-type", "application/json")
+		req.Header.Set("accept", "application/json")
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+
+		require.Equal(t, http.StatusOK, rr.Result().StatusCode)
+		got = api.AuthLockoutStatus{}
+		err := json.NewDecoder(rr.Result().Body).Decode(&got)
+		require.NoError(t, err)
+	}
+
+	lockedUntil := c.Now().UTC().Add(time.Minute)
+	assert.Equal(t, api.AuthLockoutStatus{ChargeStationLockedUntil: &lockedUntil}, got)
+
+	req := httptest.NewRequest(http.MethodGet, "/cs/cs001/auth/lockout?remoteAddr=192.168.1.1", nil)
+	req.Header.Set("accept", "application/json")
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusOK, rr.Result().StatusCode)
+	got = api.AuthLockoutStatus{}
+	err := json.NewDecoder(rr.Result().Body).Decode(&got)
+	require.NoError(t, err)
+	assert.Equal(t, api.AuthLockoutStatus{ChargeStationLockedUntil: &lockedUntil}, got)
+}
+
+func TestListAndDeleteAuthLockouts(t *testing.T) {
+	server, r, engine, c := setupServer(t)
+	defer server.Close()
+
+	now := c.Now().UTC()
+	for _, id := range []string{"cs001", "cs002"} {
+		lockedUntil := now.Add(time.Minute)
+		if id == "cs002" {
+			lockedUntil = now.Add(-time.Minute)
+		}
+		err := engine.SetAuthLockout(context.Background(), &store.AuthLockout{
+			Type:          store.AuthLockoutTypeChargeStation,
+			Id:            id,
+			Lockouts:      1,
+			LastFailureAt: now,
+			LockedUntil:   lockedUntil,
+		})
+		require.NoError(t, err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/auth/lockout", nil)
+	req.Header.Set("accept", "application/json")
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusOK, rr.Result().StatusCode)
+	var got []api.AuthLockout
+	err := json.NewDecoder(rr.Result().Body).Decode(&got)
+	require.NoError(t, err)
+	want := []api.AuthLockout{
+		{
+			Type:          api.AuthLockoutTypeChargeStation,
+			Id:            "cs001",
+			Lockouts:      1,
+			LastFailureAt: &now,
+			LockedUntil:   now.Add(time.Minute),
+		},
+	}
+	assert.Equal(t, want, got)
+
+	req = httptest.NewRequest(http.MethodDelete, "/auth/lockout/ChargeStation/cs001", nil)
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusNoContent, rr.Result().StatusCode)
+
+	lockout, err := engine.LookupAuthLockout(context.Background(), store.AuthLockoutTypeChargeStation, "cs001")
+	require.NoError(t, err)
+	assert.Nil(t, lockout)
+}
+
+func TestSetToken(t *testing.T) {
+	server, r, engine, _ := setupServer(t)
+	defer server.Close()
+
+	token := api.Token{
+		CacheMode:   "ALWAYS",
+		ContractId:  "GB-TWK-012345678-V",
+		CountryCode: "GB",
+		Issuer:      "Thoughtworks",
+		PartyId:     "TWK",
+		Type:        "RFID",
+		Uid:         "012345678",
+		Valid:       true,
+	}
+	tokenPayload, err := json.Marshal(token)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/token", bytes.NewReader(tokenPayload))
+	req.Header.Set("content-type", "application/json")
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Result().StatusCode)
+	b, err := io.ReadAll(rr.Result().Body)
+	require.NoError(t, err)
+	assert.Equal(t, "", string(b))
+
+	want := &store.Token{
+		CountryCode: "GB",
+		PartyId:     "TWK",
+		Type:        "RFID",
+		Uid:         "012345678",
+		ContractId:  "GBTWK012345678V",
+		Issuer:      "Thoughtworks",
+		Valid:       true,
+		CacheMode:   "ALWAYS",
+	}
+
+	got, err := engine.LookupToken(context.Background(), "012345678")
+
+	assert.Regexp(t, `^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}Z`, got.LastUpdated)
+	got.LastUpdated = ""
+
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+}
+
+func TestLookupToken(t *testing.T) {
+	server, r, engine, c := setupServer(t)
+	now := c.Now()
+	defer server.Close()
+
+	err := engine.SetToken(context.Background(), &store.Token{
+		CountryCode: "GB",
+		PartyId:     "TWK",
+		Type:        "RFID",
+		Uid:         "012345678",
+		ContractId:  "GBTWK012345678V",
+		Issuer:      "Thoughtworks",
+		Valid:       true,
+		CacheMode:   "ALWAYS",
+		LastUpdated: now.Format(time.RFC3339),
+	})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "/token/012345678", nil)
+	req.Header.Set("accept", "application/json")
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Result().StatusCode)
+	decoder := json.NewDecoder(rr.Result().Body)
+	var got api.Token
+	err = decoder.Decode(&got)
+	require.NoError(t, err)
+
+	lastUpdatedStr := now.Format(time.RFC3339)
+	lastUpdated, err := time.Parse(time.RFC3339, lastUpdatedStr)
+	require.NoError(t, err)
+	want := api.Token{
+		CacheMode:   "ALWAYS",
+		ContractId:  "GBTWK012345678V",
+		CountryCode: "GB",
+		Issuer:      "Thoughtworks",
+		LastUpdated: &lastUpdated,
+		PartyId:     "TWK",
+		Type:        "RFID",
+		Uid:         "012345678",
+		Valid:       true,
+	}
+
+	assert.Equal(t, want, got)
+}
+
+func TestListTokens(t *testing.T) {
+	ctx := context.Background()
+	server, r, engine, _ := setupServer(t)
+	defer server.Close()
+
+	tokens := make([]*store.Token, 20)
+	for i := 0; i < 20; i++ {
+		tokens[i] = &store.Token{
+			CountryCode: "GB",
+			PartyId:     "TWK",
+			Type:        "RFID",
+			Uid:         fmt.Sprintf("123456%02d", i),
+			ContractId:  "GBTWK012345678V",
+			Issuer:      "TWK",
+			Valid:       true,
+			CacheMode:   store.CacheModeAllowed,
+		}
+	}
+
+	for _, token := range tokens {
+		err := engine.SetToken(ctx, token)
+		require.NoError(t, err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/token", nil)
+	req.Header.Set("accept", "application/json")
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Result().StatusCode)
+	decoder := json.NewDecoder(rr.Result().Body)
+	var got []api.Token
+	err := decoder.Decode(&got)
+	require.NoError(t, err)
+
+	t.Logf("got: %+v", got)
+}
+
+func TestSetCertificate(t *testing.T) {
+	server, r, engine, _ := setupServer(t)
+	defer server.Close()
+
+	cert := generateCertificate(t)
+	pemCert := pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: cert.Raw,
+	})
+	encodedPemCert := strings.Replace(string(pemCert), "\n", "\\n", -1)
+
+	req := httptest.NewRequest(http.MethodPost, "/certificate", strings.NewReader(fmt.Sprintf(`{"certificate":"%s"}`, encodedPemCert)))
+	req.Header.Set("content-type", "application/json")
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Result().StatusCode)
+	b, err := io.ReadAll(rr.Result().Body)
+	require.NoError(t, err)
+	assert.Equal(t, "", string(b))
+
+	b64Hash := getCertificateHash(cert)
+
+	got, err := engine.LookupCertificate(context.Background(), b64Hash)
+	require.NoError(t, err)
+
+	assert.Equal(t, string(pemCert), got)
+}
+
+func TestDeleteCertificate(t *testing.T) {
+	server, r, engine, _ := setupServer(t)
+	defer server.Close()
+
+	cert := generateCertificate(t)
+	pemCert := pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: cert.Raw,
+	})
+
+	err := engine.SetCertificate(context.Background(), string(pemCert))
+	require.NoError(t, err)
+
+	b64Hash := getCertificateHash(cert)
+	req := httptest.NewRequest(http.MethodDelete, fmt.Sprintf("/certificate/%s", b64Hash), nil)
+	req.Header.Set("content-type", "application/json")
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNoContent, rr.Result().StatusCode)
+	b, err := io.ReadAll(rr.Result().Body)
+	require.NoError(t, err)
+	assert.Equal(t, "", string(b))
+
+	got, err := engine.LookupCertificate(context.Background(), b64Hash)
+	require.NoError(t, err)
+
+	assert.Equal(t, "", got)
+}
+
+func TestLookupCertificate(t *testing.T) {
+	server, r, engine, _ := setupServer(t)
+	defer server.Close()
+
+	cert := generateCertificate(t)
+	pemCert := pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: cert.Raw,
+	})
+
+	err := engine.SetCertificate(context.Background(), string(pemCert))
+	require.NoError(t, err)
+
+	b64Hash := getCertificateHash(cert)
+	req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/certificate/%s", b64Hash), nil)
+	req.Header.Set("content-type", "application/json")
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Result().StatusCode)
+	b, err := io.ReadAll(rr.Result().Body)
+	require.NoError(t, err)
+	assert.JSONEq(t, fmt.Sprintf(`{"certificate":"%s"}`, strings.Replace(string(pemCert), "\n", "\\n", -1)), string(b))
+}
+
+func TestRevokeChargeStationCertificate(t *testing.T) {
+	server, r, engine, clock := setupServer(t)
+	defer server.Close()
+
+	cert := generateCertificate(t)
+	pemCert := pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: cert.Raw,
+	})
+	encodedPemCert := strings.Replace(string(pemCert), "\n", "\\n", -1)
+
+	req := httptest.NewRequest(http.MethodPost, "/cs/cs001/certificate/revocation",
+		strings.NewReader(fmt.Sprintf(`{"certificate":"%s","reason":"key compromised"}`, encodedPemCert)))
+	req.Header.Set("content-type", "application/json")
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Result().StatusCode)
+
+	b64Hash := getCertificateHash(cert)
+	got, err := engine.LookupCertificateRevocation(context.Background(), b64Hash)
+	require.NoError(t, err)
+
+	want := &store.CertificateRevocation{
+		CertificateHash: b64Hash,
+		ChargeStationId: "cs001",
+		Reason:          "key compromised",
+		RevokedAt:       clock.Now(),
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestRevokeChargeStationCertificateDisconnectsChargeStation(t *testing.T) {
+	server, r, _, emitted := setupServerWithControlEmitter(t)
+	defer server.Close()
+
+	b64Hash := getCertificateHash(generateCertificate(t))
+	req := httptest.NewRequest(http.MethodPost, "/cs/cs001/certificate/revocation",
+		strings.NewReader(fmt.Sprintf(`{"certificateHash":"%s"}`, b64Hash)))
+	req.Header.Set("content-type", "application/json")
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Result().StatusCode)
+	require.Len(t, *emitted, 1)
+	assert.Equal(t, "cs001", (*emitted)[0].csId)
+	assert.Equal(t, transport.ControlMessage{Type: transport.ControlDisconnect, Reason: "certificate revoked"}, (*emitted)[0].msg)
+}
+
+func TestRevokeChargeStationCertificateByStdEncodedHash(t *testing.T) {
+	server, r, engine, _ := setupServer(t)
+	defer server.Close()
+
+	cert := generateCertificate(t)
+	hash := sha256.Sum256(cert.Raw)
+	stdHash := base64.StdEncoding.EncodeToString(hash[:])
+
+	req := httptest.NewRequest(http.MethodPost, "/cs/cs001/certificate/revocation",
+		strings.NewReader(fmt.Sprintf(`{"certificateHash":"%s"}`, stdHash)))
+	req.Header.Set("content-type", "application/json")
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Result().StatusCode)
+
+	got, err := engine.LookupCertificateRevocation(context.Background(), getCertificateHash(cert))
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, "cs001", got.ChargeStationId)
+}
+
+func TestRevokeChargeStationCertificateRequiresCertificateOrHash(t *testing.T) {
+	server, r, _, _ := setupServer(t)
+	defer server.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/cs/cs001/certificate/revocation",
+		strings.NewReader(`{"reason":"key compromised"}`))
+	req.Header.Set("content-type", "application/json")
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Result().StatusCode)
+}
+
+func TestLookupAndDeleteCertificateRevocation(t *testing.T) {
+	server, r, engine, clock := setupServer(t)
+	defer server.Close()
+
+	cert := generateCertificate(t)
+	b64Hash := getCertificateHash(cert)
+
+	err := engine.SetCertificateRevocation(context.Background(), &store.CertificateRevocation{
+		CertificateHash: b64Hash,
+		ChargeStationId: "cs001",
+		RevokedAt:       clock.Now(),
+	})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/certificate/%s/revocation", b64Hash), nil)
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Result().StatusCode)
+	b, err := io.ReadAll(rr.Result().Body)
+	require.NoError(t, err)
+	assert.JSONEq(t, fmt.Sprintf(`{"certificateHash":"%s","csId":"cs001","revokedAt":"%s"}`,
+		b64Hash, clock.Now().Format(time.RFC3339Nano)), string(b))
+
+	req = httptest.NewRequest(http.MethodDelete, fmt.Sprintf("/certificate/%s/revocation", b64Hash), nil)
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNoContent, rr.Result().StatusCode)
+
+	req = httptest.NewRequest(http.MethodGet, fmt.Sprintf("/certificate/%s/revocation", b64Hash), nil)
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Result().StatusCode)
+}
+
+func TestListExpiringCertificates(t *testing.T) {
+	server, r, engine, clock := setupServer(t)
+	defer server.Close()
+
+	now := clock.Now().UTC()
+	certificates := []*store.IssuedCertificate{
+		{CertificateId: "cert001", ChargeStationId: "cs001", CertificateType: store.CertificateTypeChargeStation, SerialNumber: "1a", IssuedAt: now.Add(-24 * time.Hour), NotAfter: now.Add(10 * 24 * time.Hour), RenewalRequestedAt: now},
+		{CertificateId: "cert002", ChargeStationId: "cs002", CertificateType: store.CertificateTypeEVCC, IssuedAt: now.Add(-24 * time.Hour), NotAfter: now.Add(5 * 24 * time.Hour)},
+		{CertificateId: "cert003", ChargeStationId: "cs003", CertificateType: store.CertificateTypeEVCC, IssuedAt: now.Add(-24 * time.Hour), NotAfter: now.Add(60 * 24 * time.Hour)},
+	}
+	for _, certificate := range certificates {
+		err := engine.SetIssuedCertificate(context.Background(), certificate)
+		require.NoError(t, err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/expiring-certificates", nil)
+	req.Header.Set("accept", "application/json")
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Result().StatusCode)
+
+	var got []api.IssuedCertificate
+	err := json.NewDecoder(rr.Result().Body).Decode(&got)
+	require.NoError(t, err)
+	serialNumber := "1a"
+	want := []api.IssuedCertificate{
+		{CertificateId: "cert002", CsId: "cs002", CertificateType: api.IssuedCertificateCertificateTypeV2G, IssuedAt: now.Add(-24 * time.Hour), NotAfter: now.Add(5 * 24 * time.Hour)},
+		{CertificateId: "cert001", CsId: "cs001", CertificateType: api.IssuedCertificateCertificateTypeChargeStation, SerialNumber: &serialNumber, IssuedAt: now.Add(-24 * time.Hour), NotAfter: now.Add(10 * 24 * time.Hour), RenewalRequestedAt: &now},
+	}
+	assert.Equal(t, want, got)
+
+	req = httptest.NewRequest(http.MethodGet, "/expiring-certificates?days=7", nil)
+	req.Header.Set("accept", "application/json")
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Result().StatusCode)
+
+	got = nil
+	err = json.NewDecoder(rr.Result().Body).Decode(&got)
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, "cert002", got[0].CertificateId)
+}
+
+func TestSetListAndDeleteRootCertificates(t *testing.T) {
+	server, r, _, _ := setupServer(t)
+	defer server.Close()
+
+	cert := generateCertificate(t)
+	pemCertificate := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}))
+	body, err := json.Marshal(api.RootCertificate{
+		CertificateType: api.V2G,
+		Certificate:     pemCertificate,
+	})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/root-certificate", bytes.NewReader(body))
+	req.Header.Set("content-type", "application/json")
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusCreated, rr.Result().StatusCode)
+
+	req = httptest.NewRequest(http.MethodGet, "/root-certificate", nil)
+	req.Header.Set("accept", "application/json")
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Result().StatusCode)
+
+	var got []api.RootCertificate
+	err = json.NewDecoder(rr.Result().Body).Decode(&got)
+	require.NoError(t, err)
+	hash := sha256.Sum256(cert.Raw)
+	certificateId := hex.EncodeToString(hash[:])
+	want := []api.RootCertificate{
+		{CertificateId: &certificateId, CertificateType: api.V2G, Certificate: pemCertificate},
+	}
+	assert.Equal(t, want, got)
+
+	req = httptest.NewRequest(http.MethodDelete, "/root-certificate/"+certificateId, nil)
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusNoContent, rr.Result().StatusCode)
+
+	req = httptest.NewRequest(http.MethodGet, "/root-certificate", nil)
+	req.Header.Set("accept", "application/json")
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Result().StatusCode)
+
+	got = nil
+	err = json.NewDecoder(rr.Result().Body).Decode(&got)
+	require.NoError(t, err)
+	assert.Empty(t, got)
+}
+
+func TestSetRootCertificateWithInvalidCertificate(t *testing.T) {
+	server, r, _, _ := setupServer(t)
+	defer server.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/root-certificate", strings.NewReader(`{"certificateType":"V2G","certificate":"not a certificate"}`))
+	req.Header.Set("content-type", "application/json")
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Result().StatusCode)
+}
+
+func TestSetLookupAndDeleteContract(t *testing.T) {
+	server, r, engine, _ := setupServer(t)
+	defer server.Close()
+
+	body, err := json.Marshal(api.Contract{
+		Emaid:                     "GB-TWK-012345678",
+		ProvisioningCertificateId: "WMIV1234567890ABCDEX",
+	})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/contract", bytes.NewReader(body))
+	req.Header.Set("content-type", "application/json")
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusCreated, rr.Result().StatusCode)
+
+	contract, err := engine.LookupContractByProvisioningCertificateId(context.Background(), "WMIV1234567890ABCDEX")
+	require.NoError(t, err)
+	assert.Equal(t, &store.Contract{EMAID: "GBTWK012345678V", ProvisioningCertificateId: "WMIV1234567890ABCDEX"}, contract)
+
+	req = httptest.NewRequest(http.MethodGet, "/contract/GBTWK012345678V", nil)
+	req.Header.Set("accept", "application/json")
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Result().StatusCode)
+
+	var got api.Contract
+	err = json.NewDecoder(rr.Result().Body).Decode(&got)
+	require.NoError(t, err)
+	assert.Equal(t, api.Contract{Emaid: "GBTWK012345678V", ProvisioningCertificateId: "WMIV1234567890ABCDEX"}, got)
+
+	req = httptest.NewRequest(http.MethodDelete, "/contract/GB-TWK-012345678-V", nil)
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusNoContent, rr.Result().StatusCode)
+
+	req = httptest.NewRequest(http.MethodGet, "/contract/GBTWK012345678V", nil)
+	req.Header.Set("accept", "application/json")
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusNotFound, rr.Result().StatusCode)
+}
+
+func TestSetContractWithInvalidEmaid(t *testing.T) {
+	server, r, _, _ := setupServer(t)
+	defer server.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/contract", strings.NewReader(`{"emaid":"not-an-emaid","provisioningCertificateId":"WMIV1234567890ABCDEX"}`))
+	req.Header.Set("content-type", "application/json")
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Result().StatusCode)
+}
+
+func TestInstalledCertificatesComplianceAndRemediation(t *testing.T) {
+	engine := inmemory.NewStore(clock.RealClock{})
+	now := time.Now().UTC()
+	srv, err := api.NewServer(engine, clockTest.NewFakePassiveClock(now), nil, nil)
+	require.NoError(t, err)
+	notifier := &recordingSyncNotifier{}
+	srv.SetSyncNotifier(notifier)
 
-	want := &store.Token{
-		CountryCode: "GB",
-		PartyId:     "TWK",
-		Type:        "RFID",
-		Uid:         "012345678",
-		ContractId:  "GBTWK012345678V",
-		Issuer:      "Thoughtworks",
-		Valid:       true,
-		CacheMode:   "ALWAYS",
-	}
+	r := chi.NewRouter()
+	r.Use(api.ValidationMiddleware)
+	r.Mount("/", api.Handler(srv))
 
-	got, err := engine.LookupToken(context.Background(), "012345678")
+	ctx := context.Background()
 
-	assert.Regexp(t, `^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}Z`, got.LastUpdated)
-	got.LastUpdated = ""
+	currentCert := generateCertificate(t)
+	currentPem := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: currentCert.Raw}))
+	revokedCert := generateCertificate(t)
+	revokedPem := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: revokedCert.Raw}))
+	currentId, err := handlers.GetCertificateId(currentPem)
+	require.NoError(t, err)
+	revokedId, err := handlers.GetCertificateId(revokedPem)
+	require.NoError(t, err)
 
+	err = engine.SetRootCertificate(ctx, &store.RootCertificate{CertificateId: currentId, CertificateType: store.CertificateTypeV2G, CertificateData: currentPem})
+	require.NoError(t, err)
+	err = engine.SetRootCertificate(ctx, &store.RootCertificate{CertificateId: revokedId, CertificateType: store.CertificateTypeMO, CertificateData: revokedPem, Revoked: true})
 	require.NoError(t, err)
-	assert.Equal(t, want, got)
-}
 
-func TestLookupToken(t *testing.T) {
-	server, r, engine, c := setupServer(t)
-	now := c.Now()
-	defer server.Close()
+	currentHashData, err := handlers.GetCertificateHashData(currentPem, ocpp201.HashAlgorithmEnumTypeSHA256)
+	require.NoError(t, err)
+	currentHashData.CertificateType = store.CertificateTypeV2G
+	revokedHashData, err := handlers.GetCertificateHashData(revokedPem, ocpp201.HashAlgorithmEnumTypeSHA256)
+	require.NoError(t, err)
+	revokedHashData.CertificateType = store.CertificateTypeMO
 
-	err := engine.SetToken(context.Background(), &store.Token{
-		CountryCode: "GB",
-		PartyId:     "TWK",
-		Type:        "RFID",
-		Uid:         "012345678",
-		ContractId:  "GBTWK012345678V",
-		Issuer:      "Thoughtworks",
-		Valid:       true,
-		CacheMode:   "ALWAYS",
-		LastUpdated: now.Format(time.RFC3339),
+	// cs001 is missing the current root and still has the revoked root installed
+	err = engine.SetChargeStationInstalledCertificates(ctx, "cs001", &store.ChargeStationInstalledCertificates{
+		Certificates: []*store.ChargeStationInstalledCertificate{revokedHashData},
+		RequestedAt:  now.Add(-time.Minute),
+		ReportedAt:   now,
+	})
+	require.NoError(t, err)
+	// cs002 is compliant
+	err = engine.SetChargeStationInstalledCertificates(ctx, "cs002", &store.ChargeStationInstalledCertificates{
+		Certificates: []*store.ChargeStationInstalledCertificate{currentHashData},
+		ReportedAt:   now,
+	})
+	require.NoError(t, err)
+	// cs003 has not yet reported its installed certificates
+	err = engine.SetChargeStationInstalledCertificates(ctx, "cs003", &store.ChargeStationInstalledCertificates{
+		RequestedAt: now,
 	})
 	require.NoError(t, err)
 
-	req := httptest.NewRequest(http.MethodGet, "/token/012345678", nil)
+	req := httptest.NewRequest(http.MethodGet, "/cs/cs001/installed-certificates", nil)
 	req.Header.Set("accept", "application/json")
 	rr := httptest.NewRecorder()
 	r.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Result().StatusCode)
 
-	assert.Equal(t, http.StatusOK, rr.Result().StatusCode)
-	decoder := json.NewDecoder(rr.Result().Body)
-	var got api.Token
-	err = decoder.Decode(&got)
+	var installed api.ChargeStationInstalledCertificates
+	err = json.NewDecoder(rr.Result().Body).Decode(&installed)
 	require.NoError(t, err)
+	requestedAt := now.Add(-time.Minute)
+	assert.Equal(t, api.ChargeStationInstalledCertificates{
+		CsId: "cs001",
+		Certificates: []api.InstalledCertificate{
+			{
+				CertificateType: api.InstalledCertificateCertificateTypeMO,
+				HashAlgorithm:   "SHA256",
+				IssuerNameHash:  revokedHashData.IssuerNameHash,
+				IssuerKeyHash:   revokedHashData.IssuerKeyHash,
+				SerialNumber:    revokedHashData.SerialNumber,
+			},
+		},
+		RequestedAt:             &requestedAt,
+		ReportedAt:              &now,
+		MissingRootCertificates: []string{currentId},
+		RevokedRootCertificates: []string{revokedId},
+	}, installed)
 
-	lastUpdatedStr := now.Format(time.RFC3339)
-	lastUpdated, err := time.Parse(time.RFC3339, lastUpdatedStr)
+	req = httptest.NewRequest(http.MethodGet, "/noncompliant-certificates", nil)
+	req.Header.Set("accept", "application/json")
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Result().StatusCode)
+
+	var noncompliant []api.InstalledCertificateCompliance
+	err = json.NewDecoder(rr.Result().Body).Decode(&noncompliant)
 	require.NoError(t, err)
-	want := api.Token{
-		CacheMode:   "ALWAYS",
-		ContractId:  "GBTWK012345678V",
-		CountryCode: "GB",
-		Issuer:      "Thoughtworks",
-		LastUpdated: &lastUpdated,
-		PartyId:     "TWK",
-		Type:        "RFID",
-		Uid:         "012345678",
-		Valid:       true,
+	want := api.InstalledCertificateCompliance{
+		CsId:                    "cs001",
+		MissingRootCertificates: []string{currentId},
+		RevokedRootCertificates: []string{revokedId},
 	}
+	assert.Equal(t, []api.InstalledCertificateCompliance{want}, noncompliant)
 
-	assert.Equal(t, want, got)
-}
+	req = httptest.NewRequest(http.MethodPost, "/cs/cs001/installed-certificates/remediate", nil)
+	req.Header.Set("accept", "application/json")
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Result().StatusCode)
 
-func TestListTokens(t *testing.T) {
-	ctx := context.Background()
-	server, r, engine, _ := setupServer(t)
-	defer server.Close()
+	var remediated api.InstalledCertificateCompliance
+	err = json.NewDecoder(rr.Result().Body).Decode(&remediated)
+	require.NoError(t, err)
+	assert.Equal(t, want, remediated)
+	assert.Equal(t, []string{"cs001"}, notifier.pending)
 
-	tokens := make([]*store.Token, 20)
-	for i := 0; i < 20; i++ {
-		tokens[i] = &store.Token{
-			CountryCode: "GB",
-			PartyId:     "TWK",
-			Type:        "RFID",
-			Uid:         fmt.Sprintf("123456%02d", i),
-			ContractId:  "GBTWK012345678V",
-			Issuer:      "TWK",
-			Valid:       true,
-			CacheMode:   store.CacheModeAllowed,
-		}
-	}
+	installCertificates, err := engine.LookupChargeStationInstallCertificates(ctx, "cs001")
+	require.NoError(t, err)
+	require.Len(t, installCertificates.Certificates, 1)
+	assert.Equal(t, currentId, installCertificates.Certificates[0].CertificateId)
+	assert.Equal(t, store.CertificateTypeV2G, installCertificates.Certificates[0].CertificateType)
+	assert.Equal(t, store.CertificateInstallationPending, installCertificates.Certificates[0].CertificateInstallationStatus)
 
-	for _, token := range tokens {
-		err := engine.SetToken(ctx, token)
-		require.NoError(t, err)
-	}
+	installedCertificates, err := engine.LookupChargeStationInstalledCertificates(ctx, "cs001")
+	require.NoError(t, err)
+	require.Len(t, installedCertificates.Certificates, 1)
+	assert.Equal(t, store.CertificateDeletionPending, installedCertificates.Certificates[0].DeletionStatus)
 
-	req := httptest.NewRequest(http.MethodGet, "/token", nil)
-	req.Header.Set("accept", "application/json")
-	rr := httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodPost, "/cs/cs003/installed-certificates/remediate", nil)
+	rr = httptest.NewRecorder()
 	r.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusNotFound, rr.Result().StatusCode)
 
-	assert.Equal(t, http.StatusOK, rr.Result().StatusCode)
-	decoder := json.NewDecoder(rr.Result().Body)
-	var got []api.Token
-	err := decoder.Decode(&got)
-	require.NoError(t, err)
-
-	t.Logf("got: %+v", got)
+	req = httptest.NewRequest(http.MethodGet, "/cs/unknown/installed-certificates", nil)
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusNotFound, rr.Result().StatusCode)
 }
 
-func TestSetCertificate(t *testing.T) {
-	server, r, engine, _ := setupServer(t)
+func TestCreateLookupListAndCancelCertificateCampaign(t *testing.T) {
+	server, r, engine, c := setupServer(t)
 	defer server.Close()
 
+	now := c.Now().UTC()
+	for _, cs := range []struct {
+		csId        string
+		tags        []string
+		ocppVersion string
+	}{
+		{csId: "cs001", tags: []string{"fleet"}, ocppVersion: "2.0.1"},
+		{csId: "cs002", tags: []string{"fleet"}, ocppVersion: "1.6"},
+		{csId: "cs003", tags: []string{"fleet"}, ocppVersion: "2.0.1"},
+		{csId: "cs004", ocppVersion: "2.0.1"},
+	} {
+		err := engine.SetChargeStationDetails(context.Background(), cs.csId, &store.ChargeStationDetails{
+			Tags:         cs.tags,
+			RegisteredAt: now,
+		})
+		require.NoError(t, err)
+		err = engine.SetChargeStationRuntimeDetails(context.Background(), cs.csId, &store.ChargeStationRuntimeDetails{
+			OcppVersion: cs.ocppVersion,
+		})
+		require.NoError(t, err)
+	}
+
 	cert := generateCertificate(t)
-	pemCert := pem.EncodeToMemory(&pem.Block{
-		Type:  "CERTIFICATE",
-		Bytes: cert.Raw,
+	pemCertificate := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}))
+	tag, ocppVersion, waveSize, waveIntervalMinutes, maxFailures := "fleet", "2.0.1", 1, 30, 2
+	body, err := json.Marshal(api.CertificateCampaignRequest{
+		CertificateType:     api.CertificateCampaignRequestCertificateTypeV2G,
+		Certificate:         pemCertificate,
+		Tag:                 &tag,
+		OcppVersion:         &ocppVersion,
+		WaveSize:            &waveSize,
+		WaveIntervalMinutes: &waveIntervalMinutes,
+		MaxFailures:         &maxFailures,
 	})
-	encodedPemCert := strings.Replace(string(pemCert), "\n", "\\n", -1)
+	require.NoError(t, err)
 
-	req := httptest.NewRequest(http.MethodPost, "/certificate", strings.NewReader(fmt.Sprintf(`{"certificate":"%s"}`, encodedPemCert)))
+	req := httptest.NewRequest(http.MethodPost, "/certificate-campaign", bytes.NewReader(body))
 	req.Header.Set("content-type", "application/json")
 	rr := httptest.NewRecorder()
 	r.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusCreated, rr.Result().StatusCode)
 
-	assert.Equal(t, http.StatusCreated, rr.Result().StatusCode)
-	b, err := io.ReadAll(rr.Result().Body)
+	var created api.CertificateCampaign
+	err = json.NewDecoder(rr.Result().Body).Decode(&created)
 	require.NoError(t, err)
-	assert.Equal(t, "", string(b))
-
-	b64Hash := getCertificateHash(cert)
+	hash := sha256.Sum256(cert.Raw)
+	want := api.CertificateCampaign{
+		Id:                  created.Id,
+		CertificateType:     api.CertificateCampaignCertificateTypeV2G,
+		CertificateId:       hex.EncodeToString(hash[:]),
+		Tag:                 &tag,
+		OcppVersion:         &ocppVersion,
+		WaveSize:            1,
+		WaveIntervalMinutes: 30,
+		MaxFailures:         2,
+		Status:              api.CertificateCampaignStatusInProgress,
+		CreatedAt:           now,
+		NextWaveAt:          &now,
+		Progress:            api.CertificateCampaignProgress{Total: 2, Waiting: 2},
+	}
+	assert.NotEmpty(t, created.Id)
+	assert.Equal(t, want, created)
 
-	got, err := engine.LookupCertificate(context.Background(), b64Hash)
+	// the results for charge stations are recorded against the campaign
+	_, err = engine.UpdateCertificateCampaign(context.Background(), created.Id, func(campaign *store.CertificateCampaign) bool {
+		campaign.Waves = 1
+		return true
+	})
+	require.NoError(t, err)
+	_, err = engine.UpdateCertificateCampaignStation(context.Background(), created.Id, "cs001",
+		func(campaign *store.CertificateCampaign, station *store.CertificateCampaignStation) bool {
+			station.Status = store.CertificateCampaignStationAccepted
+			station.Wave = 1
+			station.UpdatedAt = now
+			return true
+		})
 	require.NoError(t, err)
 
-	assert.Equal(t, string(pemCert), got)
-}
-
-func TestDeleteCertificate(t *testing.T) {
-	server, r, engine, _ := setupServer(t)
-	defer server.Close()
-
-	cert := generateCertificate(t)
-	pemCert := pem.EncodeToMemory(&pem.Block{
-		Type:  "CERTIFICATE",
-		Bytes: cert.Raw,
-	})
+	req = httptest.NewRequest(http.MethodGet, "/certificate-campaign/"+created.Id, nil)
+	req.Header.Set("accept", "application/json")
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Result().StatusCode)
 
-	err := engine.SetCertificate(context.Background(), string(pemCert))
+	var got api.CertificateCampaign
+	err = json.NewDecoder(rr.Result().Body).Decode(&got)
 	require.NoError(t, err)
+	wave := 1
+	want.Waves = 1
+	want.Progress = api.CertificateCampaignProgress{Total: 2, Waiting: 1, Accepted: 1}
+	want.Stations = &[]api.CertificateCampaignStation{
+		{CsId: "cs001", Status: api.CertificateCampaignStationStatusAccepted, Wave: &wave, UpdatedAt: &now},
+		{CsId: "cs003", Status: api.CertificateCampaignStationStatusWaiting},
+	}
+	assert.Equal(t, want, got)
 
-	b64Hash := getCertificateHash(cert)
-	req := httptest.NewRequest(http.MethodDelete, fmt.Sprintf("/certificate/%s", b64Hash), nil)
-	req.Header.Set("content-type", "application/json")
-	rr := httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodPost, "/certificate-campaign/"+created.Id+"/cancel", nil)
+	req.Header.Set("accept", "application/json")
+	rr = httptest.NewRecorder()
 	r.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Result().StatusCode)
 
-	assert.Equal(t, http.StatusNoContent, rr.Result().StatusCode)
-	b, err := io.ReadAll(rr.Result().Body)
-	require.NoError(t, err)
-	assert.Equal(t, "", string(b))
+	req = httptest.NewRequest(http.MethodGet, "/certificate-campaign?status=Cancelled", nil)
+	req.Header.Set("accept", "application/json")
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Result().StatusCode)
 
-	got, err := engine.LookupCertificate(context.Background(), b64Hash)
+	var list []api.CertificateCampaign
+	err = json.NewDecoder(rr.Result().Body).Decode(&list)
 	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, created.Id, list[0].Id)
+	assert.Equal(t, api.CertificateCampaignStatusCancelled, list[0].Status)
+	assert.Nil(t, list[0].NextWaveAt)
+	assert.Nil(t, list[0].Stations)
 
-	assert.Equal(t, "", got)
+	req = httptest.NewRequest(http.MethodGet, "/certificate-campaign/unknown", nil)
+	req.Header.Set("accept", "application/json")
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusNotFound, rr.Result().StatusCode)
+
+	req = httptest.NewRequest(http.MethodPost, "/certificate-campaign/unknown/cancel", nil)
+	req.Header.Set("accept", "application/json")
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusNotFound, rr.Result().StatusCode)
 }
 
-func TestLookupCertificate(t *testing.T) {
-	server, r, engine, _ := setupServer(t)
+func TestCreateCertificateCampaignWithNoMatchingChargeStations(t *testing.T) {
+	server, r, engine, c := setupServer(t)
 	defer server.Close()
 
+	setChargeStationDetails(t, engine, "cs001", "Acme", "Wallbox", c.Now().UTC())
+
 	cert := generateCertificate(t)
-	pemCert := pem.EncodeToMemory(&pem.Block{
-		Type:  "CERTIFICATE",
-		Bytes: cert.Raw,
+	pemCertificate := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}))
+	tag := "unknown"
+	body, err := json.Marshal(api.CertificateCampaignRequest{
+		CertificateType: api.CertificateCampaignRequestCertificateTypeCSMS,
+		Certificate:     pemCertificate,
+		Tag:             &tag,
 	})
-
-	err := engine.SetCertificate(context.Background(), string(pemCert))
 	require.NoError(t, err)
 
-	b64Hash := getCertificateHash(cert)
-	req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/certificate/%s", b64Hash), nil)
+	req := httptest.NewRequest(http.MethodPost, "/certificate-campaign", bytes.NewReader(body))
 	req.Header.Set("content-type", "application/json")
 	rr := httptest.NewRecorder()
 	r.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Result().StatusCode)
 
-	assert.Equal(t, http.StatusOK, rr.Result().StatusCode)
-	b, err := io.ReadAll(rr.Result().Body)
+	campaigns, err := engine.ListCertificateCampaigns(context.Background(), "", 0, 10)
 	require.NoError(t, err)
-	assert.JSONEq(t, fmt.Sprintf(`{"certificate":"%s"}`, strings.Replace(string(pemCert), "\n", "\\n", -1)), string(b))
+	assert.Empty(t, campaigns)
 }
 
 func TestRegisterLocation(t *testing.T) {
@@ -353,13 +1610,123 @@ func TestRegisterLocation(t *testing.T) {
 	assert.Equal(t, want, got)
 }
 
+func setFailedDelivery(t *testing.T, engine store.Engine, now time.Time) {
+	err := engine.SetDelivery(context.Background(), &store.OcpiDelivery{
+		Id:          "delivery001",
+		CountryCode: "GB",
+		PartyId:     "EMS",
+		Method:      http.MethodPost,
+		Url:         "https://example.com/commands/RESERVE_NOW/1",
+		Body:        `{"result":"ACCEPTED"}`,
+		Status:      store.OcpiDeliveryStatusFailed,
+		Attempts:    10,
+		LastError:   "status code: 503",
+		NextAttempt: now,
+		Created:     now,
+	})
+	require.NoError(t, err)
+}
+
+func TestListOcpiDeliveries(t *testing.T) {
+	server, r, engine, c := setupServer(t)
+	defer server.Close()
+
+	setFailedDelivery(t, engine, c.Now())
+
+	req := httptest.NewRequest(http.MethodGet, "/ocpi/delivery", nil)
+	req.Header.Set("accept", "application/json")
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Result().StatusCode)
+	var got []api.OcpiDelivery
+	err := json.NewDecoder(rr.Result().Body).Decode(&got)
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, "delivery001", got[0].Id)
+	assert.Equal(t, api.OcpiDeliveryStatusFailed, got[0].Status)
+	assert.Nil(t, got[0].Module)
+
+	req = httptest.NewRequest(http.MethodGet, "/ocpi/delivery?status=Pending", nil)
+	req.Header.Set("accept", "application/json")
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Result().StatusCode)
+	err = json.NewDecoder(rr.Result().Body).Decode(&got)
+	require.NoError(t, err)
+	assert.Len(t, got, 0)
+}
+
+func TestLookupOcpiDelivery(t *testing.T) {
+	server, r, engine, c := setupServer(t)
+	defer server.Close()
+
+	setFailedDelivery(t, engine, c.Now())
+
+	req := httptest.NewRequest(http.MethodGet, "/ocpi/delivery/delivery001", nil)
+	req.Header.Set("accept", "application/json")
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Result().StatusCode)
+	var got api.OcpiDelivery
+	err := json.NewDecoder(rr.Result().Body).Decode(&got)
+	require.NoError(t, err)
+	assert.Equal(t, "status code: 503", *got.LastError)
+	assert.Equal(t, `{"result":"ACCEPTED"}`, *got.Body)
+
+	req = httptest.NewRequest(http.MethodGet, "/ocpi/delivery/unknown", nil)
+	req.Header.Set("accept", "application/json")
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Result().StatusCode)
+}
+
+func TestReplayOcpiDelivery(t *testing.T) {
+	server, r, engine, c := setupServer(t)
+	defer server.Close()
+
+	setFailedDelivery(t, engine, c.Now().Add(-time.Hour))
+
+	req := httptest.NewRequest(http.MethodPost, "/ocpi/delivery/delivery001/replay", nil)
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusAccepted, rr.Result().StatusCode)
+
+	got, err := engine.LookupDelivery(context.Background(), "delivery001")
+	require.NoError(t, err)
+	assert.Equal(t, store.OcpiDeliveryStatusPending, got.Status)
+	assert.Equal(t, 0, got.Attempts)
+	assert.Equal(t, c.Now().UTC(), got.NextAttempt)
+}
+
+func TestDeleteOcpiDelivery(t *testing.T) {
+	server, r, engine, c := setupServer(t)
+	defer server.Close()
+
+	setFailedDelivery(t, engine, c.Now())
+
+	req := httptest.NewRequest(http.MethodDelete, "/ocpi/delivery/delivery001", nil)
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNoContent, rr.Result().StatusCode)
+
+	got, err := engine.LookupDelivery(context.Background(), "delivery001")
+	require.NoError(t, err)
+	assert.Nil(t, got)
+}
+
 func setupServer(t *testing.T) (*httptest.Server, *chi.Mux, store.Engine, clock.PassiveClock) {
 	engine := inmemory.NewStore(clock.RealClock{})
 	ocpiApi := ocpi.NewOCPI(engine, nil, "GB", "TWK")
 
 	now := time.Now().UTC()
 	c := clockTest.NewFakePassiveClock(now)
-	srv, err := api.NewServer(engine, c, ocpiApi)
+	srv, err := api.NewServer(engine, c, ocpiApi, nil)
 	require.NoError(t, err)
 
 	r := chi.NewRouter()
@@ -370,6 +1737,31 @@ func setupServer(t *testing.T) (*httptest.Server, *chi.Mux, store.Engine, clock.
 	return server, r, engine, c
 }
 
+type emittedControlMessage struct {
+	csId string
+	msg  transport.ControlMessage
+}
+
+func setupServerWithControlEmitter(t *testing.T) (*httptest.Server, *chi.Mux, store.Engine, *[]emittedControlMessage) {
+	engine := inmemory.NewStore(clock.RealClock{})
+
+	var emitted []emittedControlMessage
+	controlEmitter := transport.ControlEmitterFunc(func(ctx context.Context, chargeStationId string, message *transport.ControlMessage) error {
+		emitted = append(emitted, emittedControlMessage{csId: chargeStationId, msg: *message})
+		return nil
+	})
+
+	srv, err := api.NewServer(engine, clockTest.NewFakePassiveClock(time.Now()), nil, controlEmitter)
+	require.NoError(t, err)
+
+	r := chi.NewRouter()
+	r.Use(api.ValidationMiddleware)
+	r.Mount("/", api.Handler(srv))
+	server := httptest.NewServer(r)
+
+	return server, r, engine, &emitted
+}
+
 func generateCertificate(t *testing.T) *x509.Certificate {
 	keyPair, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
 	require.NoError(t, err)
@@ -22,7 +22,7 @@ func TestValidationMiddlewareWithInvalidRequest(t *testing.T) {
 	engine := inmemory.NewStore(clock.RealClock{})
 
 	now := time.Now()
-	srv, err := api.NewServer(engine, clockTest.NewFakePassiveClock(now), nil)
+	srv, err := api.NewServer(engine, clockTest.NewFakePassiveClock(now), nil, nil)
 	require.NoError(t, err)
 
 	r := chi.NewRouter()
@@ -46,7 +46,7 @@ func TestValidationMiddlewareWithValidRequest(t *testing.T) {
 	engine := inmemory.NewStore(clock.RealClock{})
 
 	now := time.Now()
-	srv, err := api.NewServer(engine, clockTest.NewFakePassiveClock(now), nil)
+	srv, err := api.NewServer(engine, clockTest.NewFakePassiveClock(now), nil, nil)
 	require.NoError(t, err)
 
 	r := chi.NewRouter()
@@ -87,7 +87,7 @@ func TestValidationMiddlewareWithUnknownMethod(t *testing.T) {
 	engine := inmemory.NewStore(clock.RealClock{})
 
 	now := time.Now()
-	srv, err := api.NewServer(engine, clockTest.NewFakePassiveClock(now), nil)
+	srv, err := api.NewServer(engine, clockTest.NewFakePassiveClock(now), nil, nil)
 	require.NoError(t, err)
 
 	r := chi.NewRouter()
@@ -96,7 +96,7 @@ func TestValidationMiddlewareWithUnknownMethod(t *testing.T) {
 	server := httptest.NewServer(r)
 	defer server.Close()
 
-	req := httptest.NewRequest(http.MethodGet, "/cs/cs001", strings.NewReader(""))
+	req := httptest.NewRequest(http.MethodPatch, "/cs/cs001", strings.NewReader(""))
 	req.Header.Set("content-type", "application/json")
 	rr := httptest.NewRecorder()
 	r.ServeHTTP(rr, req)
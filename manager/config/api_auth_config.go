@@ -0,0 +1,25 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+type ApiKeyConfig struct {
+	Name      string  `mapstructure:"name" toml:"name" validate:"required"`
+	Key       *string `mapstructure:"key,omitempty" toml:"key,omitempty" validate:"required_without=KeyEnvVar"`
+	KeyEnvVar *string `mapstructure:"key_env_var,omitempty" toml:"key_env_var,omitempty" validate:"required_without=Key"`
+	Role      string  `mapstructure:"role" toml:"role" validate:"required,oneof=read-only gateway operator admin"`
+}
+
+type JwtAuthConfig struct {
+	Algorithm     string  `mapstructure:"algorithm" toml:"algorithm" validate:"required"`
+	Secret        *string `mapstructure:"secret,omitempty" toml:"secret,omitempty" validate:"required_without_all=SecretEnvVar PublicKeyFile"`
+	SecretEnvVar  *string `mapstructure:"secret_env_var,omitempty" toml:"secret_env_var,omitempty" validate:"required_without_all=Secret PublicKeyFile"`
+	PublicKeyFile *string `mapstructure:"public_key_file,omitempty" toml:"public_key_file,omitempty" validate:"required_without_all=Secret SecretEnvVar"`
+	Issuer        string  `mapstructure:"issuer,omitempty" toml:"issuer,omitempty"`
+	Audience      string  `mapstructure:"audience,omitempty" toml:"audience,omitempty"`
+	RoleClaim     string  `mapstructure:"role_claim,omitempty" toml:"role_claim,omitempty"`
+}
+
+type ApiAuthConfig struct {
+	ApiKeys []ApiKeyConfig `mapstructure:"api_keys,omitempty" toml:"api_keys,omitempty" validate:"dive"`
+	Jwt     *JwtAuthConfig `mapstructure:"jwt,omitempty" toml:"jwt,omitempty"`
+}
@@ -22,6 +22,7 @@ type BaseConfig struct {
 	ContractCertProvider      ContractCertProviderConfig      `mapstructure:"contract_cert_provider" toml:"contract_cert_provider" validate:"required"`
 	ChargeStationCertProvider ChargeStationCertProviderConfig `mapstructure:"charge_station_cert_provider" toml:"charge_station_cert_provider" validate:"required"`
 	TariffService             TariffServiceConfig             `mapstructure:"tariff_service" toml:"tariff_service" validate:"required"`
+	Sync                      SyncConfig                      `mapstructure:"sync" toml:"sync"`
 	Ocpi                      *OcpiConfig                     `mapstructure:"ocpi,omitempty" toml:"ocpi,omitempty"`
 }
 
@@ -50,6 +51,7 @@ var DefaultConfig = BaseConfig{
 		HeartbeatInterval: "5m",
 		Ocpp16Enabled:     true,
 		Ocpp201Enabled:    true,
+		Ocpp21Enabled:     true,
 	},
 	Observability: ObservabilitySettingsConfig{
 		LogFormat: "text",
@@ -78,6 +80,25 @@ var DefaultConfig = BaseConfig{
 	TariffService: TariffServiceConfig{
 		Type: "kwh",
 	},
+	Sync: SyncConfig{
+		SettingsRetry:     defaultRetryPolicy,
+		CertificatesRetry: defaultRetryPolicy,
+		TriggersRetry:     defaultRetryPolicy,
+		CertificateRenewal: CertificateRenewalConfig{
+			LeadTime:      "720h",
+			RetryInterval: "24h",
+		},
+		PollInterval:                 "1m",
+		InstalledCertificatesRefresh: "24h",
+		OperationLogRetention:        "720h",
+	},
+}
+
+var defaultRetryPolicy = RetryPolicyConfig{
+	InitialInterval: "2m",
+	MaxInterval:     "1h",
+	Multiplier:      2,
+	MaxAttempts:     10,
 }
 
 // Load reads TOML configuration from a reader.
@@ -38,6 +38,7 @@ func TestParseConfig(t *testing.T) {
 			HeartbeatInterval: "10m",
 			Ocpp16Enabled:     false,
 			Ocpp201Enabled:    true,
+			Ocpp21Enabled:     true,
 		},
 		Observability: config.ObservabilitySettingsConfig{
 			LogFormat:         "text",
@@ -96,6 +97,33 @@ func TestParseConfig(t *testing.T) {
 		TariffService: config.TariffServiceConfig{
 			Type: "kwh",
 		},
+		Sync: config.SyncConfig{
+			SettingsRetry: config.RetryPolicyConfig{
+				InitialInterval: "2m",
+				MaxInterval:     "1h",
+				Multiplier:      2,
+				MaxAttempts:     10,
+			},
+			CertificatesRetry: config.RetryPolicyConfig{
+				InitialInterval: "2m",
+				MaxInterval:     "1h",
+				Multiplier:      2,
+				MaxAttempts:     10,
+			},
+			TriggersRetry: config.RetryPolicyConfig{
+				InitialInterval: "30s",
+				MaxInterval:     "5m",
+				Multiplier:      1.5,
+				MaxAttempts:     3,
+			},
+			CertificateRenewal: config.CertificateRenewalConfig{
+				LeadTime:      "720h",
+				RetryInterval: "24h",
+			},
+			PollInterval:                 "1m",
+			InstalledCertificatesRefresh: "24h",
+			OperationLogRetention:        "720h",
+		},
 	}
 
 	assert.Equal(t, want, cfg)
@@ -24,8 +24,9 @@ type DelegatingChargeStationCertProviderConfig struct {
 }
 
 type ChargeStationCertProviderConfig struct {
-	Type       string                                     `mapstructure:"type" toml:"type" validate:"required,oneof=default opcp local delegating"`
+	Type       string                                     `mapstructure:"type" toml:"type" validate:"required,oneof=default opcp local local_v2g delegating"`
 	Opcp       *OpcpChargeStationCertProviderConfig       `mapstructure:"opcp,omitempty" toml:"opcp,omitempty" validate:"required_if=Type opcp"`
 	Local      *LocalChargeStationCertProviderConfig      `mapstructure:"local,omitempty" toml:"local,omitempty" validate:"required_if=Type local"`
+	LocalV2G   *LocalChargeStationCertProviderConfig      `mapstructure:"local_v2g,omitempty" toml:"local_v2g,omitempty" validate:"required_if=Type local_v2g"`
 	Delegating *DelegatingChargeStationCertProviderConfig `mapstructure:"delegating,omitempty" toml:"delegating,omitempty" validate:"required_if=Type delegating"`
 }
@@ -5,18 +5,27 @@ package config
 import (
 	"context"
 	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
 	"fmt"
 	"github.com/subnova/slog-exporter/slogtrace"
+	"github.com/thoughtworks/maeve-csms/manager/api"
+	"github.com/thoughtworks/maeve-csms/manager/handlers"
 	"github.com/thoughtworks/maeve-csms/manager/handlers/ocpp16"
 	"github.com/thoughtworks/maeve-csms/manager/handlers/ocpp201"
+	"github.com/thoughtworks/maeve-csms/manager/handlers/ocpp21"
 	"github.com/thoughtworks/maeve-csms/manager/ocpi"
 	"github.com/thoughtworks/maeve-csms/manager/schemas"
 	"github.com/thoughtworks/maeve-csms/manager/services"
 	"github.com/thoughtworks/maeve-csms/manager/store"
 	"github.com/thoughtworks/maeve-csms/manager/store/firestore"
 	"github.com/thoughtworks/maeve-csms/manager/store/inmemory"
+	"github.com/thoughtworks/maeve-csms/manager/sync"
 	"github.com/thoughtworks/maeve-csms/manager/transport"
+	inmemoryTransport "github.com/thoughtworks/maeve-csms/manager/transport/inmemory"
 	mqtt2 "github.com/thoughtworks/maeve-csms/manager/transport/mqtt"
+	"github.com/thoughtworks/maeve-csms/manager/transport/nats"
 	"go.opentelemetry.io/contrib/detectors/gcp"
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 	"go.opentelemetry.io/otel"
@@ -37,11 +46,12 @@ import (
 )
 
 type ApiSettings struct {
-	Addr    string
-	Host    string
-	WsPort  int
-	WssPort int
-	OrgName string
+	Addr           string
+	Host           string
+	WsPort         int
+	WssPort        int
+	OrgName        string
+	Authenticators []api.Authenticator
 }
 
 type Config struct {
@@ -51,8 +61,17 @@ type Config struct {
 	Storage                          store.Engine
 	MsgEmitter                       transport.Emitter
 	MsgListener                      transport.Listener
+	MsgBroker                        *inmemoryTransport.Broker
 	Ocpp16Handler                    transport.MessageHandler
 	Ocpp201Handler                   transport.MessageHandler
+	Ocpp21Handler                    transport.MessageHandler
+	PresenceHandler                  transport.PresenceHandler
+	SyncNotifier                     *sync.Notifier
+	SyncRetryPolicies                sync.RetryPolicies
+	SyncCertificateRenewalPolicy     sync.CertificateRenewalPolicy
+	SyncPollInterval                 time.Duration
+	SyncInstalledCertificatesRefresh time.Duration
+	SyncOperationLogRetention        time.Duration
 	ContractCertValidationService    services.CertificateValidationService
 	ContractCertProviderService      services.ContractCertificateProvider
 	ChargeStationCertProviderService services.ChargeStationCertificateProvider
@@ -81,6 +100,11 @@ func Configure(ctx context.Context, cfg *BaseConfig) (c *Config, err error) {
 		},
 	}
 
+	c.Api.Authenticators, err = getApiAuthenticators(cfg.Api.Auth)
+	if err != nil {
+		return nil, err
+	}
+
 	switch cfg.Observability.LogFormat {
 	case "json":
 		slog.SetDefault(slog.New(slog.NewJSONHandler(os.Stdout, nil)))
@@ -112,7 +136,7 @@ func Configure(ctx context.Context, cfg *BaseConfig) (c *Config, err error) {
 		return nil, err
 	}
 
-	c.ContractCertProviderService, err = getContractCertProvider(&cfg.ContractCertProvider, httpClient)
+	c.ContractCertProviderService, err = getContractCertProvider(&cfg.ContractCertProvider, c.Storage, httpClient)
 	if err != nil {
 		return nil, err
 	}
@@ -127,16 +151,60 @@ func Configure(ctx context.Context, cfg *BaseConfig) (c *Config, err error) {
 		return nil, err
 	}
 
-	c.MsgEmitter, err = getMsgEmitter(&cfg.Transport, c.Tracer)
+	if cfg.Transport.Type == "in_memory" {
+		c.MsgBroker = inmemoryTransport.NewBroker()
+	}
+
+	c.MsgEmitter, err = getMsgEmitter(&cfg.Transport, c.Tracer, c.MsgBroker)
+	if err != nil {
+		return nil, err
+	}
+	c.MsgEmitter = handlers.OperationLogEmitter{
+		Emitter:        c.MsgEmitter,
+		Clock:          clock.RealClock{},
+		OperationStore: c.Storage,
+	}
+
+	c.MsgListener, err = getMsgListener(&cfg.Transport, c.Tracer, c.MsgBroker)
 	if err != nil {
 		return nil, err
 	}
 
-	c.MsgListener, err = getMsgListener(&cfg.Transport, c.Tracer)
+	if cfg.Ocpi != nil {
+		c.OcpiApi, err = getOcpiApi(cfg.Ocpi, c.Storage, httpClient, c.MsgEmitter)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	c.SyncRetryPolicies, err = getSyncRetryPolicies(&cfg.Sync)
 	if err != nil {
 		return nil, err
 	}
 
+	c.SyncCertificateRenewalPolicy, err = getCertificateRenewalPolicy(&cfg.Sync.CertificateRenewal)
+	if err != nil {
+		return nil, err
+	}
+
+	c.SyncPollInterval, err = time.ParseDuration(cfg.Sync.PollInterval)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse sync poll interval: %w", err)
+	}
+
+	c.SyncInstalledCertificatesRefresh, err = time.ParseDuration(cfg.Sync.InstalledCertificatesRefresh)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse installed certificates refresh: %w", err)
+	}
+
+	c.SyncOperationLogRetention, err = time.ParseDuration(cfg.Sync.OperationLogRetention)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse operation log retention: %w", err)
+	}
+
+	c.SyncNotifier = sync.NewNotifier(c.Storage, clock.RealClock{}, c.Tracer, c.MsgEmitter,
+		sync.WithNotifierRetryPolicies(c.SyncRetryPolicies))
+
 	if cfg.Ocpp.Ocpp16Enabled {
 		c.Ocpp16Handler = ocpp16.NewRouter(c.MsgEmitter,
 			clock.RealClock{},
@@ -144,7 +212,9 @@ func Configure(ctx context.Context, cfg *BaseConfig) (c *Config, err error) {
 			c.ContractCertValidationService,
 			c.ChargeStationCertProviderService,
 			c.ContractCertProviderService,
+			c.OcpiApi,
 			heartbeatInterval,
+			c.SyncNotifier,
 			schemas.OcppSchemas)
 	}
 	if cfg.Ocpp.Ocpp201Enabled {
@@ -155,26 +225,160 @@ func Configure(ctx context.Context, cfg *BaseConfig) (c *Config, err error) {
 			c.ContractCertValidationService,
 			c.ChargeStationCertProviderService,
 			c.ContractCertProviderService,
+			c.OcpiApi,
 			heartbeatInterval,
+			c.SyncNotifier,
+			schemas.OcppSchemas)
+	}
+	if cfg.Ocpp.Ocpp21Enabled {
+		c.Ocpp21Handler = ocpp21.NewRouter(c.MsgEmitter,
+			clock.RealClock{},
+			c.Storage,
+			c.TariffService,
+			c.ContractCertValidationService,
+			c.OcpiApi,
+			heartbeatInterval,
+			c.SyncNotifier,
 			schemas.OcppSchemas)
 	}
 
-	if cfg.Ocpi != nil {
-		c.OcpiApi, err = getOcpiApi(cfg.Ocpi, c.Storage, httpClient)
-		if err != nil {
-			return nil, err
-		}
+	c.PresenceHandler = handlers.PresenceHandler{
+		Clock:           clock.RealClock{},
+		ConnectionStore: c.Storage,
+		SyncNotifier:    c.SyncNotifier,
 	}
 
 	return
 }
 
-func getOcpiApi(o *OcpiConfig, engine store.Engine, httpClient *http.Client) (ocpi.Api, error) {
+func getSyncRetryPolicies(cfg *SyncConfig) (sync.RetryPolicies, error) {
+	var policies sync.RetryPolicies
+	var err error
+	policies.Settings, err = getRetryPolicy("settings", &cfg.SettingsRetry)
+	if err != nil {
+		return policies, err
+	}
+	policies.Certificates, err = getRetryPolicy("certificates", &cfg.CertificatesRetry)
+	if err != nil {
+		return policies, err
+	}
+	policies.Triggers, err = getRetryPolicy("triggers", &cfg.TriggersRetry)
+	if err != nil {
+		return policies, err
+	}
+	return policies, nil
+}
+
+func getRetryPolicy(name string, cfg *RetryPolicyConfig) (sync.RetryPolicy, error) {
+	initialInterval, err := time.ParseDuration(cfg.InitialInterval)
+	if err != nil {
+		return sync.RetryPolicy{}, fmt.Errorf("failed to parse %s retry initial interval: %w", name, err)
+	}
+	maxInterval, err := time.ParseDuration(cfg.MaxInterval)
+	if err != nil {
+		return sync.RetryPolicy{}, fmt.Errorf("failed to parse %s retry max interval: %w", name, err)
+	}
+	return sync.RetryPolicy{
+		InitialInterval: initialInterval,
+		MaxInterval:     maxInterval,
+		Multiplier:      cfg.Multiplier,
+		MaxAttempts:     cfg.MaxAttempts,
+	}, nil
+}
+
+func getCertificateRenewalPolicy(cfg *CertificateRenewalConfig) (sync.CertificateRenewalPolicy, error) {
+	leadTime, err := time.ParseDuration(cfg.LeadTime)
+	if err != nil {
+		return sync.CertificateRenewalPolicy{}, fmt.Errorf("failed to parse certificate renewal lead time: %w", err)
+	}
+	retryInterval, err := time.ParseDuration(cfg.RetryInterval)
+	if err != nil {
+		return sync.CertificateRenewalPolicy{}, fmt.Errorf("failed to parse certificate renewal retry interval: %w", err)
+	}
+	return sync.CertificateRenewalPolicy{
+		LeadTime:      leadTime,
+		RetryInterval: retryInterval,
+	}, nil
+}
+
+func getOcpiApi(o *OcpiConfig, engine store.Engine, httpClient *http.Client, emitter transport.Emitter) (ocpi.Api, error) {
 	api := ocpi.NewOCPI(engine, httpClient, o.CountryCode, o.PartyId)
 	api.SetExternalUrl(o.ExternalURL)
+	api.SetCallMakers(ocpp16.NewCallMaker(emitter), ocpp201.NewCallMaker(emitter), ocpp21.NewCallMaker(emitter))
 	return api, nil
 }
 
+func getApiAuthenticators(cfg *ApiAuthConfig) ([]api.Authenticator, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+
+	var authenticators []api.Authenticator
+
+	if len(cfg.ApiKeys) > 0 {
+		keys := make(map[string]api.Principal)
+		for _, keyCfg := range cfg.ApiKeys {
+			var key string
+			if keyCfg.Key != nil {
+				key = *keyCfg.Key
+			} else if keyCfg.KeyEnvVar != nil {
+				key = os.Getenv(*keyCfg.KeyEnvVar)
+			}
+			if key == "" {
+				return nil, fmt.Errorf("no key for api key %s", keyCfg.Name)
+			}
+			keys[key] = api.Principal{
+				Subject: keyCfg.Name,
+				Role:    api.Role(keyCfg.Role),
+			}
+		}
+		authenticators = append(authenticators, api.NewApiKeyAuthenticator(keys))
+	}
+
+	if cfg.Jwt != nil {
+		var key any
+		if cfg.Jwt.Secret != nil || cfg.Jwt.SecretEnvVar != nil {
+			var secret string
+			if cfg.Jwt.Secret != nil {
+				secret = *cfg.Jwt.Secret
+			} else {
+				secret = os.Getenv(*cfg.Jwt.SecretEnvVar)
+			}
+			if secret == "" {
+				return nil, errors.New("no secret for jwt authenticator")
+			}
+			key = []byte(secret)
+		} else if cfg.Jwt.PublicKeyFile != nil {
+			var err error
+			key, err = readPublicKey(*cfg.Jwt.PublicKeyFile)
+			if err != nil {
+				return nil, fmt.Errorf("read jwt public key: %w", err)
+			}
+		}
+
+		jwtAuthenticator, err := api.NewJwtAuthenticator(cfg.Jwt.Algorithm, key, cfg.Jwt.Issuer, cfg.Jwt.Audience, cfg.Jwt.RoleClaim, clock.RealClock{})
+		if err != nil {
+			return nil, fmt.Errorf("create jwt authenticator: %w", err)
+		}
+		authenticators = append(authenticators, jwtAuthenticator)
+	}
+
+	return authenticators, nil
+}
+
+func readPublicKey(fileName string) (any, error) {
+	//#nosec G304 - only files specified by the person running the application will be loaded
+	b, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return nil, fmt.Errorf("no pem data found in %s", fileName)
+	}
+	return x509.ParsePKIXPublicKey(block.Bytes)
+}
+
 func getHttpClient(keylogFile string) (*http.Client, error) {
 	var httpTransport http.RoundTripper
 
@@ -286,7 +490,7 @@ func getRootCertProvider(cfg *RootCertProviderConfig, httpClient *http.Client) (
 	return
 }
 
-func getContractCertProvider(cfg *ContractCertProviderConfig, httpClient *http.Client) (evCertificateProvider services.ContractCertificateProvider, err error) {
+func getContractCertProvider(cfg *ContractCertProviderConfig, engine store.Engine, httpClient *http.Client) (evCertificateProvider services.ContractCertificateProvider, err error) {
 	switch cfg.Type {
 	case "opcp":
 		httpTokenService, err := getHttpTokenService(&cfg.Opcp.HttpAuth, httpClient)
@@ -299,6 +503,43 @@ func getContractCertProvider(cfg *ContractCertProviderConfig, httpClient *http.C
 			HttpTokenService: httpTokenService,
 			HttpClient:       httpClient,
 		}
+	case "local":
+		certificateSource, err := getLocalSource(cfg.Local.CertificateSource)
+		if err != nil {
+			return nil, fmt.Errorf("create local source: %w", err)
+		}
+		privateKeySource, err := getLocalSource(cfg.Local.PrivateKeySource)
+		if err != nil {
+			return nil, fmt.Errorf("create private key source: %w", err)
+		}
+		provisioningCertificateSource, err := getLocalSource(cfg.Local.ProvisioningCertificateSource)
+		if err != nil {
+			return nil, fmt.Errorf("create provisioning certificate source: %w", err)
+		}
+		provisioningPrivateKeySource, err := getLocalSource(cfg.Local.ProvisioningPrivateKeySource)
+		if err != nil {
+			return nil, fmt.Errorf("create provisioning private key source: %w", err)
+		}
+
+		var oemRootCertificateProvider services.RootCertificateProviderService
+		if cfg.Local.OemRootCertProvider != nil {
+			oemRootCertificateProvider, err = getRootCertProvider(cfg.Local.OemRootCertProvider, httpClient)
+			if err != nil {
+				return nil, fmt.Errorf("create oem root certificate provider: %w", err)
+			}
+		} else if !cfg.Local.SkipOemRootValidation {
+			return nil, errors.New("oem root certificates are required unless oem root validation is skipped")
+		}
+
+		evCertificateProvider = &services.LocalContractCertificateProvider{
+			Store:                         engine,
+			CertificateReader:             certificateSource,
+			PrivateKeyReader:              privateKeySource,
+			ProvisioningCertificateReader: provisioningCertificateSource,
+			ProvisioningPrivateKeyReader:  provisioningPrivateKeySource,
+			OEMRootCertificateProvider:    oemRootCertificateProvider,
+			SkipOEMRootValidation:         cfg.Local.SkipOemRootValidation,
+		}
 	case "default":
 		evCertificateProvider = &services.DefaultContractCertificateProvider{}
 	default:
@@ -337,6 +578,21 @@ func getChargeStationCertProvider(ctx context.Context, cfg *ChargeStationCertPro
 			CertificateReader: certificateSource,
 			PrivateKeyReader:  privateKeySource,
 		}
+	case "local_v2g":
+		certificateSource, err := getLocalSource(cfg.LocalV2G.CertificateSource)
+		if err != nil {
+			return nil, fmt.Errorf("create local source: %w", err)
+		}
+		privateKeySource, err := getLocalSource(cfg.LocalV2G.PrivateKeySource)
+		if err != nil {
+			return nil, fmt.Errorf("create private key source: %w", err)
+		}
+
+		chargeStationCertProvider = &services.LocalV2GChargeStationCertificateProvider{
+			Store:             engine,
+			CertificateReader: certificateSource,
+			PrivateKeyReader:  privateKeySource,
+		}
 	case "delegating":
 		var v2gChargeStationCertProvider services.ChargeStationCertificateProvider
 		v2gChargeStationCertProvider, err = getChargeStationCertProvider(ctx, cfg.Delegating.V2G, engine, httpClient)
@@ -423,7 +679,7 @@ func getTariffService(cfg *TariffServiceConfig) (tariffService services.TariffSe
 	return
 }
 
-func getMsgEmitter(cfg *TransportConfig, tracer oteltrace.Tracer) (transport.Emitter, error) {
+func getMsgEmitter(cfg *TransportConfig, tracer oteltrace.Tracer, broker *inmemoryTransport.Broker) (transport.Emitter, error) {
 	switch cfg.Type {
 	case "mqtt":
 		var mqttUrls []*url.URL
@@ -457,12 +713,25 @@ func getMsgEmitter(cfg *TransportConfig, tracer oteltrace.Tracer) (transport.Emi
 			mqtt2.WithOtelTracer[mqtt2.Emitter](tracer))
 
 		return mqttEmitter, nil
+	case "nats":
+		natsConnectTimeout, natsReconnectWait, err := getNatsDurations(cfg.Nats)
+		if err != nil {
+			return nil, err
+		}
+
+		return nats.NewEmitter(
+			nats.WithNatsUrls[nats.Emitter](cfg.Nats.Urls),
+			nats.WithNatsPrefix[nats.Emitter](cfg.Nats.Prefix),
+			nats.WithNatsConnectSettings[nats.Emitter](natsConnectTimeout, natsReconnectWait),
+			nats.WithOtelTracer[nats.Emitter](tracer)), nil
+	case "in_memory":
+		return inmemoryTransport.NewEmitter(broker), nil
 	default:
 		return nil, fmt.Errorf("unknown transport type: %s", cfg.Type)
 	}
 }
 
-func getMsgListener(cfg *TransportConfig, tracer oteltrace.Tracer) (transport.Listener, error) {
+func getMsgListener(cfg *TransportConfig, tracer oteltrace.Tracer, broker *inmemoryTransport.Broker) (transport.Listener, error) {
 	switch cfg.Type {
 	case "mqtt":
 		var mqttUrls []*url.URL
@@ -498,11 +767,39 @@ func getMsgListener(cfg *TransportConfig, tracer oteltrace.Tracer) (transport.Li
 		}
 
 		return mqtt2.NewListener(opts...), nil
+	case "nats":
+		natsConnectTimeout, natsReconnectWait, err := getNatsDurations(cfg.Nats)
+		if err != nil {
+			return nil, err
+		}
+
+		return nats.NewListener(
+			nats.WithNatsUrls[nats.Listener](cfg.Nats.Urls),
+			nats.WithNatsPrefix[nats.Listener](cfg.Nats.Prefix),
+			nats.WithNatsConnectSettings[nats.Listener](natsConnectTimeout, natsReconnectWait),
+			nats.WithNatsGroup[nats.Listener](cfg.Nats.Group),
+			nats.WithOtelTracer[nats.Listener](tracer)), nil
+	case "in_memory":
+		return inmemoryTransport.NewListener(broker), nil
 	default:
 		return nil, fmt.Errorf("unknown transport type: %s", cfg.Type)
 	}
 }
 
+func getNatsDurations(cfg *NatsSettingsConfig) (time.Duration, time.Duration, error) {
+	natsConnectTimeout, err := time.ParseDuration(cfg.ConnectTimeout)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to parse nats connect timeout: %w", err)
+	}
+
+	natsReconnectWait, err := time.ParseDuration(cfg.ReconnectWait)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to parse nats reconnect wait: %w", err)
+	}
+
+	return natsConnectTimeout, natsReconnectWait, nil
+}
+
 func getTracerProvider(ctx context.Context, collectorAddr string) (*trace.TracerProvider, error) {
 	var err error
 	var res *resource.Resource
@@ -8,6 +8,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/thoughtworks/maeve-csms/manager/config"
+	"github.com/thoughtworks/maeve-csms/manager/transport"
 	"os"
 	"testing"
 )
@@ -35,12 +36,80 @@ func TestConfigure(t *testing.T) {
 	assert.NotNil(t, settings.MsgListener)
 	assert.NotNil(t, settings.Ocpp16Handler)
 	assert.NotNil(t, settings.Ocpp201Handler)
+	assert.NotNil(t, settings.Ocpp21Handler)
 	assert.NotNil(t, settings.ContractCertValidationService)
 	assert.NotNil(t, settings.ContractCertProviderService)
 	assert.NotNil(t, settings.ChargeStationCertProviderService)
 	assert.NotNil(t, settings.TariffService)
 }
 
+func TestConfigureApiAuth(t *testing.T) {
+	_ = os.Setenv("TEST_API_KEY", "test-key")
+	defer func() {
+		_ = os.Unsetenv("TEST_API_KEY")
+	}()
+
+	cfg := clone.Clone(&config.DefaultConfig)
+	cfg.ContractCertValidator.Ocsp.RootCertProvider.File.FileNames = []string{"testdata/root_ca.pem"}
+	keyEnvVar := "TEST_API_KEY"
+	secret := "test-secret"
+	cfg.Api.Auth = &config.ApiAuthConfig{
+		ApiKeys: []config.ApiKeyConfig{
+			{
+				Name:      "gateway",
+				KeyEnvVar: &keyEnvVar,
+				Role:      "read-only",
+			},
+		},
+		Jwt: &config.JwtAuthConfig{
+			Algorithm: "HS256",
+			Secret:    &secret,
+		},
+	}
+
+	settings, err := config.Configure(context.TODO(), cfg)
+	require.NoError(t, err)
+	assert.Len(t, settings.Api.Authenticators, 2)
+}
+
+func TestConfigureApiAuthWithInvalidRole(t *testing.T) {
+	cfg := clone.Clone(&config.DefaultConfig)
+	cfg.ContractCertValidator.Ocsp.RootCertProvider.File.FileNames = []string{"testdata/root_ca.pem"}
+	key := "test-key"
+	cfg.Api.Auth = &config.ApiAuthConfig{
+		ApiKeys: []config.ApiKeyConfig{
+			{
+				Name: "gateway",
+				Key:  &key,
+				Role: "superuser",
+			},
+		},
+	}
+
+	_, err := config.Configure(context.TODO(), cfg)
+	assert.Error(t, err)
+}
+
+func TestConfigureApiAuthWithEmptyJwtSecret(t *testing.T) {
+	_ = os.Setenv("TEST_JWT_SECRET", "")
+	defer func() {
+		_ = os.Unsetenv("TEST_JWT_SECRET")
+	}()
+
+	cfg := clone.Clone(&config.DefaultConfig)
+	cfg.ContractCertValidator.Ocsp.RootCertProvider.File.FileNames = []string{"testdata/root_ca.pem"}
+	secretEnvVar := "TEST_JWT_SECRET"
+	cfg.Api.Auth = &config.ApiAuthConfig{
+		Jwt: &config.JwtAuthConfig{
+			Algorithm:    "HS256",
+			SecretEnvVar: &secretEnvVar,
+		},
+	}
+
+	_, err := config.Configure(context.TODO(), cfg)
+	assert.ErrorContains(t, err, "no secret for jwt authenticator")
+}
+
 func TestConfigureFirestoreStorage(t *testing.T) {
 	_ = os.Setenv("FIRESTORE_EMULATOR_HOST", "localhost:8080")
 
@@ -66,6 +135,40 @@ func TestConfigureInMemoryStorage(t *testing.T) {
 	require.NotNil(t, settings.Storage)
 }
 
+func TestConfigureNatsTransport(t *testing.T) {
+	cfg := clone.Clone(&config.DefaultConfig)
+	cfg.ContractCertValidator.Ocsp.RootCertProvider.File.FileNames = []string{"testdata/root_ca.pem"}
+	cfg.Transport.Type = "nats"
+	cfg.Transport.Mqtt = nil
+	cfg.Transport.Nats = &config.NatsSettingsConfig{
+		Urls:           []string{"nats://localhost:4222"},
+		Prefix:         "cs",
+		Group:          "manager",
+		ConnectTimeout: "10s",
+		ReconnectWait:  "1s",
+	}
+
+	settings, err := config.Configure(context.TODO(), cfg)
+	require.NoError(t, err)
+	assert.NotNil(t, settings.MsgEmitter)
+	assert.NotNil(t, settings.MsgListener)
+	_, ok := settings.MsgListener.(transport.PresenceListener)
+	assert.True(t, ok)
+}
+
+func TestConfigureInMemoryTransport(t *testing.T) {
+	cfg := clone.Clone(&config.DefaultConfig)
+	cfg.ContractCertValidator.Ocsp.RootCertProvider.File.FileNames = []string{"testdata/root_ca.pem"}
+	cfg.Transport.Type = "in_memory"
+	cfg.Transport.Mqtt = nil
+
+	settings, err := config.Configure(context.TODO(), cfg)
+	require.NoError(t, err)
+	assert.NotNil(t, settings.MsgBroker)
+	assert.NotNil(t, settings.MsgEmitter)
+	assert.NotNil(t, settings.MsgListener)
+}
+
 func TestConfigureOcspContractCertValidator(t *testing.T) {
 	cfg := clone.Clone(&config.DefaultConfig)
 	cfg.ContractCertValidator.Ocsp.RootCertProvider.File.FileNames = []string{"testdata/root_ca.pem"}
@@ -99,6 +202,79 @@ func TestConfigureOpcpContractCertProvider(t *testing.T) {
 	require.NotNil(t, settings.ContractCertProviderService)
 }
 
+func TestConfigureLocalContractCertProvider(t *testing.T) {
+	cfg := clone.Clone(&config.DefaultConfig)
+	cfg.ContractCertValidator.Ocsp.RootCertProvider.File.FileNames = []string{"testdata/root_ca.pem"}
+	cfg.ContractCertProvider.Type = "local"
+	cfg.ContractCertProvider.Local = &config.LocalContractCertProviderConfig{
+		CertificateSource: &config.LocalSourceConfig{
+			Type: "file",
+			File: "testdata/ca.pem",
+		},
+		PrivateKeySource: &config.LocalSourceConfig{
+			Type: "file",
+			File: "testdata/ca.key",
+		},
+		ProvisioningCertificateSource: &config.LocalSourceConfig{
+			Type: "file",
+			File: "testdata/ca.pem",
+		},
+		ProvisioningPrivateKeySource: &config.LocalSourceConfig{
+			Type: "file",
+			File: "testdata/ca.key",
+		},
+		OemRootCertProvider: &config.RootCertProviderConfig{
+			Type: "file",
+			File: &config.FileRootCertProviderConfig{
+				FileNames: []string{"testdata/root_ca.pem"},
+			},
+		},
+	}
+
+	err := cfg.Validate()
+	require.NoError(t, err)
+	settings, err := config.Configure(context.TODO(), cfg)
+	require.NoError(t, err)
+	require.NotNil(t, settings.ContractCertProviderService)
+}
+
+func TestConfigureLocalContractCertProviderRequiresOemRootCerts(t *testing.T) {
+	cfg := clone.Clone(&config.DefaultConfig)
+	cfg.ContractCertValidator.Ocsp.RootCertProvider.File.FileNames = []string{"testdata/root_ca.pem"}
+	cfg.ContractCertProvider.Type = "local"
+	cfg.ContractCertProvider.Local = &config.LocalContractCertProviderConfig{
+		CertificateSource: &config.LocalSourceConfig{
+			Type: "file",
+			File: "testdata/ca.pem",
+		},
+		PrivateKeySource: &config.LocalSourceConfig{
+			Type: "file",
+			File: "testdata/ca.key",
+		},
+		ProvisioningCertificateSource: &config.LocalSourceConfig{
+			Type: "file",
+			File: "testdata/ca.pem",
+		},
+		ProvisioningPrivateKeySource: &config.LocalSourceConfig{
+			Type: "file",
+			File: "testdata/ca.key",
+		},
+	}
+
+	err := cfg.Validate()
+	assert.Error(t, err)
+	_, err = config.Configure(context.TODO(), cfg)
+	assert.Error(t, err)
+
+	// the oem root validation can only be explicitly disabled
+	cfg.ContractCertProvider.Local.SkipOemRootValidation = true
+	err = cfg.Validate()
+	require.NoError(t, err)
+	settings, err := config.Configure(context.TODO(), cfg)
+	require.NoError(t, err)
+	require.NotNil(t, settings.ContractCertProviderService)
+}
+
 func TestConfigureOcspContractCertProviderWithCompositeRootCertificateProvider(t *testing.T) {
 	cfg := clone.Clone(&config.DefaultConfig)
 	cfg.ContractCertValidator.Ocsp.RootCertProvider.Type = "composite"
@@ -196,6 +372,25 @@ func TestConfigureLocalChargeStationCertProviderWithGoogleCloudSecret(t *testing
 	require.NotNil(t, settings.ChargeStationCertProviderService)
 }
 
+func TestConfigureLocalV2GChargeStationCertProvider(t *testing.T) {
+	cfg := clone.Clone(&config.DefaultConfig)
+	cfg.ChargeStationCertProvider.Type = "local_v2g"
+	cfg.ChargeStationCertProvider.LocalV2G = &config.LocalChargeStationCertProviderConfig{
+		CertificateSource: &config.LocalSourceConfig{
+			Type: "file",
+			File: "testdata/ca.pem",
+		},
+		PrivateKeySource: &config.LocalSourceConfig{
+			Type: "file",
+			File: "testdata/ca.key",
+		},
+	}
+
+	settings, err := config.Configure(context.TODO(), cfg)
+	require.NoError(t, err)
+	require.NotNil(t, settings.ChargeStationCertProviderService)
+}
+
 func TestConfigureDelegatingChargeStationCertProvider(t *testing.T) {
 	_ = os.Setenv("TEST_OPCP_TOKEN", "test-token")
 	defer func() {
@@ -7,7 +7,17 @@ type OpcpContractCertProviderConfig struct {
 	HttpAuth HttpAuthConfig `mapstructure:"auth" toml:"auth" validate:"required"`
 }
 
+type LocalContractCertProviderConfig struct {
+	CertificateSource             *LocalSourceConfig      `mapstructure:"cert" toml:"cert" validate:"required"`
+	PrivateKeySource              *LocalSourceConfig      `mapstructure:"key" toml:"key" validate:"required"`
+	ProvisioningCertificateSource *LocalSourceConfig      `mapstructure:"provisioning_cert" toml:"provisioning_cert" validate:"required"`
+	ProvisioningPrivateKeySource  *LocalSourceConfig      `mapstructure:"provisioning_key" toml:"provisioning_key" validate:"required"`
+	OemRootCertProvider           *RootCertProviderConfig `mapstructure:"oem_root_certs,omitempty" toml:"oem_root_certs,omitempty" validate:"required_unless=SkipOemRootValidation true"`
+	SkipOemRootValidation         bool                    `mapstructure:"skip_oem_root_validation,omitempty" toml:"skip_oem_root_validation,omitempty"`
+}
+
 type ContractCertProviderConfig struct {
-	Type string                          `mapstructure:"type" toml:"type" validate:"required,oneof=default opcp"`
-	Opcp *OpcpContractCertProviderConfig `mapstructure:"opcp,omitempty" toml:"opcp,omitempty" validate:"required_if=Type opcp"`
+	Type  string                           `mapstructure:"type" toml:"type" validate:"required,oneof=default opcp local"`
+	Opcp  *OpcpContractCertProviderConfig  `mapstructure:"opcp,omitempty" toml:"opcp,omitempty" validate:"required_if=Type opcp"`
+	Local *LocalContractCertProviderConfig `mapstructure:"local,omitempty" toml:"local,omitempty" validate:"required_if=Type local"`
 }
@@ -3,17 +3,19 @@
 package config
 
 type ApiSettingsConfig struct {
-	Addr    string `mapstructure:"addr" toml:"addr" validate:"required"`
-	Host    string `mapstructure:"host,omitempty" toml:"host,omitempty"`
-	WsPort  int    `mapstructure:"ws_port,omitempty" toml:"ws_port,omitempty"`
-	WssPort int    `mapstructure:"wss_port,omitempty" toml:"wss_port,omitempty"`
-	OrgName string `mapstructure:"org_name,omitempty" toml:"org_name,omitempty"`
+	Addr    string         `mapstructure:"addr" toml:"addr" validate:"required"`
+	Host    string         `mapstructure:"host,omitempty" toml:"host,omitempty"`
+	WsPort  int            `mapstructure:"ws_port,omitempty" toml:"ws_port,omitempty"`
+	WssPort int            `mapstructure:"wss_port,omitempty" toml:"wss_port,omitempty"`
+	OrgName string         `mapstructure:"org_name,omitempty" toml:"org_name,omitempty"`
+	Auth    *ApiAuthConfig `mapstructure:"auth,omitempty" toml:"auth,omitempty"`
 }
 
 type OcppSettingsConfig struct {
 	HeartbeatInterval string `mapstructure:"heartbeat_interval" toml:"heartbeat_interval" validate:"required"`
 	Ocpp16Enabled     bool   `mapstructure:"ocpp16_enabled" toml:"ocpp16_enabled" validate:"required_without=Ocpp201Enabled"`
 	Ocpp201Enabled    bool   `mapstructure:"ocpp201_enabled" toml:"ocpp201_enabled" validate:"required_without=Ocpp16Enabled"`
+	Ocpp21Enabled     bool   `mapstructure:"ocpp21_enabled" toml:"ocpp21_enabled"`
 }
 
 type ObservabilitySettingsConfig struct {
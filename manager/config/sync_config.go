@@ -0,0 +1,29 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+// RetryPolicyConfig determines how often a pending change is resent to a charge station
+// that has not responded to it. A max_attempts of 0 retries forever.
+type RetryPolicyConfig struct {
+	InitialInterval string  `mapstructure:"initial_interval" toml:"initial_interval" validate:"required"`
+	MaxInterval     string  `mapstructure:"max_interval" toml:"max_interval" validate:"required"`
+	Multiplier      float64 `mapstructure:"multiplier" toml:"multiplier" validate:"gte=1"`
+	MaxAttempts     int     `mapstructure:"max_attempts" toml:"max_attempts" validate:"gte=0"`
+}
+
+// CertificateRenewalConfig determines when charge stations are asked to renew the
+// certificates that have been issued to them.
+type CertificateRenewalConfig struct {
+	LeadTime      string `mapstructure:"lead_time" toml:"lead_time" validate:"required"`
+	RetryInterval string `mapstructure:"retry_interval" toml:"retry_interval" validate:"required"`
+}
+
+type SyncConfig struct {
+	SettingsRetry                RetryPolicyConfig        `mapstructure:"settings_retry" toml:"settings_retry"`
+	CertificatesRetry            RetryPolicyConfig        `mapstructure:"certificates_retry" toml:"certificates_retry"`
+	TriggersRetry                RetryPolicyConfig        `mapstructure:"triggers_retry" toml:"triggers_retry"`
+	CertificateRenewal           CertificateRenewalConfig `mapstructure:"certificate_renewal" toml:"certificate_renewal"`
+	PollInterval                 string                   `mapstructure:"poll_interval" toml:"poll_interval" validate:"required"`
+	InstalledCertificatesRefresh string                   `mapstructure:"installed_certificates_refresh" toml:"installed_certificates_refresh" validate:"required"`
+	OperationLogRetention        string                   `mapstructure:"operation_log_retention" toml:"operation_log_retention" validate:"required"`
+}
@@ -11,7 +11,16 @@ type MqttSettingsConfig struct {
 	KeepAliveInterval string   `mapstructure:"keep_alive_interval" toml:"keep_alive_interval" validate:"required"`
 }
 
+type NatsSettingsConfig struct {
+	Urls           []string `mapstructure:"urls" toml:"urls" validate:"required,dive,required"`
+	Prefix         string   `mapstructure:"prefix" toml:"prefix" validate:"required"`
+	Group          string   `mapstructure:"group" toml:"group" validate:"required"`
+	ConnectTimeout string   `mapstructure:"connect_timeout" toml:"connect_timeout" validate:"required"`
+	ReconnectWait  string   `mapstructure:"reconnect_wait" toml:"reconnect_wait" validate:"required"`
+}
+
 type TransportConfig struct {
-	Type string              `mapstructure:"type" toml:"type" validate:"required,oneof=mqtt"`
+	Type string              `mapstructure:"type" toml:"type" validate:"required,oneof=mqtt nats in_memory"`
 	Mqtt *MqttSettingsConfig `mapstructure:"mqtt,omitempty" toml:"mqtt,omitempty" validate:"required_if=Type mqtt"`
+	Nats *NatsSettingsConfig `mapstructure:"nats,omitempty" toml:"nats,omitempty" validate:"required_if=Type nats"`
 }
@@ -0,0 +1,17 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package handlers
+
+import "context"
+
+// SyncNotifier is informed when there are changes that need to be delivered to a
+// charge station so that they can be sent immediately rather than waiting for the
+// next poll of the store.
+type SyncNotifier interface {
+	// NotifyPending is called when settings, certificates or trigger messages have been
+	// queued for the charge station.
+	NotifyPending(ctx context.Context, chargeStationId string)
+	// NotifyConnected is called when the charge station has (re)connected or booted:
+	// any outstanding changes should be resent without waiting for their retry delay.
+	NotifyConnected(ctx context.Context, chargeStationId string)
+}
@@ -4,6 +4,7 @@ package ocpp16
 
 import (
 	"context"
+	"github.com/thoughtworks/maeve-csms/manager/handlers"
 	"github.com/thoughtworks/maeve-csms/manager/store"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
@@ -17,8 +18,10 @@ import (
 type BootNotificationHandler struct {
 	Clock               clock.PassiveClock
 	RuntimeDetailsStore store.ChargeStationRuntimeDetailsStore
+	DetailsStore        store.ChargeStationDetailsStore
 	SettingsStore       store.ChargeStationSettingsStore
 	HeartbeatInterval   int
+	SyncNotifier        handlers.SyncNotifier
 }
 
 func (b BootNotificationHandler) HandleCall(ctx context.Context, chargeStationId string, request ocpp.Request) (ocpp.Response, error) {
@@ -45,6 +48,29 @@ func (b BootNotificationHandler) HandleCall(ctx context.Context, chargeStationId
 		return nil, err
 	}
 
+	details, err := b.DetailsStore.LookupChargeStationDetails(ctx, chargeStationId)
+	if err != nil {
+		return nil, err
+	}
+	if details == nil {
+		details = &store.ChargeStationDetails{}
+	}
+	details.Vendor = req.ChargePointVendor
+	details.Model = req.ChargePointModel
+	details.SerialNumber = ""
+	if req.ChargePointSerialNumber != nil {
+		details.SerialNumber = *req.ChargePointSerialNumber
+	}
+	details.FirmwareVersion = ""
+	if req.FirmwareVersion != nil {
+		details.FirmwareVersion = *req.FirmwareVersion
+	}
+	details.LastSeen = b.Clock.Now().UTC()
+	err = b.DetailsStore.SetChargeStationDetails(ctx, chargeStationId, details)
+	if err != nil {
+		return nil, err
+	}
+
 	// remove any reboot required settings
 	settings, err := b.SettingsStore.LookupChargeStationSettings(ctx, chargeStationId)
 	if err != nil {
@@ -68,6 +94,10 @@ func (b BootNotificationHandler) HandleCall(ctx context.Context, chargeStationId
 		}
 	}
 
+	if b.SyncNotifier != nil {
+		b.SyncNotifier.NotifyConnected(ctx, chargeStationId)
+	}
+
 	return &types.BootNotificationResponseJson{
 		CurrentTime: b.Clock.Now().Format(time.RFC3339),
 		Interval:    b.HeartbeatInterval,
@@ -33,6 +33,7 @@ func TestBootNotificationHandler(t *testing.T) {
 	handler := handlers.BootNotificationHandler{
 		Clock:               clockTest.NewFakePassiveClock(now),
 		RuntimeDetailsStore: engine,
+		DetailsStore:        engine,
 		SettingsStore:       engine,
 		HeartbeatInterval:   10,
 	}
@@ -65,3 +66,46 @@ func TestBootNotificationHandler(t *testing.T) {
 		assert.NotEqual(t, store.ChargeStationSettingStatusRebootRequired, v.Status)
 	}
 }
+
+func TestBootNotificationHandlerUpdatesChargeStationDetails(t *testing.T) {
+	now := time.Date(2023, 6, 15, 15, 5, 0, 0, time.UTC)
+	registeredAt := now.Add(-24 * time.Hour)
+
+	engine := inmemory.NewStore(clock.RealClock{})
+	err := engine.SetChargeStationDetails(context.Background(), "cs001", &store.ChargeStationDetails{
+		RegisteredAt: registeredAt,
+	})
+	require.NoError(t, err)
+
+	handler := handlers.BootNotificationHandler{
+		Clock:               clockTest.NewFakePassiveClock(now),
+		RuntimeDetailsStore: engine,
+		DetailsStore:        engine,
+		SettingsStore:       engine,
+		HeartbeatInterval:   10,
+	}
+
+	serialNumber := "cs001-1234"
+	firmwareVersion := "1.2.3"
+	req := &types.BootNotificationJson{
+		ChargePointVendor:       "Vendor",
+		ChargePointModel:        "Model",
+		ChargePointSerialNumber: &serialNumber,
+		FirmwareVersion:         &firmwareVersion,
+	}
+
+	_, err = handler.HandleCall(context.Background(), "cs001", req)
+	require.NoError(t, err)
+
+	details, err := engine.LookupChargeStationDetails(context.Background(), "cs001")
+	require.NoError(t, err)
+	assert.Equal(t, &store.ChargeStationDetails{
+		ChargeStationId: "cs001",
+		Vendor:          "Vendor",
+		Model:           "Model",
+		SerialNumber:    "cs001-1234",
+		FirmwareVersion: "1.2.3",
+		RegisteredAt:    registeredAt,
+		LastSeen:        now,
+	}, details)
+}
@@ -0,0 +1,58 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpp16
+
+import (
+	"context"
+	"fmt"
+	"github.com/thoughtworks/maeve-csms/manager/ocpi"
+	"github.com/thoughtworks/maeve-csms/manager/ocpp"
+	"github.com/thoughtworks/maeve-csms/manager/ocpp/ocpp16"
+	"github.com/thoughtworks/maeve-csms/manager/store"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type CancelReservationResultHandler struct {
+	Store   store.ReservationStore
+	OcpiApi ocpi.Api
+}
+
+func (h CancelReservationResultHandler) HandleCallResult(ctx context.Context, chargeStationId string, request ocpp.Request, response ocpp.Response, state any) error {
+	req := request.(*ocpp16.CancelReservationJson)
+	resp := response.(*ocpp16.CancelReservationResponseJson)
+
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(
+		attribute.Int("cancel_reservation.id", req.ReservationId),
+		attribute.String("cancel_reservation.status", string(resp.Status)))
+
+	reservation, err := h.Store.LookupReservation(ctx, req.ReservationId)
+	if err != nil {
+		return fmt.Errorf("lookup reservation %d: %w", req.ReservationId, err)
+	}
+	if reservation == nil || reservation.ChargeStationId != chargeStationId {
+		return nil
+	}
+
+	result := ocpi.CommandResultResultREJECTED
+	if resp.Status == ocpp16.CancelReservationResponseJsonStatusAccepted {
+		result = ocpi.CommandResultResultACCEPTED
+		reservation.Status = store.ReservationStatusCancelled
+		err = h.Store.SetReservation(ctx, reservation)
+		if err != nil {
+			return fmt.Errorf("update reservation %d: %w", req.ReservationId, err)
+		}
+	}
+
+	if h.OcpiApi == nil {
+		return nil
+	}
+
+	err = h.OcpiApi.ReportReservationResult(ctx, req.ReservationId, ocpi.CommandResult{Result: result})
+	if err != nil {
+		return fmt.Errorf("report reservation result: %w", err)
+	}
+
+	return nil
+}
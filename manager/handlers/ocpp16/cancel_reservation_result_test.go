@@ -0,0 +1,113 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpp16_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thoughtworks/maeve-csms/manager/handlers/ocpp16"
+	"github.com/thoughtworks/maeve-csms/manager/ocpi"
+	types "github.com/thoughtworks/maeve-csms/manager/ocpp/ocpp16"
+	"github.com/thoughtworks/maeve-csms/manager/store"
+	"github.com/thoughtworks/maeve-csms/manager/store/inmemory"
+	"github.com/thoughtworks/maeve-csms/manager/testutil"
+	"k8s.io/utils/clock"
+	"testing"
+)
+
+func TestCancelReservationResultHandler(t *testing.T) {
+	engine := inmemory.NewStore(clock.RealClock{})
+	ocpiApi := new(fakeOcpiApi)
+	handler := ocpp16.CancelReservationResultHandler{
+		Store:   engine,
+		OcpiApi: ocpiApi,
+	}
+
+	ctx := context.Background()
+
+	err := engine.SetReservation(ctx, &store.Reservation{
+		ReservationId:   1234,
+		ChargeStationId: "cs001",
+		Status:          store.ReservationStatusAccepted,
+	})
+	require.NoError(t, err)
+
+	tracer, exporter := testutil.GetTracer()
+
+	func() {
+		ctx, span := tracer.Start(ctx, `test`)
+		defer span.End()
+
+		req := &types.CancelReservationJson{
+			ReservationId: 1234,
+		}
+		resp := &types.CancelReservationResponseJson{
+			Status: types.CancelReservationResponseJsonStatusAccepted,
+		}
+
+		err := handler.HandleCallResult(ctx, "cs001", req, resp, nil)
+		require.NoError(t, err)
+	}()
+
+	testutil.AssertSpan(t, &exporter.GetSpans()[0], "test", map[string]any{
+		"cancel_reservation.id":     1234,
+		"cancel_reservation.status": "Accepted",
+	})
+
+	reservation, err := engine.LookupReservation(ctx, 1234)
+	require.NoError(t, err)
+	assert.Equal(t, store.ReservationStatusCancelled, reservation.Status)
+
+	want := []reportedReservationResult{
+		{
+			reservationId: 1234,
+			result: ocpi.CommandResult{
+				Result: ocpi.CommandResultResultACCEPTED,
+			},
+		},
+	}
+	assert.Equal(t, want, ocpiApi.reportedReservation)
+}
+
+func TestCancelReservationResultHandlerWhenRejected(t *testing.T) {
+	engine := inmemory.NewStore(clock.RealClock{})
+	ocpiApi := new(fakeOcpiApi)
+	handler := ocpp16.CancelReservationResultHandler{
+		Store:   engine,
+		OcpiApi: ocpiApi,
+	}
+
+	ctx := context.Background()
+
+	err := engine.SetReservation(ctx, &store.Reservation{
+		ReservationId:   1234,
+		ChargeStationId: "cs001",
+		Status:          store.ReservationStatusAccepted,
+	})
+	require.NoError(t, err)
+
+	req := &types.CancelReservationJson{
+		ReservationId: 1234,
+	}
+	resp := &types.CancelReservationResponseJson{
+		Status: types.CancelReservationResponseJsonStatusRejected,
+	}
+
+	err = handler.HandleCallResult(ctx, "cs001", req, resp, nil)
+	require.NoError(t, err)
+
+	reservation, err := engine.LookupReservation(ctx, 1234)
+	require.NoError(t, err)
+	assert.Equal(t, store.ReservationStatusAccepted, reservation.Status)
+
+	want := []reportedReservationResult{
+		{
+			reservationId: 1234,
+			result: ocpi.CommandResult{
+				Result: ocpi.CommandResultResultREJECTED,
+			},
+		},
+	}
+	assert.Equal(t, want, ocpiApi.reportedReservation)
+}
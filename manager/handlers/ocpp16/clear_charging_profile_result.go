@@ -0,0 +1,46 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpp16
+
+import (
+	"context"
+	"fmt"
+	"github.com/thoughtworks/maeve-csms/manager/ocpi"
+	"github.com/thoughtworks/maeve-csms/manager/ocpp"
+	"github.com/thoughtworks/maeve-csms/manager/ocpp/ocpp16"
+	"github.com/thoughtworks/maeve-csms/manager/store"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type ClearChargingProfileResultHandler struct {
+	OcpiApi ocpi.Api
+}
+
+func (h ClearChargingProfileResultHandler) HandleCallResult(ctx context.Context, chargeStationId string, request ocpp.Request, response ocpp.Response, state any) error {
+	req := request.(*ocpp16.ClearChargingProfileJson)
+	resp := response.(*ocpp16.ClearChargingProfileResponseJson)
+
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(attribute.String("clear_charging_profile.status", string(resp.Status)))
+	if req.ConnectorId != nil {
+		span.SetAttributes(attribute.Int("clear_charging_profile.connector_id", *req.ConnectorId))
+	}
+
+	if h.OcpiApi == nil || req.ConnectorId == nil {
+		return nil
+	}
+
+	result := ocpi.GenericChargingProfileResultResultUNKNOWN
+	if resp.Status == ocpp16.ClearChargingProfileResponseJsonStatusAccepted {
+		result = ocpi.GenericChargingProfileResultResultACCEPTED
+	}
+
+	err := h.OcpiApi.ReportChargingProfileResult(ctx, chargeStationId, *req.ConnectorId, store.OcpiChargingProfileRequestTypeClear,
+		ocpi.GenericChargingProfileResult{Result: result})
+	if err != nil {
+		return fmt.Errorf("report charging profile result: %w", err)
+	}
+
+	return nil
+}
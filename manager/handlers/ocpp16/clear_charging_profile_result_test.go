@@ -0,0 +1,44 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpp16_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thoughtworks/maeve-csms/manager/handlers/ocpp16"
+	"github.com/thoughtworks/maeve-csms/manager/ocpi"
+	types "github.com/thoughtworks/maeve-csms/manager/ocpp/ocpp16"
+	"github.com/thoughtworks/maeve-csms/manager/store"
+	"testing"
+)
+
+func TestClearChargingProfileResultHandler(t *testing.T) {
+	ocpiApi := new(fakeOcpiApi)
+	handler := ocpp16.ClearChargingProfileResultHandler{
+		OcpiApi: ocpiApi,
+	}
+
+	connectorId := 2
+	req := &types.ClearChargingProfileJson{
+		ConnectorId: &connectorId,
+	}
+	resp := &types.ClearChargingProfileResponseJson{
+		Status: types.ClearChargingProfileResponseJsonStatusUnknown,
+	}
+
+	err := handler.HandleCallResult(context.Background(), "cs001", req, resp, nil)
+	require.NoError(t, err)
+
+	want := []reportedChargingProfileResult{
+		{
+			chargeStationId: "cs001",
+			evseId:          2,
+			requestType:     store.OcpiChargingProfileRequestTypeClear,
+			result: ocpi.GenericChargingProfileResult{
+				Result: ocpi.GenericChargingProfileResultResultUNKNOWN,
+			},
+		},
+	}
+	assert.Equal(t, want, ocpiApi.reported)
+}
@@ -0,0 +1,85 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpp16
+
+import (
+	"context"
+	"fmt"
+	"github.com/thoughtworks/maeve-csms/manager/ocpi"
+	"github.com/thoughtworks/maeve-csms/manager/ocpp"
+	"github.com/thoughtworks/maeve-csms/manager/ocpp/ocpp16"
+	"github.com/thoughtworks/maeve-csms/manager/store"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type GetCompositeScheduleResultHandler struct {
+	OcpiApi ocpi.Api
+}
+
+func (h GetCompositeScheduleResultHandler) HandleCallResult(ctx context.Context, chargeStationId string, request ocpp.Request, response ocpp.Response, state any) error {
+	req := request.(*ocpp16.GetCompositeScheduleJson)
+	resp := response.(*ocpp16.GetCompositeScheduleResponseJson)
+
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(
+		attribute.Int("get_composite_schedule.connector_id", req.ConnectorId),
+		attribute.Int("get_composite_schedule.duration", req.Duration),
+		attribute.String("get_composite_schedule.status", string(resp.Status)))
+
+	if h.OcpiApi == nil {
+		return nil
+	}
+
+	result := ocpi.GenericChargingProfileResult{
+		Result: ocpi.GenericChargingProfileResultResultREJECTED,
+	}
+	if resp.Status == ocpp16.GetCompositeScheduleResponseJsonStatusAccepted && resp.ChargingSchedule != nil {
+		result.Result = ocpi.GenericChargingProfileResultResultACCEPTED
+		result.Profile = convertCompositeSchedule(resp)
+	}
+
+	err := h.OcpiApi.ReportChargingProfileResult(ctx, chargeStationId, req.ConnectorId, store.OcpiChargingProfileRequestTypeGet, result)
+	if err != nil {
+		return fmt.Errorf("report charging profile result: %w", err)
+	}
+
+	return nil
+}
+
+func convertCompositeSchedule(resp *ocpp16.GetCompositeScheduleResponseJson) *ocpi.ActiveChargingProfile {
+	schedule := resp.ChargingSchedule
+
+	var startDateTime string
+	if resp.ScheduleStart != nil {
+		startDateTime = *resp.ScheduleStart
+	} else if schedule.StartSchedule != nil {
+		startDateTime = *schedule.StartSchedule
+	}
+
+	var periods []ocpi.ChargingProfilePeriod
+	for _, period := range schedule.ChargingSchedulePeriod {
+		periods = append(periods, ocpi.ChargingProfilePeriod{
+			StartPeriod: int32(period.StartPeriod),
+			Limit:       float32(period.Limit),
+		})
+	}
+
+	profile := &ocpi.ActiveChargingProfile{
+		StartDateTime: startDateTime,
+		ChargingProfile: ocpi.ChargingProfile{
+			ChargingRateUnit:      ocpi.ChargingProfileChargingRateUnit(schedule.ChargingRateUnit),
+			ChargingProfilePeriod: &periods,
+		},
+	}
+	if schedule.Duration != nil {
+		duration := int32(*schedule.Duration)
+		profile.ChargingProfile.Duration = &duration
+	}
+	if schedule.MinChargingRate != nil {
+		minChargingRate := float32(*schedule.MinChargingRate)
+		profile.ChargingProfile.MinChargingRate = &minChargingRate
+	}
+
+	return profile
+}
@@ -0,0 +1,65 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpp16_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thoughtworks/maeve-csms/manager/handlers/ocpp16"
+	"github.com/thoughtworks/maeve-csms/manager/ocpi"
+	types "github.com/thoughtworks/maeve-csms/manager/ocpp/ocpp16"
+	"github.com/thoughtworks/maeve-csms/manager/store"
+	"testing"
+)
+
+func TestGetCompositeScheduleResultHandler(t *testing.T) {
+	ocpiApi := new(fakeOcpiApi)
+	handler := ocpp16.GetCompositeScheduleResultHandler{
+		OcpiApi: ocpiApi,
+	}
+
+	scheduleStart := "2023-06-15T15:05:00Z"
+	duration := 3600
+	req := &types.GetCompositeScheduleJson{
+		ConnectorId: 1,
+		Duration:    3600,
+	}
+	resp := &types.GetCompositeScheduleResponseJson{
+		Status:        types.GetCompositeScheduleResponseJsonStatusAccepted,
+		ScheduleStart: &scheduleStart,
+		ChargingSchedule: &types.GetCompositeScheduleResponseJsonChargingSchedule{
+			ChargingRateUnit: types.GetCompositeScheduleResponseJsonChargingScheduleChargingRateUnitA,
+			Duration:         &duration,
+			ChargingSchedulePeriod: []types.GetCompositeScheduleResponseJsonChargingScheduleChargingSchedulePeriodElem{
+				{StartPeriod: 0, Limit: 16},
+			},
+		},
+	}
+
+	err := handler.HandleCallResult(context.Background(), "cs001", req, resp, nil)
+	require.NoError(t, err)
+
+	wantDuration := int32(3600)
+	want := []reportedChargingProfileResult{
+		{
+			chargeStationId: "cs001",
+			evseId:          1,
+			requestType:     store.OcpiChargingProfileRequestTypeGet,
+			result: ocpi.GenericChargingProfileResult{
+				Result: ocpi.GenericChargingProfileResultResultACCEPTED,
+				Profile: &ocpi.ActiveChargingProfile{
+					StartDateTime: scheduleStart,
+					ChargingProfile: ocpi.ChargingProfile{
+						ChargingRateUnit: ocpi.A,
+						Duration:         &wantDuration,
+						ChargingProfilePeriod: &[]ocpi.ChargingProfilePeriod{
+							{StartPeriod: 0, Limit: 16},
+						},
+					},
+				},
+			},
+		},
+	}
+	assert.Equal(t, want, ocpiApi.reported)
+}
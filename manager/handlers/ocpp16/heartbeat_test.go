@@ -8,6 +8,7 @@ import (
 	"github.com/stretchr/testify/require"
 	handlers "github.com/thoughtworks/maeve-csms/manager/handlers/ocpp16"
 	types "github.com/thoughtworks/maeve-csms/manager/ocpp/ocpp16"
+	"github.com/thoughtworks/maeve-csms/manager/store/inmemory"
 	clockTest "k8s.io/utils/clock/testing"
 	"testing"
 	"time"
@@ -18,8 +19,11 @@ func TestHeartbeatHandler(t *testing.T) {
 	require.NoError(t, err)
 	clock := clockTest.NewFakePassiveClock(now)
 
+	engine := inmemory.NewStore(clock)
+
 	handler := handlers.HeartbeatHandler{
-		Clock: clock,
+		Clock:        clock,
+		DetailsStore: engine,
 	}
 
 	req := &types.HeartbeatJson{}
@@ -32,4 +36,9 @@ func TestHeartbeatHandler(t *testing.T) {
 	}
 
 	assert.Equal(t, want, got)
+
+	details, err := engine.LookupChargeStationDetails(context.Background(), "cs001")
+	require.NoError(t, err)
+	require.NotNil(t, details)
+	assert.Equal(t, now.UTC(), details.LastSeen)
 }
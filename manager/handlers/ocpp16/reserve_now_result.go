@@ -0,0 +1,67 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpp16
+
+import (
+	"context"
+	"fmt"
+	"github.com/thoughtworks/maeve-csms/manager/ocpi"
+	"github.com/thoughtworks/maeve-csms/manager/ocpp"
+	"github.com/thoughtworks/maeve-csms/manager/ocpp/ocpp16"
+	"github.com/thoughtworks/maeve-csms/manager/store"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type ReserveNowResultHandler struct {
+	Store   store.ReservationStore
+	OcpiApi ocpi.Api
+}
+
+func (h ReserveNowResultHandler) HandleCallResult(ctx context.Context, chargeStationId string, request ocpp.Request, response ocpp.Response, state any) error {
+	req := request.(*ocpp16.ReserveNowJson)
+	resp := response.(*ocpp16.ReserveNowResponseJson)
+
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(
+		attribute.Int("reserve_now.connector_id", req.ConnectorId),
+		attribute.Int("reserve_now.id", req.ReservationId),
+		attribute.String("reserve_now.status", string(resp.Status)))
+
+	reservation, err := h.Store.LookupReservation(ctx, req.ReservationId)
+	if err != nil {
+		return fmt.Errorf("lookup reservation %d: %w", req.ReservationId, err)
+	}
+	if reservation == nil || reservation.ChargeStationId != chargeStationId {
+		return nil
+	}
+
+	reservation.Status = store.ReservationStatus(resp.Status)
+	err = h.Store.SetReservation(ctx, reservation)
+	if err != nil {
+		return fmt.Errorf("update reservation %d: %w", req.ReservationId, err)
+	}
+
+	if h.OcpiApi == nil {
+		return nil
+	}
+
+	var result ocpi.CommandResultResult
+	switch resp.Status {
+	case ocpp16.ReserveNowResponseJsonStatusAccepted:
+		result = ocpi.CommandResultResultACCEPTED
+	case ocpp16.ReserveNowResponseJsonStatusOccupied:
+		result = ocpi.CommandResultResultEVSEOCCUPIED
+	case ocpp16.ReserveNowResponseJsonStatusFaulted, ocpp16.ReserveNowResponseJsonStatusUnavailable:
+		result = ocpi.CommandResultResultEVSEINOPERATIVE
+	default:
+		result = ocpi.CommandResultResultREJECTED
+	}
+
+	err = h.OcpiApi.ReportReservationResult(ctx, req.ReservationId, ocpi.CommandResult{Result: result})
+	if err != nil {
+		return fmt.Errorf("report reservation result: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,109 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpp16_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thoughtworks/maeve-csms/manager/handlers/ocpp16"
+	"github.com/thoughtworks/maeve-csms/manager/ocpi"
+	types "github.com/thoughtworks/maeve-csms/manager/ocpp/ocpp16"
+	"github.com/thoughtworks/maeve-csms/manager/store"
+	"github.com/thoughtworks/maeve-csms/manager/store/inmemory"
+	"github.com/thoughtworks/maeve-csms/manager/testutil"
+	"k8s.io/utils/clock"
+	"testing"
+)
+
+type reportedReservationResult struct {
+	reservationId int
+	result        ocpi.CommandResult
+}
+
+func (f *fakeOcpiApi) ReportReservationResult(_ context.Context, reservationId int, result ocpi.CommandResult) error {
+	f.reportedReservation = append(f.reportedReservation, reportedReservationResult{
+		reservationId: reservationId,
+		result:        result,
+	})
+	return nil
+}
+
+func TestReserveNowResultHandler(t *testing.T) {
+	engine := inmemory.NewStore(clock.RealClock{})
+	ocpiApi := new(fakeOcpiApi)
+	handler := ocpp16.ReserveNowResultHandler{
+		Store:   engine,
+		OcpiApi: ocpiApi,
+	}
+
+	ctx := context.Background()
+
+	err := engine.SetReservation(ctx, &store.Reservation{
+		ReservationId:   1234,
+		ChargeStationId: "cs001",
+		Status:          store.ReservationStatusPending,
+	})
+	require.NoError(t, err)
+
+	tracer, exporter := testutil.GetTracer()
+
+	func() {
+		ctx, span := tracer.Start(ctx, `test`)
+		defer span.End()
+
+		req := &types.ReserveNowJson{
+			ConnectorId:   0,
+			ExpiryDate:    "2023-06-15T15:05:00Z",
+			IdTag:         "DEADBEEF",
+			ReservationId: 1234,
+		}
+		resp := &types.ReserveNowResponseJson{
+			Status: types.ReserveNowResponseJsonStatusOccupied,
+		}
+
+		err := handler.HandleCallResult(ctx, "cs001", req, resp, nil)
+		require.NoError(t, err)
+	}()
+
+	testutil.AssertSpan(t, &exporter.GetSpans()[0], "test", map[string]any{
+		"reserve_now.connector_id": 0,
+		"reserve_now.id":           1234,
+		"reserve_now.status":       "Occupied",
+	})
+
+	reservation, err := engine.LookupReservation(ctx, 1234)
+	require.NoError(t, err)
+	assert.Equal(t, store.ReservationStatusOccupied, reservation.Status)
+
+	want := []reportedReservationResult{
+		{
+			reservationId: 1234,
+			result: ocpi.CommandResult{
+				Result: ocpi.CommandResultResultEVSEOCCUPIED,
+			},
+		},
+	}
+	assert.Equal(t, want, ocpiApi.reportedReservation)
+}
+
+func TestReserveNowResultHandlerWithUnknownReservation(t *testing.T) {
+	engine := inmemory.NewStore(clock.RealClock{})
+	ocpiApi := new(fakeOcpiApi)
+	handler := ocpp16.ReserveNowResultHandler{
+		Store:   engine,
+		OcpiApi: ocpiApi,
+	}
+
+	req := &types.ReserveNowJson{
+		ReservationId: 1234,
+	}
+	resp := &types.ReserveNowResponseJson{
+		Status: types.ReserveNowResponseJsonStatusAccepted,
+	}
+
+	err := handler.HandleCallResult(context.Background(), "cs001", req, resp, nil)
+	require.NoError(t, err)
+
+	assert.Empty(t, ocpiApi.reportedReservation)
+}
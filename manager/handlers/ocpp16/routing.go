@@ -10,6 +10,7 @@ import (
 	"github.com/thoughtworks/maeve-csms/manager/handlers"
 	handlersHasToBe "github.com/thoughtworks/maeve-csms/manager/handlers/has2be"
 	handlers201 "github.com/thoughtworks/maeve-csms/manager/handlers/ocpp201"
+	"github.com/thoughtworks/maeve-csms/manager/ocpi"
 	"github.com/thoughtworks/maeve-csms/manager/ocpp"
 	"github.com/thoughtworks/maeve-csms/manager/ocpp/has2be"
 	"github.com/thoughtworks/maeve-csms/manager/ocpp/ocpp16"
@@ -29,15 +30,19 @@ func NewRouter(emitter transport.Emitter,
 	certValidationService services.CertificateValidationService,
 	chargeStationCertProvider services.ChargeStationCertificateProvider,
 	contractCertProvider services.ContractCertificateProvider,
+	ocpiApi ocpi.Api,
 	heartbeatInterval time.Duration,
+	syncNotifier handlers.SyncNotifier,
 	schemaFS fs.FS) transport.MessageHandler {
 
 	standardCallMaker := NewCallMaker(emitter)
 
 	return &handlers.Router{
-		Emitter:     emitter,
-		SchemaFS:    schemaFS,
-		OcppVersion: transport.OcppVersion16,
+		Emitter:        emitter,
+		SchemaFS:       schemaFS,
+		OcppVersion:    transport.OcppVersion16,
+		Clock:          clk,
+		OperationStore: engine,
 		CallRoutes: map[string]handlers.CallRoute{
 			"BootNotification": {
 				NewRequest:     func() ocpp.Request { return new(ocpp16.BootNotificationJson) },
@@ -45,9 +50,11 @@ func NewRouter(emitter transport.Emitter,
 				ResponseSchema: "ocpp16/BootNotificationResponse.json",
 				Handler: BootNotificationHandler{
 					Clock:               clk,
+					DetailsStore:        engine,
 					RuntimeDetailsStore: engine,
 					SettingsStore:       engine,
 					HeartbeatInterval:   int(heartbeatInterval.Seconds()),
+					SyncNotifier:        syncNotifier,
 				},
 			},
 			"Heartbeat": {
@@ -55,7 +62,8 @@ func NewRouter(emitter transport.Emitter,
 				RequestSchema:  "ocpp16/Heartbeat.json",
 				ResponseSchema: "ocpp16/HeartbeatResponse.json",
 				Handler: HeartbeatHandler{
-					Clock: clk,
+					Clock:        clk,
+					DetailsStore: engine,
 				},
 			},
 			"StatusNotification": {
@@ -80,6 +88,7 @@ func NewRouter(emitter transport.Emitter,
 					Clock:            clk,
 					TokenStore:       engine,
 					TransactionStore: engine,
+					ReservationStore: engine,
 				},
 			},
 			"StopTransaction": {
@@ -139,8 +148,10 @@ func NewRouter(emitter transport.Emitter,
 								RequestSchema:  "ocpp201/SignCertificateRequest.json",
 								ResponseSchema: "ocpp201/SignCertificateResponse.json",
 								Handler: handlers201.SignCertificateHandler{
+									Clock:                            clk,
 									ChargeStationCertificateProvider: chargeStationCertProvider,
 									Store:                            engine,
+									SyncNotifier:                     syncNotifier,
 								},
 							},
 							"Get15118EVCertificate": {
@@ -193,8 +204,10 @@ func NewRouter(emitter transport.Emitter,
 								ResponseSchema: "has2be/SignCertificateRequestJson.json",
 								Handler: handlersHasToBe.SignCertificateHandler{
 									Handler201: handlers201.SignCertificateHandler{
+										Clock:                            clk,
 										ChargeStationCertificateProvider: chargeStationCertProvider,
 										Store:                            engine,
+										SyncNotifier:                     syncNotifier,
 									},
 								},
 							},
@@ -222,6 +235,25 @@ func NewRouter(emitter transport.Emitter,
 									Store: engine,
 								},
 							},
+							"DeleteCertificate": {
+								NewRequest:     func() ocpp.Request { return new(ocpp201.DeleteCertificateRequestJson) },
+								NewResponse:    func() ocpp.Response { return new(ocpp201.DeleteCertificateResponseJson) },
+								RequestSchema:  "ocpp201/DeleteCertificateRequest.json",
+								ResponseSchema: "ocpp201/DeleteCertificateResponse.json",
+								Handler: handlers201.DeleteCertificateResultHandler{
+									Store: engine,
+								},
+							},
+							"GetInstalledCertificateIds": {
+								NewRequest:     func() ocpp.Request { return new(ocpp201.GetInstalledCertificateIdsRequestJson) },
+								NewResponse:    func() ocpp.Response { return new(ocpp201.GetInstalledCertificateIdsResponseJson) },
+								RequestSchema:  "ocpp201/GetInstalledCertificateIdsRequest.json",
+								ResponseSchema: "ocpp201/GetInstalledCertificateIdsResponse.json",
+								Handler: handlers201.GetInstalledCertificateIdsResultHandler{
+									Store: engine,
+									Clock: clk,
+								},
+							},
 							"InstallCertificate": {
 								NewRequest:     func() ocpp.Request { return new(ocpp201.InstallCertificateRequestJson) },
 								NewResponse:    func() ocpp.Response { return new(ocpp201.InstallCertificateResponseJson) },
@@ -229,6 +261,7 @@ func NewRouter(emitter transport.Emitter,
 								ResponseSchema: "ocpp201/InstallCertificateResponse.json",
 								Handler: handlers201.InstallCertificateResultHandler{
 									Store: engine,
+									Clock: clk,
 								},
 							},
 							"TriggerMessage": {
@@ -237,7 +270,8 @@ func NewRouter(emitter transport.Emitter,
 								RequestSchema:  "ocpp201/TriggerMessageRequest.json",
 								ResponseSchema: "ocpp201/TriggerMessageResponse.json",
 								Handler: handlers201.TriggerMessageResultHandler{
-									Store: engine,
+									Store:        engine,
+									SyncNotifier: syncNotifier,
 								},
 							},
 						},
@@ -263,12 +297,62 @@ func NewRouter(emitter transport.Emitter,
 					CallMaker:     standardCallMaker,
 				},
 			},
+			"CancelReservation": {
+				NewRequest:     func() ocpp.Request { return new(ocpp16.CancelReservationJson) },
+				NewResponse:    func() ocpp.Response { return new(ocpp16.CancelReservationResponseJson) },
+				RequestSchema:  "ocpp16/CancelReservation.json",
+				ResponseSchema: "ocpp16/CancelReservationResponse.json",
+				Handler: CancelReservationResultHandler{
+					Store:   engine,
+					OcpiApi: ocpiApi,
+				},
+			},
+			"ClearChargingProfile": {
+				NewRequest:     func() ocpp.Request { return new(ocpp16.ClearChargingProfileJson) },
+				NewResponse:    func() ocpp.Response { return new(ocpp16.ClearChargingProfileResponseJson) },
+				RequestSchema:  "ocpp16/ClearChargingProfile.json",
+				ResponseSchema: "ocpp16/ClearChargingProfileResponse.json",
+				Handler: ClearChargingProfileResultHandler{
+					OcpiApi: ocpiApi,
+				},
+			},
+			"GetCompositeSchedule": {
+				NewRequest:     func() ocpp.Request { return new(ocpp16.GetCompositeScheduleJson) },
+				NewResponse:    func() ocpp.Response { return new(ocpp16.GetCompositeScheduleResponseJson) },
+				RequestSchema:  "ocpp16/GetCompositeSchedule.json",
+				ResponseSchema: "ocpp16/GetCompositeScheduleResponse.json",
+				Handler: GetCompositeScheduleResultHandler{
+					OcpiApi: ocpiApi,
+				},
+			},
+			"ReserveNow": {
+				NewRequest:     func() ocpp.Request { return new(ocpp16.ReserveNowJson) },
+				NewResponse:    func() ocpp.Response { return new(ocpp16.ReserveNowResponseJson) },
+				RequestSchema:  "ocpp16/ReserveNow.json",
+				ResponseSchema: "ocpp16/ReserveNowResponse.json",
+				Handler: ReserveNowResultHandler{
+					Store:   engine,
+					OcpiApi: ocpiApi,
+				},
+			},
+			"SetChargingProfile": {
+				NewRequest:     func() ocpp.Request { return new(ocpp16.SetChargingProfileJson) },
+				NewResponse:    func() ocpp.Response { return new(ocpp16.SetChargingProfileResponseJson) },
+				RequestSchema:  "ocpp16/SetChargingProfile.json",
+				ResponseSchema: "ocpp16/SetChargingProfileResponse.json",
+				Handler: SetChargingProfileResultHandler{
+					OcpiApi: ocpiApi,
+				},
+			},
 			"TriggerMessage": {
 				NewRequest:     func() ocpp.Request { return new(ocpp16.TriggerMessageJson) },
 				NewResponse:    func() ocpp.Response { return new(ocpp16.TriggerMessageResponseJson) },
 				RequestSchema:  "ocpp16/TriggerMessage.json",
 				ResponseSchema: "ocpp16/TriggerMessageResponse.json",
-				Handler:        TriggerMessageResultHandler{},
+				Handler: TriggerMessageResultHandler{
+					Store:        engine,
+					SyncNotifier: syncNotifier,
+				},
 			},
 		},
 	}
@@ -282,6 +366,11 @@ func NewCallMaker(e transport.Emitter) *handlers.OcppCallMaker {
 			reflect.TypeOf(&ocpp16.ChangeConfigurationJson{}):    "ChangeConfiguration",
 			reflect.TypeOf(&ocpp16.TriggerMessageJson{}):         "TriggerMessage",
 			reflect.TypeOf(&ocpp16.RemoteStartTransactionJson{}): "RemoteStartTransaction",
+			reflect.TypeOf(&ocpp16.SetChargingProfileJson{}):     "SetChargingProfile",
+			reflect.TypeOf(&ocpp16.GetCompositeScheduleJson{}):   "GetCompositeSchedule",
+			reflect.TypeOf(&ocpp16.ClearChargingProfileJson{}):   "ClearChargingProfile",
+			reflect.TypeOf(&ocpp16.ReserveNowJson{}):             "ReserveNow",
+			reflect.TypeOf(&ocpp16.CancelReservationJson{}):      "CancelReservation",
 		},
 	}
 }
@@ -304,6 +393,14 @@ func NewDataTransferCallMaker(e transport.Emitter) *DataTransferCallMaker {
 				VendorId:  "org.openchargealliance.iso15118pnc",
 				MessageId: "CertificateSigned",
 			},
+			reflect.TypeOf(&ocpp201.DeleteCertificateRequestJson{}): {
+				VendorId:  "org.openchargealliance.iso15118pnc",
+				MessageId: "DeleteCertificate",
+			},
+			reflect.TypeOf(&ocpp201.GetInstalledCertificateIdsRequestJson{}): {
+				VendorId:  "org.openchargealliance.iso15118pnc",
+				MessageId: "GetInstalledCertificateIds",
+			},
 			reflect.TypeOf(&ocpp201.InstallCertificateRequestJson{}): {
 				VendorId:  "org.openchargealliance.iso15118pnc",
 				MessageId: "InstallCertificate",
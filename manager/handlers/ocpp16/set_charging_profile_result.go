@@ -0,0 +1,46 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpp16
+
+import (
+	"context"
+	"fmt"
+	"github.com/thoughtworks/maeve-csms/manager/ocpi"
+	"github.com/thoughtworks/maeve-csms/manager/ocpp"
+	"github.com/thoughtworks/maeve-csms/manager/ocpp/ocpp16"
+	"github.com/thoughtworks/maeve-csms/manager/store"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type SetChargingProfileResultHandler struct {
+	OcpiApi ocpi.Api
+}
+
+func (h SetChargingProfileResultHandler) HandleCallResult(ctx context.Context, chargeStationId string, request ocpp.Request, response ocpp.Response, state any) error {
+	req := request.(*ocpp16.SetChargingProfileJson)
+	resp := response.(*ocpp16.SetChargingProfileResponseJson)
+
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(
+		attribute.Int("set_charging_profile.connector_id", req.ConnectorId),
+		attribute.Int("set_charging_profile.id", req.CsChargingProfiles.ChargingProfileId),
+		attribute.String("set_charging_profile.status", string(resp.Status)))
+
+	if h.OcpiApi == nil {
+		return nil
+	}
+
+	result := ocpi.GenericChargingProfileResultResultREJECTED
+	if resp.Status == ocpp16.SetChargingProfileResponseJsonStatusAccepted {
+		result = ocpi.GenericChargingProfileResultResultACCEPTED
+	}
+
+	err := h.OcpiApi.ReportChargingProfileResult(ctx, chargeStationId, req.ConnectorId, store.OcpiChargingProfileRequestTypeSet,
+		ocpi.GenericChargingProfileResult{Result: result})
+	if err != nil {
+		return fmt.Errorf("report charging profile result: %w", err)
+	}
+
+	return nil
+}
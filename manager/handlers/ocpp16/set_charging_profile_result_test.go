@@ -0,0 +1,107 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpp16_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thoughtworks/maeve-csms/manager/handlers/ocpp16"
+	"github.com/thoughtworks/maeve-csms/manager/ocpi"
+	types "github.com/thoughtworks/maeve-csms/manager/ocpp/ocpp16"
+	"github.com/thoughtworks/maeve-csms/manager/store"
+	"github.com/thoughtworks/maeve-csms/manager/testutil"
+	"testing"
+)
+
+type reportedChargingProfileResult struct {
+	chargeStationId string
+	evseId          int
+	requestType     store.OcpiChargingProfileRequestType
+	result          ocpi.GenericChargingProfileResult
+}
+
+type fakeOcpiApi struct {
+	ocpi.Api
+	reported            []reportedChargingProfileResult
+	reportedReservation []reportedReservationResult
+}
+
+func (f *fakeOcpiApi) ReportChargingProfileResult(_ context.Context, chargeStationId string, evseId int, requestType store.OcpiChargingProfileRequestType, result ocpi.GenericChargingProfileResult) error {
+	f.reported = append(f.reported, reportedChargingProfileResult{
+		chargeStationId: chargeStationId,
+		evseId:          evseId,
+		requestType:     requestType,
+		result:          result,
+	})
+	return nil
+}
+
+func TestSetChargingProfileResultHandler(t *testing.T) {
+	ocpiApi := new(fakeOcpiApi)
+	handler := ocpp16.SetChargingProfileResultHandler{
+		OcpiApi: ocpiApi,
+	}
+
+	tracer, exporter := testutil.GetTracer()
+
+	ctx := context.Background()
+
+	func() {
+		ctx, span := tracer.Start(ctx, `test`)
+		defer span.End()
+
+		req := &types.SetChargingProfileJson{
+			ConnectorId: 1,
+			CsChargingProfiles: types.SetChargingProfileJsonCsChargingProfiles{
+				ChargingProfileId:      100,
+				ChargingProfileKind:    types.SetChargingProfileJsonCsChargingProfilesChargingProfileKindRelative,
+				ChargingProfilePurpose: types.SetChargingProfileJsonCsChargingProfilesChargingProfilePurposeTxProfile,
+				ChargingSchedule: types.SetChargingProfileJsonCsChargingProfilesChargingSchedule{
+					ChargingRateUnit: types.SetChargingProfileJsonCsChargingProfilesChargingScheduleChargingRateUnitA,
+					ChargingSchedulePeriod: []types.SetChargingProfileJsonCsChargingProfilesChargingScheduleChargingSchedulePeriodElem{
+						{StartPeriod: 0, Limit: 16},
+					},
+				},
+			},
+		}
+		resp := &types.SetChargingProfileResponseJson{
+			Status: types.SetChargingProfileResponseJsonStatusAccepted,
+		}
+
+		err := handler.HandleCallResult(ctx, "cs001", req, resp, nil)
+		require.NoError(t, err)
+	}()
+
+	testutil.AssertSpan(t, &exporter.GetSpans()[0], "test", map[string]any{
+		"set_charging_profile.connector_id": 1,
+		"set_charging_profile.id":           100,
+		"set_charging_profile.status":       "Accepted",
+	})
+
+	want := []reportedChargingProfileResult{
+		{
+			chargeStationId: "cs001",
+			evseId:          1,
+			requestType:     store.OcpiChargingProfileRequestTypeSet,
+			result: ocpi.GenericChargingProfileResult{
+				Result: ocpi.GenericChargingProfileResultResultACCEPTED,
+			},
+		},
+	}
+	assert.Equal(t, want, ocpiApi.reported)
+}
+
+func TestSetChargingProfileResultHandlerWithoutOcpi(t *testing.T) {
+	handler := ocpp16.SetChargingProfileResultHandler{}
+
+	req := &types.SetChargingProfileJson{
+		ConnectorId: 1,
+	}
+	resp := &types.SetChargingProfileResponseJson{
+		Status: types.SetChargingProfileResponseJsonStatusRejected,
+	}
+
+	err := handler.HandleCallResult(context.Background(), "cs001", req, resp, nil)
+	require.NoError(t, err)
+}
@@ -19,6 +19,7 @@ type StartTransactionHandler struct {
 	Clock            clock.PassiveClock
 	TokenStore       store.TokenStore
 	TransactionStore store.TransactionStore
+	ReservationStore store.ReservationStore
 }
 
 func (t StartTransactionHandler) HandleCall(ctx context.Context, chargeStationId string, request ocpp.Request) (ocpp.Response, error) {
@@ -61,6 +62,17 @@ func (t StartTransactionHandler) HandleCall(ctx context.Context, chargeStationId
 	if err != nil {
 		return nil, err
 	}
+	err = t.TransactionStore.SetTransactionEvseId(ctx, chargeStationId, transactionUuid, req.ConnectorId)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.ReservationId != nil && tok != nil {
+		err = markReservationUsed(ctx, t.ReservationStore, chargeStationId, *req.ReservationId)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	return &types.StartTransactionResponseJson{
 		IdTagInfo: types.StartTransactionResponseJsonIdTagInfo{
@@ -70,6 +82,20 @@ func (t StartTransactionHandler) HandleCall(ctx context.Context, chargeStationId
 	}, nil
 }
 
+// markReservationUsed records that the charge station has started a transaction
+// against a reservation
+func markReservationUsed(ctx context.Context, reservationStore store.ReservationStore, chargeStationId string, reservationId int) error {
+	reservation, err := reservationStore.LookupReservation(ctx, reservationId)
+	if err != nil {
+		return err
+	}
+	if reservation == nil || reservation.ChargeStationId != chargeStationId {
+		return nil
+	}
+	reservation.Status = store.ReservationStatusUsed
+	return reservationStore.SetReservation(ctx, reservation)
+}
+
 func ConvertToUUID(transactionId int) string {
 	uuidBytes := []byte{
 		0x00, 0x00, 0x00, 0x00,
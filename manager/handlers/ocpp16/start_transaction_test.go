@@ -77,6 +77,7 @@ func TestStartTransaction(t *testing.T) {
 	expected := &store.Transaction{
 		ChargeStationId: "cs001",
 		TransactionId:   transactionId,
+		EvseId:          1,
 		IdToken:         "MYRFIDTAG",
 		TokenType:       "ISO14443",
 		MeterValues: []store.MeterValue{
@@ -151,3 +152,56 @@ func TestStartTransactionWithInvalidRFID(t *testing.T) {
 
 	assert.Equal(t, want, got)
 }
+
+func TestStartTransactionWithReservation(t *testing.T) {
+	engine := inmemory.NewStore(clock.RealClock{})
+
+	ctx := context.Background()
+
+	err := engine.SetToken(ctx, &store.Token{
+		CountryCode: "GB",
+		PartyId:     "TWK",
+		Type:        "RFID",
+		Uid:         "MYRFIDTAG",
+		ContractId:  "GBTWK012345678V",
+		Issuer:      "Thoughtworks",
+		Valid:       true,
+		CacheMode:   "NEVER",
+		LastUpdated: time.Now().Format(time.RFC3339),
+	})
+	require.NoError(t, err)
+
+	err = engine.SetReservation(ctx, &store.Reservation{
+		ReservationId:   1234,
+		ChargeStationId: "cs001",
+		IdToken:         "MYRFIDTAG",
+		Status:          store.ReservationStatusAccepted,
+	})
+	require.NoError(t, err)
+
+	now, err := time.Parse(time.RFC3339, "2023-06-15T15:05:00+01:00")
+	require.NoError(t, err)
+
+	handler := handlers.StartTransactionHandler{
+		Clock:            clockTest.NewFakePassiveClock(now),
+		TokenStore:       engine,
+		TransactionStore: engine,
+		ReservationStore: engine,
+	}
+
+	reservationId := 1234
+	req := &types.StartTransactionJson{
+		ConnectorId:   1,
+		IdTag:         "MYRFIDTAG",
+		MeterStart:    100,
+		ReservationId: &reservationId,
+		Timestamp:     now.Format(time.RFC3339),
+	}
+
+	_, err = handler.HandleCall(ctx, "cs001", req)
+	require.NoError(t, err)
+
+	reservation, err := engine.LookupReservation(ctx, 1234)
+	require.NoError(t, err)
+	assert.Equal(t, store.ReservationStatusUsed, reservation.Status)
+}
@@ -4,13 +4,18 @@ package ocpp16
 
 import (
 	"context"
+	"github.com/thoughtworks/maeve-csms/manager/handlers"
 	"github.com/thoughtworks/maeve-csms/manager/ocpp"
 	"github.com/thoughtworks/maeve-csms/manager/ocpp/ocpp16"
+	"github.com/thoughtworks/maeve-csms/manager/store"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
 )
 
-type TriggerMessageResultHandler struct{}
+type TriggerMessageResultHandler struct {
+	Store        store.ChargeStationTriggerMessageStore
+	SyncNotifier handlers.SyncNotifier
+}
 
 func (c TriggerMessageResultHandler) HandleCallResult(ctx context.Context, chargeStationId string, request ocpp.Request, response ocpp.Response, state any) error {
 	req := request.(*ocpp16.TriggerMessageJson)
@@ -26,5 +31,8 @@ func (c TriggerMessageResultHandler) HandleCallResult(ctx context.Context, charg
 		span.SetAttributes(attribute.Int("trigger.connector_id", *req.ConnectorId))
 	}
 
-	return nil
+	return handlers.RecordTriggerMessageResult(ctx, c.Store, c.SyncNotifier, chargeStationId, &store.ChargeStationTriggerMessage{
+		TriggerMessage: store.TriggerMessage(req.RequestedMessage),
+		TriggerStatus:  store.TriggerStatus(resp.Status),
+	})
 }
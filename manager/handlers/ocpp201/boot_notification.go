@@ -4,6 +4,7 @@ package ocpp201
 
 import (
 	"context"
+	"github.com/thoughtworks/maeve-csms/manager/handlers"
 	"github.com/thoughtworks/maeve-csms/manager/store"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
@@ -17,7 +18,10 @@ import (
 type BootNotificationHandler struct {
 	Clock               clock.PassiveClock
 	RuntimeDetailsStore store.ChargeStationRuntimeDetailsStore
+	DetailsStore        store.ChargeStationDetailsStore
 	HeartbeatInterval   int
+	OcppVersion         string // the OCPP version recorded in the runtime details: defaults to 2.0.1
+	SyncNotifier        handlers.SyncNotifier
 }
 
 func (b BootNotificationHandler) HandleCall(ctx context.Context, chargeStationId string, request ocpp.Request) (ocpp.Response, error) {
@@ -38,13 +42,44 @@ func (b BootNotificationHandler) HandleCall(ctx context.Context, chargeStationId
 		span.SetAttributes(attribute.String("boot.firmware", *req.ChargingStation.FirmwareVersion))
 	}
 
+	ocppVersion := b.OcppVersion
+	if ocppVersion == "" {
+		ocppVersion = "2.0.1"
+	}
 	err := b.RuntimeDetailsStore.SetChargeStationRuntimeDetails(ctx, chargeStationId, &store.ChargeStationRuntimeDetails{
-		OcppVersion: "2.0.1",
+		OcppVersion: ocppVersion,
 	})
 	if err != nil {
 		return nil, err
 	}
 
+	details, err := b.DetailsStore.LookupChargeStationDetails(ctx, chargeStationId)
+	if err != nil {
+		return nil, err
+	}
+	if details == nil {
+		details = &store.ChargeStationDetails{}
+	}
+	details.Vendor = req.ChargingStation.VendorName
+	details.Model = req.ChargingStation.Model
+	details.SerialNumber = ""
+	if req.ChargingStation.SerialNumber != nil {
+		details.SerialNumber = *req.ChargingStation.SerialNumber
+	}
+	details.FirmwareVersion = ""
+	if req.ChargingStation.FirmwareVersion != nil {
+		details.FirmwareVersion = *req.ChargingStation.FirmwareVersion
+	}
+	details.LastSeen = b.Clock.Now().UTC()
+	err = b.DetailsStore.SetChargeStationDetails(ctx, chargeStationId, details)
+	if err != nil {
+		return nil, err
+	}
+
+	if b.SyncNotifier != nil {
+		b.SyncNotifier.NotifyConnected(ctx, chargeStationId)
+	}
+
 	return &types.BootNotificationResponseJson{
 		CurrentTime: b.Clock.Now().Format(time.RFC3339),
 		Interval:    b.HeartbeatInterval,
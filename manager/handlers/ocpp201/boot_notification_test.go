@@ -29,6 +29,7 @@ func TestBootNotificationHandler(t *testing.T) {
 	handler := handlers.BootNotificationHandler{
 		Clock:               clockTest.NewFakePassiveClock(now),
 		RuntimeDetailsStore: engine,
+		DetailsStore:        engine,
 		HeartbeatInterval:   10,
 	}
 
@@ -56,4 +57,41 @@ func TestBootNotificationHandler(t *testing.T) {
 	assert.Equal(t, store.ChargeStationRuntimeDetails{
 		OcppVersion: "2.0.1",
 	}, *details)
+
+	csDetails, err := engine.LookupChargeStationDetails(context.Background(), "cs001")
+	require.NoError(t, err)
+	assert.Equal(t, &store.ChargeStationDetails{
+		ChargeStationId: "cs001",
+		Model:           "testy",
+		SerialNumber:    "cs001",
+		LastSeen:        now.UTC(),
+	}, csDetails)
+}
+
+func TestBootNotificationHandlerRecordsOcppVersion(t *testing.T) {
+	engine := inmemory.NewStore(clock.RealClock{})
+
+	handler := handlers.BootNotificationHandler{
+		Clock:               clock.RealClock{},
+		RuntimeDetailsStore: engine,
+		DetailsStore:        engine,
+		HeartbeatInterval:   10,
+		OcppVersion:         "2.1",
+	}
+
+	req := &types.BootNotificationRequestJson{
+		ChargingStation: types.ChargingStationType{
+			Model: "testy",
+		},
+		Reason: types.BootReasonEnumTypePowerUp,
+	}
+
+	_, err := handler.HandleCall(context.Background(), "cs001", req)
+	require.NoError(t, err)
+
+	details, err := engine.LookupChargeStationRuntimeDetails(context.Background(), "cs001")
+	require.NoError(t, err)
+	assert.Equal(t, store.ChargeStationRuntimeDetails{
+		OcppVersion: "2.1",
+	}, *details)
 }
@@ -0,0 +1,63 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpp201
+
+import (
+	"context"
+	"github.com/thoughtworks/maeve-csms/manager/store"
+	"k8s.io/utils/clock"
+)
+
+// UpdateCertificateCampaigns records the result of installing a certificate on a charge station
+// against the in-progress (or halted) certificate campaigns that have queued the certificate for
+// the charge station. Charge stations that are still waiting for their wave are not updated.
+func UpdateCertificateCampaigns(ctx context.Context,
+	engine store.Engine,
+	clock clock.PassiveClock,
+	chargeStationId string,
+	certificateId string,
+	status store.CertificateCampaignStationStatus) error {
+	for _, campaignStatus := range []store.CertificateCampaignStatus{store.CertificateCampaignInProgress, store.CertificateCampaignHalted} {
+		for offset := 0; ; offset += 50 {
+			campaigns, err := engine.ListCertificateCampaigns(ctx, campaignStatus, offset, 50)
+			if err != nil {
+				return err
+			}
+			for _, campaign := range campaigns {
+				if campaign.CertificateId != certificateId {
+					continue
+				}
+				_, err = engine.UpdateCertificateCampaignStation(ctx, campaign.CampaignId, chargeStationId,
+					func(campaign *store.CertificateCampaign, station *store.CertificateCampaignStation) bool {
+						if !IsActiveCertificateCampaign(campaign) || !IsActiveCertificateCampaignStation(station) {
+							return false
+						}
+						station.Status = status
+						station.UpdatedAt = clock.Now().UTC()
+						return true
+					})
+				if err != nil {
+					return err
+				}
+			}
+			if len(campaigns) < 50 {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// IsActiveCertificateCampaign returns true if the campaign is still recording the results of
+// the installations that it has queued
+func IsActiveCertificateCampaign(campaign *store.CertificateCampaign) bool {
+	return campaign.Status == store.CertificateCampaignInProgress ||
+		campaign.Status == store.CertificateCampaignHalted
+}
+
+// IsActiveCertificateCampaignStation returns true if the certificate has been queued for the
+// charge station but it has not yet been installed (or given up on)
+func IsActiveCertificateCampaignStation(station *store.CertificateCampaignStation) bool {
+	return station.Status == store.CertificateCampaignStationPending ||
+		station.Status == store.CertificateCampaignStationRejected
+}
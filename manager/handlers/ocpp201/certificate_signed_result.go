@@ -68,5 +68,17 @@ func (c CertificateSignedResultHandler) HandleCallResult(ctx context.Context, ch
 		return err
 	}
 
+	// the certificates that the charge station was using are only superseded once it has
+	// accepted the new certificate
+	switch installStatus {
+	case store.CertificateInstallationAccepted:
+		err = SupersedeIssuedCertificates(ctx, c.Store, chargeStationId, storeType, certId)
+	case store.CertificateInstallationRejected:
+		err = RejectIssuedCertificate(ctx, c.Store, certId)
+	}
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
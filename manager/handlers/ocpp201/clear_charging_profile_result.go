@@ -0,0 +1,49 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpp201
+
+import (
+	"context"
+	"fmt"
+	"github.com/thoughtworks/maeve-csms/manager/ocpi"
+	"github.com/thoughtworks/maeve-csms/manager/ocpp"
+	"github.com/thoughtworks/maeve-csms/manager/ocpp/ocpp201"
+	"github.com/thoughtworks/maeve-csms/manager/store"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type ClearChargingProfileResultHandler struct {
+	OcpiApi ocpi.Api
+}
+
+func (h ClearChargingProfileResultHandler) HandleCallResult(ctx context.Context, chargeStationId string, request ocpp.Request, response ocpp.Response, state any) error {
+	req := request.(*ocpp201.ClearChargingProfileRequestJson)
+	resp := response.(*ocpp201.ClearChargingProfileResponseJson)
+
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(attribute.String("clear_charging_profile.status", string(resp.Status)))
+
+	if req.ChargingProfileCriteria == nil || req.ChargingProfileCriteria.EvseId == nil {
+		return nil
+	}
+	evseId := *req.ChargingProfileCriteria.EvseId
+	span.SetAttributes(attribute.Int("clear_charging_profile.evse_id", evseId))
+
+	if h.OcpiApi == nil {
+		return nil
+	}
+
+	result := ocpi.GenericChargingProfileResultResultUNKNOWN
+	if resp.Status == ocpp201.ClearChargingProfileStatusEnumTypeAccepted {
+		result = ocpi.GenericChargingProfileResultResultACCEPTED
+	}
+
+	err := h.OcpiApi.ReportChargingProfileResult(ctx, chargeStationId, evseId, store.OcpiChargingProfileRequestTypeClear,
+		ocpi.GenericChargingProfileResult{Result: result})
+	if err != nil {
+		return fmt.Errorf("report charging profile result: %w", err)
+	}
+
+	return nil
+}
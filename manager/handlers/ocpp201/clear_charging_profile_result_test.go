@@ -0,0 +1,48 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpp201_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thoughtworks/maeve-csms/manager/handlers/ocpp201"
+	"github.com/thoughtworks/maeve-csms/manager/ocpi"
+	types "github.com/thoughtworks/maeve-csms/manager/ocpp/ocpp201"
+	"github.com/thoughtworks/maeve-csms/manager/store"
+	"testing"
+)
+
+func TestClearChargingProfileResultHandler(t *testing.T) {
+	ocpiApi := new(fakeOcpiApi)
+	handler := ocpp201.ClearChargingProfileResultHandler{
+		OcpiApi: ocpiApi,
+	}
+
+	evseId := 2
+	purpose := types.ChargingProfilePurposeEnumTypeTxProfile
+	req := &types.ClearChargingProfileRequestJson{
+		ChargingProfileCriteria: &types.ClearChargingProfileType{
+			EvseId:                 &evseId,
+			ChargingProfilePurpose: &purpose,
+		},
+	}
+	resp := &types.ClearChargingProfileResponseJson{
+		Status: types.ClearChargingProfileStatusEnumTypeAccepted,
+	}
+
+	err := handler.HandleCallResult(context.Background(), "cs001", req, resp, nil)
+	require.NoError(t, err)
+
+	want := []reportedChargingProfileResult{
+		{
+			chargeStationId: "cs001",
+			evseId:          2,
+			requestType:     store.OcpiChargingProfileRequestTypeClear,
+			result: ocpi.GenericChargingProfileResult{
+				Result: ocpi.GenericChargingProfileResultResultACCEPTED,
+			},
+		},
+	}
+	assert.Equal(t, want, ocpiApi.reported)
+}
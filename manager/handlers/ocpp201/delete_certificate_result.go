@@ -6,11 +6,16 @@ import (
 	"context"
 	"github.com/thoughtworks/maeve-csms/manager/ocpp"
 	types "github.com/thoughtworks/maeve-csms/manager/ocpp/ocpp201"
+	"github.com/thoughtworks/maeve-csms/manager/store"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
 )
 
-type DeleteCertificateResultHandler struct{}
+// DeleteCertificateResultHandler removes a certificate from the charge station's installed
+// certificates once the charge station has deleted it (or reports that it was not installed)
+type DeleteCertificateResultHandler struct {
+	Store store.Engine
+}
 
 func (h DeleteCertificateResultHandler) HandleCallResult(ctx context.Context, chargeStationId string, request ocpp.Request, response ocpp.Response, state any) error {
 	req := request.(*types.DeleteCertificateRequestJson)
@@ -22,5 +27,27 @@ func (h DeleteCertificateResultHandler) HandleCallResult(ctx context.Context, ch
 		attribute.String("delete_certificate.serial_number", req.CertificateHashData.SerialNumber),
 		attribute.String("delete_certificate.status", string(resp.Status)))
 
-	return nil
+	deleted := &store.ChargeStationInstalledCertificate{
+		HashAlgorithm:  string(req.CertificateHashData.HashAlgorithm),
+		IssuerNameHash: req.CertificateHashData.IssuerNameHash,
+		IssuerKeyHash:  req.CertificateHashData.IssuerKeyHash,
+		SerialNumber:   req.CertificateHashData.SerialNumber,
+	}
+	_, err := h.Store.UpdateChargeStationInstalledCertificates(ctx, chargeStationId, func(installed *store.ChargeStationInstalledCertificates) bool {
+		changed := false
+		var certificates []*store.ChargeStationInstalledCertificate
+		for _, certificate := range installed.Certificates {
+			if certificate.SameCertificate(deleted) {
+				changed = true
+				if resp.Status != types.DeleteCertificateStatusEnumTypeFailed {
+					continue
+				}
+				certificate.DeletionStatus = store.CertificateDeletionFailed
+			}
+			certificates = append(certificates, certificate)
+		}
+		installed.Certificates = certificates
+		return changed
+	})
+	return err
 }
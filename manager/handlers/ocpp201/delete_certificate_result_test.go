@@ -4,20 +4,48 @@ package ocpp201_test
 
 import (
 	"context"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/thoughtworks/maeve-csms/manager/handlers/ocpp201"
 	types "github.com/thoughtworks/maeve-csms/manager/ocpp/ocpp201"
+	"github.com/thoughtworks/maeve-csms/manager/store"
+	"github.com/thoughtworks/maeve-csms/manager/store/inmemory"
 	"github.com/thoughtworks/maeve-csms/manager/testutil"
+	"k8s.io/utils/clock"
 	"testing"
 )
 
 func TestDeleteCertificateResultHandler(t *testing.T) {
-	handler := ocpp201.DeleteCertificateResultHandler{}
-
-	tracer, exporter := testutil.GetTracer()
+	engine := inmemory.NewStore(clock.RealClock{})
+	handler := ocpp201.DeleteCertificateResultHandler{
+		Store: engine,
+	}
 
 	ctx := context.Background()
 
+	err := engine.SetChargeStationInstalledCertificates(ctx, "cs001", &store.ChargeStationInstalledCertificates{
+		Certificates: []*store.ChargeStationInstalledCertificate{
+			{
+				CertificateType: store.CertificateTypeV2G,
+				HashAlgorithm:   "SHA256",
+				IssuerNameHash:  "abcdef",
+				IssuerKeyHash:   "abc123",
+				SerialNumber:    "012345678",
+				DeletionStatus:  store.CertificateDeletionPending,
+			},
+			{
+				CertificateType: store.CertificateTypeMO,
+				HashAlgorithm:   "SHA256",
+				IssuerNameHash:  "fedcba",
+				IssuerKeyHash:   "321cba",
+				SerialNumber:    "87654321",
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	tracer, exporter := testutil.GetTracer()
+
 	func() {
 		ctx, span := tracer.Start(ctx, `test`)
 		defer span.End()
@@ -42,4 +70,52 @@ func TestDeleteCertificateResultHandler(t *testing.T) {
 		"delete_certificate.serial_number": "12345678",
 		"delete_certificate.status":        "Accepted",
 	})
+
+	installed, err := engine.LookupChargeStationInstalledCertificates(ctx, "cs001")
+	require.NoError(t, err)
+	require.Len(t, installed.Certificates, 1)
+	assert.Equal(t, "87654321", installed.Certificates[0].SerialNumber)
+}
+
+func TestDeleteCertificateResultHandlerMarksFailedDeletion(t *testing.T) {
+	engine := inmemory.NewStore(clock.RealClock{})
+	handler := ocpp201.DeleteCertificateResultHandler{
+		Store: engine,
+	}
+
+	ctx := context.Background()
+
+	err := engine.SetChargeStationInstalledCertificates(ctx, "cs001", &store.ChargeStationInstalledCertificates{
+		Certificates: []*store.ChargeStationInstalledCertificate{
+			{
+				CertificateType: store.CertificateTypeV2G,
+				HashAlgorithm:   "SHA256",
+				IssuerNameHash:  "abcdef",
+				IssuerKeyHash:   "abc123",
+				SerialNumber:    "12345678",
+				DeletionStatus:  store.CertificateDeletionPending,
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	req := &types.DeleteCertificateRequestJson{
+		CertificateHashData: types.CertificateHashDataType{
+			HashAlgorithm:  types.HashAlgorithmEnumTypeSHA256,
+			IssuerKeyHash:  "abc123",
+			IssuerNameHash: "abcdef",
+			SerialNumber:   "12345678",
+		},
+	}
+	resp := &types.DeleteCertificateResponseJson{
+		Status: types.DeleteCertificateStatusEnumTypeFailed,
+	}
+
+	err = handler.HandleCallResult(ctx, "cs001", req, resp, nil)
+	require.NoError(t, err)
+
+	installed, err := engine.LookupChargeStationInstalledCertificates(ctx, "cs001")
+	require.NoError(t, err)
+	require.Len(t, installed.Certificates, 1)
+	assert.Equal(t, store.CertificateDeletionFailed, installed.Certificates[0].DeletionStatus)
 }
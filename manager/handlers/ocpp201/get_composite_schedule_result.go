@@ -0,0 +1,68 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpp201
+
+import (
+	"context"
+	"fmt"
+	"github.com/thoughtworks/maeve-csms/manager/ocpi"
+	"github.com/thoughtworks/maeve-csms/manager/ocpp"
+	"github.com/thoughtworks/maeve-csms/manager/ocpp/ocpp201"
+	"github.com/thoughtworks/maeve-csms/manager/store"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type GetCompositeScheduleResultHandler struct {
+	OcpiApi ocpi.Api
+}
+
+func (h GetCompositeScheduleResultHandler) HandleCallResult(ctx context.Context, chargeStationId string, request ocpp.Request, response ocpp.Response, state any) error {
+	req := request.(*ocpp201.GetCompositeScheduleRequestJson)
+	resp := response.(*ocpp201.GetCompositeScheduleResponseJson)
+
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(
+		attribute.Int("get_composite_schedule.evse_id", req.EvseId),
+		attribute.Int("get_composite_schedule.duration", req.Duration),
+		attribute.String("get_composite_schedule.status", string(resp.Status)))
+
+	if h.OcpiApi == nil {
+		return nil
+	}
+
+	result := ocpi.GenericChargingProfileResult{
+		Result: ocpi.GenericChargingProfileResultResultREJECTED,
+	}
+	if resp.Status == ocpp201.GenericStatusEnumTypeAccepted && resp.Schedule != nil {
+		result.Result = ocpi.GenericChargingProfileResultResultACCEPTED
+		result.Profile = convertCompositeSchedule(resp.Schedule)
+	}
+
+	err := h.OcpiApi.ReportChargingProfileResult(ctx, chargeStationId, req.EvseId, store.OcpiChargingProfileRequestTypeGet, result)
+	if err != nil {
+		return fmt.Errorf("report charging profile result: %w", err)
+	}
+
+	return nil
+}
+
+func convertCompositeSchedule(schedule *ocpp201.CompositeScheduleType) *ocpi.ActiveChargingProfile {
+	var periods []ocpi.ChargingProfilePeriod
+	for _, period := range schedule.ChargingSchedulePeriod {
+		periods = append(periods, ocpi.ChargingProfilePeriod{
+			StartPeriod: int32(period.StartPeriod),
+			Limit:       float32(period.Limit),
+		})
+	}
+
+	duration := int32(schedule.Duration)
+	return &ocpi.ActiveChargingProfile{
+		StartDateTime: schedule.ScheduleStart,
+		ChargingProfile: ocpi.ChargingProfile{
+			ChargingRateUnit:      ocpi.ChargingProfileChargingRateUnit(schedule.ChargingRateUnit),
+			ChargingProfilePeriod: &periods,
+			Duration:              &duration,
+		},
+	}
+}
@@ -0,0 +1,88 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpp201_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thoughtworks/maeve-csms/manager/handlers/ocpp201"
+	"github.com/thoughtworks/maeve-csms/manager/ocpi"
+	types "github.com/thoughtworks/maeve-csms/manager/ocpp/ocpp201"
+	"github.com/thoughtworks/maeve-csms/manager/store"
+	"testing"
+)
+
+func TestGetCompositeScheduleResultHandler(t *testing.T) {
+	ocpiApi := new(fakeOcpiApi)
+	handler := ocpp201.GetCompositeScheduleResultHandler{
+		OcpiApi: ocpiApi,
+	}
+
+	req := &types.GetCompositeScheduleRequestJson{
+		EvseId:   1,
+		Duration: 600,
+	}
+	resp := &types.GetCompositeScheduleResponseJson{
+		Status: types.GenericStatusEnumTypeAccepted,
+		Schedule: &types.CompositeScheduleType{
+			EvseId:           1,
+			Duration:         600,
+			ScheduleStart:    "2023-06-15T15:05:00Z",
+			ChargingRateUnit: types.ChargingRateUnitEnumTypeW,
+			ChargingSchedulePeriod: []types.ChargingSchedulePeriodType{
+				{StartPeriod: 0, Limit: 7400},
+				{StartPeriod: 300, Limit: 3700},
+			},
+		},
+	}
+
+	err := handler.HandleCallResult(context.Background(), "cs001", req, resp, nil)
+	require.NoError(t, err)
+
+	wantDuration := int32(600)
+	want := []reportedChargingProfileResult{
+		{
+			chargeStationId: "cs001",
+			evseId:          1,
+			requestType:     store.OcpiChargingProfileRequestTypeGet,
+			result: ocpi.GenericChargingProfileResult{
+				Result: ocpi.GenericChargingProfileResultResultACCEPTED,
+				Profile: &ocpi.ActiveChargingProfile{
+					StartDateTime: "2023-06-15T15:05:00Z",
+					ChargingProfile: ocpi.ChargingProfile{
+						ChargingRateUnit: ocpi.W,
+						Duration:         &wantDuration,
+						ChargingProfilePeriod: &[]ocpi.ChargingProfilePeriod{
+							{StartPeriod: 0, Limit: 7400},
+							{StartPeriod: 300, Limit: 3700},
+						},
+					},
+				},
+			},
+		},
+	}
+	assert.Equal(t, want, ocpiApi.reported)
+}
+
+func TestGetCompositeScheduleResultHandlerWithRejection(t *testing.T) {
+	ocpiApi := new(fakeOcpiApi)
+	handler := ocpp201.GetCompositeScheduleResultHandler{
+		OcpiApi: ocpiApi,
+	}
+
+	req := &types.GetCompositeScheduleRequestJson{
+		EvseId:   1,
+		Duration: 600,
+	}
+	resp := &types.GetCompositeScheduleResponseJson{
+		Status: types.GenericStatusEnumTypeRejected,
+	}
+
+	err := handler.HandleCallResult(context.Background(), "cs001", req, resp, nil)
+	require.NoError(t, err)
+
+	require.Len(t, ocpiApi.reported, 1)
+	assert.Equal(t, ocpi.GenericChargingProfileResultResultREJECTED, ocpiApi.reported[0].result.Result)
+	assert.Nil(t, ocpiApi.reported[0].result.Profile)
+}
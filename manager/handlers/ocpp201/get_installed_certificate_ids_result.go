@@ -6,12 +6,20 @@ import (
 	"context"
 	"github.com/thoughtworks/maeve-csms/manager/ocpp"
 	types "github.com/thoughtworks/maeve-csms/manager/ocpp/ocpp201"
+	"github.com/thoughtworks/maeve-csms/manager/store"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
+	"k8s.io/utils/clock"
 	"strings"
 )
 
-type GetInstalledCertificateIdsResultHandler struct{}
+// GetInstalledCertificateIdsResultHandler records the certificates that the charge station
+// reports as installed. Only the certificates of the requested types (or all types if none
+// were requested) are replaced: certificates that are waiting to be deleted remain so.
+type GetInstalledCertificateIdsResultHandler struct {
+	Store store.Engine
+	Clock clock.PassiveClock
+}
 
 func (h GetInstalledCertificateIdsResultHandler) HandleCallResult(ctx context.Context, chargeStationId string, request ocpp.Request, response ocpp.Response, state any) error {
 	req := request.(*types.GetInstalledCertificateIdsRequestJson)
@@ -20,9 +28,11 @@ func (h GetInstalledCertificateIdsResultHandler) HandleCallResult(ctx context.Co
 	span := trace.SpanFromContext(ctx)
 
 	var certTypes []string
+	requested := make(map[store.CertificateType]bool)
 	if req.CertificateType != nil {
 		for _, ct := range req.CertificateType {
 			certTypes = append(certTypes, string(ct))
+			requested[installedCertificateType(ct)] = true
 		}
 	}
 
@@ -30,5 +40,42 @@ func (h GetInstalledCertificateIdsResultHandler) HandleCallResult(ctx context.Co
 		attribute.String("get_installed_certificate.types", strings.Join(certTypes, ",")),
 		attribute.String("get_installed_certificate.status", string(resp.Status)))
 
+	installed, err := h.Store.UpdateChargeStationInstalledCertificates(ctx, chargeStationId, func(installed *store.ChargeStationInstalledCertificates) bool {
+		var certificates []*store.ChargeStationInstalledCertificate
+		for _, certificate := range installed.Certificates {
+			if len(requested) > 0 && !requested[certificate.CertificateType] {
+				certificates = append(certificates, certificate)
+			}
+		}
+		if resp.Status == types.GetInstalledCertificateStatusEnumTypeAccepted {
+			for _, chain := range resp.CertificateHashDataChain {
+				certificate := &store.ChargeStationInstalledCertificate{
+					CertificateType: installedCertificateType(chain.CertificateType),
+					HashAlgorithm:   string(chain.CertificateHashData.HashAlgorithm),
+					IssuerNameHash:  chain.CertificateHashData.IssuerNameHash,
+					IssuerKeyHash:   chain.CertificateHashData.IssuerKeyHash,
+					SerialNumber:    chain.CertificateHashData.SerialNumber,
+				}
+				for _, previous := range installed.Certificates {
+					if previous.SameCertificate(certificate) {
+						certificate.DeletionStatus = previous.DeletionStatus
+						certificate.SendAfter = previous.SendAfter
+						certificate.Attempts = previous.Attempts
+						break
+					}
+				}
+				certificates = append(certificates, certificate)
+			}
+		}
+
+		installed.Certificates = certificates
+		installed.ReportedAt = h.Clock.Now().UTC()
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	span.SetAttributes(attribute.Int("get_installed_certificate.count", len(installed.Certificates)))
+
 	return nil
 }
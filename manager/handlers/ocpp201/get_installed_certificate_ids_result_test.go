@@ -4,20 +4,61 @@ package ocpp201_test
 
 import (
 	"context"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/thoughtworks/maeve-csms/manager/handlers/ocpp201"
 	types "github.com/thoughtworks/maeve-csms/manager/ocpp/ocpp201"
+	"github.com/thoughtworks/maeve-csms/manager/store"
+	"github.com/thoughtworks/maeve-csms/manager/store/inmemory"
 	"github.com/thoughtworks/maeve-csms/manager/testutil"
+	clockTest "k8s.io/utils/clock/testing"
 	"testing"
+	"time"
 )
 
 func TestGetInstalledCertificateIdsResultHandler(t *testing.T) {
-	handler := ocpp201.GetInstalledCertificateIdsResultHandler{}
-
-	tracer, exporter := testutil.GetTracer()
+	now := time.Date(2023, 6, 15, 15, 0, 0, 0, time.UTC)
+	clk := clockTest.NewFakePassiveClock(now)
+	engine := inmemory.NewStore(clk)
+	handler := ocpp201.GetInstalledCertificateIdsResultHandler{
+		Store: engine,
+		Clock: clk,
+	}
 
 	ctx := context.Background()
 
+	err := engine.SetChargeStationInstalledCertificates(ctx, "cs001", &store.ChargeStationInstalledCertificates{
+		Certificates: []*store.ChargeStationInstalledCertificate{
+			{
+				CertificateType: store.CertificateTypeV2G,
+				HashAlgorithm:   "SHA256",
+				IssuerNameHash:  "V2G",
+				IssuerKeyHash:   "V2G",
+				SerialNumber:    "01",
+			},
+			{
+				CertificateType: store.CertificateTypeCSMS,
+				HashAlgorithm:   "SHA256",
+				IssuerNameHash:  "ABCDEF",
+				IssuerKeyHash:   "ABC123",
+				SerialNumber:    "12345678",
+				DeletionStatus:  store.CertificateDeletionPending,
+				Attempts:        1,
+			},
+			{
+				CertificateType: store.CertificateTypeMO,
+				HashAlgorithm:   "SHA256",
+				IssuerNameHash:  "REMOVED",
+				IssuerKeyHash:   "REMOVED",
+				SerialNumber:    "02",
+			},
+		},
+		RequestedAt: now.Add(-time.Minute),
+	})
+	require.NoError(t, err)
+
+	tracer, exporter := testutil.GetTracer()
+
 	func() {
 		ctx, span := tracer.Start(ctx, `test`)
 		defer span.End()
@@ -51,5 +92,57 @@ func TestGetInstalledCertificateIdsResultHandler(t *testing.T) {
 	testutil.AssertSpan(t, &exporter.GetSpans()[0], "test", map[string]any{
 		"get_installed_certificate.types":  "CSMSRootCertificate,MORootCertificate",
 		"get_installed_certificate.status": "Accepted",
+		"get_installed_certificate.count":  2,
 	})
+
+	installed, err := engine.LookupChargeStationInstalledCertificates(ctx, "cs001")
+	require.NoError(t, err)
+
+	want := []*store.ChargeStationInstalledCertificate{
+		{
+			CertificateType: store.CertificateTypeV2G,
+			HashAlgorithm:   "SHA256",
+			IssuerNameHash:  "V2G",
+			IssuerKeyHash:   "V2G",
+			SerialNumber:    "01",
+		},
+		{
+			CertificateType: store.CertificateTypeCSMS,
+			HashAlgorithm:   "SHA256",
+			IssuerNameHash:  "ABCDEF",
+			IssuerKeyHash:   "ABC123",
+			SerialNumber:    "12345678",
+			DeletionStatus:  store.CertificateDeletionPending,
+			Attempts:        1,
+		},
+	}
+	assert.Equal(t, want, installed.Certificates)
+	assert.Equal(t, now, installed.ReportedAt)
+	assert.Equal(t, now.Add(-time.Minute), installed.RequestedAt)
+}
+
+func TestGetInstalledCertificateIdsResultHandlerWithNoCertificates(t *testing.T) {
+	now := time.Date(2023, 6, 15, 15, 0, 0, 0, time.UTC)
+	clk := clockTest.NewFakePassiveClock(now)
+	engine := inmemory.NewStore(clk)
+	handler := ocpp201.GetInstalledCertificateIdsResultHandler{
+		Store: engine,
+		Clock: clk,
+	}
+
+	ctx := context.Background()
+
+	req := &types.GetInstalledCertificateIdsRequestJson{}
+	resp := &types.GetInstalledCertificateIdsResponseJson{
+		Status: types.GetInstalledCertificateStatusEnumTypeNotFound,
+	}
+
+	err := handler.HandleCallResult(ctx, "cs001", req, resp, nil)
+	require.NoError(t, err)
+
+	installed, err := engine.LookupChargeStationInstalledCertificates(ctx, "cs001")
+	require.NoError(t, err)
+	require.NotNil(t, installed)
+	assert.Empty(t, installed.Certificates)
+	assert.Equal(t, now, installed.ReportedAt)
 }
@@ -4,6 +4,7 @@ package ocpp201
 
 import (
 	"context"
+	"github.com/thoughtworks/maeve-csms/manager/store"
 	"time"
 
 	"github.com/thoughtworks/maeve-csms/manager/ocpp"
@@ -12,10 +13,24 @@ import (
 )
 
 type HeartbeatHandler struct {
-	Clock clock.PassiveClock
+	Clock        clock.PassiveClock
+	DetailsStore store.ChargeStationDetailsStore
 }
 
 func (h HeartbeatHandler) HandleCall(ctx context.Context, chargeStationId string, request ocpp.Request) (ocpp.Response, error) {
+	details, err := h.DetailsStore.LookupChargeStationDetails(ctx, chargeStationId)
+	if err != nil {
+		return nil, err
+	}
+	if details == nil {
+		details = &store.ChargeStationDetails{}
+	}
+	details.LastSeen = h.Clock.Now().UTC()
+	err = h.DetailsStore.SetChargeStationDetails(ctx, chargeStationId, details)
+	if err != nil {
+		return nil, err
+	}
+
 	return &types.HeartbeatResponseJson{
 		CurrentTime: h.Clock.Now().Format(time.RFC3339),
 	}, nil
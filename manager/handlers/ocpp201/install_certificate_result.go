@@ -9,10 +9,12 @@ import (
 	"github.com/thoughtworks/maeve-csms/manager/store"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
+	"k8s.io/utils/clock"
 )
 
 type InstallCertificateResultHandler struct {
 	Store store.Engine
+	Clock clock.PassiveClock
 }
 
 func (i InstallCertificateResultHandler) HandleCallResult(ctx context.Context, chargeStationId string, request ocpp.Request, response ocpp.Response, state any) error {
@@ -67,5 +69,16 @@ func (i InstallCertificateResultHandler) HandleCallResult(ctx context.Context, c
 		return err
 	}
 
-	return nil
+	// a failed installation is retried, so the campaigns are only told about a final result
+	var campaignStatus store.CertificateCampaignStationStatus
+	switch resp.Status {
+	case ocpp201.InstallCertificateStatusEnumTypeAccepted:
+		campaignStatus = store.CertificateCampaignStationAccepted
+	case ocpp201.InstallCertificateStatusEnumTypeRejected:
+		campaignStatus = store.CertificateCampaignStationRejected
+	default:
+		return nil
+	}
+
+	return UpdateCertificateCampaigns(ctx, i.Store, i.Clock, chargeStationId, certId, campaignStatus)
 }
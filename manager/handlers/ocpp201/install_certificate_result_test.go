@@ -13,12 +13,14 @@ import (
 	"github.com/thoughtworks/maeve-csms/manager/store/inmemory"
 	"github.com/thoughtworks/maeve-csms/manager/testutil"
 	"k8s.io/utils/clock"
+	clockTest "k8s.io/utils/clock/testing"
 	"testing"
+	"time"
 )
 
 func TestInstallCertificateResultHandler(t *testing.T) {
 	engine := inmemory.NewStore(clock.RealClock{})
-	handler := handlers201.InstallCertificateResultHandler{Store: engine}
+	handler := handlers201.InstallCertificateResultHandler{Store: engine, Clock: clock.RealClock{}}
 
 	pemBlock := &pem.Block{
 		Type:  "CERTIFICATE",
@@ -164,3 +166,94 @@ func TestInstallCertificateResultHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestInstallCertificateResultHandlerUpdatesCertificateCampaigns(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2023, 6, 15, 15, 0, 0, 0, time.UTC)
+	engine := inmemory.NewStore(clock.RealClock{})
+	handler := handlers201.InstallCertificateResultHandler{Store: engine, Clock: clockTest.NewFakePassiveClock(now)}
+
+	pemBytes := pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: []byte("test"),
+	})
+	id, err := handlers201.GetCertificateId(string(pemBytes))
+	require.NoError(t, err)
+
+	err = engine.CreateCertificateCampaign(ctx, &store.CertificateCampaign{
+		CampaignId:      "campaign001",
+		CertificateType: store.CertificateTypeV2G,
+		CertificateId:   id,
+		CertificateData: string(pemBytes),
+		Status:          store.CertificateCampaignInProgress,
+	}, []*store.CertificateCampaignStation{
+		{ChargeStationId: "cs001", Status: store.CertificateCampaignStationPending, Wave: 1},
+		{ChargeStationId: "cs002", Status: store.CertificateCampaignStationWaiting},
+	})
+	require.NoError(t, err)
+
+	req := &ocpp201.InstallCertificateRequestJson{
+		Certificate:     string(pemBytes),
+		CertificateType: ocpp201.InstallCertificateUseEnumTypeV2GRootCertificate,
+	}
+	resp := &ocpp201.InstallCertificateResponseJson{
+		Status: ocpp201.InstallCertificateStatusEnumTypeAccepted,
+	}
+	for _, csId := range []string{"cs001", "cs002"} {
+		err = handler.HandleCallResult(ctx, csId, req, resp, nil)
+		require.NoError(t, err)
+	}
+
+	stations, err := engine.ListCertificateCampaignStations(ctx, "campaign001", "", 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []*store.CertificateCampaignStation{
+		{ChargeStationId: "cs001", Status: store.CertificateCampaignStationAccepted, Wave: 1, UpdatedAt: now},
+		{ChargeStationId: "cs002", Status: store.CertificateCampaignStationWaiting},
+	}, stations)
+
+	campaign, err := engine.LookupCertificateCampaign(ctx, "campaign001")
+	require.NoError(t, err)
+	assert.Equal(t, 1, campaign.CountStations(store.CertificateCampaignStationAccepted))
+	assert.Equal(t, 0, campaign.CountStations(store.CertificateCampaignStationPending))
+}
+
+func TestInstallCertificateResultHandlerDoesNotUpdateCancelledCertificateCampaigns(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2023, 6, 15, 15, 0, 0, 0, time.UTC)
+	engine := inmemory.NewStore(clock.RealClock{})
+	handler := handlers201.InstallCertificateResultHandler{Store: engine, Clock: clockTest.NewFakePassiveClock(now)}
+
+	pemBytes := pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: []byte("test"),
+	})
+	id, err := handlers201.GetCertificateId(string(pemBytes))
+	require.NoError(t, err)
+
+	err = engine.CreateCertificateCampaign(ctx, &store.CertificateCampaign{
+		CampaignId:      "campaign001",
+		CertificateType: store.CertificateTypeV2G,
+		CertificateId:   id,
+		CertificateData: string(pemBytes),
+		Status:          store.CertificateCampaignCancelled,
+	}, []*store.CertificateCampaignStation{
+		{ChargeStationId: "cs001", Status: store.CertificateCampaignStationPending, Wave: 1},
+	})
+	require.NoError(t, err)
+
+	req := &ocpp201.InstallCertificateRequestJson{
+		Certificate:     string(pemBytes),
+		CertificateType: ocpp201.InstallCertificateUseEnumTypeV2GRootCertificate,
+	}
+	resp := &ocpp201.InstallCertificateResponseJson{
+		Status: ocpp201.InstallCertificateStatusEnumTypeAccepted,
+	}
+	err = handler.HandleCallResult(ctx, "cs001", req, resp, nil)
+	require.NoError(t, err)
+
+	stations, err := engine.ListCertificateCampaignStations(ctx, "campaign001", "", 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []*store.CertificateCampaignStation{
+		{ChargeStationId: "cs001", Status: store.CertificateCampaignStationPending, Wave: 1},
+	}, stations)
+}
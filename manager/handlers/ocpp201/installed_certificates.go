@@ -0,0 +1,129 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpp201
+
+import (
+	"crypto"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"github.com/thoughtworks/maeve-csms/manager/ocpp/ocpp201"
+	"github.com/thoughtworks/maeve-csms/manager/store"
+	"golang.org/x/crypto/ocsp"
+)
+
+// InstalledCertificateCompliance compares the certificates that a charge station has reported
+// as installed with the expected root certificates: Missing are the current root certificates
+// that are not installed and Revoked are the installed certificates that match a revoked root
+// certificate.
+type InstalledCertificateCompliance struct {
+	ChargeStationId string
+	Missing         []*store.RootCertificate
+	Revoked         []*RevokedInstalledCertificate
+}
+
+// RevokedInstalledCertificate is an installed certificate that matches a revoked root certificate
+type RevokedInstalledCertificate struct {
+	RootCertificate      *store.RootCertificate
+	InstalledCertificate *store.ChargeStationInstalledCertificate
+}
+
+// Compliant returns true if all the current root certificates are installed and none of the
+// revoked root certificates are
+func (c *InstalledCertificateCompliance) Compliant() bool {
+	return len(c.Missing) == 0 && len(c.Revoked) == 0
+}
+
+// CheckInstalledCertificates compares the installed certificates with the root certificates
+func CheckInstalledCertificates(installed *store.ChargeStationInstalledCertificates, roots []*store.RootCertificate) (*InstalledCertificateCompliance, error) {
+	compliance := &InstalledCertificateCompliance{
+		ChargeStationId: installed.ChargeStationId,
+	}
+	for _, root := range roots {
+		var match *store.ChargeStationInstalledCertificate
+		for _, cert := range installed.Certificates {
+			if cert.CertificateType != root.CertificateType {
+				continue
+			}
+			hashData, err := GetCertificateHashData(root.CertificateData, ocpp201.HashAlgorithmEnumType(cert.HashAlgorithm))
+			if err != nil {
+				return nil, fmt.Errorf("root certificate %s: %w", root.CertificateId, err)
+			}
+			if cert.SameCertificate(hashData) {
+				match = cert
+				break
+			}
+		}
+		if root.Revoked && match != nil {
+			compliance.Revoked = append(compliance.Revoked, &RevokedInstalledCertificate{
+				RootCertificate:      root,
+				InstalledCertificate: match,
+			})
+		} else if !root.Revoked && match == nil {
+			compliance.Missing = append(compliance.Missing, root)
+		}
+	}
+	return compliance, nil
+}
+
+// GetCertificateHashData returns the OCPP certificate hash data for a self-signed (root)
+// PEM encoded certificate using the provided hash algorithm
+func GetCertificateHashData(pemData string, hashAlgorithm ocpp201.HashAlgorithmEnumType) (*store.ChargeStationInstalledCertificate, error) {
+	block, _ := pem.Decode([]byte(pemData))
+	if block == nil {
+		return nil, fmt.Errorf("failed to decode certificate")
+	}
+	if block.Type != "CERTIFICATE" {
+		return nil, fmt.Errorf("expected certificate, got %s", block.Type)
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parsing certificate: %w", err)
+	}
+
+	var hash crypto.Hash
+	switch hashAlgorithm {
+	case ocpp201.HashAlgorithmEnumTypeSHA256:
+		hash = crypto.SHA256
+	case ocpp201.HashAlgorithmEnumTypeSHA384:
+		hash = crypto.SHA384
+	case ocpp201.HashAlgorithmEnumTypeSHA512:
+		hash = crypto.SHA512
+	default:
+		return nil, fmt.Errorf("unsupported hash algorithm: %s", hashAlgorithm)
+	}
+
+	// the OCPP certificate hash data is the same as that used to identify a certificate in an OCSP request
+	ocspReq, err := ocsp.CreateRequest(cert, cert, &ocsp.RequestOptions{Hash: hash})
+	if err != nil {
+		return nil, fmt.Errorf("creating hash data: %w", err)
+	}
+	hashData, err := ocsp.ParseRequest(ocspReq)
+	if err != nil {
+		return nil, fmt.Errorf("parsing hash data: %w", err)
+	}
+
+	return &store.ChargeStationInstalledCertificate{
+		HashAlgorithm:  string(hashAlgorithm),
+		IssuerNameHash: hex.EncodeToString(hashData.IssuerNameHash),
+		IssuerKeyHash:  hex.EncodeToString(hashData.IssuerKeyHash),
+		SerialNumber:   hashData.SerialNumber.Text(16),
+	}, nil
+}
+
+func installedCertificateType(use ocpp201.GetCertificateIdUseEnumType) store.CertificateType {
+	switch use {
+	case ocpp201.GetCertificateIdUseEnumTypeV2GRootCertificate:
+		return store.CertificateTypeV2G
+	case ocpp201.GetCertificateIdUseEnumTypeMORootCertificate:
+		return store.CertificateTypeMO
+	case ocpp201.GetCertificateIdUseEnumTypeCSMSRootCertificate:
+		return store.CertificateTypeCSMS
+	case ocpp201.GetCertificateIdUseEnumTypeManufacturerRootCertificate:
+		return store.CertificateTypeMF
+	case ocpp201.GetCertificateIdUseEnumTypeV2GCertificateChain:
+		return store.CertificateTypeEVCC
+	}
+	return store.CertificateType(use)
+}
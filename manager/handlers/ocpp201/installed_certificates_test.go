@@ -0,0 +1,95 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpp201_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thoughtworks/maeve-csms/manager/handlers/ocpp201"
+	types "github.com/thoughtworks/maeve-csms/manager/ocpp/ocpp201"
+	"github.com/thoughtworks/maeve-csms/manager/store"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func generateRootCertificate(t *testing.T, commonName string, serialNumber int64) (string, *ecdsa.PrivateKey) {
+	keyPair, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := x509.Certificate{
+		SerialNumber:          big.NewInt(serialNumber),
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	derBytes, err := x509.CreateCertificate(rand.Reader, &template, &template, &keyPair.PublicKey, keyPair)
+	require.NoError(t, err)
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: derBytes})), keyPair
+}
+
+func TestGetCertificateHashData(t *testing.T) {
+	pemData, keyPair := generateRootCertificate(t, "V2G Root", 0x1234)
+
+	got, err := ocpp201.GetCertificateHashData(pemData, types.HashAlgorithmEnumTypeSHA256)
+	require.NoError(t, err)
+
+	block, _ := pem.Decode([]byte(pemData))
+	cert, err := x509.ParseCertificate(block.Bytes)
+	require.NoError(t, err)
+	ecdhKey, err := keyPair.PublicKey.ECDH()
+	require.NoError(t, err)
+
+	nameHash := sha256.Sum256(cert.RawIssuer)
+	keyHash := sha256.Sum256(ecdhKey.Bytes())
+	assert.Equal(t, "SHA256", got.HashAlgorithm)
+	assert.Equal(t, hex.EncodeToString(nameHash[:]), got.IssuerNameHash)
+	assert.Equal(t, hex.EncodeToString(keyHash[:]), got.IssuerKeyHash)
+	assert.Equal(t, "1234", got.SerialNumber)
+}
+
+func TestCheckInstalledCertificates(t *testing.T) {
+	installedPem, _ := generateRootCertificate(t, "Installed", 1)
+	missingPem, _ := generateRootCertificate(t, "Missing", 2)
+	revokedPem, _ := generateRootCertificate(t, "Revoked", 3)
+	removedPem, _ := generateRootCertificate(t, "Removed", 4)
+
+	installedHashData, err := ocpp201.GetCertificateHashData(installedPem, types.HashAlgorithmEnumTypeSHA256)
+	require.NoError(t, err)
+	installedHashData.CertificateType = store.CertificateTypeV2G
+	revokedHashData, err := ocpp201.GetCertificateHashData(revokedPem, types.HashAlgorithmEnumTypeSHA384)
+	require.NoError(t, err)
+	revokedHashData.CertificateType = store.CertificateTypeMO
+
+	roots := []*store.RootCertificate{
+		{CertificateId: "installed", CertificateType: store.CertificateTypeV2G, CertificateData: installedPem},
+		{CertificateId: "missing", CertificateType: store.CertificateTypeCSMS, CertificateData: missingPem},
+		{CertificateId: "revoked", CertificateType: store.CertificateTypeMO, CertificateData: revokedPem, Revoked: true},
+		{CertificateId: "removed", CertificateType: store.CertificateTypeMO, CertificateData: removedPem, Revoked: true},
+	}
+
+	got, err := ocpp201.CheckInstalledCertificates(&store.ChargeStationInstalledCertificates{
+		ChargeStationId: "cs001",
+		Certificates:    []*store.ChargeStationInstalledCertificate{installedHashData, revokedHashData},
+	}, roots)
+	require.NoError(t, err)
+
+	assert.False(t, got.Compliant())
+	assert.Equal(t, "cs001", got.ChargeStationId)
+	assert.Equal(t, []*store.RootCertificate{roots[1]}, got.Missing)
+	require.Len(t, got.Revoked, 1)
+	assert.Equal(t, roots[2], got.Revoked[0].RootCertificate)
+	assert.Equal(t, revokedHashData, got.Revoked[0].InstalledCertificate)
+}
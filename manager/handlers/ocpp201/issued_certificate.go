@@ -0,0 +1,85 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpp201
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"github.com/thoughtworks/maeve-csms/manager/store"
+	"k8s.io/utils/clock"
+)
+
+// IndexIssuedCertificate records the leaf certificate from a PEM encoded certificate chain that
+// has been issued to a charge station, along with its expiry, so that the charge station can be
+// asked to renew it before it expires. The certificates that were previously issued to the
+// charge station are only superseded once it accepts the new certificate (see
+// SupersedeIssuedCertificates).
+func IndexIssuedCertificate(ctx context.Context,
+	engine store.IssuedCertificateStore,
+	clock clock.PassiveClock,
+	chargeStationId string,
+	certificateType store.CertificateType,
+	pemChain string) error {
+	block, _ := pem.Decode([]byte(pemChain))
+	if block == nil || block.Type != "CERTIFICATE" {
+		return fmt.Errorf("failed to decode certificate chain")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return fmt.Errorf("failed to parse certificate: %w", err)
+	}
+	certificateId, err := GetCertificateId(pemChain)
+	if err != nil {
+		return err
+	}
+
+	return engine.SetIssuedCertificate(ctx, &store.IssuedCertificate{
+		CertificateId:   certificateId,
+		ChargeStationId: chargeStationId,
+		CertificateType: certificateType,
+		SerialNumber:    cert.SerialNumber.Text(16),
+		IssuedAt:        clock.Now().UTC(),
+		NotAfter:        cert.NotAfter.UTC(),
+	})
+}
+
+// SupersedeIssuedCertificates marks the certificates of the same type that were issued to the
+// charge station before the certificate identified by certificateId as superseded, so they are
+// no longer renewed. It is called once the charge station has accepted the new certificate.
+func SupersedeIssuedCertificates(ctx context.Context,
+	engine store.IssuedCertificateStore,
+	chargeStationId string,
+	certificateType store.CertificateType,
+	certificateId string) error {
+	previous, err := engine.ListIssuedCertificatesForChargeStation(ctx, chargeStationId)
+	if err != nil {
+		return err
+	}
+	for _, issued := range previous {
+		if issued.CertificateType == certificateType && issued.CertificateId != certificateId && !issued.Superseded {
+			issued.Superseded = true
+			err = engine.SetIssuedCertificate(ctx, issued)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// RejectIssuedCertificate marks a certificate that the charge station has rejected as
+// superseded: the charge station continues to use its existing certificate, which remains
+// the one that is renewed.
+func RejectIssuedCertificate(ctx context.Context, engine store.IssuedCertificateStore, certificateId string) error {
+	issued, err := engine.LookupIssuedCertificate(ctx, certificateId)
+	if err != nil {
+		return err
+	}
+	if issued == nil || issued.Superseded {
+		return nil
+	}
+	issued.Superseded = true
+	return engine.SetIssuedCertificate(ctx, issued)
+}
@@ -0,0 +1,44 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpp201
+
+import (
+	"context"
+	"github.com/thoughtworks/maeve-csms/manager/ocpp"
+	"github.com/thoughtworks/maeve-csms/manager/ocpp/ocpp201"
+	"github.com/thoughtworks/maeve-csms/manager/store"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type ReservationStatusUpdateHandler struct {
+	Store store.ReservationStore
+}
+
+func (h ReservationStatusUpdateHandler) HandleCall(ctx context.Context, chargeStationId string, request ocpp.Request) (response ocpp.Response, err error) {
+	req := request.(*ocpp201.ReservationStatusUpdateRequestJson)
+
+	span := trace.SpanFromContext(ctx)
+
+	span.SetAttributes(
+		attribute.Int("reservation_status.id", req.ReservationId),
+		attribute.String("reservation_status.status", string(req.ReservationUpdateStatus)))
+
+	reservation, err := h.Store.LookupReservation(ctx, req.ReservationId)
+	if err != nil {
+		return nil, err
+	}
+	if reservation != nil && reservation.ChargeStationId == chargeStationId {
+		if req.ReservationUpdateStatus == ocpp201.ReservationUpdateStatusEnumTypeExpired {
+			reservation.Status = store.ReservationStatusExpired
+		} else {
+			reservation.Status = store.ReservationStatusRemoved
+		}
+		err = h.Store.SetReservation(ctx, reservation)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &ocpp201.ReservationStatusUpdateResponseJson{}, nil
+}
@@ -0,0 +1,86 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpp201_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thoughtworks/maeve-csms/manager/handlers/ocpp201"
+	types "github.com/thoughtworks/maeve-csms/manager/ocpp/ocpp201"
+	"github.com/thoughtworks/maeve-csms/manager/store"
+	"github.com/thoughtworks/maeve-csms/manager/store/inmemory"
+	"github.com/thoughtworks/maeve-csms/manager/testutil"
+	"k8s.io/utils/clock"
+	"testing"
+)
+
+func TestReservationStatusUpdate(t *testing.T) {
+	engine := inmemory.NewStore(clock.RealClock{})
+	handler := ocpp201.ReservationStatusUpdateHandler{
+		Store: engine,
+	}
+
+	ctx := context.Background()
+
+	err := engine.SetReservation(ctx, &store.Reservation{
+		ReservationId:   1234,
+		ChargeStationId: "cs001",
+		Status:          store.ReservationStatusAccepted,
+	})
+	require.NoError(t, err)
+
+	tracer, exporter := testutil.GetTracer()
+
+	func() {
+		ctx, span := tracer.Start(ctx, "test")
+		defer span.End()
+
+		req := &types.ReservationStatusUpdateRequestJson{
+			ReservationId:           1234,
+			ReservationUpdateStatus: types.ReservationUpdateStatusEnumTypeExpired,
+		}
+
+		resp, err := handler.HandleCall(ctx, "cs001", req)
+		require.NoError(t, err)
+
+		assert.Equal(t, &types.ReservationStatusUpdateResponseJson{}, resp)
+	}()
+
+	testutil.AssertSpan(t, &exporter.GetSpans()[0], "test", map[string]any{
+		"reservation_status.id":     1234,
+		"reservation_status.status": "Expired",
+	})
+
+	reservation, err := engine.LookupReservation(ctx, 1234)
+	require.NoError(t, err)
+	assert.Equal(t, store.ReservationStatusExpired, reservation.Status)
+}
+
+func TestReservationStatusUpdateForOtherChargeStation(t *testing.T) {
+	engine := inmemory.NewStore(clock.RealClock{})
+	handler := ocpp201.ReservationStatusUpdateHandler{
+		Store: engine,
+	}
+
+	ctx := context.Background()
+
+	err := engine.SetReservation(ctx, &store.Reservation{
+		ReservationId:   1234,
+		ChargeStationId: "cs002",
+		Status:          store.ReservationStatusAccepted,
+	})
+	require.NoError(t, err)
+
+	req := &types.ReservationStatusUpdateRequestJson{
+		ReservationId:           1234,
+		ReservationUpdateStatus: types.ReservationUpdateStatusEnumTypeRemoved,
+	}
+
+	_, err = handler.HandleCall(ctx, "cs001", req)
+	require.NoError(t, err)
+
+	reservation, err := engine.LookupReservation(ctx, 1234)
+	require.NoError(t, err)
+	assert.Equal(t, store.ReservationStatusAccepted, reservation.Status)
+}
@@ -0,0 +1,69 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpp201
+
+import (
+	"context"
+	"fmt"
+	"github.com/thoughtworks/maeve-csms/manager/ocpi"
+	"github.com/thoughtworks/maeve-csms/manager/ocpp"
+	"github.com/thoughtworks/maeve-csms/manager/ocpp/ocpp201"
+	"github.com/thoughtworks/maeve-csms/manager/store"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type ReserveNowResultHandler struct {
+	Store   store.ReservationStore
+	OcpiApi ocpi.Api
+}
+
+func (h ReserveNowResultHandler) HandleCallResult(ctx context.Context, chargeStationId string, request ocpp.Request, response ocpp.Response, state any) error {
+	req := request.(*ocpp201.ReserveNowRequestJson)
+	resp := response.(*ocpp201.ReserveNowResponseJson)
+
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(
+		attribute.Int("reserve_now.id", req.Id),
+		attribute.String("reserve_now.status", string(resp.Status)))
+	if req.EvseId != nil {
+		span.SetAttributes(attribute.Int("reserve_now.evse_id", *req.EvseId))
+	}
+
+	reservation, err := h.Store.LookupReservation(ctx, req.Id)
+	if err != nil {
+		return fmt.Errorf("lookup reservation %d: %w", req.Id, err)
+	}
+	if reservation == nil || reservation.ChargeStationId != chargeStationId {
+		return nil
+	}
+
+	reservation.Status = store.ReservationStatus(resp.Status)
+	err = h.Store.SetReservation(ctx, reservation)
+	if err != nil {
+		return fmt.Errorf("update reservation %d: %w", req.Id, err)
+	}
+
+	if h.OcpiApi == nil {
+		return nil
+	}
+
+	var result ocpi.CommandResultResult
+	switch resp.Status {
+	case ocpp201.ReserveNowStatusEnumTypeAccepted:
+		result = ocpi.CommandResultResultACCEPTED
+	case ocpp201.ReserveNowStatusEnumTypeOccupied:
+		result = ocpi.CommandResultResultEVSEOCCUPIED
+	case ocpp201.ReserveNowStatusEnumTypeFaulted, ocpp201.ReserveNowStatusEnumTypeUnavailable:
+		result = ocpi.CommandResultResultEVSEINOPERATIVE
+	default:
+		result = ocpi.CommandResultResultREJECTED
+	}
+
+	err = h.OcpiApi.ReportReservationResult(ctx, req.Id, ocpi.CommandResult{Result: result})
+	if err != nil {
+		return fmt.Errorf("report reservation result: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,113 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpp201_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thoughtworks/maeve-csms/manager/handlers/ocpp201"
+	"github.com/thoughtworks/maeve-csms/manager/ocpi"
+	types "github.com/thoughtworks/maeve-csms/manager/ocpp/ocpp201"
+	"github.com/thoughtworks/maeve-csms/manager/store"
+	"github.com/thoughtworks/maeve-csms/manager/store/inmemory"
+	"github.com/thoughtworks/maeve-csms/manager/testutil"
+	"k8s.io/utils/clock"
+	"testing"
+)
+
+type reportedReservationResult struct {
+	reservationId int
+	result        ocpi.CommandResult
+}
+
+func (f *fakeOcpiApi) ReportReservationResult(_ context.Context, reservationId int, result ocpi.CommandResult) error {
+	f.reportedReservation = append(f.reportedReservation, reportedReservationResult{
+		reservationId: reservationId,
+		result:        result,
+	})
+	return nil
+}
+
+func TestReserveNowResultHandler(t *testing.T) {
+	engine := inmemory.NewStore(clock.RealClock{})
+	ocpiApi := new(fakeOcpiApi)
+	handler := ocpp201.ReserveNowResultHandler{
+		Store:   engine,
+		OcpiApi: ocpiApi,
+	}
+
+	ctx := context.Background()
+
+	err := engine.SetReservation(ctx, &store.Reservation{
+		ReservationId:   1234,
+		ChargeStationId: "cs001",
+		Status:          store.ReservationStatusPending,
+	})
+	require.NoError(t, err)
+
+	tracer, exporter := testutil.GetTracer()
+
+	func() {
+		ctx, span := tracer.Start(ctx, `test`)
+		defer span.End()
+
+		evseId := 1
+		req := &types.ReserveNowRequestJson{
+			Id:             1234,
+			EvseId:         &evseId,
+			ExpiryDateTime: "2023-06-15T15:05:00Z",
+			IdToken: types.IdTokenType{
+				IdToken: "DEADBEEF",
+				Type:    types.IdTokenEnumTypeISO14443,
+			},
+		}
+		resp := &types.ReserveNowResponseJson{
+			Status: types.ReserveNowStatusEnumTypeOccupied,
+		}
+
+		err := handler.HandleCallResult(ctx, "cs001", req, resp, nil)
+		require.NoError(t, err)
+	}()
+
+	testutil.AssertSpan(t, &exporter.GetSpans()[0], "test", map[string]any{
+		"reserve_now.evse_id": 1,
+		"reserve_now.id":      1234,
+		"reserve_now.status":  "Occupied",
+	})
+
+	reservation, err := engine.LookupReservation(ctx, 1234)
+	require.NoError(t, err)
+	assert.Equal(t, store.ReservationStatusOccupied, reservation.Status)
+
+	want := []reportedReservationResult{
+		{
+			reservationId: 1234,
+			result: ocpi.CommandResult{
+				Result: ocpi.CommandResultResultEVSEOCCUPIED,
+			},
+		},
+	}
+	assert.Equal(t, want, ocpiApi.reportedReservation)
+}
+
+func TestReserveNowResultHandlerWithUnknownReservation(t *testing.T) {
+	engine := inmemory.NewStore(clock.RealClock{})
+	ocpiApi := new(fakeOcpiApi)
+	handler := ocpp201.ReserveNowResultHandler{
+		Store:   engine,
+		OcpiApi: ocpiApi,
+	}
+
+	req := &types.ReserveNowRequestJson{
+		Id: 1234,
+	}
+	resp := &types.ReserveNowResponseJson{
+		Status: types.ReserveNowStatusEnumTypeAccepted,
+	}
+
+	err := handler.HandleCallResult(context.Background(), "cs001", req, resp, nil)
+	require.NoError(t, err)
+
+	assert.Empty(t, ocpiApi.reportedReservation)
+}
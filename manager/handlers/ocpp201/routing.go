@@ -4,6 +4,7 @@ package ocpp201
 
 import (
 	"github.com/thoughtworks/maeve-csms/manager/handlers"
+	"github.com/thoughtworks/maeve-csms/manager/ocpi"
 	"github.com/thoughtworks/maeve-csms/manager/ocpp"
 	"github.com/thoughtworks/maeve-csms/manager/ocpp/ocpp201"
 	"github.com/thoughtworks/maeve-csms/manager/services"
@@ -22,13 +23,17 @@ func NewRouter(emitter transport.Emitter,
 	certValidationService services.CertificateValidationService,
 	chargeStationCertProvider services.ChargeStationCertificateProvider,
 	contractCertProvider services.ContractCertificateProvider,
+	ocpiApi ocpi.Api,
 	heartbeatInterval time.Duration,
+	syncNotifier handlers.SyncNotifier,
 	schemaFS fs.FS) transport.MessageHandler {
 
 	return &handlers.Router{
-		Emitter:     emitter,
-		SchemaFS:    schemaFS,
-		OcppVersion: transport.OcppVersion201,
+		Emitter:        emitter,
+		SchemaFS:       schemaFS,
+		OcppVersion:    transport.OcppVersion201,
+		Clock:          clk,
+		OperationStore: engine,
 		CallRoutes: map[string]handlers.CallRoute{
 			"Authorize": {
 				NewRequest:     func() ocpp.Request { return new(ocpp201.AuthorizeRequestJson) },
@@ -49,8 +54,10 @@ func NewRouter(emitter transport.Emitter,
 				ResponseSchema: "ocpp201/BootNotificationResponse.json",
 				Handler: BootNotificationHandler{
 					Clock:               clk,
+					DetailsStore:        engine,
 					HeartbeatInterval:   int(heartbeatInterval.Seconds()),
 					RuntimeDetailsStore: engine,
+					SyncNotifier:        syncNotifier,
 				},
 			},
 			"FirmwareStatusNotification": {
@@ -80,7 +87,8 @@ func NewRouter(emitter transport.Emitter,
 				RequestSchema:  "ocpp201/HeartbeatRequest.json",
 				ResponseSchema: "ocpp201/HeartbeatResponse.json",
 				Handler: HeartbeatHandler{
-					Clock: clk,
+					Clock:        clk,
+					DetailsStore: engine,
 				},
 			},
 			"LogStatusNotification": {
@@ -101,6 +109,14 @@ func NewRouter(emitter transport.Emitter,
 				ResponseSchema: "ocpp201/NotifyReportResponse.json",
 				Handler:        NotifyReportHandler{},
 			},
+			"ReservationStatusUpdate": {
+				NewRequest:     func() ocpp.Request { return new(ocpp201.ReservationStatusUpdateRequestJson) },
+				RequestSchema:  "ocpp201/ReservationStatusUpdateRequest.json",
+				ResponseSchema: "ocpp201/ReservationStatusUpdateResponse.json",
+				Handler: ReservationStatusUpdateHandler{
+					Store: engine,
+				},
+			},
 			"StatusNotification": {
 				NewRequest:     func() ocpp.Request { return new(ocpp201.StatusNotificationRequestJson) },
 				RequestSchema:  "ocpp201/StatusNotificationRequest.json",
@@ -112,8 +128,10 @@ func NewRouter(emitter transport.Emitter,
 				RequestSchema:  "ocpp201/SignCertificateRequest.json",
 				ResponseSchema: "ocpp201/SignCertificateResponse.json",
 				Handler: SignCertificateHandler{
+					Clock:                            clk,
 					ChargeStationCertificateProvider: chargeStationCertProvider,
 					Store:                            engine,
+					SyncNotifier:                     syncNotifier,
 				},
 			},
 			"SecurityEventNotification": {
@@ -137,6 +155,16 @@ func NewRouter(emitter transport.Emitter,
 			},
 		},
 		CallResultRoutes: map[string]handlers.CallResultRoute{
+			"CancelReservation": {
+				NewRequest:     func() ocpp.Request { return new(ocpp201.CancelReservationRequestJson) },
+				NewResponse:    func() ocpp.Response { return new(ocpp201.CancelReservationResponseJson) },
+				RequestSchema:  "ocpp201/CancelReservationRequest.json",
+				ResponseSchema: "ocpp201/CancelReservationResponse.json",
+				Handler: CancelReservationResultHandler{
+					Store:   engine,
+					OcpiApi: ocpiApi,
+				},
+			},
 			"CertificateSigned": {
 				NewRequest:     func() ocpp.Request { return new(ocpp201.CertificateSignedRequestJson) },
 				NewResponse:    func() ocpp.Response { return new(ocpp201.CertificateSignedResponseJson) },
@@ -160,12 +188,23 @@ func NewRouter(emitter transport.Emitter,
 				ResponseSchema: "ocpp201/ClearCacheResponse.json",
 				Handler:        ClearCacheResultHandler{},
 			},
+			"ClearChargingProfile": {
+				NewRequest:     func() ocpp.Request { return new(ocpp201.ClearChargingProfileRequestJson) },
+				NewResponse:    func() ocpp.Response { return new(ocpp201.ClearChargingProfileResponseJson) },
+				RequestSchema:  "ocpp201/ClearChargingProfileRequest.json",
+				ResponseSchema: "ocpp201/ClearChargingProfileResponse.json",
+				Handler: ClearChargingProfileResultHandler{
+					OcpiApi: ocpiApi,
+				},
+			},
 			"DeleteCertificate": {
 				NewRequest:     func() ocpp.Request { return new(ocpp201.DeleteCertificateRequestJson) },
 				NewResponse:    func() ocpp.Response { return new(ocpp201.DeleteCertificateResponseJson) },
 				RequestSchema:  "ocpp201/DeleteCertificateRequest.json",
 				ResponseSchema: "ocpp201/DeleteCertificateResponse.json",
-				Handler:        DeleteCertificateResultHandler{},
+				Handler: DeleteCertificateResultHandler{
+					Store: engine,
+				},
 			},
 			"GetBaseReport": {
 				NewRequest:     func() ocpp.Request { return new(ocpp201.GetBaseReportRequestJson) },
@@ -174,12 +213,24 @@ func NewRouter(emitter transport.Emitter,
 				ResponseSchema: "ocpp201/GetBaseReportResponse.json",
 				Handler:        GetBaseReportResultHandler{},
 			},
+			"GetCompositeSchedule": {
+				NewRequest:     func() ocpp.Request { return new(ocpp201.GetCompositeScheduleRequestJson) },
+				NewResponse:    func() ocpp.Response { return new(ocpp201.GetCompositeScheduleResponseJson) },
+				RequestSchema:  "ocpp201/GetCompositeScheduleRequest.json",
+				ResponseSchema: "ocpp201/GetCompositeScheduleResponse.json",
+				Handler: GetCompositeScheduleResultHandler{
+					OcpiApi: ocpiApi,
+				},
+			},
 			"GetInstalledCertificateIds": {
 				NewRequest:     func() ocpp.Request { return new(ocpp201.GetInstalledCertificateIdsRequestJson) },
 				NewResponse:    func() ocpp.Response { return new(ocpp201.GetInstalledCertificateIdsResponseJson) },
 				RequestSchema:  "ocpp201/GetInstalledCertificateIdsRequest.json",
 				ResponseSchema: "ocpp201/GetInstalledCertificateIdsResponse.json",
-				Handler:        GetInstalledCertificateIdsResultHandler{},
+				Handler: GetInstalledCertificateIdsResultHandler{
+					Store: engine,
+					Clock: clk,
+				},
 			},
 			"GetLocalListVersion": {
 				NewRequest:     func() ocpp.Request { return new(ocpp201.GetLocalListVersionRequestJson) },
@@ -216,6 +267,7 @@ func NewRouter(emitter transport.Emitter,
 				ResponseSchema: "ocpp201/InstallCertificateResponse.json",
 				Handler: InstallCertificateResultHandler{
 					Store: engine,
+					Clock: clk,
 				},
 			},
 			"RequestStartTransaction": {
@@ -232,6 +284,16 @@ func NewRouter(emitter transport.Emitter,
 				ResponseSchema: "ocpp201/RequestStopTransactionResponse.json",
 				Handler:        RequestStopTransactionResultHandler{},
 			},
+			"ReserveNow": {
+				NewRequest:     func() ocpp.Request { return new(ocpp201.ReserveNowRequestJson) },
+				NewResponse:    func() ocpp.Response { return new(ocpp201.ReserveNowResponseJson) },
+				RequestSchema:  "ocpp201/ReserveNowRequest.json",
+				ResponseSchema: "ocpp201/ReserveNowResponse.json",
+				Handler: ReserveNowResultHandler{
+					Store:   engine,
+					OcpiApi: ocpiApi,
+				},
+			},
 			"Reset": {
 				NewRequest:     func() ocpp.Request { return new(ocpp201.ResetRequestJson) },
 				NewResponse:    func() ocpp.Response { return new(ocpp201.ResetResponseJson) },
@@ -246,6 +308,15 @@ func NewRouter(emitter transport.Emitter,
 				ResponseSchema: "ocpp201/SendLocalListResponse.json",
 				Handler:        SendLocalListResultHandler{},
 			},
+			"SetChargingProfile": {
+				NewRequest:     func() ocpp.Request { return new(ocpp201.SetChargingProfileRequestJson) },
+				NewResponse:    func() ocpp.Response { return new(ocpp201.SetChargingProfileResponseJson) },
+				RequestSchema:  "ocpp201/SetChargingProfileRequest.json",
+				ResponseSchema: "ocpp201/SetChargingProfileResponse.json",
+				Handler: SetChargingProfileResultHandler{
+					OcpiApi: ocpiApi,
+				},
+			},
 			"SetNetworkProfile": {
 				NewRequest:     func() ocpp.Request { return new(ocpp201.SetNetworkProfileRequestJson) },
 				NewResponse:    func() ocpp.Response { return new(ocpp201.SetNetworkProfileResponseJson) },
@@ -268,7 +339,8 @@ func NewRouter(emitter transport.Emitter,
 				RequestSchema:  "ocpp201/TriggerMessageRequest.json",
 				ResponseSchema: "ocpp201/TriggerMessageResponse.json",
 				Handler: TriggerMessageResultHandler{
-					Store: engine,
+					Store:        engine,
+					SyncNotifier: syncNotifier,
 				},
 			},
 			"UnlockConnector": {
@@ -287,11 +359,14 @@ func NewCallMaker(e transport.Emitter) *handlers.OcppCallMaker {
 		Emitter:     e,
 		OcppVersion: transport.OcppVersion201,
 		Actions: map[reflect.Type]string{
+			reflect.TypeOf(&ocpp201.CancelReservationRequestJson{}):          "CancelReservation",
 			reflect.TypeOf(&ocpp201.CertificateSignedRequestJson{}):          "CertificateSigned",
 			reflect.TypeOf(&ocpp201.ChangeAvailabilityRequestJson{}):         "ChangeAvailability",
 			reflect.TypeOf(&ocpp201.ClearCacheRequestJson{}):                 "ClearCache",
+			reflect.TypeOf(&ocpp201.ClearChargingProfileRequestJson{}):       "ClearChargingProfile",
 			reflect.TypeOf(&ocpp201.DeleteCertificateRequestJson{}):          "DeleteCertificate",
 			reflect.TypeOf(&ocpp201.GetBaseReportRequestJson{}):              "GetBaseReport",
+			reflect.TypeOf(&ocpp201.GetCompositeScheduleRequestJson{}):       "GetCompositeSchedule",
 			reflect.TypeOf(&ocpp201.GetInstalledCertificateIdsRequestJson{}): "GetInstalledCertificateIds",
 			reflect.TypeOf(&ocpp201.GetLocalListVersionRequestJson{}):        "GetLocalListVersion",
 			reflect.TypeOf(&ocpp201.GetReportRequestJson{}):                  "GetReport",
@@ -300,8 +375,10 @@ func NewCallMaker(e transport.Emitter) *handlers.OcppCallMaker {
 			reflect.TypeOf(&ocpp201.InstallCertificateRequestJson{}):         "InstallCertificate",
 			reflect.TypeOf(&ocpp201.RequestStartTransactionRequestJson{}):    "RequestStartTransaction",
 			reflect.TypeOf(&ocpp201.RequestStopTransactionRequestJson{}):     "RequestStopTransaction",
+			reflect.TypeOf(&ocpp201.ReserveNowRequestJson{}):                 "ReserveNow",
 			reflect.TypeOf(&ocpp201.ResetRequestJson{}):                      "Reset",
 			reflect.TypeOf(&ocpp201.SendLocalListRequestJson{}):              "SendLocalList",
+			reflect.TypeOf(&ocpp201.SetChargingProfileRequestJson{}):         "SetChargingProfile",
 			reflect.TypeOf(&ocpp201.SetNetworkProfileRequestJson{}):          "SetNetworkProfile",
 			reflect.TypeOf(&ocpp201.SetVariablesRequestJson{}):               "SetVariables",
 			reflect.TypeOf(&ocpp201.TriggerMessageRequestJson{}):             "TriggerMessage",
@@ -84,7 +84,9 @@ func TestRoutingCalls(t *testing.T) {
 		&fakeCertValidationService{},
 		&fakeChargeStationCertProvider{},
 		&fakeContractCertProvider{},
+		nil,
 		5*time.Minute,
+		nil,
 		schemas.OcppSchemas,
 	)
 
@@ -233,7 +235,9 @@ func TestRoutingCallResults(t *testing.T) {
 		&fakeCertValidationService{},
 		&fakeChargeStationCertProvider{},
 		&fakeContractCertProvider{},
+		nil,
 		5*time.Minute,
+		nil,
 		schemas.OcppSchemas,
 	)
 
@@ -0,0 +1,46 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpp201
+
+import (
+	"context"
+	"fmt"
+	"github.com/thoughtworks/maeve-csms/manager/ocpi"
+	"github.com/thoughtworks/maeve-csms/manager/ocpp"
+	"github.com/thoughtworks/maeve-csms/manager/ocpp/ocpp201"
+	"github.com/thoughtworks/maeve-csms/manager/store"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type SetChargingProfileResultHandler struct {
+	OcpiApi ocpi.Api
+}
+
+func (h SetChargingProfileResultHandler) HandleCallResult(ctx context.Context, chargeStationId string, request ocpp.Request, response ocpp.Response, state any) error {
+	req := request.(*ocpp201.SetChargingProfileRequestJson)
+	resp := response.(*ocpp201.SetChargingProfileResponseJson)
+
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(
+		attribute.Int("set_charging_profile.evse_id", req.EvseId),
+		attribute.Int("set_charging_profile.id", req.ChargingProfile.Id),
+		attribute.String("set_charging_profile.status", string(resp.Status)))
+
+	if h.OcpiApi == nil {
+		return nil
+	}
+
+	result := ocpi.GenericChargingProfileResultResultREJECTED
+	if resp.Status == ocpp201.ChargingProfileStatusEnumTypeAccepted {
+		result = ocpi.GenericChargingProfileResultResultACCEPTED
+	}
+
+	err := h.OcpiApi.ReportChargingProfileResult(ctx, chargeStationId, req.EvseId, store.OcpiChargingProfileRequestTypeSet,
+		ocpi.GenericChargingProfileResult{Result: result})
+	if err != nil {
+		return fmt.Errorf("report charging profile result: %w", err)
+	}
+
+	return nil
+}
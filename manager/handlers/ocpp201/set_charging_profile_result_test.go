@@ -0,0 +1,96 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpp201_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thoughtworks/maeve-csms/manager/handlers/ocpp201"
+	"github.com/thoughtworks/maeve-csms/manager/ocpi"
+	types "github.com/thoughtworks/maeve-csms/manager/ocpp/ocpp201"
+	"github.com/thoughtworks/maeve-csms/manager/store"
+	"github.com/thoughtworks/maeve-csms/manager/testutil"
+	"testing"
+)
+
+type reportedChargingProfileResult struct {
+	chargeStationId string
+	evseId          int
+	requestType     store.OcpiChargingProfileRequestType
+	result          ocpi.GenericChargingProfileResult
+}
+
+type fakeOcpiApi struct {
+	ocpi.Api
+	reported            []reportedChargingProfileResult
+	reportedReservation []reportedReservationResult
+}
+
+func (f *fakeOcpiApi) ReportChargingProfileResult(_ context.Context, chargeStationId string, evseId int, requestType store.OcpiChargingProfileRequestType, result ocpi.GenericChargingProfileResult) error {
+	f.reported = append(f.reported, reportedChargingProfileResult{
+		chargeStationId: chargeStationId,
+		evseId:          evseId,
+		requestType:     requestType,
+		result:          result,
+	})
+	return nil
+}
+
+func TestSetChargingProfileResultHandler(t *testing.T) {
+	ocpiApi := new(fakeOcpiApi)
+	handler := ocpp201.SetChargingProfileResultHandler{
+		OcpiApi: ocpiApi,
+	}
+
+	tracer, exporter := testutil.GetTracer()
+
+	ctx := context.Background()
+
+	func() {
+		ctx, span := tracer.Start(ctx, `test`)
+		defer span.End()
+
+		req := &types.SetChargingProfileRequestJson{
+			EvseId: 1,
+			ChargingProfile: types.ChargingProfileType{
+				Id:                     100,
+				ChargingProfileKind:    types.ChargingProfileKindEnumTypeRelative,
+				ChargingProfilePurpose: types.ChargingProfilePurposeEnumTypeTxProfile,
+				ChargingSchedule: []types.ChargingScheduleType{
+					{
+						Id:               100,
+						ChargingRateUnit: types.ChargingRateUnitEnumTypeA,
+						ChargingSchedulePeriod: []types.ChargingSchedulePeriodType{
+							{StartPeriod: 0, Limit: 16},
+						},
+					},
+				},
+			},
+		}
+		resp := &types.SetChargingProfileResponseJson{
+			Status: types.ChargingProfileStatusEnumTypeRejected,
+		}
+
+		err := handler.HandleCallResult(ctx, "cs001", req, resp, nil)
+		require.NoError(t, err)
+	}()
+
+	testutil.AssertSpan(t, &exporter.GetSpans()[0], "test", map[string]any{
+		"set_charging_profile.evse_id": 1,
+		"set_charging_profile.id":      100,
+		"set_charging_profile.status":  "Rejected",
+	})
+
+	want := []reportedChargingProfileResult{
+		{
+			chargeStationId: "cs001",
+			evseId:          1,
+			requestType:     store.OcpiChargingProfileRequestTypeSet,
+			result: ocpi.GenericChargingProfileResult{
+				Result: ocpi.GenericChargingProfileResultResultREJECTED,
+			},
+		},
+	}
+	assert.Equal(t, want, ocpiApi.reported)
+}
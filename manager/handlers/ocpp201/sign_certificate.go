@@ -4,6 +4,7 @@ package ocpp201
 
 import (
 	"context"
+	"github.com/thoughtworks/maeve-csms/manager/handlers"
 	"github.com/thoughtworks/maeve-csms/manager/store"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
@@ -12,11 +13,14 @@ import (
 	types "github.com/thoughtworks/maeve-csms/manager/ocpp/ocpp201"
 	"github.com/thoughtworks/maeve-csms/manager/services"
 	"golang.org/x/exp/slog"
+	"k8s.io/utils/clock"
 )
 
 type SignCertificateHandler struct {
+	Clock                            clock.PassiveClock
 	ChargeStationCertificateProvider services.ChargeStationCertificateProvider
 	Store                            store.Engine
+	SyncNotifier                     handlers.SyncNotifier
 }
 
 func (s SignCertificateHandler) HandleCall(ctx context.Context, chargeStationId string, request ocpp.Request) (ocpp.Response, error) {
@@ -69,6 +73,14 @@ func (s SignCertificateHandler) HandleCall(ctx context.Context, chargeStationId
 					span.AddEvent("failed to update charge station install certificates", trace.WithAttributes(attribute.String("err", err.Error())))
 				} else {
 					status = types.GenericStatusEnumTypeAccepted
+					err = IndexIssuedCertificate(ctx, s.Store, s.Clock, chargeStationId, storeType, pemChain)
+					if err != nil {
+						slog.Warn("failed to index issued certificate", "err", err)
+						span.AddEvent("failed to index issued certificate", trace.WithAttributes(attribute.String("err", err.Error())))
+					}
+					if s.SyncNotifier != nil {
+						s.SyncNotifier.NotifyPending(ctx, chargeStationId)
+					}
 				}
 			}
 		}
@@ -4,7 +4,13 @@ package ocpp201_test
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"encoding/pem"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	handlers201 "github.com/thoughtworks/maeve-csms/manager/handlers/ocpp201"
 	"github.com/thoughtworks/maeve-csms/manager/ocpp/ocpp201"
@@ -13,7 +19,10 @@ import (
 	"github.com/thoughtworks/maeve-csms/manager/store/inmemory"
 	"github.com/thoughtworks/maeve-csms/manager/testutil"
 	"k8s.io/utils/clock"
+	clockTest "k8s.io/utils/clock/testing"
+	"math/big"
 	"testing"
+	"time"
 )
 
 type mockCertificateProvider struct{}
@@ -36,6 +45,7 @@ func TestSignCertificateHandler(t *testing.T) {
 	}
 
 	handler := handlers201.SignCertificateHandler{
+		Clock:                            clock.RealClock{},
 		ChargeStationCertificateProvider: mockCertificateProvider{},
 		Store:                            engine,
 	}
@@ -65,3 +75,158 @@ func TestSignCertificateHandler(t *testing.T) {
 	require.Equal(t, store.CertificateTypeEVCC, certs.Certificates[0].CertificateType)
 	require.Equal(t, store.CertificateInstallationPending, certs.Certificates[0].CertificateInstallationStatus)
 }
+
+type issuingCertificateProvider struct {
+	serial   int64
+	notAfter time.Time
+}
+
+func (i *issuingCertificateProvider) ProvideCertificate(context.Context, services.CertificateType, string, string) (pemEncodedCertificateChain string, err error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return "", err
+	}
+	i.serial++
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(i.serial),
+		Subject:      pkix.Name{CommonName: "cs001"},
+		NotBefore:    i.notAfter.Add(-365 * 24 * time.Hour),
+		NotAfter:     i.notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return "", err
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})), nil
+}
+
+func TestSignCertificateHandlerIndexesIssuedCertificate(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2023, 6, 15, 15, 0, 0, 0, time.UTC)
+	engine := inmemory.NewStore(clock.RealClock{})
+	provider := &issuingCertificateProvider{notAfter: now.Add(90 * 24 * time.Hour)}
+
+	handler := handlers201.SignCertificateHandler{
+		Clock:                            clockTest.NewFakePassiveClock(now),
+		ChargeStationCertificateProvider: provider,
+		Store:                            engine,
+	}
+
+	typ := ocpp201.CertificateSigningUseEnumTypeChargingStationCertificate
+	req := &ocpp201.SignCertificateRequestJson{
+		CertificateType: &typ,
+		Csr:             "test",
+	}
+
+	_, err := handler.HandleCall(ctx, "cs001", req)
+	require.NoError(t, err)
+	provider.notAfter = now.Add(180 * 24 * time.Hour)
+	_, err = handler.HandleCall(ctx, "cs001", req)
+	require.NoError(t, err)
+
+	// the existing certificate is not superseded until the charge station accepts the new one
+	issued, err := engine.ListIssuedCertificatesForChargeStation(ctx, "cs001")
+	require.NoError(t, err)
+	require.Len(t, issued, 2)
+	for _, certificate := range issued {
+		assert.False(t, certificate.Superseded)
+	}
+
+	err = handlers201.CertificateSignedResultHandler{Store: engine}.HandleCallResult(ctx, "cs001",
+		&ocpp201.CertificateSignedRequestJson{
+			CertificateChain: issuedCertificateChain(t, engine, "cs001", "2"),
+			CertificateType:  &typ,
+		},
+		&ocpp201.CertificateSignedResponseJson{
+			Status: ocpp201.CertificateSignedStatusEnumTypeAccepted,
+		}, nil)
+	require.NoError(t, err)
+
+	issued, err = engine.ListIssuedCertificatesForChargeStation(ctx, "cs001")
+	require.NoError(t, err)
+	require.Len(t, issued, 2)
+
+	var current, superseded *store.IssuedCertificate
+	for _, certificate := range issued {
+		if certificate.Superseded {
+			superseded = certificate
+		} else {
+			current = certificate
+		}
+	}
+	require.NotNil(t, current)
+	require.NotNil(t, superseded)
+	assert.Equal(t, store.CertificateTypeChargeStation, current.CertificateType)
+	assert.Equal(t, "2", current.SerialNumber)
+	assert.Equal(t, now.Add(180*24*time.Hour), current.NotAfter)
+	assert.Equal(t, now, current.IssuedAt)
+	assert.Equal(t, "1", superseded.SerialNumber)
+
+	expiring, err := engine.ListExpiringIssuedCertificates(ctx, now.Add(365*24*time.Hour), 0, 10)
+	require.NoError(t, err)
+	require.Len(t, expiring, 1)
+	assert.Equal(t, current.CertificateId, expiring[0].CertificateId)
+}
+
+func TestCertificateSignedResultHandlerDoesNotSupersedeWhenRejected(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2023, 6, 15, 15, 0, 0, 0, time.UTC)
+	engine := inmemory.NewStore(clock.RealClock{})
+	provider := &issuingCertificateProvider{notAfter: now.Add(90 * 24 * time.Hour)}
+
+	handler := handlers201.SignCertificateHandler{
+		Clock:                            clockTest.NewFakePassiveClock(now),
+		ChargeStationCertificateProvider: provider,
+		Store:                            engine,
+	}
+
+	typ := ocpp201.CertificateSigningUseEnumTypeChargingStationCertificate
+	req := &ocpp201.SignCertificateRequestJson{
+		CertificateType: &typ,
+		Csr:             "test",
+	}
+
+	_, err := handler.HandleCall(ctx, "cs001", req)
+	require.NoError(t, err)
+	provider.notAfter = now.Add(180 * 24 * time.Hour)
+	_, err = handler.HandleCall(ctx, "cs001", req)
+	require.NoError(t, err)
+
+	err = handlers201.CertificateSignedResultHandler{Store: engine}.HandleCallResult(ctx, "cs001",
+		&ocpp201.CertificateSignedRequestJson{
+			CertificateChain: issuedCertificateChain(t, engine, "cs001", "2"),
+			CertificateType:  &typ,
+		},
+		&ocpp201.CertificateSignedResponseJson{
+			Status: ocpp201.CertificateSignedStatusEnumTypeRejected,
+		}, nil)
+	require.NoError(t, err)
+
+	// the charge station is still using the first certificate, so that is the one to renew
+	expiring, err := engine.ListExpiringIssuedCertificates(ctx, now.Add(365*24*time.Hour), 0, 10)
+	require.NoError(t, err)
+	require.Len(t, expiring, 1)
+	assert.Equal(t, "1", expiring[0].SerialNumber)
+}
+
+// issuedCertificateChain returns the PEM chain that was queued for installation on the charge
+// station for the issued certificate with the given serial number
+func issuedCertificateChain(t *testing.T, engine store.Engine, chargeStationId, serialNumber string) string {
+	ctx := context.Background()
+	issued, err := engine.ListIssuedCertificatesForChargeStation(ctx, chargeStationId)
+	require.NoError(t, err)
+	install, err := engine.LookupChargeStationInstallCertificates(ctx, chargeStationId)
+	require.NoError(t, err)
+	for _, certificate := range issued {
+		if certificate.SerialNumber != serialNumber {
+			continue
+		}
+		for _, pending := range install.Certificates {
+			if pending.CertificateId == certificate.CertificateId {
+				return pending.CertificateData
+			}
+		}
+	}
+	require.Failf(t, "certificate not found", "serial number %s", serialNumber)
+	return ""
+}
@@ -69,6 +69,27 @@ func (t TransactionEventHandler) HandleCall(ctx context.Context, chargeStationId
 		return nil, err
 	}
 
+	if req.Evse != nil {
+		err = t.Store.SetTransactionEvseId(ctx, chargeStationId, req.TransactionInfo.TransactionId, req.Evse.Id)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if req.ReservationId != nil {
+		reservation, err := t.Store.LookupReservation(ctx, *req.ReservationId)
+		if err != nil {
+			return nil, err
+		}
+		if reservation != nil && reservation.ChargeStationId == chargeStationId {
+			reservation.Status = store.ReservationStatusUsed
+			err = t.Store.SetReservation(ctx, reservation)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
 	if req.EventType == types.TransactionEventEnumTypeEnded {
 		transaction, err := t.Store.FindTransaction(ctx, chargeStationId, req.TransactionInfo.TransactionId)
 		if err != nil {
@@ -240,3 +240,72 @@ func TestTransactionEventHandlerWithEndedEvent(t *testing.T) {
 	require.NoError(t, err)
 	assert.NotNil(t, transaction)
 }
+
+func TestTransactionEventHandlerRecordsEvseId(t *testing.T) {
+	ctx := context.Background()
+	engine := inmemory.NewStore(clock.RealClock{})
+
+	handler := handlers.TransactionEventHandler{
+		Store:            engine,
+		TokenAuthService: &services.OcppTokenAuthService{Clock: clock.RealClock{}, TokenStore: engine},
+		TariffService:    services.BasicKwhTariffService{},
+	}
+
+	req := &types.TransactionEventRequestJson{
+		EventType:     types.TransactionEventEnumTypeStarted,
+		TriggerReason: types.TriggerReasonEnumTypeCablePluggedIn,
+		Timestamp:     "2023-05-05T12:00:00+01:00",
+		SeqNo:         0,
+		Evse: &types.EVSEType{
+			Id: 2,
+		},
+		TransactionInfo: types.TransactionType{
+			TransactionId: "5555",
+		},
+	}
+
+	_, err := handler.HandleCall(ctx, "cs001", req)
+	require.NoError(t, err)
+
+	transaction, err := engine.FindTransaction(ctx, "cs001", "5555")
+	require.NoError(t, err)
+	require.NotNil(t, transaction)
+	assert.Equal(t, 2, transaction.EvseId)
+}
+
+func TestTransactionEventHandlerMarksReservationUsed(t *testing.T) {
+	ctx := context.Background()
+	engine := inmemory.NewStore(clock.RealClock{})
+
+	err := engine.SetReservation(ctx, &store.Reservation{
+		ReservationId:   1234,
+		ChargeStationId: "cs001",
+		Status:          store.ReservationStatusAccepted,
+	})
+	require.NoError(t, err)
+
+	handler := handlers.TransactionEventHandler{
+		Store:            engine,
+		TokenAuthService: &services.OcppTokenAuthService{Clock: clock.RealClock{}, TokenStore: engine},
+		TariffService:    services.BasicKwhTariffService{},
+	}
+
+	reservationId := 1234
+	req := &types.TransactionEventRequestJson{
+		EventType:     types.TransactionEventEnumTypeStarted,
+		TriggerReason: types.TriggerReasonEnumTypeCablePluggedIn,
+		Timestamp:     "2023-05-05T12:00:00+01:00",
+		SeqNo:         0,
+		ReservationId: &reservationId,
+		TransactionInfo: types.TransactionType{
+			TransactionId: "5555",
+		},
+	}
+
+	_, err = handler.HandleCall(ctx, "cs001", req)
+	require.NoError(t, err)
+
+	reservation, err := engine.LookupReservation(ctx, 1234)
+	require.NoError(t, err)
+	assert.Equal(t, store.ReservationStatusUsed, reservation.Status)
+}
@@ -4,6 +4,7 @@ package ocpp201
 
 import (
 	"context"
+	"github.com/thoughtworks/maeve-csms/manager/handlers"
 	"github.com/thoughtworks/maeve-csms/manager/ocpp"
 	"github.com/thoughtworks/maeve-csms/manager/ocpp/ocpp201"
 	"github.com/thoughtworks/maeve-csms/manager/store"
@@ -11,8 +12,12 @@ import (
 	"go.opentelemetry.io/otel/trace"
 )
 
+// TriggerMessageResultHandler records the result of a queued trigger message. An accepted
+// trigger message is removed from the queue and, if a SyncNotifier is provided, it is told
+// so that the next trigger message in the queue can be sent.
 type TriggerMessageResultHandler struct {
-	Store store.Engine
+	Store        store.Engine
+	SyncNotifier handlers.SyncNotifier
 }
 
 func (i TriggerMessageResultHandler) HandleCallResult(ctx context.Context, chargeStationId string, request ocpp.Request, response ocpp.Response, state any) error {
@@ -21,33 +26,33 @@ func (i TriggerMessageResultHandler) HandleCallResult(ctx context.Context, charg
 	status := ocpp201.TriggerMessageStatusEnumTypeNotImplemented
 
 	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(attribute.String("trigger_message.trigger", string(req.RequestedMessage)))
+	if req.Evse != nil {
+		span.SetAttributes(attribute.Int("trigger_message.evse_id", req.Evse.Id))
+		if req.Evse.ConnectorId != nil {
+			span.SetAttributes(attribute.Int("trigger_message.connector_id", *req.Evse.ConnectorId))
+		}
+	}
+
 	if response != nil {
 		resp := response.(*ocpp201.TriggerMessageResponseJson)
 
-		span.SetAttributes(
-			attribute.String("trigger_message.trigger", string(req.RequestedMessage)),
-			attribute.String("trigger_message.status", string(resp.Status)))
+		span.SetAttributes(attribute.String("trigger_message.status", string(resp.Status)))
 
-		if resp.Status == ocpp201.TriggerMessageStatusEnumTypeAccepted {
-			status = resp.Status
-		}
+		status = resp.Status
 	} else {
-		span.SetAttributes(
-			attribute.String("trigger_message.trigger", string(req.RequestedMessage)))
-
 		status = ocpp201.TriggerMessageStatusEnumTypeAccepted
 	}
 
-	if status == ocpp201.TriggerMessageStatusEnumTypeAccepted {
-		return i.Store.DeleteChargeStationTriggerMessage(ctx, chargeStationId)
-	} else {
-		err := i.Store.SetChargeStationTriggerMessage(ctx, chargeStationId, &store.ChargeStationTriggerMessage{
-			TriggerMessage: store.TriggerMessage(req.RequestedMessage),
-			TriggerStatus:  store.TriggerStatus(status),
-		})
-		if err != nil {
-			return err
-		}
+	triggerMessage := &store.ChargeStationTriggerMessage{
+		TriggerMessage: store.TriggerMessage(req.RequestedMessage),
+		TriggerStatus:  store.TriggerStatus(status),
 	}
-	return nil
+	if req.Evse != nil {
+		evseId := req.Evse.Id
+		triggerMessage.EvseId = &evseId
+		triggerMessage.ConnectorId = req.Evse.ConnectorId
+	}
+
+	return handlers.RecordTriggerMessageResult(ctx, i.Store, i.SyncNotifier, chargeStationId, triggerMessage)
 }
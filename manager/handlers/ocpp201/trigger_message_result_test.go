@@ -0,0 +1,71 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpp201_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	handlers201 "github.com/thoughtworks/maeve-csms/manager/handlers/ocpp201"
+	"github.com/thoughtworks/maeve-csms/manager/ocpp/ocpp201"
+	"github.com/thoughtworks/maeve-csms/manager/store"
+	"github.com/thoughtworks/maeve-csms/manager/store/inmemory"
+	"k8s.io/utils/clock"
+	"testing"
+)
+
+type recordingSyncNotifier struct {
+	pending []string
+}
+
+func (r *recordingSyncNotifier) NotifyPending(_ context.Context, chargeStationId string) {
+	r.pending = append(r.pending, chargeStationId)
+}
+
+func (r *recordingSyncNotifier) NotifyConnected(context.Context, string) {}
+
+func TestTriggerMessageResultHandler(t *testing.T) {
+	ctx := context.Background()
+	engine := inmemory.NewStore(clock.RealClock{})
+	notifier := &recordingSyncNotifier{}
+	handler := handlers201.TriggerMessageResultHandler{Store: engine, SyncNotifier: notifier}
+
+	evseId, connectorId := 1, 1
+	err := engine.UpdateChargeStationTriggerMessages(ctx, "cs001", &store.ChargeStationTriggerMessages{
+		TriggerMessages: []*store.ChargeStationTriggerMessage{
+			{TriggerMessage: store.TriggerMessageStatusNotification, EvseId: &evseId, ConnectorId: &connectorId, TriggerStatus: store.TriggerStatusPending, Attempts: 1},
+			{TriggerMessage: store.TriggerMessageMeterValues, EvseId: &evseId, TriggerStatus: store.TriggerStatusPending},
+			{TriggerMessage: store.TriggerMessageHeartbeat, TriggerStatus: store.TriggerStatusPending},
+		},
+	})
+	require.NoError(t, err)
+
+	req := &ocpp201.TriggerMessageRequestJson{
+		RequestedMessage: ocpp201.MessageTriggerEnumTypeStatusNotification,
+		Evse:             &ocpp201.EVSEType{Id: evseId, ConnectorId: &connectorId},
+	}
+	resp := &ocpp201.TriggerMessageResponseJson{
+		Status: ocpp201.TriggerMessageStatusEnumTypeAccepted,
+	}
+	err = handler.HandleCallResult(ctx, "cs001", req, resp, nil)
+	require.NoError(t, err)
+
+	req = &ocpp201.TriggerMessageRequestJson{
+		RequestedMessage: ocpp201.MessageTriggerEnumTypeMeterValues,
+		Evse:             &ocpp201.EVSEType{Id: evseId},
+	}
+	resp = &ocpp201.TriggerMessageResponseJson{
+		Status: ocpp201.TriggerMessageStatusEnumTypeRejected,
+	}
+	err = handler.HandleCallResult(ctx, "cs001", req, resp, nil)
+	require.NoError(t, err)
+
+	triggerMessages, err := engine.LookupChargeStationTriggerMessages(ctx, "cs001")
+	require.NoError(t, err)
+	require.Len(t, triggerMessages.TriggerMessages, 2)
+	assert.Equal(t, store.TriggerMessageMeterValues, triggerMessages.TriggerMessages[0].TriggerMessage)
+	assert.Equal(t, store.TriggerStatusRejected, triggerMessages.TriggerMessages[0].TriggerStatus)
+	assert.Equal(t, store.TriggerMessageHeartbeat, triggerMessages.TriggerMessages[1].TriggerMessage)
+	assert.Equal(t, store.TriggerStatusPending, triggerMessages.TriggerMessages[1].TriggerStatus)
+	assert.Equal(t, []string{"cs001", "cs001"}, notifier.pending)
+}
@@ -0,0 +1,35 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpp21
+
+import (
+	"context"
+	"github.com/thoughtworks/maeve-csms/manager/handlers"
+	"github.com/thoughtworks/maeve-csms/manager/ocpp"
+	types201 "github.com/thoughtworks/maeve-csms/manager/ocpp/ocpp201"
+	types "github.com/thoughtworks/maeve-csms/manager/ocpp/ocpp21"
+)
+
+// AuthorizeHandler handles an OCPP 2.1 Authorize using the OCPP 2.0.1 handler: the
+// OCPP 2.1 IdTokenType allows types that are not defined by OCPP 2.0.1.
+type AuthorizeHandler struct {
+	Handler201 handlers.CallHandler
+}
+
+func (a AuthorizeHandler) HandleCall(ctx context.Context, chargeStationId string, request ocpp.Request) (ocpp.Response, error) {
+	req201, err := convert[types201.AuthorizeRequestJson](request.(*types.AuthorizeRequestJson))
+	if err != nil {
+		return nil, err
+	}
+
+	res201, err := a.Handler201.HandleCall(ctx, chargeStationId, req201)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := convert[types.AuthorizeResponseJson](res201)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
@@ -0,0 +1,51 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpp21_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thoughtworks/maeve-csms/manager/handlers"
+	"github.com/thoughtworks/maeve-csms/manager/handlers/ocpp21"
+	"github.com/thoughtworks/maeve-csms/manager/ocpp"
+	types201 "github.com/thoughtworks/maeve-csms/manager/ocpp/ocpp201"
+	types "github.com/thoughtworks/maeve-csms/manager/ocpp/ocpp21"
+	"testing"
+)
+
+func TestAuthorizeHandlerUsesOcpp201Handler(t *testing.T) {
+	var got *types201.AuthorizeRequestJson
+	handler := ocpp21.AuthorizeHandler{
+		Handler201: handlers.CallHandlerFunc(func(ctx context.Context, chargeStationId string, request ocpp.Request) (ocpp.Response, error) {
+			got = request.(*types201.AuthorizeRequestJson)
+			return &types201.AuthorizeResponseJson{
+				IdTokenInfo: types201.IdTokenInfoType{
+					Status: types201.AuthorizationStatusEnumTypeAccepted,
+				},
+			}, nil
+		}),
+	}
+
+	req := &types.AuthorizeRequestJson{
+		IdToken: types.IdTokenType{
+			IdToken: "DEADBEEF",
+			Type:    "DirectPayment",
+		},
+	}
+
+	resp, err := handler.HandleCall(context.Background(), "cs001", req)
+	require.NoError(t, err)
+
+	assert.Equal(t, &types201.AuthorizeRequestJson{
+		IdToken: types201.IdTokenType{
+			IdToken: "DEADBEEF",
+			Type:    "DirectPayment",
+		},
+	}, got)
+	assert.Equal(t, &types.AuthorizeResponseJson{
+		IdTokenInfo: types.IdTokenInfoType{
+			Status: types.AuthorizationStatusEnumTypeAccepted,
+		},
+	}, resp)
+}
@@ -0,0 +1,24 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpp21
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// convert copies an OCPP 2.1 message to the equivalent OCPP 2.0.1 message (or vice versa)
+// using the JSON representation of the message. Fields that are only present in the source
+// message are dropped and fields that are only present in the target message are left unset.
+func convert[T any](from any) (*T, error) {
+	b, err := json.Marshal(from)
+	if err != nil {
+		return nil, fmt.Errorf("marshalling %T: %w", from, err)
+	}
+	to := new(T)
+	err = json.Unmarshal(b, to)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshalling %T: %w", to, err)
+	}
+	return to, nil
+}
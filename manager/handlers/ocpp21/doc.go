@@ -0,0 +1,7 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package ocpp21 defines handlers for processing OCPP 2.1 messages. Most OCPP 2.1
+// messages are handled by the OCPP 2.0.1 handlers: those that are unchanged are
+// routed to them directly and those that have changed are converted to (and from)
+// their OCPP 2.0.1 equivalent.
+package ocpp21
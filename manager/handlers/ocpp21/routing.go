@@ -0,0 +1,353 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpp21
+
+import (
+	"github.com/thoughtworks/maeve-csms/manager/handlers"
+	handlers201 "github.com/thoughtworks/maeve-csms/manager/handlers/ocpp201"
+	"github.com/thoughtworks/maeve-csms/manager/ocpi"
+	"github.com/thoughtworks/maeve-csms/manager/ocpp"
+	"github.com/thoughtworks/maeve-csms/manager/ocpp/ocpp201"
+	"github.com/thoughtworks/maeve-csms/manager/ocpp/ocpp21"
+	"github.com/thoughtworks/maeve-csms/manager/services"
+	"github.com/thoughtworks/maeve-csms/manager/store"
+	"github.com/thoughtworks/maeve-csms/manager/transport"
+	"io/fs"
+	"k8s.io/utils/clock"
+	"reflect"
+	"time"
+)
+
+// NewRouter creates a router for the OCPP 2.1 core provisioning, authorization, transaction,
+// certificate management, smart charging and reservation messages. Messages that are unchanged
+// from OCPP 2.0.1 use the OCPP 2.0.1 types and handlers.
+//
+// The OCPP 2.1 schemas for the certificate management, smart charging and reservation messages
+// are not yet included: the CSMS sends the OCPP 2.0.1 form of these messages (which is valid
+// OCPP 2.1) and the messages are validated using the OCPP 2.0.1 schemas.
+func NewRouter(emitter transport.Emitter,
+	clk clock.PassiveClock,
+	engine store.Engine,
+	tariffService services.TariffService,
+	certValidationService services.CertificateValidationService,
+	ocpiApi ocpi.Api,
+	heartbeatInterval time.Duration,
+	syncNotifier handlers.SyncNotifier,
+	schemaFS fs.FS) transport.MessageHandler {
+
+	return &handlers.Router{
+		Emitter:        emitter,
+		SchemaFS:       schemaFS,
+		OcppVersion:    transport.OcppVersion21,
+		Clock:          clk,
+		OperationStore: engine,
+		CallRoutes: map[string]handlers.CallRoute{
+			"Authorize": {
+				NewRequest:     func() ocpp.Request { return new(ocpp21.AuthorizeRequestJson) },
+				RequestSchema:  "ocpp21/AuthorizeRequest.json",
+				ResponseSchema: "ocpp21/AuthorizeResponse.json",
+				Handler: AuthorizeHandler{
+					Handler201: handlers201.AuthorizeHandler{
+						// PENDING: inject token auth service
+						TokenAuthService: &services.OcppTokenAuthService{
+							Clock:      clk,
+							TokenStore: engine,
+						},
+						CertificateValidationService: certValidationService,
+					},
+				},
+			},
+			"BootNotification": {
+				NewRequest:     func() ocpp.Request { return new(ocpp201.BootNotificationRequestJson) },
+				RequestSchema:  "ocpp21/BootNotificationRequest.json",
+				ResponseSchema: "ocpp21/BootNotificationResponse.json",
+				Handler: handlers201.BootNotificationHandler{
+					Clock:               clk,
+					DetailsStore:        engine,
+					HeartbeatInterval:   int(heartbeatInterval.Seconds()),
+					RuntimeDetailsStore: engine,
+					OcppVersion:         "2.1",
+					SyncNotifier:        syncNotifier,
+				},
+			},
+			"Heartbeat": {
+				NewRequest:     func() ocpp.Request { return new(ocpp201.HeartbeatRequestJson) },
+				RequestSchema:  "ocpp21/HeartbeatRequest.json",
+				ResponseSchema: "ocpp21/HeartbeatResponse.json",
+				Handler: handlers201.HeartbeatHandler{
+					Clock:        clk,
+					DetailsStore: engine,
+				},
+			},
+			"MeterValues": {
+				NewRequest:     func() ocpp.Request { return new(ocpp201.MeterValuesRequestJson) },
+				RequestSchema:  "ocpp21/MeterValuesRequest.json",
+				ResponseSchema: "ocpp21/MeterValuesResponse.json",
+				Handler:        handlers201.MeterValuesHandler{},
+			},
+			"NotifyReport": {
+				NewRequest:     func() ocpp.Request { return new(ocpp201.NotifyReportRequestJson) },
+				RequestSchema:  "ocpp21/NotifyReportRequest.json",
+				ResponseSchema: "ocpp21/NotifyReportResponse.json",
+				Handler:        handlers201.NotifyReportHandler{},
+			},
+			"StatusNotification": {
+				NewRequest:     func() ocpp.Request { return new(ocpp201.StatusNotificationRequestJson) },
+				RequestSchema:  "ocpp21/StatusNotificationRequest.json",
+				ResponseSchema: "ocpp21/StatusNotificationResponse.json",
+				Handler:        handlers.CallHandlerFunc(handlers201.StatusNotificationHandler),
+			},
+			"TransactionEvent": {
+				NewRequest:     func() ocpp.Request { return new(ocpp21.TransactionEventRequestJson) },
+				RequestSchema:  "ocpp21/TransactionEventRequest.json",
+				ResponseSchema: "ocpp21/TransactionEventResponse.json",
+				Handler: TransactionEventHandler{
+					Handler201: handlers201.TransactionEventHandler{
+						Store: engine,
+						TokenAuthService: &services.OcppTokenAuthService{
+							Clock:      clk,
+							TokenStore: engine,
+						},
+						TariffService: tariffService,
+					},
+				},
+			},
+		},
+		CallResultRoutes: map[string]handlers.CallResultRoute{
+			"CancelReservation": {
+				NewRequest:     func() ocpp.Request { return new(ocpp201.CancelReservationRequestJson) },
+				NewResponse:    func() ocpp.Response { return new(ocpp201.CancelReservationResponseJson) },
+				RequestSchema:  "ocpp201/CancelReservationRequest.json",
+				ResponseSchema: "ocpp201/CancelReservationResponse.json",
+				Handler: handlers201.CancelReservationResultHandler{
+					Store:   engine,
+					OcpiApi: ocpiApi,
+				},
+			},
+			"CertificateSigned": {
+				NewRequest:     func() ocpp.Request { return new(ocpp201.CertificateSignedRequestJson) },
+				NewResponse:    func() ocpp.Response { return new(ocpp201.CertificateSignedResponseJson) },
+				RequestSchema:  "ocpp201/CertificateSignedRequest.json",
+				ResponseSchema: "ocpp201/CertificateSignedResponse.json",
+				Handler: handlers201.CertificateSignedResultHandler{
+					Store: engine,
+				},
+			},
+			"ChangeAvailability": {
+				NewRequest:     func() ocpp.Request { return new(ocpp201.ChangeAvailabilityRequestJson) },
+				NewResponse:    func() ocpp.Response { return new(ocpp201.ChangeAvailabilityResponseJson) },
+				RequestSchema:  "ocpp21/ChangeAvailabilityRequest.json",
+				ResponseSchema: "ocpp21/ChangeAvailabilityResponse.json",
+				Handler:        handlers201.ChangeAvailabilityResultHandler{},
+			},
+			"ClearCache": {
+				NewRequest:     func() ocpp.Request { return new(ocpp201.ClearCacheRequestJson) },
+				NewResponse:    func() ocpp.Response { return new(ocpp201.ClearCacheResponseJson) },
+				RequestSchema:  "ocpp21/ClearCacheRequest.json",
+				ResponseSchema: "ocpp21/ClearCacheResponse.json",
+				Handler:        handlers201.ClearCacheResultHandler{},
+			},
+			"ClearChargingProfile": {
+				NewRequest:     func() ocpp.Request { return new(ocpp201.ClearChargingProfileRequestJson) },
+				NewResponse:    func() ocpp.Response { return new(ocpp201.ClearChargingProfileResponseJson) },
+				RequestSchema:  "ocpp201/ClearChargingProfileRequest.json",
+				ResponseSchema: "ocpp201/ClearChargingProfileResponse.json",
+				Handler: handlers201.ClearChargingProfileResultHandler{
+					OcpiApi: ocpiApi,
+				},
+			},
+			"DeleteCertificate": {
+				NewRequest:     func() ocpp.Request { return new(ocpp201.DeleteCertificateRequestJson) },
+				NewResponse:    func() ocpp.Response { return new(ocpp201.DeleteCertificateResponseJson) },
+				RequestSchema:  "ocpp201/DeleteCertificateRequest.json",
+				ResponseSchema: "ocpp201/DeleteCertificateResponse.json",
+				Handler: handlers201.DeleteCertificateResultHandler{
+					Store: engine,
+				},
+			},
+			"GetBaseReport": {
+				NewRequest:     func() ocpp.Request { return new(ocpp201.GetBaseReportRequestJson) },
+				NewResponse:    func() ocpp.Response { return new(ocpp201.GetBaseReportResponseJson) },
+				RequestSchema:  "ocpp21/GetBaseReportRequest.json",
+				ResponseSchema: "ocpp21/GetBaseReportResponse.json",
+				Handler:        handlers201.GetBaseReportResultHandler{},
+			},
+			"GetCompositeSchedule": {
+				NewRequest:     func() ocpp.Request { return new(ocpp201.GetCompositeScheduleRequestJson) },
+				NewResponse:    func() ocpp.Response { return new(ocpp201.GetCompositeScheduleResponseJson) },
+				RequestSchema:  "ocpp201/GetCompositeScheduleRequest.json",
+				ResponseSchema: "ocpp201/GetCompositeScheduleResponse.json",
+				Handler: handlers201.GetCompositeScheduleResultHandler{
+					OcpiApi: ocpiApi,
+				},
+			},
+			"GetInstalledCertificateIds": {
+				NewRequest:     func() ocpp.Request { return new(ocpp201.GetInstalledCertificateIdsRequestJson) },
+				NewResponse:    func() ocpp.Response { return new(ocpp201.GetInstalledCertificateIdsResponseJson) },
+				RequestSchema:  "ocpp201/GetInstalledCertificateIdsRequest.json",
+				ResponseSchema: "ocpp201/GetInstalledCertificateIdsResponse.json",
+				Handler: handlers201.GetInstalledCertificateIdsResultHandler{
+					Store: engine,
+					Clock: clk,
+				},
+			},
+			"GetLocalListVersion": {
+				NewRequest:     func() ocpp.Request { return new(ocpp201.GetLocalListVersionRequestJson) },
+				NewResponse:    func() ocpp.Response { return new(ocpp201.GetLocalListVersionResponseJson) },
+				RequestSchema:  "ocpp21/GetLocalListVersionRequest.json",
+				ResponseSchema: "ocpp21/GetLocalListVersionResponse.json",
+				Handler:        handlers201.GetLocalListVersionResultHandler{},
+			},
+			"GetReport": {
+				NewRequest:     func() ocpp.Request { return new(ocpp201.GetReportRequestJson) },
+				NewResponse:    func() ocpp.Response { return new(ocpp201.GetReportResponseJson) },
+				RequestSchema:  "ocpp21/GetReportRequest.json",
+				ResponseSchema: "ocpp21/GetReportResponse.json",
+				Handler:        handlers201.GetReportResultHandler{},
+			},
+			"GetTransactionStatus": {
+				NewRequest:     func() ocpp.Request { return new(ocpp201.GetTransactionStatusRequestJson) },
+				NewResponse:    func() ocpp.Response { return new(ocpp201.GetTransactionStatusResponseJson) },
+				RequestSchema:  "ocpp21/GetTransactionStatusRequest.json",
+				ResponseSchema: "ocpp21/GetTransactionStatusResponse.json",
+				Handler:        handlers201.GetTransactionStatusResultHandler{},
+			},
+			"GetVariables": {
+				NewRequest:     func() ocpp.Request { return new(ocpp201.GetVariablesRequestJson) },
+				NewResponse:    func() ocpp.Response { return new(ocpp201.GetVariablesResponseJson) },
+				RequestSchema:  "ocpp21/GetVariablesRequest.json",
+				ResponseSchema: "ocpp21/GetVariablesResponse.json",
+				Handler:        handlers201.GetVariablesResultHandler{},
+			},
+			"InstallCertificate": {
+				NewRequest:     func() ocpp.Request { return new(ocpp201.InstallCertificateRequestJson) },
+				NewResponse:    func() ocpp.Response { return new(ocpp201.InstallCertificateResponseJson) },
+				RequestSchema:  "ocpp201/InstallCertificateRequest.json",
+				ResponseSchema: "ocpp201/InstallCertificateResponse.json",
+				Handler: handlers201.InstallCertificateResultHandler{
+					Store: engine,
+					Clock: clk,
+				},
+			},
+			"RequestStartTransaction": {
+				NewRequest:     func() ocpp.Request { return new(ocpp21.RequestStartTransactionRequestJson) },
+				NewResponse:    func() ocpp.Response { return new(ocpp201.RequestStartTransactionResponseJson) },
+				RequestSchema:  "ocpp21/RequestStartTransactionRequest.json",
+				ResponseSchema: "ocpp21/RequestStartTransactionResponse.json",
+				Handler: RequestStartTransactionResultHandler{
+					Handler201: handlers201.RequestStartTransactionResultHandler{},
+				},
+			},
+			"RequestStopTransaction": {
+				NewRequest:     func() ocpp.Request { return new(ocpp201.RequestStopTransactionRequestJson) },
+				NewResponse:    func() ocpp.Response { return new(ocpp201.RequestStopTransactionResponseJson) },
+				RequestSchema:  "ocpp21/RequestStopTransactionRequest.json",
+				ResponseSchema: "ocpp21/RequestStopTransactionResponse.json",
+				Handler:        handlers201.RequestStopTransactionResultHandler{},
+			},
+			"ReserveNow": {
+				NewRequest:     func() ocpp.Request { return new(ocpp201.ReserveNowRequestJson) },
+				NewResponse:    func() ocpp.Response { return new(ocpp201.ReserveNowResponseJson) },
+				RequestSchema:  "ocpp201/ReserveNowRequest.json",
+				ResponseSchema: "ocpp201/ReserveNowResponse.json",
+				Handler: handlers201.ReserveNowResultHandler{
+					Store:   engine,
+					OcpiApi: ocpiApi,
+				},
+			},
+			"Reset": {
+				NewRequest:     func() ocpp.Request { return new(ocpp201.ResetRequestJson) },
+				NewResponse:    func() ocpp.Response { return new(ocpp201.ResetResponseJson) },
+				RequestSchema:  "ocpp21/ResetRequest.json",
+				ResponseSchema: "ocpp21/ResetResponse.json",
+				Handler:        handlers201.ResetResultHandler{},
+			},
+			"SendLocalList": {
+				NewRequest:     func() ocpp.Request { return new(ocpp21.SendLocalListRequestJson) },
+				NewResponse:    func() ocpp.Response { return new(ocpp201.SendLocalListResponseJson) },
+				RequestSchema:  "ocpp21/SendLocalListRequest.json",
+				ResponseSchema: "ocpp21/SendLocalListResponse.json",
+				Handler: SendLocalListResultHandler{
+					Handler201: handlers201.SendLocalListResultHandler{},
+				},
+			},
+			"SetChargingProfile": {
+				NewRequest:     func() ocpp.Request { return new(ocpp201.SetChargingProfileRequestJson) },
+				NewResponse:    func() ocpp.Response { return new(ocpp201.SetChargingProfileResponseJson) },
+				RequestSchema:  "ocpp201/SetChargingProfileRequest.json",
+				ResponseSchema: "ocpp201/SetChargingProfileResponse.json",
+				Handler: handlers201.SetChargingProfileResultHandler{
+					OcpiApi: ocpiApi,
+				},
+			},
+			"SetNetworkProfile": {
+				NewRequest:     func() ocpp.Request { return new(ocpp201.SetNetworkProfileRequestJson) },
+				NewResponse:    func() ocpp.Response { return new(ocpp201.SetNetworkProfileResponseJson) },
+				RequestSchema:  "ocpp21/SetNetworkProfileRequest.json",
+				ResponseSchema: "ocpp21/SetNetworkProfileResponse.json",
+				Handler:        handlers201.SetNetworkProfileResultHandler{},
+			},
+			"SetVariables": {
+				NewRequest:     func() ocpp.Request { return new(ocpp201.SetVariablesRequestJson) },
+				NewResponse:    func() ocpp.Response { return new(ocpp201.SetVariablesResponseJson) },
+				RequestSchema:  "ocpp21/SetVariablesRequest.json",
+				ResponseSchema: "ocpp21/SetVariablesResponse.json",
+				Handler: handlers201.SetVariablesResultHandler{
+					Store: engine,
+				},
+			},
+			"TriggerMessage": {
+				NewRequest:     func() ocpp.Request { return new(ocpp21.TriggerMessageRequestJson) },
+				NewResponse:    func() ocpp.Response { return new(ocpp201.TriggerMessageResponseJson) },
+				RequestSchema:  "ocpp21/TriggerMessageRequest.json",
+				ResponseSchema: "ocpp21/TriggerMessageResponse.json",
+				Handler: TriggerMessageResultHandler{
+					Handler201: handlers201.TriggerMessageResultHandler{
+						Store:        engine,
+						SyncNotifier: syncNotifier,
+					},
+				},
+			},
+			"UnlockConnector": {
+				NewRequest:     func() ocpp.Request { return new(ocpp201.UnlockConnectorRequestJson) },
+				NewResponse:    func() ocpp.Response { return new(ocpp201.UnlockConnectorResponseJson) },
+				RequestSchema:  "ocpp21/UnlockConnectorRequest.json",
+				ResponseSchema: "ocpp21/UnlockConnectorResponse.json",
+				Handler:        handlers201.UnlockConnectorResultHandler{},
+			},
+		},
+	}
+}
+
+func NewCallMaker(e transport.Emitter) *handlers.OcppCallMaker {
+	return &handlers.OcppCallMaker{
+		Emitter:     e,
+		OcppVersion: transport.OcppVersion21,
+		Actions: map[reflect.Type]string{
+			reflect.TypeOf(&ocpp201.CancelReservationRequestJson{}):          "CancelReservation",
+			reflect.TypeOf(&ocpp201.CertificateSignedRequestJson{}):          "CertificateSigned",
+			reflect.TypeOf(&ocpp201.ChangeAvailabilityRequestJson{}):         "ChangeAvailability",
+			reflect.TypeOf(&ocpp201.ClearCacheRequestJson{}):                 "ClearCache",
+			reflect.TypeOf(&ocpp201.ClearChargingProfileRequestJson{}):       "ClearChargingProfile",
+			reflect.TypeOf(&ocpp201.DeleteCertificateRequestJson{}):          "DeleteCertificate",
+			reflect.TypeOf(&ocpp201.GetBaseReportRequestJson{}):              "GetBaseReport",
+			reflect.TypeOf(&ocpp201.GetCompositeScheduleRequestJson{}):       "GetCompositeSchedule",
+			reflect.TypeOf(&ocpp201.GetInstalledCertificateIdsRequestJson{}): "GetInstalledCertificateIds",
+			reflect.TypeOf(&ocpp201.GetLocalListVersionRequestJson{}):        "GetLocalListVersion",
+			reflect.TypeOf(&ocpp201.GetReportRequestJson{}):                  "GetReport",
+			reflect.TypeOf(&ocpp201.GetTransactionStatusRequestJson{}):       "GetTransactionStatus",
+			reflect.TypeOf(&ocpp201.GetVariablesRequestJson{}):               "GetVariables",
+			reflect.TypeOf(&ocpp201.InstallCertificateRequestJson{}):         "InstallCertificate",
+			reflect.TypeOf(&ocpp21.RequestStartTransactionRequestJson{}):     "RequestStartTransaction",
+			reflect.TypeOf(&ocpp201.RequestStopTransactionRequestJson{}):     "RequestStopTransaction",
+			reflect.TypeOf(&ocpp201.ReserveNowRequestJson{}):                 "ReserveNow",
+			reflect.TypeOf(&ocpp201.ResetRequestJson{}):                      "Reset",
+			reflect.TypeOf(&ocpp21.SendLocalListRequestJson{}):               "SendLocalList",
+			reflect.TypeOf(&ocpp201.SetChargingProfileRequestJson{}):         "SetChargingProfile",
+			reflect.TypeOf(&ocpp201.SetNetworkProfileRequestJson{}):          "SetNetworkProfile",
+			reflect.TypeOf(&ocpp201.SetVariablesRequestJson{}):               "SetVariables",
+			reflect.TypeOf(&ocpp21.TriggerMessageRequestJson{}):              "TriggerMessage",
+			reflect.TypeOf(&ocpp201.UnlockConnectorRequestJson{}):            "UnlockConnector",
+		},
+	}
+}
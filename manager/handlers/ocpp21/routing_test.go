@@ -0,0 +1,556 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpp21_test
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/pem"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thoughtworks/maeve-csms/manager/handlers/ocpp21"
+	"github.com/thoughtworks/maeve-csms/manager/ocpp"
+	types201 "github.com/thoughtworks/maeve-csms/manager/ocpp/ocpp201"
+	types "github.com/thoughtworks/maeve-csms/manager/ocpp/ocpp21"
+	"github.com/thoughtworks/maeve-csms/manager/schemas"
+	"github.com/thoughtworks/maeve-csms/manager/store"
+	"github.com/thoughtworks/maeve-csms/manager/store/inmemory"
+	"github.com/thoughtworks/maeve-csms/manager/testutil"
+	"github.com/thoughtworks/maeve-csms/manager/transport"
+	"go.opentelemetry.io/otel/codes"
+	clockTest "k8s.io/utils/clock/testing"
+	"testing"
+	"time"
+)
+
+type fakeEmitter struct {
+	Called          bool
+	OcppVersion     transport.OcppVersion
+	ChargeStationId string
+	Message         *transport.Message
+}
+
+func (f *fakeEmitter) Emit(ctx context.Context, ocppVersion transport.OcppVersion, chargeStationId string, message *transport.Message) error {
+	f.Called = true
+	f.OcppVersion = ocppVersion
+	f.ChargeStationId = chargeStationId
+	f.Message = message
+	return nil
+}
+
+type fakeTariffService struct{}
+
+func (f fakeTariffService) CalculateCost(transaction *store.Transaction) (float64, error) {
+	return 42.0, nil
+}
+
+type fakeCertValidationService struct{}
+
+func (f fakeCertValidationService) ValidatePEMCertificateChain(ctx context.Context, pemChain []byte, eMAID string) (*string, error) {
+	return nil, nil
+}
+
+func (f fakeCertValidationService) ValidateHashedCertificateChain(ctx context.Context, ocspRequestData []types201.OCSPRequestDataType) (*string, error) {
+	return nil, nil
+}
+
+func makePtr[T any](t T) *T {
+	p := t
+	return &p
+}
+
+func TestRoutingCalls(t *testing.T) {
+	now, err := time.Parse(time.RFC3339, "2023-06-15T15:05:00+01:00")
+	require.NoError(t, err)
+	clock := clockTest.NewFakePassiveClock(now)
+
+	engine := inmemory.NewStore(clock)
+
+	emitter := &fakeEmitter{}
+	router := ocpp21.NewRouter(emitter,
+		clock,
+		engine,
+		&fakeTariffService{},
+		&fakeCertValidationService{},
+		nil,
+		5*time.Minute,
+		nil,
+		schemas.OcppSchemas,
+	)
+
+	inputMessages := map[string]ocpp.Request{
+		"Authorize": &types.AuthorizeRequestJson{
+			IdToken: types.IdTokenType{
+				Type:    "ISO14443",
+				IdToken: "DEADBEEF",
+			},
+		},
+		"BootNotification": &types201.BootNotificationRequestJson{
+			ChargingStation: types201.ChargingStationType{
+				Model:        "Powergen",
+				SerialNumber: makePtr("012345ABCDEF"),
+				VendorName:   "Vendor",
+			},
+			Reason: types201.BootReasonEnumTypePowerUp,
+		},
+		"Heartbeat": &types201.HeartbeatRequestJson{},
+		"MeterValues": &types201.MeterValuesRequestJson{
+			EvseId: 1,
+			MeterValue: []types201.MeterValueType{
+				{
+					SampledValue: []types201.SampledValueType{
+						{
+							Location:  makePtr(types201.LocationEnumTypeOutlet),
+							Measurand: makePtr(types201.MeasurandEnumTypeCurrentExport),
+							Value:     12,
+						},
+					},
+					Timestamp: "2023-06-15T15:05:00+01:00",
+				},
+			},
+		},
+		"NotifyReport": &types201.NotifyReportRequestJson{
+			GeneratedAt: "2024-03-18T17:50:00.000Z",
+			RequestId:   33,
+			SeqNo:       1,
+			Tbc:         false,
+		},
+		"StatusNotification": &types201.StatusNotificationRequestJson{
+			ConnectorId:     1,
+			ConnectorStatus: types201.ConnectorStatusEnumTypeAvailable,
+			EvseId:          1,
+			Timestamp:       "2023-06-15T15:05:00+01:00",
+		},
+		"TransactionEvent": &types.TransactionEventRequestJson{
+			EventType: types.TransactionEventEnumTypeStarted,
+			Evse: &types.EVSEType{
+				Id:          1,
+				ConnectorId: makePtr(1),
+			},
+			IdToken: &types.IdTokenType{
+				IdToken: "DEADBEEF",
+				Type:    "ISO14443",
+			},
+			MeterValue: []types.MeterValueType{
+				{
+					Timestamp: "2023-06-15T15:05:00+01:00",
+					SampledValue: []types.SampledValueType{
+						{
+							Location:  makePtr(types.LocationEnumTypeOutlet),
+							Measurand: makePtr(types.MeasurandEnumTypeCurrentExport),
+							Value:     24,
+						},
+					},
+				},
+			},
+			EvseSleep:          makePtr(false),
+			NumberOfPhasesUsed: makePtr(3),
+			Offline:            false,
+			SeqNo:              1,
+			Timestamp:          "2023-06-15T15:05:00+01:00",
+			TransactionInfo: types.TransactionType{
+				TransactionId: "abc123",
+				OperationMode: makePtr(types.OperationModeEnumTypeChargingOnly),
+			},
+			TriggerReason: types.TriggerReasonEnumTypeTariffChanged,
+		},
+	}
+
+	for action, req := range inputMessages {
+		t.Run(action, func(t *testing.T) {
+			reqBytes, err := json.Marshal(req)
+			require.NoError(t, err)
+
+			messageId, err := uuid.NewUUID()
+			require.NoError(t, err)
+
+			msg := transport.Message{
+				MessageType:    transport.MessageTypeCall,
+				Action:         action,
+				MessageId:      messageId.String(),
+				RequestPayload: reqBytes,
+			}
+
+			tracer, exporter := testutil.GetTracer()
+
+			func() {
+				ctx, span := tracer.Start(context.TODO(), "test")
+				defer span.End()
+				router.Handle(ctx, "cs001", &msg)
+			}()
+
+			require.Greater(t, len(exporter.GetSpans()), 0)
+			assert.Equal(t, codes.Ok, exporter.GetSpans()[0].Status.Code)
+			assert.Equal(t, transport.OcppVersion21, emitter.OcppVersion)
+			assert.Equal(t, transport.MessageTypeCallResult, emitter.Message.MessageType)
+		})
+	}
+}
+
+func TestRoutingCallResults(t *testing.T) {
+	now, err := time.Parse(time.RFC3339, "2023-06-15T15:05:00+01:00")
+	require.NoError(t, err)
+	clock := clockTest.NewFakePassiveClock(now)
+
+	engine := inmemory.NewStore(clock)
+
+	router := ocpp21.NewRouter(&fakeEmitter{},
+		clock,
+		engine,
+		&fakeTariffService{},
+		&fakeCertValidationService{},
+		nil,
+		5*time.Minute,
+		nil,
+		schemas.OcppSchemas,
+	)
+
+	pemBytes := pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: []byte("test"),
+	})
+
+	inputMessages := map[string]struct {
+		request  ocpp.Request
+		response ocpp.Response
+	}{
+		"CancelReservation": {
+			request: &types201.CancelReservationRequestJson{
+				ReservationId: 1234,
+			},
+			response: &types201.CancelReservationResponseJson{
+				Status: types201.CancelReservationStatusEnumTypeAccepted,
+			},
+		},
+		"CertificateSigned": {
+			request: &types201.CertificateSignedRequestJson{
+				CertificateChain: string(pemBytes),
+				CertificateType:  makePtr(types201.CertificateSigningUseEnumTypeV2GCertificate),
+			},
+			response: &types201.CertificateSignedResponseJson{
+				Status: types201.CertificateSignedStatusEnumTypeRejected,
+			},
+		},
+		"ChangeAvailability": {
+			request: &types201.ChangeAvailabilityRequestJson{
+				OperationalStatus: types201.OperationalStatusEnumTypeOperative,
+			},
+			response: &types201.ChangeAvailabilityResponseJson{
+				Status: types201.ChangeAvailabilityStatusEnumTypeAccepted,
+			},
+		},
+		"ClearCache": {
+			request: &types201.ClearCacheRequestJson{},
+			response: &types201.ClearCacheResponseJson{
+				Status: types201.ClearCacheStatusEnumTypeAccepted,
+			},
+		},
+		"ClearChargingProfile": {
+			request: &types201.ClearChargingProfileRequestJson{
+				ChargingProfileCriteria: &types201.ClearChargingProfileType{
+					EvseId:                 makePtr(1),
+					ChargingProfilePurpose: makePtr(types201.ChargingProfilePurposeEnumTypeTxProfile),
+				},
+			},
+			response: &types201.ClearChargingProfileResponseJson{
+				Status: types201.ClearChargingProfileStatusEnumTypeAccepted,
+			},
+		},
+		"DeleteCertificate": {
+			request: &types201.DeleteCertificateRequestJson{
+				CertificateHashData: types201.CertificateHashDataType{
+					HashAlgorithm:  types201.HashAlgorithmEnumTypeSHA256,
+					IssuerKeyHash:  "ABC123",
+					IssuerNameHash: "ABCDEF",
+					SerialNumber:   "1234578",
+				},
+			},
+			response: &types201.DeleteCertificateResponseJson{
+				Status: types201.DeleteCertificateStatusEnumTypeAccepted,
+			},
+		},
+		"GetCompositeSchedule": {
+			request: &types201.GetCompositeScheduleRequestJson{
+				EvseId:   1,
+				Duration: 600,
+			},
+			response: &types201.GetCompositeScheduleResponseJson{
+				Status: types201.GenericStatusEnumTypeAccepted,
+				Schedule: &types201.CompositeScheduleType{
+					EvseId:           1,
+					Duration:         600,
+					ScheduleStart:    "2023-06-15T15:05:00Z",
+					ChargingRateUnit: types201.ChargingRateUnitEnumTypeW,
+					ChargingSchedulePeriod: []types201.ChargingSchedulePeriodType{
+						{StartPeriod: 0, Limit: 7400},
+					},
+				},
+			},
+		},
+		"GetInstalledCertificateIds": {
+			request: &types201.GetInstalledCertificateIdsRequestJson{
+				CertificateType: []types201.GetCertificateIdUseEnumType{
+					types201.GetCertificateIdUseEnumTypeCSMSRootCertificate,
+				},
+			},
+			response: &types201.GetInstalledCertificateIdsResponseJson{
+				Status: types201.GetInstalledCertificateStatusEnumTypeAccepted,
+				CertificateHashDataChain: []types201.CertificateHashDataChainType{
+					{
+						CertificateHashData: types201.CertificateHashDataType{
+							HashAlgorithm:  types201.HashAlgorithmEnumTypeSHA256,
+							IssuerKeyHash:  "ABC123",
+							IssuerNameHash: "ABCDEF",
+							SerialNumber:   "12345678",
+						},
+						CertificateType: types201.GetCertificateIdUseEnumTypeCSMSRootCertificate,
+					},
+				},
+			},
+		},
+		"GetVariables": {
+			request: &types201.GetVariablesRequestJson{
+				GetVariableData: []types201.GetVariableDataType{
+					{
+						Component: types201.ComponentType{
+							Name: "SomeCtrlr",
+						},
+						Variable: types201.VariableType{
+							Name: "AnyValue",
+						},
+					},
+				},
+			},
+			response: &types201.GetVariablesResponseJson{
+				GetVariableResult: []types201.GetVariableResultType{
+					{
+						AttributeStatus: types201.GetVariableStatusEnumTypeAccepted,
+						Component: types201.ComponentType{
+							Name: "SomeCtrlr",
+						},
+						Variable: types201.VariableType{
+							Name: "AnyValue",
+						},
+					},
+				},
+			},
+		},
+		"InstallCertificate": {
+			request: &types201.InstallCertificateRequestJson{
+				Certificate:     string(pemBytes),
+				CertificateType: types201.InstallCertificateUseEnumTypeMORootCertificate,
+			},
+			response: &types201.InstallCertificateResponseJson{
+				Status: types201.InstallCertificateStatusEnumTypeAccepted,
+			},
+		},
+		"RequestStartTransaction": {
+			request: &types.RequestStartTransactionRequestJson{
+				IdToken: types.IdTokenType{
+					Type:    "DirectPayment",
+					IdToken: "DEADBEEF",
+				},
+				RemoteStartId: 12345,
+			},
+			response: &types201.RequestStartTransactionResponseJson{
+				Status: types201.RequestStartStopStatusEnumTypeAccepted,
+			},
+		},
+		"ReserveNow": {
+			request: &types201.ReserveNowRequestJson{
+				Id:             1234,
+				EvseId:         makePtr(1),
+				ExpiryDateTime: "2023-06-15T15:05:00Z",
+				IdToken: types201.IdTokenType{
+					IdToken: "DEADBEEF",
+					Type:    types201.IdTokenEnumTypeISO14443,
+				},
+			},
+			response: &types201.ReserveNowResponseJson{
+				Status: types201.ReserveNowStatusEnumTypeAccepted,
+			},
+		},
+		"SendLocalList": {
+			request: &types.SendLocalListRequestJson{
+				LocalAuthorizationList: []types.AuthorizationData{
+					{
+						IdToken: types.IdTokenType{
+							Type:    "ISO14443",
+							IdToken: "ABCD1234",
+						},
+					},
+				},
+				UpdateType:    types.UpdateEnumTypeDifferential,
+				VersionNumber: 12,
+			},
+			response: &types201.SendLocalListResponseJson{
+				Status: types201.SendLocalListStatusEnumTypeAccepted,
+			},
+		},
+		"SetChargingProfile": {
+			request: &types201.SetChargingProfileRequestJson{
+				EvseId: 1,
+				ChargingProfile: types201.ChargingProfileType{
+					Id:                     100,
+					ChargingProfileKind:    types201.ChargingProfileKindEnumTypeRelative,
+					ChargingProfilePurpose: types201.ChargingProfilePurposeEnumTypeTxProfile,
+					ChargingSchedule: []types201.ChargingScheduleType{
+						{
+							Id:               100,
+							ChargingRateUnit: types201.ChargingRateUnitEnumTypeA,
+							ChargingSchedulePeriod: []types201.ChargingSchedulePeriodType{
+								{StartPeriod: 0, Limit: 16},
+							},
+						},
+					},
+				},
+			},
+			response: &types201.SetChargingProfileResponseJson{
+				Status: types201.ChargingProfileStatusEnumTypeAccepted,
+			},
+		},
+		"TriggerMessage": {
+			request: &types.TriggerMessageRequestJson{
+				RequestedMessage: types.MessageTriggerEnumTypeCustomTrigger,
+				CustomTrigger:    makePtr("VendorStatus"),
+			},
+			response: &types201.TriggerMessageResponseJson{
+				Status: types201.TriggerMessageStatusEnumTypeAccepted,
+			},
+		},
+		"UnlockConnector": {
+			request: &types201.UnlockConnectorRequestJson{
+				EvseId:      1,
+				ConnectorId: 1,
+			},
+			response: &types201.UnlockConnectorResponseJson{
+				Status: types201.UnlockStatusEnumTypeUnlocked,
+			},
+		},
+	}
+
+	for action, input := range inputMessages {
+		t.Run(action, func(t *testing.T) {
+			reqBytes, err := json.Marshal(input.request)
+			require.NoError(t, err)
+
+			respBytes, err := json.Marshal(input.response)
+			require.NoError(t, err)
+
+			messageId, err := uuid.NewUUID()
+			require.NoError(t, err)
+
+			msg := transport.Message{
+				MessageType:     transport.MessageTypeCallResult,
+				Action:          action,
+				MessageId:       messageId.String(),
+				RequestPayload:  reqBytes,
+				ResponsePayload: respBytes,
+			}
+
+			tracer, exporter := testutil.GetTracer()
+
+			func() {
+				ctx, span := tracer.Start(context.TODO(), "test")
+				defer span.End()
+				router.Handle(ctx, "cs001", &msg)
+			}()
+
+			require.Greater(t, len(exporter.GetSpans()), 0)
+			assert.Equal(t, codes.Ok, exporter.GetSpans()[0].Status.Code)
+		})
+	}
+}
+
+func TestCallMaker(t *testing.T) {
+	emitter := &fakeEmitter{}
+	callMaker := ocpp21.NewCallMaker(emitter)
+
+	inputMessages := map[string]ocpp.Request{
+		"CancelReservation": &types201.CancelReservationRequestJson{
+			ReservationId: 1234,
+		},
+		"CertificateSigned": &types201.CertificateSignedRequestJson{
+			CertificateChain: "pem-data",
+		},
+		"ChangeAvailability": &types201.ChangeAvailabilityRequestJson{
+			OperationalStatus: types201.OperationalStatusEnumTypeInoperative,
+		},
+		"ClearCache":           &types201.ClearCacheRequestJson{},
+		"ClearChargingProfile": &types201.ClearChargingProfileRequestJson{},
+		"DeleteCertificate": &types201.DeleteCertificateRequestJson{
+			CertificateHashData: types201.CertificateHashDataType{
+				HashAlgorithm:  types201.HashAlgorithmEnumTypeSHA256,
+				IssuerKeyHash:  "ABC123",
+				IssuerNameHash: "ABCDEF",
+				SerialNumber:   "1234578",
+			},
+		},
+		"GetBaseReport": &types201.GetBaseReportRequestJson{
+			RequestId:  42,
+			ReportBase: types201.ReportBaseEnumTypeSummaryInventory,
+		},
+		"GetCompositeSchedule": &types201.GetCompositeScheduleRequestJson{
+			EvseId:   1,
+			Duration: 600,
+		},
+		"GetInstalledCertificateIds": &types201.GetInstalledCertificateIdsRequestJson{},
+		"GetLocalListVersion":        &types201.GetLocalListVersionRequestJson{},
+		"GetReport": &types201.GetReportRequestJson{
+			RequestId: 42,
+		},
+		"GetTransactionStatus": &types201.GetTransactionStatusRequestJson{
+			TransactionId: makePtr(""),
+		},
+		"GetVariables": &types201.GetVariablesRequestJson{},
+		"InstallCertificate": &types201.InstallCertificateRequestJson{
+			Certificate:     "pem-data",
+			CertificateType: types201.InstallCertificateUseEnumTypeV2GRootCertificate,
+		},
+		"RequestStartTransaction": &types.RequestStartTransactionRequestJson{
+			IdToken: types.IdTokenType{
+				Type:    "ISO14443",
+				IdToken: "DEADBEEF",
+			},
+			RemoteStartId: 12345,
+		},
+		"RequestStopTransaction": &types201.RequestStopTransactionRequestJson{
+			TransactionId: "123abcde",
+		},
+		"ReserveNow": &types201.ReserveNowRequestJson{
+			Id:             1234,
+			ExpiryDateTime: "2023-06-15T15:05:00Z",
+		},
+		"Reset": &types201.ResetRequestJson{
+			Type: types201.ResetEnumTypeImmediate,
+		},
+		"SendLocalList": &types.SendLocalListRequestJson{
+			UpdateType:    types.UpdateEnumTypeFull,
+			VersionNumber: 12,
+		},
+		"SetChargingProfile": &types201.SetChargingProfileRequestJson{
+			EvseId: 1,
+		},
+		"SetNetworkProfile": &types201.SetNetworkProfileRequestJson{},
+		"SetVariables":      &types201.SetVariablesRequestJson{},
+		"TriggerMessage": &types.TriggerMessageRequestJson{
+			RequestedMessage: types.MessageTriggerEnumTypeHeartbeat,
+		},
+		"UnlockConnector": &types201.UnlockConnectorRequestJson{
+			EvseId:      1,
+			ConnectorId: 1,
+		},
+	}
+
+	for action, req := range inputMessages {
+		t.Run(action, func(t *testing.T) {
+			err := callMaker.Send(context.TODO(), "cs001", req)
+			require.NoError(t, err)
+
+			assert.Equal(t, transport.OcppVersion21, emitter.OcppVersion)
+			assert.Equal(t, "cs001", emitter.ChargeStationId)
+			assert.Equal(t, action, emitter.Message.Action)
+		})
+	}
+}
@@ -0,0 +1,26 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpp21
+
+import (
+	"context"
+	"github.com/thoughtworks/maeve-csms/manager/handlers"
+	"github.com/thoughtworks/maeve-csms/manager/ocpp"
+	types201 "github.com/thoughtworks/maeve-csms/manager/ocpp/ocpp201"
+	types "github.com/thoughtworks/maeve-csms/manager/ocpp/ocpp21"
+)
+
+// SendLocalListResultHandler handles the result of an OCPP 2.1 SendLocalList using the OCPP 2.0.1
+// handler. The response is unchanged in OCPP 2.1 so only the request is converted.
+type SendLocalListResultHandler struct {
+	Handler201 handlers.CallResultHandler
+}
+
+func (s SendLocalListResultHandler) HandleCallResult(ctx context.Context, chargeStationId string, request ocpp.Request, response ocpp.Response, state any) error {
+	req201, err := convert[types201.SendLocalListRequestJson](request.(*types.SendLocalListRequestJson))
+	if err != nil {
+		return err
+	}
+
+	return s.Handler201.HandleCallResult(ctx, chargeStationId, req201, response, state)
+}
@@ -0,0 +1,35 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpp21
+
+import (
+	"context"
+	"github.com/thoughtworks/maeve-csms/manager/handlers"
+	"github.com/thoughtworks/maeve-csms/manager/ocpp"
+	types201 "github.com/thoughtworks/maeve-csms/manager/ocpp/ocpp201"
+	types "github.com/thoughtworks/maeve-csms/manager/ocpp/ocpp21"
+)
+
+// TransactionEventHandler handles an OCPP 2.1 TransactionEvent using the OCPP 2.0.1 handler.
+// The transaction limits, operation mode and tariff introduced by OCPP 2.1 are not yet used.
+type TransactionEventHandler struct {
+	Handler201 handlers.CallHandler
+}
+
+func (t TransactionEventHandler) HandleCall(ctx context.Context, chargeStationId string, request ocpp.Request) (ocpp.Response, error) {
+	req201, err := convert[types201.TransactionEventRequestJson](request.(*types.TransactionEventRequestJson))
+	if err != nil {
+		return nil, err
+	}
+
+	res201, err := t.Handler201.HandleCall(ctx, chargeStationId, req201)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := convert[types.TransactionEventResponseJson](res201)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
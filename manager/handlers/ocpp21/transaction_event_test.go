@@ -0,0 +1,59 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpp21_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thoughtworks/maeve-csms/manager/handlers"
+	"github.com/thoughtworks/maeve-csms/manager/handlers/ocpp21"
+	"github.com/thoughtworks/maeve-csms/manager/ocpp"
+	types201 "github.com/thoughtworks/maeve-csms/manager/ocpp/ocpp201"
+	types "github.com/thoughtworks/maeve-csms/manager/ocpp/ocpp21"
+	"testing"
+)
+
+func TestTransactionEventHandlerUsesOcpp201Handler(t *testing.T) {
+	var got *types201.TransactionEventRequestJson
+	handler := ocpp21.TransactionEventHandler{
+		Handler201: handlers.CallHandlerFunc(func(ctx context.Context, chargeStationId string, request ocpp.Request) (ocpp.Response, error) {
+			got = request.(*types201.TransactionEventRequestJson)
+			return &types201.TransactionEventResponseJson{
+				TotalCost: makePtr(12.5),
+			}, nil
+		}),
+	}
+
+	req := &types.TransactionEventRequestJson{
+		EventType:             types.TransactionEventEnumTypeUpdated,
+		EvseSleep:             makePtr(true),
+		PreconditioningStatus: makePtr(types.PreconditioningStatusEnumTypeReady),
+		SeqNo:                 2,
+		Timestamp:             "2023-06-15T15:05:00+01:00",
+		TransactionInfo: types.TransactionType{
+			TransactionId: "abc123",
+			TariffId:      makePtr("tariff-1"),
+			TransactionLimit: &types.TransactionLimitType{
+				MaxEnergy: makePtr(10000.0),
+			},
+		},
+		TriggerReason: types.TriggerReasonEnumTypeRunningCost,
+	}
+
+	resp, err := handler.HandleCall(context.Background(), "cs001", req)
+	require.NoError(t, err)
+
+	assert.Equal(t, &types201.TransactionEventRequestJson{
+		EventType: types201.TransactionEventEnumTypeUpdated,
+		SeqNo:     2,
+		Timestamp: "2023-06-15T15:05:00+01:00",
+		TransactionInfo: types201.TransactionType{
+			TransactionId: "abc123",
+		},
+		TriggerReason: "RunningCost",
+	}, got)
+	assert.Equal(t, &types.TransactionEventResponseJson{
+		TotalCost: makePtr(12.5),
+	}, resp)
+}
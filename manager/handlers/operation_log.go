@@ -0,0 +1,139 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/thoughtworks/maeve-csms/manager/store"
+	"github.com/thoughtworks/maeve-csms/manager/transport"
+	"golang.org/x/exp/slog"
+	"k8s.io/utils/clock"
+	"strings"
+)
+
+// RecordOperation appends an entry to the operation log of a charge station. The log is an
+// audit trail, so a failure to record an entry is logged rather than failing the operation.
+func RecordOperation(ctx context.Context, operationStore store.ChargeStationOperationStore, operation *store.ChargeStationOperation) {
+	if operationStore == nil {
+		return
+	}
+	err := operationStore.AppendChargeStationOperation(ctx, operation)
+	if err != nil {
+		slog.Warn("unable to record charge station operation", slog.String("chargeStationId", operation.ChargeStationId),
+			slog.String("event", string(operation.Event)), "err", err)
+	}
+}
+
+// OperationLogEmitter is an implementation of transport.Emitter that records every call
+// that is sent to a charge station in its operation log before passing the message on.
+type OperationLogEmitter struct {
+	Emitter        transport.Emitter
+	Clock          clock.PassiveClock
+	OperationStore store.ChargeStationOperationStore
+}
+
+func (e OperationLogEmitter) Emit(ctx context.Context, ocppVersion transport.OcppVersion, chargeStationId string, message *transport.Message) error {
+	err := e.Emitter.Emit(ctx, ocppVersion, chargeStationId, message)
+	if message.MessageType == transport.MessageTypeCall {
+		operation := &store.ChargeStationOperation{
+			ChargeStationId: chargeStationId,
+			Timestamp:       e.Clock.Now().UTC(),
+			Event:           store.ChargeStationOperationSent,
+			Action:          message.Action,
+			MessageId:       message.MessageId,
+			Payload:         RedactPayload(message.RequestPayload),
+		}
+		if err != nil {
+			operation.Result = err.Error()
+		}
+		RecordOperation(ctx, e.OperationStore, operation)
+	}
+	return err
+}
+
+// responseStatus returns the status from an OCPP response: most responses to calls made by
+// the CSMS have a top-level status field.
+func responseStatus(payload json.RawMessage) string {
+	var resp struct {
+		Status string `json:"status"`
+	}
+	if err := json.Unmarshal(payload, &resp); err != nil {
+		return ""
+	}
+	return resp.Status
+}
+
+// redacted replaces the value of a secret in a payload that is recorded in the operation log
+const redacted = "<redacted>"
+
+// RedactPayload returns an OCPP payload with the secrets that it contains replaced so that it
+// can be recorded in the operation log. Secrets are fields named like a password or key (e.g.
+// basicAuthPassword, apnPassword or the key of a VPN) and the values of configuration keys or
+// variables named like a secret (e.g. AuthorizationKey or BasicAuthPassword). A payload that is
+// not valid JSON cannot be checked, so it is not recorded at all.
+func RedactPayload(payload json.RawMessage) string {
+	if len(payload) == 0 {
+		return ""
+	}
+	var value any
+	if err := json.Unmarshal(payload, &value); err != nil {
+		return ""
+	}
+	if !redactValue(value, "") {
+		return string(payload)
+	}
+	redactedPayload, err := json.Marshal(value)
+	if err != nil {
+		return ""
+	}
+	return string(redactedPayload)
+}
+
+// redactValue redacts the secrets in a decoded JSON value in place and returns true if any
+// were found. The field name of the parent object is used to identify the key of a VPN.
+func redactValue(value any, parent string) bool {
+	found := false
+	switch v := value.(type) {
+	case []any:
+		for _, item := range v {
+			found = redactValue(item, parent) || found
+		}
+	case map[string]any:
+		// OCPP 1.6 configuration keys are {"key": "...", "value": "..."} and OCPP 2.x
+		// variables are {"variable": {"name": "..."}, "attributeValue": "..."}
+		if name, ok := v["key"].(string); ok && isSecretName(name) {
+			found = redactField(v, "value") || found
+		}
+		if variable, ok := v["variable"].(map[string]any); ok {
+			if name, ok := variable["name"].(string); ok && isSecretName(name) {
+				found = redactField(v, "attributeValue") || found
+				found = redactField(v, "value") || found
+			}
+		}
+		for field, item := range v {
+			if isSecretName(field) || (strings.EqualFold(parent, "vpn") && field == "key") {
+				found = redactField(v, field) || found
+				continue
+			}
+			found = redactValue(item, field) || found
+		}
+	}
+	return found
+}
+
+func redactField(object map[string]any, field string) bool {
+	if _, ok := object[field]; !ok {
+		return false
+	}
+	object[field] = redacted
+	return true
+}
+
+func isSecretName(name string) bool {
+	name = strings.ToLower(name)
+	return strings.Contains(name, "password") ||
+		strings.Contains(name, "secret") ||
+		name == "authorizationkey" ||
+		name == "simpin"
+}
@@ -0,0 +1,176 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package handlers_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thoughtworks/maeve-csms/manager/handlers"
+	"github.com/thoughtworks/maeve-csms/manager/ocpp"
+	"github.com/thoughtworks/maeve-csms/manager/ocpp/ocpp201"
+	"github.com/thoughtworks/maeve-csms/manager/schemas"
+	"github.com/thoughtworks/maeve-csms/manager/store"
+	"github.com/thoughtworks/maeve-csms/manager/store/inmemory"
+	"github.com/thoughtworks/maeve-csms/manager/transport"
+	"k8s.io/utils/clock"
+	clockTest "k8s.io/utils/clock/testing"
+	"testing"
+	"time"
+)
+
+func TestOperationLogEmitterRecordsCalls(t *testing.T) {
+	now := time.Date(2023, 6, 15, 15, 0, 0, 0, time.UTC)
+	engine := inmemory.NewStore(clock.RealClock{})
+	fakeEmitter := new(FakeEmitter)
+	emitter := handlers.OperationLogEmitter{
+		Emitter:        fakeEmitter,
+		Clock:          clockTest.NewFakePassiveClock(now),
+		OperationStore: engine,
+	}
+
+	call := &transport.Message{
+		MessageType:    transport.MessageTypeCall,
+		Action:         "Reset",
+		MessageId:      "1234",
+		RequestPayload: []byte(`{"type":"Immediate"}`),
+	}
+	err := emitter.Emit(context.Background(), transport.OcppVersion201, "cs001", call)
+	require.NoError(t, err)
+	assert.Equal(t, call, fakeEmitter.msg)
+
+	// responses to calls made by the charge station are not CSMS operations
+	err = emitter.Emit(context.Background(), transport.OcppVersion201, "cs001", &transport.Message{
+		MessageType:     transport.MessageTypeCallResult,
+		Action:          "Heartbeat",
+		MessageId:       "5678",
+		ResponsePayload: []byte(`{}`),
+	})
+	require.NoError(t, err)
+
+	got, err := engine.ListChargeStationOperations(context.Background(), "cs001", 0, 10)
+	require.NoError(t, err)
+	want := []*store.ChargeStationOperation{
+		{
+			ChargeStationId: "cs001",
+			Timestamp:       now,
+			Event:           store.ChargeStationOperationSent,
+			Action:          "Reset",
+			MessageId:       "1234",
+			Payload:         `{"type":"Immediate"}`,
+		},
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestRouterRecordsCallResults(t *testing.T) {
+	now := time.Date(2023, 6, 15, 15, 0, 0, 0, time.UTC)
+	engine := inmemory.NewStore(clock.RealClock{})
+
+	router := handlers.Router{
+		Emitter:        new(FakeEmitter),
+		SchemaFS:       schemas.OcppSchemas,
+		Clock:          clockTest.NewFakePassiveClock(now),
+		OperationStore: engine,
+		CallResultRoutes: map[string]handlers.CallResultRoute{
+			"Reset": {
+				NewRequest:     func() ocpp.Request { return new(ocpp201.ResetRequestJson) },
+				NewResponse:    func() ocpp.Response { return new(ocpp201.ResetResponseJson) },
+				RequestSchema:  "ocpp201/ResetRequest.json",
+				ResponseSchema: "ocpp201/ResetResponse.json",
+				Handler: handlers.CallResultHandlerFunc(func(context.Context, string, ocpp.Request, ocpp.Response, any) error {
+					return nil
+				}),
+			},
+		},
+	}
+
+	router.Handle(context.Background(), "cs001", &transport.Message{
+		MessageType:     transport.MessageTypeCallResult,
+		Action:          "Reset",
+		MessageId:       "1234",
+		RequestPayload:  []byte(`{"type":"Immediate"}`),
+		ResponsePayload: []byte(`{"status":"Accepted"}`),
+	})
+
+	got, err := engine.ListChargeStationOperations(context.Background(), "cs001", 0, 10)
+	require.NoError(t, err)
+	want := []*store.ChargeStationOperation{
+		{
+			ChargeStationId: "cs001",
+			Timestamp:       now,
+			Event:           store.ChargeStationOperationResponded,
+			Action:          "Reset",
+			MessageId:       "1234",
+			Result:          "Accepted",
+			Payload:         `{"status":"Accepted"}`,
+		},
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestOperationLogEmitterRedactsSecrets(t *testing.T) {
+	engine := inmemory.NewStore(clock.RealClock{})
+	emitter := handlers.OperationLogEmitter{
+		Emitter:        new(FakeEmitter),
+		Clock:          clock.RealClock{},
+		OperationStore: engine,
+	}
+
+	err := emitter.Emit(context.Background(), transport.OcppVersion16, "cs001", &transport.Message{
+		MessageType:    transport.MessageTypeCall,
+		Action:         "ChangeConfiguration",
+		MessageId:      "1234",
+		RequestPayload: []byte(`{"key":"AuthorizationKey","value":"0123456789abcdef"}`),
+	})
+	require.NoError(t, err)
+
+	got, err := engine.ListChargeStationOperations(context.Background(), "cs001", 0, 10)
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.JSONEq(t, `{"key":"AuthorizationKey","value":"<redacted>"}`, got[0].Payload)
+}
+
+func TestRedactPayload(t *testing.T) {
+	tests := map[string]struct {
+		payload string
+		want    string
+	}{
+		"no secrets": {
+			payload: `{"type":"Immediate"}`,
+			want:    `{"type":"Immediate"}`,
+		},
+		"ocpp 1.6 configuration key": {
+			payload: `{"key":"AuthorizationKey","value":"0123456789abcdef"}`,
+			want:    `{"key":"AuthorizationKey","value":"<redacted>"}`,
+		},
+		"ocpp 1.6 configuration key that is not a secret": {
+			payload: `{"key":"HeartbeatInterval","value":"300"}`,
+			want:    `{"key":"HeartbeatInterval","value":"300"}`,
+		},
+		"ocpp 2.0.1 variables": {
+			payload: `{"setVariableData":[` +
+				`{"component":{"name":"SecurityCtrlr"},"variable":{"name":"BasicAuthPassword"},"attributeValue":"s3cr3t"},` +
+				`{"component":{"name":"OCPPCommCtrlr"},"variable":{"name":"HeartbeatInterval"},"attributeValue":"300"}]}`,
+			want: `{"setVariableData":[` +
+				`{"component":{"name":"SecurityCtrlr"},"variable":{"name":"BasicAuthPassword"},"attributeValue":"<redacted>"},` +
+				`{"component":{"name":"OCPPCommCtrlr"},"variable":{"name":"HeartbeatInterval"},"attributeValue":"300"}]}`,
+		},
+		"ocpp 2.0.1 network profile": {
+			payload: `{"configurationSlot":1,"connectionData":{"ocppCsmsUrl":"wss://csms.example.com",` +
+				`"vpn":{"server":"vpn.example.com","user":"cs001","password":"s3cr3t","key":"vpnkey","type":"IPSec"},` +
+				`"apn":{"apn":"internet","apnPassword":"s3cr3t","simPin":1234}}}`,
+			want: `{"configurationSlot":1,"connectionData":{"ocppCsmsUrl":"wss://csms.example.com",` +
+				`"vpn":{"server":"vpn.example.com","user":"cs001","password":"<redacted>","key":"<redacted>","type":"IPSec"},` +
+				`"apn":{"apn":"internet","apnPassword":"<redacted>","simPin":"<redacted>"}}}`,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.JSONEq(t, tc.want, handlers.RedactPayload([]byte(tc.payload)))
+		})
+	}
+
+	assert.Equal(t, "", handlers.RedactPayload([]byte(`not json`)))
+}
@@ -0,0 +1,77 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package handlers
+
+import (
+	"context"
+	"github.com/thoughtworks/maeve-csms/manager/store"
+	"github.com/thoughtworks/maeve-csms/manager/transport"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/exp/slog"
+	"k8s.io/utils/clock"
+)
+
+// PresenceHandler is an implementation of transport.PresenceHandler that records the
+// connection status of each charge station in the store. If a SyncNotifier is provided
+// it is told when a charge station connects so that any pending changes can be sent.
+type PresenceHandler struct {
+	Clock           clock.PassiveClock
+	ConnectionStore store.ChargeStationConnectionStore
+	SyncNotifier    SyncNotifier
+}
+
+func (h PresenceHandler) HandlePresence(ctx context.Context, chargeStationId string, event *transport.PresenceEvent) {
+	span := trace.SpanFromContext(ctx)
+
+	err := h.handle(ctx, chargeStationId, event)
+	if err != nil {
+		slog.Error("unable to handle presence event", slog.String("chargeStationId", chargeStationId),
+			slog.String("type", string(event.Type)), "err", err)
+		span.SetStatus(codes.Error, "handling presence event failed")
+		span.RecordError(err)
+	} else {
+		span.SetStatus(codes.Ok, "ok")
+	}
+}
+
+func (h PresenceHandler) handle(ctx context.Context, chargeStationId string, event *transport.PresenceEvent) error {
+	now := h.Clock.Now().UTC()
+
+	switch event.Type {
+	case transport.PresenceConnected:
+		err := h.ConnectionStore.SetChargeStationConnection(ctx, chargeStationId, &store.ChargeStationConnection{
+			Connected:       true,
+			GatewayId:       event.GatewayId,
+			ConnectionId:    event.ConnectionId,
+			OcppVersion:     event.Protocol,
+			RemoteAddr:      event.RemoteAddr,
+			SecurityProfile: store.SecurityProfile(event.SecurityProfile),
+			ConnectedAt:     now,
+		})
+		if err != nil {
+			return err
+		}
+		if h.SyncNotifier != nil {
+			h.SyncNotifier.NotifyConnected(ctx, chargeStationId)
+		}
+		return nil
+	case transport.PresenceDisconnected:
+		connection, err := h.ConnectionStore.LookupChargeStationConnection(ctx, chargeStationId)
+		if err != nil {
+			return err
+		}
+		// the charge station may have already reconnected (possibly to another gateway)
+		if connection == nil || connection.ConnectionId != event.ConnectionId {
+			slog.Info("ignoring disconnection of superseded connection", "chargeStationId", chargeStationId,
+				"connectionId", event.ConnectionId)
+			return nil
+		}
+		connection.Connected = false
+		connection.DisconnectedAt = now
+		return h.ConnectionStore.SetChargeStationConnection(ctx, chargeStationId, connection)
+	default:
+		slog.Warn("unknown presence event type", "chargeStationId", chargeStationId, "type", event.Type)
+		return nil
+	}
+}
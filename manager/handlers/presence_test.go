@@ -0,0 +1,125 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package handlers_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thoughtworks/maeve-csms/manager/handlers"
+	"github.com/thoughtworks/maeve-csms/manager/store"
+	"github.com/thoughtworks/maeve-csms/manager/store/inmemory"
+	"github.com/thoughtworks/maeve-csms/manager/transport"
+	"k8s.io/utils/clock"
+	clockTest "k8s.io/utils/clock/testing"
+	"testing"
+	"time"
+)
+
+func TestPresenceHandlerRecordsConnectionAndDisconnection(t *testing.T) {
+	ctx := context.Background()
+	engine := inmemory.NewStore(clock.RealClock{})
+	now := time.Date(2023, 6, 15, 15, 0, 0, 0, time.UTC)
+	c := clockTest.NewFakePassiveClock(now)
+
+	handler := handlers.PresenceHandler{
+		Clock:           c,
+		ConnectionStore: engine,
+	}
+
+	event := &transport.PresenceEvent{
+		Type:            transport.PresenceConnected,
+		GatewayId:       "gateway1",
+		ConnectionId:    "abc123",
+		Protocol:        "ocpp1.6",
+		RemoteAddr:      "127.0.0.1:1234",
+		SecurityProfile: 1,
+	}
+	handler.HandlePresence(ctx, "cs001", event)
+
+	got, err := engine.LookupChargeStationConnection(ctx, "cs001")
+	require.NoError(t, err)
+	assert.Equal(t, &store.ChargeStationConnection{
+		ChargeStationId: "cs001",
+		Connected:       true,
+		GatewayId:       "gateway1",
+		ConnectionId:    "abc123",
+		OcppVersion:     "ocpp1.6",
+		RemoteAddr:      "127.0.0.1:1234",
+		SecurityProfile: store.TLSWithBasicAuth,
+		ConnectedAt:     now,
+	}, got)
+
+	c.SetTime(now.Add(time.Hour))
+	event.Type = transport.PresenceDisconnected
+	handler.HandlePresence(ctx, "cs001", event)
+
+	got, err = engine.LookupChargeStationConnection(ctx, "cs001")
+	require.NoError(t, err)
+	assert.False(t, got.Connected)
+	assert.Equal(t, now, got.ConnectedAt)
+	assert.Equal(t, now.Add(time.Hour), got.DisconnectedAt)
+}
+
+func TestPresenceHandlerIgnoresDisconnectionOfSupersededConnection(t *testing.T) {
+	ctx := context.Background()
+	engine := inmemory.NewStore(clock.RealClock{})
+
+	handler := handlers.PresenceHandler{
+		Clock:           clock.RealClock{},
+		ConnectionStore: engine,
+	}
+
+	handler.HandlePresence(ctx, "cs001", &transport.PresenceEvent{
+		Type:         transport.PresenceConnected,
+		GatewayId:    "gateway2",
+		ConnectionId: "new",
+	})
+	handler.HandlePresence(ctx, "cs001", &transport.PresenceEvent{
+		Type:         transport.PresenceDisconnected,
+		GatewayId:    "gateway1",
+		ConnectionId: "old",
+	})
+
+	got, err := engine.LookupChargeStationConnection(ctx, "cs001")
+	require.NoError(t, err)
+	assert.True(t, got.Connected)
+	assert.Equal(t, "gateway2", got.GatewayId)
+}
+
+type recordingSyncNotifier struct {
+	pending   []string
+	connected []string
+}
+
+func (n *recordingSyncNotifier) NotifyPending(_ context.Context, chargeStationId string) {
+	n.pending = append(n.pending, chargeStationId)
+}
+
+func (n *recordingSyncNotifier) NotifyConnected(_ context.Context, chargeStationId string) {
+	n.connected = append(n.connected, chargeStationId)
+}
+
+func TestPresenceHandlerNotifiesSyncOnConnection(t *testing.T) {
+	ctx := context.Background()
+	engine := inmemory.NewStore(clock.RealClock{})
+	notifier := &recordingSyncNotifier{}
+
+	handler := handlers.PresenceHandler{
+		Clock:           clock.RealClock{},
+		ConnectionStore: engine,
+		SyncNotifier:    notifier,
+	}
+
+	handler.HandlePresence(ctx, "cs001", &transport.PresenceEvent{
+		Type:         transport.PresenceConnected,
+		ConnectionId: "abc123",
+	})
+	handler.HandlePresence(ctx, "cs001", &transport.PresenceEvent{
+		Type:         transport.PresenceDisconnected,
+		ConnectionId: "abc123",
+	})
+
+	assert.Equal(t, []string{"cs001"}, notifier.connected)
+	assert.Empty(t, notifier.pending)
+}
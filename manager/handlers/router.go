@@ -9,20 +9,24 @@ import (
 	"fmt"
 	"github.com/santhosh-tekuri/jsonschema"
 	"github.com/thoughtworks/maeve-csms/manager/schemas"
+	"github.com/thoughtworks/maeve-csms/manager/store"
 	"github.com/thoughtworks/maeve-csms/manager/transport"
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/exp/slog"
 	"io/fs"
+	"k8s.io/utils/clock"
 )
 
 // Router is the primary implementation of the transport.Router interface.
 type Router struct {
-	Emitter          transport.Emitter          // used to send responses to the gateway
-	SchemaFS         fs.FS                      // used to obtain schema files
-	OcppVersion      transport.OcppVersion      // the OCPP version that this router supports
-	CallRoutes       map[string]CallRoute       // the set of routes for incoming calls (indexed by action)
-	CallResultRoutes map[string]CallResultRoute // the set of routes for call results (indexed by action)
+	Emitter          transport.Emitter                 // used to send responses to the gateway
+	SchemaFS         fs.FS                             // used to obtain schema files
+	OcppVersion      transport.OcppVersion             // the OCPP version that this router supports
+	CallRoutes       map[string]CallRoute              // the set of routes for incoming calls (indexed by action)
+	CallResultRoutes map[string]CallResultRoute        // the set of routes for call results (indexed by action)
+	Clock            clock.PassiveClock                // used to timestamp the operation log
+	OperationStore   store.ChargeStationOperationStore // used to record responses to calls (optional)
 }
 
 func (r Router) Handle(ctx context.Context, chargeStationId string, msg *transport.Message) {
@@ -126,14 +130,31 @@ func (r Router) route(ctx context.Context, chargeStationId string, message *tran
 		if err != nil {
 			return fmt.Errorf("unmarshalling %s response payload: %v", message.Action, err)
 		}
+		r.recordResponse(ctx, chargeStationId, message, responseStatus(message.ResponsePayload), RedactPayload(message.ResponsePayload))
 		err = route.Handler.HandleCallResult(ctx, chargeStationId, req, resp, message.State)
 		if err != nil {
 			return err
 		}
 	case transport.MessageTypeCallError:
+		r.recordResponse(ctx, chargeStationId, message, string(message.ErrorCode), message.ErrorDescription)
 		// TODO: what do we want to do with errors?
 		return errors.New("we shouldn't get here at the moment")
 	}
 
 	return nil
 }
+
+func (r Router) recordResponse(ctx context.Context, chargeStationId string, message *transport.Message, result, payload string) {
+	if r.OperationStore == nil {
+		return
+	}
+	RecordOperation(ctx, r.OperationStore, &store.ChargeStationOperation{
+		ChargeStationId: chargeStationId,
+		Timestamp:       r.Clock.Now().UTC(),
+		Event:           store.ChargeStationOperationResponded,
+		Action:          message.Action,
+		MessageId:       message.MessageId,
+		Result:          result,
+		Payload:         payload,
+	})
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/thoughtworks/maeve-csms/manager/handlers"
 	handlers201 "github.com/thoughtworks/maeve-csms/manager/handlers/ocpp201"
 	"github.com/thoughtworks/maeve-csms/manager/schemas"
+	"github.com/thoughtworks/maeve-csms/manager/store/inmemory"
 	"github.com/thoughtworks/maeve-csms/manager/testutil"
 	"github.com/thoughtworks/maeve-csms/manager/transport"
 	"go.opentelemetry.io/otel/attribute"
@@ -49,7 +50,8 @@ func TestRouterHandlesCall(t *testing.T) {
 				RequestSchema:  "ocpp201/HeartbeatRequest.json",
 				ResponseSchema: "ocpp201/HeartbeatResponse.json",
 				Handler: handlers201.HeartbeatHandler{
-					Clock: clock.RealClock{},
+					Clock:        clock.RealClock{},
+					DetailsStore: inmemory.NewStore(clock.RealClock{}),
 				},
 			},
 		},
@@ -96,7 +98,8 @@ func TestRouterErrorWhenCallRequestPayloadIsInvalid(t *testing.T) {
 				RequestSchema:  "ocpp201/Heartbeat.json",
 				ResponseSchema: "ocpp201/Heartbeat.json",
 				Handler: handlers201.HeartbeatHandler{
-					Clock: clock.RealClock{},
+					Clock:        clock.RealClock{},
+					DetailsStore: inmemory.NewStore(clock.RealClock{}),
 				},
 			},
 		},
@@ -130,7 +133,8 @@ func TestRouterErrorWhenCantUnmarshallCallRequestPayload(t *testing.T) {
 				RequestSchema:  "schemas/EmptySchema.json",
 				ResponseSchema: "schemas/EmptySchema.json",
 				Handler: handlers201.HeartbeatHandler{
-					Clock: clock.RealClock{},
+					Clock:        clock.RealClock{},
+					DetailsStore: inmemory.NewStore(clock.RealClock{}),
 				},
 			},
 		},
@@ -0,0 +1,53 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package handlers
+
+import (
+	"context"
+	"github.com/thoughtworks/maeve-csms/manager/store"
+)
+
+// RecordTriggerMessageResult updates the queue of trigger messages for a charge station with the
+// result of a trigger message. An accepted trigger message is removed from the queue: any other
+// result is recorded against the queued trigger message. The SyncNotifier (which is optional) is
+// told so that the next trigger message in the queue can be sent.
+func RecordTriggerMessageResult(ctx context.Context,
+	triggerMessageStore store.ChargeStationTriggerMessageStore,
+	syncNotifier SyncNotifier,
+	chargeStationId string,
+	result *store.ChargeStationTriggerMessage) error {
+	queue, err := triggerMessageStore.LookupChargeStationTriggerMessages(ctx, chargeStationId)
+	if err != nil {
+		return err
+	}
+	var queued *store.ChargeStationTriggerMessage
+	if queue != nil {
+		for _, triggerMessage := range queue.TriggerMessages {
+			if triggerMessage.SameTarget(result) {
+				queued = triggerMessage
+				break
+			}
+		}
+	}
+	if queued == nil {
+		// the trigger message was not sent from the queue
+		return nil
+	}
+
+	if result.TriggerStatus == store.TriggerStatusAccepted {
+		err = triggerMessageStore.DeleteChargeStationTriggerMessage(ctx, chargeStationId, queued)
+	} else {
+		queued.TriggerStatus = result.TriggerStatus
+		err = triggerMessageStore.UpdateChargeStationTriggerMessages(ctx, chargeStationId, &store.ChargeStationTriggerMessages{
+			TriggerMessages: []*store.ChargeStationTriggerMessage{queued},
+		})
+	}
+	if err != nil {
+		return err
+	}
+
+	if syncNotifier != nil {
+		syncNotifier.NotifyPending(ctx, chargeStationId)
+	}
+	return nil
+}
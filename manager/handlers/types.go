@@ -60,3 +60,17 @@ type CallMaker interface {
 	// Send receives the charge station id and the request to send. It may return an error.
 	Send(ctx context.Context, chargeStationId string, request ocpp.Request) error
 }
+
+// CallMakerForVersion returns the call maker for the OCPP version that a charge station
+// last connected with (as recorded in its runtime details). OCPP 2.0.1 is assumed for
+// any other version.
+func CallMakerForVersion(ocppVersion string, v16CallMaker, v201CallMaker, v21CallMaker CallMaker) CallMaker {
+	switch ocppVersion {
+	case "1.6":
+		return v16CallMaker
+	case "2.1":
+		return v21CallMaker
+	default:
+		return v201CallMaker
+	}
+}
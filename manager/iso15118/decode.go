@@ -0,0 +1,597 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package iso15118
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// DecodeCertificateInstallationReq decodes a V2G message that has a CertificateInstallationReq body
+func DecodeCertificateInstallationReq(data []byte) (*CertificateInstallationReqMessage, error) {
+	msg := new(CertificateInstallationReqMessage)
+	err := decodeDocument(data, &msg.Header, qname{ns: nsMsgBody, local: "CertificateInstallationReq"}, func(r *bitReader) error {
+		return decodeCertificateInstallationReq(r, &msg.Body)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+// DecodeCertificateInstallationRes decodes a V2G message that has a CertificateInstallationRes body
+func DecodeCertificateInstallationRes(data []byte) (*CertificateInstallationResMessage, error) {
+	msg := new(CertificateInstallationResMessage)
+	err := decodeDocument(data, &msg.Header, qname{ns: nsMsgBody, local: "CertificateInstallationRes"}, func(r *bitReader) error {
+		return decodeCertificateInstallationRes(r, &msg.Body)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+func decodeDocument(data []byte, header *MessageHeader, body qname, decodeBody func(r *bitReader) error) error {
+	r := &bitReader{buf: data}
+	h, err := r.readBits(8)
+	if err != nil {
+		return fmt.Errorf("decoding EXI header: %w", err)
+	}
+	if h != exiHeader {
+		return fmt.Errorf("unsupported EXI header: %#x", h)
+	}
+	code, err := r.readEventCode(documentCodeBits)
+	if err != nil {
+		return err
+	}
+	if code != documentElements.code(qname{ns: nsMsgDef, local: "V2G_Message"}) {
+		return fmt.Errorf("expected V2G_Message, got %s: %w", documentElements.name(code), errUnsupportedEvent)
+	}
+	if err = r.expectEventCode(1, 0, "SE(Header)"); err != nil {
+		return err
+	}
+	if err = decodeMessageHeader(r, header); err != nil {
+		return fmt.Errorf("decoding header: %w", err)
+	}
+	if err = r.expectEventCode(1, 0, "SE(Body)"); err != nil {
+		return err
+	}
+	code, err = r.readEventCode(bodyCodeBits)
+	if err != nil {
+		return err
+	}
+	if code != bodyElements.code(body) {
+		return fmt.Errorf("expected %s, got %s: %w", body.local, bodyElements.name(code), errUnsupportedEvent)
+	}
+	if err = decodeBody(r); err != nil {
+		return fmt.Errorf("decoding %s: %w", body.local, err)
+	}
+	if err = r.expectEventCode(1, 0, "EE(Body)"); err != nil {
+		return err
+	}
+	return r.expectEventCode(1, 0, "EE(V2G_Message)")
+}
+
+func decodeMessageHeader(r *bitReader, header *MessageHeader) error {
+	err := r.expectEventCode(1, 0, "SE(SessionID)")
+	if err != nil {
+		return err
+	}
+	header.SessionID, err = decodeBinaryContent(r)
+	if err != nil {
+		return err
+	}
+	if len(header.SessionID) > 8 {
+		return fmt.Errorf("session id is %d bytes: must be no more than 8", len(header.SessionID))
+	}
+	code, err := r.readEventCode(2)
+	if err != nil {
+		return err
+	}
+	if code == 0 {
+		header.Notification = new(Notification)
+		if err = decodeNotification(r, header.Notification); err != nil {
+			return err
+		}
+		code, err = r.readEventCode(2)
+		if err != nil {
+			return err
+		}
+		// the codes are the same as when there is no notification, less SE(Notification)
+		code++
+	}
+	switch code {
+	case 1:
+		header.Signature = new(Signature)
+		if err = decodeSignature(r, header.Signature); err != nil {
+			return err
+		}
+		return r.expectEventCode(1, 0, "EE(Header)")
+	case 2:
+		return nil
+	default:
+		return fmt.Errorf("unexpected event code %d in header: %w", code, errUnsupportedEvent)
+	}
+}
+
+func decodeNotification(r *bitReader, notification *Notification) error {
+	err := r.expectEventCode(1, 0, "SE(FaultCode)")
+	if err != nil {
+		return err
+	}
+	notification.FaultCode, err = decodeEnumerationContent(r, faultCodes)
+	if err != nil {
+		return err
+	}
+	code, err := r.readEventCode(2)
+	if err != nil {
+		return err
+	}
+	switch code {
+	case 0:
+		notification.FaultMsg, err = decodeStringContent(r)
+		if err != nil {
+			return err
+		}
+		return r.expectEventCode(1, 0, "EE(Notification)")
+	case 1:
+		return nil
+	default:
+		return fmt.Errorf("unexpected event code %d in notification: %w", code, errUnsupportedEvent)
+	}
+}
+
+func decodeSignature(r *bitReader, signature *Signature) error {
+	code, err := r.readEventCode(2)
+	if err != nil {
+		return err
+	}
+	switch code {
+	case 0:
+		signature.Id, err = r.readString()
+		if err != nil {
+			return err
+		}
+		if err = r.expectEventCode(1, 0, "SE(SignedInfo)"); err != nil {
+			return err
+		}
+	case 1:
+	default:
+		return fmt.Errorf("unexpected event code %d in signature: %w", code, errUnsupportedEvent)
+	}
+	if err = decodeSignedInfo(r, &signature.SignedInfo); err != nil {
+		return err
+	}
+	if err = r.expectEventCode(1, 0, "SE(SignatureValue)"); err != nil {
+		return err
+	}
+	code, err = r.readEventCode(2)
+	if err != nil {
+		return err
+	}
+	switch code {
+	case 0:
+		// the Id of the signature value is not retained
+		if _, err = r.readString(); err != nil {
+			return err
+		}
+		if err = r.expectEventCode(1, 0, "CH(SignatureValue)"); err != nil {
+			return err
+		}
+	case 1:
+	default:
+		return fmt.Errorf("unexpected event code %d in signature value: %w", code, errUnsupportedEvent)
+	}
+	signature.SignatureValue, err = r.readBinary()
+	if err != nil {
+		return err
+	}
+	if err = r.expectEventCode(1, 0, "EE(SignatureValue)"); err != nil {
+		return err
+	}
+	return r.expectEventCode(2, 2, "EE(Signature)")
+}
+
+func decodeSignedInfo(r *bitReader, signedInfo *SignedInfo) error {
+	code, err := r.readEventCode(2)
+	if err != nil {
+		return err
+	}
+	switch code {
+	case 0:
+		signedInfo.Id, err = r.readString()
+		if err != nil {
+			return err
+		}
+		if err = r.expectEventCode(1, 0, "SE(CanonicalizationMethod)"); err != nil {
+			return err
+		}
+	case 1:
+	default:
+		return fmt.Errorf("unexpected event code %d in signed info: %w", code, errUnsupportedEvent)
+	}
+	if err = r.expectEventCode(1, 0, "AT(Algorithm)"); err != nil {
+		return err
+	}
+	signedInfo.CanonicalizationMethod, err = r.readString()
+	if err != nil {
+		return err
+	}
+	if err = r.expectEventCode(2, 1, "EE(CanonicalizationMethod)"); err != nil {
+		return err
+	}
+	if err = r.expectEventCode(1, 0, "SE(SignatureMethod)"); err != nil {
+		return err
+	}
+	if err = r.expectEventCode(1, 0, "AT(Algorithm)"); err != nil {
+		return err
+	}
+	signedInfo.SignatureMethod, err = r.readString()
+	if err != nil {
+		return err
+	}
+	if err = r.expectEventCode(3, 2, "EE(SignatureMethod)"); err != nil {
+		return err
+	}
+	if err = r.expectEventCode(1, 0, "SE(Reference)"); err != nil {
+		return err
+	}
+	for {
+		var reference Reference
+		if err = decodeReference(r, &reference); err != nil {
+			return err
+		}
+		signedInfo.References = append(signedInfo.References, reference)
+		code, err = r.readEventCode(2)
+		if err != nil {
+			return err
+		}
+		switch code {
+		case 0:
+			continue
+		case 1:
+			return nil
+		default:
+			return fmt.Errorf("unexpected event code %d in signed info: %w", code, errUnsupportedEvent)
+		}
+	}
+}
+
+func decodeReference(r *bitReader, reference *Reference) error {
+	// the attributes are optional, so the event code for each depends on which of the
+	// preceding attributes are present: offset is the number of productions that are no
+	// longer available
+	codeBits, offset := 3, 0
+	for {
+		code, err := r.readEventCode(codeBits)
+		if err != nil {
+			return err
+		}
+		code += offset
+		switch code {
+		case 0:
+			reference.Id, err = r.readString()
+			codeBits, offset = 3, 1
+		case 1:
+			reference.Type, err = r.readString()
+			codeBits, offset = 2, 2
+		case 2:
+			reference.URI, err = r.readString()
+			codeBits, offset = 2, 3
+		case 3:
+			err = decodeTransforms(r, reference)
+			if err == nil {
+				err = r.expectEventCode(1, 0, "SE(DigestMethod)")
+			}
+		case 4:
+		default:
+			return fmt.Errorf("unexpected event code %d in reference: %w", code, errUnsupportedEvent)
+		}
+		if err != nil {
+			return err
+		}
+		if code >= 3 {
+			break
+		}
+	}
+	err := r.expectEventCode(1, 0, "AT(Algorithm)")
+	if err != nil {
+		return err
+	}
+	reference.DigestMethod, err = r.readString()
+	if err != nil {
+		return err
+	}
+	if err = r.expectEventCode(2, 1, "EE(DigestMethod)"); err != nil {
+		return err
+	}
+	if err = r.expectEventCode(1, 0, "SE(DigestValue)"); err != nil {
+		return err
+	}
+	reference.DigestValue, err = decodeBinaryContent(r)
+	if err != nil {
+		return err
+	}
+	return r.expectEventCode(1, 0, "EE(Reference)")
+}
+
+func decodeTransforms(r *bitReader, reference *Reference) error {
+	err := r.expectEventCode(1, 0, "SE(Transform)")
+	if err != nil {
+		return err
+	}
+	for {
+		if err = r.expectEventCode(1, 0, "AT(Algorithm)"); err != nil {
+			return err
+		}
+		algorithm, err := r.readString()
+		if err != nil {
+			return err
+		}
+		reference.Transforms = append(reference.Transforms, algorithm)
+		if err = r.expectEventCode(3, 2, "EE(Transform)"); err != nil {
+			return err
+		}
+		code, err := r.readEventCode(2)
+		if err != nil {
+			return err
+		}
+		switch code {
+		case 0:
+			continue
+		case 1:
+			return nil
+		default:
+			return fmt.Errorf("unexpected event code %d in transforms: %w", code, errUnsupportedEvent)
+		}
+	}
+}
+
+func decodeCertificateInstallationReq(r *bitReader, req *CertificateInstallationReq) error {
+	err := r.expectEventCode(1, 0, "AT(Id)")
+	if err != nil {
+		return err
+	}
+	req.Id, err = r.readString()
+	if err != nil {
+		return err
+	}
+	if err = r.expectEventCode(1, 0, "SE(OEMProvisioningCert)"); err != nil {
+		return err
+	}
+	req.OEMProvisioningCert, err = decodeBinaryContent(r)
+	if err != nil {
+		return err
+	}
+	if err = r.expectEventCode(1, 0, "SE(ListOfRootCertificateIDs)"); err != nil {
+		return err
+	}
+	if err = r.expectEventCode(1, 0, "SE(RootCertificateID)"); err != nil {
+		return err
+	}
+	for {
+		var issuerSerial X509IssuerSerial
+		if err = decodeX509IssuerSerial(r, &issuerSerial); err != nil {
+			return err
+		}
+		req.ListOfRootCertificateIDs = append(req.ListOfRootCertificateIDs, issuerSerial)
+		if len(req.ListOfRootCertificateIDs) == maxRootCertificateIDs {
+			if err = r.expectEventCode(1, 0, "EE(ListOfRootCertificateIDs)"); err != nil {
+				return err
+			}
+			break
+		}
+		code, err := r.readEventCode(2)
+		if err != nil {
+			return err
+		}
+		if code == 1 {
+			break
+		}
+		if code != 0 {
+			return fmt.Errorf("unexpected event code %d in list of root certificate ids: %w", code, errUnsupportedEvent)
+		}
+	}
+	return r.expectEventCode(1, 0, "EE(CertificateInstallationReq)")
+}
+
+func decodeX509IssuerSerial(r *bitReader, issuerSerial *X509IssuerSerial) error {
+	err := r.expectEventCode(1, 0, "SE(X509IssuerName)")
+	if err != nil {
+		return err
+	}
+	issuerSerial.X509IssuerName, err = decodeStringContent(r)
+	if err != nil {
+		return err
+	}
+	if err = r.expectEventCode(1, 0, "SE(X509SerialNumber)"); err != nil {
+		return err
+	}
+	issuerSerial.X509SerialNumber, err = decodeIntegerContent(r)
+	if err != nil {
+		return err
+	}
+	return r.expectEventCode(1, 0, "EE(RootCertificateID)")
+}
+
+func decodeCertificateInstallationRes(r *bitReader, res *CertificateInstallationRes) error {
+	err := r.expectEventCode(1, 0, "SE(ResponseCode)")
+	if err != nil {
+		return err
+	}
+	res.ResponseCode, err = decodeEnumerationContent(r, responseCodes)
+	if err != nil {
+		return err
+	}
+	if err = r.expectEventCode(1, 0, "SE(SAProvisioningCertificateChain)"); err != nil {
+		return err
+	}
+	if err = decodeCertificateChain(r, &res.SAProvisioningCertificateChain); err != nil {
+		return err
+	}
+	if err = r.expectEventCode(1, 0, "SE(ContractSignatureCertChain)"); err != nil {
+		return err
+	}
+	if err = decodeCertificateChain(r, &res.ContractSignatureCertChain); err != nil {
+		return err
+	}
+	if err = r.expectEventCode(1, 0, "SE(ContractSignatureEncryptedPrivateKey)"); err != nil {
+		return err
+	}
+	res.ContractSignatureEncryptedPrivateKey.Id, res.ContractSignatureEncryptedPrivateKey.Value, err = decodeIdentifiedBinary(r)
+	if err != nil {
+		return err
+	}
+	if err = r.expectEventCode(1, 0, "SE(DHpublickey)"); err != nil {
+		return err
+	}
+	res.DHpublickey.Id, res.DHpublickey.Value, err = decodeIdentifiedBinary(r)
+	if err != nil {
+		return err
+	}
+	if err = r.expectEventCode(1, 0, "SE(eMAID)"); err != nil {
+		return err
+	}
+	if err = r.expectEventCode(1, 0, "AT(Id)"); err != nil {
+		return err
+	}
+	res.EMAID.Id, err = r.readString()
+	if err != nil {
+		return err
+	}
+	res.EMAID.Value, err = decodeStringContent(r)
+	if err != nil {
+		return err
+	}
+	return r.expectEventCode(1, 0, "EE(CertificateInstallationRes)")
+}
+
+func decodeCertificateChain(r *bitReader, chain *CertificateChain) error {
+	code, err := r.readEventCode(2)
+	if err != nil {
+		return err
+	}
+	switch code {
+	case 0:
+		chain.Id, err = r.readString()
+		if err != nil {
+			return err
+		}
+		if err = r.expectEventCode(1, 0, "SE(Certificate)"); err != nil {
+			return err
+		}
+	case 1:
+	default:
+		return fmt.Errorf("unexpected event code %d in certificate chain: %w", code, errUnsupportedEvent)
+	}
+	chain.Certificate, err = decodeBinaryContent(r)
+	if err != nil {
+		return err
+	}
+	code, err = r.readEventCode(2)
+	if err != nil {
+		return err
+	}
+	switch code {
+	case 0:
+	case 1:
+		return nil
+	default:
+		return fmt.Errorf("unexpected event code %d in certificate chain: %w", code, errUnsupportedEvent)
+	}
+	if err = r.expectEventCode(1, 0, "SE(Certificate)"); err != nil {
+		return err
+	}
+	for {
+		certificate, err := decodeBinaryContent(r)
+		if err != nil {
+			return err
+		}
+		chain.SubCertificates = append(chain.SubCertificates, certificate)
+		if len(chain.SubCertificates) == maxSubCertificates {
+			if err = r.expectEventCode(1, 0, "EE(SubCertificates)"); err != nil {
+				return err
+			}
+			break
+		}
+		code, err = r.readEventCode(2)
+		if err != nil {
+			return err
+		}
+		if code == 1 {
+			break
+		}
+		if code != 0 {
+			return fmt.Errorf("unexpected event code %d in sub-certificates: %w", code, errUnsupportedEvent)
+		}
+	}
+	return r.expectEventCode(1, 0, "EE(CertificateChain)")
+}
+
+func decodeIdentifiedBinary(r *bitReader) (string, []byte, error) {
+	err := r.expectEventCode(1, 0, "AT(Id)")
+	if err != nil {
+		return "", nil, err
+	}
+	id, err := r.readString()
+	if err != nil {
+		return "", nil, err
+	}
+	value, err := decodeBinaryContent(r)
+	if err != nil {
+		return "", nil, err
+	}
+	return id, value, nil
+}
+
+func decodeBinaryContent(r *bitReader) ([]byte, error) {
+	err := r.expectEventCode(1, 0, "CH")
+	if err != nil {
+		return nil, err
+	}
+	value, err := r.readBinary()
+	if err != nil {
+		return nil, err
+	}
+	return value, r.expectEventCode(1, 0, "EE")
+}
+
+func decodeStringContent(r *bitReader) (string, error) {
+	err := r.expectEventCode(1, 0, "CH")
+	if err != nil {
+		return "", err
+	}
+	value, err := r.readString()
+	if err != nil {
+		return "", err
+	}
+	return value, r.expectEventCode(1, 0, "EE")
+}
+
+func decodeIntegerContent(r *bitReader) (*big.Int, error) {
+	err := r.expectEventCode(1, 0, "CH")
+	if err != nil {
+		return nil, err
+	}
+	value, err := r.readInteger()
+	if err != nil {
+		return nil, err
+	}
+	return value, r.expectEventCode(1, 0, "EE")
+}
+
+func decodeEnumerationContent[T any](r *bitReader, values []T) (T, error) {
+	var value T
+	err := r.expectEventCode(1, 0, "CH")
+	if err != nil {
+		return value, err
+	}
+	index, err := r.readBits(codeBits(len(values)))
+	if err != nil {
+		return value, err
+	}
+	if index >= uint64(len(values)) {
+		return value, fmt.Errorf("invalid enumeration index: %d", index)
+	}
+	return values[index], r.expectEventCode(1, 0, "EE")
+}
@@ -0,0 +1,8 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package iso15118 encodes and decodes the ISO 15118-2 messages that the CSMS
+// handles on behalf of an EV: the certificate installation messages that are
+// tunnelled through OCPP as EXI streams. Only the schema-informed EXI options
+// used by ISO 15118-2 (bit-packed, non-strict, no string table hits) are
+// supported.
+package iso15118
@@ -0,0 +1,441 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package iso15118
+
+import (
+	"fmt"
+	"math/big"
+)
+
+const (
+	maxRootCertificateIDs = 20
+	maxSubCertificates    = 4
+)
+
+// EncodeCertificateInstallationReq encodes a V2G message with a CertificateInstallationReq body
+func EncodeCertificateInstallationReq(msg *CertificateInstallationReqMessage) ([]byte, error) {
+	return encodeDocument(&msg.Header, qname{ns: nsMsgBody, local: "CertificateInstallationReq"}, func(w *bitWriter) error {
+		return encodeCertificateInstallationReq(w, &msg.Body)
+	})
+}
+
+// EncodeCertificateInstallationRes encodes a V2G message with a CertificateInstallationRes body
+func EncodeCertificateInstallationRes(msg *CertificateInstallationResMessage) ([]byte, error) {
+	return encodeDocument(&msg.Header, qname{ns: nsMsgBody, local: "CertificateInstallationRes"}, func(w *bitWriter) error {
+		return encodeCertificateInstallationRes(w, &msg.Body)
+	})
+}
+
+// encodeDocument encodes a V2G_Message document: the body is encoded by the provided
+// function, which writes the content of the body element (up to and including its EE)
+func encodeDocument(header *MessageHeader, body qname, encodeBody func(w *bitWriter) error) ([]byte, error) {
+	w := &bitWriter{}
+	w.writeBits(8, exiHeader)
+	// SE(V2G_Message)
+	w.writeEventCode(documentCodeBits, documentElements.code(qname{ns: nsMsgDef, local: "V2G_Message"}))
+	// SE(Header)
+	w.writeEventCode(1, 0)
+	err := encodeMessageHeader(w, header)
+	if err != nil {
+		return nil, err
+	}
+	// SE(Body)
+	w.writeEventCode(1, 0)
+	w.writeEventCode(bodyCodeBits, bodyElements.code(body))
+	err = encodeBody(w)
+	if err != nil {
+		return nil, err
+	}
+	// EE(Body), EE(V2G_Message): ED has a single production so takes no bits
+	w.writeEventCode(1, 0)
+	w.writeEventCode(1, 0)
+	return w.bytes(), nil
+}
+
+// encodeFragment encodes a single element as an EXI fragment: this is the canonical form
+// of the element that is digested (or signed) by an XML signature
+func encodeFragment(name qname, encodeContent func(w *bitWriter) error) ([]byte, error) {
+	w := &bitWriter{}
+	w.writeBits(8, exiHeader)
+	w.writeEventCode(fragmentCodeBits, fragmentElements.code(name))
+	err := encodeContent(w)
+	if err != nil {
+		return nil, err
+	}
+	// ED
+	w.writeEventCode(fragmentCodeBits, len(fragmentElements.names)+1)
+	return w.bytes(), nil
+}
+
+func encodeMessageHeader(w *bitWriter, header *MessageHeader) error {
+	if len(header.SessionID) > 8 {
+		return fmt.Errorf("session id is %d bytes: must be no more than 8", len(header.SessionID))
+	}
+	// SE(SessionID)
+	w.writeEventCode(1, 0)
+	encodeBinaryContent(w, header.SessionID)
+	if header.Notification != nil {
+		// SE(Notification)
+		w.writeEventCode(2, 0)
+		err := encodeNotification(w, header.Notification)
+		if err != nil {
+			return err
+		}
+		if header.Signature != nil {
+			// SE(Signature)
+			w.writeEventCode(2, 0)
+			err = encodeSignature(w, header.Signature)
+			if err != nil {
+				return err
+			}
+			// EE
+			w.writeEventCode(1, 0)
+		} else {
+			// EE
+			w.writeEventCode(2, 1)
+		}
+	} else if header.Signature != nil {
+		// SE(Signature)
+		w.writeEventCode(2, 1)
+		err := encodeSignature(w, header.Signature)
+		if err != nil {
+			return err
+		}
+		// EE
+		w.writeEventCode(1, 0)
+	} else {
+		// EE
+		w.writeEventCode(2, 2)
+	}
+	return nil
+}
+
+func encodeNotification(w *bitWriter, notification *Notification) error {
+	// SE(FaultCode)
+	w.writeEventCode(1, 0)
+	err := encodeEnumerationContent(w, faultCodes, notification.FaultCode)
+	if err != nil {
+		return err
+	}
+	if notification.FaultMsg != "" {
+		// SE(FaultMsg)
+		w.writeEventCode(2, 0)
+		encodeStringContent(w, notification.FaultMsg)
+		// EE
+		w.writeEventCode(1, 0)
+	} else {
+		// EE
+		w.writeEventCode(2, 1)
+	}
+	return nil
+}
+
+func encodeSignature(w *bitWriter, signature *Signature) error {
+	if signature.Id != "" {
+		// AT(Id), SE(SignedInfo)
+		w.writeEventCode(2, 0)
+		w.writeString(signature.Id)
+		w.writeEventCode(1, 0)
+	} else {
+		// SE(SignedInfo)
+		w.writeEventCode(2, 1)
+	}
+	err := encodeSignedInfo(w, &signature.SignedInfo)
+	if err != nil {
+		return err
+	}
+	// SE(SignatureValue), CH
+	w.writeEventCode(1, 0)
+	w.writeEventCode(2, 1)
+	w.writeBinary(signature.SignatureValue)
+	// EE(SignatureValue), EE
+	w.writeEventCode(1, 0)
+	w.writeEventCode(2, 2)
+	return nil
+}
+
+func encodeSignedInfo(w *bitWriter, signedInfo *SignedInfo) error {
+	if len(signedInfo.References) == 0 {
+		return fmt.Errorf("signed info must have at least one reference")
+	}
+	if signedInfo.Id != "" {
+		// AT(Id), SE(CanonicalizationMethod)
+		w.writeEventCode(2, 0)
+		w.writeString(signedInfo.Id)
+		w.writeEventCode(1, 0)
+	} else {
+		// SE(CanonicalizationMethod)
+		w.writeEventCode(2, 1)
+	}
+	// AT(Algorithm), EE
+	w.writeEventCode(1, 0)
+	w.writeString(signedInfo.CanonicalizationMethod)
+	w.writeEventCode(2, 1)
+	// SE(SignatureMethod), AT(Algorithm), EE
+	w.writeEventCode(1, 0)
+	w.writeEventCode(1, 0)
+	w.writeString(signedInfo.SignatureMethod)
+	w.writeEventCode(3, 2)
+	for i := range signedInfo.References {
+		if i == 0 {
+			// SE(Reference)
+			w.writeEventCode(1, 0)
+		} else {
+			// SE(Reference)
+			w.writeEventCode(2, 0)
+		}
+		err := encodeReference(w, &signedInfo.References[i])
+		if err != nil {
+			return err
+		}
+	}
+	// EE
+	w.writeEventCode(2, 1)
+	return nil
+}
+
+func encodeReference(w *bitWriter, reference *Reference) error {
+	// the attributes are optional, so the event code for each depends on which of the
+	// preceding attributes are present
+	codeBits, offset := 3, 0
+	if reference.Id != "" {
+		w.writeEventCode(codeBits, 0)
+		w.writeString(reference.Id)
+		codeBits, offset = 3, 1
+	}
+	if reference.Type != "" {
+		w.writeEventCode(codeBits, 1-offset)
+		w.writeString(reference.Type)
+		codeBits, offset = 2, 2
+	}
+	if reference.URI != "" {
+		w.writeEventCode(codeBits, 2-offset)
+		w.writeString(reference.URI)
+		codeBits, offset = 2, 3
+	}
+	if len(reference.Transforms) > 0 {
+		// SE(Transforms)
+		w.writeEventCode(codeBits, 3-offset)
+		for i, algorithm := range reference.Transforms {
+			if i == 0 {
+				// SE(Transform)
+				w.writeEventCode(1, 0)
+			} else {
+				// SE(Transform)
+				w.writeEventCode(2, 0)
+			}
+			// AT(Algorithm), EE
+			w.writeEventCode(1, 0)
+			w.writeString(algorithm)
+			w.writeEventCode(3, 2)
+		}
+		// EE(Transforms), SE(DigestMethod)
+		w.writeEventCode(2, 1)
+		w.writeEventCode(1, 0)
+	} else {
+		// SE(DigestMethod)
+		w.writeEventCode(codeBits, 4-offset)
+	}
+	// AT(Algorithm), EE
+	w.writeEventCode(1, 0)
+	w.writeString(reference.DigestMethod)
+	w.writeEventCode(2, 1)
+	// SE(DigestValue)
+	w.writeEventCode(1, 0)
+	encodeBinaryContent(w, reference.DigestValue)
+	// EE
+	w.writeEventCode(1, 0)
+	return nil
+}
+
+func encodeCertificateInstallationReq(w *bitWriter, req *CertificateInstallationReq) error {
+	if len(req.ListOfRootCertificateIDs) == 0 || len(req.ListOfRootCertificateIDs) > maxRootCertificateIDs {
+		return fmt.Errorf("list of root certificate ids has %d entries: must have between 1 and %d",
+			len(req.ListOfRootCertificateIDs), maxRootCertificateIDs)
+	}
+	// AT(Id)
+	w.writeEventCode(1, 0)
+	w.writeString(req.Id)
+	// SE(OEMProvisioningCert)
+	w.writeEventCode(1, 0)
+	encodeBinaryContent(w, req.OEMProvisioningCert)
+	// SE(ListOfRootCertificateIDs)
+	w.writeEventCode(1, 0)
+	for i := range req.ListOfRootCertificateIDs {
+		if i == 0 {
+			// SE(RootCertificateID)
+			w.writeEventCode(1, 0)
+		} else {
+			// SE(RootCertificateID)
+			w.writeEventCode(2, 0)
+		}
+		err := encodeX509IssuerSerial(w, &req.ListOfRootCertificateIDs[i])
+		if err != nil {
+			return err
+		}
+	}
+	// EE(ListOfRootCertificateIDs)
+	if len(req.ListOfRootCertificateIDs) == maxRootCertificateIDs {
+		w.writeEventCode(1, 0)
+	} else {
+		w.writeEventCode(2, 1)
+	}
+	// EE
+	w.writeEventCode(1, 0)
+	return nil
+}
+
+func encodeX509IssuerSerial(w *bitWriter, issuerSerial *X509IssuerSerial) error {
+	if issuerSerial.X509SerialNumber == nil {
+		return fmt.Errorf("root certificate id for %s has no serial number", issuerSerial.X509IssuerName)
+	}
+	// SE(X509IssuerName)
+	w.writeEventCode(1, 0)
+	encodeStringContent(w, issuerSerial.X509IssuerName)
+	// SE(X509SerialNumber)
+	w.writeEventCode(1, 0)
+	encodeIntegerContent(w, issuerSerial.X509SerialNumber)
+	// EE
+	w.writeEventCode(1, 0)
+	return nil
+}
+
+func encodeCertificateInstallationRes(w *bitWriter, res *CertificateInstallationRes) error {
+	// SE(ResponseCode)
+	w.writeEventCode(1, 0)
+	err := encodeEnumerationContent(w, responseCodes, res.ResponseCode)
+	if err != nil {
+		return err
+	}
+	// SE(SAProvisioningCertificateChain)
+	w.writeEventCode(1, 0)
+	err = encodeCertificateChain(w, &res.SAProvisioningCertificateChain)
+	if err != nil {
+		return err
+	}
+	// SE(ContractSignatureCertChain)
+	w.writeEventCode(1, 0)
+	err = encodeCertificateChain(w, &res.ContractSignatureCertChain)
+	if err != nil {
+		return err
+	}
+	// SE(ContractSignatureEncryptedPrivateKey)
+	w.writeEventCode(1, 0)
+	encodeIdentifiedBinary(w, res.ContractSignatureEncryptedPrivateKey.Id, res.ContractSignatureEncryptedPrivateKey.Value)
+	// SE(DHpublickey)
+	w.writeEventCode(1, 0)
+	encodeIdentifiedBinary(w, res.DHpublickey.Id, res.DHpublickey.Value)
+	// SE(eMAID)
+	w.writeEventCode(1, 0)
+	encodeEMAID(w, &res.EMAID)
+	// EE
+	w.writeEventCode(1, 0)
+	return nil
+}
+
+func encodeCertificateChain(w *bitWriter, chain *CertificateChain) error {
+	if len(chain.SubCertificates) > maxSubCertificates {
+		return fmt.Errorf("certificate chain has %d sub-certificates: must be no more than %d",
+			len(chain.SubCertificates), maxSubCertificates)
+	}
+	if chain.Id != "" {
+		// AT(Id), SE(Certificate)
+		w.writeEventCode(2, 0)
+		w.writeString(chain.Id)
+		w.writeEventCode(1, 0)
+	} else {
+		// SE(Certificate)
+		w.writeEventCode(2, 1)
+	}
+	encodeBinaryContent(w, chain.Certificate)
+	if len(chain.SubCertificates) == 0 {
+		// EE
+		w.writeEventCode(2, 1)
+		return nil
+	}
+	// SE(SubCertificates)
+	w.writeEventCode(2, 0)
+	for i, certificate := range chain.SubCertificates {
+		if i == 0 {
+			// SE(Certificate)
+			w.writeEventCode(1, 0)
+		} else {
+			// SE(Certificate)
+			w.writeEventCode(2, 0)
+		}
+		encodeBinaryContent(w, certificate)
+	}
+	// EE(SubCertificates)
+	if len(chain.SubCertificates) == maxSubCertificates {
+		w.writeEventCode(1, 0)
+	} else {
+		w.writeEventCode(2, 1)
+	}
+	// EE
+	w.writeEventCode(1, 0)
+	return nil
+}
+
+// encodeIdentifiedBinary encodes the content of an element with binary content and a
+// required Id attribute
+func encodeIdentifiedBinary(w *bitWriter, id string, value []byte) {
+	// AT(Id)
+	w.writeEventCode(1, 0)
+	w.writeString(id)
+	encodeBinaryContent(w, value)
+}
+
+func encodeEMAID(w *bitWriter, emaid *EMAID) {
+	// AT(Id)
+	w.writeEventCode(1, 0)
+	w.writeString(emaid.Id)
+	encodeStringContent(w, emaid.Value)
+}
+
+// encodeEMAIDFragment encodes the content of the eMAID element in a fragment. The eMAID
+// element has different types in different messages, so fragments use the relaxed grammar
+// with an untyped value.
+func encodeEMAIDFragment(w *bitWriter, emaid *EMAID) {
+	// AT(Id)
+	w.writeEventCode(3, 0)
+	w.writeString(emaid.Id)
+	// CH
+	w.writeEventCode(3, 4)
+	w.writeString(emaid.Value)
+	// EE
+	w.writeEventCode(2, 1)
+}
+
+// encodeBinaryContent encodes CH and EE for an element with binary content
+func encodeBinaryContent(w *bitWriter, value []byte) {
+	w.writeEventCode(1, 0)
+	w.writeBinary(value)
+	w.writeEventCode(1, 0)
+}
+
+// encodeStringContent encodes CH and EE for an element with string content
+func encodeStringContent(w *bitWriter, value string) {
+	w.writeEventCode(1, 0)
+	w.writeString(value)
+	w.writeEventCode(1, 0)
+}
+
+// encodeIntegerContent encodes CH and EE for an element with integer content
+func encodeIntegerContent(w *bitWriter, value *big.Int) {
+	w.writeEventCode(1, 0)
+	w.writeInteger(value)
+	w.writeEventCode(1, 0)
+}
+
+// encodeEnumerationContent encodes CH and EE for an element with enumerated content
+func encodeEnumerationContent[T comparable](w *bitWriter, values []T, value T) error {
+	for i, v := range values {
+		if v == value {
+			w.writeEventCode(1, 0)
+			w.writeBits(codeBits(len(values)), uint64(i))
+			w.writeEventCode(1, 0)
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid enumeration value: %v", value)
+}
@@ -0,0 +1,256 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package iso15118
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"math/bits"
+	"unicode/utf8"
+)
+
+// exiHeader is the EXI header used by ISO 15118-2: the distinguishing bits,
+// no options and EXI version 1
+const exiHeader = 0x80
+
+var (
+	errEndOfStream      = errors.New("unexpected end of EXI stream")
+	errUnsupportedEvent = errors.New("unsupported EXI event")
+)
+
+// codeBits returns the number of bits used to represent an event code for a
+// grammar state with the provided number of productions
+func codeBits(productions int) int {
+	if productions <= 1 {
+		return 0
+	}
+	return bits.Len(uint(productions - 1))
+}
+
+// bitWriter writes an EXI stream using the bit-packed alignment
+type bitWriter struct {
+	buf    []byte
+	unused int
+}
+
+func (w *bitWriter) writeBits(n int, value uint64) {
+	for i := n - 1; i >= 0; i-- {
+		if w.unused == 0 {
+			w.buf = append(w.buf, 0)
+			w.unused = 8
+		}
+		w.unused--
+		if (value>>uint(i))&1 == 1 {
+			w.buf[len(w.buf)-1] |= 1 << uint(w.unused)
+		}
+	}
+}
+
+func (w *bitWriter) writeEventCode(n int, code int) {
+	w.writeBits(n, uint64(code))
+}
+
+func (w *bitWriter) writeBoolean(value bool) {
+	if value {
+		w.writeBits(1, 1)
+	} else {
+		w.writeBits(1, 0)
+	}
+}
+
+func (w *bitWriter) writeUnsignedInteger(value uint64) {
+	for {
+		octet := value & 0x7f
+		value >>= 7
+		if value == 0 {
+			w.writeBits(8, octet)
+			return
+		}
+		w.writeBits(8, octet|0x80)
+	}
+}
+
+func (w *bitWriter) writeBigUnsignedInteger(value *big.Int) {
+	v := new(big.Int).Set(value)
+	mask := big.NewInt(0x7f)
+	for {
+		octet := new(big.Int).And(v, mask).Uint64()
+		v.Rsh(v, 7)
+		if v.Sign() == 0 {
+			w.writeBits(8, octet)
+			return
+		}
+		w.writeBits(8, octet|0x80)
+	}
+}
+
+func (w *bitWriter) writeInteger(value *big.Int) {
+	if value.Sign() < 0 {
+		w.writeBoolean(true)
+		magnitude := new(big.Int).Neg(value)
+		w.writeBigUnsignedInteger(magnitude.Sub(magnitude, big.NewInt(1)))
+	} else {
+		w.writeBoolean(false)
+		w.writeBigUnsignedInteger(value)
+	}
+}
+
+func (w *bitWriter) writeBinary(value []byte) {
+	w.writeUnsignedInteger(uint64(len(value)))
+	for _, b := range value {
+		w.writeBits(8, uint64(b))
+	}
+}
+
+// writeString writes a string literal: values are never added to (or looked up
+// in) the string table
+func (w *bitWriter) writeString(value string) {
+	runes := []rune(value)
+	w.writeUnsignedInteger(uint64(len(runes)) + 2)
+	for _, r := range runes {
+		w.writeUnsignedInteger(uint64(r))
+	}
+}
+
+func (w *bitWriter) bytes() []byte {
+	return w.buf
+}
+
+// bitReader reads an EXI stream that uses the bit-packed alignment
+type bitReader struct {
+	buf []byte
+	pos int
+}
+
+func (r *bitReader) remainingBits() int {
+	return len(r.buf)*8 - r.pos
+}
+
+func (r *bitReader) readBits(n int) (uint64, error) {
+	if n > r.remainingBits() {
+		return 0, errEndOfStream
+	}
+	var value uint64
+	for i := 0; i < n; i++ {
+		bit := (r.buf[r.pos/8] >> (7 - uint(r.pos%8))) & 1
+		value = value<<1 | uint64(bit)
+		r.pos++
+	}
+	return value, nil
+}
+
+func (r *bitReader) readEventCode(n int) (int, error) {
+	code, err := r.readBits(n)
+	return int(code), err
+}
+
+// expectEventCode reads an event code and returns an error if it does not
+// match the expected event
+func (r *bitReader) expectEventCode(n int, code int, event string) error {
+	actual, err := r.readEventCode(n)
+	if err != nil {
+		return err
+	}
+	if actual != code {
+		return fmt.Errorf("expected %s, got event code %d: %w", event, actual, errUnsupportedEvent)
+	}
+	return nil
+}
+
+func (r *bitReader) readBoolean() (bool, error) {
+	value, err := r.readBits(1)
+	return value == 1, err
+}
+
+func (r *bitReader) readUnsignedInteger() (uint64, error) {
+	var value uint64
+	for shift := 0; ; shift += 7 {
+		if shift > 56 {
+			return 0, fmt.Errorf("unsigned integer too large")
+		}
+		octet, err := r.readBits(8)
+		if err != nil {
+			return 0, err
+		}
+		value |= (octet & 0x7f) << uint(shift)
+		if octet&0x80 == 0 {
+			return value, nil
+		}
+	}
+}
+
+func (r *bitReader) readBigUnsignedInteger() (*big.Int, error) {
+	value := new(big.Int)
+	for shift := uint(0); ; shift += 7 {
+		octet, err := r.readBits(8)
+		if err != nil {
+			return nil, err
+		}
+		value.Or(value, new(big.Int).Lsh(big.NewInt(int64(octet&0x7f)), shift))
+		if octet&0x80 == 0 {
+			return value, nil
+		}
+	}
+}
+
+func (r *bitReader) readInteger() (*big.Int, error) {
+	negative, err := r.readBoolean()
+	if err != nil {
+		return nil, err
+	}
+	value, err := r.readBigUnsignedInteger()
+	if err != nil {
+		return nil, err
+	}
+	if negative {
+		value.Add(value, big.NewInt(1))
+		value.Neg(value)
+	}
+	return value, nil
+}
+
+func (r *bitReader) readBinary() ([]byte, error) {
+	length, err := r.readUnsignedInteger()
+	if err != nil {
+		return nil, err
+	}
+	if length > uint64(r.remainingBits()/8) {
+		return nil, errEndOfStream
+	}
+	value := make([]byte, length)
+	for i := range value {
+		b, err := r.readBits(8)
+		if err != nil {
+			return nil, err
+		}
+		value[i] = byte(b)
+	}
+	return value, nil
+}
+
+func (r *bitReader) readString() (string, error) {
+	length, err := r.readUnsignedInteger()
+	if err != nil {
+		return "", err
+	}
+	if length < 2 {
+		return "", fmt.Errorf("string table hits are not supported")
+	}
+	length -= 2
+	if length > uint64(r.remainingBits()/8) {
+		return "", errEndOfStream
+	}
+	runes := make([]rune, length)
+	for i := range runes {
+		codePoint, err := r.readUnsignedInteger()
+		if err != nil {
+			return "", err
+		}
+		if codePoint > utf8.MaxRune || !utf8.ValidRune(rune(codePoint)) {
+			return "", fmt.Errorf("invalid character %d in string", codePoint)
+		}
+		runes[i] = rune(codePoint)
+	}
+	return string(runes), nil
+}
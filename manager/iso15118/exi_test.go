@@ -0,0 +1,69 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package iso15118
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"testing"
+)
+
+func TestEventCodes(t *testing.T) {
+	assert.Len(t, documentElements.names, 80)
+	assert.Equal(t, 7, documentCodeBits)
+	assert.Equal(t, 76, documentElements.code(qname{ns: nsMsgDef, local: "V2G_Message"}))
+
+	assert.Len(t, fragmentElements.names, 243)
+	assert.Equal(t, 8, fragmentCodeBits)
+
+	assert.Equal(t, 6, bodyCodeBits)
+	assert.Equal(t, 5, bodyElements.code(qname{ns: nsMsgBody, local: "CertificateInstallationReq"}))
+	assert.Equal(t, 6, bodyElements.code(qname{ns: nsMsgBody, local: "CertificateInstallationRes"}))
+}
+
+func TestBitWriterAndReader(t *testing.T) {
+	w := &bitWriter{}
+	w.writeBits(3, 5)
+	w.writeBoolean(true)
+	w.writeUnsignedInteger(300)
+	w.writeInteger(big.NewInt(-129))
+	w.writeInteger(new(big.Int).Lsh(big.NewInt(1), 100))
+	w.writeBinary([]byte{0xde, 0xad})
+	w.writeString("héllo")
+
+	r := &bitReader{buf: w.bytes()}
+	value, err := r.readBits(3)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(5), value)
+	b, err := r.readBoolean()
+	require.NoError(t, err)
+	assert.True(t, b)
+	u, err := r.readUnsignedInteger()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(300), u)
+	i, err := r.readInteger()
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(-129), i)
+	i, err = r.readInteger()
+	require.NoError(t, err)
+	assert.Equal(t, new(big.Int).Lsh(big.NewInt(1), 100), i)
+	bin, err := r.readBinary()
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0xde, 0xad}, bin)
+	s, err := r.readString()
+	require.NoError(t, err)
+	assert.Equal(t, "héllo", s)
+
+	assert.Less(t, r.remainingBits(), 8)
+	_, err = r.readBits(8)
+	assert.ErrorIs(t, err, errEndOfStream)
+}
+
+func TestUnsignedIntegerEncoding(t *testing.T) {
+	w := &bitWriter{}
+	w.writeUnsignedInteger(0)
+	w.writeUnsignedInteger(127)
+	w.writeUnsignedInteger(128)
+	assert.Equal(t, []byte{0x00, 0x7f, 0x80, 0x01}, w.bytes())
+}
@@ -0,0 +1,190 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package iso15118
+
+import "math/big"
+
+const (
+	// AlgorithmCanonicalExi is the canonicalization (and transform) algorithm used for signatures
+	AlgorithmCanonicalExi = "http://www.w3.org/TR/canonical-exi/"
+	// AlgorithmSha256 is the digest algorithm used for signature references
+	AlgorithmSha256 = "http://www.w3.org/2001/04/xmlenc#sha256"
+	// AlgorithmEcdsaSha256 is the signature algorithm used for signatures
+	AlgorithmEcdsaSha256 = "http://www.w3.org/2001/04/xmldsig-more#ecdsa-sha256"
+)
+
+// FaultCode is the type of fault reported in a message header notification
+type FaultCode string
+
+var (
+	FaultCodeParsingError                 FaultCode = "ParsingError"
+	FaultCodeNoTLSRootCertificatAvailable FaultCode = "NoTLSRootCertificatAvailable"
+	FaultCodeUnknownError                 FaultCode = "UnknownError"
+)
+
+var faultCodes = []FaultCode{
+	FaultCodeParsingError,
+	FaultCodeNoTLSRootCertificatAvailable,
+	FaultCodeUnknownError,
+}
+
+// ResponseCode is the result reported by the SECC in a response message
+type ResponseCode string
+
+var (
+	ResponseCodeOK                                    ResponseCode = "OK"
+	ResponseCodeOKNewSessionEstablished               ResponseCode = "OK_NewSessionEstablished"
+	ResponseCodeOKOldSessionJoined                    ResponseCode = "OK_OldSessionJoined"
+	ResponseCodeOKCertificateExpiresSoon              ResponseCode = "OK_CertificateExpiresSoon"
+	ResponseCodeFailed                                ResponseCode = "FAILED"
+	ResponseCodeFailedSequenceError                   ResponseCode = "FAILED_SequenceError"
+	ResponseCodeFailedServiceIDInvalid                ResponseCode = "FAILED_ServiceIDInvalid"
+	ResponseCodeFailedUnknownSession                  ResponseCode = "FAILED_UnknownSession"
+	ResponseCodeFailedServiceSelectionInvalid         ResponseCode = "FAILED_ServiceSelectionInvalid"
+	ResponseCodeFailedPaymentSelectionInvalid         ResponseCode = "FAILED_PaymentSelectionInvalid"
+	ResponseCodeFailedCertificateExpired              ResponseCode = "FAILED_CertificateExpired"
+	ResponseCodeFailedSignatureError                  ResponseCode = "FAILED_SignatureError"
+	ResponseCodeFailedNoCertificateAvailable          ResponseCode = "FAILED_NoCertificateAvailable"
+	ResponseCodeFailedCertChainError                  ResponseCode = "FAILED_CertChainError"
+	ResponseCodeFailedChallengeInvalid                ResponseCode = "FAILED_ChallengeInvalid"
+	ResponseCodeFailedContractCanceled                ResponseCode = "FAILED_ContractCanceled"
+	ResponseCodeFailedWrongChargeParameter            ResponseCode = "FAILED_WrongChargeParameter"
+	ResponseCodeFailedPowerDeliveryNotApplied         ResponseCode = "FAILED_PowerDeliveryNotApplied"
+	ResponseCodeFailedTariffSelectionInvalid          ResponseCode = "FAILED_TariffSelectionInvalid"
+	ResponseCodeFailedChargingProfileInvalid          ResponseCode = "FAILED_ChargingProfileInvalid"
+	ResponseCodeFailedMeteringSignatureNotValid       ResponseCode = "FAILED_MeteringSignatureNotValid"
+	ResponseCodeFailedNoChargeServiceSelected         ResponseCode = "FAILED_NoChargeServiceSelected"
+	ResponseCodeFailedWrongEnergyTransferMode         ResponseCode = "FAILED_WrongEnergyTransferMode"
+	ResponseCodeFailedContactorError                  ResponseCode = "FAILED_ContactorError"
+	ResponseCodeFailedCertificateNotAllowedAtThisEVSE ResponseCode = "FAILED_CertificateNotAllowedAtThisEVSE"
+	ResponseCodeFailedCertificateRevoked              ResponseCode = "FAILED_CertificateRevoked"
+)
+
+var responseCodes = []ResponseCode{
+	ResponseCodeOK,
+	ResponseCodeOKNewSessionEstablished,
+	ResponseCodeOKOldSessionJoined,
+	ResponseCodeOKCertificateExpiresSoon,
+	ResponseCodeFailed,
+	ResponseCodeFailedSequenceError,
+	ResponseCodeFailedServiceIDInvalid,
+	ResponseCodeFailedUnknownSession,
+	ResponseCodeFailedServiceSelectionInvalid,
+	ResponseCodeFailedPaymentSelectionInvalid,
+	ResponseCodeFailedCertificateExpired,
+	ResponseCodeFailedSignatureError,
+	ResponseCodeFailedNoCertificateAvailable,
+	ResponseCodeFailedCertChainError,
+	ResponseCodeFailedChallengeInvalid,
+	ResponseCodeFailedContractCanceled,
+	ResponseCodeFailedWrongChargeParameter,
+	ResponseCodeFailedPowerDeliveryNotApplied,
+	ResponseCodeFailedTariffSelectionInvalid,
+	ResponseCodeFailedChargingProfileInvalid,
+	ResponseCodeFailedMeteringSignatureNotValid,
+	ResponseCodeFailedNoChargeServiceSelected,
+	ResponseCodeFailedWrongEnergyTransferMode,
+	ResponseCodeFailedContactorError,
+	ResponseCodeFailedCertificateNotAllowedAtThisEVSE,
+	ResponseCodeFailedCertificateRevoked,
+}
+
+// MessageHeader is the header of every V2G message
+type MessageHeader struct {
+	SessionID    []byte
+	Notification *Notification
+	Signature    *Signature
+}
+
+type Notification struct {
+	FaultCode FaultCode
+	FaultMsg  string
+}
+
+// Signature is an XML signature. Only the parts of the signature that are used by
+// ISO 15118-2 are supported: KeyInfo and Object are not.
+type Signature struct {
+	Id             string
+	SignedInfo     SignedInfo
+	SignatureValue []byte
+}
+
+type SignedInfo struct {
+	Id                     string
+	CanonicalizationMethod string
+	SignatureMethod        string
+	References             []Reference
+}
+
+type Reference struct {
+	Id           string
+	Type         string
+	URI          string
+	Transforms   []string
+	DigestMethod string
+	DigestValue  []byte
+}
+
+// X509IssuerSerial identifies a certificate by its issuer and serial number
+type X509IssuerSerial struct {
+	X509IssuerName   string
+	X509SerialNumber *big.Int
+}
+
+// CertificateInstallationReq is sent by the EV to request a contract certificate. It is
+// signed with the key of the OEM provisioning certificate.
+type CertificateInstallationReq struct {
+	Id                       string
+	OEMProvisioningCert      []byte
+	ListOfRootCertificateIDs []X509IssuerSerial
+}
+
+// CertificateChain is a DER encoded certificate and up to four DER encoded sub-CA certificates
+type CertificateChain struct {
+	Id              string
+	Certificate     []byte
+	SubCertificates [][]byte
+}
+
+// ContractSignatureEncryptedPrivateKey is the IV followed by the AES-128-CBC encrypted private
+// key of the contract certificate
+type ContractSignatureEncryptedPrivateKey struct {
+	Id    string
+	Value []byte
+}
+
+// DiffieHellmanPublickey is the uncompressed ephemeral public key used to derive the key that
+// encrypts the contract certificate private key
+type DiffieHellmanPublickey struct {
+	Id    string
+	Value []byte
+}
+
+type EMAID struct {
+	Id    string
+	Value string
+}
+
+// CertificateInstallationRes is returned to the EV with the contract certificate. The
+// ContractSignatureCertChain, ContractSignatureEncryptedPrivateKey, DHpublickey and EMAID are
+// signed with the key of the SA provisioning certificate.
+type CertificateInstallationRes struct {
+	ResponseCode                         ResponseCode
+	SAProvisioningCertificateChain       CertificateChain
+	ContractSignatureCertChain           CertificateChain
+	ContractSignatureEncryptedPrivateKey ContractSignatureEncryptedPrivateKey
+	DHpublickey                          DiffieHellmanPublickey
+	EMAID                                EMAID
+}
+
+// CertificateInstallationReqMessage is a V2G message with a CertificateInstallationReq body
+type CertificateInstallationReqMessage struct {
+	Header MessageHeader
+	Body   CertificateInstallationReq
+}
+
+// CertificateInstallationResMessage is a V2G message with a CertificateInstallationRes body
+type CertificateInstallationResMessage struct {
+	Header MessageHeader
+	Body   CertificateInstallationRes
+}
@@ -0,0 +1,202 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package iso15118
+
+import (
+	"fmt"
+	"sort"
+)
+
+const (
+	nsXmlDsig      = "http://www.w3.org/2000/09/xmldsig#"
+	nsMsgDef       = "urn:iso:15118:2:2013:MsgDef"
+	nsMsgHeader    = "urn:iso:15118:2:2013:MsgHeader"
+	nsMsgBody      = "urn:iso:15118:2:2013:MsgBody"
+	nsMsgDataTypes = "urn:iso:15118:2:2013:MsgDataTypes"
+)
+
+type qname struct {
+	ns    string
+	local string
+}
+
+// schemaElements lists the names of the elements that are declared in each namespace of the
+// ISO 15118-2 message schemas (V2G_CI_MsgDef.xsd and the schemas that it imports). The EXI
+// event codes for the start of a document, the start of a fragment and the members of the
+// BodyElement substitution group are derived from these lists.
+var schemaElements = []struct {
+	ns     string
+	global []string
+	local  []string
+}{
+	{
+		ns: nsXmlDsig,
+		global: []string{
+			"CanonicalizationMethod", "DSAKeyValue", "DigestMethod", "DigestValue", "KeyInfo", "KeyName",
+			"KeyValue", "Manifest", "MgmtData", "Object", "PGPData", "RSAKeyValue", "Reference",
+			"RetrievalMethod", "SPKIData", "Signature", "SignatureMethod", "SignatureProperties",
+			"SignatureProperty", "SignatureValue", "SignedInfo", "Transform", "Transforms", "X509Data",
+		},
+		local: []string{
+			"Exponent", "G", "HMACOutputLength", "J", "Modulus", "P", "PGPKeyID", "PGPKeyPacket",
+			"PgenCounter", "Q", "SPKISexp", "Seed", "X509CRL", "X509Certificate", "X509IssuerName",
+			"X509IssuerSerial", "X509SKI", "X509SerialNumber", "X509SubjectName", "XPath", "Y",
+		},
+	},
+	{
+		ns:     nsMsgDef,
+		global: []string{"V2G_Message"},
+		local:  []string{"Body", "Header"},
+	},
+	{
+		ns:    nsMsgHeader,
+		local: []string{"Notification", "SessionID"},
+	},
+	{
+		ns: nsMsgBody,
+		global: []string{
+			"AuthorizationReq", "AuthorizationRes", "BodyElement", "CableCheckReq", "CableCheckRes",
+			"CertificateInstallationReq", "CertificateInstallationRes", "CertificateUpdateReq",
+			"CertificateUpdateRes", "ChargeParameterDiscoveryReq", "ChargeParameterDiscoveryRes",
+			"ChargingStatusReq", "ChargingStatusRes", "CurrentDemandReq", "CurrentDemandRes",
+			"MeteringReceiptReq", "MeteringReceiptRes", "PaymentDetailsReq", "PaymentDetailsRes",
+			"PaymentServiceSelectionReq", "PaymentServiceSelectionRes", "PowerDeliveryReq",
+			"PowerDeliveryRes", "PreChargeReq", "PreChargeRes", "ServiceDetailReq", "ServiceDetailRes",
+			"ServiceDiscoveryReq", "ServiceDiscoveryRes", "SessionSetupReq", "SessionSetupRes",
+			"SessionStopReq", "SessionStopRes", "WeldingDetectionReq", "WeldingDetectionRes",
+		},
+		local: []string{
+			"AC_EVSEStatus", "BulkChargingComplete", "ChargeProgress", "ChargeService", "ChargingComplete",
+			"ChargingProfile", "ChargingSession", "ContractSignatureCertChain",
+			"ContractSignatureEncryptedPrivateKey", "DC_EVSEStatus", "DC_EVStatus", "DHpublickey", "EVCCID",
+			"EVMaximumCurrentLimit", "EVMaximumPowerLimit", "EVMaximumVoltageLimit",
+			"EVSECurrentLimitAchieved", "EVSEID", "EVSEMaxCurrent", "EVSEMaximumCurrentLimit",
+			"EVSEMaximumPowerLimit", "EVSEMaximumVoltageLimit", "EVSEPowerLimitAchieved",
+			"EVSEPresentCurrent", "EVSEPresentVoltage", "EVSEProcessing", "EVSETimeStamp",
+			"EVSEVoltageLimitAchieved", "EVTargetCurrent", "EVTargetVoltage", "GenChallenge",
+			"ListOfRootCertificateIDs", "MaxEntriesSAScheduleTuple", "MeterInfo", "OEMProvisioningCert",
+			"PaymentOptionList", "ReceiptRequired", "RemainingTimeToBulkSoC", "RemainingTimeToFullSoC",
+			"RequestedEnergyTransferMode", "ResponseCode", "RetryCounter", "SAProvisioningCertificateChain",
+			"SAScheduleTupleID", "SelectedPaymentOption", "SelectedServiceList", "ServiceCategory",
+			"ServiceID", "ServiceList", "ServiceParameterList", "ServiceScope", "SessionID", "eMAID",
+		},
+	},
+	{
+		ns: nsMsgDataTypes,
+		global: []string{
+			"AC_EVChargeParameter", "AC_EVSEChargeParameter", "AC_EVSEStatus", "DC_EVChargeParameter",
+			"DC_EVPowerDeliveryParameter", "DC_EVSEChargeParameter", "DC_EVSEStatus", "DC_EVStatus",
+			"EVChargeParameter", "EVPowerDeliveryParameter", "EVSEChargeParameter", "EVSEStatus", "EVStatus",
+			"Entry", "PMaxScheduleEntry", "RelativeTimeInterval", "SAScheduleList", "SASchedules",
+			"SalesTariffEntry", "TimeInterval",
+		},
+		local: []string{
+			"BulkChargingComplete", "BulkSOC", "Certificate", "ChargingComplete",
+			"ChargingProfileEntryMaxNumberOfPhasesInUse", "ChargingProfileEntryMaxPower",
+			"ChargingProfileEntryStart", "ConsumptionCost", "Cost", "DepartureTime", "EAmount", "EPriceLevel",
+			"EVEnergyCapacity", "EVEnergyRequest", "EVErrorCode", "EVMaxCurrent", "EVMaxVoltage",
+			"EVMaximumCurrentLimit", "EVMaximumPowerLimit", "EVMaximumVoltageLimit", "EVMinCurrent",
+			"EVRESSSOC", "EVReady", "EVSECurrentRegulationTolerance", "EVSEEnergyToBeDelivered",
+			"EVSEIsolationStatus", "EVSEMaxCurrent", "EVSEMaximumCurrentLimit", "EVSEMaximumPowerLimit",
+			"EVSEMaximumVoltageLimit", "EVSEMinimumCurrentLimit", "EVSEMinimumVoltageLimit",
+			"EVSENominalVoltage", "EVSENotification", "EVSEPeakCurrentRipple", "EVSEStatusCode",
+			"EnergyTransferMode", "FaultCode", "FaultMsg", "FreeService", "FullSOC", "MeterID", "MeterReading",
+			"MeterStatus", "Multiplier", "NotificationMaxDelay", "NumEPriceLevels", "PMax", "PMaxSchedule",
+			"Parameter", "ParameterSet", "ParameterSetID", "PaymentOption", "ProfileEntry", "RCD",
+			"RootCertificateID", "SAScheduleTuple", "SAScheduleTupleID", "SalesTariff",
+			"SalesTariffDescription", "SalesTariffID", "SelectedService", "Service", "ServiceCategory",
+			"ServiceID", "ServiceName", "ServiceScope", "SigMeterReading", "SubCertificates",
+			"SupportedEnergyTransferMode", "TMeter", "Unit", "Value", "amount", "amountMultiplier",
+			"boolValue", "byteValue", "costKind", "duration", "intValue", "physicalValue", "shortValue",
+			"start", "startValue", "stringValue",
+		},
+	},
+}
+
+// eventCodes assigns event codes to a set of element names: EXI sorts the names by local name
+// and then by namespace
+type eventCodes struct {
+	codes map[qname]int
+	names []qname
+}
+
+func newEventCodes(names []qname) *eventCodes {
+	sort.Slice(names, func(i, j int) bool {
+		if names[i].local != names[j].local {
+			return names[i].local < names[j].local
+		}
+		return names[i].ns < names[j].ns
+	})
+	e := &eventCodes{codes: make(map[qname]int), names: names}
+	for i, name := range names {
+		e.codes[name] = i
+	}
+	return e
+}
+
+func (e *eventCodes) code(name qname) int {
+	code, ok := e.codes[name]
+	if !ok {
+		panic(fmt.Sprintf("no event code for element {%s}%s", name.ns, name.local))
+	}
+	return code
+}
+
+func (e *eventCodes) name(code int) string {
+	if code < 0 || code >= len(e.names) {
+		return fmt.Sprintf("event code %d", code)
+	}
+	return e.names[code].local
+}
+
+var (
+	// documentElements are the global elements that may start an EXI document
+	documentElements *eventCodes
+	// fragmentElements are all the elements (global or local) that may start an EXI fragment
+	fragmentElements *eventCodes
+	// bodyElements are the members of the BodyElement substitution group
+	bodyElements *eventCodes
+
+	// documentCodeBits is the size of the document content event codes: SE for each global
+	// element and SE(*)
+	documentCodeBits int
+	// fragmentCodeBits is the size of the fragment content event codes: SE for each element,
+	// SE(*) and ED
+	fragmentCodeBits int
+	// bodyCodeBits is the size of the event codes for the first event in the Body: SE for each
+	// member of the substitution group, EE and the escape to the second level productions
+	bodyCodeBits int
+)
+
+func init() {
+	var global, all, body []qname
+	seen := make(map[qname]bool)
+	for _, schema := range schemaElements {
+		for _, local := range schema.global {
+			name := qname{ns: schema.ns, local: local}
+			global = append(global, name)
+			if schema.ns == nsMsgBody {
+				body = append(body, name)
+			}
+			if !seen[name] {
+				seen[name] = true
+				all = append(all, name)
+			}
+		}
+		for _, local := range schema.local {
+			name := qname{ns: schema.ns, local: local}
+			if !seen[name] {
+				seen[name] = true
+				all = append(all, name)
+			}
+		}
+	}
+
+	documentElements = newEventCodes(global)
+	fragmentElements = newEventCodes(all)
+	bodyElements = newEventCodes(body)
+
+	documentCodeBits = codeBits(len(global) + 1)
+	fragmentCodeBits = codeBits(len(all) + 2)
+	bodyCodeBits = codeBits(len(body) + 2)
+}
@@ -0,0 +1,211 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package iso15118
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// ErrInvalidSignature is returned when a message signature cannot be verified
+var ErrInvalidSignature = errors.New("invalid signature")
+
+// signedElement is an element that is referenced by a signature
+type signedElement struct {
+	id       string
+	fragment []byte
+}
+
+// SignCertificateInstallationReq signs the body of the request with the private key of the
+// OEM provisioning certificate
+func SignCertificateInstallationReq(msg *CertificateInstallationReqMessage, key *ecdsa.PrivateKey) error {
+	elements, err := certificateInstallationReqSignedElements(&msg.Body)
+	if err != nil {
+		return err
+	}
+	msg.Header.Signature, err = sign(elements, key)
+	return err
+}
+
+// VerifyCertificateInstallationReq verifies that the body of the request has been signed with
+// the private key that corresponds to the (OEM provisioning certificate) public key
+func VerifyCertificateInstallationReq(msg *CertificateInstallationReqMessage, publicKey *ecdsa.PublicKey) error {
+	elements, err := certificateInstallationReqSignedElements(&msg.Body)
+	if err != nil {
+		return err
+	}
+	return verify(msg.Header.Signature, elements, publicKey)
+}
+
+// SignCertificateInstallationRes signs the contract certificate chain, encrypted private key,
+// Diffie-Hellman public key and eMAID in the response with the private key of the SA
+// provisioning certificate
+func SignCertificateInstallationRes(msg *CertificateInstallationResMessage, key *ecdsa.PrivateKey) error {
+	elements, err := certificateInstallationResSignedElements(&msg.Body)
+	if err != nil {
+		return err
+	}
+	msg.Header.Signature, err = sign(elements, key)
+	return err
+}
+
+// VerifyCertificateInstallationRes verifies that the response has been signed with the private
+// key that corresponds to the (SA provisioning certificate) public key
+func VerifyCertificateInstallationRes(msg *CertificateInstallationResMessage, publicKey *ecdsa.PublicKey) error {
+	elements, err := certificateInstallationResSignedElements(&msg.Body)
+	if err != nil {
+		return err
+	}
+	return verify(msg.Header.Signature, elements, publicKey)
+}
+
+func certificateInstallationReqSignedElements(req *CertificateInstallationReq) ([]signedElement, error) {
+	fragment, err := encodeFragment(qname{ns: nsMsgBody, local: "CertificateInstallationReq"}, func(w *bitWriter) error {
+		return encodeCertificateInstallationReq(w, req)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return []signedElement{{id: req.Id, fragment: fragment}}, nil
+}
+
+func certificateInstallationResSignedElements(res *CertificateInstallationRes) ([]signedElement, error) {
+	chain, err := encodeFragment(qname{ns: nsMsgBody, local: "ContractSignatureCertChain"}, func(w *bitWriter) error {
+		return encodeCertificateChain(w, &res.ContractSignatureCertChain)
+	})
+	if err != nil {
+		return nil, err
+	}
+	privateKey, err := encodeFragment(qname{ns: nsMsgBody, local: "ContractSignatureEncryptedPrivateKey"}, func(w *bitWriter) error {
+		encodeIdentifiedBinary(w, res.ContractSignatureEncryptedPrivateKey.Id, res.ContractSignatureEncryptedPrivateKey.Value)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	publicKey, err := encodeFragment(qname{ns: nsMsgBody, local: "DHpublickey"}, func(w *bitWriter) error {
+		encodeIdentifiedBinary(w, res.DHpublickey.Id, res.DHpublickey.Value)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	emaid, err := encodeFragment(qname{ns: nsMsgBody, local: "eMAID"}, func(w *bitWriter) error {
+		encodeEMAIDFragment(w, &res.EMAID)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return []signedElement{
+		{id: res.ContractSignatureCertChain.Id, fragment: chain},
+		{id: res.ContractSignatureEncryptedPrivateKey.Id, fragment: privateKey},
+		{id: res.DHpublickey.Id, fragment: publicKey},
+		{id: res.EMAID.Id, fragment: emaid},
+	}, nil
+}
+
+func sign(elements []signedElement, key *ecdsa.PrivateKey) (*Signature, error) {
+	if key.Curve != elliptic.P256() {
+		return nil, fmt.Errorf("signing key must use the P-256 curve")
+	}
+	signature := &Signature{
+		SignedInfo: SignedInfo{
+			CanonicalizationMethod: AlgorithmCanonicalExi,
+			SignatureMethod:        AlgorithmEcdsaSha256,
+		},
+	}
+	for _, element := range elements {
+		if element.id == "" {
+			return nil, fmt.Errorf("signed elements must have an id")
+		}
+		digest := sha256.Sum256(element.fragment)
+		signature.SignedInfo.References = append(signature.SignedInfo.References, Reference{
+			URI:          "#" + element.id,
+			Transforms:   []string{AlgorithmCanonicalExi},
+			DigestMethod: AlgorithmSha256,
+			DigestValue:  digest[:],
+		})
+	}
+
+	digest, err := signedInfoDigest(&signature.SignedInfo)
+	if err != nil {
+		return nil, err
+	}
+	r, s, err := ecdsa.Sign(rand.Reader, key, digest)
+	if err != nil {
+		return nil, fmt.Errorf("signing: %w", err)
+	}
+	signature.SignatureValue = make([]byte, 64)
+	r.FillBytes(signature.SignatureValue[:32])
+	s.FillBytes(signature.SignatureValue[32:])
+	return signature, nil
+}
+
+func verify(signature *Signature, elements []signedElement, publicKey *ecdsa.PublicKey) error {
+	if signature == nil {
+		return fmt.Errorf("message is not signed: %w", ErrInvalidSignature)
+	}
+	if signature.SignedInfo.SignatureMethod != AlgorithmEcdsaSha256 {
+		return fmt.Errorf("unsupported signature method %s: %w", signature.SignedInfo.SignatureMethod, ErrInvalidSignature)
+	}
+	if len(signature.SignedInfo.References) != len(elements) {
+		return fmt.Errorf("expected %d references, got %d: %w",
+			len(elements), len(signature.SignedInfo.References), ErrInvalidSignature)
+	}
+	for _, element := range elements {
+		reference := findReference(signature.SignedInfo.References, "#"+element.id)
+		if reference == nil {
+			return fmt.Errorf("no reference to #%s: %w", element.id, ErrInvalidSignature)
+		}
+		if reference.DigestMethod != AlgorithmSha256 {
+			return fmt.Errorf("unsupported digest method %s: %w", reference.DigestMethod, ErrInvalidSignature)
+		}
+		digest := sha256.Sum256(element.fragment)
+		if !bytes.Equal(digest[:], reference.DigestValue) {
+			return fmt.Errorf("digest of #%s does not match: %w", element.id, ErrInvalidSignature)
+		}
+	}
+
+	if len(signature.SignatureValue) != 64 {
+		return fmt.Errorf("signature value is %d bytes: %w", len(signature.SignatureValue), ErrInvalidSignature)
+	}
+	digest, err := signedInfoDigest(&signature.SignedInfo)
+	if err != nil {
+		return err
+	}
+	r := new(big.Int).SetBytes(signature.SignatureValue[:32])
+	s := new(big.Int).SetBytes(signature.SignatureValue[32:])
+	if !ecdsa.Verify(publicKey, digest, r, s) {
+		return fmt.Errorf("signature does not match: %w", ErrInvalidSignature)
+	}
+	return nil
+}
+
+func findReference(references []Reference, uri string) *Reference {
+	for i := range references {
+		if references[i].URI == uri {
+			return &references[i]
+		}
+	}
+	return nil
+}
+
+// signedInfoDigest returns the SHA-256 digest of the canonical (EXI fragment) form of the
+// signed info
+func signedInfoDigest(signedInfo *SignedInfo) ([]byte, error) {
+	fragment, err := encodeFragment(qname{ns: nsXmlDsig, local: "SignedInfo"}, func(w *bitWriter) error {
+		return encodeSignedInfo(w, signedInfo)
+	})
+	if err != nil {
+		return nil, err
+	}
+	digest := sha256.Sum256(fragment)
+	return digest[:], nil
+}
@@ -0,0 +1,290 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/thoughtworks/maeve-csms/manager/handlers"
+	"github.com/thoughtworks/maeve-csms/manager/ocpp"
+	"github.com/thoughtworks/maeve-csms/manager/ocpp/ocpp16"
+	"github.com/thoughtworks/maeve-csms/manager/ocpp/ocpp201"
+	"github.com/thoughtworks/maeve-csms/manager/store"
+	"golang.org/x/exp/slog"
+	"hash/crc32"
+	"net/http"
+)
+
+// chargingProfileTimeout is the number of seconds that the OCPI party is told to wait for
+// the asynchronous result of a charging profile request
+const chargingProfileTimeout = 30
+
+func (o *OCPI) SetChargingProfile(ctx context.Context, countryCode, partyId, sessionId string, setChargingProfile SetChargingProfile) (*ChargingProfileResponse, error) {
+	transaction, ocppVersion, err := o.lookupSession(ctx, sessionId)
+	if err != nil {
+		return nil, err
+	}
+	if transaction == nil {
+		return &ChargingProfileResponse{Result: ChargingProfileResponseResultUNKNOWNSESSION}, nil
+	}
+
+	var req ocpp.Request
+	if ocppVersion == "1.6" {
+		req = newOcpp16SetChargingProfile(transaction, setChargingProfile.ChargingProfile)
+	} else {
+		req = newOcpp201SetChargingProfile(transaction, setChargingProfile.ChargingProfile)
+	}
+
+	return o.sendChargingProfileRequest(ctx, &store.OcpiChargingProfileRequest{
+		ChargeStationId: transaction.ChargeStationId,
+		EvseId:          transaction.EvseId,
+		Type:            store.OcpiChargingProfileRequestTypeSet,
+		SessionId:       sessionId,
+		CountryCode:     countryCode,
+		PartyId:         partyId,
+		ResponseUrl:     setChargingProfile.ResponseUrl,
+	}, ocppVersion, req)
+}
+
+func (o *OCPI) GetChargingProfile(ctx context.Context, countryCode, partyId, sessionId string, duration int, responseUrl string) (*ChargingProfileResponse, error) {
+	transaction, ocppVersion, err := o.lookupSession(ctx, sessionId)
+	if err != nil {
+		return nil, err
+	}
+	if transaction == nil {
+		return &ChargingProfileResponse{Result: ChargingProfileResponseResultUNKNOWNSESSION}, nil
+	}
+
+	var req ocpp.Request
+	if ocppVersion == "1.6" {
+		req = &ocpp16.GetCompositeScheduleJson{
+			ConnectorId: transaction.EvseId,
+			Duration:    duration,
+		}
+	} else {
+		req = &ocpp201.GetCompositeScheduleRequestJson{
+			EvseId:   transaction.EvseId,
+			Duration: duration,
+		}
+	}
+
+	return o.sendChargingProfileRequest(ctx, &store.OcpiChargingProfileRequest{
+		ChargeStationId: transaction.ChargeStationId,
+		EvseId:          transaction.EvseId,
+		Type:            store.OcpiChargingProfileRequestTypeGet,
+		SessionId:       sessionId,
+		CountryCode:     countryCode,
+		PartyId:         partyId,
+		ResponseUrl:     responseUrl,
+	}, ocppVersion, req)
+}
+
+func (o *OCPI) ClearChargingProfile(ctx context.Context, countryCode, partyId, sessionId string, responseUrl string) (*ChargingProfileResponse, error) {
+	transaction, ocppVersion, err := o.lookupSession(ctx, sessionId)
+	if err != nil {
+		return nil, err
+	}
+	if transaction == nil {
+		return &ChargingProfileResponse{Result: ChargingProfileResponseResultUNKNOWNSESSION}, nil
+	}
+
+	var req ocpp.Request
+	if ocppVersion == "1.6" {
+		purpose := ocpp16.ClearChargingProfileJsonChargingProfilePurposeTxProfile
+		req = &ocpp16.ClearChargingProfileJson{
+			ConnectorId:            &transaction.EvseId,
+			ChargingProfilePurpose: &purpose,
+		}
+	} else {
+		purpose := ocpp201.ChargingProfilePurposeEnumTypeTxProfile
+		req = &ocpp201.ClearChargingProfileRequestJson{
+			ChargingProfileCriteria: &ocpp201.ClearChargingProfileType{
+				EvseId:                 &transaction.EvseId,
+				ChargingProfilePurpose: &purpose,
+			},
+		}
+	}
+
+	return o.sendChargingProfileRequest(ctx, &store.OcpiChargingProfileRequest{
+		ChargeStationId: transaction.ChargeStationId,
+		EvseId:          transaction.EvseId,
+		Type:            store.OcpiChargingProfileRequestTypeClear,
+		SessionId:       sessionId,
+		CountryCode:     countryCode,
+		PartyId:         partyId,
+		ResponseUrl:     responseUrl,
+	}, ocppVersion, req)
+}
+
+func (o *OCPI) ReportChargingProfileResult(ctx context.Context, chargeStationId string, evseId int, requestType store.OcpiChargingProfileRequestType, result GenericChargingProfileResult) error {
+	request, err := o.store.LookupChargingProfileRequest(ctx, chargeStationId, evseId, requestType)
+	if err != nil {
+		return err
+	}
+	if request == nil {
+		// not an OCPI initiated request
+		return nil
+	}
+
+	err = o.store.DeleteChargingProfileRequest(ctx, chargeStationId, evseId, requestType)
+	if err != nil {
+		return err
+	}
+
+	return o.enqueue(ctx, request.CountryCode, request.PartyId, "", http.MethodPost, request.ResponseUrl, result)
+}
+
+func (o *OCPI) lookupSession(ctx context.Context, sessionId string) (*store.Transaction, string, error) {
+	transaction, err := o.store.FindTransactionById(ctx, sessionId)
+	if err != nil {
+		return nil, "", err
+	}
+	if transaction == nil {
+		return nil, "", nil
+	}
+
+	details, err := o.store.LookupChargeStationRuntimeDetails(ctx, transaction.ChargeStationId)
+	if err != nil {
+		return nil, "", err
+	}
+	if details == nil {
+		return nil, "", fmt.Errorf("no runtime details for charge station %s", transaction.ChargeStationId)
+	}
+
+	return transaction, details.OcppVersion, nil
+}
+
+func (o *OCPI) lookupParty(ctx context.Context, countryCode, partyId string) (*store.OcpiParty, error) {
+	for _, role := range []CredentialsRoleRole{CredentialsRoleRoleEMSP, CredentialsRoleRoleSCSP} {
+		party, err := o.store.GetPartyDetails(ctx, string(role), countryCode, partyId)
+		if err != nil {
+			return nil, err
+		}
+		if party != nil {
+			return party, nil
+		}
+	}
+	return nil, nil
+}
+
+func (o *OCPI) sendChargingProfileRequest(ctx context.Context, request *store.OcpiChargingProfileRequest, ocppVersion string, req ocpp.Request) (*ChargingProfileResponse, error) {
+	if o.v16CallMaker == nil || o.v201CallMaker == nil || o.v21CallMaker == nil {
+		return nil, errors.New("no call makers configured")
+	}
+
+	err := o.store.SetChargingProfileRequest(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	err = handlers.CallMakerForVersion(ocppVersion, o.v16CallMaker, o.v201CallMaker, o.v21CallMaker).
+		Send(ctx, request.ChargeStationId, req)
+	if err != nil {
+		slog.Error("error sending charging profile request", "err", err, "chargeStationId", request.ChargeStationId)
+		// the charge station will not respond, so the request must not be left pending
+		err = o.store.DeleteChargingProfileRequest(ctx, request.ChargeStationId, request.EvseId, request.Type)
+		if err != nil {
+			return nil, err
+		}
+		return &ChargingProfileResponse{Result: ChargingProfileResponseResultREJECTED}, nil
+	}
+
+	return &ChargingProfileResponse{
+		Result:  ChargingProfileResponseResultACCEPTED,
+		Timeout: chargingProfileTimeout,
+	}, nil
+}
+
+// chargingProfileId derives a stable OCPP charging profile id from the session id so that
+// repeated requests for the same session replace the existing profile
+func chargingProfileId(sessionId string) int {
+	return int(crc32.ChecksumIEEE([]byte(sessionId)) & 0x7fffffff)
+}
+
+func newOcpp16SetChargingProfile(transaction *store.Transaction, profile ChargingProfile) *ocpp16.SetChargingProfileJson {
+	kind := ocpp16.SetChargingProfileJsonCsChargingProfilesChargingProfileKindRelative
+	if profile.StartDateTime != nil {
+		kind = ocpp16.SetChargingProfileJsonCsChargingProfilesChargingProfileKindAbsolute
+	}
+
+	var periods []ocpp16.SetChargingProfileJsonCsChargingProfilesChargingScheduleChargingSchedulePeriodElem
+	if profile.ChargingProfilePeriod != nil {
+		for _, period := range *profile.ChargingProfilePeriod {
+			periods = append(periods, ocpp16.SetChargingProfileJsonCsChargingProfilesChargingScheduleChargingSchedulePeriodElem{
+				StartPeriod: int(period.StartPeriod),
+				Limit:       float64(period.Limit),
+			})
+		}
+	}
+
+	return &ocpp16.SetChargingProfileJson{
+		ConnectorId: transaction.EvseId,
+		CsChargingProfiles: ocpp16.SetChargingProfileJsonCsChargingProfiles{
+			ChargingProfileId:      chargingProfileId(transaction.TransactionId),
+			ChargingProfileKind:    kind,
+			ChargingProfilePurpose: ocpp16.SetChargingProfileJsonCsChargingProfilesChargingProfilePurposeTxProfile,
+			ChargingSchedule: ocpp16.SetChargingProfileJsonCsChargingProfilesChargingSchedule{
+				ChargingRateUnit:       ocpp16.SetChargingProfileJsonCsChargingProfilesChargingScheduleChargingRateUnit(profile.ChargingRateUnit),
+				ChargingSchedulePeriod: periods,
+				Duration:               int32PtrToIntPtr(profile.Duration),
+				MinChargingRate:        float32PtrToFloat64Ptr(profile.MinChargingRate),
+				StartSchedule:          profile.StartDateTime,
+			},
+		},
+	}
+}
+
+func newOcpp201SetChargingProfile(transaction *store.Transaction, profile ChargingProfile) *ocpp201.SetChargingProfileRequestJson {
+	kind := ocpp201.ChargingProfileKindEnumTypeRelative
+	if profile.StartDateTime != nil {
+		kind = ocpp201.ChargingProfileKindEnumTypeAbsolute
+	}
+
+	var periods []ocpp201.ChargingSchedulePeriodType
+	if profile.ChargingProfilePeriod != nil {
+		for _, period := range *profile.ChargingProfilePeriod {
+			periods = append(periods, ocpp201.ChargingSchedulePeriodType{
+				StartPeriod: int(period.StartPeriod),
+				Limit:       float64(period.Limit),
+			})
+		}
+	}
+
+	id := chargingProfileId(transaction.TransactionId)
+	return &ocpp201.SetChargingProfileRequestJson{
+		EvseId: transaction.EvseId,
+		ChargingProfile: ocpp201.ChargingProfileType{
+			Id:                     id,
+			ChargingProfileKind:    kind,
+			ChargingProfilePurpose: ocpp201.ChargingProfilePurposeEnumTypeTxProfile,
+			TransactionId:          &transaction.TransactionId,
+			ChargingSchedule: []ocpp201.ChargingScheduleType{
+				{
+					Id:                     id,
+					ChargingRateUnit:       ocpp201.ChargingRateUnitEnumType(profile.ChargingRateUnit),
+					ChargingSchedulePeriod: periods,
+					Duration:               int32PtrToIntPtr(profile.Duration),
+					MinChargingRate:        float32PtrToFloat64Ptr(profile.MinChargingRate),
+					StartSchedule:          profile.StartDateTime,
+				},
+			},
+		},
+	}
+}
+
+func int32PtrToIntPtr(in *int32) *int {
+	if in == nil {
+		return nil
+	}
+	out := int(*in)
+	return &out
+}
+
+func float32PtrToFloat64Ptr(in *float32) *float64 {
+	if in == nil {
+		return nil
+	}
+	out := float64(*in)
+	return &out
+}
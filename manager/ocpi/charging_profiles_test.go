@@ -0,0 +1,235 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpi_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thoughtworks/maeve-csms/manager/ocpi"
+	"github.com/thoughtworks/maeve-csms/manager/ocpp"
+	"github.com/thoughtworks/maeve-csms/manager/ocpp/ocpp16"
+	"github.com/thoughtworks/maeve-csms/manager/ocpp/ocpp201"
+	"github.com/thoughtworks/maeve-csms/manager/store"
+	"github.com/thoughtworks/maeve-csms/manager/store/inmemory"
+	"k8s.io/utils/clock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type recordingCallMaker struct {
+	chargeStationId string
+	request         ocpp.Request
+	err             error
+}
+
+func (r *recordingCallMaker) Send(_ context.Context, chargeStationId string, request ocpp.Request) error {
+	r.chargeStationId = chargeStationId
+	r.request = request
+	return r.err
+}
+
+func setupChargingProfileTest(t *testing.T, ocppVersion string) (*ocpi.OCPI, store.Engine, *recordingCallMaker, *recordingCallMaker) {
+	ctx := context.Background()
+	engine := inmemory.NewStore(clock.RealClock{})
+	err := engine.SetChargeStationRuntimeDetails(ctx, "cs001", &store.ChargeStationRuntimeDetails{
+		OcppVersion: ocppVersion,
+	})
+	require.NoError(t, err)
+	err = engine.CreateTransaction(ctx, "cs001", "session001", "DEADBEEF", "ISO14443", nil, 0, false)
+	require.NoError(t, err)
+	err = engine.SetTransactionEvseId(ctx, "cs001", "session001", 2)
+	require.NoError(t, err)
+
+	ocpiApi := ocpi.NewOCPI(engine, http.DefaultClient, "GB", "TWK")
+	v16CallMaker := new(recordingCallMaker)
+	v201CallMaker := new(recordingCallMaker)
+	ocpiApi.SetCallMakers(v16CallMaker, v201CallMaker, new(recordingCallMaker))
+
+	return ocpiApi, engine, v16CallMaker, v201CallMaker
+}
+
+func TestSetChargingProfileWithOcpp16(t *testing.T) {
+	ocpiApi, engine, v16CallMaker, _ := setupChargingProfileTest(t, "1.6")
+
+	startDateTime := "2023-06-15T15:05:00Z"
+	got, err := ocpiApi.SetChargingProfile(context.Background(), "GB", "EMS", "session001", ocpi.SetChargingProfile{
+		ResponseUrl: "https://example.com/chargingprofiles/result/1",
+		ChargingProfile: ocpi.ChargingProfile{
+			ChargingRateUnit: ocpi.A,
+			StartDateTime:    &startDateTime,
+			ChargingProfilePeriod: &[]ocpi.ChargingProfilePeriod{
+				{StartPeriod: 0, Limit: 16},
+				{StartPeriod: 600, Limit: 8},
+			},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, ocpi.ChargingProfileResponseResultACCEPTED, got.Result)
+	assert.Equal(t, int32(30), got.Timeout)
+
+	assert.Equal(t, "cs001", v16CallMaker.chargeStationId)
+	req, ok := v16CallMaker.request.(*ocpp16.SetChargingProfileJson)
+	require.True(t, ok)
+	assert.Equal(t, 2, req.ConnectorId)
+	assert.Equal(t, ocpp16.SetChargingProfileJsonCsChargingProfilesChargingProfilePurposeTxProfile, req.CsChargingProfiles.ChargingProfilePurpose)
+	assert.Equal(t, ocpp16.SetChargingProfileJsonCsChargingProfilesChargingProfileKindAbsolute, req.CsChargingProfiles.ChargingProfileKind)
+	assert.Equal(t, &startDateTime, req.CsChargingProfiles.ChargingSchedule.StartSchedule)
+	assert.Equal(t, []ocpp16.SetChargingProfileJsonCsChargingProfilesChargingScheduleChargingSchedulePeriodElem{
+		{StartPeriod: 0, Limit: 16},
+		{StartPeriod: 600, Limit: 8},
+	}, req.CsChargingProfiles.ChargingSchedule.ChargingSchedulePeriod)
+
+	pending, err := engine.LookupChargingProfileRequest(context.Background(), "cs001", 2, store.OcpiChargingProfileRequestTypeSet)
+	require.NoError(t, err)
+	require.NotNil(t, pending)
+	assert.Equal(t, "https://example.com/chargingprofiles/result/1", pending.ResponseUrl)
+	assert.Equal(t, "GB", pending.CountryCode)
+	assert.Equal(t, "EMS", pending.PartyId)
+}
+
+func TestSetChargingProfileWithOcpp201(t *testing.T) {
+	ocpiApi, _, _, v201CallMaker := setupChargingProfileTest(t, "2.0.1")
+
+	got, err := ocpiApi.SetChargingProfile(context.Background(), "GB", "EMS", "session001", ocpi.SetChargingProfile{
+		ResponseUrl: "https://example.com/chargingprofiles/result/1",
+		ChargingProfile: ocpi.ChargingProfile{
+			ChargingRateUnit: ocpi.W,
+			ChargingProfilePeriod: &[]ocpi.ChargingProfilePeriod{
+				{StartPeriod: 0, Limit: 11000},
+			},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, ocpi.ChargingProfileResponseResultACCEPTED, got.Result)
+
+	req, ok := v201CallMaker.request.(*ocpp201.SetChargingProfileRequestJson)
+	require.True(t, ok)
+	assert.Equal(t, 2, req.EvseId)
+	assert.Equal(t, ocpp201.ChargingProfilePurposeEnumTypeTxProfile, req.ChargingProfile.ChargingProfilePurpose)
+	assert.Equal(t, ocpp201.ChargingProfileKindEnumTypeRelative, req.ChargingProfile.ChargingProfileKind)
+	require.NotNil(t, req.ChargingProfile.TransactionId)
+	assert.Equal(t, "session001", *req.ChargingProfile.TransactionId)
+	require.Len(t, req.ChargingProfile.ChargingSchedule, 1)
+	assert.Equal(t, ocpp201.ChargingRateUnitEnumTypeW, req.ChargingProfile.ChargingSchedule[0].ChargingRateUnit)
+	assert.Equal(t, []ocpp201.ChargingSchedulePeriodType{{StartPeriod: 0, Limit: 11000}}, req.ChargingProfile.ChargingSchedule[0].ChargingSchedulePeriod)
+}
+
+func TestSetChargingProfileWhenSendFails(t *testing.T) {
+	ocpiApi, engine, v16CallMaker, _ := setupChargingProfileTest(t, "1.6")
+	v16CallMaker.err = errors.New("send failed")
+
+	got, err := ocpiApi.SetChargingProfile(context.Background(), "GB", "EMS", "session001", ocpi.SetChargingProfile{
+		ResponseUrl: "https://example.com/chargingprofiles/result/1",
+		ChargingProfile: ocpi.ChargingProfile{
+			ChargingRateUnit: ocpi.A,
+			ChargingProfilePeriod: &[]ocpi.ChargingProfilePeriod{
+				{StartPeriod: 0, Limit: 16},
+			},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, ocpi.ChargingProfileResponseResultREJECTED, got.Result)
+
+	pending, err := engine.LookupChargingProfileRequest(context.Background(), "cs001", 2, store.OcpiChargingProfileRequestTypeSet)
+	require.NoError(t, err)
+	assert.Nil(t, pending)
+}
+
+func TestSetChargingProfileWithUnknownSession(t *testing.T) {
+	ocpiApi, _, v16CallMaker, _ := setupChargingProfileTest(t, "1.6")
+
+	got, err := ocpiApi.SetChargingProfile(context.Background(), "GB", "EMS", "unknown", ocpi.SetChargingProfile{
+		ResponseUrl: "https://example.com/chargingprofiles/result/1",
+		ChargingProfile: ocpi.ChargingProfile{
+			ChargingRateUnit: ocpi.A,
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, ocpi.ChargingProfileResponseResultUNKNOWNSESSION, got.Result)
+	assert.Nil(t, v16CallMaker.request)
+}
+
+func TestGetChargingProfile(t *testing.T) {
+	ocpiApi, _, _, v201CallMaker := setupChargingProfileTest(t, "2.0.1")
+
+	got, err := ocpiApi.GetChargingProfile(context.Background(), "GB", "EMS", "session001", 3600, "https://example.com/chargingprofiles/result/2")
+	require.NoError(t, err)
+	assert.Equal(t, ocpi.ChargingProfileResponseResultACCEPTED, got.Result)
+
+	want := &ocpp201.GetCompositeScheduleRequestJson{
+		EvseId:   2,
+		Duration: 3600,
+	}
+	assert.Equal(t, want, v201CallMaker.request)
+}
+
+func TestClearChargingProfile(t *testing.T) {
+	ocpiApi, _, v16CallMaker, _ := setupChargingProfileTest(t, "1.6")
+
+	got, err := ocpiApi.ClearChargingProfile(context.Background(), "GB", "EMS", "session001", "https://example.com/chargingprofiles/result/3")
+	require.NoError(t, err)
+	assert.Equal(t, ocpi.ChargingProfileResponseResultACCEPTED, got.Result)
+
+	connectorId := 2
+	purpose := ocpp16.ClearChargingProfileJsonChargingProfilePurposeTxProfile
+	want := &ocpp16.ClearChargingProfileJson{
+		ConnectorId:            &connectorId,
+		ChargingProfilePurpose: &purpose,
+	}
+	assert.Equal(t, want, v16CallMaker.request)
+}
+
+func TestReportChargingProfileResult(t *testing.T) {
+	ocpiApi, engine, _, _ := setupChargingProfileTest(t, "1.6")
+	ctx := context.Background()
+
+	var got ocpi.GenericChargingProfileResult
+	var gotAuthz string
+	receiverServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuthz = r.Header.Get("Authorization")
+		err := json.NewDecoder(r.Body).Decode(&got)
+		require.NoError(t, err)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer receiverServer.Close()
+
+	err := engine.SetPartyDetails(ctx, &store.OcpiParty{
+		Role:        "EMSP",
+		CountryCode: "GB",
+		PartyId:     "EMS",
+		Url:         receiverServer.URL,
+		Token:       "abc123",
+	})
+	require.NoError(t, err)
+
+	_, err = ocpiApi.SetChargingProfile(ctx, "GB", "EMS", "session001", ocpi.SetChargingProfile{
+		ResponseUrl: receiverServer.URL + "/chargingprofiles/result/1",
+		ChargingProfile: ocpi.ChargingProfile{
+			ChargingRateUnit: ocpi.A,
+		},
+	})
+	require.NoError(t, err)
+
+	err = ocpiApi.ReportChargingProfileResult(ctx, "cs001", 2, store.OcpiChargingProfileRequestTypeSet,
+		ocpi.GenericChargingProfileResult{Result: ocpi.GenericChargingProfileResultResultACCEPTED})
+	require.NoError(t, err)
+
+	assert.Equal(t, "Token abc123", gotAuthz)
+	assert.Equal(t, ocpi.GenericChargingProfileResultResultACCEPTED, got.Result)
+
+	pending, err := engine.LookupChargingProfileRequest(ctx, "cs001", 2, store.OcpiChargingProfileRequestTypeSet)
+	require.NoError(t, err)
+	assert.Nil(t, pending)
+}
+
+func TestReportChargingProfileResultWithoutPendingRequest(t *testing.T) {
+	ocpiApi, _, _, _ := setupChargingProfileTest(t, "1.6")
+
+	err := ocpiApi.ReportChargingProfileResult(context.Background(), "cs001", 2, store.OcpiChargingProfileRequestTypeSet,
+		ocpi.GenericChargingProfileResult{Result: ocpi.GenericChargingProfileResultResultACCEPTED})
+	require.NoError(t, err)
+}
@@ -0,0 +1,225 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/thoughtworks/maeve-csms/manager/store"
+	"golang.org/x/exp/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// deliveryMaxAttempts is the number of times a delivery is attempted before it is
+	// dead-lettered
+	deliveryMaxAttempts = 10
+	// deliveryInitialBackoff is the time to wait before retrying a delivery that has failed
+	// once: the wait doubles after each subsequent failure up to deliveryMaxBackoff
+	deliveryInitialBackoff = 30 * time.Second
+	deliveryMaxBackoff     = 1 * time.Hour
+	deliveryPageSize       = 50
+)
+
+// ProcessDeliveries attempts any pending deliveries that are due to be retried. A delivery
+// that cannot be updated does not prevent the remaining deliveries from being attempted.
+func (o *OCPI) ProcessDeliveries(ctx context.Context) error {
+	now := o.clock.Now()
+
+	var due []*store.OcpiDelivery
+	for offset := 0; ; offset += deliveryPageSize {
+		deliveries, err := o.store.ListDeliveries(ctx, store.OcpiDeliveryStatusPending, offset, deliveryPageSize)
+		if err != nil {
+			return err
+		}
+		for _, delivery := range deliveries {
+			if !delivery.NextAttempt.After(now) {
+				due = append(due, delivery)
+			}
+		}
+		if len(deliveries) < deliveryPageSize {
+			break
+		}
+	}
+
+	var errs []error
+	for _, delivery := range due {
+		err := o.attemptDelivery(ctx, delivery)
+		if err != nil {
+			slog.Error("error recording ocpi delivery attempt", "deliveryId", delivery.Id, "err", err)
+			errs = append(errs, fmt.Errorf("delivery %s: %w", delivery.Id, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// enqueue records a delivery so that it will be retried if it cannot be delivered and then
+// makes the first attempt. Only failures to record the delivery are returned. A PUT replaces
+// the whole resource so it supersedes any pending delivery for the same resource: this keeps
+// a retry of an older version from overwriting a newer one at the receiver.
+func (o *OCPI) enqueue(ctx context.Context, countryCode, partyId, module, method, url string, body any) error {
+	b, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	correlationId, ok := ctx.Value(ContextKeyCorrelationId).(string)
+	if !ok {
+		correlationId = uuid.New().String()
+	}
+
+	deliveryId := uuid.New().String()
+	if method == http.MethodPut {
+		deliveryId = resourceDeliveryId(countryCode, partyId, module, url)
+	}
+
+	now := o.clock.Now().UTC()
+	delivery := &store.OcpiDelivery{
+		Id:            deliveryId,
+		CountryCode:   countryCode,
+		PartyId:       partyId,
+		Module:        module,
+		Method:        method,
+		Url:           url,
+		Body:          string(b),
+		CorrelationId: correlationId,
+		Status:        store.OcpiDeliveryStatusPending,
+		NextAttempt:   now,
+		Created:       now,
+	}
+	err = o.store.SetDelivery(ctx, delivery)
+	if err != nil {
+		return err
+	}
+
+	return o.attemptDelivery(ctx, delivery)
+}
+
+func (o *OCPI) attemptDelivery(ctx context.Context, delivery *store.OcpiDelivery) error {
+	err := o.deliver(ctx, delivery)
+
+	superseded, lookupErr := o.isSuperseded(ctx, delivery)
+	if lookupErr != nil {
+		return lookupErr
+	}
+	if superseded {
+		// any newer version of the resource that has been queued is delivered in its place
+		return nil
+	}
+
+	if err == nil {
+		return o.store.DeleteDelivery(ctx, delivery.Id)
+	}
+
+	delivery.Attempts++
+	delivery.LastError = err.Error()
+	if delivery.Attempts >= deliveryMaxAttempts {
+		slog.Warn("ocpi delivery failed, giving up", "deliveryId", delivery.Id, "attempts", delivery.Attempts, "err", err)
+		delivery.Status = store.OcpiDeliveryStatusFailed
+	} else {
+		slog.Info("ocpi delivery failed, will retry", "deliveryId", delivery.Id, "attempts", delivery.Attempts, "err", err)
+		delivery.NextAttempt = o.clock.Now().UTC().Add(deliveryBackoff(delivery.Attempts))
+	}
+
+	return o.store.SetDelivery(ctx, delivery)
+}
+
+// isSuperseded reports whether the delivery has been replaced by a newer delivery for the
+// same resource, or removed, while it was being attempted
+func (o *OCPI) isSuperseded(ctx context.Context, delivery *store.OcpiDelivery) (bool, error) {
+	if delivery.Method != http.MethodPut {
+		return false, nil
+	}
+	current, err := o.store.LookupDelivery(ctx, delivery.Id)
+	if err != nil {
+		return false, err
+	}
+	return current == nil || current.Body != delivery.Body, nil
+}
+
+// resourceDeliveryId returns a stable delivery id for the resource identified by the party,
+// module and url
+func resourceDeliveryId(countryCode, partyId, module, url string) string {
+	return uuid.NewSHA1(uuid.NameSpaceURL, []byte(strings.Join([]string{countryCode, partyId, module, url}, "|"))).String()
+}
+
+func (o *OCPI) deliver(ctx context.Context, delivery *store.OcpiDelivery) error {
+	party, err := o.lookupParty(ctx, delivery.CountryCode, delivery.PartyId)
+	if err != nil {
+		return err
+	}
+	if party == nil {
+		return fmt.Errorf("no party details for %s:%s", delivery.CountryCode, delivery.PartyId)
+	}
+
+	url := delivery.Url
+	if delivery.Module != "" {
+		endpointUrl, err := o.getReceiverEndpointUrl(ctx, party, delivery.Module)
+		if err != nil {
+			return err
+		}
+		url = endpointUrl + delivery.Url
+	}
+
+	req, err := http.NewRequestWithContext(ctx, delivery.Method, url, strings.NewReader(delivery.Body))
+	if err != nil {
+		return err
+	}
+	o.setRequestHeaders(context.WithValue(ctx, ContextKeyCorrelationId, delivery.CorrelationId), req, party.Token, party.CountryCode, party.PartyId)
+
+	resp, err := o.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("status code: %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+func (o *OCPI) getReceiverEndpointUrl(ctx context.Context, party *store.OcpiParty, module string) (string, error) {
+	// TODO: retrieve endpoints from store, not via OCPI exchange
+	versions, err := o.getVersions(ctx, party.Url, party.Token)
+	if err != nil {
+		return "", err
+	}
+
+	endpointUrl, err := getEndpointUrl(versions)
+	if err != nil {
+		return "", err
+	}
+
+	endpoints, err := o.getEndpoints(ctx, endpointUrl, party.Token)
+	if err != nil {
+		return "", err
+	}
+
+	for _, endpoint := range endpoints {
+		if endpoint.Identifier == module && endpoint.Role == RECEIVER {
+			return endpoint.Url, nil
+		}
+	}
+	return "", fmt.Errorf("no %s endpoint for receiver found", module)
+}
+
+func deliveryBackoff(attempts int) time.Duration {
+	backoff := deliveryInitialBackoff
+	for i := 1; i < attempts && backoff < deliveryMaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > deliveryMaxBackoff {
+		backoff = deliveryMaxBackoff
+	}
+	return backoff
+}
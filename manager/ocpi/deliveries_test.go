@@ -0,0 +1,321 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpi_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thoughtworks/maeve-csms/manager/ocpi"
+	"github.com/thoughtworks/maeve-csms/manager/store"
+	"github.com/thoughtworks/maeve-csms/manager/store/inmemory"
+	"io"
+	"k8s.io/utils/clock"
+	fakeclock "k8s.io/utils/clock/testing"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func setupDeliveryTest(t *testing.T, handler http.HandlerFunc) (*ocpi.OCPI, store.Engine, *fakeclock.FakePassiveClock, string) {
+	engine := inmemory.NewStore(clock.RealClock{})
+
+	receiverServer := httptest.NewServer(handler)
+	t.Cleanup(receiverServer.Close)
+
+	err := engine.SetPartyDetails(context.Background(), &store.OcpiParty{
+		Role:        "EMSP",
+		CountryCode: "GB",
+		PartyId:     "EMS",
+		Url:         receiverServer.URL,
+		Token:       "abc123",
+	})
+	require.NoError(t, err)
+
+	now := time.Date(2023, 6, 15, 15, 0, 0, 0, time.UTC)
+	fakeClock := fakeclock.NewFakePassiveClock(now)
+	ocpiApi := ocpi.NewOCPI(engine, http.DefaultClient, "GB", "TWK")
+	ocpiApi.SetClock(fakeClock)
+
+	return ocpiApi, engine, fakeClock, receiverServer.URL
+}
+
+func reportReservationResult(t *testing.T, ocpiApi *ocpi.OCPI, engine store.Engine, responseUrl string) {
+	ctx := context.Background()
+	err := engine.SetReservation(ctx, &store.Reservation{
+		ReservationId:   1,
+		ChargeStationId: "cs001",
+		Status:          store.ReservationStatusPending,
+		CountryCode:     "GB",
+		PartyId:         "EMS",
+		ResponseUrl:     responseUrl,
+	})
+	require.NoError(t, err)
+
+	err = ocpiApi.ReportReservationResult(ctx, 1, ocpi.CommandResult{Result: ocpi.CommandResultResultACCEPTED})
+	require.NoError(t, err)
+}
+
+func TestFailedDeliveryIsScheduledForRetry(t *testing.T) {
+	ocpiApi, engine, fakeClock, url := setupDeliveryTest(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	reportReservationResult(t, ocpiApi, engine, url+"/commands/RESERVE_NOW/1")
+
+	deliveries, err := engine.ListDeliveries(context.Background(), store.OcpiDeliveryStatusPending, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, 1, deliveries[0].Attempts)
+	assert.Equal(t, "status code: 503", deliveries[0].LastError)
+	assert.Equal(t, fakeClock.Now().Add(30*time.Second), deliveries[0].NextAttempt)
+	assert.Equal(t, http.MethodPost, deliveries[0].Method)
+	assert.Equal(t, url+"/commands/RESERVE_NOW/1", deliveries[0].Url)
+}
+
+func TestDeliveryIsFailedAfterMaxAttempts(t *testing.T) {
+	ocpiApi, engine, fakeClock, url := setupDeliveryTest(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	ctx := context.Background()
+
+	reportReservationResult(t, ocpiApi, engine, url+"/commands/RESERVE_NOW/1")
+
+	for i := 0; i < 9; i++ {
+		fakeClock.SetTime(fakeClock.Now().Add(time.Hour))
+		err := ocpiApi.ProcessDeliveries(ctx)
+		require.NoError(t, err)
+	}
+
+	pending, err := engine.ListDeliveries(ctx, store.OcpiDeliveryStatusPending, 0, 10)
+	require.NoError(t, err)
+	assert.Len(t, pending, 0)
+
+	failed, err := engine.ListDeliveries(ctx, store.OcpiDeliveryStatusFailed, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, failed, 1)
+	assert.Equal(t, 10, failed[0].Attempts)
+}
+
+func TestProcessDeliveriesRetriesDueDeliveries(t *testing.T) {
+	var fail = true
+	var got int
+	ocpiApi, engine, fakeClock, url := setupDeliveryTest(t, func(w http.ResponseWriter, r *http.Request) {
+		got++
+		if fail {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+	ctx := context.Background()
+
+	reportReservationResult(t, ocpiApi, engine, url+"/commands/RESERVE_NOW/1")
+	assert.Equal(t, 1, got)
+
+	// not yet due
+	fail = false
+	err := ocpiApi.ProcessDeliveries(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, got)
+
+	fakeClock.SetTime(fakeClock.Now().Add(30 * time.Second))
+	err = ocpiApi.ProcessDeliveries(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, got)
+
+	deliveries, err := engine.ListDeliveries(ctx, store.OcpiDeliveryStatusPending, 0, 10)
+	require.NoError(t, err)
+	assert.Len(t, deliveries, 0)
+}
+
+// receiverHandler serves the version and endpoint details for the receiver at the root of the
+// server and passes all module requests to the handler
+func receiverHandler(handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		baseUrl := "http://" + r.Host
+		switch r.URL.Path {
+		case "/":
+			_, _ = fmt.Fprintf(w, `{"data":[{"version":"2.2","url":"%s/ocpi/2.2"}], "status_code":1000}`, baseUrl)
+		case "/ocpi/2.2":
+			_, _ = fmt.Fprintf(w, `{"data":{"version":"2.2","endpoints":[
+				{"identifier":"sessions","role":"RECEIVER","url":"%[1]s/ocpi/receiver/2.2/sessions"},
+				{"identifier":"cdrs","role":"RECEIVER","url":"%[1]s/ocpi/receiver/2.2/cdrs"}]},
+				"status_code":1000}`, baseUrl)
+		default:
+			handler(w, r)
+		}
+	}
+}
+
+func setToken(t *testing.T, ocpiApi *ocpi.OCPI) {
+	err := ocpiApi.SetToken(context.Background(), ocpi.Token{
+		CountryCode: "GB",
+		PartyId:     "EMS",
+		Type:        ocpi.TokenTypeRFID,
+		Uid:         "DEADBEEF",
+		ContractId:  "GBEMS012345678V",
+		Valid:       true,
+		Whitelist:   ocpi.NEVER,
+	})
+	require.NoError(t, err)
+}
+
+func newSession(kwh float32) ocpi.Session {
+	return ocpi.Session{
+		Id:          "s001",
+		CountryCode: "GB",
+		PartyId:     "TWK",
+		CdrToken: ocpi.CdrToken{
+			Uid:  "DEADBEEF",
+			Type: "RFID",
+		},
+		Kwh:    kwh,
+		Status: "ACTIVE",
+	}
+}
+
+func TestPushSessionIsDeliveredToTokenIssuer(t *testing.T) {
+	var gotMethod, gotPath string
+	ocpiApi, engine, _, _ := setupDeliveryTest(t, receiverHandler(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod = r.Method
+		gotPath = r.URL.Path
+		w.WriteHeader(http.StatusOK)
+	}))
+	setToken(t, ocpiApi)
+
+	err := ocpiApi.PushSession(context.Background(), newSession(1.5))
+	require.NoError(t, err)
+
+	assert.Equal(t, http.MethodPut, gotMethod)
+	assert.Equal(t, "/ocpi/receiver/2.2/sessions/GB/TWK/s001", gotPath)
+
+	deliveries, err := engine.ListDeliveries(context.Background(), store.OcpiDeliveryStatusPending, 0, 10)
+	require.NoError(t, err)
+	assert.Len(t, deliveries, 0)
+}
+
+func TestPushCdrIsQueuedWhenReceiverIsUnavailable(t *testing.T) {
+	ocpiApi, engine, _, _ := setupDeliveryTest(t, receiverHandler(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	setToken(t, ocpiApi)
+
+	err := ocpiApi.PushCdr(context.Background(), ocpi.CDR{
+		Id: "cdr001",
+		CdrToken: ocpi.CdrToken{
+			Uid:  "DEADBEEF",
+			Type: "RFID",
+		},
+	})
+	require.NoError(t, err)
+
+	deliveries, err := engine.ListDeliveries(context.Background(), store.OcpiDeliveryStatusPending, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, "GB", deliveries[0].CountryCode)
+	assert.Equal(t, "EMS", deliveries[0].PartyId)
+	assert.Equal(t, "cdrs", deliveries[0].Module)
+	assert.Equal(t, http.MethodPost, deliveries[0].Method)
+	assert.Equal(t, "", deliveries[0].Url)
+}
+
+func TestPendingPutIsSupersededByNewerVersion(t *testing.T) {
+	var fail = true
+	var got []ocpi.Session
+	ocpiApi, engine, fakeClock, _ := setupDeliveryTest(t, receiverHandler(func(w http.ResponseWriter, r *http.Request) {
+		if fail {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		b, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		var session ocpi.Session
+		err = json.Unmarshal(b, &session)
+		require.NoError(t, err)
+		got = append(got, session)
+		w.WriteHeader(http.StatusOK)
+	}))
+	setToken(t, ocpiApi)
+	ctx := context.Background()
+
+	err := ocpiApi.PushSession(ctx, newSession(1.5))
+	require.NoError(t, err)
+	err = ocpiApi.PushSession(ctx, newSession(3.0))
+	require.NoError(t, err)
+
+	deliveries, err := engine.ListDeliveries(ctx, store.OcpiDeliveryStatusPending, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+
+	fail = false
+	fakeClock.SetTime(fakeClock.Now().Add(time.Minute))
+	err = ocpiApi.ProcessDeliveries(ctx)
+	require.NoError(t, err)
+
+	require.Len(t, got, 1)
+	assert.Equal(t, float32(3.0), got[0].Kwh)
+}
+
+type failingDeliveryStore struct {
+	store.Engine
+	failDeliveryId string
+}
+
+func (s failingDeliveryStore) SetDelivery(ctx context.Context, delivery *store.OcpiDelivery) error {
+	if delivery.Id == s.failDeliveryId && delivery.Attempts > 1 {
+		return errors.New("store unavailable")
+	}
+	return s.Engine.SetDelivery(ctx, delivery)
+}
+
+func TestProcessDeliveriesContinuesAfterStoreError(t *testing.T) {
+	engine := inmemory.NewStore(clock.RealClock{})
+	ctx := context.Background()
+
+	receiverServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(receiverServer.Close)
+
+	err := engine.SetPartyDetails(ctx, &store.OcpiParty{
+		Role:        "EMSP",
+		CountryCode: "GB",
+		PartyId:     "EMS",
+		Url:         receiverServer.URL,
+		Token:       "abc123",
+	})
+	require.NoError(t, err)
+
+	now := time.Date(2023, 6, 15, 15, 0, 0, 0, time.UTC)
+	for _, id := range []string{"d001", "d002"} {
+		err = engine.SetDelivery(ctx, &store.OcpiDelivery{
+			Id:          id,
+			CountryCode: "GB",
+			PartyId:     "EMS",
+			Method:      http.MethodPost,
+			Url:         receiverServer.URL + "/commands/RESERVE_NOW/" + id,
+			Body:        "{}",
+			Status:      store.OcpiDeliveryStatusPending,
+			Attempts:    1,
+			NextAttempt: now,
+			Created:     now,
+		})
+		require.NoError(t, err)
+	}
+
+	ocpiApi := ocpi.NewOCPI(failingDeliveryStore{Engine: engine, failDeliveryId: "d001"}, http.DefaultClient, "GB", "TWK")
+	ocpiApi.SetClock(fakeclock.NewFakePassiveClock(now))
+
+	err = ocpiApi.ProcessDeliveries(ctx)
+	assert.ErrorContains(t, err, "delivery d001: store unavailable")
+
+	other, err := engine.LookupDelivery(ctx, "d002")
+	require.NoError(t, err)
+	require.NotNil(t, other)
+	assert.Equal(t, 2, other.Attempts)
+}
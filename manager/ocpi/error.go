@@ -5,6 +5,7 @@ package ocpi
 import (
 	"github.com/go-chi/render"
 	"net/http"
+	"time"
 )
 
 type ErrResponse struct {
@@ -39,6 +40,30 @@ func ErrInternalError(err error) render.Renderer {
 	}
 }
 
+// OcpiErrResponse is an OCPI response that reports an error using an OCPI status code
+type OcpiErrResponse struct {
+	HTTPStatusCode int     `json:"-"` // http response status code
+	StatusCode     int32   `json:"status_code"`
+	StatusMessage  *string `json:"status_message,omitempty"`
+	Timestamp      string  `json:"timestamp"`
+}
+
+func (e *OcpiErrResponse) Render(w http.ResponseWriter, r *http.Request) error {
+	render.Status(r, e.HTTPStatusCode)
+	return nil
+}
+
+// ErrInvalidParameters reports a request that has invalid or missing parameters
+func ErrInvalidParameters(err error, now time.Time) render.Renderer {
+	message := err.Error()
+	return &OcpiErrResponse{
+		HTTPStatusCode: http.StatusBadRequest,
+		StatusCode:     StatusInvalidParameters,
+		StatusMessage:  &message,
+		Timestamp:      now.Format(time.RFC3339),
+	}
+}
+
 var ErrNotFound = &ErrResponse{
 	HTTPStatusCode: http.StatusNotFound,
 	StatusText:     http.StatusText(http.StatusNotFound),
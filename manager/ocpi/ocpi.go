@@ -3,14 +3,14 @@
 package ocpi
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
-	"errors"
 	"fmt"
 	"github.com/google/uuid"
+	"github.com/thoughtworks/maeve-csms/manager/handlers"
 	"github.com/thoughtworks/maeve-csms/manager/store"
+	"k8s.io/utils/clock"
 	"net/http"
+	"time"
 )
 
 //go:generate oapi-codegen -config cfg.yaml ocpi22-spec.yaml
@@ -24,14 +24,28 @@ type Api interface {
 	SetToken(ctx context.Context, token Token) error
 	GetToken(ctx context.Context, countryCode string, partyID string, tokenUID string) (*Token, error)
 	PushLocation(ctx context.Context, location Location) error
+	PushSession(ctx context.Context, session Session) error
+	PushCdr(ctx context.Context, cdr CDR) error
+	SetChargingProfile(ctx context.Context, countryCode, partyId, sessionId string, setChargingProfile SetChargingProfile) (*ChargingProfileResponse, error)
+	GetChargingProfile(ctx context.Context, countryCode, partyId, sessionId string, duration int, responseUrl string) (*ChargingProfileResponse, error)
+	ClearChargingProfile(ctx context.Context, countryCode, partyId, sessionId string, responseUrl string) (*ChargingProfileResponse, error)
+	ReportChargingProfileResult(ctx context.Context, chargeStationId string, evseId int, requestType store.OcpiChargingProfileRequestType, result GenericChargingProfileResult) error
+	ReserveNow(ctx context.Context, countryCode, partyId, chargeStationId string, expiryDate time.Time, reserveNow ReserveNow) (*CommandResponse, error)
+	CancelReservation(ctx context.Context, countryCode, partyId string, cancelReservation CancelReservation) (*CommandResponse, error)
+	ReportReservationResult(ctx context.Context, reservationId int, result CommandResult) error
+	ProcessDeliveries(ctx context.Context) error
 }
 
 type OCPI struct {
-	store       store.Engine
-	httpClient  *http.Client
-	externalUrl string
-	countryCode string
-	partyId     string
+	store         store.Engine
+	httpClient    *http.Client
+	externalUrl   string
+	countryCode   string
+	partyId       string
+	v16CallMaker  handlers.CallMaker
+	v201CallMaker handlers.CallMaker
+	v21CallMaker  handlers.CallMaker
+	clock         clock.PassiveClock
 }
 
 func NewOCPI(store store.Engine, httpClient *http.Client, countryCode, partyId string) *OCPI {
@@ -40,6 +54,7 @@ func NewOCPI(store store.Engine, httpClient *http.Client, countryCode, partyId s
 		httpClient:  httpClient,
 		countryCode: countryCode,
 		partyId:     partyId,
+		clock:       clock.RealClock{},
 	}
 }
 
@@ -47,6 +62,19 @@ func (o *OCPI) SetExternalUrl(externalUrl string) {
 	o.externalUrl = externalUrl
 }
 
+// SetCallMakers provides the call makers that are used to send OCPP requests to
+// charge stations in response to OCPI requests
+func (o *OCPI) SetCallMakers(v16CallMaker, v201CallMaker, v21CallMaker handlers.CallMaker) {
+	o.v16CallMaker = v16CallMaker
+	o.v201CallMaker = v201CallMaker
+	o.v21CallMaker = v21CallMaker
+}
+
+// SetClock replaces the clock that is used to schedule outbound deliveries
+func (o *OCPI) SetClock(clock clock.PassiveClock) {
+	o.clock = clock
+}
+
 func (o *OCPI) GetVersions(context.Context) ([]Version, error) {
 	return []Version{
 		{
@@ -74,6 +102,11 @@ func (o *OCPI) GetVersion(context.Context) (VersionDetail, error) {
 				Role:       RECEIVER,
 				Url:        fmt.Sprintf("%s/ocpi/receiver/2.2/tokens/", o.externalUrl),
 			},
+			{
+				Identifier: "chargingprofiles",
+				Role:       RECEIVER,
+				Url:        fmt.Sprintf("%s/ocpi/2.2/receiver/chargingprofiles", o.externalUrl),
+			},
 		},
 		Version: "2.2",
 	}, nil
@@ -170,7 +203,8 @@ func (o *OCPI) PushLocation(ctx context.Context, location Location) error {
 		return err
 	}
 	for _, party := range parties {
-		err = o.pushLocationToParty(ctx, party, location)
+		err = o.enqueue(ctx, party.CountryCode, party.PartyId, "locations", http.MethodPut,
+			fmt.Sprintf("/%s/%s/%s", o.countryCode, o.partyId, location.Id), location)
 		if err != nil {
 			return err
 		}
@@ -179,70 +213,33 @@ func (o *OCPI) PushLocation(ctx context.Context, location Location) error {
 	return nil
 }
 
-func (o *OCPI) pushLocationToParty(ctx context.Context, party *store.OcpiParty, location Location) error {
-	// TODO: retrieve endpoints from store, not via OCPI exchange
-	versions, err := o.getVersions(ctx, party.Url, party.Token)
+// PushSession sends the session to the EMSP that issued the session's token
+func (o *OCPI) PushSession(ctx context.Context, session Session) error {
+	tok, err := o.store.LookupToken(ctx, session.CdrToken.Uid)
 	if err != nil {
 		return err
 	}
-
-	endpointUrl, err := getEndpointUrl(versions)
-	if err != nil {
-		return err
+	if tok == nil {
+		// not a token issued by an OCPI party
+		return nil
 	}
 
-	endpoints, err := o.getEndpoints(ctx, endpointUrl, party.Token)
-	if err != nil {
-		return err
-	}
-
-	locationsUrl, err := o.getLocationsUrl(endpoints)
-	if err != nil {
-		return err
-	}
-
-	err = o.putLocation(ctx, locationsUrl, party.CountryCode, party.PartyId, party.Token, location)
-	if err != nil {
-		return err
-	}
-
-	return nil
+	return o.enqueue(ctx, tok.CountryCode, tok.PartyId, "sessions", http.MethodPut,
+		fmt.Sprintf("/%s/%s/%s", o.countryCode, o.partyId, session.Id), session)
 }
 
-func (o *OCPI) getLocationsUrl(endpoints []Endpoint) (string, error) {
-	for _, endpoint := range endpoints {
-		if endpoint.Identifier == "locations" && endpoint.Role == RECEIVER {
-			return fmt.Sprintf("%s/%s/%s", endpoint.Url, o.countryCode, o.partyId), nil
-		}
-	}
-	return "", errors.New("no locations endpoint for receiver found")
-}
-
-func (o *OCPI) putLocation(ctx context.Context, url string, toCountryCode string, toPartyId string, token string, location Location) error {
-	b, err := json.Marshal(location)
-	if err != nil {
-		return err
-	}
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodPut, fmt.Sprintf("%s/%s", url, location.Id), bytes.NewReader(b))
+// PushCdr sends the CDR to the EMSP that issued the CDR's token
+func (o *OCPI) PushCdr(ctx context.Context, cdr CDR) error {
+	tok, err := o.store.LookupToken(ctx, cdr.CdrToken.Uid)
 	if err != nil {
 		return err
 	}
-	o.setRequestHeaders(ctx, req, token, toCountryCode, toPartyId)
-
-	resp, err := o.httpClient.Do(req)
-	if err != nil {
-		return err
-	}
-	defer func() {
-		_ = resp.Body.Close()
-	}()
-
-	if resp.StatusCode != http.StatusCreated {
-		return fmt.Errorf("status code: %d", resp.StatusCode)
+	if tok == nil {
+		// not a token issued by an OCPI party
+		return nil
 	}
 
-	return nil
+	return o.enqueue(ctx, tok.CountryCode, tok.PartyId, "cdrs", http.MethodPost, "", cdr)
 }
 
 func (o *OCPI) setRequestHeaders(ctx context.Context, req *http.Request, token string, toCountryCode string, toPartyId string) {
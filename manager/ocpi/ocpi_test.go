@@ -54,6 +54,11 @@ func TestGetVersionDetails(t *testing.T) {
 				Role:       ocpi.RECEIVER,
 				Url:        "/ocpi/receiver/2.2/tokens/",
 			},
+			{
+				Identifier: "chargingprofiles",
+				Role:       ocpi.RECEIVER,
+				Url:        "/ocpi/2.2/receiver/chargingprofiles",
+			},
 		},
 	}
 
@@ -20,6 +20,10 @@ func (OcpiResponseToken) Render(http.ResponseWriter, *http.Request) error {
 	return nil
 }
 
+func (OcpiResponseChargingProfileResponse) Render(http.ResponseWriter, *http.Request) error {
+	return nil
+}
+
 func (Credentials) Bind(r *http.Request) error {
 	return nil
 }
@@ -31,3 +35,15 @@ func (Token) Bind(r *http.Request) error {
 func (StartSession) Bind(r *http.Request) error {
 	return nil
 }
+
+func (SetChargingProfile) Bind(r *http.Request) error {
+	return nil
+}
+
+func (ReserveNow) Bind(r *http.Request) error {
+	return nil
+}
+
+func (CancelReservation) Bind(r *http.Request) error {
+	return nil
+}
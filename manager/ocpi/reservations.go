@@ -0,0 +1,183 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/thoughtworks/maeve-csms/manager/handlers"
+	"github.com/thoughtworks/maeve-csms/manager/ocpp"
+	"github.com/thoughtworks/maeve-csms/manager/ocpp/ocpp16"
+	"github.com/thoughtworks/maeve-csms/manager/ocpp/ocpp201"
+	"github.com/thoughtworks/maeve-csms/manager/store"
+	"golang.org/x/exp/slog"
+	"math"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+// reservationTimeout is the number of seconds that the OCPI party is told to wait for
+// the asynchronous result of a reservation command
+const reservationTimeout = 30
+
+func (o *OCPI) ReserveNow(ctx context.Context, countryCode, partyId, chargeStationId string, expiryDate time.Time, reserveNow ReserveNow) (*CommandResponse, error) {
+	if o.v16CallMaker == nil || o.v201CallMaker == nil || o.v21CallMaker == nil {
+		return nil, errors.New("no call makers configured")
+	}
+
+	details, err := o.store.LookupChargeStationRuntimeDetails(ctx, chargeStationId)
+	if err != nil {
+		return nil, err
+	}
+	if details == nil {
+		return &CommandResponse{Result: CommandResponseResultREJECTED}, nil
+	}
+
+	// without an EVSE the reservation applies to any EVSE of the charge station
+	var evseId *int
+	if reserveNow.EvseUid != nil {
+		evseId, err = extractEvseId(*reserveNow.EvseUid)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// the charge station will need to authorize the token when the reservation is used
+	err = o.SetToken(ctx, reserveNow.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	// a reserve now command with an existing reservation id replaces the existing reservation
+	existing, err := o.store.FindReservationByOcpiId(ctx, countryCode, partyId, reserveNow.ReservationId)
+	if err != nil {
+		return nil, err
+	}
+	reservationId := newReservationId()
+	if existing != nil {
+		reservationId = existing.ReservationId
+	}
+
+	reservation := &store.Reservation{
+		ReservationId:     reservationId,
+		ChargeStationId:   chargeStationId,
+		EvseId:            evseId,
+		IdToken:           reserveNow.Token.Uid,
+		TokenType:         string(reserveNow.Token.Type),
+		ExpiryDate:        expiryDate.UTC(),
+		Status:            store.ReservationStatusPending,
+		OcpiReservationId: reserveNow.ReservationId,
+		CountryCode:       countryCode,
+		PartyId:           partyId,
+		ResponseUrl:       reserveNow.ResponseUrl,
+	}
+
+	var req ocpp.Request
+	if details.OcppVersion == "1.6" {
+		connectorId := 0
+		if evseId != nil {
+			connectorId = *evseId
+		}
+		req = &ocpp16.ReserveNowJson{
+			ConnectorId:   connectorId,
+			ExpiryDate:    reservation.ExpiryDate.Format(time.RFC3339),
+			IdTag:         reservation.IdToken,
+			ReservationId: reservationId,
+		}
+	} else {
+		req = &ocpp201.ReserveNowRequestJson{
+			Id:             reservationId,
+			EvseId:         evseId,
+			ExpiryDateTime: reservation.ExpiryDate.Format(time.RFC3339),
+			IdToken: ocpp201.IdTokenType{
+				IdToken: reservation.IdToken,
+				Type:    idTokenType(reserveNow.Token.Type),
+			},
+		}
+	}
+
+	return o.sendReservationRequest(ctx, reservation, details.OcppVersion, req)
+}
+
+func (o *OCPI) CancelReservation(ctx context.Context, countryCode, partyId string, cancelReservation CancelReservation) (*CommandResponse, error) {
+	if o.v16CallMaker == nil || o.v201CallMaker == nil || o.v21CallMaker == nil {
+		return nil, errors.New("no call makers configured")
+	}
+
+	reservation, err := o.store.FindReservationByOcpiId(ctx, countryCode, partyId, cancelReservation.ReservationId)
+	if err != nil {
+		return nil, err
+	}
+	if reservation == nil {
+		return &CommandResponse{Result: CommandResponseResultREJECTED}, nil
+	}
+
+	details, err := o.store.LookupChargeStationRuntimeDetails(ctx, reservation.ChargeStationId)
+	if err != nil {
+		return nil, err
+	}
+	if details == nil {
+		return nil, fmt.Errorf("no runtime details for charge station %s", reservation.ChargeStationId)
+	}
+
+	reservation.ResponseUrl = cancelReservation.ResponseUrl
+
+	var req ocpp.Request
+	if details.OcppVersion == "1.6" {
+		req = &ocpp16.CancelReservationJson{
+			ReservationId: reservation.ReservationId,
+		}
+	} else {
+		req = &ocpp201.CancelReservationRequestJson{
+			ReservationId: reservation.ReservationId,
+		}
+	}
+
+	return o.sendReservationRequest(ctx, reservation, details.OcppVersion, req)
+}
+
+func (o *OCPI) ReportReservationResult(ctx context.Context, reservationId int, result CommandResult) error {
+	reservation, err := o.store.LookupReservation(ctx, reservationId)
+	if err != nil {
+		return err
+	}
+	if reservation == nil || reservation.ResponseUrl == "" {
+		// not an OCPI initiated reservation
+		return nil
+	}
+
+	return o.enqueue(ctx, reservation.CountryCode, reservation.PartyId, "", http.MethodPost, reservation.ResponseUrl, result)
+}
+
+func (o *OCPI) sendReservationRequest(ctx context.Context, reservation *store.Reservation, ocppVersion string, req ocpp.Request) (*CommandResponse, error) {
+	err := o.store.SetReservation(ctx, reservation)
+	if err != nil {
+		return nil, err
+	}
+
+	err = handlers.CallMakerForVersion(ocppVersion, o.v16CallMaker, o.v201CallMaker, o.v21CallMaker).
+		Send(ctx, reservation.ChargeStationId, req)
+	if err != nil {
+		slog.Error("error sending reservation request", "err", err, "chargeStationId", reservation.ChargeStationId)
+		return &CommandResponse{Result: CommandResponseResultREJECTED}, nil
+	}
+
+	return &CommandResponse{
+		Result:  CommandResponseResultACCEPTED,
+		Timeout: reservationTimeout,
+	}, nil
+}
+
+// newReservationId returns a positive OCPP reservation id
+func newReservationId() int {
+	return int(rand.Int31n(math.MaxInt32-1)) + 1
+}
+
+func idTokenType(tokenType TokenType) ocpp201.IdTokenEnumType {
+	if tokenType == TokenTypeRFID {
+		return ocpp201.IdTokenEnumTypeISO14443
+	}
+	return ocpp201.IdTokenEnumTypeCentral
+}
@@ -0,0 +1,242 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpi_test
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thoughtworks/maeve-csms/manager/ocpi"
+	"github.com/thoughtworks/maeve-csms/manager/ocpp/ocpp16"
+	"github.com/thoughtworks/maeve-csms/manager/ocpp/ocpp201"
+	"github.com/thoughtworks/maeve-csms/manager/store"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+var reservationExpiry = time.Date(2023, 6, 15, 15, 5, 0, 0, time.UTC)
+
+func newReserveNow(responseUrl string) ocpi.ReserveNow {
+	evseUid := "GBTWKEcs001"
+	return ocpi.ReserveNow{
+		EvseUid:       &evseUid,
+		ExpiryDate:    reservationExpiry.Format(time.RFC3339),
+		LocationId:    "loc001",
+		ReservationId: "res001",
+		ResponseUrl:   responseUrl,
+		Token: ocpi.Token{
+			CountryCode: "GB",
+			PartyId:     "EMS",
+			Type:        ocpi.TokenTypeRFID,
+			Uid:         "DEADBEEF",
+			ContractId:  "GBEMS012345678V",
+			Issuer:      "Example",
+			Valid:       true,
+			Whitelist:   ocpi.NEVER,
+		},
+	}
+}
+
+func TestReserveNowWithOcpp16(t *testing.T) {
+	ocpiApi, engine, v16CallMaker, _ := setupChargingProfileTest(t, "1.6")
+	ctx := context.Background()
+
+	got, err := ocpiApi.ReserveNow(ctx, "GB", "EMS", "cs001", reservationExpiry, newReserveNow("https://example.com/commands/RESERVE_NOW/1"))
+	require.NoError(t, err)
+	assert.Equal(t, ocpi.CommandResponseResultACCEPTED, got.Result)
+	assert.Equal(t, int32(30), got.Timeout)
+
+	reservation, err := engine.FindReservationByOcpiId(ctx, "GB", "EMS", "res001")
+	require.NoError(t, err)
+	require.NotNil(t, reservation)
+	assert.Equal(t, "cs001", reservation.ChargeStationId)
+	assert.Equal(t, "DEADBEEF", reservation.IdToken)
+	assert.Equal(t, store.ReservationStatusPending, reservation.Status)
+	assert.Equal(t, "https://example.com/commands/RESERVE_NOW/1", reservation.ResponseUrl)
+
+	want := &ocpp16.ReserveNowJson{
+		ConnectorId:   0,
+		ExpiryDate:    "2023-06-15T15:05:00Z",
+		IdTag:         "DEADBEEF",
+		ReservationId: reservation.ReservationId,
+	}
+	assert.Equal(t, "cs001", v16CallMaker.chargeStationId)
+	assert.Equal(t, want, v16CallMaker.request)
+
+	tok, err := engine.LookupToken(ctx, "DEADBEEF")
+	require.NoError(t, err)
+	assert.NotNil(t, tok)
+}
+
+func TestReserveNowForEvseWithOcpp16(t *testing.T) {
+	ocpiApi, engine, v16CallMaker, _ := setupChargingProfileTest(t, "1.6")
+	ctx := context.Background()
+
+	reserveNow := newReserveNow("https://example.com/commands/RESERVE_NOW/1")
+	evseUid := "GBTWKEcs001*2"
+	reserveNow.EvseUid = &evseUid
+
+	got, err := ocpiApi.ReserveNow(ctx, "GB", "EMS", "cs001", reservationExpiry, reserveNow)
+	require.NoError(t, err)
+	assert.Equal(t, ocpi.CommandResponseResultACCEPTED, got.Result)
+
+	reservation, err := engine.FindReservationByOcpiId(ctx, "GB", "EMS", "res001")
+	require.NoError(t, err)
+	require.NotNil(t, reservation)
+	require.NotNil(t, reservation.EvseId)
+	assert.Equal(t, 2, *reservation.EvseId)
+
+	want := &ocpp16.ReserveNowJson{
+		ConnectorId:   2,
+		ExpiryDate:    "2023-06-15T15:05:00Z",
+		IdTag:         "DEADBEEF",
+		ReservationId: reservation.ReservationId,
+	}
+	assert.Equal(t, want, v16CallMaker.request)
+}
+
+func TestReserveNowForEvseWithOcpp201(t *testing.T) {
+	ocpiApi, engine, _, v201CallMaker := setupChargingProfileTest(t, "2.0.1")
+	ctx := context.Background()
+
+	reserveNow := newReserveNow("https://example.com/commands/RESERVE_NOW/1")
+	evseUid := "GBTWKEcs001*2"
+	reserveNow.EvseUid = &evseUid
+
+	got, err := ocpiApi.ReserveNow(ctx, "GB", "EMS", "cs001", reservationExpiry, reserveNow)
+	require.NoError(t, err)
+	assert.Equal(t, ocpi.CommandResponseResultACCEPTED, got.Result)
+
+	reservation, err := engine.FindReservationByOcpiId(ctx, "GB", "EMS", "res001")
+	require.NoError(t, err)
+	require.NotNil(t, reservation)
+
+	evseId := 2
+	want := &ocpp201.ReserveNowRequestJson{
+		Id:             reservation.ReservationId,
+		EvseId:         &evseId,
+		ExpiryDateTime: "2023-06-15T15:05:00Z",
+		IdToken: ocpp201.IdTokenType{
+			IdToken: "DEADBEEF",
+			Type:    ocpp201.IdTokenEnumTypeISO14443,
+		},
+	}
+	assert.Equal(t, want, v201CallMaker.request)
+}
+
+func TestReserveNowWithOcpp201ReplacesExistingReservation(t *testing.T) {
+	ocpiApi, engine, _, v201CallMaker := setupChargingProfileTest(t, "2.0.1")
+	ctx := context.Background()
+
+	_, err := ocpiApi.ReserveNow(ctx, "GB", "EMS", "cs001", reservationExpiry, newReserveNow("https://example.com/commands/RESERVE_NOW/1"))
+	require.NoError(t, err)
+	first, err := engine.FindReservationByOcpiId(ctx, "GB", "EMS", "res001")
+	require.NoError(t, err)
+
+	got, err := ocpiApi.ReserveNow(ctx, "GB", "EMS", "cs001", reservationExpiry.Add(time.Hour), newReserveNow("https://example.com/commands/RESERVE_NOW/2"))
+	require.NoError(t, err)
+	assert.Equal(t, ocpi.CommandResponseResultACCEPTED, got.Result)
+
+	want := &ocpp201.ReserveNowRequestJson{
+		Id:             first.ReservationId,
+		ExpiryDateTime: "2023-06-15T16:05:00Z",
+		IdToken: ocpp201.IdTokenType{
+			IdToken: "DEADBEEF",
+			Type:    ocpp201.IdTokenEnumTypeISO14443,
+		},
+	}
+	assert.Equal(t, want, v201CallMaker.request)
+}
+
+func TestReserveNowWithUnknownChargeStation(t *testing.T) {
+	ocpiApi, _, v16CallMaker, _ := setupChargingProfileTest(t, "1.6")
+
+	got, err := ocpiApi.ReserveNow(context.Background(), "GB", "EMS", "unknown", reservationExpiry, newReserveNow("https://example.com/commands/RESERVE_NOW/1"))
+	require.NoError(t, err)
+	assert.Equal(t, ocpi.CommandResponseResultREJECTED, got.Result)
+	assert.Nil(t, v16CallMaker.request)
+}
+
+func TestCancelReservation(t *testing.T) {
+	ocpiApi, engine, v16CallMaker, _ := setupChargingProfileTest(t, "1.6")
+	ctx := context.Background()
+
+	err := engine.SetReservation(ctx, &store.Reservation{
+		ReservationId:     1234,
+		ChargeStationId:   "cs001",
+		Status:            store.ReservationStatusAccepted,
+		OcpiReservationId: "res001",
+		CountryCode:       "GB",
+		PartyId:           "EMS",
+	})
+	require.NoError(t, err)
+
+	got, err := ocpiApi.CancelReservation(ctx, "GB", "EMS", ocpi.CancelReservation{
+		ReservationId: "res001",
+		ResponseUrl:   "https://example.com/commands/CANCEL_RESERVATION/1",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, ocpi.CommandResponseResultACCEPTED, got.Result)
+
+	assert.Equal(t, &ocpp16.CancelReservationJson{ReservationId: 1234}, v16CallMaker.request)
+
+	reservation, err := engine.LookupReservation(ctx, 1234)
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/commands/CANCEL_RESERVATION/1", reservation.ResponseUrl)
+}
+
+func TestCancelReservationWithUnknownReservation(t *testing.T) {
+	ocpiApi, _, v16CallMaker, _ := setupChargingProfileTest(t, "1.6")
+
+	got, err := ocpiApi.CancelReservation(context.Background(), "GB", "EMS", ocpi.CancelReservation{
+		ReservationId: "unknown",
+		ResponseUrl:   "https://example.com/commands/CANCEL_RESERVATION/1",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, ocpi.CommandResponseResultREJECTED, got.Result)
+	assert.Nil(t, v16CallMaker.request)
+}
+
+func TestReportReservationResult(t *testing.T) {
+	ocpiApi, engine, _, _ := setupChargingProfileTest(t, "1.6")
+	ctx := context.Background()
+
+	var got ocpi.CommandResult
+	var gotPath string
+	receiverServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		err := json.NewDecoder(r.Body).Decode(&got)
+		require.NoError(t, err)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer receiverServer.Close()
+
+	err := engine.SetPartyDetails(ctx, &store.OcpiParty{
+		Role:        "EMSP",
+		CountryCode: "GB",
+		PartyId:     "EMS",
+		Url:         receiverServer.URL,
+		Token:       "abc123",
+	})
+	require.NoError(t, err)
+
+	err = engine.SetReservation(ctx, &store.Reservation{
+		ReservationId:     1234,
+		ChargeStationId:   "cs001",
+		Status:            store.ReservationStatusAccepted,
+		OcpiReservationId: "res001",
+		CountryCode:       "GB",
+		PartyId:           "EMS",
+		ResponseUrl:       receiverServer.URL + "/commands/RESERVE_NOW/1",
+	})
+	require.NoError(t, err)
+
+	err = ocpiApi.ReportReservationResult(ctx, 1234, ocpi.CommandResult{Result: ocpi.CommandResultResultACCEPTED})
+	require.NoError(t, err)
+
+	assert.Equal(t, "/commands/RESERVE_NOW/1", gotPath)
+	assert.Equal(t, ocpi.CommandResultResultACCEPTED, got.Result)
+}
@@ -211,15 +211,57 @@ func (s *Server) PutCredentials(w http.ResponseWriter, r *http.Request, params P
 }
 
 func (s *Server) DeleteReceiverChargingProfile(w http.ResponseWriter, r *http.Request, sessionId string, params DeleteReceiverChargingProfileParams) {
-	w.WriteHeader(http.StatusNotImplemented)
+	resp, err := s.ocpi.ClearChargingProfile(r.Context(), params.OCPIFromCountryCode, params.OCPIFromPartyId, sessionId, params.ResponseUrl)
+	if err != nil {
+		slog.Error("error clearing charging profile", "err", err)
+		_ = render.Render(w, r, ErrInternalError(err))
+		return
+	}
+
+	_ = render.Render(w, r, OcpiResponseChargingProfileResponse{
+		StatusCode:    StatusSuccess,
+		StatusMessage: &StatusSuccessMessage,
+		Timestamp:     s.clock.Now().Format(time.RFC3339),
+		Data:          resp,
+	})
 }
 
 func (s *Server) GetReceiverChargingProfile(w http.ResponseWriter, r *http.Request, sessionId string, params GetReceiverChargingProfileParams) {
-	w.WriteHeader(http.StatusNotImplemented)
+	resp, err := s.ocpi.GetChargingProfile(r.Context(), params.OCPIFromCountryCode, params.OCPIFromPartyId, sessionId, int(params.Duration), params.ResponseUrl)
+	if err != nil {
+		slog.Error("error getting charging profile", "err", err)
+		_ = render.Render(w, r, ErrInternalError(err))
+		return
+	}
+
+	_ = render.Render(w, r, OcpiResponseChargingProfileResponse{
+		StatusCode:    StatusSuccess,
+		StatusMessage: &StatusSuccessMessage,
+		Timestamp:     s.clock.Now().Format(time.RFC3339),
+		Data:          resp,
+	})
 }
 
 func (s *Server) PutReceiverChargingProfile(w http.ResponseWriter, r *http.Request, sessionId string, params PutReceiverChargingProfileParams) {
-	w.WriteHeader(http.StatusNotImplemented)
+	setChargingProfile := new(SetChargingProfile)
+	if err := render.Bind(r, setChargingProfile); err != nil {
+		_ = render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	resp, err := s.ocpi.SetChargingProfile(r.Context(), params.OCPIFromCountryCode, params.OCPIFromPartyId, sessionId, *setChargingProfile)
+	if err != nil {
+		slog.Error("error setting charging profile", "err", err)
+		_ = render.Render(w, r, ErrInternalError(err))
+		return
+	}
+
+	_ = render.Render(w, r, OcpiResponseChargingProfileResponse{
+		StatusCode:    StatusSuccess,
+		StatusMessage: &StatusSuccessMessage,
+		Timestamp:     s.clock.Now().Format(time.RFC3339),
+		Data:          resp,
+	})
 }
 
 func (s *Server) PostGenericChargingProfileResult(w http.ResponseWriter, r *http.Request, uid string, params PostGenericChargingProfileResultParams) {
@@ -239,11 +281,66 @@ func (s *Server) GetClientOwnedCdr(w http.ResponseWriter, r *http.Request, cdrID
 }
 
 func (s *Server) PostCancelReservation(w http.ResponseWriter, r *http.Request, params PostCancelReservationParams) {
-	w.WriteHeader(http.StatusNotImplemented)
+	cancelReservation := new(CancelReservation)
+	if err := render.Bind(r, cancelReservation); err != nil {
+		_ = render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	resp, err := s.ocpi.CancelReservation(r.Context(), params.OCPIFromCountryCode, params.OCPIFromPartyId, *cancelReservation)
+	if err != nil {
+		slog.Error("error cancelling reservation", "err", err)
+		_ = render.Render(w, r, ErrInternalError(err))
+		return
+	}
+
+	_ = render.Render(w, r, OcpiResponseCommandResponse{
+		StatusCode:    StatusSuccess,
+		StatusMessage: &StatusSuccessMessage,
+		Timestamp:     s.clock.Now().Format(time.RFC3339),
+		Data:          resp,
+	})
 }
 
 func (s *Server) PostReserveNow(w http.ResponseWriter, r *http.Request, params PostReserveNowParams) {
-	w.WriteHeader(http.StatusNotImplemented)
+	reserveNow := new(ReserveNow)
+	if err := render.Bind(r, reserveNow); err != nil {
+		_ = render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+	if reserveNow.EvseUid == nil {
+		_ = render.Render(w, r, ErrInvalidRequest(errors.New("CSMS does not support reserve now commands without evse_uid")))
+		return
+	}
+	chargeStationId, err := extractChargeStationId(*reserveNow.EvseUid)
+	if err != nil {
+		_ = render.Render(w, r, ErrInvalidParameters(err, s.clock.Now()))
+		return
+	}
+	_, err = extractEvseId(*reserveNow.EvseUid)
+	if err != nil {
+		_ = render.Render(w, r, ErrInvalidParameters(err, s.clock.Now()))
+		return
+	}
+	expiryDate, err := time.Parse(time.RFC3339, reserveNow.ExpiryDate)
+	if err != nil {
+		_ = render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	resp, err := s.ocpi.ReserveNow(r.Context(), params.OCPIFromCountryCode, params.OCPIFromPartyId, chargeStationId, expiryDate, *reserveNow)
+	if err != nil {
+		slog.Error("error reserving charge station", "err", err)
+		_ = render.Render(w, r, ErrInternalError(err))
+		return
+	}
+
+	_ = render.Render(w, r, OcpiResponseCommandResponse{
+		StatusCode:    StatusSuccess,
+		StatusMessage: &StatusSuccessMessage,
+		Timestamp:     s.clock.Now().Format(time.RFC3339),
+		Data:          resp,
+	})
 }
 
 func (s *Server) PostStartSession(w http.ResponseWriter, r *http.Request, params PostStartSessionParams) {
@@ -430,10 +527,12 @@ func (s *Server) PostRealTimeTokenAuthorization(w http.ResponseWriter, r *http.R
 	w.WriteHeader(http.StatusNotImplemented)
 }
 
+// evseUidPattern matches EVSE uids of the form <country><party>E<charge station id>, optionally
+// followed by *<evse id> when the uid identifies a single EVSE of the charge station
+var evseUidPattern = regexp.MustCompile(`^[a-zA-Z]{5}E([a-zA-Z0-9]+)?(?:\*([0-9]+))?$`)
+
 func extractChargeStationId(evseId string) (string, error) {
-	pattern := `^[a-zA-Z]{5}E([a-zA-Z0-9]+)?$`
-	regex := regexp.MustCompile(pattern)
-	match := regex.FindStringSubmatch(evseId)
+	match := evseUidPattern.FindStringSubmatch(evseId)
 	if len(match) >= 2 {
 		chargePointID := match[1]
 		return chargePointID, nil
@@ -441,3 +540,20 @@ func extractChargeStationId(evseId string) (string, error) {
 		return "", fmt.Errorf("invalid EVSE ID format, could not extract charge point ID: %s", evseId)
 	}
 }
+
+// extractEvseId returns the OCPP EVSE id encoded in an EVSE uid or nil if the uid
+// refers to the charge station as a whole
+func extractEvseId(evseUid string) (*int, error) {
+	match := evseUidPattern.FindStringSubmatch(evseUid)
+	if match == nil {
+		return nil, fmt.Errorf("invalid EVSE ID format, could not extract EVSE ID: %s", evseUid)
+	}
+	if match[2] == "" {
+		return nil, nil
+	}
+	evseId, err := strconv.Atoi(match[2])
+	if err != nil {
+		return nil, fmt.Errorf("invalid EVSE ID format, could not extract EVSE ID: %s: %w", evseUid, err)
+	}
+	return &evseId, nil
+}
@@ -6,11 +6,14 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"github.com/go-chi/chi/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/thoughtworks/maeve-csms/manager/handlers"
 	"github.com/thoughtworks/maeve-csms/manager/handlers/ocpp16"
+	"github.com/thoughtworks/maeve-csms/manager/handlers/ocpp201"
+	"github.com/thoughtworks/maeve-csms/manager/handlers/ocpp21"
 	"github.com/thoughtworks/maeve-csms/manager/ocpi"
 	"github.com/thoughtworks/maeve-csms/manager/store"
 	"github.com/thoughtworks/maeve-csms/manager/store/inmemory"
@@ -34,6 +37,7 @@ func setupHandler(t *testing.T) (http.Handler, store.Engine, time.Time) {
 
 	ocpiApi := ocpi.NewOCPI(engine, http.DefaultClient, "GB", "TWK")
 	v16CallMaker := newNoopV16CallMaker()
+	ocpiApi.SetCallMakers(v16CallMaker, newNoopV201CallMaker(), newNoopV21CallMaker())
 	now := time.Now().UTC()
 	server, err := ocpi.NewServer(ocpiApi, fakeclock.NewFakePassiveClock(now), v16CallMaker)
 	require.NoError(t, err)
@@ -102,6 +106,11 @@ func TestServerGetVersion(t *testing.T) {
 					Url:        "/ocpi/receiver/2.2/tokens/",
 					Role:       ocpi.RECEIVER,
 				},
+				{
+					Identifier: "chargingprofiles",
+					Url:        "/ocpi/2.2/receiver/chargingprofiles",
+					Role:       ocpi.RECEIVER,
+				},
 			},
 			Version: "2.2",
 		},
@@ -331,9 +340,191 @@ func TestPostStartSession(t *testing.T) {
 	assert.Equal(t, ocpi.CommandResponseResultACCEPTED, ocpiResponseCommandResponse.Data.Result)
 }
 
+func TestPutReceiverChargingProfile(t *testing.T) {
+	handler, engine, _ := setupHandler(t)
+
+	ctx := context.Background()
+	err := engine.SetChargeStationRuntimeDetails(ctx, "cs001", &store.ChargeStationRuntimeDetails{
+		OcppVersion: "2.0.1",
+	})
+	require.NoError(t, err)
+	err = engine.CreateTransaction(ctx, "cs001", "session001", "DEADBEEF", "ISO14443", nil, 0, false)
+	require.NoError(t, err)
+	err = engine.SetTransactionEvseId(ctx, "cs001", "session001", 1)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPut, "/ocpi/2.2/receiver/chargingprofiles/session001",
+		strings.NewReader(`{
+			"response_url": "https://example.com/ocpi/2.2/sender/chargingprofiles/result/12345",
+			"charging_profile": {
+				"charging_rate_unit": "A",
+				"charging_profile_period": [
+					{ "start_period": 0, "limit": 16 }
+				]
+			}
+		}`))
+	req.Header.Set("Authorization", "Token 123")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Request-ID", "123")
+	req.Header.Set("X-Correlation-ID", "123")
+	req.Header.Set("OCPI-from-country-code", "GB")
+	req.Header.Set("OCPI-from-party-id", "EMS")
+	req.Header.Set("OCPI-to-country-code", "GB")
+	req.Header.Set("OCPI-to-party-id", "TWK")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	resp := w.Result()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	b, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	var got ocpi.OcpiResponseChargingProfileResponse
+	err = json.Unmarshal(b, &got)
+	require.NoError(t, err)
+	assert.Equal(t, ocpi.StatusSuccess, got.StatusCode)
+	require.NotNil(t, got.Data)
+	assert.Equal(t, ocpi.ChargingProfileResponseResultACCEPTED, got.Data.Result)
+
+	pending, err := engine.LookupChargingProfileRequest(ctx, "cs001", 1, store.OcpiChargingProfileRequestTypeSet)
+	require.NoError(t, err)
+	require.NotNil(t, pending)
+	assert.Equal(t, "EMS", pending.PartyId)
+}
+
+func TestPostReserveNow(t *testing.T) {
+	handler, engine, _ := setupHandler(t)
+
+	ctx := context.Background()
+	err := engine.SetChargeStationRuntimeDetails(ctx, "cs001", &store.ChargeStationRuntimeDetails{
+		OcppVersion: "1.6",
+	})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/ocpi/receiver/2.2/commands/RESERVE_NOW",
+		strings.NewReader(`{
+			"response_url": "https://example.com/ocpi/2.2/sender/commands/RESERVE_NOW/12345",
+			"token": {
+				"country_code": "GB",
+				"party_id": "EMS",
+				"uid": "DEADBEEF",
+				"type": "RFID",
+				"contract_id": "GBEMS012345678V",
+				"issuer": "Example",
+				"valid": true,
+				"whitelist": "NEVER",
+				"last_updated": "2023-06-15T15:00:00Z"
+			},
+			"expiry_date": "2023-06-15T15:30:00Z",
+			"reservation_id": "res001",
+			"location_id": "loc001",
+			"evse_uid": "GBTWKEcs001*1"
+		}`))
+	req.Header.Set("Authorization", "Token 123")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Request-ID", "123")
+	req.Header.Set("X-Correlation-ID", "123")
+	req.Header.Set("OCPI-from-country-code", "GB")
+	req.Header.Set("OCPI-from-party-id", "EMS")
+	req.Header.Set("OCPI-to-country-code", "GB")
+	req.Header.Set("OCPI-to-party-id", "TWK")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	resp := w.Result()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	b, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	var got ocpi.OcpiResponseCommandResponse
+	err = json.Unmarshal(b, &got)
+	require.NoError(t, err)
+	assert.Equal(t, ocpi.StatusSuccess, got.StatusCode)
+	require.NotNil(t, got.Data)
+	assert.Equal(t, ocpi.CommandResponseResultACCEPTED, got.Data.Result)
+
+	reservation, err := engine.FindReservationByOcpiId(ctx, "GB", "EMS", "res001")
+	require.NoError(t, err)
+	require.NotNil(t, reservation)
+	assert.Equal(t, "cs001", reservation.ChargeStationId)
+	require.NotNil(t, reservation.EvseId)
+	assert.Equal(t, 1, *reservation.EvseId)
+	assert.Equal(t, time.Date(2023, 6, 15, 15, 30, 0, 0, time.UTC), reservation.ExpiryDate)
+}
+
+func TestPostReserveNowWithMalformedEvseUid(t *testing.T) {
+	handler, engine, _ := setupHandler(t)
+
+	ctx := context.Background()
+	err := engine.SetChargeStationRuntimeDetails(ctx, "cs001", &store.ChargeStationRuntimeDetails{
+		OcppVersion: "1.6",
+	})
+	require.NoError(t, err)
+
+	for _, evseUid := range []string{"cs001", "GBTWKEcs001*99999999999999999999"} {
+		t.Run(evseUid, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/ocpi/receiver/2.2/commands/RESERVE_NOW",
+				strings.NewReader(fmt.Sprintf(`{
+					"response_url": "https://example.com/ocpi/2.2/sender/commands/RESERVE_NOW/12345",
+					"token": {
+						"country_code": "GB",
+						"party_id": "EMS",
+						"uid": "DEADBEEF",
+						"type": "RFID",
+						"contract_id": "GBEMS012345678V",
+						"issuer": "Example",
+						"valid": true,
+						"whitelist": "NEVER",
+						"last_updated": "2023-06-15T15:00:00Z"
+					},
+					"expiry_date": "2023-06-15T15:30:00Z",
+					"reservation_id": "res001",
+					"location_id": "loc001",
+					"evse_uid": %q
+				}`, evseUid)))
+			req.Header.Set("Authorization", "Token 123")
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("X-Request-ID", "123")
+			req.Header.Set("X-Correlation-ID", "123")
+			req.Header.Set("OCPI-from-country-code", "GB")
+			req.Header.Set("OCPI-from-party-id", "EMS")
+			req.Header.Set("OCPI-to-country-code", "GB")
+			req.Header.Set("OCPI-to-party-id", "TWK")
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+			resp := w.Result()
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+			b, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			var got ocpi.OcpiResponseCommandResponse
+			err = json.Unmarshal(b, &got)
+			require.NoError(t, err)
+			assert.Equal(t, ocpi.StatusInvalidParameters, got.StatusCode)
+			assert.Nil(t, got.Data)
+
+			reservation, err := engine.FindReservationByOcpiId(ctx, "GB", "EMS", "res001")
+			require.NoError(t, err)
+			assert.Nil(t, reservation)
+		})
+	}
+}
+
 func newNoopV16CallMaker() *handlers.OcppCallMaker {
 	emitter := transport.EmitterFunc(func(ctx context.Context, ocppVersion transport.OcppVersion, chargeStationId string, message *transport.Message) error {
 		return nil
 	})
 	return ocpp16.NewCallMaker(emitter)
 }
+
+func newNoopV201CallMaker() *handlers.OcppCallMaker {
+	emitter := transport.EmitterFunc(func(ctx context.Context, ocppVersion transport.OcppVersion, chargeStationId string, message *transport.Message) error {
+		return nil
+	})
+	return ocpp201.NewCallMaker(emitter)
+}
+
+func newNoopV21CallMaker() *handlers.OcppCallMaker {
+	emitter := transport.EmitterFunc(func(ctx context.Context, ocppVersion transport.OcppVersion, chargeStationId string, message *transport.Message) error {
+		return nil
+	})
+	return ocpp21.NewCallMaker(emitter)
+}
@@ -0,0 +1,15 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpp16
+
+type CancelReservationResponseJsonStatus string
+
+type CancelReservationResponseJson struct {
+	// Status corresponds to the JSON schema field "status".
+	Status CancelReservationResponseJsonStatus `json:"status" yaml:"status" mapstructure:"status"`
+}
+
+const CancelReservationResponseJsonStatusAccepted CancelReservationResponseJsonStatus = "Accepted"
+const CancelReservationResponseJsonStatusRejected CancelReservationResponseJsonStatus = "Rejected"
+
+func (*CancelReservationResponseJson) IsResponse() {}
@@ -0,0 +1,26 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpp16
+
+type ClearChargingProfileJsonChargingProfilePurpose string
+
+type ClearChargingProfileJson struct {
+	// ChargingProfilePurpose corresponds to the JSON schema field
+	// "chargingProfilePurpose".
+	ChargingProfilePurpose *ClearChargingProfileJsonChargingProfilePurpose `json:"chargingProfilePurpose,omitempty" yaml:"chargingProfilePurpose,omitempty" mapstructure:"chargingProfilePurpose,omitempty"`
+
+	// ConnectorId corresponds to the JSON schema field "connectorId".
+	ConnectorId *int `json:"connectorId,omitempty" yaml:"connectorId,omitempty" mapstructure:"connectorId,omitempty"`
+
+	// Id corresponds to the JSON schema field "id".
+	Id *int `json:"id,omitempty" yaml:"id,omitempty" mapstructure:"id,omitempty"`
+
+	// StackLevel corresponds to the JSON schema field "stackLevel".
+	StackLevel *int `json:"stackLevel,omitempty" yaml:"stackLevel,omitempty" mapstructure:"stackLevel,omitempty"`
+}
+
+const ClearChargingProfileJsonChargingProfilePurposeChargePointMaxProfile ClearChargingProfileJsonChargingProfilePurpose = "ChargePointMaxProfile"
+const ClearChargingProfileJsonChargingProfilePurposeTxDefaultProfile ClearChargingProfileJsonChargingProfilePurpose = "TxDefaultProfile"
+const ClearChargingProfileJsonChargingProfilePurposeTxProfile ClearChargingProfileJsonChargingProfilePurpose = "TxProfile"
+
+func (*ClearChargingProfileJson) IsRequest() {}
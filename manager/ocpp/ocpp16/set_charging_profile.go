@@ -0,0 +1,95 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpp16
+
+const SetChargingProfileJsonCsChargingProfilesChargingProfileKindAbsolute SetChargingProfileJsonCsChargingProfilesChargingProfileKind = "Absolute"
+const SetChargingProfileJsonCsChargingProfilesChargingProfileKindRecurring SetChargingProfileJsonCsChargingProfilesChargingProfileKind = "Recurring"
+const SetChargingProfileJsonCsChargingProfilesChargingProfileKindRelative SetChargingProfileJsonCsChargingProfilesChargingProfileKind = "Relative"
+
+type SetChargingProfileJsonCsChargingProfilesChargingProfilePurpose string
+
+const SetChargingProfileJsonCsChargingProfilesChargingProfilePurposeChargePointMaxProfile SetChargingProfileJsonCsChargingProfilesChargingProfilePurpose = "ChargePointMaxProfile"
+const SetChargingProfileJsonCsChargingProfilesChargingProfilePurposeTxDefaultProfile SetChargingProfileJsonCsChargingProfilesChargingProfilePurpose = "TxDefaultProfile"
+const SetChargingProfileJsonCsChargingProfilesChargingProfilePurposeTxProfile SetChargingProfileJsonCsChargingProfilesChargingProfilePurpose = "TxProfile"
+
+type SetChargingProfileJsonCsChargingProfilesChargingScheduleChargingRateUnit string
+
+type SetChargingProfileJsonCsChargingProfilesChargingProfileKind string
+
+type SetChargingProfileJsonCsChargingProfilesChargingScheduleChargingSchedulePeriodElem struct {
+	// Limit corresponds to the JSON schema field "limit".
+	Limit float64 `json:"limit" yaml:"limit" mapstructure:"limit"`
+
+	// NumberPhases corresponds to the JSON schema field "numberPhases".
+	NumberPhases *int `json:"numberPhases,omitempty" yaml:"numberPhases,omitempty" mapstructure:"numberPhases,omitempty"`
+
+	// StartPeriod corresponds to the JSON schema field "startPeriod".
+	StartPeriod int `json:"startPeriod" yaml:"startPeriod" mapstructure:"startPeriod"`
+}
+
+type SetChargingProfileJsonCsChargingProfilesChargingSchedule struct {
+	// ChargingRateUnit corresponds to the JSON schema field "chargingRateUnit".
+	ChargingRateUnit SetChargingProfileJsonCsChargingProfilesChargingScheduleChargingRateUnit `json:"chargingRateUnit" yaml:"chargingRateUnit" mapstructure:"chargingRateUnit"`
+
+	// ChargingSchedulePeriod corresponds to the JSON schema field
+	// "chargingSchedulePeriod".
+	ChargingSchedulePeriod []SetChargingProfileJsonCsChargingProfilesChargingScheduleChargingSchedulePeriodElem `json:"chargingSchedulePeriod" yaml:"chargingSchedulePeriod" mapstructure:"chargingSchedulePeriod"`
+
+	// Duration corresponds to the JSON schema field "duration".
+	Duration *int `json:"duration,omitempty" yaml:"duration,omitempty" mapstructure:"duration,omitempty"`
+
+	// MinChargingRate corresponds to the JSON schema field "minChargingRate".
+	MinChargingRate *float64 `json:"minChargingRate,omitempty" yaml:"minChargingRate,omitempty" mapstructure:"minChargingRate,omitempty"`
+
+	// StartSchedule corresponds to the JSON schema field "startSchedule".
+	StartSchedule *string `json:"startSchedule,omitempty" yaml:"startSchedule,omitempty" mapstructure:"startSchedule,omitempty"`
+}
+
+const SetChargingProfileJsonCsChargingProfilesChargingScheduleChargingRateUnitA SetChargingProfileJsonCsChargingProfilesChargingScheduleChargingRateUnit = "A"
+
+type SetChargingProfileJsonCsChargingProfilesRecurrencyKind string
+
+const SetChargingProfileJsonCsChargingProfilesChargingScheduleChargingRateUnitW SetChargingProfileJsonCsChargingProfilesChargingScheduleChargingRateUnit = "W"
+
+const SetChargingProfileJsonCsChargingProfilesRecurrencyKindDaily SetChargingProfileJsonCsChargingProfilesRecurrencyKind = "Daily"
+const SetChargingProfileJsonCsChargingProfilesRecurrencyKindWeekly SetChargingProfileJsonCsChargingProfilesRecurrencyKind = "Weekly"
+
+type SetChargingProfileJsonCsChargingProfiles struct {
+	// ChargingProfileId corresponds to the JSON schema field "chargingProfileId".
+	ChargingProfileId int `json:"chargingProfileId" yaml:"chargingProfileId" mapstructure:"chargingProfileId"`
+
+	// ChargingProfileKind corresponds to the JSON schema field "chargingProfileKind".
+	ChargingProfileKind SetChargingProfileJsonCsChargingProfilesChargingProfileKind `json:"chargingProfileKind" yaml:"chargingProfileKind" mapstructure:"chargingProfileKind"`
+
+	// ChargingProfilePurpose corresponds to the JSON schema field
+	// "chargingProfilePurpose".
+	ChargingProfilePurpose SetChargingProfileJsonCsChargingProfilesChargingProfilePurpose `json:"chargingProfilePurpose" yaml:"chargingProfilePurpose" mapstructure:"chargingProfilePurpose"`
+
+	// ChargingSchedule corresponds to the JSON schema field "chargingSchedule".
+	ChargingSchedule SetChargingProfileJsonCsChargingProfilesChargingSchedule `json:"chargingSchedule" yaml:"chargingSchedule" mapstructure:"chargingSchedule"`
+
+	// RecurrencyKind corresponds to the JSON schema field "recurrencyKind".
+	RecurrencyKind *SetChargingProfileJsonCsChargingProfilesRecurrencyKind `json:"recurrencyKind,omitempty" yaml:"recurrencyKind,omitempty" mapstructure:"recurrencyKind,omitempty"`
+
+	// StackLevel corresponds to the JSON schema field "stackLevel".
+	StackLevel int `json:"stackLevel" yaml:"stackLevel" mapstructure:"stackLevel"`
+
+	// TransactionId corresponds to the JSON schema field "transactionId".
+	TransactionId *int `json:"transactionId,omitempty" yaml:"transactionId,omitempty" mapstructure:"transactionId,omitempty"`
+
+	// ValidFrom corresponds to the JSON schema field "validFrom".
+	ValidFrom *string `json:"validFrom,omitempty" yaml:"validFrom,omitempty" mapstructure:"validFrom,omitempty"`
+
+	// ValidTo corresponds to the JSON schema field "validTo".
+	ValidTo *string `json:"validTo,omitempty" yaml:"validTo,omitempty" mapstructure:"validTo,omitempty"`
+}
+
+type SetChargingProfileJson struct {
+	// ConnectorId corresponds to the JSON schema field "connectorId".
+	ConnectorId int `json:"connectorId" yaml:"connectorId" mapstructure:"connectorId"`
+
+	// CsChargingProfiles corresponds to the JSON schema field "csChargingProfiles".
+	CsChargingProfiles SetChargingProfileJsonCsChargingProfiles `json:"csChargingProfiles" yaml:"csChargingProfiles" mapstructure:"csChargingProfiles"`
+}
+
+func (*SetChargingProfileJson) IsRequest() {}
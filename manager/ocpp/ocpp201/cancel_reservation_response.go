@@ -0,0 +1,21 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpp201
+
+type CancelReservationStatusEnumType string
+
+const CancelReservationStatusEnumTypeAccepted CancelReservationStatusEnumType = "Accepted"
+const CancelReservationStatusEnumTypeRejected CancelReservationStatusEnumType = "Rejected"
+
+type CancelReservationResponseJson struct {
+	// CustomData corresponds to the JSON schema field "customData".
+	CustomData *CustomDataType `json:"customData,omitempty" yaml:"customData,omitempty" mapstructure:"customData,omitempty"`
+
+	// Status corresponds to the JSON schema field "status".
+	Status CancelReservationStatusEnumType `json:"status" yaml:"status" mapstructure:"status"`
+
+	// StatusInfo corresponds to the JSON schema field "statusInfo".
+	StatusInfo *StatusInfoType `json:"statusInfo,omitempty" yaml:"statusInfo,omitempty" mapstructure:"statusInfo,omitempty"`
+}
+
+func (*CancelReservationResponseJson) IsResponse() {}
@@ -0,0 +1,48 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpp201
+
+type ClearChargingProfileRequestJson struct {
+	// ChargingProfileCriteria corresponds to the JSON schema field
+	// "chargingProfileCriteria".
+	ChargingProfileCriteria *ClearChargingProfileType `json:"chargingProfileCriteria,omitempty" yaml:"chargingProfileCriteria,omitempty" mapstructure:"chargingProfileCriteria,omitempty"`
+
+	// The Id of the charging profile to clear.
+	//
+	ChargingProfileId *int `json:"chargingProfileId,omitempty" yaml:"chargingProfileId,omitempty" mapstructure:"chargingProfileId,omitempty"`
+
+	// CustomData corresponds to the JSON schema field "customData".
+	CustomData *CustomDataType `json:"customData,omitempty" yaml:"customData,omitempty" mapstructure:"customData,omitempty"`
+}
+
+// Charging_ Profile
+// urn:x-oca:ocpp:uid:2:233255
+// A ChargingProfile consists of a ChargingSchedule, describing the amount of power
+// or current that can be delivered per time interval.
+type ClearChargingProfileType struct {
+	// ChargingProfilePurpose corresponds to the JSON schema field
+	// "chargingProfilePurpose".
+	ChargingProfilePurpose *ChargingProfilePurposeEnumType `json:"chargingProfilePurpose,omitempty" yaml:"chargingProfilePurpose,omitempty" mapstructure:"chargingProfilePurpose,omitempty"`
+
+	// CustomData corresponds to the JSON schema field "customData".
+	CustomData *CustomDataType `json:"customData,omitempty" yaml:"customData,omitempty" mapstructure:"customData,omitempty"`
+
+	// Identified_ Object. MRID. Numeric_ Identifier
+	// urn:x-enexis:ecdm:uid:1:569198
+	// Specifies the id of the EVSE for which to clear charging profiles. An evseId of
+	// zero (0) specifies the charging profile for the overall Charging Station.
+	// Absence of this parameter means the clearing applies to all charging profiles
+	// that match the other criteria in the request.
+	//
+	//
+	EvseId *int `json:"evseId,omitempty" yaml:"evseId,omitempty" mapstructure:"evseId,omitempty"`
+
+	// Charging_ Profile. Stack_ Level. Counter
+	// urn:x-oca:ocpp:uid:1:569230
+	// Specifies the stackLevel for which charging profiles will be cleared, if they
+	// meet the other criteria in the request.
+	//
+	StackLevel *int `json:"stackLevel,omitempty" yaml:"stackLevel,omitempty" mapstructure:"stackLevel,omitempty"`
+}
+
+func (*ClearChargingProfileRequestJson) IsRequest() {}
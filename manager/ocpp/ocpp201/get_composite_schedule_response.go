@@ -0,0 +1,51 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpp201
+
+// Composite_ Schedule
+// urn:x-oca:ocpp:uid:2:233362
+type CompositeScheduleType struct {
+	// ChargingRateUnit corresponds to the JSON schema field "chargingRateUnit".
+	ChargingRateUnit ChargingRateUnitEnumType `json:"chargingRateUnit" yaml:"chargingRateUnit" mapstructure:"chargingRateUnit"`
+
+	// ChargingSchedulePeriod corresponds to the JSON schema field
+	// "chargingSchedulePeriod".
+	ChargingSchedulePeriod []ChargingSchedulePeriodType `json:"chargingSchedulePeriod" yaml:"chargingSchedulePeriod" mapstructure:"chargingSchedulePeriod"`
+
+	// CustomData corresponds to the JSON schema field "customData".
+	CustomData *CustomDataType `json:"customData,omitempty" yaml:"customData,omitempty" mapstructure:"customData,omitempty"`
+
+	// Duration of the schedule in seconds.
+	//
+	Duration int `json:"duration" yaml:"duration" mapstructure:"duration"`
+
+	// The ID of the EVSE for which the
+	// schedule is requested. When evseid=0, the
+	// Charging Station calculated the expected
+	// consumption for the grid connection.
+	//
+	EvseId int `json:"evseId" yaml:"evseId" mapstructure:"evseId"`
+
+	// Composite_ Schedule. Start. Date_ Time
+	// urn:x-oca:ocpp:uid:1:569456
+	// Date and time at which the schedule becomes active. All time measurements
+	// within the schedule are relative to this timestamp.
+	//
+	ScheduleStart string `json:"scheduleStart" yaml:"scheduleStart" mapstructure:"scheduleStart"`
+}
+
+type GetCompositeScheduleResponseJson struct {
+	// CustomData corresponds to the JSON schema field "customData".
+	CustomData *CustomDataType `json:"customData,omitempty" yaml:"customData,omitempty" mapstructure:"customData,omitempty"`
+
+	// Schedule corresponds to the JSON schema field "schedule".
+	Schedule *CompositeScheduleType `json:"schedule,omitempty" yaml:"schedule,omitempty" mapstructure:"schedule,omitempty"`
+
+	// Status corresponds to the JSON schema field "status".
+	Status GenericStatusEnumType `json:"status" yaml:"status" mapstructure:"status"`
+
+	// StatusInfo corresponds to the JSON schema field "statusInfo".
+	StatusInfo *StatusInfoType `json:"statusInfo,omitempty" yaml:"statusInfo,omitempty" mapstructure:"statusInfo,omitempty"`
+}
+
+func (*GetCompositeScheduleResponseJson) IsResponse() {}
@@ -0,0 +1,23 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpp201
+
+type ReservationUpdateStatusEnumType string
+
+type ReservationStatusUpdateRequestJson struct {
+	// CustomData corresponds to the JSON schema field "customData".
+	CustomData *CustomDataType `json:"customData,omitempty" yaml:"customData,omitempty" mapstructure:"customData,omitempty"`
+
+	// The ID of the reservation.
+	//
+	ReservationId int `json:"reservationId" yaml:"reservationId" mapstructure:"reservationId"`
+
+	// ReservationUpdateStatus corresponds to the JSON schema field
+	// "reservationUpdateStatus".
+	ReservationUpdateStatus ReservationUpdateStatusEnumType `json:"reservationUpdateStatus" yaml:"reservationUpdateStatus" mapstructure:"reservationUpdateStatus"`
+}
+
+const ReservationUpdateStatusEnumTypeExpired ReservationUpdateStatusEnumType = "Expired"
+const ReservationUpdateStatusEnumTypeRemoved ReservationUpdateStatusEnumType = "Removed"
+
+func (*ReservationStatusUpdateRequestJson) IsRequest() {}
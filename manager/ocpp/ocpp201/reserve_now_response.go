@@ -0,0 +1,24 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpp201
+
+type ReserveNowResponseJson struct {
+	// CustomData corresponds to the JSON schema field "customData".
+	CustomData *CustomDataType `json:"customData,omitempty" yaml:"customData,omitempty" mapstructure:"customData,omitempty"`
+
+	// Status corresponds to the JSON schema field "status".
+	Status ReserveNowStatusEnumType `json:"status" yaml:"status" mapstructure:"status"`
+
+	// StatusInfo corresponds to the JSON schema field "statusInfo".
+	StatusInfo *StatusInfoType `json:"statusInfo,omitempty" yaml:"statusInfo,omitempty" mapstructure:"statusInfo,omitempty"`
+}
+
+type ReserveNowStatusEnumType string
+
+const ReserveNowStatusEnumTypeAccepted ReserveNowStatusEnumType = "Accepted"
+const ReserveNowStatusEnumTypeFaulted ReserveNowStatusEnumType = "Faulted"
+const ReserveNowStatusEnumTypeOccupied ReserveNowStatusEnumType = "Occupied"
+const ReserveNowStatusEnumTypeRejected ReserveNowStatusEnumType = "Rejected"
+const ReserveNowStatusEnumTypeUnavailable ReserveNowStatusEnumType = "Unavailable"
+
+func (*ReserveNowResponseJson) IsResponse() {}
@@ -0,0 +1,45 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpp21
+
+type AuthorizeCertificateStatusEnumType string
+
+const AuthorizeCertificateStatusEnumTypeAccepted AuthorizeCertificateStatusEnumType = "Accepted"
+const AuthorizeCertificateStatusEnumTypeCertChainError AuthorizeCertificateStatusEnumType = "CertChainError"
+const AuthorizeCertificateStatusEnumTypeCertificateExpired AuthorizeCertificateStatusEnumType = "CertificateExpired"
+const AuthorizeCertificateStatusEnumTypeCertificateRevoked AuthorizeCertificateStatusEnumType = "CertificateRevoked"
+const AuthorizeCertificateStatusEnumTypeContractCancelled AuthorizeCertificateStatusEnumType = "ContractCancelled"
+const AuthorizeCertificateStatusEnumTypeNoCertificateAvailable AuthorizeCertificateStatusEnumType = "NoCertificateAvailable"
+const AuthorizeCertificateStatusEnumTypeSignatureError AuthorizeCertificateStatusEnumType = "SignatureError"
+
+type AuthorizeResponseJson struct {
+	// *(2.1)* List of allowed energy transfer modes the EV can choose from. If
+	// omitted this defaults to charging only.
+	//
+	AllowedEnergyTransfer []EnergyTransferModeEnumType `json:"allowedEnergyTransfer,omitempty" yaml:"allowedEnergyTransfer,omitempty" mapstructure:"allowedEnergyTransfer,omitempty"`
+
+	// CertificateStatus corresponds to the JSON schema field "certificateStatus".
+	CertificateStatus *AuthorizeCertificateStatusEnumType `json:"certificateStatus,omitempty" yaml:"certificateStatus,omitempty" mapstructure:"certificateStatus,omitempty"`
+
+	// CustomData corresponds to the JSON schema field "customData".
+	CustomData *CustomDataType `json:"customData,omitempty" yaml:"customData,omitempty" mapstructure:"customData,omitempty"`
+
+	// IdTokenInfo corresponds to the JSON schema field "idTokenInfo".
+	IdTokenInfo IdTokenInfoType `json:"idTokenInfo" yaml:"idTokenInfo" mapstructure:"idTokenInfo"`
+}
+
+type EnergyTransferModeEnumType string
+
+const EnergyTransferModeEnumTypeACBPT EnergyTransferModeEnumType = "AC_BPT"
+const EnergyTransferModeEnumTypeACBPTDER EnergyTransferModeEnumType = "AC_BPT_DER"
+const EnergyTransferModeEnumTypeACDER EnergyTransferModeEnumType = "AC_DER"
+const EnergyTransferModeEnumTypeACSinglePhase EnergyTransferModeEnumType = "AC_single_phase"
+const EnergyTransferModeEnumTypeACThreePhase EnergyTransferModeEnumType = "AC_three_phase"
+const EnergyTransferModeEnumTypeACTwoPhase EnergyTransferModeEnumType = "AC_two_phase"
+const EnergyTransferModeEnumTypeDC EnergyTransferModeEnumType = "DC"
+const EnergyTransferModeEnumTypeDCACDP EnergyTransferModeEnumType = "DC_ACDP"
+const EnergyTransferModeEnumTypeDCACDPBPT EnergyTransferModeEnumType = "DC_ACDP_BPT"
+const EnergyTransferModeEnumTypeDCBPT EnergyTransferModeEnumType = "DC_BPT"
+const EnergyTransferModeEnumTypeWPT EnergyTransferModeEnumType = "WPT"
+
+func (*AuthorizeResponseJson) IsResponse() {}
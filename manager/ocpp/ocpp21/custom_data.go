@@ -0,0 +1,11 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpp21
+
+// This class does not get 'AdditionalProperties = false' in the schema generation,
+// so it can be extended with arbitrary JSON properties to allow adding custom
+// data.
+type CustomDataType struct {
+	// VendorId corresponds to the JSON schema field "vendorId".
+	VendorId string `json:"vendorId" yaml:"vendorId" mapstructure:"vendorId"`
+}
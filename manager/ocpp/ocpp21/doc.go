@@ -0,0 +1,8 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package ocpp21 contains types that represent the OCPP 2.1 protocol messages that
+// differ from their OCPP 2.0.1 equivalents: messages that are unchanged use the
+// types in the ocpp201 package. The files have been generated using gojsonschema
+// with all references to the CustomDataType removed (as this type is shared) and
+// with all explicit Unmarshaller functions removed as validation is handled separately.
+package ocpp21
@@ -0,0 +1,22 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpp21
+
+// EVSE
+// urn:x-oca:ocpp:uid:2:233123
+// Electric Vehicle Supply Equipment
+type EVSEType struct {
+	// An id to designate a specific connector (on an EVSE) by connector index number.
+	//
+	ConnectorId *int `json:"connectorId,omitempty" yaml:"connectorId,omitempty" mapstructure:"connectorId,omitempty"`
+
+	// CustomData corresponds to the JSON schema field "customData".
+	CustomData *CustomDataType `json:"customData,omitempty" yaml:"customData,omitempty" mapstructure:"customData,omitempty"`
+
+	// Identified_ Object. MRID. Numeric_ Identifier
+	// urn:x-enexis:ecdm:uid:1:569198
+	// EVSE Identifier. This contains a number (&gt; 0) designating an EVSE of the
+	// Charging Station.
+	//
+	Id int `json:"id" yaml:"id" mapstructure:"id"`
+}
@@ -0,0 +1,10 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpp21
+
+type MessageFormatEnumType string
+
+const MessageFormatEnumTypeASCII MessageFormatEnumType = "ASCII"
+const MessageFormatEnumTypeHTML MessageFormatEnumType = "HTML"
+const MessageFormatEnumTypeURI MessageFormatEnumType = "URI"
+const MessageFormatEnumTypeUTF8 MessageFormatEnumType = "UTF8"
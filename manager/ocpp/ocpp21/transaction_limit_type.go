@@ -0,0 +1,25 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpp21
+
+// *(2.1)* Cost, energy, time or SoC limit for a transaction.
+type TransactionLimitType struct {
+	// CustomData corresponds to the JSON schema field "customData".
+	CustomData *CustomDataType `json:"customData,omitempty" yaml:"customData,omitempty" mapstructure:"customData,omitempty"`
+
+	// Maximum allowed cost of transaction in currency of tariff.
+	//
+	MaxCost *float64 `json:"maxCost,omitempty" yaml:"maxCost,omitempty" mapstructure:"maxCost,omitempty"`
+
+	// Maximum allowed energy in Wh to charge in transaction.
+	//
+	MaxEnergy *float64 `json:"maxEnergy,omitempty" yaml:"maxEnergy,omitempty" mapstructure:"maxEnergy,omitempty"`
+
+	// Maximum State of Charge of EV in percentage.
+	//
+	MaxSoC *int `json:"maxSoC,omitempty" yaml:"maxSoC,omitempty" mapstructure:"maxSoC,omitempty"`
+
+	// Maximum duration of transaction in seconds from start to end.
+	//
+	MaxTime *int `json:"maxTime,omitempty" yaml:"maxTime,omitempty" mapstructure:"maxTime,omitempty"`
+}
@@ -0,0 +1,37 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package ocpp21
+
+type MessageTriggerEnumType string
+
+const MessageTriggerEnumTypeBootNotification MessageTriggerEnumType = "BootNotification"
+const MessageTriggerEnumTypeCustomTrigger MessageTriggerEnumType = "CustomTrigger"
+const MessageTriggerEnumTypeFirmwareStatusNotification MessageTriggerEnumType = "FirmwareStatusNotification"
+const MessageTriggerEnumTypeHeartbeat MessageTriggerEnumType = "Heartbeat"
+const MessageTriggerEnumTypeLogStatusNotification MessageTriggerEnumType = "LogStatusNotification"
+const MessageTriggerEnumTypeMeterValues MessageTriggerEnumType = "MeterValues"
+const MessageTriggerEnumTypePublishFirmwareStatusNotification MessageTriggerEnumType = "PublishFirmwareStatusNotification"
+const MessageTriggerEnumTypeSignChargingStationCertificate MessageTriggerEnumType = "SignChargingStationCertificate"
+const MessageTriggerEnumTypeSignCombinedCertificate MessageTriggerEnumType = "SignCombinedCertificate"
+const MessageTriggerEnumTypeSignV2GCertificate MessageTriggerEnumType = "SignV2GCertificate"
+const MessageTriggerEnumTypeStatusNotification MessageTriggerEnumType = "StatusNotification"
+const MessageTriggerEnumTypeTransactionEvent MessageTriggerEnumType = "TransactionEvent"
+
+type TriggerMessageRequestJson struct {
+	// CustomData corresponds to the JSON schema field "customData".
+	CustomData *CustomDataType `json:"customData,omitempty" yaml:"customData,omitempty" mapstructure:"customData,omitempty"`
+
+	// *(2.1)* When _requestedMessage_ = `CustomTrigger` this will trigger sending the
+	// corresponding message in field _customTrigger_, if supported by Charging
+	// Station.
+	//
+	CustomTrigger *string `json:"customTrigger,omitempty" yaml:"customTrigger,omitempty" mapstructure:"customTrigger,omitempty"`
+
+	// Evse corresponds to the JSON schema field "evse".
+	Evse *EVSEType `json:"evse,omitempty" yaml:"evse,omitempty" mapstructure:"evse,omitempty"`
+
+	// RequestedMessage corresponds to the JSON schema field "requestedMessage".
+	RequestedMessage MessageTriggerEnumType `json:"requestedMessage" yaml:"requestedMessage" mapstructure:"requestedMessage"`
+}
+
+func (*TriggerMessageRequestJson) IsRequest() {}
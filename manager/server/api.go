@@ -10,7 +10,9 @@ import (
 	"github.com/thoughtworks/maeve-csms/manager/ocpi"
 	"github.com/thoughtworks/maeve-csms/manager/services"
 	"github.com/thoughtworks/maeve-csms/manager/store"
+	"github.com/thoughtworks/maeve-csms/manager/transport"
 	"github.com/unrolled/secure"
+	"golang.org/x/exp/slog"
 	"k8s.io/utils/clock"
 	"net/http"
 	"os"
@@ -22,11 +24,15 @@ import (
 	"github.com/thoughtworks/maeve-csms/manager/templates"
 )
 
-func NewApiHandler(settings config.ApiSettings, engine store.Engine, ocpi ocpi.Api, csCertProvider services.ChargeStationCertificateProvider) http.Handler {
-	apiServer, err := api.NewServer(engine, clock.RealClock{}, ocpi)
+func NewApiHandler(settings config.ApiSettings, engine store.Engine, ocpi ocpi.Api, msgEmitter transport.Emitter, csCertProvider services.ChargeStationCertificateProvider, syncNotifier api.SyncNotifier) http.Handler {
+	controlEmitter, _ := msgEmitter.(transport.ControlEmitter)
+	apiServer, err := api.NewServer(engine, clock.RealClock{}, ocpi, controlEmitter)
 	if err != nil {
 		panic(err)
 	}
+	if syncNotifier != nil {
+		apiServer.SetSyncNotifier(syncNotifier)
+	}
 
 	var isDevelopment bool
 	if os.Getenv("ENVIRONMENT") == "dev" {
@@ -46,14 +52,35 @@ func NewApiHandler(settings config.ApiSettings, engine store.Engine, ocpi ocpi.A
 
 	r.Use(middleware.Recoverer, secureMiddleware.Handler, cors.Default().Handler, api.ValidationMiddleware)
 	r.Get("/health", health)
-	r.Get("/transactions", transactions(engine))
+	r.With(requireRole(api.RoleReadOnly, settings.Authenticators)).Get("/transactions", transactions(engine))
 	r.Handle("/metrics", promhttp.Handler())
 	r.Get("/api/openapi.json", getApiSwaggerJson)
-	r.With(logger).Mount("/api/v0", api.Handler(apiServer))
-	r.With(logger).Mount("/adminui", adminui.NewServer(settings.Host, settings.WsPort, settings.WssPort, settings.OrgName, engine, csCertProvider))
+	r.With(logger).Mount("/api/v0", api.HandlerWithOptions(apiServer, apiHandlerOptions(settings.Authenticators)))
+	r.With(logger, requireRole(api.RoleAdmin, settings.Authenticators)).Mount("/adminui", adminui.NewServer(settings.Host, settings.WsPort, settings.WssPort, settings.OrgName, engine, csCertProvider))
 	return r
 }
 
+// requireRole protects the handlers that sit outside the API with the same authenticators as the API
+func requireRole(role api.Role, authenticators []api.Authenticator) func(http.Handler) http.Handler {
+	if len(authenticators) == 0 {
+		return func(next http.Handler) http.Handler {
+			return next
+		}
+	}
+	return api.RequireRole(role, authenticators...)
+}
+
+func apiHandlerOptions(authenticators []api.Authenticator) api.ChiServerOptions {
+	if len(authenticators) == 0 {
+		slog.Warn("api authentication is disabled: configure api.auth to require authentication")
+		return api.ChiServerOptions{}
+	}
+
+	return api.ChiServerOptions{
+		Middlewares: []api.MiddlewareFunc{api.NewAuthMiddleware(authenticators...)},
+	}
+}
+
 func getApiSwaggerJson(w http.ResponseWriter, r *http.Request) {
 	swagger, err := api.GetSwagger()
 	if err != nil {
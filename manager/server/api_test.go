@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/thoughtworks/maeve-csms/manager/api"
 	"github.com/thoughtworks/maeve-csms/manager/config"
 	"github.com/thoughtworks/maeve-csms/manager/store/inmemory"
 	"io"
@@ -18,7 +19,7 @@ import (
 )
 
 func TestHealthHandler(t *testing.T) {
-	handler := server.NewApiHandler(config.ApiSettings{}, inmemory.NewStore(clock.RealClock{}), nil, nil)
+	handler := server.NewApiHandler(config.ApiSettings{}, inmemory.NewStore(clock.RealClock{}), nil, nil, nil, nil)
 
 	req := httptest.NewRequest(http.MethodGet, "/health", nil)
 	w := httptest.NewRecorder()
@@ -37,7 +38,7 @@ func TestHealthHandler(t *testing.T) {
 }
 
 func TestMetricsHandler(t *testing.T) {
-	handler := server.NewApiHandler(config.ApiSettings{}, inmemory.NewStore(clock.RealClock{}), nil, nil)
+	handler := server.NewApiHandler(config.ApiSettings{}, inmemory.NewStore(clock.RealClock{}), nil, nil, nil, nil)
 
 	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
 	w := httptest.NewRecorder()
@@ -61,7 +62,7 @@ func TestMetricsHandler(t *testing.T) {
 }
 
 func TestSwaggerHandler(t *testing.T) {
-	handler := server.NewApiHandler(config.ApiSettings{}, inmemory.NewStore(clock.RealClock{}), nil, nil)
+	handler := server.NewApiHandler(config.ApiSettings{}, inmemory.NewStore(clock.RealClock{}), nil, nil, nil, nil)
 
 	req := httptest.NewRequest(http.MethodGet, "/api/openapi.json", nil)
 	w := httptest.NewRecorder()
@@ -86,3 +87,35 @@ func TestSwaggerHandler(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, jsonData["info"].(map[string]any)["title"], "MaEVe CSMS")
 }
+
+func TestNonApiHandlersRequireAuthentication(t *testing.T) {
+	handler := server.NewApiHandler(config.ApiSettings{
+		Authenticators: []api.Authenticator{api.NewApiKeyAuthenticator(map[string]api.Principal{
+			"read-key": {Subject: "reader", Role: api.RoleReadOnly},
+		})},
+	}, inmemory.NewStore(clock.RealClock{}), nil, nil, nil, nil)
+
+	tests := map[string]struct {
+		path string
+		key  string
+		want int
+	}{
+		"transactions without credentials": {path: "/transactions", want: http.StatusUnauthorized},
+		"transactions with read-only role": {path: "/transactions", key: "read-key", want: http.StatusOK},
+		"adminui without credentials":      {path: "/adminui/", want: http.StatusUnauthorized},
+		"adminui with read-only role":      {path: "/adminui/", key: "read-key", want: http.StatusForbidden},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			if tc.key != "" {
+				req.Header.Set("X-API-Key", tc.key)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.want, w.Result().StatusCode)
+		})
+	}
+}
@@ -0,0 +1,108 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"github.com/thoughtworks/maeve-csms/manager/config"
+	"github.com/thoughtworks/maeve-csms/manager/store"
+	"github.com/thoughtworks/maeve-csms/manager/sync"
+	"github.com/thoughtworks/maeve-csms/manager/transport"
+	"golang.org/x/exp/slog"
+	"k8s.io/utils/clock"
+)
+
+// Run starts the API (and OCPI) servers, the sync processes and the message listeners
+// for the manager. It blocks until an error is written to errCh (which may also be used
+// by servers that the caller has started), then disconnects the listeners and returns
+// that error.
+func Run(cfg *config.BaseConfig, settings *config.Config, errCh chan error) error {
+	var err error
+
+	apiServer := New("api", cfg.Api.Addr, nil,
+		NewApiHandler(settings.Api, settings.Storage, settings.OcpiApi, settings.MsgEmitter, settings.ChargeStationCertProviderService, settings.SyncNotifier))
+
+	go backfillChargeStationDetails(settings.Storage)
+
+	sync.Sync(settings.Storage, clock.RealClock{}, settings.Tracer, settings.MsgEmitter, settings.OcpiApi, settings.SyncNotifier, settings.SyncRetryPolicies, settings.SyncCertificateRenewalPolicy, settings.SyncPollInterval, settings.SyncInstalledCertificatesRefresh, settings.SyncOperationLogRetention)
+
+	apiServer.Start(errCh)
+	var ocpp16Connection transport.Connection
+	if settings.Ocpp16Handler != nil {
+		ocpp16Connection, err = settings.MsgListener.Connect(context.Background(), transport.OcppVersion16, nil, settings.Ocpp16Handler)
+		if err != nil {
+			errCh <- err
+		}
+	}
+
+	var ocpp201Connection transport.Connection
+	if settings.Ocpp201Handler != nil {
+		ocpp201Connection, err = settings.MsgListener.Connect(context.Background(), transport.OcppVersion201, nil, settings.Ocpp201Handler)
+		if err != nil {
+			errCh <- err
+		}
+	}
+
+	var ocpp21Connection transport.Connection
+	if settings.Ocpp21Handler != nil {
+		ocpp21Connection, err = settings.MsgListener.Connect(context.Background(), transport.OcppVersion21, nil, settings.Ocpp21Handler)
+		if err != nil {
+			errCh <- err
+		}
+	}
+
+	var presenceConnection transport.Connection
+	if presenceListener, ok := settings.MsgListener.(transport.PresenceListener); ok {
+		presenceConnection, err = presenceListener.ConnectPresence(context.Background(), settings.PresenceHandler)
+		if err != nil {
+			errCh <- err
+		}
+	}
+
+	if settings.OcpiApi != nil {
+		ocpiServer := New("ocpi", cfg.Ocpi.Addr, nil, NewOcpiHandler(settings.Storage, clock.RealClock{}, settings.OcpiApi, settings.MsgEmitter))
+		ocpiServer.Start(errCh)
+	}
+
+	err = <-errCh
+
+	if ocpp16Connection != nil {
+		err := ocpp16Connection.Disconnect(context.Background())
+		if err != nil {
+			slog.Warn("disconnecting from broker", "err", err)
+		}
+	}
+	if ocpp201Connection != nil {
+		err := ocpp201Connection.Disconnect(context.Background())
+		if err != nil {
+			slog.Warn("disconnecting from broker", "err", err)
+		}
+	}
+	if ocpp21Connection != nil {
+		err := ocpp21Connection.Disconnect(context.Background())
+		if err != nil {
+			slog.Warn("disconnecting from broker", "err", err)
+		}
+	}
+	if presenceConnection != nil {
+		err := presenceConnection.Disconnect(context.Background())
+		if err != nil {
+			slog.Warn("disconnecting from broker", "err", err)
+		}
+	}
+
+	return err
+}
+
+// backfillChargeStationDetails creates the details records for charge stations that were
+// registered before details were recorded so that they are included when listing charge stations
+func backfillChargeStationDetails(engine store.Engine) {
+	created, err := engine.BackfillChargeStationDetails(context.Background())
+	if err != nil {
+		slog.Error("backfilling charge station details", "err", err)
+		return
+	}
+	if created > 0 {
+		slog.Info("backfilled charge station details", "count", created)
+	}
+}
@@ -0,0 +1,177 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package services
+
+import (
+	"context"
+	"github.com/thoughtworks/maeve-csms/manager/store"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/exp/slog"
+	"k8s.io/utils/clock"
+	"time"
+)
+
+// AuthLockoutStatus reports when the lockouts that apply to a basic auth attempt
+// expire: a zero time means that no lockout is in force
+type AuthLockoutStatus struct {
+	ChargeStationLockedUntil time.Time
+	RemoteAddrLockedUntil    time.Time
+}
+
+// LockedUntil returns the time at which both lockouts will have expired
+func (s *AuthLockoutStatus) LockedUntil() time.Time {
+	if s.RemoteAddrLockedUntil.After(s.ChargeStationLockedUntil) {
+		return s.RemoteAddrLockedUntil
+	}
+	return s.ChargeStationLockedUntil
+}
+
+// AuthLockoutService tracks failed basic auth attempts by charge station and by source
+// address, and locks out further attempts once too many have failed.
+type AuthLockoutService interface {
+	// RecordFailure records a failed authentication attempt and returns the resulting lockout status
+	RecordFailure(ctx context.Context, chargeStationId, remoteAddr string) (*AuthLockoutStatus, error)
+	// LookupStatus returns the current lockout status without recording an attempt
+	LookupStatus(ctx context.Context, chargeStationId, remoteAddr string) (*AuthLockoutStatus, error)
+}
+
+// StoreAuthLockoutService is an AuthLockoutService that records the failed attempts in
+// the store so that they are shared between all gateways. Each lockout lasts twice as
+// long as the previous one, up to MaxLockout, until there have been no failed attempts
+// for ResetAfter.
+type StoreAuthLockoutService struct {
+	Store store.AuthLockoutStore
+	Clock clock.PassiveClock
+	// MaxChargeStationFailures is the number of failures for a charge station that trigger a lockout (defaults to 5)
+	MaxChargeStationFailures int
+	// MaxRemoteAddrFailures is the number of failures from a source address that trigger a lockout (defaults to 20)
+	MaxRemoteAddrFailures int
+	// FailureWindow is how long a failed attempt is counted for (defaults to 15 minutes)
+	FailureWindow time.Duration
+	// BaseLockout is the duration of the first lockout (defaults to 1 minute)
+	BaseLockout time.Duration
+	// MaxLockout is the maximum duration of a lockout (defaults to 1 hour)
+	MaxLockout time.Duration
+	// ResetAfter is how long after the last failure that the lockout duration is reset (defaults to 24 hours)
+	ResetAfter time.Duration
+}
+
+func (s *StoreAuthLockoutService) RecordFailure(ctx context.Context, chargeStationId, remoteAddr string) (*AuthLockoutStatus, error) {
+	status := new(AuthLockoutStatus)
+
+	lockedUntil, err := s.recordFailure(ctx, store.AuthLockoutTypeChargeStation, chargeStationId,
+		withDefault(s.MaxChargeStationFailures, 5))
+	if err != nil {
+		return nil, err
+	}
+	status.ChargeStationLockedUntil = lockedUntil
+
+	if remoteAddr != "" {
+		lockedUntil, err = s.recordFailure(ctx, store.AuthLockoutTypeRemoteAddr, remoteAddr,
+			withDefault(s.MaxRemoteAddrFailures, 20))
+		if err != nil {
+			return nil, err
+		}
+		status.RemoteAddrLockedUntil = lockedUntil
+	}
+
+	return status, nil
+}
+
+func (s *StoreAuthLockoutService) recordFailure(ctx context.Context, lockoutType store.AuthLockoutType, id string, maxFailures int) (time.Time, error) {
+	now := s.Clock.Now().UTC()
+
+	// the failure is counted in a transaction so that concurrent failures reported by
+	// different gateways are not lost
+	var lockedOut bool
+	lockout, err := s.Store.UpdateAuthLockout(ctx, lockoutType, id, func(lockout *store.AuthLockout) bool {
+		lockedOut = false
+
+		// attempts made during a lockout are rejected before the password is checked
+		if lockout.LockedUntil.After(now) {
+			return false
+		}
+
+		sinceLastFailure := now.Sub(lockout.LastFailureAt)
+		if sinceLastFailure > withDefault(s.ResetAfter, 24*time.Hour) {
+			lockout.Lockouts = 0
+		}
+		if sinceLastFailure > withDefault(s.FailureWindow, 15*time.Minute) {
+			lockout.Failures = 0
+		}
+
+		lockout.Failures++
+		lockout.LastFailureAt = now
+
+		if lockout.Failures >= maxFailures {
+			duration := withDefault(s.BaseLockout, time.Minute)
+			maxDuration := withDefault(s.MaxLockout, time.Hour)
+			for i := 0; i < lockout.Lockouts && duration < maxDuration; i++ {
+				duration *= 2
+			}
+			if duration > maxDuration {
+				duration = maxDuration
+			}
+			lockout.LockedUntil = now.Add(duration)
+			lockout.Lockouts++
+			lockout.Failures = 0
+			lockedOut = true
+		}
+
+		return true
+	})
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if lockedOut {
+		slog.Warn("security event: basic auth locked out", "type", lockoutType, "id", id,
+			"lockedUntil", lockout.LockedUntil, "lockouts", lockout.Lockouts)
+		trace.SpanFromContext(ctx).AddEvent("security_event", trace.WithAttributes(
+			attribute.String("security_event.type", "AuthLockout"),
+			attribute.String("auth_lockout.type", string(lockoutType)),
+			attribute.String("auth_lockout.id", id),
+			attribute.Int("auth_lockout.count", lockout.Lockouts)))
+	}
+
+	return lockout.LockedUntil, nil
+}
+
+func (s *StoreAuthLockoutService) LookupStatus(ctx context.Context, chargeStationId, remoteAddr string) (*AuthLockoutStatus, error) {
+	status := new(AuthLockoutStatus)
+
+	lockedUntil, err := s.lookupLockedUntil(ctx, store.AuthLockoutTypeChargeStation, chargeStationId)
+	if err != nil {
+		return nil, err
+	}
+	status.ChargeStationLockedUntil = lockedUntil
+
+	if remoteAddr != "" {
+		lockedUntil, err = s.lookupLockedUntil(ctx, store.AuthLockoutTypeRemoteAddr, remoteAddr)
+		if err != nil {
+			return nil, err
+		}
+		status.RemoteAddrLockedUntil = lockedUntil
+	}
+
+	return status, nil
+}
+
+func (s *StoreAuthLockoutService) lookupLockedUntil(ctx context.Context, lockoutType store.AuthLockoutType, id string) (time.Time, error) {
+	lockout, err := s.Store.LookupAuthLockout(ctx, lockoutType, id)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if lockout == nil || !lockout.LockedUntil.After(s.Clock.Now()) {
+		return time.Time{}, nil
+	}
+	return lockout.LockedUntil, nil
+}
+
+func withDefault[T int | time.Duration](value, defaultValue T) T {
+	if value == 0 {
+		return defaultValue
+	}
+	return value
+}
@@ -0,0 +1,137 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package services_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thoughtworks/maeve-csms/manager/services"
+	"github.com/thoughtworks/maeve-csms/manager/store"
+	"github.com/thoughtworks/maeve-csms/manager/store/inmemory"
+	fakeclock "k8s.io/utils/clock/testing"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestAuthLockoutServiceLocksOutChargeStationAfterMaxFailures(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2023, 6, 15, 15, 0, 0, 0, time.UTC)
+	clock := fakeclock.NewFakeClock(now)
+	engine := inmemory.NewStore(clock)
+
+	lockoutService := &services.StoreAuthLockoutService{
+		Store:                    engine,
+		Clock:                    clock,
+		MaxChargeStationFailures: 3,
+	}
+
+	for i := 0; i < 2; i++ {
+		status, err := lockoutService.RecordFailure(ctx, "cs001", "192.168.1.1")
+		require.NoError(t, err)
+		assert.True(t, status.LockedUntil().IsZero())
+	}
+
+	status, err := lockoutService.RecordFailure(ctx, "cs001", "192.168.1.1")
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(time.Minute), status.ChargeStationLockedUntil)
+	assert.True(t, status.RemoteAddrLockedUntil.IsZero())
+
+	status, err = lockoutService.LookupStatus(ctx, "cs001", "192.168.1.2")
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(time.Minute), status.LockedUntil())
+
+	clock.Step(time.Minute)
+
+	status, err = lockoutService.LookupStatus(ctx, "cs001", "192.168.1.2")
+	require.NoError(t, err)
+	assert.True(t, status.LockedUntil().IsZero())
+}
+
+func TestAuthLockoutServiceDoublesLockoutDuration(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2023, 6, 15, 15, 0, 0, 0, time.UTC)
+	clock := fakeclock.NewFakeClock(now)
+	engine := inmemory.NewStore(clock)
+
+	lockoutService := &services.StoreAuthLockoutService{
+		Store:                    engine,
+		Clock:                    clock,
+		MaxChargeStationFailures: 1,
+		MaxLockout:               3 * time.Minute,
+	}
+
+	for _, want := range []time.Duration{time.Minute, 2 * time.Minute, 3 * time.Minute} {
+		status, err := lockoutService.RecordFailure(ctx, "cs001", "")
+		require.NoError(t, err)
+		assert.Equal(t, clock.Now().Add(want), status.ChargeStationLockedUntil)
+
+		// failures during the lockout do not extend it
+		status, err = lockoutService.RecordFailure(ctx, "cs001", "")
+		require.NoError(t, err)
+		assert.Equal(t, clock.Now().Add(want), status.ChargeStationLockedUntil)
+
+		clock.Step(want)
+	}
+
+	clock.Step(24 * time.Hour)
+
+	status, err := lockoutService.RecordFailure(ctx, "cs001", "")
+	require.NoError(t, err)
+	assert.Equal(t, clock.Now().Add(time.Minute), status.ChargeStationLockedUntil)
+}
+
+func TestAuthLockoutServiceLocksOutRemoteAddr(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2023, 6, 15, 15, 0, 0, 0, time.UTC)
+	clock := fakeclock.NewFakeClock(now)
+	engine := inmemory.NewStore(clock)
+
+	lockoutService := &services.StoreAuthLockoutService{
+		Store:                 engine,
+		Clock:                 clock,
+		MaxRemoteAddrFailures: 2,
+	}
+
+	_, err := lockoutService.RecordFailure(ctx, "cs001", "192.168.1.1")
+	require.NoError(t, err)
+	status, err := lockoutService.RecordFailure(ctx, "cs002", "192.168.1.1")
+	require.NoError(t, err)
+	assert.True(t, status.ChargeStationLockedUntil.IsZero())
+	assert.Equal(t, now.Add(time.Minute), status.RemoteAddrLockedUntil)
+
+	lockouts, err := engine.ListAuthLockouts(ctx, now, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, lockouts, 1)
+	assert.Equal(t, store.AuthLockoutTypeRemoteAddr, lockouts[0].Type)
+	assert.Equal(t, "192.168.1.1", lockouts[0].Id)
+}
+
+func TestAuthLockoutServiceCountsConcurrentFailures(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2023, 6, 15, 15, 0, 0, 0, time.UTC)
+	clock := fakeclock.NewFakeClock(now)
+	engine := inmemory.NewStore(clock)
+
+	lockoutService := &services.StoreAuthLockoutService{
+		Store:                    engine,
+		Clock:                    clock,
+		MaxChargeStationFailures: 10,
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := lockoutService.RecordFailure(ctx, "cs001", "")
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	status, err := lockoutService.LookupStatus(ctx, "cs001", "")
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(time.Minute), status.ChargeStationLockedUntil)
+}
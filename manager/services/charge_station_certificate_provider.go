@@ -6,7 +6,10 @@ import (
 	"bytes"
 	"context"
 	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
 	"crypto/rand"
+	"crypto/sha1"
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"encoding/asn1"
@@ -332,6 +335,198 @@ func (l *LocalChargeStationCertificateProvider) ProvideCertificate(ctx context.C
 	return pemSignedCert + pemSigningCert, nil
 }
 
+// LocalV2GChargeStationCertificateProvider issues V2G (SECC) certificates using a local V2G
+// PKI. The CertificateReader provides the CPO sub-CA chain starting with the sub-CA that signs
+// the SECC certificates (any self-signed root is not returned to the charge station) and the
+// PrivateKeyReader provides the private key of the signing sub-CA. The certificates follow the
+// ISO 15118-2 SECC certificate profile.
+type LocalV2GChargeStationCertificateProvider struct {
+	Store             store.CertificateStore
+	CertificateReader LocalSource
+	PrivateKeyReader  LocalSource
+}
+
+func (l *LocalV2GChargeStationCertificateProvider) ProvideCertificate(ctx context.Context, typ CertificateType, pemEncodedCSR string, csId string) (pemEncodedCertificateChain string, err error) {
+	if typ != CertificateTypeV2G {
+		return "", fmt.Errorf("local v2g provider cannot provide CSO certificate")
+	}
+
+	chain, err := readCertificateChain(ctx, l.CertificateReader)
+	if err != nil {
+		return "", err
+	}
+	certificate := chain[0]
+	if !certificate.IsCA {
+		return "", fmt.Errorf("signing certificate is not a CA certificate")
+	}
+
+	privateKey, err := readPrivateKey(ctx, l.PrivateKeyReader)
+	if err != nil {
+		return "", err
+	}
+	if key, ok := privateKey.(*ecdsa.PrivateKey); !ok || key.Curve != elliptic.P256() {
+		return "", fmt.Errorf("signing key must be an ECDSA secp256r1 key")
+	}
+
+	csr, err := readCsr(pemEncodedCSR)
+	if err != nil {
+		return "", err
+	}
+	if err = csr.CheckSignature(); err != nil {
+		return "", fmt.Errorf("checking csr signature: %w", err)
+	}
+	publicKey, ok := csr.PublicKey.(*ecdsa.PublicKey)
+	if !ok || publicKey.Curve != elliptic.P256() {
+		return "", fmt.Errorf("csr public key must be an ECDSA secp256r1 key")
+	}
+
+	subject, err := secc15118Subject(csr.RawSubject)
+	if err != nil {
+		return "", err
+	}
+
+	subjectKeyId, err := subjectKeyIdentifier(publicKey)
+	if err != nil {
+		return "", err
+	}
+
+	serial, err := rand.Int(rand.Reader, (&big.Int{}).Exp(big.NewInt(2), big.NewInt(159), nil))
+	if err != nil {
+		return "", fmt.Errorf("creating serial number: %w", err)
+	}
+
+	// the SECC certificate must not outlive the sub-CA that signs it
+	now := time.Now()
+	notAfter := now.AddDate(1, 0, 0)
+	if certificate.NotAfter.Before(notAfter) {
+		notAfter = certificate.NotAfter
+	}
+
+	template := x509.Certificate{
+		SerialNumber:          serial,
+		RawSubject:            subject,
+		NotBefore:             now,
+		NotAfter:              notAfter,
+		BasicConstraintsValid: true,
+		IsCA:                  false,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageKeyAgreement,
+		SubjectKeyId:          subjectKeyId,
+		SignatureAlgorithm:    x509.ECDSAWithSHA256,
+	}
+
+	signedCert, err := x509.CreateCertificate(rand.Reader, &template, certificate, publicKey, privateKey)
+	if err != nil {
+		return "", fmt.Errorf("creating certificate: %v", err)
+	}
+
+	pemSignedCert := string(pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: signedCert,
+	}))
+
+	err = l.Store.SetCertificate(ctx, pemSignedCert)
+	if err != nil {
+		return "", fmt.Errorf("adding certificate to store: %w", err)
+	}
+
+	pemCertificateChain := pemSignedCert
+	for _, cert := range chain {
+		if bytes.Equal(cert.RawSubject, cert.RawIssuer) {
+			continue
+		}
+		pemCertificateChain += string(pem.EncodeToMemory(&pem.Block{
+			Type:  "CERTIFICATE",
+			Bytes: cert.Raw,
+		}))
+	}
+
+	return pemCertificateChain, nil
+}
+
+var oidDomainComponent = asn1.ObjectIdentifier{0, 9, 2342, 19200300, 100, 1, 25}
+
+// secc15118Subject returns the subject requested in the CSR with the domain component that
+// ISO 15118-2 requires for SECC certificates (DC=CPO) added if the CSR does not include it
+func secc15118Subject(rawSubject []byte) ([]byte, error) {
+	var rdns pkix.RDNSequence
+	rest, err := asn1.Unmarshal(rawSubject, &rdns)
+	if err != nil {
+		return nil, fmt.Errorf("parsing csr subject: %w", err)
+	}
+	if len(rest) != 0 {
+		return nil, fmt.Errorf("trailing data after csr subject")
+	}
+
+	var commonName string
+	for _, rdn := range rdns {
+		for _, attr := range rdn {
+			if attr.Type.Equal(oidDomainComponent) {
+				if dc, ok := attr.Value.(string); !ok || dc != "CPO" {
+					return nil, fmt.Errorf("csr subject domain component must be CPO")
+				}
+				return rawSubject, nil
+			}
+			if attr.Type.Equal(asn1.ObjectIdentifier{2, 5, 4, 3}) {
+				commonName, _ = attr.Value.(string)
+			}
+		}
+	}
+	if commonName == "" {
+		return nil, fmt.Errorf("csr subject has no common name")
+	}
+
+	rdns = append(rdns, pkix.RelativeDistinguishedNameSET{
+		{Type: oidDomainComponent, Value: asn1.RawValue{Tag: asn1.TagIA5String, Bytes: []byte("CPO")}},
+	})
+	return asn1.Marshal(rdns)
+}
+
+// subjectKeyIdentifier returns the SHA-1 hash of the public key as described in
+// RFC 5280, section 4.2.1.2
+func subjectKeyIdentifier(publicKey crypto.PublicKey) ([]byte, error) {
+	der, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		return nil, fmt.Errorf("marshalling public key: %w", err)
+	}
+	var spki struct {
+		Algorithm        pkix.AlgorithmIdentifier
+		SubjectPublicKey asn1.BitString
+	}
+	_, err = asn1.Unmarshal(der, &spki)
+	if err != nil {
+		return nil, fmt.Errorf("parsing public key: %w", err)
+	}
+	hash := sha1.Sum(spki.SubjectPublicKey.Bytes)
+	return hash[:], nil
+}
+
+// readCertificateChain reads all the certificates provided by the certificateSource in order
+func readCertificateChain(ctx context.Context, certificateSource LocalSource) ([]*x509.Certificate, error) {
+	pemCertificates, err := certificateSource.GetData(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("reading certificate: %v", err)
+	}
+
+	var chain []*x509.Certificate
+	block, r := pem.Decode([]byte(pemCertificates))
+	for block != nil {
+		if block.Type == "CERTIFICATE" {
+			certificate, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				return nil, fmt.Errorf("cannot parse certificate: %v", err)
+			}
+			chain = append(chain, certificate)
+		}
+		block, r = pem.Decode(r)
+	}
+
+	if len(chain) == 0 {
+		return nil, fmt.Errorf("no signing certificate")
+	}
+
+	return chain, nil
+}
+
 func readSigningCertificate(ctx context.Context, certificateSource LocalSource) (*x509.Certificate, error) {
 	pemCertificate, err := certificateSource.GetData(ctx)
 	if err != nil {
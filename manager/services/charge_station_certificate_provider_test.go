@@ -410,6 +410,103 @@ func TestLocalChargeStationCertificateIsAddedToStore(t *testing.T) {
 	require.NotEqual(t, "", cert)
 }
 
+func TestLocalV2GChargeStationCertificateProvider(t *testing.T) {
+	store := inmemory.NewStore(clock.RealClock{})
+
+	rootCert, rootKey := createRootCACertificate(t, "V2G Root")
+	subCA1Cert, subCA1Key := createIntermediateCACertificate(t, "CPO Sub-CA 1", "", rootCert, rootKey)
+	subCA2Cert, subCA2Key := createIntermediateCACertificate(t, "CPO Sub-CA 2", "", subCA1Cert, subCA1Key)
+
+	privateKey, err := x509.MarshalPKCS8PrivateKey(subCA2Key)
+	require.NoError(t, err)
+
+	var pemCertificates []byte
+	for _, cert := range []*x509.Certificate{subCA2Cert, subCA1Cert, rootCert} {
+		pemCertificates = append(pemCertificates, pem.EncodeToMemory(&pem.Block{
+			Type:  "CERTIFICATE",
+			Bytes: cert.Raw,
+		})...)
+	}
+
+	pemPrivateKey := pem.EncodeToMemory(&pem.Block{
+		Type:  "PRIVATE KEY",
+		Bytes: privateKey,
+	})
+
+	certificateProvider := &services.LocalV2GChargeStationCertificateProvider{
+		Store:             store,
+		CertificateReader: services.StringSource{Data: string(pemCertificates)},
+		PrivateKeyReader:  services.StringSource{Data: string(pemPrivateKey)},
+	}
+
+	pemCsr := createCertificateSigningRequest(t)
+
+	ctx := context.TODO()
+	chain, err := certificateProvider.ProvideCertificate(ctx, services.CertificateTypeV2G, string(pemCsr), "cs001")
+	require.NoError(t, err)
+
+	var certs []*x509.Certificate
+	block, r := pem.Decode([]byte(chain))
+	for block != nil {
+		if block.Type == "CERTIFICATE" {
+			x509Cert, err := x509.ParseCertificate(block.Bytes)
+			require.NoError(t, err)
+			certs = append(certs, x509Cert)
+		}
+		block, r = pem.Decode(r)
+	}
+	// the root certificate is not included in the chain
+	require.Len(t, certs, 3)
+	assert.Equal(t, subCA2Cert.Raw, certs[1].Raw)
+	assert.Equal(t, subCA1Cert.Raw, certs[2].Raw)
+
+	leafCert := certs[0]
+	assert.Equal(t, "cs001", leafCert.Subject.CommonName)
+	assert.Contains(t, leafCert.Subject.Names, pkix.AttributeTypeAndValue{
+		Type:  asn1.ObjectIdentifier{0, 9, 2342, 19200300, 100, 1, 25},
+		Value: "CPO",
+	})
+	assert.False(t, leafCert.IsCA)
+	assert.Equal(t, x509.KeyUsageDigitalSignature|x509.KeyUsageKeyAgreement, leafCert.KeyUsage)
+	assert.Empty(t, leafCert.ExtKeyUsage)
+	assert.Equal(t, x509.ECDSAWithSHA256, leafCert.SignatureAlgorithm)
+	assert.NotEmpty(t, leafCert.SubjectKeyId)
+	assert.Equal(t, subCA2Cert.SubjectKeyId, leafCert.AuthorityKeyId)
+	assert.False(t, leafCert.NotAfter.After(subCA2Cert.NotAfter))
+
+	roots := x509.NewCertPool()
+	roots.AddCert(rootCert)
+	intermediates := x509.NewCertPool()
+	intermediates.AddCert(certs[1])
+	intermediates.AddCert(certs[2])
+	_, err = leafCert.Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	require.NoError(t, err)
+
+	cert, err := store.LookupCertificate(ctx, getCertificateHash(leafCert))
+	require.NoError(t, err)
+	require.NotEqual(t, "", cert)
+}
+
+func TestLocalV2GChargeStationCertificateProviderRejectsCSOCertificate(t *testing.T) {
+	store := inmemory.NewStore(clock.RealClock{})
+
+	certificateProvider := &services.LocalV2GChargeStationCertificateProvider{
+		Store:             store,
+		CertificateReader: services.StringSource{},
+		PrivateKeyReader:  services.StringSource{},
+	}
+
+	pemCsr := createCertificateSigningRequest(t)
+
+	chain, err := certificateProvider.ProvideCertificate(context.TODO(), services.CertificateTypeCSO, string(pemCsr), "cs001")
+	assert.Error(t, err)
+	assert.Equal(t, "", chain)
+}
+
 func getCertificateHash(cert *x509.Certificate) string {
 	hash := sha256.Sum256(cert.Raw)
 	b64Hash := base64.RawURLEncoding.EncodeToString(hash[:])
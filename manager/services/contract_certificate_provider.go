@@ -5,15 +5,31 @@ package services
 import (
 	"bytes"
 	"context"
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"github.com/thoughtworks/maeve-csms/manager/iso15118"
 	"github.com/thoughtworks/maeve-csms/manager/ocpp/ocpp201"
+	"github.com/thoughtworks/maeve-csms/manager/store"
 	"go.opentelemetry.io/otel/codes"
 	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
 	"go.opentelemetry.io/otel/trace"
 	"io"
+	"math/big"
 	"net/http"
+	"time"
 )
 
 const XsdMsgDefinition = "urn:iso:15118:2:2013:MsgDef"
@@ -186,3 +202,293 @@ func (d DefaultContractCertificateProvider) ProvideCertificate(context.Context,
 		Status: ocpp201.Iso15118EVCertificateStatusEnumTypeFailed,
 	}, errors.New("not implemented")
 }
+
+// LocalContractCertificateProvider issues contract certificates using a local MO PKI so that
+// Plug & Charge can be used without an external contract certificate pool. The contract is
+// found using the provisioning certificate id (the common name of the OEM provisioning
+// certificate) of the vehicle. The CertificateReader provides the MO sub-CA chain starting with
+// the sub-CA that signs the contract certificates and the PrivateKeyReader provides its private
+// key. The ProvisioningCertificateReader provides the certificate provisioning service chain
+// starting with the certificate that signs the response and the ProvisioningPrivateKeyReader
+// provides its private key. Any self-signed root in either chain is not returned to the vehicle.
+//
+// The signature of the request is verified using the OEM provisioning certificate, which must
+// chain to one of the OEM roots provided by the OEMRootCertificateProvider. The request does not
+// include the OEM sub-CAs, so the provider must also provide these: any certificate that is not
+// self-signed is used as an intermediate. The validation is only skipped if
+// SkipOEMRootValidation is set.
+type LocalContractCertificateProvider struct {
+	Store                         store.ContractStore
+	CertificateReader             LocalSource
+	PrivateKeyReader              LocalSource
+	ProvisioningCertificateReader LocalSource
+	ProvisioningPrivateKeyReader  LocalSource
+	OEMRootCertificateProvider    RootCertificateProviderService
+	SkipOEMRootValidation         bool
+}
+
+func (l *LocalContractCertificateProvider) ProvideCertificate(ctx context.Context, exiRequest string) (EvCertificate15118Response, error) {
+	res, err := l.provideCertificate(ctx, exiRequest)
+	if err != nil {
+		return EvCertificate15118Response{
+			Status: ocpp201.Iso15118EVCertificateStatusEnumTypeFailed,
+		}, err
+	}
+
+	return EvCertificate15118Response{
+		Status:                     ocpp201.Iso15118EVCertificateStatusEnumTypeAccepted,
+		CertificateInstallationRes: res,
+	}, nil
+}
+
+func (l *LocalContractCertificateProvider) provideCertificate(ctx context.Context, exiRequest string) (string, error) {
+	exi, err := base64.StdEncoding.DecodeString(exiRequest)
+	if err != nil {
+		return "", fmt.Errorf("decoding exi request: %w", err)
+	}
+	req, err := iso15118.DecodeCertificateInstallationReq(exi)
+	if err != nil {
+		return "", fmt.Errorf("decoding certificate installation request: %w", err)
+	}
+
+	oemCertificate, err := x509.ParseCertificate(req.Body.OEMProvisioningCert)
+	if err != nil {
+		return "", fmt.Errorf("parsing oem provisioning certificate: %w", err)
+	}
+	err = l.verifyOEMProvisioningCertificate(ctx, oemCertificate)
+	if err != nil {
+		return "", fmt.Errorf("verifying oem provisioning certificate: %w", err)
+	}
+	oemPublicKey, ok := oemCertificate.PublicKey.(*ecdsa.PublicKey)
+	if !ok || oemPublicKey.Curve != elliptic.P256() {
+		return "", fmt.Errorf("oem provisioning certificate public key must be an ECDSA secp256r1 key")
+	}
+	err = iso15118.VerifyCertificateInstallationReq(req, oemPublicKey)
+	if err != nil {
+		return "", fmt.Errorf("verifying certificate installation request: %w", err)
+	}
+
+	pcid := oemCertificate.Subject.CommonName
+	contract, err := l.Store.LookupContractByProvisioningCertificateId(ctx, pcid)
+	if err != nil {
+		return "", fmt.Errorf("looking up contract: %w", err)
+	}
+	if contract == nil {
+		return "", fmt.Errorf("no contract for provisioning certificate id %s", pcid)
+	}
+
+	chain, err := readCertificateChain(ctx, l.CertificateReader)
+	if err != nil {
+		return "", err
+	}
+	if !chain[0].IsCA {
+		return "", fmt.Errorf("signing certificate is not a CA certificate")
+	}
+	privateKey, err := readPrivateKey(ctx, l.PrivateKeyReader)
+	if err != nil {
+		return "", err
+	}
+	if key, ok := privateKey.(*ecdsa.PrivateKey); !ok || key.Curve != elliptic.P256() {
+		return "", fmt.Errorf("signing key must be an ECDSA secp256r1 key")
+	}
+
+	provisioningChain, err := readCertificateChain(ctx, l.ProvisioningCertificateReader)
+	if err != nil {
+		return "", err
+	}
+	provisioningPrivateKey, err := readPrivateKey(ctx, l.ProvisioningPrivateKeyReader)
+	if err != nil {
+		return "", err
+	}
+	provisioningKey, ok := provisioningPrivateKey.(*ecdsa.PrivateKey)
+	if !ok || provisioningKey.Curve != elliptic.P256() {
+		return "", fmt.Errorf("provisioning key must be an ECDSA secp256r1 key")
+	}
+
+	contractKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return "", fmt.Errorf("generating contract key: %w", err)
+	}
+	contractCertificate, err := createContractCertificate(contract.EMAID, &contractKey.PublicKey, chain[0], privateKey)
+	if err != nil {
+		return "", err
+	}
+
+	encryptedPrivateKey, dhPublicKey, err := encryptContractPrivateKey(contractKey, oemPublicKey)
+	if err != nil {
+		return "", err
+	}
+
+	res := &iso15118.CertificateInstallationResMessage{
+		Header: iso15118.MessageHeader{
+			SessionID: req.Header.SessionID,
+		},
+		Body: iso15118.CertificateInstallationRes{
+			ResponseCode: iso15118.ResponseCodeOK,
+			SAProvisioningCertificateChain: iso15118.CertificateChain{
+				Certificate:     provisioningChain[0].Raw,
+				SubCertificates: subCertificates(provisioningChain[1:]),
+			},
+			ContractSignatureCertChain: iso15118.CertificateChain{
+				Id:              "id1",
+				Certificate:     contractCertificate,
+				SubCertificates: subCertificates(chain),
+			},
+			ContractSignatureEncryptedPrivateKey: iso15118.ContractSignatureEncryptedPrivateKey{
+				Id:    "id2",
+				Value: encryptedPrivateKey,
+			},
+			DHpublickey: iso15118.DiffieHellmanPublickey{
+				Id:    "id3",
+				Value: dhPublicKey,
+			},
+			EMAID: iso15118.EMAID{
+				Id:    "id4",
+				Value: contract.EMAID,
+			},
+		},
+	}
+	err = iso15118.SignCertificateInstallationRes(res, provisioningKey)
+	if err != nil {
+		return "", fmt.Errorf("signing certificate installation response: %w", err)
+	}
+	exi, err = iso15118.EncodeCertificateInstallationRes(res)
+	if err != nil {
+		return "", fmt.Errorf("encoding certificate installation response: %w", err)
+	}
+
+	return base64.StdEncoding.EncodeToString(exi), nil
+}
+
+// verifyOEMProvisioningCertificate checks that the OEM provisioning certificate chains to one of
+// the OEM roots
+func (l *LocalContractCertificateProvider) verifyOEMProvisioningCertificate(ctx context.Context, oemCertificate *x509.Certificate) error {
+	if l.SkipOEMRootValidation {
+		return nil
+	}
+	if l.OEMRootCertificateProvider == nil {
+		return errors.New("no oem root certificates configured")
+	}
+	certificates, err := l.OEMRootCertificateProvider.ProvideCertificates(ctx)
+	if err != nil {
+		return fmt.Errorf("providing oem root certificates: %w", err)
+	}
+
+	roots := x509.NewCertPool()
+	intermediates := x509.NewCertPool()
+	for _, certificate := range certificates {
+		if bytes.Equal(certificate.RawSubject, certificate.RawIssuer) {
+			roots.AddCert(certificate)
+		} else {
+			intermediates.AddCert(certificate)
+		}
+	}
+
+	_, err = oemCertificate.Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	return err
+}
+
+// createContractCertificate creates a DER encoded contract certificate for the eMAID that
+// follows the ISO 15118-2 contract certificate profile
+func createContractCertificate(emaid string, publicKey *ecdsa.PublicKey, issuer *x509.Certificate, issuerKey crypto.PrivateKey) ([]byte, error) {
+	subject, err := asn1.Marshal(pkix.RDNSequence{
+		{{Type: asn1.ObjectIdentifier{2, 5, 4, 3}, Value: emaid}},
+		{{Type: oidDomainComponent, Value: asn1.RawValue{Tag: asn1.TagIA5String, Bytes: []byte("MO")}}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("marshalling subject: %w", err)
+	}
+
+	subjectKeyId, err := subjectKeyIdentifier(publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	serial, err := rand.Int(rand.Reader, (&big.Int{}).Exp(big.NewInt(2), big.NewInt(159), nil))
+	if err != nil {
+		return nil, fmt.Errorf("creating serial number: %w", err)
+	}
+
+	// the contract certificate must not outlive the sub-CA that signs it
+	now := time.Now()
+	notAfter := now.AddDate(1, 0, 0)
+	if issuer.NotAfter.Before(notAfter) {
+		notAfter = issuer.NotAfter
+	}
+
+	template := x509.Certificate{
+		SerialNumber:          serial,
+		RawSubject:            subject,
+		NotBefore:             now,
+		NotAfter:              notAfter,
+		BasicConstraintsValid: true,
+		IsCA:                  false,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageContentCommitment,
+		SubjectKeyId:          subjectKeyId,
+		SignatureAlgorithm:    x509.ECDSAWithSHA256,
+	}
+
+	certificate, err := x509.CreateCertificate(rand.Reader, &template, issuer, publicKey, issuerKey)
+	if err != nil {
+		return nil, fmt.Errorf("creating certificate: %v", err)
+	}
+	return certificate, nil
+}
+
+// encryptContractPrivateKey encrypts the contract certificate private key for the vehicle
+// as described in ISO 15118-2: the key is derived from an ephemeral ECDH key agreement with the
+// OEM provisioning certificate public key using the concatenation KDF and the private key is
+// encrypted with AES-128-CBC. It returns the IV followed by the encrypted private key and the
+// uncompressed ephemeral public key.
+func encryptContractPrivateKey(contractKey *ecdsa.PrivateKey, oemPublicKey *ecdsa.PublicKey) ([]byte, []byte, error) {
+	peerKey, err := oemPublicKey.ECDH()
+	if err != nil {
+		return nil, nil, fmt.Errorf("converting oem provisioning public key: %w", err)
+	}
+	ephemeralKey, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("generating ephemeral key: %w", err)
+	}
+	sharedSecret, err := ephemeralKey.ECDH(peerKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("computing shared secret: %w", err)
+	}
+
+	// concatenation KDF (NIST SP 800-56A) with a single round: counter || Z || OtherInfo, where
+	// OtherInfo is the AlgorithmID (0x01), PartyUInfo (0x55) and PartyVInfo (0x56)
+	kdf := sha256.New()
+	kdf.Write([]byte{0x00, 0x00, 0x00, 0x01})
+	kdf.Write(sharedSecret)
+	kdf.Write([]byte{0x01, 0x55, 0x56})
+	sessionKey := kdf.Sum(nil)[:16]
+
+	block, err := aes.NewCipher(sessionKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("creating cipher: %w", err)
+	}
+	encrypted := make([]byte, aes.BlockSize+32)
+	iv := encrypted[:aes.BlockSize]
+	if _, err = rand.Read(iv); err != nil {
+		return nil, nil, fmt.Errorf("creating iv: %w", err)
+	}
+	// the 32 byte private key is a multiple of the block size so is not padded
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(encrypted[aes.BlockSize:], contractKey.D.FillBytes(make([]byte, 32)))
+
+	return encrypted, ephemeralKey.PublicKey().Bytes(), nil
+}
+
+// subCertificates returns the DER encoded certificates in the chain that are not self-signed
+func subCertificates(chain []*x509.Certificate) [][]byte {
+	var certificates [][]byte
+	for _, cert := range chain {
+		if bytes.Equal(cert.RawSubject, cert.RawIssuer) {
+			continue
+		}
+		certificates = append(certificates, cert.Raw)
+	}
+	return certificates
+}
@@ -4,16 +4,32 @@ package services_test
 
 import (
 	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
 	"encoding/json"
+	"encoding/pem"
 	"fmt"
 	"io"
+	"math/big"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
+	"github.com/thoughtworks/maeve-csms/manager/iso15118"
 	"github.com/thoughtworks/maeve-csms/manager/ocpp/ocpp201"
+	"github.com/thoughtworks/maeve-csms/manager/store"
+	"github.com/thoughtworks/maeve-csms/manager/store/inmemory"
+	"k8s.io/utils/clock"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/thoughtworks/maeve-csms/manager/services"
 )
 
@@ -151,3 +167,250 @@ func TestContractCertificateProviderFailureCases(t *testing.T) {
 		})
 	}
 }
+
+func pemEncodeChain(certs ...*x509.Certificate) string {
+	var pemCertificates []byte
+	for _, cert := range certs {
+		pemCertificates = append(pemCertificates, pem.EncodeToMemory(&pem.Block{
+			Type:  "CERTIFICATE",
+			Bytes: cert.Raw,
+		})...)
+	}
+	return string(pemCertificates)
+}
+
+func pemEncodePrivateKey(t *testing.T, key *ecdsa.PrivateKey) string {
+	privateKey, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	return string(pem.EncodeToMemory(&pem.Block{
+		Type:  "PRIVATE KEY",
+		Bytes: privateKey,
+	}))
+}
+
+func createCertificateInstallationRequest(t *testing.T, oemCert *x509.Certificate, oemKey *ecdsa.PrivateKey) string {
+	req := &iso15118.CertificateInstallationReqMessage{
+		Header: iso15118.MessageHeader{
+			SessionID: []byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08},
+		},
+		Body: iso15118.CertificateInstallationReq{
+			Id:                  "id1",
+			OEMProvisioningCert: oemCert.Raw,
+			ListOfRootCertificateIDs: []iso15118.X509IssuerSerial{
+				{X509IssuerName: "CN=V2G Root,O=Thoughtworks", X509SerialNumber: big.NewInt(1)},
+			},
+		},
+	}
+	err := iso15118.SignCertificateInstallationReq(req, oemKey)
+	require.NoError(t, err)
+	exi, err := iso15118.EncodeCertificateInstallationReq(req)
+	require.NoError(t, err)
+	return base64.StdEncoding.EncodeToString(exi)
+}
+
+func TestLocalContractCertificateProvider(t *testing.T) {
+	engine := inmemory.NewStore(clock.RealClock{})
+	ctx := context.Background()
+
+	oemRootCert, oemRootKey := createRootCACertificate(t, "OEM Root")
+	oemCert, oemKey := createIntermediateCACertificate(t, "WMIV1234567890ABCDEX", "", oemRootCert, oemRootKey)
+
+	moRootCert, moRootKey := createRootCACertificate(t, "MO Root")
+	moSubCA1Cert, moSubCA1Key := createIntermediateCACertificate(t, "MO Sub-CA 1", "", moRootCert, moRootKey)
+	moSubCA2Cert, moSubCA2Key := createIntermediateCACertificate(t, "MO Sub-CA 2", "", moSubCA1Cert, moSubCA1Key)
+
+	v2gRootCert, v2gRootKey := createRootCACertificate(t, "V2G Root")
+	cpsSubCACert, cpsSubCAKey := createIntermediateCACertificate(t, "CPS Sub-CA", "", v2gRootCert, v2gRootKey)
+	cpsCert, cpsKey := createIntermediateCACertificate(t, "CPS Leaf", "", cpsSubCACert, cpsSubCAKey)
+
+	err := engine.SetContract(ctx, &store.Contract{
+		EMAID:                     "GBTWK012345678V",
+		ProvisioningCertificateId: "WMIV1234567890ABCDEX",
+	})
+	require.NoError(t, err)
+
+	provider := &services.LocalContractCertificateProvider{
+		Store:                         engine,
+		CertificateReader:             services.StringSource{Data: pemEncodeChain(moSubCA2Cert, moSubCA1Cert, moRootCert)},
+		PrivateKeyReader:              services.StringSource{Data: pemEncodePrivateKey(t, moSubCA2Key)},
+		ProvisioningCertificateReader: services.StringSource{Data: pemEncodeChain(cpsCert, cpsSubCACert, v2gRootCert)},
+		ProvisioningPrivateKeyReader:  services.StringSource{Data: pemEncodePrivateKey(t, cpsKey)},
+		OEMRootCertificateProvider:    services.X509RootCertificateProviderService{Certificates: []*x509.Certificate{oemRootCert}},
+	}
+
+	response, err := provider.ProvideCertificate(ctx, createCertificateInstallationRequest(t, oemCert, oemKey))
+	require.NoError(t, err)
+	assert.Equal(t, ocpp201.Iso15118EVCertificateStatusEnumTypeAccepted, response.Status)
+
+	exi, err := base64.StdEncoding.DecodeString(response.CertificateInstallationRes)
+	require.NoError(t, err)
+	res, err := iso15118.DecodeCertificateInstallationRes(exi)
+	require.NoError(t, err)
+
+	assert.Equal(t, []byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08}, res.Header.SessionID)
+	assert.Equal(t, iso15118.ResponseCodeOK, res.Body.ResponseCode)
+	assert.Equal(t, "GBTWK012345678V", res.Body.EMAID.Value)
+	err = iso15118.VerifyCertificateInstallationRes(res, cpsKey.Public().(*ecdsa.PublicKey))
+	require.NoError(t, err)
+
+	// the root certificates are not included in the chains
+	assert.Equal(t, cpsCert.Raw, res.Body.SAProvisioningCertificateChain.Certificate)
+	assert.Equal(t, [][]byte{cpsSubCACert.Raw}, res.Body.SAProvisioningCertificateChain.SubCertificates)
+	assert.Equal(t, [][]byte{moSubCA2Cert.Raw, moSubCA1Cert.Raw}, res.Body.ContractSignatureCertChain.SubCertificates)
+
+	contractCert, err := x509.ParseCertificate(res.Body.ContractSignatureCertChain.Certificate)
+	require.NoError(t, err)
+	assert.Equal(t, "GBTWK012345678V", contractCert.Subject.CommonName)
+	assert.Contains(t, contractCert.Subject.Names, pkix.AttributeTypeAndValue{
+		Type:  asn1.ObjectIdentifier{0, 9, 2342, 19200300, 100, 1, 25},
+		Value: "MO",
+	})
+	assert.False(t, contractCert.IsCA)
+	assert.Equal(t, x509.KeyUsageDigitalSignature|x509.KeyUsageContentCommitment, contractCert.KeyUsage)
+	assert.Equal(t, x509.ECDSAWithSHA256, contractCert.SignatureAlgorithm)
+	assert.Equal(t, moSubCA2Cert.SubjectKeyId, contractCert.AuthorityKeyId)
+	assert.False(t, contractCert.NotAfter.After(moSubCA2Cert.NotAfter))
+
+	roots := x509.NewCertPool()
+	roots.AddCert(moRootCert)
+	intermediates := x509.NewCertPool()
+	intermediates.AddCert(moSubCA1Cert)
+	intermediates.AddCert(moSubCA2Cert)
+	_, err = contractCert.Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	require.NoError(t, err)
+
+	// the vehicle can decrypt the private key of the contract certificate
+	oemEcdhKey, err := oemKey.ECDH()
+	require.NoError(t, err)
+	dhPublicKey, err := ecdh.P256().NewPublicKey(res.Body.DHpublickey.Value)
+	require.NoError(t, err)
+	sharedSecret, err := oemEcdhKey.ECDH(dhPublicKey)
+	require.NoError(t, err)
+	kdf := sha256.New()
+	kdf.Write([]byte{0x00, 0x00, 0x00, 0x01})
+	kdf.Write(sharedSecret)
+	kdf.Write([]byte{0x01, 0x55, 0x56})
+	block, err := aes.NewCipher(kdf.Sum(nil)[:16])
+	require.NoError(t, err)
+	encrypted := res.Body.ContractSignatureEncryptedPrivateKey.Value
+	require.Len(t, encrypted, 48)
+	privateKey := make([]byte, 32)
+	cipher.NewCBCDecrypter(block, encrypted[:16]).CryptBlocks(privateKey, encrypted[16:])
+
+	contractPublicKey := contractCert.PublicKey.(*ecdsa.PublicKey)
+	x, y := contractPublicKey.Curve.ScalarBaseMult(privateKey)
+	assert.Equal(t, contractPublicKey.X, x)
+	assert.Equal(t, contractPublicKey.Y, y)
+}
+
+func TestLocalContractCertificateProviderFailureCases(t *testing.T) {
+	engine := inmemory.NewStore(clock.RealClock{})
+	ctx := context.Background()
+
+	oemRootCert, oemRootKey := createRootCACertificate(t, "OEM Root")
+	oemCert, _ := createIntermediateCACertificate(t, "WMIV1234567890ABCDEX", "", oemRootCert, oemRootKey)
+	unknownOemCert, unknownOemKey := createIntermediateCACertificate(t, "WMIV0000000000ABCDEX", "", oemRootCert, oemRootKey)
+	otherOemRootCert, otherOemRootKey := createRootCACertificate(t, "Other OEM Root")
+	untrustedOemCert, untrustedOemKey := createIntermediateCACertificate(t, "WMIV1234567890ABCDEX", "", otherOemRootCert, otherOemRootKey)
+
+	moRootCert, moRootKey := createRootCACertificate(t, "MO Root")
+	moSubCACert, moSubCAKey := createIntermediateCACertificate(t, "MO Sub-CA", "", moRootCert, moRootKey)
+
+	err := engine.SetContract(ctx, &store.Contract{
+		EMAID:                     "GBTWK012345678V",
+		ProvisioningCertificateId: "WMIV1234567890ABCDEX",
+	})
+	require.NoError(t, err)
+
+	provider := &services.LocalContractCertificateProvider{
+		Store:                         engine,
+		CertificateReader:             services.StringSource{Data: pemEncodeChain(moSubCACert, moRootCert)},
+		PrivateKeyReader:              services.StringSource{Data: pemEncodePrivateKey(t, moSubCAKey)},
+		ProvisioningCertificateReader: services.StringSource{Data: pemEncodeChain(moSubCACert, moRootCert)},
+		ProvisioningPrivateKeyReader:  services.StringSource{Data: pemEncodePrivateKey(t, moSubCAKey)},
+		OEMRootCertificateProvider:    services.X509RootCertificateProviderService{Certificates: []*x509.Certificate{oemRootCert}},
+	}
+
+	tests := map[string]string{
+		"not base64":        "not base64!",
+		"not exi":           base64.StdEncoding.EncodeToString([]byte("not exi")),
+		"invalid signature": createCertificateInstallationRequest(t, oemCert, unknownOemKey),
+		"untrusted oem":     createCertificateInstallationRequest(t, untrustedOemCert, untrustedOemKey),
+		"no contract":       createCertificateInstallationRequest(t, unknownOemCert, unknownOemKey),
+	}
+
+	for name, exiRequest := range tests {
+		t.Run(name, func(t *testing.T) {
+			response, err := provider.ProvideCertificate(ctx, exiRequest)
+
+			assert.Error(t, err)
+			assert.Equal(t, ocpp201.Iso15118EVCertificateStatusEnumTypeFailed, response.Status)
+		})
+	}
+}
+
+func TestLocalContractCertificateProviderOEMRootValidation(t *testing.T) {
+	engine := inmemory.NewStore(clock.RealClock{})
+	ctx := context.Background()
+
+	oemRootCert, oemRootKey := createRootCACertificate(t, "OEM Root")
+	oemSubCACert, oemSubCAKey := createIntermediateCACertificate(t, "OEM Sub-CA", "", oemRootCert, oemRootKey)
+	oemCert, oemKey := createIntermediateCACertificate(t, "WMIV1234567890ABCDEX", "", oemSubCACert, oemSubCAKey)
+
+	moRootCert, moRootKey := createRootCACertificate(t, "MO Root")
+	moSubCACert, moSubCAKey := createIntermediateCACertificate(t, "MO Sub-CA", "", moRootCert, moRootKey)
+
+	err := engine.SetContract(ctx, &store.Contract{
+		EMAID:                     "GBTWK012345678V",
+		ProvisioningCertificateId: "WMIV1234567890ABCDEX",
+	})
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		oemRootCertificateProvider services.RootCertificateProviderService
+		skipOEMRootValidation      bool
+		wantStatus                 ocpp201.Iso15118EVCertificateStatusEnumType
+	}{
+		"root and sub-ca": {
+			oemRootCertificateProvider: services.X509RootCertificateProviderService{Certificates: []*x509.Certificate{oemRootCert, oemSubCACert}},
+			wantStatus:                 ocpp201.Iso15118EVCertificateStatusEnumTypeAccepted,
+		},
+		"missing sub-ca": {
+			oemRootCertificateProvider: services.X509RootCertificateProviderService{Certificates: []*x509.Certificate{oemRootCert}},
+			wantStatus:                 ocpp201.Iso15118EVCertificateStatusEnumTypeFailed,
+		},
+		"no oem roots": {
+			wantStatus: ocpp201.Iso15118EVCertificateStatusEnumTypeFailed,
+		},
+		"validation skipped": {
+			skipOEMRootValidation: true,
+			wantStatus:            ocpp201.Iso15118EVCertificateStatusEnumTypeAccepted,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			provider := &services.LocalContractCertificateProvider{
+				Store:                         engine,
+				CertificateReader:             services.StringSource{Data: pemEncodeChain(moSubCACert, moRootCert)},
+				PrivateKeyReader:              services.StringSource{Data: pemEncodePrivateKey(t, moSubCAKey)},
+				ProvisioningCertificateReader: services.StringSource{Data: pemEncodeChain(moSubCACert, moRootCert)},
+				ProvisioningPrivateKeyReader:  services.StringSource{Data: pemEncodePrivateKey(t, moSubCAKey)},
+				OEMRootCertificateProvider:    tc.oemRootCertificateProvider,
+				SkipOEMRootValidation:         tc.skipOEMRootValidation,
+			}
+
+			response, err := provider.ProvideCertificate(ctx, createCertificateInstallationRequest(t, oemCert, oemKey))
+			if tc.wantStatus == ocpp201.Iso15118EVCertificateStatusEnumTypeAccepted {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+			assert.Equal(t, tc.wantStatus, response.Status)
+		})
+	}
+}
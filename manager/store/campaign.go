@@ -0,0 +1,124 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package store
+
+import (
+	"context"
+	"time"
+)
+
+// CertificateCampaignStatus identifies the progress of a certificate campaign
+type CertificateCampaignStatus string
+
+var (
+	// CertificateCampaignInProgress campaigns are still queueing waves of installations
+	CertificateCampaignInProgress CertificateCampaignStatus = "InProgress"
+	// CertificateCampaignHalted campaigns have stopped queueing waves because too many
+	// charge stations failed to install the certificate
+	CertificateCampaignHalted CertificateCampaignStatus = "Halted"
+	// CertificateCampaignCompleted campaigns have a final result for every charge station
+	CertificateCampaignCompleted CertificateCampaignStatus = "Completed"
+	// CertificateCampaignCancelled campaigns were cancelled through the API
+	CertificateCampaignCancelled CertificateCampaignStatus = "Cancelled"
+)
+
+// CertificateCampaignStationStatus identifies the progress of a certificate campaign for a
+// single charge station
+type CertificateCampaignStationStatus string
+
+var (
+	// CertificateCampaignStationWaiting charge stations have not yet been included in a wave
+	CertificateCampaignStationWaiting CertificateCampaignStationStatus = "Waiting"
+	// CertificateCampaignStationPending charge stations have the certificate queued for installation
+	CertificateCampaignStationPending CertificateCampaignStationStatus = "Pending"
+	// CertificateCampaignStationAccepted charge stations have installed the certificate
+	CertificateCampaignStationAccepted CertificateCampaignStationStatus = "Accepted"
+	// CertificateCampaignStationRejected charge stations rejected the certificate: the
+	// installation is retried until the retry policy is exhausted
+	CertificateCampaignStationRejected CertificateCampaignStationStatus = "Rejected"
+	// CertificateCampaignStationFailed charge stations did not install the certificate before
+	// the retry policy was exhausted
+	CertificateCampaignStationFailed CertificateCampaignStationStatus = "Failed"
+)
+
+// CertificateCampaignStation records the progress of a certificate campaign for a single
+// charge station. Wave is the (1-based) wave that the charge station was included in.
+type CertificateCampaignStation struct {
+	ChargeStationId string
+	Status          CertificateCampaignStationStatus
+	Wave            int
+	UpdatedAt       time.Time
+}
+
+// CertificateCampaign installs a root certificate (identified by the same SHA-256 thumbprint
+// as ChargeStationInstallCertificate.CertificateId) on a set of charge stations. The charge
+// stations are those that matched the Tag and OcppVersion (empty fields match all charge
+// stations) when the campaign was created. Installations are queued in waves of WaveSize
+// charge stations every WaveInterval: no more waves are queued once more than MaxFailures
+// charge stations have failed (when MaxFailures is greater than zero). The charge stations
+// are stored separately from the campaign: StationCounts is the number of charge stations with
+// each status and is maintained by the store as the charge stations are updated.
+type CertificateCampaign struct {
+	CampaignId      string
+	CertificateType CertificateType
+	CertificateId   string
+	CertificateData string
+	Tag             string
+	OcppVersion     string
+	WaveSize        int
+	WaveInterval    time.Duration
+	MaxFailures     int
+	Status          CertificateCampaignStatus
+	CreatedAt       time.Time
+	NextWaveAt      time.Time
+	Waves           int
+	StationCounts   map[CertificateCampaignStationStatus]int
+}
+
+// CountStations returns the number of charge stations in the campaign with the provided status
+func (c *CertificateCampaign) CountStations(status CertificateCampaignStationStatus) int {
+	return c.StationCounts[status]
+}
+
+// TotalStations returns the number of charge stations in the campaign
+func (c *CertificateCampaign) TotalStations() int {
+	total := 0
+	for _, count := range c.StationCounts {
+		total += count
+	}
+	return total
+}
+
+// CountCertificateCampaignStations returns the number of charge stations with each status
+func CountCertificateCampaignStations(stations []*CertificateCampaignStation) map[CertificateCampaignStationStatus]int {
+	counts := make(map[CertificateCampaignStationStatus]int)
+	for _, station := range stations {
+		counts[station.Status]++
+	}
+	return counts
+}
+
+type CertificateCampaignStore interface {
+	// CreateCertificateCampaign stores a new campaign with its charge stations. The campaign's
+	// StationCounts are set from the charge stations.
+	CreateCertificateCampaign(ctx context.Context, campaign *CertificateCampaign, stations []*CertificateCampaignStation) error
+	LookupCertificateCampaign(ctx context.Context, campaignId string) (*CertificateCampaign, error)
+	// ListCertificateCampaigns returns the campaigns with the provided status (or all campaigns
+	// if the status is empty) in the order that they were created
+	ListCertificateCampaigns(ctx context.Context, status CertificateCampaignStatus, offset, limit int) ([]*CertificateCampaign, error)
+	// UpdateCertificateCampaign atomically reads the campaign, applies the update and, if the
+	// update reports a change, writes it back. The update may be called more than once and must
+	// not change the StationCounts. The resulting campaign is returned, or nil if there is no
+	// campaign with the id.
+	UpdateCertificateCampaign(ctx context.Context, campaignId string, update func(campaign *CertificateCampaign) bool) (*CertificateCampaign, error)
+	// ListCertificateCampaignStations returns the charge stations in the campaign with the
+	// provided status (or all charge stations if the status is empty) ordered by charge
+	// station id
+	ListCertificateCampaignStations(ctx context.Context, campaignId string, status CertificateCampaignStationStatus, offset, limit int) ([]*CertificateCampaignStation, error)
+	// UpdateCertificateCampaignStation atomically reads the campaign and one of its charge
+	// stations, applies the update to the charge station and, if the update reports a change,
+	// writes it back along with the campaign's StationCounts. The update may be called more than
+	// once and must not change the campaign. The resulting charge station is returned, or nil if
+	// the campaign does not exist or does not include the charge station.
+	UpdateCertificateCampaignStation(ctx context.Context, campaignId, chargeStationId string, update func(campaign *CertificateCampaign, station *CertificateCampaignStation) bool) (*CertificateCampaignStation, error)
+}
@@ -2,10 +2,71 @@
 
 package store
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 type CertificateStore interface {
 	SetCertificate(ctx context.Context, pemCertificate string) error
 	LookupCertificate(ctx context.Context, certificateHash string) (string, error)
 	DeleteCertificate(ctx context.Context, certificateHash string) error
 }
+
+// CertificateRevocation records that a charge station client certificate, identified
+// by the base64 URL encoded SHA-256 hash of its DER encoding, must no longer be
+// accepted by the gateway
+type CertificateRevocation struct {
+	CertificateHash string
+	ChargeStationId string
+	Reason          string
+	RevokedAt       time.Time
+}
+
+type CertificateRevocationStore interface {
+	SetCertificateRevocation(ctx context.Context, revocation *CertificateRevocation) error
+	LookupCertificateRevocation(ctx context.Context, certificateHash string) (*CertificateRevocation, error)
+	DeleteCertificateRevocation(ctx context.Context, certificateHash string) error
+}
+
+// IssuedCertificate records a certificate that has been issued to a charge station (identified
+// by the same SHA-256 thumbprint as ChargeStationInstallCertificate.CertificateId) so that it can
+// be renewed before it expires. A certificate is superseded once the charge station has accepted
+// a newer certificate of the same type, or if the charge station rejects it. RenewalRequestedAt is the last time that the charge
+// station was asked to renew the certificate.
+type IssuedCertificate struct {
+	CertificateId      string
+	ChargeStationId    string
+	CertificateType    CertificateType
+	SerialNumber       string
+	IssuedAt           time.Time
+	NotAfter           time.Time
+	Superseded         bool
+	RenewalRequestedAt time.Time
+}
+
+type IssuedCertificateStore interface {
+	SetIssuedCertificate(ctx context.Context, certificate *IssuedCertificate) error
+	LookupIssuedCertificate(ctx context.Context, certificateId string) (*IssuedCertificate, error)
+	ListIssuedCertificatesForChargeStation(ctx context.Context, chargeStationId string) ([]*IssuedCertificate, error)
+	// ListExpiringIssuedCertificates returns the certificates that have not been superseded and
+	// expire before the provided time, ordered by expiry
+	ListExpiringIssuedCertificates(ctx context.Context, expiresBefore time.Time, offset, limit int) ([]*IssuedCertificate, error)
+}
+
+// RootCertificate is a root certificate that charge stations are expected to have installed
+// or, once it has been revoked, to have removed. The CertificateId is the same SHA-256
+// thumbprint as ChargeStationInstallCertificate.CertificateId.
+type RootCertificate struct {
+	CertificateId   string
+	CertificateType CertificateType
+	CertificateData string
+	Revoked         bool
+}
+
+type RootCertificateStore interface {
+	SetRootCertificate(ctx context.Context, certificate *RootCertificate) error
+	LookupRootCertificate(ctx context.Context, certificateId string) (*RootCertificate, error)
+	ListRootCertificates(ctx context.Context) ([]*RootCertificate, error)
+	DeleteRootCertificate(ctx context.Context, certificateId string) error
+}
@@ -0,0 +1,20 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package store
+
+import "context"
+
+// Contract associates an e-mobility account (identified by its eMAID) with the vehicle that is
+// provisioned to use it for Plug & Charge: the vehicle is identified by the provisioning
+// certificate id (PCID), which is the common name of its OEM provisioning certificate.
+type Contract struct {
+	EMAID                     string
+	ProvisioningCertificateId string
+}
+
+type ContractStore interface {
+	SetContract(ctx context.Context, contract *Contract) error
+	LookupContract(ctx context.Context, emaid string) (*Contract, error)
+	LookupContractByProvisioningCertificateId(ctx context.Context, provisioningCertificateId string) (*Contract, error)
+	DeleteContract(ctx context.Context, emaid string) error
+}
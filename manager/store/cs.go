@@ -4,6 +4,8 @@ package store
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"time"
 )
 
@@ -26,6 +28,43 @@ type ChargeStationAuthStore interface {
 	LookupChargeStationAuth(ctx context.Context, chargeStationId string) (*ChargeStationAuth, error)
 }
 
+// ChargeStationDetails is the inventory record for a charge station. It is created when the
+// charge station is registered and is updated with the details the charge station reports
+// in its boot notification. Tags are assigned by operators to group charge stations.
+type ChargeStationDetails struct {
+	ChargeStationId string
+	Vendor          string
+	Model           string
+	SerialNumber    string
+	FirmwareVersion string
+	Tags            []string
+	RegisteredAt    time.Time
+	LastSeen        time.Time
+}
+
+// ChargeStationFilter restricts the charge stations that are listed: empty fields match
+// all charge stations
+type ChargeStationFilter struct {
+	Vendor          string
+	Model           string
+	FirmwareVersion string
+	Tag             string
+}
+
+type ChargeStationDetailsStore interface {
+	SetChargeStationDetails(ctx context.Context, chargeStationId string, details *ChargeStationDetails) error
+	LookupChargeStationDetails(ctx context.Context, chargeStationId string) (*ChargeStationDetails, error)
+	ListChargeStationDetails(ctx context.Context, filter ChargeStationFilter, offset, limit int) ([]*ChargeStationDetails, error)
+	// DeleteChargeStation removes the charge station and all of its auth, settings, certificates,
+	// trigger messages, runtime details, connection details, operation log, auth lockout, issued
+	// certificates and reservations
+	DeleteChargeStation(ctx context.Context, chargeStationId string) error
+	// BackfillChargeStationDetails creates an empty details record for each registered charge
+	// station that does not have one, e.g. charge stations that were registered before details
+	// were recorded, and returns the number of records that were created
+	BackfillChargeStationDetails(ctx context.Context) (int, error)
+}
+
 type ChargeStationSettingStatus string
 
 var (
@@ -34,12 +73,18 @@ var (
 	ChargeStationSettingStatusRejected       ChargeStationSettingStatus = "Rejected"
 	ChargeStationSettingStatusRebootRequired ChargeStationSettingStatus = "RebootRequired"
 	ChargeStationSettingStatusNotSupported   ChargeStationSettingStatus = "NotSupported"
+	ChargeStationSettingStatusFailed         ChargeStationSettingStatus = "Failed"
 )
 
+// ChargeStationSetting is a setting that is to be applied to a charge station. While
+// the setting is Pending it is sent to the charge station once SendAfter has passed:
+// Attempts counts the number of times it has been sent. If the charge station does not
+// respond before the retry policy gives up then its status is set to Failed.
 type ChargeStationSetting struct {
 	Value     string
 	Status    ChargeStationSettingStatus
 	SendAfter time.Time
+	Attempts  int
 }
 
 type ChargeStationSettings struct {
@@ -63,6 +108,26 @@ type ChargeStationRuntimeDetailsStore interface {
 	LookupChargeStationRuntimeDetails(ctx context.Context, chargeStationId string) (*ChargeStationRuntimeDetails, error)
 }
 
+// ChargeStationConnection records the most recent connection of a charge station
+// to a gateway, as reported by the gateway's presence events
+type ChargeStationConnection struct {
+	ChargeStationId string
+	Connected       bool
+	GatewayId       string
+	ConnectionId    string
+	OcppVersion     string
+	RemoteAddr      string
+	SecurityProfile SecurityProfile
+	ConnectedAt     time.Time
+	DisconnectedAt  time.Time
+}
+
+type ChargeStationConnectionStore interface {
+	SetChargeStationConnection(ctx context.Context, chargeStationId string, connection *ChargeStationConnection) error
+	LookupChargeStationConnection(ctx context.Context, chargeStationId string) (*ChargeStationConnection, error)
+	ListConnectedChargeStations(ctx context.Context, offset, limit int) ([]*ChargeStationConnection, error)
+}
+
 type CertificateType string
 
 var (
@@ -80,6 +145,7 @@ var (
 	CertificateInstallationPending  CertificateInstallationStatus = "Pending"
 	CertificateInstallationAccepted CertificateInstallationStatus = "Accepted"
 	CertificateInstallationRejected CertificateInstallationStatus = "Rejected"
+	CertificateInstallationFailed   CertificateInstallationStatus = "Failed"
 )
 
 type ChargeStationInstallCertificate struct {
@@ -88,6 +154,7 @@ type ChargeStationInstallCertificate struct {
 	CertificateData               string
 	CertificateInstallationStatus CertificateInstallationStatus
 	SendAfter                     time.Time
+	Attempts                      int
 }
 
 type ChargeStationInstallCertificates struct {
@@ -101,6 +168,57 @@ type ChargeStationInstallCertificatesStore interface {
 	ListChargeStationInstallCertificates(ctx context.Context, pageSize int, previousChargeStationId string) ([]*ChargeStationInstallCertificates, error)
 }
 
+type CertificateDeletionStatus string
+
+var (
+	CertificateDeletionPending CertificateDeletionStatus = "Pending"
+	CertificateDeletionFailed  CertificateDeletionStatus = "Failed"
+)
+
+// ChargeStationInstalledCertificate is a certificate that a charge station has reported as
+// installed, identified by its OCPP certificate hash data. A certificate that is to be removed
+// from the charge station has a DeletionStatus until the charge station confirms the deletion.
+type ChargeStationInstalledCertificate struct {
+	CertificateType CertificateType
+	HashAlgorithm   string
+	IssuerNameHash  string
+	IssuerKeyHash   string
+	SerialNumber    string
+	DeletionStatus  CertificateDeletionStatus
+	SendAfter       time.Time
+	Attempts        int
+}
+
+// SameCertificate returns true if both installed certificates have the same hash data: hashes
+// are compared ignoring case and serial numbers also ignoring leading zeros.
+func (c *ChargeStationInstalledCertificate) SameCertificate(other *ChargeStationInstalledCertificate) bool {
+	return strings.EqualFold(c.HashAlgorithm, other.HashAlgorithm) &&
+		strings.EqualFold(c.IssuerNameHash, other.IssuerNameHash) &&
+		strings.EqualFold(c.IssuerKeyHash, other.IssuerKeyHash) &&
+		strings.EqualFold(strings.TrimLeft(c.SerialNumber, "0"), strings.TrimLeft(other.SerialNumber, "0"))
+}
+
+// ChargeStationInstalledCertificates is the inventory of certificates that a charge station
+// last reported as installed. RequestedAt is the last time that the charge station was asked
+// to report its installed certificates and ReportedAt is the last time that it did.
+type ChargeStationInstalledCertificates struct {
+	ChargeStationId string
+	Certificates    []*ChargeStationInstalledCertificate
+	RequestedAt     time.Time
+	ReportedAt      time.Time
+}
+
+type ChargeStationInstalledCertificatesStore interface {
+	SetChargeStationInstalledCertificates(ctx context.Context, chargeStationId string, certificates *ChargeStationInstalledCertificates) error
+	LookupChargeStationInstalledCertificates(ctx context.Context, chargeStationId string) (*ChargeStationInstalledCertificates, error)
+	// UpdateChargeStationInstalledCertificates atomically reads the installed certificates for the
+	// charge station (or an empty inventory if there isn't one), applies the update and, if the
+	// update reports a change, writes them back. The update may be called more than once. The
+	// resulting installed certificates are returned.
+	UpdateChargeStationInstalledCertificates(ctx context.Context, chargeStationId string, update func(installed *ChargeStationInstalledCertificates) bool) (*ChargeStationInstalledCertificates, error)
+	ListChargeStationInstalledCertificates(ctx context.Context, pageSize int, previousChargeStationId string) ([]*ChargeStationInstalledCertificates, error)
+}
+
 type TriggerStatus string
 
 var (
@@ -108,6 +226,7 @@ var (
 	TriggerStatusAccepted       TriggerStatus = "Accepted"
 	TriggerStatusRejected       TriggerStatus = "Rejected"
 	TriggerStatusNotImplemented TriggerStatus = "NotImplemented"
+	TriggerStatusFailed         TriggerStatus = "Failed"
 )
 
 type TriggerMessage string
@@ -125,16 +244,60 @@ var (
 	TriggerMessagePublishFirmwareStatusNotification TriggerMessage = "PublishFirmwareStatusNotification"
 )
 
+// ChargeStationTriggerMessage is a request for the charge station to send a message. The
+// EvseId and ConnectorId optionally target the request at a specific EVSE or connector:
+// they are only used for OCPP 2.0.1 charge stations.
 type ChargeStationTriggerMessage struct {
 	ChargeStationId string
 	TriggerMessage  TriggerMessage
+	EvseId          *int
+	ConnectorId     *int
 	TriggerStatus   TriggerStatus
 	SendAfter       time.Time
+	Attempts        int
+}
+
+// SameTarget returns true if both trigger messages request the same message from the same
+// EVSE and connector: only one such trigger message can be queued for a charge station.
+func (t *ChargeStationTriggerMessage) SameTarget(other *ChargeStationTriggerMessage) bool {
+	return t.TriggerMessage == other.TriggerMessage &&
+		equalIntPtr(t.EvseId, other.EvseId) &&
+		equalIntPtr(t.ConnectorId, other.ConnectorId)
+}
+
+// Name identifies the trigger message within the queue: it is the message followed by the
+// EVSE and connector that it targets (if any), separated by ';', e.g. "StatusNotification;1;2"
+func (t *ChargeStationTriggerMessage) Name() string {
+	name := string(t.TriggerMessage)
+	if t.EvseId != nil {
+		name = fmt.Sprintf("%s;%d", name, *t.EvseId)
+		if t.ConnectorId != nil {
+			name = fmt.Sprintf("%s;%d", name, *t.ConnectorId)
+		}
+	}
+	return name
+}
+
+func equalIntPtr(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// ChargeStationTriggerMessages is the queue of trigger messages for a charge station: they
+// are sent to the charge station in order.
+type ChargeStationTriggerMessages struct {
+	ChargeStationId string
+	TriggerMessages []*ChargeStationTriggerMessage
 }
 
 type ChargeStationTriggerMessageStore interface {
-	SetChargeStationTriggerMessage(ctx context.Context, chargeStationId string, triggerMessage *ChargeStationTriggerMessage) error
-	DeleteChargeStationTriggerMessage(ctx context.Context, chargeStationId string) error
-	LookupChargeStationTriggerMessage(ctx context.Context, chargeStationId string) (*ChargeStationTriggerMessage, error)
-	ListChargeStationTriggerMessages(ctx context.Context, pageSize int, previousChargeStationId string) ([]*ChargeStationTriggerMessage, error)
+	// UpdateChargeStationTriggerMessages updates the trigger messages that are already queued
+	// for the charge station with the same target and adds the others to the end of the queue
+	UpdateChargeStationTriggerMessages(ctx context.Context, chargeStationId string, triggerMessages *ChargeStationTriggerMessages) error
+	// DeleteChargeStationTriggerMessage removes the trigger message with the same target from the queue
+	DeleteChargeStationTriggerMessage(ctx context.Context, chargeStationId string, triggerMessage *ChargeStationTriggerMessage) error
+	LookupChargeStationTriggerMessages(ctx context.Context, chargeStationId string) (*ChargeStationTriggerMessages, error)
+	ListChargeStationTriggerMessages(ctx context.Context, pageSize int, previousChargeStationId string) ([]*ChargeStationTriggerMessages, error)
 }
@@ -4,13 +4,25 @@ package store
 
 type Engine interface {
 	ChargeStationAuthStore
+	ChargeStationDetailsStore
 	ChargeStationSettingsStore
 	ChargeStationRuntimeDetailsStore
+	ChargeStationConnectionStore
 	ChargeStationInstallCertificatesStore
+	ChargeStationInstalledCertificatesStore
 	ChargeStationTriggerMessageStore
+	ChargeStationOperationStore
 	TokenStore
 	TransactionStore
 	CertificateStore
+	CertificateRevocationStore
+	IssuedCertificateStore
+	RootCertificateStore
+	CertificateCampaignStore
+	ContractStore
 	OcpiStore
 	LocationStore
+	ReservationStore
+	AuthLockoutStore
+	LeaseStore
 }
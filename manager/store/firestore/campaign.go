@@ -0,0 +1,284 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package firestore
+
+import (
+	"cloud.google.com/go/firestore"
+	"context"
+	"fmt"
+	"github.com/thoughtworks/maeve-csms/manager/store"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"time"
+)
+
+// certificateCampaignStation is stored in the Station sub-collection of the campaign with the
+// charge station id as the document id: the number of charge stations in a campaign is
+// unbounded, so they cannot be embedded in the campaign document
+type certificateCampaignStation struct {
+	Status    string    `firestore:"s"`
+	Wave      int       `firestore:"w"`
+	UpdatedAt time.Time `firestore:"u"`
+}
+
+type certificateCampaign struct {
+	CertificateType  string         `firestore:"type"`
+	CertificateId    string         `firestore:"certificateId"`
+	PemCertificate   string         `firestore:"pem"`
+	Tag              string         `firestore:"tag"`
+	OcppVersion      string         `firestore:"ocppVersion"`
+	WaveSize         int            `firestore:"waveSize"`
+	WaveIntervalSecs int64          `firestore:"waveIntervalSecs"`
+	MaxFailures      int            `firestore:"maxFailures"`
+	Status           string         `firestore:"status"`
+	CreatedAt        time.Time      `firestore:"createdAt"`
+	NextWaveAt       time.Time      `firestore:"nextWaveAt"`
+	Waves            int            `firestore:"waves"`
+	StationCounts    map[string]int `firestore:"stationCounts"`
+}
+
+func certificateCampaignRef(s *Store, campaignId string) *firestore.DocumentRef {
+	return s.client.Doc(fmt.Sprintf("CertificateCampaign/%s", campaignId))
+}
+
+func certificateCampaignStationsRef(s *Store, campaignId string) *firestore.CollectionRef {
+	return certificateCampaignRef(s, campaignId).Collection("Station")
+}
+
+func toFirestoreCertificateCampaign(campaign *store.CertificateCampaign) *certificateCampaign {
+	var counts = make(map[string]int, len(campaign.StationCounts))
+	for stationStatus, count := range campaign.StationCounts {
+		if count > 0 {
+			counts[string(stationStatus)] = count
+		}
+	}
+	return &certificateCampaign{
+		CertificateType:  string(campaign.CertificateType),
+		CertificateId:    campaign.CertificateId,
+		PemCertificate:   campaign.CertificateData,
+		Tag:              campaign.Tag,
+		OcppVersion:      campaign.OcppVersion,
+		WaveSize:         campaign.WaveSize,
+		WaveIntervalSecs: int64(campaign.WaveInterval / time.Second),
+		MaxFailures:      campaign.MaxFailures,
+		Status:           string(campaign.Status),
+		CreatedAt:        campaign.CreatedAt,
+		NextWaveAt:       campaign.NextWaveAt,
+		Waves:            campaign.Waves,
+		StationCounts:    counts,
+	}
+}
+
+func toFirestoreCertificateCampaignStation(station *store.CertificateCampaignStation) *certificateCampaignStation {
+	return &certificateCampaignStation{
+		Status:    string(station.Status),
+		Wave:      station.Wave,
+		UpdatedAt: station.UpdatedAt,
+	}
+}
+
+func (s *Store) CreateCertificateCampaign(ctx context.Context, campaign *store.CertificateCampaign, stations []*store.CertificateCampaignStation) error {
+	campaign.StationCounts = store.CountCertificateCampaignStations(stations)
+
+	// the charge stations are written in pages before the campaign so that the campaign is
+	// not progressed until all of its charge stations have been stored
+	for start := 0; start < len(stations); start += deletePageSize {
+		end := start + deletePageSize
+		if end > len(stations) {
+			end = len(stations)
+		}
+		bw := s.client.BulkWriter(ctx)
+		var jobs = make([]*firestore.BulkWriterJob, 0, end-start)
+		for _, station := range stations[start:end] {
+			job, err := bw.Set(certificateCampaignStationsRef(s, campaign.CampaignId).Doc(station.ChargeStationId),
+				toFirestoreCertificateCampaignStation(station))
+			if err != nil {
+				bw.End()
+				return fmt.Errorf("create certificate campaign %s: %w", campaign.CampaignId, err)
+			}
+			jobs = append(jobs, job)
+		}
+		bw.End()
+		for _, job := range jobs {
+			if _, err := job.Results(); err != nil {
+				return fmt.Errorf("create certificate campaign %s: %w", campaign.CampaignId, err)
+			}
+		}
+	}
+
+	_, err := certificateCampaignRef(s, campaign.CampaignId).Create(ctx, toFirestoreCertificateCampaign(campaign))
+	if err != nil {
+		return fmt.Errorf("create certificate campaign %s: %w", campaign.CampaignId, err)
+	}
+	return nil
+}
+
+func (s *Store) LookupCertificateCampaign(ctx context.Context, campaignId string) (*store.CertificateCampaign, error) {
+	snap, err := certificateCampaignRef(s, campaignId).Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("lookup certificate campaign %s: %w", campaignId, err)
+	}
+	var data certificateCampaign
+	if err = snap.DataTo(&data); err != nil {
+		return nil, fmt.Errorf("map certificate campaign %s: %w", campaignId, err)
+	}
+	return mapCertificateCampaign(campaignId, &data), nil
+}
+
+// ListCertificateCampaigns needs the CertificateCampaign (status, createdAt) index defined in
+// firestore.indexes.json when filtering by status
+func (s *Store) ListCertificateCampaigns(ctx context.Context, campaignStatus store.CertificateCampaignStatus, offset, limit int) ([]*store.CertificateCampaign, error) {
+	query := s.client.Collection("CertificateCampaign").Query
+	if campaignStatus != "" {
+		query = query.Where("status", "==", string(campaignStatus))
+	}
+	snaps, err := query.OrderBy("createdAt", firestore.Asc).OrderBy(firestore.DocumentID, firestore.Asc).
+		Offset(offset).Limit(limit).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("list certificate campaigns: %w", err)
+	}
+	var campaigns = make([]*store.CertificateCampaign, 0, len(snaps))
+	for _, snap := range snaps {
+		var data certificateCampaign
+		if err = snap.DataTo(&data); err != nil {
+			return nil, fmt.Errorf("map certificate campaign %s: %w", snap.Ref.ID, err)
+		}
+		campaigns = append(campaigns, mapCertificateCampaign(snap.Ref.ID, &data))
+	}
+	return campaigns, nil
+}
+
+func (s *Store) UpdateCertificateCampaign(ctx context.Context, campaignId string, update func(campaign *store.CertificateCampaign) bool) (*store.CertificateCampaign, error) {
+	ref := certificateCampaignRef(s, campaignId)
+	var campaign *store.CertificateCampaign
+	err := s.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		campaign = nil
+		snap, err := tx.Get(ref)
+		if err != nil {
+			if status.Code(err) == codes.NotFound {
+				return nil
+			}
+			return err
+		}
+		var data certificateCampaign
+		if err = snap.DataTo(&data); err != nil {
+			return err
+		}
+		campaign = mapCertificateCampaign(campaignId, &data)
+		if !update(campaign) {
+			return nil
+		}
+		// the station counts are only changed along with the charge stations
+		campaign.StationCounts = mapCertificateCampaign(campaignId, &data).StationCounts
+		return tx.Set(ref, toFirestoreCertificateCampaign(campaign))
+	})
+	if err != nil {
+		return nil, fmt.Errorf("update certificate campaign %s: %w", campaignId, err)
+	}
+	return campaign, nil
+}
+
+func (s *Store) ListCertificateCampaignStations(ctx context.Context, campaignId string, stationStatus store.CertificateCampaignStationStatus, offset, limit int) ([]*store.CertificateCampaignStation, error) {
+	query := certificateCampaignStationsRef(s, campaignId).Query
+	if stationStatus != "" {
+		query = query.Where("s", "==", string(stationStatus))
+	}
+	// an equality filter ordered by document id is served by the single-field indexes
+	snaps, err := query.OrderBy(firestore.DocumentID, firestore.Asc).Offset(offset).Limit(limit).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("list certificate campaign stations %s: %w", campaignId, err)
+	}
+	var stations = make([]*store.CertificateCampaignStation, 0, len(snaps))
+	for _, snap := range snaps {
+		var data certificateCampaignStation
+		if err = snap.DataTo(&data); err != nil {
+			return nil, fmt.Errorf("map certificate campaign station %s %s: %w", campaignId, snap.Ref.ID, err)
+		}
+		stations = append(stations, mapCertificateCampaignStation(snap.Ref.ID, &data))
+	}
+	return stations, nil
+}
+
+func (s *Store) UpdateCertificateCampaignStation(ctx context.Context, campaignId, chargeStationId string, update func(campaign *store.CertificateCampaign, station *store.CertificateCampaignStation) bool) (*store.CertificateCampaignStation, error) {
+	campaignRef := certificateCampaignRef(s, campaignId)
+	stationRef := certificateCampaignStationsRef(s, campaignId).Doc(chargeStationId)
+	var station *store.CertificateCampaignStation
+	err := s.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		station = nil
+		campaignSnap, err := tx.Get(campaignRef)
+		if err != nil {
+			if status.Code(err) == codes.NotFound {
+				return nil
+			}
+			return err
+		}
+		stationSnap, err := tx.Get(stationRef)
+		if err != nil {
+			if status.Code(err) == codes.NotFound {
+				return nil
+			}
+			return err
+		}
+		var campaignData certificateCampaign
+		if err = campaignSnap.DataTo(&campaignData); err != nil {
+			return err
+		}
+		var stationData certificateCampaignStation
+		if err = stationSnap.DataTo(&stationData); err != nil {
+			return err
+		}
+
+		campaign := mapCertificateCampaign(campaignId, &campaignData)
+		station = mapCertificateCampaignStation(chargeStationId, &stationData)
+		previousStatus := station.Status
+		if !update(mapCertificateCampaign(campaignId, &campaignData), station) {
+			return nil
+		}
+		campaign.StationCounts[previousStatus]--
+		campaign.StationCounts[station.Status]++
+		err = tx.Set(stationRef, toFirestoreCertificateCampaignStation(station))
+		if err != nil {
+			return err
+		}
+		return tx.Set(campaignRef, toFirestoreCertificateCampaign(campaign))
+	})
+	if err != nil {
+		return nil, fmt.Errorf("update certificate campaign station %s %s: %w", campaignId, chargeStationId, err)
+	}
+	return station, nil
+}
+
+func mapCertificateCampaignStation(chargeStationId string, data *certificateCampaignStation) *store.CertificateCampaignStation {
+	return &store.CertificateCampaignStation{
+		ChargeStationId: chargeStationId,
+		Status:          store.CertificateCampaignStationStatus(data.Status),
+		Wave:            data.Wave,
+		UpdatedAt:       data.UpdatedAt,
+	}
+}
+
+func mapCertificateCampaign(campaignId string, data *certificateCampaign) *store.CertificateCampaign {
+	var counts = make(map[store.CertificateCampaignStationStatus]int, len(data.StationCounts))
+	for stationStatus, count := range data.StationCounts {
+		counts[store.CertificateCampaignStationStatus(stationStatus)] = count
+	}
+	return &store.CertificateCampaign{
+		CampaignId:      campaignId,
+		CertificateType: store.CertificateType(data.CertificateType),
+		CertificateId:   data.CertificateId,
+		CertificateData: data.PemCertificate,
+		Tag:             data.Tag,
+		OcppVersion:     data.OcppVersion,
+		WaveSize:        data.WaveSize,
+		WaveInterval:    time.Duration(data.WaveIntervalSecs) * time.Second,
+		MaxFailures:     data.MaxFailures,
+		Status:          store.CertificateCampaignStatus(data.Status),
+		CreatedAt:       data.CreatedAt,
+		NextWaveAt:      data.NextWaveAt,
+		Waves:           data.Waves,
+		StationCounts:   counts,
+	}
+}
@@ -0,0 +1,228 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build integration
+
+package firestore_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thoughtworks/maeve-csms/manager/store"
+	"github.com/thoughtworks/maeve-csms/manager/store/firestore"
+	"k8s.io/utils/clock"
+	"testing"
+	"time"
+)
+
+func TestCreateLookupAndListCertificateCampaigns(t *testing.T) {
+	defer cleanupAllCollections(t, "myproject")
+
+	ctx := context.Background()
+
+	engine, err := firestore.NewStore(ctx, "myproject", clock.RealClock{})
+	require.NoError(t, err)
+
+	createdAt := time.Date(2023, 6, 15, 15, 0, 0, 0, time.UTC)
+	updatedAt := createdAt.Add(time.Hour)
+	for i, campaignId := range []string{"campaign002", "campaign001", "campaign003"} {
+		status := store.CertificateCampaignInProgress
+		if i == 2 {
+			status = store.CertificateCampaignCompleted
+		}
+		err = engine.CreateCertificateCampaign(ctx, &store.CertificateCampaign{
+			CampaignId:      campaignId,
+			CertificateType: store.CertificateTypeV2G,
+			CertificateId:   "v2g001",
+			CertificateData: "pem-data",
+			Tag:             "fleet",
+			OcppVersion:     "2.0.1",
+			WaveSize:        10,
+			WaveInterval:    time.Hour,
+			MaxFailures:     2,
+			Status:          status,
+			CreatedAt:       createdAt.Add(time.Duration(i) * time.Minute),
+			NextWaveAt:      createdAt.Add(time.Hour),
+			Waves:           1,
+		}, []*store.CertificateCampaignStation{
+			{ChargeStationId: "cs002", Status: store.CertificateCampaignStationWaiting},
+			{ChargeStationId: "cs001", Status: store.CertificateCampaignStationPending, Wave: 1, UpdatedAt: updatedAt},
+		})
+		require.NoError(t, err)
+	}
+
+	got, err := engine.LookupCertificateCampaign(ctx, "campaign001")
+	require.NoError(t, err)
+	assert.Equal(t, &store.CertificateCampaign{
+		CampaignId:      "campaign001",
+		CertificateType: store.CertificateTypeV2G,
+		CertificateId:   "v2g001",
+		CertificateData: "pem-data",
+		Tag:             "fleet",
+		OcppVersion:     "2.0.1",
+		WaveSize:        10,
+		WaveInterval:    time.Hour,
+		MaxFailures:     2,
+		Status:          store.CertificateCampaignInProgress,
+		CreatedAt:       createdAt.Add(time.Minute),
+		NextWaveAt:      createdAt.Add(time.Hour),
+		Waves:           1,
+		StationCounts: map[store.CertificateCampaignStationStatus]int{
+			store.CertificateCampaignStationWaiting: 1,
+			store.CertificateCampaignStationPending: 1,
+		},
+	}, got)
+	assert.Equal(t, 2, got.TotalStations())
+
+	got, err = engine.LookupCertificateCampaign(ctx, "unknown")
+	require.NoError(t, err)
+	assert.Nil(t, got)
+
+	list, err := engine.ListCertificateCampaigns(ctx, store.CertificateCampaignInProgress, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, "campaign002", list[0].CampaignId)
+	assert.Equal(t, "campaign001", list[1].CampaignId)
+
+	list, err = engine.ListCertificateCampaigns(ctx, "", 1, 10)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, "campaign001", list[0].CampaignId)
+	assert.Equal(t, "campaign003", list[1].CampaignId)
+
+	stations, err := engine.ListCertificateCampaignStations(ctx, "campaign001", "", 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []*store.CertificateCampaignStation{
+		{ChargeStationId: "cs001", Status: store.CertificateCampaignStationPending, Wave: 1, UpdatedAt: updatedAt},
+		{ChargeStationId: "cs002", Status: store.CertificateCampaignStationWaiting},
+	}, stations)
+
+	stations, err = engine.ListCertificateCampaignStations(ctx, "campaign001", store.CertificateCampaignStationWaiting, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, stations, 1)
+	assert.Equal(t, "cs002", stations[0].ChargeStationId)
+
+	stations, err = engine.ListCertificateCampaignStations(ctx, "campaign001", "", 1, 10)
+	require.NoError(t, err)
+	require.Len(t, stations, 1)
+	assert.Equal(t, "cs002", stations[0].ChargeStationId)
+}
+
+func TestUpdateCertificateCampaign(t *testing.T) {
+	defer cleanupAllCollections(t, "myproject")
+
+	ctx := context.Background()
+
+	engine, err := firestore.NewStore(ctx, "myproject", clock.RealClock{})
+	require.NoError(t, err)
+	now := time.Date(2023, 6, 15, 15, 0, 0, 0, time.UTC)
+
+	err = engine.CreateCertificateCampaign(ctx, &store.CertificateCampaign{
+		CampaignId:    "campaign001",
+		CertificateId: "v2g001",
+		Status:        store.CertificateCampaignInProgress,
+		CreatedAt:     now,
+	}, []*store.CertificateCampaignStation{
+		{ChargeStationId: "cs001", Status: store.CertificateCampaignStationWaiting},
+	})
+	require.NoError(t, err)
+
+	got, err := engine.UpdateCertificateCampaign(ctx, "campaign001", func(campaign *store.CertificateCampaign) bool {
+		campaign.Status = store.CertificateCampaignCancelled
+		return true
+	})
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, store.CertificateCampaignCancelled, got.Status)
+
+	// an update that reports no change is not written
+	got, err = engine.UpdateCertificateCampaign(ctx, "campaign001", func(campaign *store.CertificateCampaign) bool {
+		campaign.Status = store.CertificateCampaignCompleted
+		return false
+	})
+	require.NoError(t, err)
+	require.NotNil(t, got)
+
+	got, err = engine.LookupCertificateCampaign(ctx, "campaign001")
+	require.NoError(t, err)
+	assert.Equal(t, store.CertificateCampaignCancelled, got.Status)
+	assert.Equal(t, 1, got.CountStations(store.CertificateCampaignStationWaiting))
+
+	got, err = engine.UpdateCertificateCampaign(ctx, "unknown", func(campaign *store.CertificateCampaign) bool {
+		return true
+	})
+	require.NoError(t, err)
+	assert.Nil(t, got)
+}
+
+func TestUpdateCertificateCampaignStation(t *testing.T) {
+	defer cleanupAllCollections(t, "myproject")
+
+	ctx := context.Background()
+
+	engine, err := firestore.NewStore(ctx, "myproject", clock.RealClock{})
+	require.NoError(t, err)
+	now := time.Date(2023, 6, 15, 15, 0, 0, 0, time.UTC)
+
+	err = engine.CreateCertificateCampaign(ctx, &store.CertificateCampaign{
+		CampaignId:    "campaign001",
+		CertificateId: "v2g001",
+		Status:        store.CertificateCampaignInProgress,
+		CreatedAt:     now,
+	}, []*store.CertificateCampaignStation{
+		{ChargeStationId: "cs001", Status: store.CertificateCampaignStationWaiting},
+		{ChargeStationId: "cs002", Status: store.CertificateCampaignStationWaiting},
+	})
+	require.NoError(t, err)
+
+	got, err := engine.UpdateCertificateCampaignStation(ctx, "campaign001", "cs001",
+		func(campaign *store.CertificateCampaign, station *store.CertificateCampaignStation) bool {
+			assert.Equal(t, store.CertificateCampaignInProgress, campaign.Status)
+			station.Status = store.CertificateCampaignStationPending
+			station.Wave = 1
+			station.UpdatedAt = now
+			return true
+		})
+	require.NoError(t, err)
+	assert.Equal(t, &store.CertificateCampaignStation{
+		ChargeStationId: "cs001",
+		Status:          store.CertificateCampaignStationPending,
+		Wave:            1,
+		UpdatedAt:       now,
+	}, got)
+
+	// the campaign's counts are updated with the charge station
+	campaign, err := engine.LookupCertificateCampaign(ctx, "campaign001")
+	require.NoError(t, err)
+	assert.Equal(t, map[store.CertificateCampaignStationStatus]int{
+		store.CertificateCampaignStationWaiting: 1,
+		store.CertificateCampaignStationPending: 1,
+	}, campaign.StationCounts)
+
+	// an update that reports no change is not written
+	_, err = engine.UpdateCertificateCampaignStation(ctx, "campaign001", "cs002",
+		func(campaign *store.CertificateCampaign, station *store.CertificateCampaignStation) bool {
+			station.Status = store.CertificateCampaignStationFailed
+			return false
+		})
+	require.NoError(t, err)
+
+	stations, err := engine.ListCertificateCampaignStations(ctx, "campaign001", store.CertificateCampaignStationWaiting, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, stations, 1)
+	assert.Equal(t, "cs002", stations[0].ChargeStationId)
+
+	got, err = engine.UpdateCertificateCampaignStation(ctx, "campaign001", "cs003",
+		func(campaign *store.CertificateCampaign, station *store.CertificateCampaignStation) bool {
+			return true
+		})
+	require.NoError(t, err)
+	assert.Nil(t, got)
+
+	got, err = engine.UpdateCertificateCampaignStation(ctx, "unknown", "cs001",
+		func(campaign *store.CertificateCampaign, station *store.CertificateCampaignStation) bool {
+			return true
+		})
+	require.NoError(t, err)
+	assert.Nil(t, got)
+}
@@ -3,14 +3,17 @@
 package firestore
 
 import (
+	"cloud.google.com/go/firestore"
 	"context"
 	"crypto/sha256"
 	"crypto/x509"
 	"encoding/base64"
 	"encoding/pem"
 	"fmt"
+	"github.com/thoughtworks/maeve-csms/manager/store"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"time"
 )
 
 type certificate struct {
@@ -82,3 +85,219 @@ func (s *Store) DeleteCertificate(ctx context.Context, certificateHash string) e
 
 	return nil
 }
+
+type certificateRevocation struct {
+	ChargeStationId string    `firestore:"csId"`
+	Reason          string    `firestore:"reason"`
+	RevokedAt       time.Time `firestore:"revokedAt"`
+}
+
+func (s *Store) SetCertificateRevocation(ctx context.Context, revocation *store.CertificateRevocation) error {
+	ref := s.client.Doc(fmt.Sprintf("CertificateRevocation/%s", revocation.CertificateHash))
+	_, err := ref.Set(ctx, &certificateRevocation{
+		ChargeStationId: revocation.ChargeStationId,
+		Reason:          revocation.Reason,
+		RevokedAt:       revocation.RevokedAt,
+	})
+	if err != nil {
+		return fmt.Errorf("setting certificate revocation %s: %w", revocation.CertificateHash, err)
+	}
+	return nil
+}
+
+func (s *Store) LookupCertificateRevocation(ctx context.Context, certificateHash string) (*store.CertificateRevocation, error) {
+	ref := s.client.Doc(fmt.Sprintf("CertificateRevocation/%s", certificateHash))
+	snap, err := ref.Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("lookup certificate revocation %s: %w", certificateHash, err)
+	}
+	var data certificateRevocation
+	if err = snap.DataTo(&data); err != nil {
+		return nil, fmt.Errorf("map certificate revocation %s: %w", certificateHash, err)
+	}
+	return &store.CertificateRevocation{
+		CertificateHash: certificateHash,
+		ChargeStationId: data.ChargeStationId,
+		Reason:          data.Reason,
+		RevokedAt:       data.RevokedAt,
+	}, nil
+}
+
+func (s *Store) DeleteCertificateRevocation(ctx context.Context, certificateHash string) error {
+	ref := s.client.Doc(fmt.Sprintf("CertificateRevocation/%s", certificateHash))
+	_, err := ref.Delete(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil
+		}
+		return fmt.Errorf("delete certificate revocation %s: %w", certificateHash, err)
+	}
+	return nil
+}
+
+type issuedCertificate struct {
+	ChargeStationId    string    `firestore:"csId"`
+	CertificateType    string    `firestore:"type"`
+	SerialNumber       string    `firestore:"serial"`
+	IssuedAt           time.Time `firestore:"issuedAt"`
+	NotAfter           time.Time `firestore:"notAfter"`
+	Superseded         bool      `firestore:"superseded"`
+	RenewalRequestedAt time.Time `firestore:"renewalRequestedAt"`
+}
+
+func mapIssuedCertificate(certificateId string, data *issuedCertificate) *store.IssuedCertificate {
+	return &store.IssuedCertificate{
+		CertificateId:      certificateId,
+		ChargeStationId:    data.ChargeStationId,
+		CertificateType:    store.CertificateType(data.CertificateType),
+		SerialNumber:       data.SerialNumber,
+		IssuedAt:           data.IssuedAt,
+		NotAfter:           data.NotAfter,
+		Superseded:         data.Superseded,
+		RenewalRequestedAt: data.RenewalRequestedAt,
+	}
+}
+
+func (s *Store) SetIssuedCertificate(ctx context.Context, certificate *store.IssuedCertificate) error {
+	ref := s.client.Doc(fmt.Sprintf("IssuedCertificate/%s", certificate.CertificateId))
+	_, err := ref.Set(ctx, &issuedCertificate{
+		ChargeStationId:    certificate.ChargeStationId,
+		CertificateType:    string(certificate.CertificateType),
+		SerialNumber:       certificate.SerialNumber,
+		IssuedAt:           certificate.IssuedAt,
+		NotAfter:           certificate.NotAfter,
+		Superseded:         certificate.Superseded,
+		RenewalRequestedAt: certificate.RenewalRequestedAt,
+	})
+	if err != nil {
+		return fmt.Errorf("setting issued certificate %s: %w", certificate.CertificateId, err)
+	}
+	return nil
+}
+
+func (s *Store) LookupIssuedCertificate(ctx context.Context, certificateId string) (*store.IssuedCertificate, error) {
+	ref := s.client.Doc(fmt.Sprintf("IssuedCertificate/%s", certificateId))
+	snap, err := ref.Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("lookup issued certificate %s: %w", certificateId, err)
+	}
+	var data issuedCertificate
+	if err = snap.DataTo(&data); err != nil {
+		return nil, fmt.Errorf("map issued certificate %s: %w", certificateId, err)
+	}
+	return mapIssuedCertificate(certificateId, &data), nil
+}
+
+// ListIssuedCertificatesForChargeStation needs the IssuedCertificate (csId, issuedAt) index
+// defined in firestore.indexes.json
+func (s *Store) ListIssuedCertificatesForChargeStation(ctx context.Context, chargeStationId string) ([]*store.IssuedCertificate, error) {
+	snaps, err := s.client.Collection("IssuedCertificate").Where("csId", "==", chargeStationId).
+		OrderBy("issuedAt", firestore.Asc).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("list issued certificates for %s: %w", chargeStationId, err)
+	}
+	return mapIssuedCertificates(snaps)
+}
+
+// ListExpiringIssuedCertificates needs the IssuedCertificate (superseded, notAfter) index
+// defined in firestore.indexes.json
+func (s *Store) ListExpiringIssuedCertificates(ctx context.Context, expiresBefore time.Time, offset, limit int) ([]*store.IssuedCertificate, error) {
+	snaps, err := s.client.Collection("IssuedCertificate").Where("superseded", "==", false).
+		Where("notAfter", "<", expiresBefore).OrderBy("notAfter", firestore.Asc).
+		Offset(offset).Limit(limit).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("list expiring issued certificates: %w", err)
+	}
+	return mapIssuedCertificates(snaps)
+}
+
+func mapIssuedCertificates(snaps []*firestore.DocumentSnapshot) ([]*store.IssuedCertificate, error) {
+	var certificates = make([]*store.IssuedCertificate, 0, len(snaps))
+	for _, snap := range snaps {
+		var data issuedCertificate
+		if err := snap.DataTo(&data); err != nil {
+			return nil, fmt.Errorf("map issued certificate %s: %w", snap.Ref.ID, err)
+		}
+		certificates = append(certificates, mapIssuedCertificate(snap.Ref.ID, &data))
+	}
+	return certificates, nil
+}
+
+type rootCertificate struct {
+	CertificateType string `firestore:"type"`
+	PemCertificate  string `firestore:"pem"`
+	Revoked         bool   `firestore:"revoked"`
+}
+
+func (s *Store) SetRootCertificate(ctx context.Context, certificate *store.RootCertificate) error {
+	ref := s.client.Doc(fmt.Sprintf("RootCertificate/%s", certificate.CertificateId))
+	_, err := ref.Set(ctx, &rootCertificate{
+		CertificateType: string(certificate.CertificateType),
+		PemCertificate:  certificate.CertificateData,
+		Revoked:         certificate.Revoked,
+	})
+	if err != nil {
+		return fmt.Errorf("setting root certificate %s: %w", certificate.CertificateId, err)
+	}
+	return nil
+}
+
+func (s *Store) LookupRootCertificate(ctx context.Context, certificateId string) (*store.RootCertificate, error) {
+	ref := s.client.Doc(fmt.Sprintf("RootCertificate/%s", certificateId))
+	snap, err := ref.Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("lookup root certificate %s: %w", certificateId, err)
+	}
+	var data rootCertificate
+	if err = snap.DataTo(&data); err != nil {
+		return nil, fmt.Errorf("map root certificate %s: %w", certificateId, err)
+	}
+	return mapRootCertificate(certificateId, &data), nil
+}
+
+func (s *Store) ListRootCertificates(ctx context.Context) ([]*store.RootCertificate, error) {
+	snaps, err := s.client.Collection("RootCertificate").OrderBy(firestore.DocumentID, firestore.Asc).
+		Documents(ctx).GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("list root certificates: %w", err)
+	}
+	var certificates = make([]*store.RootCertificate, 0, len(snaps))
+	for _, snap := range snaps {
+		var data rootCertificate
+		if err = snap.DataTo(&data); err != nil {
+			return nil, fmt.Errorf("map root certificate %s: %w", snap.Ref.ID, err)
+		}
+		certificates = append(certificates, mapRootCertificate(snap.Ref.ID, &data))
+	}
+	return certificates, nil
+}
+
+func mapRootCertificate(certificateId string, data *rootCertificate) *store.RootCertificate {
+	return &store.RootCertificate{
+		CertificateId:   certificateId,
+		CertificateType: store.CertificateType(data.CertificateType),
+		CertificateData: data.PemCertificate,
+		Revoked:         data.Revoked,
+	}
+}
+
+func (s *Store) DeleteRootCertificate(ctx context.Context, certificateId string) error {
+	ref := s.client.Doc(fmt.Sprintf("RootCertificate/%s", certificateId))
+	_, err := ref.Delete(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil
+		}
+		return fmt.Errorf("delete root certificate %s: %w", certificateId, err)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package firestore
+
+import (
+	"context"
+	"fmt"
+	"github.com/thoughtworks/maeve-csms/manager/store"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type contract struct {
+	ProvisioningCertificateId string `firestore:"pcid"`
+}
+
+func (s *Store) SetContract(ctx context.Context, c *store.Contract) error {
+	ref := s.client.Doc(fmt.Sprintf("Contract/%s", c.EMAID))
+	_, err := ref.Set(ctx, &contract{
+		ProvisioningCertificateId: c.ProvisioningCertificateId,
+	})
+	if err != nil {
+		return fmt.Errorf("setting contract %s: %w", c.EMAID, err)
+	}
+	return nil
+}
+
+func (s *Store) LookupContract(ctx context.Context, emaid string) (*store.Contract, error) {
+	ref := s.client.Doc(fmt.Sprintf("Contract/%s", emaid))
+	snap, err := ref.Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("lookup contract %s: %w", emaid, err)
+	}
+	var data contract
+	if err = snap.DataTo(&data); err != nil {
+		return nil, fmt.Errorf("map contract %s: %w", emaid, err)
+	}
+	return mapContract(emaid, &data), nil
+}
+
+func (s *Store) LookupContractByProvisioningCertificateId(ctx context.Context, provisioningCertificateId string) (*store.Contract, error) {
+	snaps, err := s.client.Collection("Contract").Where("pcid", "==", provisioningCertificateId).
+		Limit(1).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("lookup contract for provisioning certificate %s: %w", provisioningCertificateId, err)
+	}
+	if len(snaps) == 0 {
+		return nil, nil
+	}
+	var data contract
+	if err = snaps[0].DataTo(&data); err != nil {
+		return nil, fmt.Errorf("map contract %s: %w", snaps[0].Ref.ID, err)
+	}
+	return mapContract(snaps[0].Ref.ID, &data), nil
+}
+
+func mapContract(emaid string, data *contract) *store.Contract {
+	return &store.Contract{
+		EMAID:                     emaid,
+		ProvisioningCertificateId: data.ProvisioningCertificateId,
+	}
+}
+
+func (s *Store) DeleteContract(ctx context.Context, emaid string) error {
+	ref := s.client.Doc(fmt.Sprintf("Contract/%s", emaid))
+	_, err := ref.Delete(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil
+		}
+		return fmt.Errorf("delete contract %s: %w", emaid, err)
+	}
+	return nil
+}
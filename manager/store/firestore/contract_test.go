@@ -0,0 +1,52 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build integration
+
+package firestore_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thoughtworks/maeve-csms/manager/store"
+	"github.com/thoughtworks/maeve-csms/manager/store/firestore"
+	"k8s.io/utils/clock"
+	"testing"
+)
+
+func TestSetLookupAndDeleteContract(t *testing.T) {
+	defer cleanupAllCollections(t, "myproject")
+
+	ctx := context.Background()
+
+	engine, err := firestore.NewStore(ctx, "myproject", clock.RealClock{})
+	require.NoError(t, err)
+
+	contracts := []*store.Contract{
+		{EMAID: "GBTWK012345678", ProvisioningCertificateId: "WMIV1234567890ABCDEX"},
+		{EMAID: "GBTWK876543210", ProvisioningCertificateId: "WMIV0987654321ABCDEX"},
+	}
+	for _, contract := range contracts {
+		err = engine.SetContract(ctx, contract)
+		require.NoError(t, err)
+	}
+
+	got, err := engine.LookupContract(ctx, "GBTWK876543210")
+	require.NoError(t, err)
+	assert.Equal(t, contracts[1], got)
+
+	got, err = engine.LookupContractByProvisioningCertificateId(ctx, "WMIV1234567890ABCDEX")
+	require.NoError(t, err)
+	assert.Equal(t, contracts[0], got)
+
+	got, err = engine.LookupContractByProvisioningCertificateId(ctx, "WMIV0000000000ABCDEX")
+	require.NoError(t, err)
+	assert.Nil(t, got)
+
+	err = engine.DeleteContract(ctx, "GBTWK012345678")
+	require.NoError(t, err)
+
+	got, err = engine.LookupContract(ctx, "GBTWK012345678")
+	require.NoError(t, err)
+	assert.Nil(t, got)
+}
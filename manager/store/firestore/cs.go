@@ -51,10 +51,210 @@ func (s *Store) LookupChargeStationAuth(ctx context.Context, chargeStationId str
 	}, nil
 }
 
+type chargeStationDetails struct {
+	Vendor          string    `firestore:"vendor"`
+	Model           string    `firestore:"model"`
+	SerialNumber    string    `firestore:"serial"`
+	FirmwareVersion string    `firestore:"firmware"`
+	Tags            []string  `firestore:"tags"`
+	RegisteredAt    time.Time `firestore:"registered"`
+	LastSeen        time.Time `firestore:"seen"`
+}
+
+func (s *Store) SetChargeStationDetails(ctx context.Context, chargeStationId string, details *store.ChargeStationDetails) error {
+	csRef := s.client.Doc(fmt.Sprintf("ChargeStationDetails/%s", chargeStationId))
+	_, err := csRef.Set(ctx, &chargeStationDetails{
+		Vendor:          details.Vendor,
+		Model:           details.Model,
+		SerialNumber:    details.SerialNumber,
+		FirmwareVersion: details.FirmwareVersion,
+		Tags:            details.Tags,
+		RegisteredAt:    details.RegisteredAt,
+		LastSeen:        details.LastSeen,
+	})
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func mapChargeStationDetails(chargeStationId string, csData *chargeStationDetails) *store.ChargeStationDetails {
+	return &store.ChargeStationDetails{
+		ChargeStationId: chargeStationId,
+		Vendor:          csData.Vendor,
+		Model:           csData.Model,
+		SerialNumber:    csData.SerialNumber,
+		FirmwareVersion: csData.FirmwareVersion,
+		Tags:            csData.Tags,
+		RegisteredAt:    csData.RegisteredAt,
+		LastSeen:        csData.LastSeen,
+	}
+}
+
+func (s *Store) LookupChargeStationDetails(ctx context.Context, chargeStationId string) (*store.ChargeStationDetails, error) {
+	csRef := s.client.Doc(fmt.Sprintf("ChargeStationDetails/%s", chargeStationId))
+	snap, err := csRef.Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("lookup charge station details %s: %w", chargeStationId, err)
+	}
+	var csData chargeStationDetails
+	if err = snap.DataTo(&csData); err != nil {
+		return nil, fmt.Errorf("map charge station details %s: %w", chargeStationId, err)
+	}
+	return mapChargeStationDetails(chargeStationId, &csData), nil
+}
+
+func (s *Store) ListChargeStationDetails(ctx context.Context, filter store.ChargeStationFilter, offset, limit int) ([]*store.ChargeStationDetails, error) {
+	query := s.client.Collection("ChargeStationDetails").Query
+	if filter.Vendor != "" {
+		query = query.Where("vendor", "==", filter.Vendor)
+	}
+	if filter.Model != "" {
+		query = query.Where("model", "==", filter.Model)
+	}
+	if filter.FirmwareVersion != "" {
+		query = query.Where("firmware", "==", filter.FirmwareVersion)
+	}
+	if filter.Tag != "" {
+		query = query.Where("tags", "array-contains", filter.Tag)
+	}
+	snaps, err := query.OrderBy(firestore.DocumentID, firestore.Asc).Offset(offset).Limit(limit).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("list charge station details: %w", err)
+	}
+	var details = make([]*store.ChargeStationDetails, 0, len(snaps))
+	for _, snap := range snaps {
+		var csData chargeStationDetails
+		if err = snap.DataTo(&csData); err != nil {
+			return nil, fmt.Errorf("map charge station details %s: %w", snap.Ref.ID, err)
+		}
+		details = append(details, mapChargeStationDetails(snap.Ref.ID, &csData))
+	}
+	return details, nil
+}
+
+// deletePageSize is the number of documents that are deleted together when deleting the
+// results of a query: it is kept within the limit on the number of writes in a single commit
+const deletePageSize = 500
+
+func (s *Store) DeleteChargeStation(ctx context.Context, chargeStationId string) error {
+	// the documents that are keyed by the charge station id are deleted atomically
+	err := s.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		for _, collection := range []string{
+			"ChargeStation",
+			"ChargeStationDetails",
+			"ChargeStationSettings",
+			"ChargeStationInstallCertificates",
+			"ChargeStationInstalledCertificates",
+			"ChargeStationTriggerMessage",
+			"ChargeStationRuntimeDetails",
+			"ChargeStationConnection",
+		} {
+			err := tx.Delete(s.client.Doc(fmt.Sprintf("%s/%s", collection, chargeStationId)))
+			if err != nil {
+				return err
+			}
+		}
+		return tx.Delete(authLockoutRef(s, store.AuthLockoutTypeChargeStation, chargeStationId))
+	})
+	if err != nil {
+		return fmt.Errorf("delete charge station %s: %w", chargeStationId, err)
+	}
+
+	// the number of documents that refer to the charge station is unbounded, so they are
+	// deleted in pages: deleting the charge station again completes an interrupted delete
+	for name, query := range map[string]firestore.Query{
+		"operation log":       chargeStationOperationsRef(s, chargeStationId).Query,
+		"issued certificates": s.client.Collection("IssuedCertificate").Where("csId", "==", chargeStationId),
+		"reservations":        s.client.Collection("Reservation").Where("chargeStationId", "==", chargeStationId),
+	} {
+		err = s.deleteQueryResults(ctx, query)
+		if err != nil {
+			return fmt.Errorf("delete charge station %s %s: %w", chargeStationId, name, err)
+		}
+	}
+	return nil
+}
+
+// deleteQueryResults deletes all the documents that match the query
+func (s *Store) deleteQueryResults(ctx context.Context, query firestore.Query) error {
+	for {
+		snaps, err := query.Limit(deletePageSize).Documents(ctx).GetAll()
+		if err != nil {
+			return err
+		}
+		if len(snaps) == 0 {
+			return nil
+		}
+
+		bw := s.client.BulkWriter(ctx)
+		var jobs = make([]*firestore.BulkWriterJob, 0, len(snaps))
+		for _, snap := range snaps {
+			job, err := bw.Delete(snap.Ref)
+			if err != nil {
+				bw.End()
+				return err
+			}
+			jobs = append(jobs, job)
+		}
+		bw.End()
+		for _, job := range jobs {
+			if _, err = job.Results(); err != nil {
+				return err
+			}
+		}
+
+		if len(snaps) < deletePageSize {
+			return nil
+		}
+	}
+}
+
+func (s *Store) BackfillChargeStationDetails(ctx context.Context) (int, error) {
+	authRefs, err := s.client.Collection("ChargeStation").DocumentRefs(ctx).GetAll()
+	if err != nil {
+		return 0, fmt.Errorf("list charge stations: %w", err)
+	}
+
+	created := 0
+	for start := 0; start < len(authRefs); start += deletePageSize {
+		end := start + deletePageSize
+		if end > len(authRefs) {
+			end = len(authRefs)
+		}
+		var detailsRefs = make([]*firestore.DocumentRef, 0, end-start)
+		for _, authRef := range authRefs[start:end] {
+			detailsRefs = append(detailsRefs, s.client.Doc(fmt.Sprintf("ChargeStationDetails/%s", authRef.ID)))
+		}
+		snaps, err := s.client.GetAll(ctx, detailsRefs)
+		if err != nil {
+			return created, fmt.Errorf("lookup charge station details: %w", err)
+		}
+		for _, snap := range snaps {
+			if snap.Exists() {
+				continue
+			}
+			// create fails if the details have been written since they were read
+			_, err = snap.Ref.Create(ctx, &chargeStationDetails{})
+			if err != nil && status.Code(err) != codes.AlreadyExists {
+				return created, fmt.Errorf("create charge station details %s: %w", snap.Ref.ID, err)
+			}
+			if err == nil {
+				created++
+			}
+		}
+	}
+	return created, nil
+}
+
 type chargeStationSetting struct {
 	Value     string    `firestore:"v"`
 	Status    string    `firestore:"s"`
 	SendAfter time.Time `firestore:"u"`
+	Attempts  int       `firestore:"a"`
 }
 
 func (s *Store) UpdateChargeStationSettings(ctx context.Context, chargeStationId string, settings *store.ChargeStationSettings) error {
@@ -62,8 +262,10 @@ func (s *Store) UpdateChargeStationSettings(ctx context.Context, chargeStationId
 	var set = make(map[string]*chargeStationSetting)
 	for k, v := range settings.Settings {
 		set[k] = &chargeStationSetting{
-			Value:  v.Value,
-			Status: string(v.Status),
+			Value:     v.Value,
+			Status:    string(v.Status),
+			SendAfter: v.SendAfter,
+			Attempts:  v.Attempts,
 		}
 	}
 	_, err := csRef.Set(ctx, set, firestore.MergeAll)
@@ -109,6 +311,7 @@ func mapChargeStationSettings(csData map[string]*chargeStationSetting) map[strin
 			Value:     v.Value,
 			Status:    store.ChargeStationSettingStatus(v.Status),
 			SendAfter: v.SendAfter,
+			Attempts:  v.Attempts,
 		}
 	}
 	return settings
@@ -147,6 +350,7 @@ type chargeStationInstallCertificate struct {
 	Data      string    `firestore:"d"`
 	Status    string    `firestore:"s"`
 	SendAfter time.Time `firestore:"u"`
+	Attempts  int       `firestore:"a"`
 }
 
 func mapChargeStationInstallCertificates(certificates map[string]*chargeStationInstallCertificate) []*store.ChargeStationInstallCertificate {
@@ -158,6 +362,7 @@ func mapChargeStationInstallCertificates(certificates map[string]*chargeStationI
 			CertificateData:               c.Data,
 			CertificateInstallationStatus: store.CertificateInstallationStatus(c.Status),
 			SendAfter:                     c.SendAfter,
+			Attempts:                      c.Attempts,
 		})
 	}
 	return certs
@@ -172,6 +377,7 @@ func (s *Store) UpdateChargeStationInstallCertificates(ctx context.Context, char
 			Data:      c.CertificateData,
 			Status:    string(c.CertificateInstallationStatus),
 			SendAfter: c.SendAfter,
+			Attempts:  c.Attempts,
 		}
 	}
 	_, err := csRef.Set(ctx, set, firestore.MergeAll)
@@ -229,6 +435,141 @@ func (s *Store) ListChargeStationInstallCertificates(ctx context.Context, pageSi
 	return installCerts, nil
 }
 
+type chargeStationInstalledCertificate struct {
+	Type           string    `firestore:"t"`
+	HashAlgorithm  string    `firestore:"h"`
+	IssuerNameHash string    `firestore:"n"`
+	IssuerKeyHash  string    `firestore:"k"`
+	SerialNumber   string    `firestore:"s"`
+	DeletionStatus string    `firestore:"d"`
+	SendAfter      time.Time `firestore:"u"`
+	Attempts       int       `firestore:"a"`
+}
+
+type chargeStationInstalledCertificates struct {
+	Certificates []*chargeStationInstalledCertificate `firestore:"certs"`
+	RequestedAt  time.Time                            `firestore:"requestedAt"`
+	ReportedAt   time.Time                            `firestore:"reportedAt"`
+}
+
+func mapChargeStationInstalledCertificates(chargeStationId string, data *chargeStationInstalledCertificates) *store.ChargeStationInstalledCertificates {
+	var certs = make([]*store.ChargeStationInstalledCertificate, 0, len(data.Certificates))
+	for _, c := range data.Certificates {
+		certs = append(certs, &store.ChargeStationInstalledCertificate{
+			CertificateType: store.CertificateType(c.Type),
+			HashAlgorithm:   c.HashAlgorithm,
+			IssuerNameHash:  c.IssuerNameHash,
+			IssuerKeyHash:   c.IssuerKeyHash,
+			SerialNumber:    c.SerialNumber,
+			DeletionStatus:  store.CertificateDeletionStatus(c.DeletionStatus),
+			SendAfter:       c.SendAfter,
+			Attempts:        c.Attempts,
+		})
+	}
+	return &store.ChargeStationInstalledCertificates{
+		ChargeStationId: chargeStationId,
+		Certificates:    certs,
+		RequestedAt:     data.RequestedAt,
+		ReportedAt:      data.ReportedAt,
+	}
+}
+
+func toFirestoreInstalledCertificates(certificates *store.ChargeStationInstalledCertificates) *chargeStationInstalledCertificates {
+	var certs = make([]*chargeStationInstalledCertificate, 0, len(certificates.Certificates))
+	for _, c := range certificates.Certificates {
+		certs = append(certs, &chargeStationInstalledCertificate{
+			Type:           string(c.CertificateType),
+			HashAlgorithm:  c.HashAlgorithm,
+			IssuerNameHash: c.IssuerNameHash,
+			IssuerKeyHash:  c.IssuerKeyHash,
+			SerialNumber:   c.SerialNumber,
+			DeletionStatus: string(c.DeletionStatus),
+			SendAfter:      c.SendAfter,
+			Attempts:       c.Attempts,
+		})
+	}
+	return &chargeStationInstalledCertificates{
+		Certificates: certs,
+		RequestedAt:  certificates.RequestedAt,
+		ReportedAt:   certificates.ReportedAt,
+	}
+}
+
+func (s *Store) SetChargeStationInstalledCertificates(ctx context.Context, chargeStationId string, certificates *store.ChargeStationInstalledCertificates) error {
+	csRef := s.client.Doc(fmt.Sprintf("ChargeStationInstalledCertificates/%s", chargeStationId))
+	_, err := csRef.Set(ctx, toFirestoreInstalledCertificates(certificates))
+	if err != nil {
+		return fmt.Errorf("setting charge station installed certificates %s: %w", chargeStationId, err)
+	}
+	return nil
+}
+
+func (s *Store) UpdateChargeStationInstalledCertificates(ctx context.Context, chargeStationId string, update func(installed *store.ChargeStationInstalledCertificates) bool) (*store.ChargeStationInstalledCertificates, error) {
+	csRef := s.client.Doc(fmt.Sprintf("ChargeStationInstalledCertificates/%s", chargeStationId))
+	var installed *store.ChargeStationInstalledCertificates
+	err := s.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		var data chargeStationInstalledCertificates
+		snap, err := tx.Get(csRef)
+		if err != nil && status.Code(err) != codes.NotFound {
+			return err
+		}
+		if err == nil {
+			if err = snap.DataTo(&data); err != nil {
+				return err
+			}
+		}
+		installed = mapChargeStationInstalledCertificates(chargeStationId, &data)
+		if !update(installed) {
+			return nil
+		}
+		return tx.Set(csRef, toFirestoreInstalledCertificates(installed))
+	})
+	if err != nil {
+		return nil, fmt.Errorf("update charge station installed certificates %s: %w", chargeStationId, err)
+	}
+	return installed, nil
+}
+
+func (s *Store) LookupChargeStationInstalledCertificates(ctx context.Context, chargeStationId string) (*store.ChargeStationInstalledCertificates, error) {
+	csRef := s.client.Doc(fmt.Sprintf("ChargeStationInstalledCertificates/%s", chargeStationId))
+	snap, err := csRef.Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("lookup charge station installed certificates %s: %w", chargeStationId, err)
+	}
+	var csData chargeStationInstalledCertificates
+	if err = snap.DataTo(&csData); err != nil {
+		return nil, fmt.Errorf("map charge station installed certificates %s: %w", chargeStationId, err)
+	}
+	return mapChargeStationInstalledCertificates(chargeStationId, &csData), nil
+}
+
+func (s *Store) ListChargeStationInstalledCertificates(ctx context.Context, pageSize int, previousCsId string) ([]*store.ChargeStationInstalledCertificates, error) {
+	var installedCerts []*store.ChargeStationInstalledCertificates
+	var docIt *firestore.DocumentIterator
+	if previousCsId == "" {
+		docIt = s.client.Collection("ChargeStationInstalledCertificates").OrderBy(firestore.DocumentID, firestore.Asc).
+			Limit(pageSize).Documents(ctx)
+	} else {
+		docIt = s.client.Collection("ChargeStationInstalledCertificates").OrderBy(firestore.DocumentID, firestore.Asc).
+			StartAfter(previousCsId).Limit(pageSize).Documents(ctx)
+	}
+	snaps, err := docIt.GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("list charge station installed certificates: %w", err)
+	}
+	for _, snap := range snaps {
+		var csData chargeStationInstalledCertificates
+		if err = snap.DataTo(&csData); err != nil {
+			return nil, fmt.Errorf("map charge station installed certificates: %w", err)
+		}
+		installedCerts = append(installedCerts, mapChargeStationInstalledCertificates(snap.Ref.ID, &csData))
+	}
+	return installedCerts, nil
+}
+
 type chargeStationRuntimeDetails struct {
 	OcppVersion string `firestore:"v"`
 }
@@ -263,56 +604,152 @@ func (s *Store) LookupChargeStationRuntimeDetails(ctx context.Context, chargeSta
 }
 
 type chargeStationTriggerMessage struct {
-	Type      string    `firestore:"t"`
-	Status    string    `firestore:"s"`
-	SendAfter time.Time `firestore:"u"`
+	Type        string    `firestore:"t"`
+	EvseId      *int      `firestore:"e"`
+	ConnectorId *int      `firestore:"c"`
+	Status      string    `firestore:"s"`
+	SendAfter   time.Time `firestore:"u"`
+	Attempts    int       `firestore:"a"`
+}
+
+// chargeStationTriggerMessages holds the queue of trigger messages for a charge station.
+// Before trigger messages were queued the document held a single trigger message, which
+// is read as a queue of one.
+type chargeStationTriggerMessages struct {
+	Queue     []*chargeStationTriggerMessage `firestore:"q"`
+	Type      string                         `firestore:"t,omitempty"`
+	Status    string                         `firestore:"s,omitempty"`
+	SendAfter time.Time                      `firestore:"u,omitempty"`
+	Attempts  int                            `firestore:"a,omitempty"`
+}
+
+func (t *chargeStationTriggerMessages) queue() []*chargeStationTriggerMessage {
+	if len(t.Queue) == 0 && t.Type != "" {
+		return []*chargeStationTriggerMessage{
+			{
+				Type:      t.Type,
+				Status:    t.Status,
+				SendAfter: t.SendAfter,
+				Attempts:  t.Attempts,
+			},
+		}
+	}
+	return t.Queue
 }
 
-func (s *Store) SetChargeStationTriggerMessage(ctx context.Context, chargeStationId string, triggerMessage *store.ChargeStationTriggerMessage) error {
+func toFirestoreTriggerMessage(triggerMessage *store.ChargeStationTriggerMessage) *chargeStationTriggerMessage {
+	return &chargeStationTriggerMessage{
+		Type:        string(triggerMessage.TriggerMessage),
+		EvseId:      triggerMessage.EvseId,
+		ConnectorId: triggerMessage.ConnectorId,
+		Status:      string(triggerMessage.TriggerStatus),
+		SendAfter:   triggerMessage.SendAfter,
+		Attempts:    triggerMessage.Attempts,
+	}
+}
+
+func mapChargeStationTriggerMessages(chargeStationId string, data *chargeStationTriggerMessages) *store.ChargeStationTriggerMessages {
+	triggerMessages := &store.ChargeStationTriggerMessages{
+		ChargeStationId: chargeStationId,
+	}
+	for _, t := range data.queue() {
+		triggerMessages.TriggerMessages = append(triggerMessages.TriggerMessages, &store.ChargeStationTriggerMessage{
+			ChargeStationId: chargeStationId,
+			TriggerMessage:  store.TriggerMessage(t.Type),
+			EvseId:          t.EvseId,
+			ConnectorId:     t.ConnectorId,
+			TriggerStatus:   store.TriggerStatus(t.Status),
+			SendAfter:       t.SendAfter,
+			Attempts:        t.Attempts,
+		})
+	}
+	return triggerMessages
+}
+
+// updateChargeStationTriggerMessages reads the queue of trigger messages for the charge station,
+// applies the update and writes the queue back (deleting the document if the queue is empty)
+func (s *Store) updateChargeStationTriggerMessages(ctx context.Context, chargeStationId string, update func(queue *store.ChargeStationTriggerMessages)) error {
 	csRef := s.client.Doc(fmt.Sprintf("ChargeStationTriggerMessage/%s", chargeStationId))
-	_, err := csRef.Set(ctx, &chargeStationTriggerMessage{
-		Type:      string(triggerMessage.TriggerMessage),
-		Status:    string(triggerMessage.TriggerStatus),
-		SendAfter: triggerMessage.SendAfter,
+	return s.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		var data chargeStationTriggerMessages
+		snap, err := tx.Get(csRef)
+		if err != nil && status.Code(err) != codes.NotFound {
+			return err
+		}
+		if err == nil {
+			if err = snap.DataTo(&data); err != nil {
+				return err
+			}
+		}
+		queue := mapChargeStationTriggerMessages(chargeStationId, &data)
+		update(queue)
+		if len(queue.TriggerMessages) == 0 {
+			return tx.Delete(csRef)
+		}
+		var updated chargeStationTriggerMessages
+		for _, t := range queue.TriggerMessages {
+			updated.Queue = append(updated.Queue, toFirestoreTriggerMessage(t))
+		}
+		return tx.Set(csRef, &updated)
+	})
+}
+
+func (s *Store) UpdateChargeStationTriggerMessages(ctx context.Context, chargeStationId string, triggerMessages *store.ChargeStationTriggerMessages) error {
+	err := s.updateChargeStationTriggerMessages(ctx, chargeStationId, func(queue *store.ChargeStationTriggerMessages) {
+		for _, v := range triggerMessages.TriggerMessages {
+			matched := false
+			for i, t := range queue.TriggerMessages {
+				if t.SameTarget(v) {
+					queue.TriggerMessages[i] = v
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				queue.TriggerMessages = append(queue.TriggerMessages, v)
+			}
+		}
 	})
 	if err != nil {
-		return err
+		return fmt.Errorf("update charge station trigger messages %s: %w", chargeStationId, err)
 	}
 	return nil
 }
 
-func (s *Store) DeleteChargeStationTriggerMessage(ctx context.Context, chargeStationId string) error {
-	csRef := s.client.Doc(fmt.Sprintf("ChargeStationTriggerMessage/%s", chargeStationId))
-	_, err := csRef.Delete(ctx)
+func (s *Store) DeleteChargeStationTriggerMessage(ctx context.Context, chargeStationId string, triggerMessage *store.ChargeStationTriggerMessage) error {
+	err := s.updateChargeStationTriggerMessages(ctx, chargeStationId, func(queue *store.ChargeStationTriggerMessages) {
+		var remaining []*store.ChargeStationTriggerMessage
+		for _, t := range queue.TriggerMessages {
+			if !t.SameTarget(triggerMessage) {
+				remaining = append(remaining, t)
+			}
+		}
+		queue.TriggerMessages = remaining
+	})
 	if err != nil {
-		return err
+		return fmt.Errorf("delete charge station trigger message %s: %w", chargeStationId, err)
 	}
 	return nil
 }
 
-func (s *Store) LookupChargeStationTriggerMessage(ctx context.Context, chargeStationId string) (*store.ChargeStationTriggerMessage, error) {
+func (s *Store) LookupChargeStationTriggerMessages(ctx context.Context, chargeStationId string) (*store.ChargeStationTriggerMessages, error) {
 	csRef := s.client.Doc(fmt.Sprintf("ChargeStationTriggerMessage/%s", chargeStationId))
 	snap, err := csRef.Get(ctx)
 	if err != nil {
 		if status.Code(err) == codes.NotFound {
 			return nil, nil
 		}
-		return nil, fmt.Errorf("lookup charge station trigger message %s: %w", chargeStationId, err)
+		return nil, fmt.Errorf("lookup charge station trigger messages %s: %w", chargeStationId, err)
 	}
-	var csData chargeStationTriggerMessage
+	var csData chargeStationTriggerMessages
 	if err = snap.DataTo(&csData); err != nil {
-		return nil, fmt.Errorf("map charge station trigger message %s: %w", chargeStationId, err)
+		return nil, fmt.Errorf("map charge station trigger messages %s: %w", chargeStationId, err)
 	}
-	return &store.ChargeStationTriggerMessage{
-		ChargeStationId: chargeStationId,
-		TriggerMessage:  store.TriggerMessage(csData.Type),
-		TriggerStatus:   store.TriggerStatus(csData.Status),
-		SendAfter:       csData.SendAfter,
-	}, nil
+	return mapChargeStationTriggerMessages(chargeStationId, &csData), nil
 }
 
-func (s *Store) ListChargeStationTriggerMessages(ctx context.Context, pageSize int, previousCsId string) ([]*store.ChargeStationTriggerMessage, error) {
-	var triggerMessages []*store.ChargeStationTriggerMessage
+func (s *Store) ListChargeStationTriggerMessages(ctx context.Context, pageSize int, previousCsId string) ([]*store.ChargeStationTriggerMessages, error) {
+	var triggerMessages []*store.ChargeStationTriggerMessages
 	var docIt *firestore.DocumentIterator
 	if previousCsId == "" {
 		docIt = s.client.Collection("ChargeStationTriggerMessage").OrderBy(firestore.DocumentID, firestore.Asc).
@@ -326,16 +763,87 @@ func (s *Store) ListChargeStationTriggerMessages(ctx context.Context, pageSize i
 		return nil, fmt.Errorf("list charge station trigger messages: %w", err)
 	}
 	for _, snap := range snaps {
-		var triggerMessage chargeStationTriggerMessage
-		if err = snap.DataTo(&triggerMessage); err != nil {
-			return nil, fmt.Errorf("map charge station trigger message: %w", err)
+		var csData chargeStationTriggerMessages
+		if err = snap.DataTo(&csData); err != nil {
+			return nil, fmt.Errorf("map charge station trigger messages: %w", err)
 		}
-		triggerMessages = append(triggerMessages, &store.ChargeStationTriggerMessage{
-			ChargeStationId: snap.Ref.ID,
-			TriggerMessage:  store.TriggerMessage(triggerMessage.Type),
-			TriggerStatus:   store.TriggerStatus(triggerMessage.Status),
-			SendAfter:       triggerMessage.SendAfter,
-		})
+		triggerMessages = append(triggerMessages, mapChargeStationTriggerMessages(snap.Ref.ID, &csData))
 	}
 	return triggerMessages, nil
 }
+
+type chargeStationConnection struct {
+	Connected       bool      `firestore:"connected"`
+	GatewayId       string    `firestore:"gw"`
+	ConnectionId    string    `firestore:"conn"`
+	OcppVersion     string    `firestore:"v"`
+	RemoteAddr      string    `firestore:"addr"`
+	SecurityProfile int8      `firestore:"sp"`
+	ConnectedAt     time.Time `firestore:"up"`
+	DisconnectedAt  time.Time `firestore:"down"`
+}
+
+func (s *Store) SetChargeStationConnection(ctx context.Context, chargeStationId string, connection *store.ChargeStationConnection) error {
+	csRef := s.client.Doc(fmt.Sprintf("ChargeStationConnection/%s", chargeStationId))
+	_, err := csRef.Set(ctx, &chargeStationConnection{
+		Connected:       connection.Connected,
+		GatewayId:       connection.GatewayId,
+		ConnectionId:    connection.ConnectionId,
+		OcppVersion:     connection.OcppVersion,
+		RemoteAddr:      connection.RemoteAddr,
+		SecurityProfile: int8(connection.SecurityProfile),
+		ConnectedAt:     connection.ConnectedAt,
+		DisconnectedAt:  connection.DisconnectedAt,
+	})
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func mapChargeStationConnection(chargeStationId string, csData *chargeStationConnection) *store.ChargeStationConnection {
+	return &store.ChargeStationConnection{
+		ChargeStationId: chargeStationId,
+		Connected:       csData.Connected,
+		GatewayId:       csData.GatewayId,
+		ConnectionId:    csData.ConnectionId,
+		OcppVersion:     csData.OcppVersion,
+		RemoteAddr:      csData.RemoteAddr,
+		SecurityProfile: store.SecurityProfile(csData.SecurityProfile),
+		ConnectedAt:     csData.ConnectedAt,
+		DisconnectedAt:  csData.DisconnectedAt,
+	}
+}
+
+func (s *Store) LookupChargeStationConnection(ctx context.Context, chargeStationId string) (*store.ChargeStationConnection, error) {
+	csRef := s.client.Doc(fmt.Sprintf("ChargeStationConnection/%s", chargeStationId))
+	snap, err := csRef.Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("lookup charge station connection %s: %w", chargeStationId, err)
+	}
+	var csData chargeStationConnection
+	if err = snap.DataTo(&csData); err != nil {
+		return nil, fmt.Errorf("map charge station connection %s: %w", chargeStationId, err)
+	}
+	return mapChargeStationConnection(chargeStationId, &csData), nil
+}
+
+func (s *Store) ListConnectedChargeStations(ctx context.Context, offset, limit int) ([]*store.ChargeStationConnection, error) {
+	snaps, err := s.client.Collection("ChargeStationConnection").Where("connected", "==", true).
+		OrderBy(firestore.DocumentID, firestore.Asc).Offset(offset).Limit(limit).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("list connected charge stations: %w", err)
+	}
+	var connections = make([]*store.ChargeStationConnection, 0, len(snaps))
+	for _, snap := range snaps {
+		var csData chargeStationConnection
+		if err = snap.DataTo(&csData); err != nil {
+			return nil, fmt.Errorf("map charge station connection %s: %w", snap.Ref.ID, err)
+		}
+		connections = append(connections, mapChargeStationConnection(snap.Ref.ID, &csData))
+	}
+	return connections, nil
+}
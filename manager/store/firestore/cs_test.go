@@ -289,6 +289,109 @@ func TestListChargeStationInstallCertificates(t *testing.T) {
 	assert.Len(t, csIds, 25)
 }
 
+func TestSetLookupAndListChargeStationInstalledCertificates(t *testing.T) {
+	defer cleanupAllCollections(t, "myproject")
+
+	ctx := context.Background()
+
+	now := time.Now().UTC()
+	engine, err := firestore.NewStore(ctx, "myproject", clockTest.NewFakePassiveClock(now))
+	require.NoError(t, err)
+
+	want := &store.ChargeStationInstalledCertificates{
+		Certificates: []*store.ChargeStationInstalledCertificate{
+			{
+				CertificateType: store.CertificateTypeV2G,
+				HashAlgorithm:   "SHA256",
+				IssuerNameHash:  "name-hash",
+				IssuerKeyHash:   "key-hash",
+				SerialNumber:    "01",
+				DeletionStatus:  store.CertificateDeletionPending,
+				SendAfter:       now,
+			},
+		},
+		RequestedAt: now.Add(-time.Minute),
+		ReportedAt:  now,
+	}
+	for i := 0; i < 15; i++ {
+		err = engine.SetChargeStationInstalledCertificates(ctx, fmt.Sprintf("cs%03d", i), want)
+		require.NoError(t, err)
+	}
+
+	got, err := engine.LookupChargeStationInstalledCertificates(ctx, "cs001")
+	require.NoError(t, err)
+	assert.Equal(t, "cs001", got.ChargeStationId)
+	assert.Equal(t, want.Certificates, got.Certificates)
+	assert.Equal(t, want.RequestedAt, got.RequestedAt)
+	assert.Equal(t, want.ReportedAt, got.ReportedAt)
+
+	got, err = engine.LookupChargeStationInstalledCertificates(ctx, "unknown")
+	require.NoError(t, err)
+	assert.Nil(t, got)
+
+	page1, err := engine.ListChargeStationInstalledCertificates(ctx, 10, "")
+	require.NoError(t, err)
+	require.Len(t, page1, 10)
+	page2, err := engine.ListChargeStationInstalledCertificates(ctx, 10, page1[len(page1)-1].ChargeStationId)
+	require.NoError(t, err)
+	require.Len(t, page2, 5)
+	assert.Equal(t, "cs014", page2[4].ChargeStationId)
+}
+
+func TestUpdateChargeStationInstalledCertificates(t *testing.T) {
+	defer cleanupAllCollections(t, "myproject")
+
+	ctx := context.Background()
+
+	engine, err := firestore.NewStore(ctx, "myproject", clock.RealClock{})
+	require.NoError(t, err)
+
+	now := time.Date(2023, 6, 15, 15, 0, 0, 0, time.UTC)
+	certificate := &store.ChargeStationInstalledCertificate{
+		CertificateType: store.CertificateTypeV2G,
+		HashAlgorithm:   "SHA256",
+		IssuerNameHash:  "name-hash",
+		IssuerKeyHash:   "key-hash",
+		SerialNumber:    "01",
+	}
+
+	// a charge station without installed certificates starts with an empty inventory
+	got, err := engine.UpdateChargeStationInstalledCertificates(ctx, "cs001", func(installed *store.ChargeStationInstalledCertificates) bool {
+		assert.Equal(t, "cs001", installed.ChargeStationId)
+		assert.Empty(t, installed.Certificates)
+		installed.Certificates = append(installed.Certificates, certificate)
+		installed.ReportedAt = now
+		return true
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []*store.ChargeStationInstalledCertificate{certificate}, got.Certificates)
+
+	_, err = engine.UpdateChargeStationInstalledCertificates(ctx, "cs001", func(installed *store.ChargeStationInstalledCertificates) bool {
+		installed.Certificates[0].DeletionStatus = store.CertificateDeletionPending
+		installed.Certificates[0].Attempts = 1
+		return true
+	})
+	require.NoError(t, err)
+
+	// an update that reports no change is not written
+	_, err = engine.UpdateChargeStationInstalledCertificates(ctx, "cs001", func(installed *store.ChargeStationInstalledCertificates) bool {
+		installed.Certificates = nil
+		return false
+	})
+	require.NoError(t, err)
+
+	got, err = engine.LookupChargeStationInstalledCertificates(ctx, "cs001")
+	require.NoError(t, err)
+	require.Len(t, got.Certificates, 1)
+	assert.Equal(t, store.CertificateDeletionPending, got.Certificates[0].DeletionStatus)
+	assert.Equal(t, 1, got.Certificates[0].Attempts)
+	assert.Equal(t, now, got.ReportedAt.UTC())
+
+	got, err = engine.LookupChargeStationInstalledCertificates(ctx, "cs002")
+	require.NoError(t, err)
+	assert.Nil(t, got)
+}
+
 func TestSetAndLookupChargeStationRuntimeDetails(t *testing.T) {
 	defer cleanupAllCollections(t, "myproject")
 
@@ -323,7 +426,7 @@ func TestLookupChargeStationRuntimeDetailsWithUnregisteredChargeStation(t *testi
 	assert.Nil(t, got)
 }
 
-func TestListChargeStationTriggerMessages(t *testing.T) {
+func TestUpdateLookupAndDeleteChargeStationTriggerMessages(t *testing.T) {
 	defer cleanupAllCollections(t, "myproject")
 
 	ctx := context.Background()
@@ -331,14 +434,251 @@ func TestListChargeStationTriggerMessages(t *testing.T) {
 	triggerStore, err := firestore.NewStore(ctx, "myproject", clock.RealClock{})
 	require.NoError(t, err)
 
-	err = triggerStore.SetChargeStationTriggerMessage(ctx, "cs001", &store.ChargeStationTriggerMessage{
-		TriggerMessage: store.TriggerMessageBootNotification,
-		TriggerStatus:  store.TriggerStatusPending,
+	evseId, connectorId := 1, 2
+	err = triggerStore.UpdateChargeStationTriggerMessages(ctx, "cs001", &store.ChargeStationTriggerMessages{
+		TriggerMessages: []*store.ChargeStationTriggerMessage{
+			{TriggerMessage: store.TriggerMessageBootNotification, TriggerStatus: store.TriggerStatusPending},
+			{TriggerMessage: store.TriggerMessageStatusNotification, EvseId: &evseId, ConnectorId: &connectorId, TriggerStatus: store.TriggerStatusPending},
+		},
+	})
+	require.NoError(t, err)
+
+	// a trigger for the same target replaces the queued trigger, others are appended
+	err = triggerStore.UpdateChargeStationTriggerMessages(ctx, "cs001", &store.ChargeStationTriggerMessages{
+		TriggerMessages: []*store.ChargeStationTriggerMessage{
+			{TriggerMessage: store.TriggerMessageBootNotification, TriggerStatus: store.TriggerStatusRejected, Attempts: 1},
+			{TriggerMessage: store.TriggerMessageStatusNotification, TriggerStatus: store.TriggerStatusPending},
+		},
+	})
+	require.NoError(t, err)
+
+	want := &store.ChargeStationTriggerMessages{
+		ChargeStationId: "cs001",
+		TriggerMessages: []*store.ChargeStationTriggerMessage{
+			{ChargeStationId: "cs001", TriggerMessage: store.TriggerMessageBootNotification, TriggerStatus: store.TriggerStatusRejected, Attempts: 1},
+			{ChargeStationId: "cs001", TriggerMessage: store.TriggerMessageStatusNotification, EvseId: &evseId, ConnectorId: &connectorId, TriggerStatus: store.TriggerStatusPending},
+			{ChargeStationId: "cs001", TriggerMessage: store.TriggerMessageStatusNotification, TriggerStatus: store.TriggerStatusPending},
+		},
+	}
+
+	got, err := triggerStore.LookupChargeStationTriggerMessages(ctx, "cs001")
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+
+	list, err := triggerStore.ListChargeStationTriggerMessages(ctx, 10, "")
+	require.NoError(t, err)
+	assert.Equal(t, []*store.ChargeStationTriggerMessages{want}, list)
+
+	err = triggerStore.DeleteChargeStationTriggerMessage(ctx, "cs001", &store.ChargeStationTriggerMessage{
+		TriggerMessage: store.TriggerMessageStatusNotification,
+		EvseId:         &evseId,
+		ConnectorId:    &connectorId,
+	})
+	require.NoError(t, err)
+
+	got, err = triggerStore.LookupChargeStationTriggerMessages(ctx, "cs001")
+	require.NoError(t, err)
+	require.Len(t, got.TriggerMessages, 2)
+	assert.Equal(t, store.TriggerMessageBootNotification, got.TriggerMessages[0].TriggerMessage)
+	assert.Nil(t, got.TriggerMessages[1].EvseId)
+
+	for _, triggerMessage := range got.TriggerMessages {
+		err = triggerStore.DeleteChargeStationTriggerMessage(ctx, "cs001", triggerMessage)
+		require.NoError(t, err)
+	}
+
+	got, err = triggerStore.LookupChargeStationTriggerMessages(ctx, "cs001")
+	require.NoError(t, err)
+	assert.Nil(t, got)
+}
+
+func TestSetListAndDeleteChargeStationDetails(t *testing.T) {
+	defer cleanupAllCollections(t, "myproject")
+
+	ctx := context.Background()
+
+	engine, err := firestore.NewStore(ctx, "myproject", clock.RealClock{})
+	require.NoError(t, err)
+
+	registeredAt := time.Date(2023, 6, 15, 15, 0, 0, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		model := "Model A"
+		if i == 2 {
+			model = "Model B"
+		}
+		err = engine.SetChargeStationDetails(ctx, fmt.Sprintf("cs%03d", i), &store.ChargeStationDetails{
+			Vendor:       "Vendor",
+			Model:        model,
+			Tags:         []string{fmt.Sprintf("site%d", i%2)},
+			RegisteredAt: registeredAt,
+		})
+		require.NoError(t, err)
+	}
+	err = engine.SetChargeStationAuth(ctx, "cs000", &store.ChargeStationAuth{
+		SecurityProfile: store.TLSWithBasicAuth,
+	})
+	require.NoError(t, err)
+
+	got, err := engine.LookupChargeStationDetails(ctx, "cs000")
+	require.NoError(t, err)
+	assert.Equal(t, &store.ChargeStationDetails{
+		ChargeStationId: "cs000",
+		Vendor:          "Vendor",
+		Model:           "Model A",
+		Tags:            []string{"site0"},
+		RegisteredAt:    registeredAt,
+	}, got)
+
+	list, err := engine.ListChargeStationDetails(ctx, store.ChargeStationFilter{Model: "Model A"}, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, "cs000", list[0].ChargeStationId)
+	assert.Equal(t, "cs001", list[1].ChargeStationId)
+
+	list, err = engine.ListChargeStationDetails(ctx, store.ChargeStationFilter{}, 1, 10)
+	require.NoError(t, err)
+	assert.Len(t, list, 2)
+
+	list, err = engine.ListChargeStationDetails(ctx, store.ChargeStationFilter{Tag: "site0"}, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, "cs000", list[0].ChargeStationId)
+	assert.Equal(t, "cs002", list[1].ChargeStationId)
+
+	err = engine.DeleteChargeStation(ctx, "cs000")
+	require.NoError(t, err)
+
+	got, err = engine.LookupChargeStationDetails(ctx, "cs000")
+	require.NoError(t, err)
+	assert.Nil(t, got)
+	auth, err := engine.LookupChargeStationAuth(ctx, "cs000")
+	require.NoError(t, err)
+	assert.Nil(t, auth)
+}
+
+func TestSetLookupAndListChargeStationConnections(t *testing.T) {
+	defer cleanupAllCollections(t, "myproject")
+
+	ctx := context.Background()
+
+	engine, err := firestore.NewStore(ctx, "myproject", clock.RealClock{})
+	require.NoError(t, err)
+
+	connectedAt := time.Date(2023, 6, 15, 15, 0, 0, 0, time.UTC)
+	want := &store.ChargeStationConnection{
+		ChargeStationId: "cs001",
+		Connected:       true,
+		GatewayId:       "gateway1",
+		ConnectionId:    "abc123",
+		OcppVersion:     "ocpp2.0.1",
+		RemoteAddr:      "127.0.0.1:1234",
+		SecurityProfile: store.TLSWithClientSideCertificates,
+		ConnectedAt:     connectedAt,
+	}
+	err = engine.SetChargeStationConnection(ctx, "cs001", want)
+	require.NoError(t, err)
+	err = engine.SetChargeStationConnection(ctx, "cs002", &store.ChargeStationConnection{
+		Connected:      false,
+		GatewayId:      "gateway1",
+		ConnectedAt:    connectedAt,
+		DisconnectedAt: connectedAt.Add(time.Minute),
 	})
 	require.NoError(t, err)
 
-	got, err := triggerStore.ListChargeStationTriggerMessages(ctx, 10, "")
+	got, err := engine.LookupChargeStationConnection(ctx, "cs001")
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+
+	list, err := engine.ListConnectedChargeStations(ctx, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, "cs001", list[0].ChargeStationId)
+
+	got, err = engine.LookupChargeStationConnection(ctx, "unknown")
 	require.NoError(t, err)
+	assert.Nil(t, got)
+}
 
-	t.Logf("%+v", got)
+func TestDeleteChargeStationDeletesRelatedRecords(t *testing.T) {
+	defer cleanupAllCollections(t, "myproject")
+
+	ctx := context.Background()
+
+	engine, err := firestore.NewStore(ctx, "myproject", clock.RealClock{})
+	require.NoError(t, err)
+
+	err = engine.SetChargeStationAuth(ctx, "cs001", &store.ChargeStationAuth{})
+	require.NoError(t, err)
+	err = engine.AppendChargeStationOperation(ctx, &store.ChargeStationOperation{
+		ChargeStationId: "cs001",
+		Timestamp:       time.Now().UTC(),
+		Event:           store.ChargeStationOperationSent,
+		Action:          "Reset",
+	})
+	require.NoError(t, err)
+	err = engine.SetAuthLockout(ctx, &store.AuthLockout{
+		Type:     store.AuthLockoutTypeChargeStation,
+		Id:       "cs001",
+		Failures: 1,
+	})
+	require.NoError(t, err)
+	err = engine.SetIssuedCertificate(ctx, &store.IssuedCertificate{
+		CertificateId:   "cert001",
+		ChargeStationId: "cs001",
+		CertificateType: store.CertificateTypeCSMS,
+	})
+	require.NoError(t, err)
+	for id, csId := range map[int]string{1: "cs001", 2: "cs002"} {
+		err = engine.SetReservation(ctx, &store.Reservation{
+			ReservationId:   id,
+			ChargeStationId: csId,
+			Status:          store.ReservationStatusAccepted,
+		})
+		require.NoError(t, err)
+	}
+
+	err = engine.DeleteChargeStation(ctx, "cs001")
+	require.NoError(t, err)
+
+	operations, err := engine.ListChargeStationOperations(ctx, "cs001", 0, 10)
+	require.NoError(t, err)
+	assert.Len(t, operations, 0)
+	lockout, err := engine.LookupAuthLockout(ctx, store.AuthLockoutTypeChargeStation, "cs001")
+	require.NoError(t, err)
+	assert.Nil(t, lockout)
+	issued, err := engine.LookupIssuedCertificate(ctx, "cert001")
+	require.NoError(t, err)
+	assert.Nil(t, issued)
+	reservation, err := engine.LookupReservation(ctx, 1)
+	require.NoError(t, err)
+	assert.Nil(t, reservation)
+	reservation, err = engine.LookupReservation(ctx, 2)
+	require.NoError(t, err)
+	assert.NotNil(t, reservation)
+}
+
+func TestBackfillChargeStationDetails(t *testing.T) {
+	defer cleanupAllCollections(t, "myproject")
+
+	ctx := context.Background()
+
+	engine, err := firestore.NewStore(ctx, "myproject", clock.RealClock{})
+	require.NoError(t, err)
+
+	err = engine.SetChargeStationAuth(ctx, "cs001", &store.ChargeStationAuth{})
+	require.NoError(t, err)
+	err = engine.SetChargeStationAuth(ctx, "cs002", &store.ChargeStationAuth{})
+	require.NoError(t, err)
+	err = engine.SetChargeStationDetails(ctx, "cs002", &store.ChargeStationDetails{Vendor: "Vendor"})
+	require.NoError(t, err)
+
+	created, err := engine.BackfillChargeStationDetails(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, created)
+
+	list, err := engine.ListChargeStationDetails(ctx, store.ChargeStationFilter{}, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, "cs001", list[0].ChargeStationId)
+	assert.Equal(t, "Vendor", list[1].Vendor)
 }
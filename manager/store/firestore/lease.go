@@ -0,0 +1,97 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package firestore
+
+import (
+	"cloud.google.com/go/firestore"
+	"context"
+	"fmt"
+	"github.com/thoughtworks/maeve-csms/manager/store"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"time"
+)
+
+type lease struct {
+	Holder    string    `firestore:"holder"`
+	ExpiresAt time.Time `firestore:"expires"`
+}
+
+func leaseRef(s *Store, name string) *firestore.DocumentRef {
+	return s.client.Doc(fmt.Sprintf("Lease/%s", name))
+}
+
+func (s *Store) AcquireLease(ctx context.Context, name, holder string, duration time.Duration) (bool, error) {
+	ref := leaseRef(s, name)
+	var acquired bool
+	err := s.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		acquired = false
+		now := s.clock.Now()
+		snap, err := tx.Get(ref)
+		if err != nil && status.Code(err) != codes.NotFound {
+			return err
+		}
+		if err == nil {
+			var data lease
+			if err = snap.DataTo(&data); err != nil {
+				return err
+			}
+			if data.Holder != holder && data.ExpiresAt.After(now) {
+				return nil
+			}
+		}
+		acquired = true
+		return tx.Set(ref, &lease{
+			Holder:    holder,
+			ExpiresAt: now.Add(duration),
+		})
+	})
+	if err != nil {
+		return false, fmt.Errorf("acquire lease %s: %w", name, err)
+	}
+	return acquired, nil
+}
+
+func (s *Store) ReleaseLease(ctx context.Context, name, holder string) error {
+	ref := leaseRef(s, name)
+	err := s.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		snap, err := tx.Get(ref)
+		if err != nil {
+			if status.Code(err) == codes.NotFound {
+				return nil
+			}
+			return err
+		}
+		var data lease
+		if err = snap.DataTo(&data); err != nil {
+			return err
+		}
+		if data.Holder != holder {
+			return nil
+		}
+		return tx.Delete(ref)
+	})
+	if err != nil {
+		return fmt.Errorf("release lease %s: %w", name, err)
+	}
+	return nil
+}
+
+func (s *Store) LookupLease(ctx context.Context, name string) (*store.Lease, error) {
+	snap, err := leaseRef(s, name).Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("lookup lease %s: %w", name, err)
+	}
+	var data lease
+	if err = snap.DataTo(&data); err != nil {
+		return nil, fmt.Errorf("map lease %s: %w", name, err)
+	}
+	return &store.Lease{
+		Name:      name,
+		Holder:    data.Holder,
+		ExpiresAt: data.ExpiresAt,
+	}, nil
+}
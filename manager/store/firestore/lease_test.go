@@ -0,0 +1,54 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build integration
+
+package firestore_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thoughtworks/maeve-csms/manager/store/firestore"
+	clockTest "k8s.io/utils/clock/testing"
+	"testing"
+	"time"
+)
+
+func TestAcquireRenewAndReleaseLease(t *testing.T) {
+	defer cleanupAllCollections(t, "myproject")
+
+	ctx := context.Background()
+	now := time.Date(2023, 6, 15, 15, 0, 0, 0, time.UTC)
+	clock := clockTest.NewFakePassiveClock(now)
+
+	engine, err := firestore.NewStore(ctx, "myproject", clock)
+	require.NoError(t, err)
+
+	acquired, err := engine.AcquireLease(ctx, "sync-settings", "replica1", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, acquired)
+
+	acquired, err = engine.AcquireLease(ctx, "sync-settings", "replica2", time.Minute)
+	require.NoError(t, err)
+	assert.False(t, acquired)
+
+	clock.SetTime(now.Add(30 * time.Second))
+	acquired, err = engine.AcquireLease(ctx, "sync-settings", "replica1", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, acquired)
+
+	got, err := engine.LookupLease(ctx, "sync-settings")
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, "replica1", got.Holder)
+	assert.Equal(t, now.Add(90*time.Second), got.ExpiresAt.UTC())
+
+	err = engine.ReleaseLease(ctx, "sync-settings", "replica2")
+	require.NoError(t, err)
+	err = engine.ReleaseLease(ctx, "sync-settings", "replica1")
+	require.NoError(t, err)
+
+	acquired, err = engine.AcquireLease(ctx, "sync-settings", "replica2", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, acquired)
+}
@@ -0,0 +1,123 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package firestore
+
+import (
+	"cloud.google.com/go/firestore"
+	"context"
+	"fmt"
+	"github.com/thoughtworks/maeve-csms/manager/store"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"time"
+)
+
+type authLockout struct {
+	Type          string    `firestore:"type"`
+	Id            string    `firestore:"id"`
+	Failures      int       `firestore:"failures"`
+	Lockouts      int       `firestore:"lockouts"`
+	LastFailureAt time.Time `firestore:"last"`
+	LockedUntil   time.Time `firestore:"until"`
+}
+
+func authLockoutRef(s *Store, lockoutType store.AuthLockoutType, id string) *firestore.DocumentRef {
+	return s.client.Doc(fmt.Sprintf("AuthLockout/%s:%s", lockoutType, id))
+}
+
+func mapAuthLockout(data *authLockout) *store.AuthLockout {
+	return &store.AuthLockout{
+		Type:          store.AuthLockoutType(data.Type),
+		Id:            data.Id,
+		Failures:      data.Failures,
+		Lockouts:      data.Lockouts,
+		LastFailureAt: data.LastFailureAt,
+		LockedUntil:   data.LockedUntil,
+	}
+}
+
+func toFirestoreAuthLockout(lockout *store.AuthLockout) *authLockout {
+	return &authLockout{
+		Type:          string(lockout.Type),
+		Id:            lockout.Id,
+		Failures:      lockout.Failures,
+		Lockouts:      lockout.Lockouts,
+		LastFailureAt: lockout.LastFailureAt,
+		LockedUntil:   lockout.LockedUntil,
+	}
+}
+
+func (s *Store) SetAuthLockout(ctx context.Context, lockout *store.AuthLockout) error {
+	_, err := authLockoutRef(s, lockout.Type, lockout.Id).Set(ctx, toFirestoreAuthLockout(lockout))
+	if err != nil {
+		return fmt.Errorf("setting auth lockout %s %s: %w", lockout.Type, lockout.Id, err)
+	}
+	return nil
+}
+
+func (s *Store) LookupAuthLockout(ctx context.Context, lockoutType store.AuthLockoutType, id string) (*store.AuthLockout, error) {
+	snap, err := authLockoutRef(s, lockoutType, id).Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("lookup auth lockout %s %s: %w", lockoutType, id, err)
+	}
+	var data authLockout
+	if err = snap.DataTo(&data); err != nil {
+		return nil, fmt.Errorf("map auth lockout %s %s: %w", lockoutType, id, err)
+	}
+	return mapAuthLockout(&data), nil
+}
+
+func (s *Store) UpdateAuthLockout(ctx context.Context, lockoutType store.AuthLockoutType, id string, update func(lockout *store.AuthLockout) bool) (*store.AuthLockout, error) {
+	ref := authLockoutRef(s, lockoutType, id)
+	var lockout *store.AuthLockout
+	err := s.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		lockout = &store.AuthLockout{Type: lockoutType, Id: id}
+		snap, err := tx.Get(ref)
+		if err != nil && status.Code(err) != codes.NotFound {
+			return err
+		}
+		if err == nil {
+			var data authLockout
+			if err = snap.DataTo(&data); err != nil {
+				return err
+			}
+			lockout = mapAuthLockout(&data)
+		}
+		if !update(lockout) {
+			return nil
+		}
+		return tx.Set(ref, toFirestoreAuthLockout(lockout))
+	})
+	if err != nil {
+		return nil, fmt.Errorf("update auth lockout %s %s: %w", lockoutType, id, err)
+	}
+	return lockout, nil
+}
+
+func (s *Store) ListAuthLockouts(ctx context.Context, lockedAt time.Time, offset, limit int) ([]*store.AuthLockout, error) {
+	snaps, err := s.client.Collection("AuthLockout").Where("until", ">", lockedAt).
+		OrderBy("until", firestore.Asc).Offset(offset).Limit(limit).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("list auth lockouts: %w", err)
+	}
+	var lockouts = make([]*store.AuthLockout, 0, len(snaps))
+	for _, snap := range snaps {
+		var data authLockout
+		if err = snap.DataTo(&data); err != nil {
+			return nil, fmt.Errorf("map auth lockout %s: %w", snap.Ref.ID, err)
+		}
+		lockouts = append(lockouts, mapAuthLockout(&data))
+	}
+	return lockouts, nil
+}
+
+func (s *Store) DeleteAuthLockout(ctx context.Context, lockoutType store.AuthLockoutType, id string) error {
+	_, err := authLockoutRef(s, lockoutType, id).Delete(ctx)
+	if err != nil {
+		return fmt.Errorf("delete auth lockout %s %s: %w", lockoutType, id, err)
+	}
+	return nil
+}
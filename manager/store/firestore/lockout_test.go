@@ -0,0 +1,97 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build integration
+
+package firestore_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thoughtworks/maeve-csms/manager/store"
+	"github.com/thoughtworks/maeve-csms/manager/store/firestore"
+	"k8s.io/utils/clock"
+	"testing"
+	"time"
+)
+
+func TestSetLookupListAndDeleteAuthLockouts(t *testing.T) {
+	defer cleanupAllCollections(t, "myproject")
+
+	ctx := context.Background()
+
+	engine, err := firestore.NewStore(ctx, "myproject", clock.RealClock{})
+	require.NoError(t, err)
+
+	now := time.Date(2023, 6, 15, 15, 0, 0, 0, time.UTC)
+	want := &store.AuthLockout{
+		Type:          store.AuthLockoutTypeChargeStation,
+		Id:            "cs001",
+		Failures:      0,
+		Lockouts:      1,
+		LastFailureAt: now,
+		LockedUntil:   now.Add(time.Minute),
+	}
+	err = engine.SetAuthLockout(ctx, want)
+	require.NoError(t, err)
+	err = engine.SetAuthLockout(ctx, &store.AuthLockout{
+		Type:          store.AuthLockoutTypeRemoteAddr,
+		Id:            "192.168.1.1",
+		Failures:      2,
+		LastFailureAt: now,
+	})
+	require.NoError(t, err)
+
+	got, err := engine.LookupAuthLockout(ctx, store.AuthLockoutTypeChargeStation, "cs001")
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+
+	list, err := engine.ListAuthLockouts(ctx, now, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, want, list[0])
+
+	err = engine.DeleteAuthLockout(ctx, store.AuthLockoutTypeChargeStation, "cs001")
+	require.NoError(t, err)
+
+	got, err = engine.LookupAuthLockout(ctx, store.AuthLockoutTypeChargeStation, "cs001")
+	require.NoError(t, err)
+	assert.Nil(t, got)
+}
+
+func TestUpdateAuthLockout(t *testing.T) {
+	defer cleanupAllCollections(t, "myproject")
+
+	ctx := context.Background()
+
+	engine, err := firestore.NewStore(ctx, "myproject", clock.RealClock{})
+	require.NoError(t, err)
+
+	now := time.Date(2023, 6, 15, 15, 0, 0, 0, time.UTC)
+	increment := func(lockout *store.AuthLockout) bool {
+		lockout.Failures++
+		lockout.LastFailureAt = now
+		return true
+	}
+
+	for i := 0; i < 2; i++ {
+		_, err = engine.UpdateAuthLockout(ctx, store.AuthLockoutTypeChargeStation, "cs001", increment)
+		require.NoError(t, err)
+	}
+
+	got, err := engine.UpdateAuthLockout(ctx, store.AuthLockoutTypeChargeStation, "cs001", func(lockout *store.AuthLockout) bool {
+		return false
+	})
+	require.NoError(t, err)
+	want := &store.AuthLockout{
+		Type:          store.AuthLockoutTypeChargeStation,
+		Id:            "cs001",
+		Failures:      2,
+		LastFailureAt: now,
+	}
+	assert.Equal(t, want, got)
+
+	got, err = engine.LookupAuthLockout(ctx, store.AuthLockoutTypeChargeStation, "cs001")
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+}
@@ -74,14 +74,23 @@ func TestMain(m *testing.M) {
 }
 
 func cleanupAllCollections(t *testing.T, gcloudProject string) {
+	cleanupCollection(t, gcloudProject, "AuthLockout")
 	cleanupCollection(t, gcloudProject, "Certificate")
+	cleanupCollection(t, gcloudProject, "CertificateRevocation")
 	cleanupCollection(t, gcloudProject, "ChargeStation")
+	cleanupCollection(t, gcloudProject, "ChargeStationDetails")
 	cleanupCollection(t, gcloudProject, "ChargeStationSettings")
 	cleanupCollection(t, gcloudProject, "ChargeStationInstallCertificates")
 	cleanupCollection(t, gcloudProject, "ChargeStationRuntimeDetails")
+	cleanupCollection(t, gcloudProject, "ChargeStationConnection")
+	cleanupCollection(t, gcloudProject, "Contract")
+	cleanupCollection(t, gcloudProject, "IssuedCertificate")
 	cleanupCollection(t, gcloudProject, "Location")
+	cleanupCollection(t, gcloudProject, "OcpiChargingProfileRequest")
+	cleanupCollection(t, gcloudProject, "OcpiDelivery")
 	cleanupCollection(t, gcloudProject, "OcpiParty")
 	cleanupCollection(t, gcloudProject, "OcpiRegistration")
+	cleanupCollection(t, gcloudProject, "Reservation")
 	cleanupCollection(t, gcloudProject, "Token")
 	cleanupCollection(t, gcloudProject, "Transaction")
 }
@@ -3,6 +3,7 @@
 package firestore
 
 import (
+	"cloud.google.com/go/firestore"
 	"context"
 	"errors"
 	"fmt"
@@ -96,3 +97,97 @@ func (s *Store) ListPartyDetailsForRole(context context.Context, role string) ([
 	}
 	return parties, nil
 }
+
+func (s *Store) SetChargingProfileRequest(ctx context.Context, request *store.OcpiChargingProfileRequest) error {
+	reqRef := s.client.Doc(fmt.Sprintf("OcpiChargingProfileRequest/%s:%d:%s", request.ChargeStationId, request.EvseId, request.Type))
+	_, err := reqRef.Set(ctx, request)
+	if err != nil {
+		return fmt.Errorf("setting charging profile request %s:%d:%s: %w", request.ChargeStationId, request.EvseId, request.Type, err)
+	}
+	return nil
+}
+
+func (s *Store) LookupChargingProfileRequest(ctx context.Context, chargeStationId string, evseId int, requestType store.OcpiChargingProfileRequestType) (*store.OcpiChargingProfileRequest, error) {
+	reqRef := s.client.Doc(fmt.Sprintf("OcpiChargingProfileRequest/%s:%d:%s", chargeStationId, evseId, requestType))
+	snap, err := reqRef.Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("lookup charging profile request %s:%d:%s: %w", chargeStationId, evseId, requestType, err)
+	}
+	var request store.OcpiChargingProfileRequest
+	err = snap.DataTo(&request)
+	if err != nil {
+		return nil, fmt.Errorf("map charging profile request %s:%d:%s: %w", chargeStationId, evseId, requestType, err)
+	}
+	return &request, nil
+}
+
+func (s *Store) DeleteChargingProfileRequest(ctx context.Context, chargeStationId string, evseId int, requestType store.OcpiChargingProfileRequestType) error {
+	reqRef := s.client.Doc(fmt.Sprintf("OcpiChargingProfileRequest/%s:%d:%s", chargeStationId, evseId, requestType))
+	_, err := reqRef.Delete(ctx)
+	if err != nil {
+		return fmt.Errorf("delete charging profile request %s:%d:%s: %w", chargeStationId, evseId, requestType, err)
+	}
+	return nil
+}
+
+func (s *Store) SetDelivery(ctx context.Context, delivery *store.OcpiDelivery) error {
+	deliveryRef := s.client.Doc(fmt.Sprintf("OcpiDelivery/%s", delivery.Id))
+	_, err := deliveryRef.Set(ctx, delivery)
+	if err != nil {
+		return fmt.Errorf("setting delivery %s: %w", delivery.Id, err)
+	}
+	return nil
+}
+
+func (s *Store) LookupDelivery(ctx context.Context, deliveryId string) (*store.OcpiDelivery, error) {
+	deliveryRef := s.client.Doc(fmt.Sprintf("OcpiDelivery/%s", deliveryId))
+	snap, err := deliveryRef.Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("lookup delivery %s: %w", deliveryId, err)
+	}
+	var delivery store.OcpiDelivery
+	err = snap.DataTo(&delivery)
+	if err != nil {
+		return nil, fmt.Errorf("map delivery %s: %w", deliveryId, err)
+	}
+	return &delivery, nil
+}
+
+func (s *Store) ListDeliveries(ctx context.Context, deliveryStatus store.OcpiDeliveryStatus, offset int, limit int) ([]*store.OcpiDelivery, error) {
+	var deliveries []*store.OcpiDelivery
+	iter := s.client.Collection("OcpiDelivery").Where("Status", "==", string(deliveryStatus)).
+		OrderBy(firestore.DocumentID, firestore.Asc).Offset(offset).Limit(limit).Documents(ctx)
+	for {
+		doc, err := iter.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("next delivery: %w", err)
+		}
+		var delivery store.OcpiDelivery
+		if err = doc.DataTo(&delivery); err != nil {
+			return nil, fmt.Errorf("map delivery: %w", err)
+		}
+		deliveries = append(deliveries, &delivery)
+	}
+	if deliveries == nil {
+		deliveries = make([]*store.OcpiDelivery, 0)
+	}
+	return deliveries, nil
+}
+
+func (s *Store) DeleteDelivery(ctx context.Context, deliveryId string) error {
+	deliveryRef := s.client.Doc(fmt.Sprintf("OcpiDelivery/%s", deliveryId))
+	_, err := deliveryRef.Delete(ctx)
+	if err != nil {
+		return fmt.Errorf("delete delivery %s: %w", deliveryId, err)
+	}
+	return nil
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/thoughtworks/maeve-csms/manager/store/firestore"
 	"k8s.io/utils/clock"
 	"testing"
+	"time"
 )
 
 func TestSetAndLookupRegistrationDetails(t *testing.T) {
@@ -110,3 +111,91 @@ func TestSetAndListPartyDetails(t *testing.T) {
 	assert.Equal(t, 1, len(got))
 	assert.Equal(t, want, got[0])
 }
+
+func TestSetLookupAndDeleteChargingProfileRequest(t *testing.T) {
+	defer cleanupAllCollections(t, "myproject")
+
+	ctx := context.Background()
+
+	engine, err := firestore.NewStore(ctx, "myproject", clock.RealClock{})
+	require.NoError(t, err)
+
+	want := &store.OcpiChargingProfileRequest{
+		ChargeStationId: "cs001",
+		EvseId:          1,
+		Type:            store.OcpiChargingProfileRequestTypeSet,
+		SessionId:       "session001",
+		CountryCode:     "GB",
+		PartyId:         "EMS",
+		ResponseUrl:     "https://example.com/result/1",
+	}
+
+	err = engine.SetChargingProfileRequest(ctx, want)
+	require.NoError(t, err)
+
+	got, err := engine.LookupChargingProfileRequest(ctx, "cs001", 1, store.OcpiChargingProfileRequestTypeSet)
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+
+	err = engine.DeleteChargingProfileRequest(ctx, "cs001", 1, store.OcpiChargingProfileRequestTypeSet)
+	require.NoError(t, err)
+
+	got, err = engine.LookupChargingProfileRequest(ctx, "cs001", 1, store.OcpiChargingProfileRequestTypeSet)
+	require.NoError(t, err)
+	assert.Nil(t, got)
+}
+
+func TestSetListAndDeleteDeliveries(t *testing.T) {
+	defer cleanupAllCollections(t, "myproject")
+
+	ctx := context.Background()
+
+	engine, err := firestore.NewStore(ctx, "myproject", clock.RealClock{})
+	require.NoError(t, err)
+
+	created := time.Date(2023, 6, 15, 15, 0, 0, 0, time.UTC)
+	pending := &store.OcpiDelivery{
+		Id:          "delivery001",
+		CountryCode: "GB",
+		PartyId:     "EMS",
+		Module:      "locations",
+		Method:      "PUT",
+		Url:         "/GB/TWK/loc001",
+		Body:        `{"id":"loc001"}`,
+		Status:      store.OcpiDeliveryStatusPending,
+		Attempts:    1,
+		LastError:   "status code: 503",
+		NextAttempt: created.Add(30 * time.Second),
+		Created:     created,
+	}
+	failed := &store.OcpiDelivery{
+		Id:          "delivery002",
+		CountryCode: "GB",
+		PartyId:     "EMS",
+		Method:      "POST",
+		Url:         "https://example.com/commands/RESERVE_NOW/1",
+		Status:      store.OcpiDeliveryStatusFailed,
+		NextAttempt: created,
+		Created:     created,
+	}
+
+	err = engine.SetDelivery(ctx, pending)
+	require.NoError(t, err)
+	err = engine.SetDelivery(ctx, failed)
+	require.NoError(t, err)
+
+	got, err := engine.LookupDelivery(ctx, "delivery001")
+	require.NoError(t, err)
+	assert.Equal(t, pending, got)
+
+	list, err := engine.ListDeliveries(ctx, store.OcpiDeliveryStatusFailed, 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []*store.OcpiDelivery{failed}, list)
+
+	err = engine.DeleteDelivery(ctx, "delivery001")
+	require.NoError(t, err)
+
+	got, err = engine.LookupDelivery(ctx, "delivery001")
+	require.NoError(t, err)
+	assert.Nil(t, got)
+}
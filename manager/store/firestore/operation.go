@@ -0,0 +1,88 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package firestore
+
+import (
+	"cloud.google.com/go/firestore"
+	"context"
+	"fmt"
+	"github.com/thoughtworks/maeve-csms/manager/store"
+	"time"
+)
+
+type chargeStationOperation struct {
+	ChargeStationId string    `firestore:"csId"`
+	Timestamp       time.Time `firestore:"ts"`
+	Event           string    `firestore:"event"`
+	Type            string    `firestore:"type,omitempty"`
+	Subject         string    `firestore:"subject,omitempty"`
+	Action          string    `firestore:"action,omitempty"`
+	MessageId       string    `firestore:"msgId,omitempty"`
+	Attempt         int       `firestore:"attempt,omitempty"`
+	RequestedBy     string    `firestore:"requestedBy,omitempty"`
+	Result          string    `firestore:"result,omitempty"`
+	Payload         string    `firestore:"payload,omitempty"`
+}
+
+func chargeStationOperationsRef(s *Store, chargeStationId string) *firestore.CollectionRef {
+	return s.client.Collection(fmt.Sprintf("ChargeStationOperation/%s/Log", chargeStationId))
+}
+
+func (s *Store) AppendChargeStationOperation(ctx context.Context, operation *store.ChargeStationOperation) error {
+	_, err := chargeStationOperationsRef(s, operation.ChargeStationId).NewDoc().Create(ctx, &chargeStationOperation{
+		ChargeStationId: operation.ChargeStationId,
+		Timestamp:       operation.Timestamp,
+		Event:           string(operation.Event),
+		Type:            string(operation.Type),
+		Subject:         operation.Subject,
+		Action:          operation.Action,
+		MessageId:       operation.MessageId,
+		Attempt:         operation.Attempt,
+		RequestedBy:     operation.RequestedBy,
+		Result:          operation.Result,
+		Payload:         operation.Payload,
+	})
+	if err != nil {
+		return fmt.Errorf("append charge station operation %s: %w", operation.ChargeStationId, err)
+	}
+	return nil
+}
+
+func (s *Store) ListChargeStationOperations(ctx context.Context, chargeStationId string, offset, limit int) ([]*store.ChargeStationOperation, error) {
+	snaps, err := chargeStationOperationsRef(s, chargeStationId).OrderBy("ts", firestore.Asc).
+		Offset(offset).Limit(limit).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("list charge station operations %s: %w", chargeStationId, err)
+	}
+	var operations = make([]*store.ChargeStationOperation, 0, len(snaps))
+	for _, snap := range snaps {
+		var data chargeStationOperation
+		if err = snap.DataTo(&data); err != nil {
+			return nil, fmt.Errorf("map charge station operation %s: %w", snap.Ref.ID, err)
+		}
+		operations = append(operations, &store.ChargeStationOperation{
+			ChargeStationId: data.ChargeStationId,
+			Timestamp:       data.Timestamp.UTC(),
+			Event:           store.ChargeStationOperationEvent(data.Event),
+			Type:            store.ChargeStationOperationType(data.Type),
+			Subject:         data.Subject,
+			Action:          data.Action,
+			MessageId:       data.MessageId,
+			Attempt:         data.Attempt,
+			RequestedBy:     data.RequestedBy,
+			Result:          data.Result,
+			Payload:         data.Payload,
+		})
+	}
+	return operations, nil
+}
+
+func (s *Store) DeleteChargeStationOperationsBefore(ctx context.Context, before time.Time) error {
+	// the log for every charge station is in a Log sub-collection, so all the logs can be
+	// pruned with a single collection group query
+	err := s.deleteQueryResults(ctx, s.client.CollectionGroup("Log").Where("ts", "<", before))
+	if err != nil {
+		return fmt.Errorf("delete charge station operations before %s: %w", before.Format(time.RFC3339), err)
+	}
+	return nil
+}
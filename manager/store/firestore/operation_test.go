@@ -0,0 +1,98 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build integration
+
+package firestore_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thoughtworks/maeve-csms/manager/store"
+	"github.com/thoughtworks/maeve-csms/manager/store/firestore"
+	"k8s.io/utils/clock"
+	"testing"
+	"time"
+)
+
+func TestAppendAndListChargeStationOperations(t *testing.T) {
+	defer cleanupCollection(t, "myproject", "ChargeStationOperation/cs001/Log")
+
+	ctx := context.Background()
+
+	engine, err := firestore.NewStore(ctx, "myproject", clock.RealClock{})
+	require.NoError(t, err)
+
+	now := time.Date(2023, 6, 15, 15, 0, 0, 0, time.UTC)
+	want := []*store.ChargeStationOperation{
+		{
+			ChargeStationId: "cs001",
+			Timestamp:       now,
+			Event:           store.ChargeStationOperationRequested,
+			Type:            store.ChargeStationOperationTypeTrigger,
+			Subject:         "BootNotification",
+			RequestedBy:     "admin",
+		},
+		{
+			ChargeStationId: "cs001",
+			Timestamp:       now.Add(time.Second),
+			Event:           store.ChargeStationOperationSent,
+			Action:          "TriggerMessage",
+			MessageId:       "1234",
+			Payload:         `{"requestedMessage":"BootNotification"}`,
+		},
+		{
+			ChargeStationId: "cs001",
+			Timestamp:       now.Add(2 * time.Second),
+			Event:           store.ChargeStationOperationResponded,
+			Action:          "TriggerMessage",
+			MessageId:       "1234",
+			Result:          "Accepted",
+		},
+	}
+	// append out of order to check that the log is ordered by timestamp
+	for _, i := range []int{1, 0, 2} {
+		err = engine.AppendChargeStationOperation(ctx, want[i])
+		require.NoError(t, err)
+	}
+
+	got, err := engine.ListChargeStationOperations(ctx, "cs001", 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+
+	got, err = engine.ListChargeStationOperations(ctx, "cs001", 2, 10)
+	require.NoError(t, err)
+	assert.Equal(t, want[2:], got)
+}
+
+func TestDeleteChargeStationOperationsBefore(t *testing.T) {
+	defer cleanupCollection(t, "myproject", "ChargeStationOperation/cs001/Log")
+	defer cleanupCollection(t, "myproject", "ChargeStationOperation/cs002/Log")
+
+	ctx := context.Background()
+
+	engine, err := firestore.NewStore(ctx, "myproject", clock.RealClock{})
+	require.NoError(t, err)
+
+	now := time.Date(2023, 6, 15, 15, 0, 0, 0, time.UTC)
+	for _, op := range []*store.ChargeStationOperation{
+		{ChargeStationId: "cs001", Timestamp: now.Add(-2 * time.Hour), Event: store.ChargeStationOperationSent, Action: "Reset"},
+		{ChargeStationId: "cs001", Timestamp: now, Event: store.ChargeStationOperationSent, Action: "TriggerMessage"},
+		{ChargeStationId: "cs002", Timestamp: now.Add(-time.Hour - time.Second), Event: store.ChargeStationOperationSent, Action: "Reset"},
+	} {
+		err = engine.AppendChargeStationOperation(ctx, op)
+		require.NoError(t, err)
+	}
+
+	err = engine.DeleteChargeStationOperationsBefore(ctx, now.Add(-time.Hour))
+	require.NoError(t, err)
+
+	got, err := engine.ListChargeStationOperations(ctx, "cs001", 0, 10)
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, "TriggerMessage", got[0].Action)
+
+	got, err = engine.ListChargeStationOperations(ctx, "cs002", 0, 10)
+	require.NoError(t, err)
+	assert.Empty(t, got)
+}
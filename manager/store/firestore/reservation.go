@@ -0,0 +1,125 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package firestore
+
+import (
+	"cloud.google.com/go/firestore"
+	"context"
+	"fmt"
+	"github.com/thoughtworks/maeve-csms/manager/store"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"time"
+)
+
+type reservation struct {
+	ReservationId     int       `firestore:"id"`
+	ChargeStationId   string    `firestore:"chargeStationId"`
+	EvseId            *int      `firestore:"evseId"`
+	IdToken           string    `firestore:"idToken"`
+	TokenType         string    `firestore:"tokenType"`
+	ExpiryDate        time.Time `firestore:"expiryDate"`
+	Status            string    `firestore:"status"`
+	OcpiReservationId string    `firestore:"ocpiReservationId"`
+	CountryCode       string    `firestore:"countryCode"`
+	PartyId           string    `firestore:"partyId"`
+	ResponseUrl       string    `firestore:"responseUrl"`
+}
+
+func (s *Store) SetReservation(ctx context.Context, res *store.Reservation) error {
+	resRef := s.client.Doc(fmt.Sprintf("Reservation/%d", res.ReservationId))
+	_, err := resRef.Set(ctx, &reservation{
+		ReservationId:     res.ReservationId,
+		ChargeStationId:   res.ChargeStationId,
+		EvseId:            res.EvseId,
+		IdToken:           res.IdToken,
+		TokenType:         res.TokenType,
+		ExpiryDate:        res.ExpiryDate,
+		Status:            string(res.Status),
+		OcpiReservationId: res.OcpiReservationId,
+		CountryCode:       res.CountryCode,
+		PartyId:           res.PartyId,
+		ResponseUrl:       res.ResponseUrl,
+	})
+	if err != nil {
+		return fmt.Errorf("setting reservation %d: %w", res.ReservationId, err)
+	}
+	return nil
+}
+
+func (s *Store) LookupReservation(ctx context.Context, reservationId int) (*store.Reservation, error) {
+	resRef := s.client.Doc(fmt.Sprintf("Reservation/%d", reservationId))
+	snap, err := resRef.Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("lookup reservation %d: %w", reservationId, err)
+	}
+	var resData reservation
+	if err = snap.DataTo(&resData); err != nil {
+		return nil, fmt.Errorf("map reservation %d: %w", reservationId, err)
+	}
+	return toStoreReservation(&resData), nil
+}
+
+func (s *Store) FindReservationByOcpiId(ctx context.Context, countryCode, partyId, ocpiReservationId string) (*store.Reservation, error) {
+	snaps, err := s.client.Collection("Reservation").
+		Where("countryCode", "==", countryCode).
+		Where("partyId", "==", partyId).
+		Where("ocpiReservationId", "==", ocpiReservationId).
+		Limit(1).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("lookup reservation %s:%s:%s: %w", countryCode, partyId, ocpiReservationId, err)
+	}
+	if len(snaps) == 0 {
+		return nil, nil
+	}
+	var resData reservation
+	if err = snaps[0].DataTo(&resData); err != nil {
+		return nil, fmt.Errorf("map reservation %s:%s:%s: %w", countryCode, partyId, ocpiReservationId, err)
+	}
+	return toStoreReservation(&resData), nil
+}
+
+func (s *Store) ListReservations(ctx context.Context, pageSize int, previousReservationId int) ([]*store.Reservation, error) {
+	snaps, err := s.client.Collection("Reservation").OrderBy("id", firestore.Asc).
+		StartAfter(previousReservationId).Limit(pageSize).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("list reservations: %w", err)
+	}
+	var reservations []*store.Reservation
+	for _, snap := range snaps {
+		var resData reservation
+		if err = snap.DataTo(&resData); err != nil {
+			return nil, fmt.Errorf("map reservation: %w", err)
+		}
+		reservations = append(reservations, toStoreReservation(&resData))
+	}
+	return reservations, nil
+}
+
+func (s *Store) DeleteReservation(ctx context.Context, reservationId int) error {
+	resRef := s.client.Doc(fmt.Sprintf("Reservation/%d", reservationId))
+	_, err := resRef.Delete(ctx)
+	if err != nil {
+		return fmt.Errorf("delete reservation %d: %w", reservationId, err)
+	}
+	return nil
+}
+
+func toStoreReservation(res *reservation) *store.Reservation {
+	return &store.Reservation{
+		ReservationId:     res.ReservationId,
+		ChargeStationId:   res.ChargeStationId,
+		EvseId:            res.EvseId,
+		IdToken:           res.IdToken,
+		TokenType:         res.TokenType,
+		ExpiryDate:        res.ExpiryDate.UTC(),
+		Status:            store.ReservationStatus(res.Status),
+		OcpiReservationId: res.OcpiReservationId,
+		CountryCode:       res.CountryCode,
+		PartyId:           res.PartyId,
+		ResponseUrl:       res.ResponseUrl,
+	}
+}
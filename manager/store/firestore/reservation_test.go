@@ -0,0 +1,110 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build integration
+
+package firestore_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thoughtworks/maeve-csms/manager/store"
+	"github.com/thoughtworks/maeve-csms/manager/store/firestore"
+	"k8s.io/utils/clock"
+	"testing"
+	"time"
+)
+
+func TestSetAndLookupReservation(t *testing.T) {
+	defer cleanupAllCollections(t, "myproject")
+
+	ctx := context.Background()
+
+	engine, err := firestore.NewStore(ctx, "myproject", clock.RealClock{})
+	require.NoError(t, err)
+
+	want := &store.Reservation{
+		ReservationId:     1234,
+		ChargeStationId:   "cs001",
+		EvseId:            makePtr(1),
+		IdToken:           "DEADBEEF",
+		TokenType:         "RFID",
+		ExpiryDate:        time.Date(2023, 6, 15, 15, 5, 0, 0, time.UTC),
+		Status:            store.ReservationStatusPending,
+		OcpiReservationId: "res001",
+		CountryCode:       "GB",
+		PartyId:           "TWK",
+		ResponseUrl:       "https://example.com/commands/RESERVE_NOW/res001",
+	}
+
+	err = engine.SetReservation(ctx, want)
+	require.NoError(t, err)
+
+	got, err := engine.LookupReservation(ctx, 1234)
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+
+	got, err = engine.FindReservationByOcpiId(ctx, "GB", "TWK", "res001")
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+
+	got, err = engine.FindReservationByOcpiId(ctx, "GB", "XXX", "res001")
+	require.NoError(t, err)
+	assert.Nil(t, got)
+}
+
+func TestDeleteReservation(t *testing.T) {
+	defer cleanupAllCollections(t, "myproject")
+
+	ctx := context.Background()
+
+	engine, err := firestore.NewStore(ctx, "myproject", clock.RealClock{})
+	require.NoError(t, err)
+
+	err = engine.SetReservation(ctx, &store.Reservation{
+		ReservationId:   1234,
+		ChargeStationId: "cs001",
+		Status:          store.ReservationStatusAccepted,
+	})
+	require.NoError(t, err)
+
+	err = engine.DeleteReservation(ctx, 1234)
+	require.NoError(t, err)
+
+	got, err := engine.LookupReservation(ctx, 1234)
+	require.NoError(t, err)
+	assert.Nil(t, got)
+}
+
+func TestListReservationsReturnsDataInPages(t *testing.T) {
+	defer cleanupAllCollections(t, "myproject")
+
+	ctx := context.Background()
+
+	engine, err := firestore.NewStore(ctx, "myproject", clock.RealClock{})
+	require.NoError(t, err)
+
+	for i := 1; i <= 25; i++ {
+		err := engine.SetReservation(ctx, &store.Reservation{
+			ReservationId:   i,
+			ChargeStationId: "cs001",
+			Status:          store.ReservationStatusAccepted,
+		})
+		require.NoError(t, err)
+	}
+
+	page1, err := engine.ListReservations(ctx, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, page1, 10)
+	assert.Equal(t, 1, page1[0].ReservationId)
+
+	page2, err := engine.ListReservations(ctx, 10, page1[9].ReservationId)
+	require.NoError(t, err)
+	require.Len(t, page2, 10)
+	assert.Equal(t, 11, page2[0].ReservationId)
+
+	page3, err := engine.ListReservations(ctx, 10, page2[9].ReservationId)
+	require.NoError(t, err)
+	require.Len(t, page3, 5)
+	assert.Equal(t, 21, page3[0].ReservationId)
+}
@@ -58,6 +58,23 @@ func (s *Store) FindTransaction(ctx context.Context, chargeStationId, transactio
 	return &transaction, nil
 }
 
+func (s *Store) FindTransactionById(ctx context.Context, transactionId string) (*store.Transaction, error) {
+	snaps, err := s.client.Collection("Transaction").Where("transactionId", "==", transactionId).Limit(1).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("lookup transaction %s: %w", transactionId, err)
+	}
+	if len(snaps) == 0 {
+		return nil, nil
+	}
+
+	var transaction store.Transaction
+	if err = snaps[0].DataTo(&transaction); err != nil {
+		return nil, fmt.Errorf("map transaction %s: %w", transactionId, err)
+	}
+
+	return &transaction, nil
+}
+
 func (s *Store) Transactions(ctx context.Context) ([]*store.Transaction, error) {
 	transactionRefs, err := s.client.Collection("Transaction").Documents(ctx).GetAll()
 	if err != nil {
@@ -120,6 +137,23 @@ func (s *Store) EndTransaction(ctx context.Context, chargeStationId, transaction
 	return s.updateTransaction(ctx, chargeStationId, transactionId, transaction)
 }
 
+func (s *Store) SetTransactionEvseId(ctx context.Context, chargeStationId, transactionId string, evseId int) error {
+	transaction, err := s.FindTransaction(ctx, chargeStationId, transactionId)
+	if err != nil {
+		return fmt.Errorf("getting transaction: %w", err)
+	}
+
+	if transaction == nil {
+		transaction = &store.Transaction{
+			ChargeStationId: chargeStationId,
+			TransactionId:   transactionId,
+		}
+	}
+	transaction.EvseId = evseId
+
+	return s.updateTransaction(ctx, chargeStationId, transactionId, transaction)
+}
+
 func (s *Store) updateTransaction(ctx context.Context, chargeStationId, transactionId string, transaction *store.Transaction) error {
 	transactionRef := s.client.Doc(getPath(chargeStationId, transactionId))
 	_, err := transactionRef.Set(ctx, transaction)
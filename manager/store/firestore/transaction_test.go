@@ -241,3 +241,27 @@ func TestTransactionStoreEndNonExistingTransaction(t *testing.T) {
 
 	assert.Equal(t, want, got)
 }
+
+func TestFindTransactionById(t *testing.T) {
+	defer cleanupAllCollections(t, "myproject")
+
+	ctx := context.Background()
+
+	transactionStore, err := firestore.NewStore(ctx, "myproject", clock.RealClock{})
+	require.NoError(t, err)
+
+	err = transactionStore.CreateTransaction(ctx, "cs001", "1234", idToken, tokenType, nil, 0, false)
+	require.NoError(t, err)
+	err = transactionStore.SetTransactionEvseId(ctx, "cs001", "1234", 2)
+	require.NoError(t, err)
+
+	got, err := transactionStore.FindTransactionById(ctx, "1234")
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, "cs001", got.ChargeStationId)
+	assert.Equal(t, 2, got.EvseId)
+
+	got, err = transactionStore.FindTransactionById(ctx, "unknown")
+	require.NoError(t, err)
+	assert.Nil(t, got)
+}
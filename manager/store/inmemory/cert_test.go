@@ -14,6 +14,7 @@ import (
 	"encoding/pem"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/thoughtworks/maeve-csms/manager/store"
 	"github.com/thoughtworks/maeve-csms/manager/store/inmemory"
 	"k8s.io/utils/clock"
 	"math/big"
@@ -80,3 +81,70 @@ func generateCertificate(t *testing.T) *x509.Certificate {
 
 	return cert
 }
+
+func TestSetLookupAndListIssuedCertificates(t *testing.T) {
+	ctx := context.Background()
+	engine := inmemory.NewStore(clock.RealClock{})
+	var err error
+
+	issuedAt := time.Date(2023, 6, 15, 15, 0, 0, 0, time.UTC)
+	certificates := []*store.IssuedCertificate{
+		{CertificateId: "cert001", ChargeStationId: "cs001", CertificateType: store.CertificateTypeChargeStation, SerialNumber: "1", IssuedAt: issuedAt, NotAfter: issuedAt.Add(48 * time.Hour), Superseded: true},
+		{CertificateId: "cert002", ChargeStationId: "cs001", CertificateType: store.CertificateTypeChargeStation, SerialNumber: "2", IssuedAt: issuedAt.Add(time.Hour), NotAfter: issuedAt.Add(72 * time.Hour)},
+		{CertificateId: "cert003", ChargeStationId: "cs002", CertificateType: store.CertificateTypeEVCC, SerialNumber: "3", IssuedAt: issuedAt, NotAfter: issuedAt.Add(24 * time.Hour)},
+		{CertificateId: "cert004", ChargeStationId: "cs002", CertificateType: store.CertificateTypeChargeStation, SerialNumber: "4", IssuedAt: issuedAt, NotAfter: issuedAt.Add(365 * 24 * time.Hour)},
+	}
+	for _, certificate := range certificates {
+		err = engine.SetIssuedCertificate(ctx, certificate)
+		require.NoError(t, err)
+	}
+
+	got, err := engine.LookupIssuedCertificate(ctx, "cert002")
+	require.NoError(t, err)
+	assert.Equal(t, certificates[1], got)
+
+	got, err = engine.LookupIssuedCertificate(ctx, "unknown")
+	require.NoError(t, err)
+	assert.Nil(t, got)
+
+	forChargeStation, err := engine.ListIssuedCertificatesForChargeStation(ctx, "cs001")
+	require.NoError(t, err)
+	assert.Equal(t, certificates[:2], forChargeStation)
+
+	expiring, err := engine.ListExpiringIssuedCertificates(ctx, issuedAt.Add(30*24*time.Hour), 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []*store.IssuedCertificate{certificates[2], certificates[1]}, expiring)
+
+	expiring, err = engine.ListExpiringIssuedCertificates(ctx, issuedAt.Add(30*24*time.Hour), 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []*store.IssuedCertificate{certificates[1]}, expiring)
+}
+
+func TestSetLookupListAndDeleteRootCertificates(t *testing.T) {
+	ctx := context.Background()
+	engine := inmemory.NewStore(clock.RealClock{})
+
+	certificates := []*store.RootCertificate{
+		{CertificateId: "root001", CertificateType: store.CertificateTypeV2G, CertificateData: "v2g-pem-data"},
+		{CertificateId: "root002", CertificateType: store.CertificateTypeMO, CertificateData: "mo-pem-data", Revoked: true},
+	}
+	for _, certificate := range certificates {
+		err := engine.SetRootCertificate(ctx, certificate)
+		require.NoError(t, err)
+	}
+
+	got, err := engine.LookupRootCertificate(ctx, "root002")
+	require.NoError(t, err)
+	assert.Equal(t, certificates[1], got)
+
+	all, err := engine.ListRootCertificates(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, certificates, all)
+
+	err = engine.DeleteRootCertificate(ctx, "root001")
+	require.NoError(t, err)
+
+	got, err = engine.LookupRootCertificate(ctx, "root001")
+	require.NoError(t, err)
+	assert.Nil(t, got)
+}
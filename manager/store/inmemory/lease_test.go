@@ -0,0 +1,73 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package inmemory_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thoughtworks/maeve-csms/manager/store/inmemory"
+	clockTest "k8s.io/utils/clock/testing"
+	"testing"
+	"time"
+)
+
+func TestAcquireLeaseHeldByAnotherHolder(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2023, 6, 15, 15, 0, 0, 0, time.UTC)
+	clock := clockTest.NewFakePassiveClock(now)
+	engine := inmemory.NewStore(clock)
+
+	acquired, err := engine.AcquireLease(ctx, "sync-settings", "replica1", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, acquired)
+
+	acquired, err = engine.AcquireLease(ctx, "sync-settings", "replica2", time.Minute)
+	require.NoError(t, err)
+	assert.False(t, acquired)
+
+	// the lease is renewed by its holder
+	clock.SetTime(now.Add(30 * time.Second))
+	acquired, err = engine.AcquireLease(ctx, "sync-settings", "replica1", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, acquired)
+
+	clock.SetTime(now.Add(80 * time.Second))
+	acquired, err = engine.AcquireLease(ctx, "sync-settings", "replica2", time.Minute)
+	require.NoError(t, err)
+	assert.False(t, acquired)
+
+	// once it expires another holder can acquire it
+	clock.SetTime(now.Add(91 * time.Second))
+	acquired, err = engine.AcquireLease(ctx, "sync-settings", "replica2", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, acquired)
+
+	got, err := engine.LookupLease(ctx, "sync-settings")
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, "replica2", got.Holder)
+	assert.Equal(t, now.Add(151*time.Second), got.ExpiresAt)
+}
+
+func TestReleaseLease(t *testing.T) {
+	ctx := context.Background()
+	engine := inmemory.NewStore(clockTest.NewFakePassiveClock(time.Now()))
+
+	acquired, err := engine.AcquireLease(ctx, "sync-settings", "replica1", time.Minute)
+	require.NoError(t, err)
+	require.True(t, acquired)
+
+	// only the holder can release the lease
+	err = engine.ReleaseLease(ctx, "sync-settings", "replica2")
+	require.NoError(t, err)
+	got, err := engine.LookupLease(ctx, "sync-settings")
+	require.NoError(t, err)
+	assert.NotNil(t, got)
+
+	err = engine.ReleaseLease(ctx, "sync-settings", "replica1")
+	require.NoError(t, err)
+	got, err = engine.LookupLease(ctx, "sync-settings")
+	require.NoError(t, err)
+	assert.Nil(t, got)
+}
@@ -0,0 +1,78 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package inmemory_test
+
+import (
+	"context"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thoughtworks/maeve-csms/manager/store"
+	"github.com/thoughtworks/maeve-csms/manager/store/inmemory"
+	"k8s.io/utils/clock"
+	"testing"
+	"time"
+)
+
+func TestSetAndLookupDelivery(t *testing.T) {
+	engine := inmemory.NewStore(clock.RealClock{})
+	ctx := context.Background()
+
+	want := &store.OcpiDelivery{
+		Id:          "delivery001",
+		CountryCode: "GB",
+		PartyId:     "EMS",
+		Module:      "locations",
+		Method:      "PUT",
+		Url:         "/GB/TWK/loc001",
+		Body:        `{"id":"loc001"}`,
+		Status:      store.OcpiDeliveryStatusPending,
+		NextAttempt: time.Date(2023, 6, 15, 15, 0, 0, 0, time.UTC),
+		Created:     time.Date(2023, 6, 15, 15, 0, 0, 0, time.UTC),
+	}
+
+	err := engine.SetDelivery(ctx, want)
+	require.NoError(t, err)
+
+	got, err := engine.LookupDelivery(ctx, "delivery001")
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+
+	err = engine.DeleteDelivery(ctx, "delivery001")
+	require.NoError(t, err)
+
+	got, err = engine.LookupDelivery(ctx, "delivery001")
+	require.NoError(t, err)
+	assert.Nil(t, got)
+}
+
+func TestListDeliveriesFiltersByStatus(t *testing.T) {
+	engine := inmemory.NewStore(clock.RealClock{})
+	ctx := context.Background()
+
+	for i := 0; i < 15; i++ {
+		status := store.OcpiDeliveryStatusPending
+		if i%3 == 0 {
+			status = store.OcpiDeliveryStatusFailed
+		}
+		err := engine.SetDelivery(ctx, &store.OcpiDelivery{
+			Id:     fmt.Sprintf("delivery%03d", i),
+			Status: status,
+		})
+		require.NoError(t, err)
+	}
+
+	failed, err := engine.ListDeliveries(ctx, store.OcpiDeliveryStatusFailed, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, failed, 5)
+	assert.Equal(t, "delivery000", failed[0].Id)
+
+	pending, err := engine.ListDeliveries(ctx, store.OcpiDeliveryStatusPending, 5, 10)
+	require.NoError(t, err)
+	require.Len(t, pending, 5)
+	assert.Equal(t, "delivery008", pending[0].Id)
+
+	none, err := engine.ListDeliveries(ctx, store.OcpiDeliveryStatusPending, 20, 10)
+	require.NoError(t, err)
+	assert.Empty(t, none)
+}
@@ -0,0 +1,84 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package inmemory_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thoughtworks/maeve-csms/manager/store"
+	"github.com/thoughtworks/maeve-csms/manager/store/inmemory"
+	"k8s.io/utils/clock"
+	"testing"
+	"time"
+)
+
+func TestAppendAndListChargeStationOperations(t *testing.T) {
+	ctx := context.Background()
+	engine := inmemory.NewStore(clock.RealClock{})
+
+	now := time.Date(2023, 6, 15, 15, 0, 0, 0, time.UTC)
+	var want []*store.ChargeStationOperation
+	for i, event := range []store.ChargeStationOperationEvent{
+		store.ChargeStationOperationRequested,
+		store.ChargeStationOperationAttempted,
+		store.ChargeStationOperationSent,
+	} {
+		op := &store.ChargeStationOperation{
+			ChargeStationId: "cs001",
+			Timestamp:       now.Add(time.Duration(i) * time.Second),
+			Event:           event,
+			Type:            store.ChargeStationOperationTypeSetting,
+			Subject:         "HeartbeatInterval",
+		}
+		err := engine.AppendChargeStationOperation(ctx, op)
+		require.NoError(t, err)
+		want = append(want, op)
+	}
+	err := engine.AppendChargeStationOperation(ctx, &store.ChargeStationOperation{
+		ChargeStationId: "cs002",
+		Timestamp:       now,
+		Event:           store.ChargeStationOperationSent,
+		Action:          "Reset",
+	})
+	require.NoError(t, err)
+
+	got, err := engine.ListChargeStationOperations(ctx, "cs001", 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+
+	got, err = engine.ListChargeStationOperations(ctx, "cs001", 1, 1)
+	require.NoError(t, err)
+	assert.Equal(t, want[1:2], got)
+
+	got, err = engine.ListChargeStationOperations(ctx, "cs001", 3, 10)
+	require.NoError(t, err)
+	assert.Empty(t, got)
+}
+
+func TestDeleteChargeStationOperationsBefore(t *testing.T) {
+	ctx := context.Background()
+	engine := inmemory.NewStore(clock.RealClock{})
+
+	now := time.Date(2023, 6, 15, 15, 0, 0, 0, time.UTC)
+	for _, op := range []*store.ChargeStationOperation{
+		{ChargeStationId: "cs001", Timestamp: now.Add(-2 * time.Hour), Event: store.ChargeStationOperationSent, Action: "Reset"},
+		{ChargeStationId: "cs001", Timestamp: now, Event: store.ChargeStationOperationSent, Action: "TriggerMessage"},
+		{ChargeStationId: "cs002", Timestamp: now.Add(-time.Hour - time.Second), Event: store.ChargeStationOperationSent, Action: "Reset"},
+	} {
+		err := engine.AppendChargeStationOperation(ctx, op)
+		require.NoError(t, err)
+	}
+
+	err := engine.DeleteChargeStationOperationsBefore(ctx, now.Add(-time.Hour))
+	require.NoError(t, err)
+
+	got, err := engine.ListChargeStationOperations(ctx, "cs001", 0, 10)
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, "TriggerMessage", got[0].Action)
+
+	got, err = engine.ListChargeStationOperations(ctx, "cs002", 0, 10)
+	require.NoError(t, err)
+	assert.Empty(t, got)
+}
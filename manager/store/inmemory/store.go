@@ -27,32 +27,62 @@ type Store struct {
 	sync.Mutex
 	clock                            clock.PassiveClock
 	chargeStationAuth                map[string]*store.ChargeStationAuth
+	chargeStationDetails             map[string]*store.ChargeStationDetails
 	chargeStationSettings            map[string]*store.ChargeStationSettings
 	chargeStationInstallCertificates map[string]*store.ChargeStationInstallCertificates
+	chargeStationInstalledCerts      map[string]*store.ChargeStationInstalledCertificates
 	chargeStationRuntimeDetails      map[string]*store.ChargeStationRuntimeDetails
-	chargeStationTriggerMessage      map[string]*store.ChargeStationTriggerMessage
+	chargeStationTriggerMessages     map[string]*store.ChargeStationTriggerMessages
+	chargeStationConnections         map[string]*store.ChargeStationConnection
 	tokens                           map[string]*store.Token
 	transactions                     map[string]*store.Transaction
 	certificates                     map[string]string
+	certificateRevocations           map[string]*store.CertificateRevocation
+	issuedCertificates               map[string]*store.IssuedCertificate
+	rootCertificates                 map[string]*store.RootCertificate
+	certificateCampaigns             map[string]*store.CertificateCampaign
+	certificateCampaignStations      map[string]map[string]*store.CertificateCampaignStation
+	contracts                        map[string]*store.Contract
 	registrations                    map[string]*store.OcpiRegistration
 	partyDetails                     map[string]*store.OcpiParty
+	chargingProfileRequests          map[string]*store.OcpiChargingProfileRequest
 	locations                        map[string]*store.Location
+	reservations                     map[int]*store.Reservation
+	deliveries                       map[string]*store.OcpiDelivery
+	authLockouts                     map[string]*store.AuthLockout
+	leases                           map[string]*store.Lease
+	chargeStationOperations          map[string][]*store.ChargeStationOperation
 }
 
 func NewStore(clock clock.PassiveClock) *Store {
 	return &Store{
 		clock:                            clock,
 		chargeStationAuth:                make(map[string]*store.ChargeStationAuth),
+		chargeStationDetails:             make(map[string]*store.ChargeStationDetails),
 		chargeStationSettings:            make(map[string]*store.ChargeStationSettings),
 		chargeStationInstallCertificates: make(map[string]*store.ChargeStationInstallCertificates),
+		chargeStationInstalledCerts:      make(map[string]*store.ChargeStationInstalledCertificates),
 		chargeStationRuntimeDetails:      make(map[string]*store.ChargeStationRuntimeDetails),
-		chargeStationTriggerMessage:      make(map[string]*store.ChargeStationTriggerMessage),
+		chargeStationTriggerMessages:     make(map[string]*store.ChargeStationTriggerMessages),
+		chargeStationConnections:         make(map[string]*store.ChargeStationConnection),
 		tokens:                           make(map[string]*store.Token),
 		transactions:                     make(map[string]*store.Transaction),
 		certificates:                     make(map[string]string),
+		certificateRevocations:           make(map[string]*store.CertificateRevocation),
+		issuedCertificates:               make(map[string]*store.IssuedCertificate),
+		rootCertificates:                 make(map[string]*store.RootCertificate),
+		certificateCampaigns:             make(map[string]*store.CertificateCampaign),
+		certificateCampaignStations:      make(map[string]map[string]*store.CertificateCampaignStation),
+		contracts:                        make(map[string]*store.Contract),
 		registrations:                    make(map[string]*store.OcpiRegistration),
 		partyDetails:                     make(map[string]*store.OcpiParty),
+		chargingProfileRequests:          make(map[string]*store.OcpiChargingProfileRequest),
 		locations:                        make(map[string]*store.Location),
+		reservations:                     make(map[int]*store.Reservation),
+		deliveries:                       make(map[string]*store.OcpiDelivery),
+		authLockouts:                     make(map[string]*store.AuthLockout),
+		leases:                           make(map[string]*store.Lease),
+		chargeStationOperations:          make(map[string][]*store.ChargeStationOperation),
 	}
 }
 
@@ -69,6 +99,117 @@ func (s *Store) LookupChargeStationAuth(_ context.Context, chargeStationId strin
 	return s.chargeStationAuth[chargeStationId], nil
 }
 
+func (s *Store) SetChargeStationDetails(_ context.Context, chargeStationId string, details *store.ChargeStationDetails) error {
+	s.Lock()
+	defer s.Unlock()
+	d := *details
+	d.ChargeStationId = chargeStationId
+	d.Tags = slices.Clone(details.Tags)
+	s.chargeStationDetails[chargeStationId] = &d
+	return nil
+}
+
+func (s *Store) LookupChargeStationDetails(_ context.Context, chargeStationId string) (*store.ChargeStationDetails, error) {
+	s.Lock()
+	defer s.Unlock()
+	return s.chargeStationDetails[chargeStationId], nil
+}
+
+func (s *Store) ListChargeStationDetails(_ context.Context, filter store.ChargeStationFilter, offset, limit int) ([]*store.ChargeStationDetails, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	keys := maps.Keys(s.chargeStationDetails)
+	sort.Strings(keys)
+
+	var matching []*store.ChargeStationDetails
+	for _, key := range keys {
+		details := s.chargeStationDetails[key]
+		if (filter.Vendor == "" || details.Vendor == filter.Vendor) &&
+			(filter.Model == "" || details.Model == filter.Model) &&
+			(filter.FirmwareVersion == "" || details.FirmwareVersion == filter.FirmwareVersion) &&
+			(filter.Tag == "" || slices.Contains(details.Tags, filter.Tag)) {
+			matching = append(matching, details)
+		}
+	}
+
+	if offset >= len(matching) {
+		return []*store.ChargeStationDetails{}, nil
+	}
+	return matching[offset:int(math.Min(float64(offset+limit), float64(len(matching))))], nil
+}
+
+func (s *Store) DeleteChargeStation(_ context.Context, chargeStationId string) error {
+	s.Lock()
+	defer s.Unlock()
+	delete(s.chargeStationAuth, chargeStationId)
+	delete(s.chargeStationDetails, chargeStationId)
+	delete(s.chargeStationSettings, chargeStationId)
+	delete(s.chargeStationInstallCertificates, chargeStationId)
+	delete(s.chargeStationInstalledCerts, chargeStationId)
+	delete(s.chargeStationTriggerMessages, chargeStationId)
+	delete(s.chargeStationRuntimeDetails, chargeStationId)
+	delete(s.chargeStationConnections, chargeStationId)
+	delete(s.chargeStationOperations, chargeStationId)
+	delete(s.authLockouts, authLockoutKey(store.AuthLockoutTypeChargeStation, chargeStationId))
+	maps.DeleteFunc(s.issuedCertificates, func(_ string, certificate *store.IssuedCertificate) bool {
+		return certificate.ChargeStationId == chargeStationId
+	})
+	maps.DeleteFunc(s.reservations, func(_ int, reservation *store.Reservation) bool {
+		return reservation.ChargeStationId == chargeStationId
+	})
+	return nil
+}
+
+func (s *Store) BackfillChargeStationDetails(_ context.Context) (int, error) {
+	s.Lock()
+	defer s.Unlock()
+	created := 0
+	for chargeStationId := range s.chargeStationAuth {
+		if _, ok := s.chargeStationDetails[chargeStationId]; !ok {
+			s.chargeStationDetails[chargeStationId] = &store.ChargeStationDetails{ChargeStationId: chargeStationId}
+			created++
+		}
+	}
+	return created, nil
+}
+
+func (s *Store) SetChargeStationConnection(_ context.Context, chargeStationId string, connection *store.ChargeStationConnection) error {
+	s.Lock()
+	defer s.Unlock()
+	c := *connection
+	c.ChargeStationId = chargeStationId
+	s.chargeStationConnections[chargeStationId] = &c
+	return nil
+}
+
+func (s *Store) LookupChargeStationConnection(_ context.Context, chargeStationId string) (*store.ChargeStationConnection, error) {
+	s.Lock()
+	defer s.Unlock()
+	return s.chargeStationConnections[chargeStationId], nil
+}
+
+func (s *Store) ListConnectedChargeStations(_ context.Context, offset, limit int) ([]*store.ChargeStationConnection, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	keys := maps.Keys(s.chargeStationConnections)
+	sort.Strings(keys)
+
+	var connected []*store.ChargeStationConnection
+	for _, key := range keys {
+		connection := s.chargeStationConnections[key]
+		if connection.Connected {
+			connected = append(connected, connection)
+		}
+	}
+
+	if offset >= len(connected) {
+		return []*store.ChargeStationConnection{}, nil
+	}
+	return connected[offset:int(math.Min(float64(offset+limit), float64(len(connected))))], nil
+}
+
 func (s *Store) UpdateChargeStationSettings(_ context.Context, chargeStationId string, settings *store.ChargeStationSettings) error {
 	s.Lock()
 	defer s.Unlock()
@@ -141,6 +282,8 @@ func (s *Store) UpdateChargeStationInstallCertificates(_ context.Context, charge
 					c.CertificateData = v.CertificateData
 					c.CertificateInstallationStatus = v.CertificateInstallationStatus
 					c.CertificateType = v.CertificateType
+					c.SendAfter = v.SendAfter
+					c.Attempts = v.Attempts
 					matched = true
 					break
 				}
@@ -183,6 +326,69 @@ func (s *Store) ListChargeStationInstallCertificates(_ context.Context, pageSize
 	return installCertificates, nil
 }
 
+func (s *Store) SetChargeStationInstalledCertificates(_ context.Context, chargeStationId string, certificates *store.ChargeStationInstalledCertificates) error {
+	s.Lock()
+	defer s.Unlock()
+	s.chargeStationInstalledCerts[chargeStationId] = copyInstalledCertificates(chargeStationId, certificates)
+	return nil
+}
+
+func (s *Store) LookupChargeStationInstalledCertificates(_ context.Context, chargeStationId string) (*store.ChargeStationInstalledCertificates, error) {
+	s.Lock()
+	defer s.Unlock()
+	certificates := s.chargeStationInstalledCerts[chargeStationId]
+	if certificates == nil {
+		return nil, nil
+	}
+	return copyInstalledCertificates(chargeStationId, certificates), nil
+}
+
+func (s *Store) UpdateChargeStationInstalledCertificates(_ context.Context, chargeStationId string, update func(installed *store.ChargeStationInstalledCertificates) bool) (*store.ChargeStationInstalledCertificates, error) {
+	s.Lock()
+	defer s.Unlock()
+	installed := &store.ChargeStationInstalledCertificates{ChargeStationId: chargeStationId}
+	if certificates := s.chargeStationInstalledCerts[chargeStationId]; certificates != nil {
+		installed = copyInstalledCertificates(chargeStationId, certificates)
+	}
+	if update(installed) {
+		s.chargeStationInstalledCerts[chargeStationId] = copyInstalledCertificates(chargeStationId, installed)
+	}
+	return installed, nil
+}
+
+func (s *Store) ListChargeStationInstalledCertificates(_ context.Context, pageSize int, previousChargeStationId string) ([]*store.ChargeStationInstalledCertificates, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	keys := maps.Keys(s.chargeStationInstalledCerts)
+	sort.Strings(keys)
+
+	i, found := slices.BinarySearch(keys, previousChargeStationId)
+	if !found {
+		i = 0
+	} else {
+		i++
+	}
+
+	var installedCertificates []*store.ChargeStationInstalledCertificates
+	max := int(math.Min(float64(i+pageSize), float64(len(keys))))
+	for _, k := range keys[i:max] {
+		installedCertificates = append(installedCertificates, copyInstalledCertificates(k, s.chargeStationInstalledCerts[k]))
+	}
+	return installedCertificates, nil
+}
+
+func copyInstalledCertificates(chargeStationId string, certificates *store.ChargeStationInstalledCertificates) *store.ChargeStationInstalledCertificates {
+	c := *certificates
+	c.ChargeStationId = chargeStationId
+	c.Certificates = make([]*store.ChargeStationInstalledCertificate, len(certificates.Certificates))
+	for i, certificate := range certificates.Certificates {
+		cert := *certificate
+		c.Certificates[i] = &cert
+	}
+	return &c
+}
+
 func (s *Store) SetChargeStationRuntimeDetails(_ context.Context, chargeStationId string, details *store.ChargeStationRuntimeDetails) error {
 	s.Lock()
 	defer s.Unlock()
@@ -196,31 +402,61 @@ func (s *Store) LookupChargeStationRuntimeDetails(_ context.Context, chargeStati
 	return s.chargeStationRuntimeDetails[chargeStationId], nil
 }
 
-func (s *Store) SetChargeStationTriggerMessage(ctx context.Context, chargeStationId string, triggerMessage *store.ChargeStationTriggerMessage) error {
+func (s *Store) UpdateChargeStationTriggerMessages(_ context.Context, chargeStationId string, triggerMessages *store.ChargeStationTriggerMessages) error {
 	s.Lock()
 	defer s.Unlock()
-	s.chargeStationTriggerMessage[chargeStationId] = triggerMessage
+	queue := s.chargeStationTriggerMessages[chargeStationId]
+	if queue == nil {
+		queue = &store.ChargeStationTriggerMessages{
+			ChargeStationId: chargeStationId,
+		}
+	}
+	for _, v := range triggerMessages.TriggerMessages {
+		triggerMessage := *v
+		triggerMessage.ChargeStationId = chargeStationId
+		matched := false
+		for i, t := range queue.TriggerMessages {
+			if t.SameTarget(&triggerMessage) {
+				queue.TriggerMessages[i] = &triggerMessage
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			queue.TriggerMessages = append(queue.TriggerMessages, &triggerMessage)
+		}
+	}
+	s.chargeStationTriggerMessages[chargeStationId] = queue
 	return nil
 }
 
-func (s *Store) DeleteChargeStationTriggerMessage(ctx context.Context, chargeStationId string) error {
+func (s *Store) DeleteChargeStationTriggerMessage(_ context.Context, chargeStationId string, triggerMessage *store.ChargeStationTriggerMessage) error {
 	s.Lock()
 	defer s.Unlock()
-	delete(s.chargeStationTriggerMessage, chargeStationId)
+	queue := s.chargeStationTriggerMessages[chargeStationId]
+	if queue == nil {
+		return nil
+	}
+	queue.TriggerMessages = slices.DeleteFunc(queue.TriggerMessages, func(t *store.ChargeStationTriggerMessage) bool {
+		return t.SameTarget(triggerMessage)
+	})
+	if len(queue.TriggerMessages) == 0 {
+		delete(s.chargeStationTriggerMessages, chargeStationId)
+	}
 	return nil
 }
 
-func (s *Store) LookupChargeStationTriggerMessage(ctx context.Context, chargeStationId string) (*store.ChargeStationTriggerMessage, error) {
+func (s *Store) LookupChargeStationTriggerMessages(_ context.Context, chargeStationId string) (*store.ChargeStationTriggerMessages, error) {
 	s.Lock()
 	defer s.Unlock()
-	return s.chargeStationTriggerMessage[chargeStationId], nil
+	return cloneChargeStationTriggerMessages(s.chargeStationTriggerMessages[chargeStationId]), nil
 }
 
-func (s *Store) ListChargeStationTriggerMessages(ctx context.Context, pageSize int, previousChargeStationId string) ([]*store.ChargeStationTriggerMessage, error) {
+func (s *Store) ListChargeStationTriggerMessages(_ context.Context, pageSize int, previousChargeStationId string) ([]*store.ChargeStationTriggerMessages, error) {
 	s.Lock()
 	defer s.Unlock()
 
-	keys := maps.Keys(s.chargeStationTriggerMessage)
+	keys := maps.Keys(s.chargeStationTriggerMessages)
 	sort.Strings(keys)
 
 	i, found := slices.BinarySearch(keys, previousChargeStationId)
@@ -230,14 +466,28 @@ func (s *Store) ListChargeStationTriggerMessages(ctx context.Context, pageSize i
 		i++
 	}
 
-	var triggerMessages []*store.ChargeStationTriggerMessage
+	var triggerMessages []*store.ChargeStationTriggerMessages
 	max := int(math.Min(float64(i+pageSize), float64(len(keys))))
 	for _, k := range keys[i:max] {
-		triggerMessages = append(triggerMessages, s.chargeStationTriggerMessage[k])
+		triggerMessages = append(triggerMessages, cloneChargeStationTriggerMessages(s.chargeStationTriggerMessages[k]))
 	}
 	return triggerMessages, nil
 }
 
+func cloneChargeStationTriggerMessages(queue *store.ChargeStationTriggerMessages) *store.ChargeStationTriggerMessages {
+	if queue == nil {
+		return nil
+	}
+	clone := &store.ChargeStationTriggerMessages{
+		ChargeStationId: queue.ChargeStationId,
+	}
+	for _, t := range queue.TriggerMessages {
+		triggerMessage := *t
+		clone.TriggerMessages = append(clone.TriggerMessages, &triggerMessage)
+	}
+	return clone
+}
+
 func (s *Store) SetToken(_ context.Context, token *store.Token) error {
 	s.Lock()
 	defer s.Unlock()
@@ -302,6 +552,17 @@ func (s *Store) FindTransaction(_ context.Context, chargeStationId, transactionI
 	return s.getTransaction(chargeStationId, transactionId), nil
 }
 
+func (s *Store) FindTransactionById(_ context.Context, transactionId string) (*store.Transaction, error) {
+	s.Lock()
+	defer s.Unlock()
+	for _, transaction := range s.transactions {
+		if transaction.TransactionId == transactionId {
+			return transaction, nil
+		}
+	}
+	return nil, nil
+}
+
 func (s *Store) CreateTransaction(_ context.Context, chargeStationId, transactionId, idToken, tokenType string, meterValues []store.MeterValue, seqNo int, offline bool) error {
 	s.Lock()
 	defer s.Unlock()
@@ -370,6 +631,21 @@ func (s *Store) EndTransaction(_ context.Context, chargeStationId, transactionId
 	return nil
 }
 
+func (s *Store) SetTransactionEvseId(_ context.Context, chargeStationId, transactionId string, evseId int) error {
+	s.Lock()
+	defer s.Unlock()
+	transaction := s.getTransaction(chargeStationId, transactionId)
+	if transaction == nil {
+		transaction = &store.Transaction{
+			ChargeStationId: chargeStationId,
+			TransactionId:   transactionId,
+		}
+		s.updateTransaction(transaction)
+	}
+	transaction.EvseId = evseId
+	return nil
+}
+
 func (s *Store) SetCertificate(_ context.Context, pemCertificate string) error {
 	s.Lock()
 	defer s.Unlock()
@@ -422,6 +698,167 @@ func (s *Store) DeleteCertificate(_ context.Context, certificateHash string) err
 	return nil
 }
 
+func (s *Store) SetCertificateRevocation(_ context.Context, revocation *store.CertificateRevocation) error {
+	s.Lock()
+	defer s.Unlock()
+	r := *revocation
+	s.certificateRevocations[revocation.CertificateHash] = &r
+	return nil
+}
+
+func (s *Store) LookupCertificateRevocation(_ context.Context, certificateHash string) (*store.CertificateRevocation, error) {
+	s.Lock()
+	defer s.Unlock()
+	revocation := s.certificateRevocations[certificateHash]
+	if revocation == nil {
+		return nil, nil
+	}
+	r := *revocation
+	return &r, nil
+}
+
+func (s *Store) DeleteCertificateRevocation(_ context.Context, certificateHash string) error {
+	s.Lock()
+	defer s.Unlock()
+	delete(s.certificateRevocations, certificateHash)
+	return nil
+}
+
+func (s *Store) SetIssuedCertificate(_ context.Context, certificate *store.IssuedCertificate) error {
+	s.Lock()
+	defer s.Unlock()
+	c := *certificate
+	s.issuedCertificates[certificate.CertificateId] = &c
+	return nil
+}
+
+func (s *Store) LookupIssuedCertificate(_ context.Context, certificateId string) (*store.IssuedCertificate, error) {
+	s.Lock()
+	defer s.Unlock()
+	certificate := s.issuedCertificates[certificateId]
+	if certificate == nil {
+		return nil, nil
+	}
+	c := *certificate
+	return &c, nil
+}
+
+func (s *Store) ListIssuedCertificatesForChargeStation(_ context.Context, chargeStationId string) ([]*store.IssuedCertificate, error) {
+	s.Lock()
+	defer s.Unlock()
+	var certificates []*store.IssuedCertificate
+	for _, certificate := range s.issuedCertificates {
+		if certificate.ChargeStationId == chargeStationId {
+			c := *certificate
+			certificates = append(certificates, &c)
+		}
+	}
+	sort.Slice(certificates, func(i, j int) bool {
+		return certificates[i].IssuedAt.Before(certificates[j].IssuedAt)
+	})
+	return certificates, nil
+}
+
+func (s *Store) ListExpiringIssuedCertificates(_ context.Context, expiresBefore time.Time, offset, limit int) ([]*store.IssuedCertificate, error) {
+	s.Lock()
+	defer s.Unlock()
+	var expiring []*store.IssuedCertificate
+	for _, certificate := range s.issuedCertificates {
+		if !certificate.Superseded && certificate.NotAfter.Before(expiresBefore) {
+			c := *certificate
+			expiring = append(expiring, &c)
+		}
+	}
+	sort.Slice(expiring, func(i, j int) bool {
+		if expiring[i].NotAfter.Equal(expiring[j].NotAfter) {
+			return expiring[i].CertificateId < expiring[j].CertificateId
+		}
+		return expiring[i].NotAfter.Before(expiring[j].NotAfter)
+	})
+	if offset >= len(expiring) {
+		return []*store.IssuedCertificate{}, nil
+	}
+	return expiring[offset:int(math.Min(float64(offset+limit), float64(len(expiring))))], nil
+}
+
+func (s *Store) SetRootCertificate(_ context.Context, certificate *store.RootCertificate) error {
+	s.Lock()
+	defer s.Unlock()
+	c := *certificate
+	s.rootCertificates[certificate.CertificateId] = &c
+	return nil
+}
+
+func (s *Store) LookupRootCertificate(_ context.Context, certificateId string) (*store.RootCertificate, error) {
+	s.Lock()
+	defer s.Unlock()
+	certificate := s.rootCertificates[certificateId]
+	if certificate == nil {
+		return nil, nil
+	}
+	c := *certificate
+	return &c, nil
+}
+
+func (s *Store) ListRootCertificates(_ context.Context) ([]*store.RootCertificate, error) {
+	s.Lock()
+	defer s.Unlock()
+	var certificates = make([]*store.RootCertificate, 0, len(s.rootCertificates))
+	for _, certificate := range s.rootCertificates {
+		c := *certificate
+		certificates = append(certificates, &c)
+	}
+	sort.Slice(certificates, func(i, j int) bool {
+		return certificates[i].CertificateId < certificates[j].CertificateId
+	})
+	return certificates, nil
+}
+
+func (s *Store) DeleteRootCertificate(_ context.Context, certificateId string) error {
+	s.Lock()
+	defer s.Unlock()
+	delete(s.rootCertificates, certificateId)
+	return nil
+}
+
+func (s *Store) SetContract(_ context.Context, contract *store.Contract) error {
+	s.Lock()
+	defer s.Unlock()
+	c := *contract
+	s.contracts[contract.EMAID] = &c
+	return nil
+}
+
+func (s *Store) LookupContract(_ context.Context, emaid string) (*store.Contract, error) {
+	s.Lock()
+	defer s.Unlock()
+	contract := s.contracts[emaid]
+	if contract == nil {
+		return nil, nil
+	}
+	c := *contract
+	return &c, nil
+}
+
+func (s *Store) LookupContractByProvisioningCertificateId(_ context.Context, provisioningCertificateId string) (*store.Contract, error) {
+	s.Lock()
+	defer s.Unlock()
+	for _, contract := range s.contracts {
+		if contract.ProvisioningCertificateId == provisioningCertificateId {
+			c := *contract
+			return &c, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *Store) DeleteContract(_ context.Context, emaid string) error {
+	s.Lock()
+	defer s.Unlock()
+	delete(s.contracts, emaid)
+	return nil
+}
+
 func (s *Store) SetRegistrationDetails(_ context.Context, token string, registration *store.OcpiRegistration) error {
 	s.Lock()
 	defer s.Unlock()
@@ -481,6 +918,82 @@ func (s *Store) ListPartyDetailsForRole(_ context.Context, role string) ([]*stor
 	return parties, nil
 }
 
+func chargingProfileRequestKey(chargeStationId string, evseId int, requestType store.OcpiChargingProfileRequestType) string {
+	return fmt.Sprintf("%s:%d:%s", chargeStationId, evseId, requestType)
+}
+
+func (s *Store) SetChargingProfileRequest(_ context.Context, request *store.OcpiChargingProfileRequest) error {
+	s.Lock()
+	defer s.Unlock()
+
+	s.chargingProfileRequests[chargingProfileRequestKey(request.ChargeStationId, request.EvseId, request.Type)] = request
+
+	return nil
+}
+
+func (s *Store) LookupChargingProfileRequest(_ context.Context, chargeStationId string, evseId int, requestType store.OcpiChargingProfileRequestType) (*store.OcpiChargingProfileRequest, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	return s.chargingProfileRequests[chargingProfileRequestKey(chargeStationId, evseId, requestType)], nil
+}
+
+func (s *Store) DeleteChargingProfileRequest(_ context.Context, chargeStationId string, evseId int, requestType store.OcpiChargingProfileRequestType) error {
+	s.Lock()
+	defer s.Unlock()
+
+	delete(s.chargingProfileRequests, chargingProfileRequestKey(chargeStationId, evseId, requestType))
+
+	return nil
+}
+
+func (s *Store) SetDelivery(_ context.Context, delivery *store.OcpiDelivery) error {
+	s.Lock()
+	defer s.Unlock()
+
+	s.deliveries[delivery.Id] = delivery
+
+	return nil
+}
+
+func (s *Store) LookupDelivery(_ context.Context, deliveryId string) (*store.OcpiDelivery, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	return s.deliveries[deliveryId], nil
+}
+
+func (s *Store) ListDeliveries(_ context.Context, status store.OcpiDeliveryStatus, offset int, limit int) ([]*store.OcpiDelivery, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	var matching []*store.OcpiDelivery
+	for _, delivery := range s.deliveries {
+		if delivery.Status == status {
+			matching = append(matching, delivery)
+		}
+	}
+	sort.Slice(matching, func(i, j int) bool {
+		return matching[i].Id < matching[j].Id
+	})
+
+	deliveries := make([]*store.OcpiDelivery, 0)
+	if offset < len(matching) {
+		max := int(math.Min(float64(offset+limit), float64(len(matching))))
+		deliveries = append(deliveries, matching[offset:max]...)
+	}
+	return deliveries, nil
+}
+
+func (s *Store) DeleteDelivery(_ context.Context, deliveryId string) error {
+	s.Lock()
+	defer s.Unlock()
+
+	delete(s.deliveries, deliveryId)
+
+	return nil
+}
+
 func (s *Store) SetLocation(_ context.Context, location *store.Location) error {
 	s.Lock()
 	defer s.Unlock()
@@ -513,3 +1026,329 @@ func (s *Store) ListLocations(_ context.Context, offset int, limit int) ([]*stor
 	}
 	return locations, nil
 }
+
+func (s *Store) SetReservation(_ context.Context, reservation *store.Reservation) error {
+	s.Lock()
+	defer s.Unlock()
+
+	s.reservations[reservation.ReservationId] = reservation
+
+	return nil
+}
+
+func (s *Store) LookupReservation(_ context.Context, reservationId int) (*store.Reservation, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	return s.reservations[reservationId], nil
+}
+
+func (s *Store) FindReservationByOcpiId(_ context.Context, countryCode, partyId, ocpiReservationId string) (*store.Reservation, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	for _, reservation := range s.reservations {
+		if reservation.CountryCode == countryCode && reservation.PartyId == partyId && reservation.OcpiReservationId == ocpiReservationId {
+			return reservation, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *Store) ListReservations(_ context.Context, pageSize int, previousReservationId int) ([]*store.Reservation, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	keys := maps.Keys(s.reservations)
+	sort.Ints(keys)
+
+	i, found := slices.BinarySearch(keys, previousReservationId)
+	if found {
+		i++
+	}
+
+	var reservations []*store.Reservation
+	max := int(math.Min(float64(i+pageSize), float64(len(keys))))
+	for _, k := range keys[i:max] {
+		reservations = append(reservations, s.reservations[k])
+	}
+	return reservations, nil
+}
+
+func (s *Store) DeleteReservation(_ context.Context, reservationId int) error {
+	s.Lock()
+	defer s.Unlock()
+
+	delete(s.reservations, reservationId)
+
+	return nil
+}
+
+func authLockoutKey(lockoutType store.AuthLockoutType, id string) string {
+	return fmt.Sprintf("%s/%s", lockoutType, id)
+}
+
+func (s *Store) SetAuthLockout(_ context.Context, lockout *store.AuthLockout) error {
+	s.Lock()
+	defer s.Unlock()
+	l := *lockout
+	s.authLockouts[authLockoutKey(lockout.Type, lockout.Id)] = &l
+	return nil
+}
+
+func (s *Store) LookupAuthLockout(_ context.Context, lockoutType store.AuthLockoutType, id string) (*store.AuthLockout, error) {
+	s.Lock()
+	defer s.Unlock()
+	lockout := s.authLockouts[authLockoutKey(lockoutType, id)]
+	if lockout == nil {
+		return nil, nil
+	}
+	l := *lockout
+	return &l, nil
+}
+
+func (s *Store) UpdateAuthLockout(_ context.Context, lockoutType store.AuthLockoutType, id string, update func(lockout *store.AuthLockout) bool) (*store.AuthLockout, error) {
+	s.Lock()
+	defer s.Unlock()
+	l := store.AuthLockout{Type: lockoutType, Id: id}
+	if lockout := s.authLockouts[authLockoutKey(lockoutType, id)]; lockout != nil {
+		l = *lockout
+	}
+	if update(&l) {
+		updated := l
+		s.authLockouts[authLockoutKey(lockoutType, id)] = &updated
+	}
+	return &l, nil
+}
+
+func (s *Store) ListAuthLockouts(_ context.Context, lockedAt time.Time, offset, limit int) ([]*store.AuthLockout, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	keys := maps.Keys(s.authLockouts)
+	sort.Strings(keys)
+
+	var lockouts []*store.AuthLockout
+	for _, key := range keys {
+		lockout := s.authLockouts[key]
+		if lockout.LockedUntil.After(lockedAt) {
+			l := *lockout
+			lockouts = append(lockouts, &l)
+		}
+	}
+
+	if offset >= len(lockouts) {
+		return []*store.AuthLockout{}, nil
+	}
+	return lockouts[offset:int(math.Min(float64(offset+limit), float64(len(lockouts))))], nil
+}
+
+func (s *Store) DeleteAuthLockout(_ context.Context, lockoutType store.AuthLockoutType, id string) error {
+	s.Lock()
+	defer s.Unlock()
+	delete(s.authLockouts, authLockoutKey(lockoutType, id))
+	return nil
+}
+
+func (s *Store) CreateCertificateCampaign(_ context.Context, campaign *store.CertificateCampaign, stations []*store.CertificateCampaignStation) error {
+	s.Lock()
+	defer s.Unlock()
+	if s.certificateCampaigns[campaign.CampaignId] != nil {
+		return fmt.Errorf("certificate campaign %s already exists", campaign.CampaignId)
+	}
+	campaign.StationCounts = store.CountCertificateCampaignStations(stations)
+	s.certificateCampaigns[campaign.CampaignId] = copyCertificateCampaign(campaign)
+	campaignStations := make(map[string]*store.CertificateCampaignStation)
+	for _, station := range stations {
+		st := *station
+		campaignStations[station.ChargeStationId] = &st
+	}
+	s.certificateCampaignStations[campaign.CampaignId] = campaignStations
+	return nil
+}
+
+func (s *Store) LookupCertificateCampaign(_ context.Context, campaignId string) (*store.CertificateCampaign, error) {
+	s.Lock()
+	defer s.Unlock()
+	campaign := s.certificateCampaigns[campaignId]
+	if campaign == nil {
+		return nil, nil
+	}
+	return copyCertificateCampaign(campaign), nil
+}
+
+func (s *Store) ListCertificateCampaigns(_ context.Context, status store.CertificateCampaignStatus, offset, limit int) ([]*store.CertificateCampaign, error) {
+	s.Lock()
+	defer s.Unlock()
+	var campaigns []*store.CertificateCampaign
+	for _, campaign := range s.certificateCampaigns {
+		if status == "" || campaign.Status == status {
+			campaigns = append(campaigns, campaign)
+		}
+	}
+	sort.Slice(campaigns, func(i, j int) bool {
+		if campaigns[i].CreatedAt.Equal(campaigns[j].CreatedAt) {
+			return campaigns[i].CampaignId < campaigns[j].CampaignId
+		}
+		return campaigns[i].CreatedAt.Before(campaigns[j].CreatedAt)
+	})
+	if offset >= len(campaigns) {
+		return []*store.CertificateCampaign{}, nil
+	}
+	campaigns = campaigns[offset:int(math.Min(float64(offset+limit), float64(len(campaigns))))]
+
+	var result = make([]*store.CertificateCampaign, len(campaigns))
+	for i, campaign := range campaigns {
+		result[i] = copyCertificateCampaign(campaign)
+	}
+	return result, nil
+}
+
+func (s *Store) UpdateCertificateCampaign(_ context.Context, campaignId string, update func(campaign *store.CertificateCampaign) bool) (*store.CertificateCampaign, error) {
+	s.Lock()
+	defer s.Unlock()
+	campaign := s.certificateCampaigns[campaignId]
+	if campaign == nil {
+		return nil, nil
+	}
+	updated := copyCertificateCampaign(campaign)
+	if update(updated) {
+		updated.StationCounts = campaign.StationCounts
+		s.certificateCampaigns[campaignId] = copyCertificateCampaign(updated)
+	}
+	return updated, nil
+}
+
+func (s *Store) ListCertificateCampaignStations(_ context.Context, campaignId string, status store.CertificateCampaignStationStatus, offset, limit int) ([]*store.CertificateCampaignStation, error) {
+	s.Lock()
+	defer s.Unlock()
+	campaignStations := s.certificateCampaignStations[campaignId]
+	keys := maps.Keys(campaignStations)
+	sort.Strings(keys)
+
+	var stations []*store.CertificateCampaignStation
+	for _, key := range keys {
+		station := campaignStations[key]
+		if status == "" || station.Status == status {
+			st := *station
+			stations = append(stations, &st)
+		}
+	}
+	if offset >= len(stations) {
+		return []*store.CertificateCampaignStation{}, nil
+	}
+	return stations[offset:int(math.Min(float64(offset+limit), float64(len(stations))))], nil
+}
+
+func (s *Store) UpdateCertificateCampaignStation(_ context.Context, campaignId, chargeStationId string, update func(campaign *store.CertificateCampaign, station *store.CertificateCampaignStation) bool) (*store.CertificateCampaignStation, error) {
+	s.Lock()
+	defer s.Unlock()
+	campaign := s.certificateCampaigns[campaignId]
+	station := s.certificateCampaignStations[campaignId][chargeStationId]
+	if campaign == nil || station == nil {
+		return nil, nil
+	}
+	updated := *station
+	if update(copyCertificateCampaign(campaign), &updated) {
+		campaign.StationCounts[station.Status]--
+		if campaign.StationCounts[station.Status] <= 0 {
+			delete(campaign.StationCounts, station.Status)
+		}
+		campaign.StationCounts[updated.Status]++
+		st := updated
+		s.certificateCampaignStations[campaignId][chargeStationId] = &st
+	}
+	return &updated, nil
+}
+
+func copyCertificateCampaign(campaign *store.CertificateCampaign) *store.CertificateCampaign {
+	c := *campaign
+	c.StationCounts = make(map[store.CertificateCampaignStationStatus]int)
+	for status, count := range campaign.StationCounts {
+		c.StationCounts[status] = count
+	}
+	return &c
+}
+
+func (s *Store) AcquireLease(_ context.Context, name, holder string, duration time.Duration) (bool, error) {
+	s.Lock()
+	defer s.Unlock()
+	now := s.clock.Now()
+	lease := s.leases[name]
+	if lease != nil && lease.Holder != holder && lease.ExpiresAt.After(now) {
+		return false, nil
+	}
+	s.leases[name] = &store.Lease{
+		Name:      name,
+		Holder:    holder,
+		ExpiresAt: now.Add(duration),
+	}
+	return true, nil
+}
+
+func (s *Store) ReleaseLease(_ context.Context, name, holder string) error {
+	s.Lock()
+	defer s.Unlock()
+	lease := s.leases[name]
+	if lease != nil && lease.Holder == holder {
+		delete(s.leases, name)
+	}
+	return nil
+}
+
+func (s *Store) LookupLease(_ context.Context, name string) (*store.Lease, error) {
+	s.Lock()
+	defer s.Unlock()
+	lease := s.leases[name]
+	if lease == nil {
+		return nil, nil
+	}
+	l := *lease
+	return &l, nil
+}
+
+func (s *Store) AppendChargeStationOperation(_ context.Context, operation *store.ChargeStationOperation) error {
+	s.Lock()
+	defer s.Unlock()
+	op := *operation
+	s.chargeStationOperations[operation.ChargeStationId] = append(s.chargeStationOperations[operation.ChargeStationId], &op)
+	return nil
+}
+
+func (s *Store) ListChargeStationOperations(_ context.Context, chargeStationId string, offset, limit int) ([]*store.ChargeStationOperation, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	operations := s.chargeStationOperations[chargeStationId]
+	if offset >= len(operations) {
+		return []*store.ChargeStationOperation{}, nil
+	}
+	operations = operations[offset:int(math.Min(float64(offset+limit), float64(len(operations))))]
+
+	var result = make([]*store.ChargeStationOperation, len(operations))
+	for i, operation := range operations {
+		op := *operation
+		result[i] = &op
+	}
+	return result, nil
+}
+
+func (s *Store) DeleteChargeStationOperationsBefore(_ context.Context, before time.Time) error {
+	s.Lock()
+	defer s.Unlock()
+
+	for chargeStationId, operations := range s.chargeStationOperations {
+		var retained []*store.ChargeStationOperation
+		for _, operation := range operations {
+			if !operation.Timestamp.Before(before) {
+				retained = append(retained, operation)
+			}
+		}
+		if len(retained) == 0 {
+			delete(s.chargeStationOperations, chargeStationId)
+		} else {
+			s.chargeStationOperations[chargeStationId] = retained
+		}
+	}
+	return nil
+}
@@ -214,3 +214,321 @@ func TestUpdateChargeStationCertificateWithNewCertificate(t *testing.T) {
 	assert.Equal(t, "evcc-pem-data", got.Certificates[1].CertificateData)
 	assert.Equal(t, store.CertificateInstallationPending, got.Certificates[1].CertificateInstallationStatus)
 }
+
+func TestSetLookupAndListChargeStationInstalledCertificates(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now().UTC()
+	engine := inmemory.NewStore(clock.RealClock{})
+
+	want := &store.ChargeStationInstalledCertificates{
+		Certificates: []*store.ChargeStationInstalledCertificate{
+			{
+				CertificateType: store.CertificateTypeV2G,
+				HashAlgorithm:   "SHA256",
+				IssuerNameHash:  "name-hash",
+				IssuerKeyHash:   "key-hash",
+				SerialNumber:    "01",
+				DeletionStatus:  store.CertificateDeletionPending,
+				SendAfter:       now,
+			},
+		},
+		RequestedAt: now.Add(-time.Minute),
+		ReportedAt:  now,
+	}
+	for i := 0; i < 15; i++ {
+		err := engine.SetChargeStationInstalledCertificates(ctx, fmt.Sprintf("cs%03d", i), want)
+		require.NoError(t, err)
+	}
+
+	got, err := engine.LookupChargeStationInstalledCertificates(ctx, "cs001")
+	require.NoError(t, err)
+	assert.Equal(t, "cs001", got.ChargeStationId)
+	assert.Equal(t, want.Certificates, got.Certificates)
+	assert.Equal(t, want.RequestedAt, got.RequestedAt)
+	assert.Equal(t, want.ReportedAt, got.ReportedAt)
+
+	got, err = engine.LookupChargeStationInstalledCertificates(ctx, "unknown")
+	require.NoError(t, err)
+	assert.Nil(t, got)
+
+	page1, err := engine.ListChargeStationInstalledCertificates(ctx, 10, "")
+	require.NoError(t, err)
+	require.Len(t, page1, 10)
+	page2, err := engine.ListChargeStationInstalledCertificates(ctx, 10, page1[len(page1)-1].ChargeStationId)
+	require.NoError(t, err)
+	require.Len(t, page2, 5)
+	assert.Equal(t, "cs014", page2[4].ChargeStationId)
+}
+
+func TestUpdateChargeStationInstalledCertificates(t *testing.T) {
+	ctx := context.Background()
+	engine := inmemory.NewStore(clock.RealClock{})
+
+	now := time.Date(2023, 6, 15, 15, 0, 0, 0, time.UTC)
+	certificate := &store.ChargeStationInstalledCertificate{
+		CertificateType: store.CertificateTypeV2G,
+		HashAlgorithm:   "SHA256",
+		IssuerNameHash:  "name-hash",
+		IssuerKeyHash:   "key-hash",
+		SerialNumber:    "01",
+	}
+
+	// a charge station without installed certificates starts with an empty inventory
+	got, err := engine.UpdateChargeStationInstalledCertificates(ctx, "cs001", func(installed *store.ChargeStationInstalledCertificates) bool {
+		assert.Equal(t, "cs001", installed.ChargeStationId)
+		assert.Empty(t, installed.Certificates)
+		installed.Certificates = append(installed.Certificates, certificate)
+		installed.ReportedAt = now
+		return true
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []*store.ChargeStationInstalledCertificate{certificate}, got.Certificates)
+
+	_, err = engine.UpdateChargeStationInstalledCertificates(ctx, "cs001", func(installed *store.ChargeStationInstalledCertificates) bool {
+		installed.Certificates[0].DeletionStatus = store.CertificateDeletionPending
+		installed.Certificates[0].Attempts = 1
+		return true
+	})
+	require.NoError(t, err)
+
+	// an update that reports no change is not written
+	_, err = engine.UpdateChargeStationInstalledCertificates(ctx, "cs001", func(installed *store.ChargeStationInstalledCertificates) bool {
+		installed.Certificates = nil
+		return false
+	})
+	require.NoError(t, err)
+
+	got, err = engine.LookupChargeStationInstalledCertificates(ctx, "cs001")
+	require.NoError(t, err)
+	require.Len(t, got.Certificates, 1)
+	assert.Equal(t, store.CertificateDeletionPending, got.Certificates[0].DeletionStatus)
+	assert.Equal(t, 1, got.Certificates[0].Attempts)
+	assert.Equal(t, now, got.ReportedAt.UTC())
+
+	got, err = engine.LookupChargeStationInstalledCertificates(ctx, "cs002")
+	require.NoError(t, err)
+	assert.Nil(t, got)
+}
+
+func TestUpdateLookupAndDeleteChargeStationTriggerMessages(t *testing.T) {
+	ctx := context.Background()
+	engine := inmemory.NewStore(clock.RealClock{})
+	var err error
+
+	evseId, connectorId := 1, 2
+	err = engine.UpdateChargeStationTriggerMessages(ctx, "cs001", &store.ChargeStationTriggerMessages{
+		TriggerMessages: []*store.ChargeStationTriggerMessage{
+			{TriggerMessage: store.TriggerMessageBootNotification, TriggerStatus: store.TriggerStatusPending},
+			{TriggerMessage: store.TriggerMessageStatusNotification, EvseId: &evseId, ConnectorId: &connectorId, TriggerStatus: store.TriggerStatusPending},
+		},
+	})
+	require.NoError(t, err)
+
+	// a trigger for the same target replaces the queued trigger, others are appended
+	err = engine.UpdateChargeStationTriggerMessages(ctx, "cs001", &store.ChargeStationTriggerMessages{
+		TriggerMessages: []*store.ChargeStationTriggerMessage{
+			{TriggerMessage: store.TriggerMessageBootNotification, TriggerStatus: store.TriggerStatusRejected, Attempts: 1},
+			{TriggerMessage: store.TriggerMessageStatusNotification, TriggerStatus: store.TriggerStatusPending},
+		},
+	})
+	require.NoError(t, err)
+
+	want := &store.ChargeStationTriggerMessages{
+		ChargeStationId: "cs001",
+		TriggerMessages: []*store.ChargeStationTriggerMessage{
+			{ChargeStationId: "cs001", TriggerMessage: store.TriggerMessageBootNotification, TriggerStatus: store.TriggerStatusRejected, Attempts: 1},
+			{ChargeStationId: "cs001", TriggerMessage: store.TriggerMessageStatusNotification, EvseId: &evseId, ConnectorId: &connectorId, TriggerStatus: store.TriggerStatusPending},
+			{ChargeStationId: "cs001", TriggerMessage: store.TriggerMessageStatusNotification, TriggerStatus: store.TriggerStatusPending},
+		},
+	}
+
+	got, err := engine.LookupChargeStationTriggerMessages(ctx, "cs001")
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+
+	list, err := engine.ListChargeStationTriggerMessages(ctx, 10, "")
+	require.NoError(t, err)
+	assert.Equal(t, []*store.ChargeStationTriggerMessages{want}, list)
+
+	err = engine.DeleteChargeStationTriggerMessage(ctx, "cs001", &store.ChargeStationTriggerMessage{
+		TriggerMessage: store.TriggerMessageStatusNotification,
+		EvseId:         &evseId,
+		ConnectorId:    &connectorId,
+	})
+	require.NoError(t, err)
+
+	got, err = engine.LookupChargeStationTriggerMessages(ctx, "cs001")
+	require.NoError(t, err)
+	require.Len(t, got.TriggerMessages, 2)
+	assert.Equal(t, store.TriggerMessageBootNotification, got.TriggerMessages[0].TriggerMessage)
+	assert.Nil(t, got.TriggerMessages[1].EvseId)
+
+	for _, triggerMessage := range got.TriggerMessages {
+		err = engine.DeleteChargeStationTriggerMessage(ctx, "cs001", triggerMessage)
+		require.NoError(t, err)
+	}
+
+	got, err = engine.LookupChargeStationTriggerMessages(ctx, "cs001")
+	require.NoError(t, err)
+	assert.Nil(t, got)
+}
+
+func TestListChargeStationDetailsWithFilter(t *testing.T) {
+	ctx := context.Background()
+	engine := inmemory.NewStore(clock.RealClock{})
+
+	for i := 0; i < 3; i++ {
+		firmware := "1.0"
+		if i == 1 {
+			firmware = "2.0"
+		}
+		err := engine.SetChargeStationDetails(ctx, fmt.Sprintf("cs%03d", i), &store.ChargeStationDetails{
+			Vendor:          "Vendor",
+			Model:           "Model",
+			FirmwareVersion: firmware,
+			Tags:            []string{fmt.Sprintf("site%d", i%2), "fleet"},
+		})
+		require.NoError(t, err)
+	}
+
+	got, err := engine.ListChargeStationDetails(ctx, store.ChargeStationFilter{FirmwareVersion: "1.0"}, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.Equal(t, "cs000", got[0].ChargeStationId)
+	assert.Equal(t, "cs002", got[1].ChargeStationId)
+
+	got, err = engine.ListChargeStationDetails(ctx, store.ChargeStationFilter{Vendor: "Vendor"}, 2, 10)
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, "cs002", got[0].ChargeStationId)
+
+	got, err = engine.ListChargeStationDetails(ctx, store.ChargeStationFilter{Tag: "site1"}, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, "cs001", got[0].ChargeStationId)
+	assert.Equal(t, []string{"site1", "fleet"}, got[0].Tags)
+}
+
+func TestDeleteChargeStation(t *testing.T) {
+	ctx := context.Background()
+	engine := inmemory.NewStore(clock.RealClock{})
+
+	err := engine.SetChargeStationAuth(ctx, "cs001", &store.ChargeStationAuth{})
+	require.NoError(t, err)
+	err = engine.SetChargeStationDetails(ctx, "cs001", &store.ChargeStationDetails{})
+	require.NoError(t, err)
+	err = engine.UpdateChargeStationSettings(ctx, "cs001", &store.ChargeStationSettings{
+		Settings: map[string]*store.ChargeStationSetting{"foo": {Value: "bar"}},
+	})
+	require.NoError(t, err)
+	err = engine.UpdateChargeStationTriggerMessages(ctx, "cs001", &store.ChargeStationTriggerMessages{
+		TriggerMessages: []*store.ChargeStationTriggerMessage{
+			{TriggerMessage: store.TriggerMessageBootNotification},
+		},
+	})
+	require.NoError(t, err)
+	err = engine.AppendChargeStationOperation(ctx, &store.ChargeStationOperation{
+		ChargeStationId: "cs001",
+		Timestamp:       time.Now().UTC(),
+		Event:           store.ChargeStationOperationSent,
+		Action:          "Reset",
+	})
+	require.NoError(t, err)
+	err = engine.SetAuthLockout(ctx, &store.AuthLockout{
+		Type:     store.AuthLockoutTypeChargeStation,
+		Id:       "cs001",
+		Failures: 1,
+	})
+	require.NoError(t, err)
+	err = engine.SetIssuedCertificate(ctx, &store.IssuedCertificate{
+		CertificateId:   "cert001",
+		ChargeStationId: "cs001",
+		CertificateType: store.CertificateTypeCSMS,
+	})
+	require.NoError(t, err)
+	for id, csId := range map[int]string{1: "cs001", 2: "cs002"} {
+		err = engine.SetReservation(ctx, &store.Reservation{
+			ReservationId:   id,
+			ChargeStationId: csId,
+			Status:          store.ReservationStatusAccepted,
+		})
+		require.NoError(t, err)
+	}
+
+	err = engine.DeleteChargeStation(ctx, "cs001")
+	require.NoError(t, err)
+
+	auth, err := engine.LookupChargeStationAuth(ctx, "cs001")
+	require.NoError(t, err)
+	assert.Nil(t, auth)
+	details, err := engine.LookupChargeStationDetails(ctx, "cs001")
+	require.NoError(t, err)
+	assert.Nil(t, details)
+	settings, err := engine.LookupChargeStationSettings(ctx, "cs001")
+	require.NoError(t, err)
+	assert.Nil(t, settings)
+	triggers, err := engine.LookupChargeStationTriggerMessages(ctx, "cs001")
+	require.NoError(t, err)
+	assert.Nil(t, triggers)
+
+	operations, err := engine.ListChargeStationOperations(ctx, "cs001", 0, 10)
+	require.NoError(t, err)
+	assert.Len(t, operations, 0)
+	lockout, err := engine.LookupAuthLockout(ctx, store.AuthLockoutTypeChargeStation, "cs001")
+	require.NoError(t, err)
+	assert.Nil(t, lockout)
+	issued, err := engine.LookupIssuedCertificate(ctx, "cert001")
+	require.NoError(t, err)
+	assert.Nil(t, issued)
+	reservation, err := engine.LookupReservation(ctx, 1)
+	require.NoError(t, err)
+	assert.Nil(t, reservation)
+	reservation, err = engine.LookupReservation(ctx, 2)
+	require.NoError(t, err)
+	assert.NotNil(t, reservation)
+}
+
+func TestBackfillChargeStationDetails(t *testing.T) {
+	ctx := context.Background()
+	engine := inmemory.NewStore(clock.RealClock{})
+
+	err := engine.SetChargeStationAuth(ctx, "cs001", &store.ChargeStationAuth{})
+	require.NoError(t, err)
+	err = engine.SetChargeStationAuth(ctx, "cs002", &store.ChargeStationAuth{})
+	require.NoError(t, err)
+	err = engine.SetChargeStationDetails(ctx, "cs002", &store.ChargeStationDetails{Vendor: "Vendor"})
+	require.NoError(t, err)
+
+	created, err := engine.BackfillChargeStationDetails(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, created)
+
+	list, err := engine.ListChargeStationDetails(ctx, store.ChargeStationFilter{}, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, "cs001", list[0].ChargeStationId)
+	assert.Equal(t, "Vendor", list[1].Vendor)
+}
+
+func TestListConnectedChargeStations(t *testing.T) {
+	ctx := context.Background()
+	engine := inmemory.NewStore(clock.RealClock{})
+
+	for _, csId := range []string{"cs003", "cs001", "cs002"} {
+		err := engine.SetChargeStationConnection(ctx, csId, &store.ChargeStationConnection{
+			Connected: csId != "cs002",
+			GatewayId: "gateway1",
+		})
+		require.NoError(t, err)
+	}
+
+	got, err := engine.ListConnectedChargeStations(ctx, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.Equal(t, "cs001", got[0].ChargeStationId)
+	assert.Equal(t, "cs003", got[1].ChargeStationId)
+
+	got, err = engine.ListConnectedChargeStations(ctx, 2, 10)
+	require.NoError(t, err)
+	assert.Len(t, got, 0)
+}
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/thoughtworks/maeve-csms/manager/store"
 	"github.com/thoughtworks/maeve-csms/manager/store/inmemory"
 )
@@ -193,3 +194,24 @@ func TestTransactionStoreEndNonExistingTransaction(t *testing.T) {
 
 	assert.Equal(t, want, got)
 }
+
+func TestFindTransactionById(t *testing.T) {
+	ctx := context.Background()
+
+	transactionStore := inmemory.NewStore(clock.RealClock{})
+
+	err := transactionStore.CreateTransaction(ctx, "cs001", "1234", idToken, tokenType, nil, 0, false)
+	require.NoError(t, err)
+	err = transactionStore.SetTransactionEvseId(ctx, "cs001", "1234", 2)
+	require.NoError(t, err)
+
+	got, err := transactionStore.FindTransactionById(ctx, "1234")
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, "cs001", got.ChargeStationId)
+	assert.Equal(t, 2, got.EvseId)
+
+	got, err = transactionStore.FindTransactionById(ctx, "unknown")
+	require.NoError(t, err)
+	assert.Nil(t, got)
+}
@@ -0,0 +1,27 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package store
+
+import (
+	"context"
+	"time"
+)
+
+// Lease records which manager instance currently holds a named lease. A lease is
+// used to ensure that only one instance performs a task (such as running a sync
+// loop) at a time. The holder must renew the lease before ExpiresAt or another
+// instance may acquire it.
+type Lease struct {
+	Name      string
+	Holder    string
+	ExpiresAt time.Time
+}
+
+type LeaseStore interface {
+	// AcquireLease acquires (or renews) the named lease for the holder for the provided
+	// duration. It returns false if the lease is currently held by another holder.
+	AcquireLease(ctx context.Context, name, holder string, duration time.Duration) (bool, error)
+	// ReleaseLease releases the named lease if it is held by the holder.
+	ReleaseLease(ctx context.Context, name, holder string) error
+	LookupLease(ctx context.Context, name string) (*Lease, error)
+}
@@ -0,0 +1,42 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package store
+
+import (
+	"context"
+	"time"
+)
+
+// AuthLockoutType identifies what is being locked out: either a charge station
+// (by its identifier) or a source address that attempted to authenticate
+type AuthLockoutType string
+
+var (
+	AuthLockoutTypeChargeStation AuthLockoutType = "ChargeStation"
+	AuthLockoutTypeRemoteAddr    AuthLockoutType = "RemoteAddr"
+)
+
+// AuthLockout records the failed basic auth attempts for a charge station or a
+// source address. Once too many attempts have failed authentication is refused
+// until LockedUntil. Lockouts counts the number of times this has happened so
+// that repeated lockouts last progressively longer.
+type AuthLockout struct {
+	Type          AuthLockoutType
+	Id            string
+	Failures      int
+	Lockouts      int
+	LastFailureAt time.Time
+	LockedUntil   time.Time
+}
+
+type AuthLockoutStore interface {
+	SetAuthLockout(ctx context.Context, lockout *AuthLockout) error
+	LookupAuthLockout(ctx context.Context, lockoutType AuthLockoutType, id string) (*AuthLockout, error)
+	// UpdateAuthLockout atomically reads the lockout (or a new lockout with no failures if
+	// there isn't one), applies the update and, if the update reports a change, writes it
+	// back. The update may be called more than once. The resulting lockout is returned.
+	UpdateAuthLockout(ctx context.Context, lockoutType AuthLockoutType, id string, update func(lockout *AuthLockout) bool) (*AuthLockout, error)
+	// ListAuthLockouts returns the lockouts that are in force at the provided time
+	ListAuthLockouts(ctx context.Context, lockedAt time.Time, offset, limit int) ([]*AuthLockout, error)
+	DeleteAuthLockout(ctx context.Context, lockoutType AuthLockoutType, id string) error
+}
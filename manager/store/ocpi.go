@@ -4,6 +4,7 @@ package store
 
 import (
 	"context"
+	"time"
 )
 
 type OcpiRegistrationStatusType string
@@ -25,6 +26,53 @@ type OcpiParty struct {
 	Token       string
 }
 
+type OcpiChargingProfileRequestType string
+
+var (
+	OcpiChargingProfileRequestTypeSet   OcpiChargingProfileRequestType = "set"
+	OcpiChargingProfileRequestTypeGet   OcpiChargingProfileRequestType = "get"
+	OcpiChargingProfileRequestTypeClear OcpiChargingProfileRequestType = "clear"
+)
+
+// OcpiChargingProfileRequest records an OCPI charging profile request that is waiting
+// for the charge station to respond so that the result can be sent to the ResponseUrl
+type OcpiChargingProfileRequest struct {
+	ChargeStationId string
+	EvseId          int
+	Type            OcpiChargingProfileRequestType
+	SessionId       string
+	CountryCode     string
+	PartyId         string
+	ResponseUrl     string
+}
+
+type OcpiDeliveryStatus string
+
+var (
+	OcpiDeliveryStatusPending OcpiDeliveryStatus = "Pending"
+	OcpiDeliveryStatusFailed  OcpiDeliveryStatus = "Failed"
+)
+
+// OcpiDelivery is an outbound OCPI request that has not yet been successfully delivered to
+// the party. When Module is set the Url is relative to the party's receiver endpoint for that
+// module, otherwise it is absolute. Deliveries with a Failed status have exhausted their
+// retries and will not be attempted again unless they are replayed.
+type OcpiDelivery struct {
+	Id            string
+	CountryCode   string
+	PartyId       string
+	Module        string
+	Method        string
+	Url           string
+	Body          string
+	CorrelationId string
+	Status        OcpiDeliveryStatus
+	Attempts      int
+	LastError     string
+	NextAttempt   time.Time
+	Created       time.Time
+}
+
 type OcpiStore interface {
 	SetRegistrationDetails(ctx context.Context, token string, registration *OcpiRegistration) error
 	GetRegistrationDetails(ctx context.Context, token string) (*OcpiRegistration, error)
@@ -33,4 +81,13 @@ type OcpiStore interface {
 	SetPartyDetails(ctx context.Context, partyDetails *OcpiParty) error
 	GetPartyDetails(ctx context.Context, role, countryCode, partyId string) (*OcpiParty, error)
 	ListPartyDetailsForRole(ctx context.Context, role string) ([]*OcpiParty, error)
+
+	SetChargingProfileRequest(ctx context.Context, request *OcpiChargingProfileRequest) error
+	LookupChargingProfileRequest(ctx context.Context, chargeStationId string, evseId int, requestType OcpiChargingProfileRequestType) (*OcpiChargingProfileRequest, error)
+	DeleteChargingProfileRequest(ctx context.Context, chargeStationId string, evseId int, requestType OcpiChargingProfileRequestType) error
+
+	SetDelivery(ctx context.Context, delivery *OcpiDelivery) error
+	LookupDelivery(ctx context.Context, deliveryId string) (*OcpiDelivery, error)
+	ListDeliveries(ctx context.Context, status OcpiDeliveryStatus, offset int, limit int) ([]*OcpiDelivery, error)
+	DeleteDelivery(ctx context.Context, deliveryId string) error
 }
@@ -0,0 +1,66 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package store
+
+import (
+	"context"
+	"time"
+)
+
+// ChargeStationOperationEvent identifies what happened to an operation
+type ChargeStationOperationEvent string
+
+var (
+	// ChargeStationOperationRequested is recorded when a change is requested through the API
+	ChargeStationOperationRequested ChargeStationOperationEvent = "Requested"
+	// ChargeStationOperationAttempted is recorded each time the CSMS tries to deliver a change
+	ChargeStationOperationAttempted ChargeStationOperationEvent = "Attempted"
+	// ChargeStationOperationSent is recorded for every call that is sent to the charge station
+	ChargeStationOperationSent ChargeStationOperationEvent = "Sent"
+	// ChargeStationOperationResponded is recorded when the charge station responds to a call
+	ChargeStationOperationResponded ChargeStationOperationEvent = "Responded"
+	// ChargeStationOperationFailed is recorded when the CSMS gives up trying to deliver a change
+	ChargeStationOperationFailed ChargeStationOperationEvent = "Failed"
+	// ChargeStationOperationRearmed is recorded when a failed change is re-armed through the API
+	ChargeStationOperationRearmed ChargeStationOperationEvent = "Rearmed"
+)
+
+// ChargeStationOperationType identifies the kind of change that an operation relates to
+type ChargeStationOperationType string
+
+var (
+	ChargeStationOperationTypeSetting             ChargeStationOperationType = "Setting"
+	ChargeStationOperationTypeCertificate         ChargeStationOperationType = "Certificate"
+	ChargeStationOperationTypeTrigger             ChargeStationOperationType = "Trigger"
+	ChargeStationOperationTypeCertificateDeletion ChargeStationOperationType = "CertificateDeletion"
+)
+
+// ChargeStationOperation is an entry in the append-only log of the operations that the CSMS
+// has initiated with a charge station. Entries that relate to a queued change (a setting,
+// certificate, trigger message or certificate deletion) have a Type and a Subject (the setting
+// name, certificate id, trigger message or serial number of the certificate being deleted).
+// Entries that relate to an OCPP call have an Action and a MessageId which can be used to
+// match a call with its response.
+type ChargeStationOperation struct {
+	ChargeStationId string
+	Timestamp       time.Time
+	Event           ChargeStationOperationEvent
+	Type            ChargeStationOperationType
+	Subject         string
+	Action          string
+	MessageId       string
+	Attempt         int
+	RequestedBy     string
+	Result          string
+	Payload         string
+}
+
+type ChargeStationOperationStore interface {
+	AppendChargeStationOperation(ctx context.Context, operation *ChargeStationOperation) error
+	// ListChargeStationOperations returns the operations for the charge station in the order
+	// that they were recorded
+	ListChargeStationOperations(ctx context.Context, chargeStationId string, offset, limit int) ([]*ChargeStationOperation, error)
+	// DeleteChargeStationOperationsBefore removes the operations for all charge stations that
+	// were recorded before the given time
+	DeleteChargeStationOperationsBefore(ctx context.Context, before time.Time) error
+}
@@ -0,0 +1,48 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package store
+
+import (
+	"context"
+	"time"
+)
+
+type ReservationStatus string
+
+var (
+	ReservationStatusPending     ReservationStatus = "Pending"
+	ReservationStatusAccepted    ReservationStatus = "Accepted"
+	ReservationStatusRejected    ReservationStatus = "Rejected"
+	ReservationStatusFaulted     ReservationStatus = "Faulted"
+	ReservationStatusOccupied    ReservationStatus = "Occupied"
+	ReservationStatusUnavailable ReservationStatus = "Unavailable"
+	ReservationStatusCancelled   ReservationStatus = "Cancelled"
+	ReservationStatusExpired     ReservationStatus = "Expired"
+	ReservationStatusRemoved     ReservationStatus = "Removed"
+	ReservationStatusUsed        ReservationStatus = "Used"
+)
+
+// Reservation records a reservation that has been requested from a charge station. The
+// OCPI fields are only populated for reservations that were made through OCPI and identify
+// where the result of any outstanding command should be sent.
+type Reservation struct {
+	ReservationId     int
+	ChargeStationId   string
+	EvseId            *int
+	IdToken           string
+	TokenType         string
+	ExpiryDate        time.Time
+	Status            ReservationStatus
+	OcpiReservationId string
+	CountryCode       string
+	PartyId           string
+	ResponseUrl       string
+}
+
+type ReservationStore interface {
+	SetReservation(ctx context.Context, reservation *Reservation) error
+	LookupReservation(ctx context.Context, reservationId int) (*Reservation, error)
+	FindReservationByOcpiId(ctx context.Context, countryCode, partyId, ocpiReservationId string) (*Reservation, error)
+	ListReservations(ctx context.Context, pageSize int, previousReservationId int) ([]*Reservation, error)
+	DeleteReservation(ctx context.Context, reservationId int) error
+}
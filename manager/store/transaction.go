@@ -7,6 +7,7 @@ import "context"
 type Transaction struct {
 	ChargeStationId   string       `firestore:"chargeStationId"`
 	TransactionId     string       `firestore:"transactionId"`
+	EvseId            int          `firestore:"evseId"`
 	IdToken           string       `firestore:"idToken"`
 	TokenType         string       `firestore:"tokenType"`
 	MeterValues       []MeterValue `firestore:"meterValues"`
@@ -38,7 +39,9 @@ type UnitOfMeasure struct {
 type TransactionStore interface {
 	Transactions(ctx context.Context) ([]*Transaction, error)
 	FindTransaction(ctx context.Context, chargeStationId, transactionId string) (*Transaction, error)
+	FindTransactionById(ctx context.Context, transactionId string) (*Transaction, error)
 	CreateTransaction(ctx context.Context, chargeStationId, transactionId, idToken, tokenType string, meterValue []MeterValue, seqNo int, offline bool) error
 	UpdateTransaction(ctx context.Context, chargeStationId, transactionId string, meterValue []MeterValue) error
 	EndTransaction(ctx context.Context, chargeStationId, transactionId, idToken, tokenType string, meterValue []MeterValue, seqNo int) error
+	SetTransactionEvseId(ctx context.Context, chargeStationId, transactionId string, evseId int) error
 }
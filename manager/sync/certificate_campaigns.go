@@ -0,0 +1,247 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package sync
+
+import (
+	"context"
+	"github.com/thoughtworks/maeve-csms/manager/handlers"
+	"github.com/thoughtworks/maeve-csms/manager/handlers/ocpp201"
+	"github.com/thoughtworks/maeve-csms/manager/store"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/exp/slog"
+	"k8s.io/utils/clock"
+	"time"
+)
+
+// certificateCampaignRequester identifies campaign installations in the charge station's operation log
+const certificateCampaignRequester = "certificate-campaign"
+
+// SyncCertificateCampaigns progresses the certificate campaigns that are in progress (or halted).
+// Each run records the charge stations that have given up installing the certificate, halts the
+// campaign if too many charge stations have failed, queues the next wave of installations when
+// it is due and completes the campaign once every charge station has a final result. The notifier
+// (which is optional) is told so that the certificates are sent immediately.
+func SyncCertificateCampaigns(ctx context.Context,
+	tracer trace.Tracer,
+	engine store.Engine,
+	clock clock.PassiveClock,
+	notifier handlers.SyncNotifier,
+	runEvery time.Duration) {
+	for {
+		select {
+		case <-ctx.Done():
+			slog.Info("shutting down sync certificate campaigns")
+			return
+		case <-time.After(runEvery):
+			func() {
+				ctx, span := tracer.Start(ctx, "sync certificate campaigns", trace.WithSpanKind(trace.SpanKindInternal))
+				defer span.End()
+				queued := 0
+				for _, status := range []store.CertificateCampaignStatus{store.CertificateCampaignInProgress, store.CertificateCampaignHalted} {
+					// campaigns that change status drop out of the list, so the list is read
+					// in full before any campaign is progressed
+					var campaigns []*store.CertificateCampaign
+					for offset := 0; ; offset += 50 {
+						page, err := engine.ListCertificateCampaigns(ctx, status, offset, 50)
+						if err != nil {
+							span.RecordError(err)
+							return
+						}
+						campaigns = append(campaigns, page...)
+						if len(page) < 50 {
+							break
+						}
+					}
+					for _, campaign := range campaigns {
+						count, err := syncCertificateCampaign(ctx, engine, clock, notifier, campaign)
+						if err != nil {
+							span.RecordError(err)
+							continue
+						}
+						queued += count
+					}
+				}
+				span.SetAttributes(attribute.Int("sync.certificate_campaign.queued_count", queued))
+			}()
+		}
+	}
+}
+
+// syncCertificateCampaign progresses a single certificate campaign. It returns the number of
+// charge stations that had the certificate queued for installation. The campaign and each of
+// its charge stations are updated in transactions that re-check their status, so a campaign
+// that is cancelled while it is being progressed stays cancelled.
+func syncCertificateCampaign(ctx context.Context,
+	engine store.Engine,
+	clock clock.PassiveClock,
+	notifier handlers.SyncNotifier,
+	campaign *store.CertificateCampaign) (int, error) {
+	now := clock.Now().UTC()
+
+	err := reconcileCertificateCampaignStations(ctx, engine, now, campaign)
+	if err != nil {
+		return 0, err
+	}
+
+	// a new wave is claimed by the same update that decides whether the campaign has halted
+	// or completed, so the decision is made with up to date counts
+	wave := 0
+	campaign, err = engine.UpdateCertificateCampaign(ctx, campaign.CampaignId, func(campaign *store.CertificateCampaign) bool {
+		wave = 0
+		if campaign.Status != store.CertificateCampaignInProgress {
+			return false
+		}
+		failures := campaign.CountStations(store.CertificateCampaignStationFailed)
+		if campaign.MaxFailures > 0 && failures > campaign.MaxFailures {
+			slog.Warn("halting certificate campaign", "campaignId", campaign.CampaignId, "failures", failures)
+			campaign.Status = store.CertificateCampaignHalted
+			return true
+		}
+		if campaign.CountStations(store.CertificateCampaignStationWaiting) > 0 {
+			if now.Before(campaign.NextWaveAt) {
+				return false
+			}
+			campaign.Waves++
+			campaign.NextWaveAt = now.Add(campaign.WaveInterval)
+			wave = campaign.Waves
+			return true
+		}
+		if campaign.CountStations(store.CertificateCampaignStationPending) == 0 &&
+			campaign.CountStations(store.CertificateCampaignStationRejected) == 0 {
+			slog.Info("completed certificate campaign", "campaignId", campaign.CampaignId, "failures", failures)
+			campaign.Status = store.CertificateCampaignCompleted
+			return true
+		}
+		return false
+	})
+	if err != nil {
+		return 0, err
+	}
+	if campaign == nil || wave == 0 {
+		return 0, nil
+	}
+
+	stations, err := engine.ListCertificateCampaignStations(ctx, campaign.CampaignId, store.CertificateCampaignStationWaiting, 0, campaign.WaveSize)
+	if err != nil {
+		return 0, err
+	}
+	var queued []string
+	for _, station := range stations {
+		updated, err := engine.UpdateCertificateCampaignStation(ctx, campaign.CampaignId, station.ChargeStationId,
+			func(campaign *store.CertificateCampaign, station *store.CertificateCampaignStation) bool {
+				if campaign.Status != store.CertificateCampaignInProgress || station.Status != store.CertificateCampaignStationWaiting {
+					return false
+				}
+				station.Status = store.CertificateCampaignStationPending
+				station.Wave = wave
+				station.UpdatedAt = now
+				return true
+			})
+		if err != nil {
+			return len(queued), err
+		}
+		if updated == nil || updated.Status != store.CertificateCampaignStationPending || updated.Wave != wave {
+			continue
+		}
+		// a charge station whose installation cannot be queued is recorded as Failed when the
+		// campaign is next reconciled
+		err = engine.UpdateChargeStationInstallCertificates(ctx, station.ChargeStationId, &store.ChargeStationInstallCertificates{
+			Certificates: []*store.ChargeStationInstallCertificate{
+				{
+					CertificateType:               campaign.CertificateType,
+					CertificateId:                 campaign.CertificateId,
+					CertificateData:               campaign.CertificateData,
+					CertificateInstallationStatus: store.CertificateInstallationPending,
+				},
+			},
+		})
+		if err != nil {
+			return len(queued), err
+		}
+		queued = append(queued, station.ChargeStationId)
+
+		handlers.RecordOperation(ctx, engine, &store.ChargeStationOperation{
+			ChargeStationId: station.ChargeStationId,
+			Timestamp:       now,
+			Event:           store.ChargeStationOperationRequested,
+			Type:            store.ChargeStationOperationTypeCertificate,
+			Subject:         campaign.CertificateId,
+			RequestedBy:     certificateCampaignRequester,
+		})
+		if notifier != nil {
+			notifier.NotifyPending(ctx, station.ChargeStationId)
+		}
+	}
+	slog.Info("queued certificate campaign wave", "campaignId", campaign.CampaignId,
+		"wave", wave, "chargeStations", len(queued))
+
+	return len(queued), nil
+}
+
+// reconcileCertificateCampaignStations updates the charge stations that have the certificate
+// queued with the status of the installation: this records the charge stations that have
+// exhausted the retry policy (or have been deleted) as Failed and catches any results that
+// were not recorded when the charge station responded.
+func reconcileCertificateCampaignStations(ctx context.Context,
+	engine store.Engine,
+	now time.Time,
+	campaign *store.CertificateCampaign) error {
+	// charge stations that change status drop out of the list, so the list is read in full
+	// before any charge station is updated
+	var stations []*store.CertificateCampaignStation
+	for _, stationStatus := range []store.CertificateCampaignStationStatus{store.CertificateCampaignStationPending, store.CertificateCampaignStationRejected} {
+		for offset := 0; ; offset += 50 {
+			page, err := engine.ListCertificateCampaignStations(ctx, campaign.CampaignId, stationStatus, offset, 50)
+			if err != nil {
+				return err
+			}
+			stations = append(stations, page...)
+			if len(page) < 50 {
+				break
+			}
+		}
+	}
+
+	for _, station := range stations {
+		installCertificates, err := engine.LookupChargeStationInstallCertificates(ctx, station.ChargeStationId)
+		if err != nil {
+			return err
+		}
+		status := store.CertificateCampaignStationFailed
+		if installCertificates != nil {
+			for _, certificate := range installCertificates.Certificates {
+				if certificate.CertificateId != campaign.CertificateId {
+					continue
+				}
+				switch certificate.CertificateInstallationStatus {
+				case store.CertificateInstallationAccepted:
+					status = store.CertificateCampaignStationAccepted
+				case store.CertificateInstallationFailed:
+					status = store.CertificateCampaignStationFailed
+				default:
+					status = station.Status
+				}
+				break
+			}
+		}
+		if status == station.Status {
+			continue
+		}
+		_, err = engine.UpdateCertificateCampaignStation(ctx, campaign.CampaignId, station.ChargeStationId,
+			func(campaign *store.CertificateCampaign, current *store.CertificateCampaignStation) bool {
+				// the campaign may have been cancelled, or the result recorded, since the
+				// list was read
+				if !ocpp201.IsActiveCertificateCampaign(campaign) || current.Status != station.Status {
+					return false
+				}
+				current.Status = status
+				current.UpdatedAt = now
+				return true
+			})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package sync_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thoughtworks/maeve-csms/manager/store"
+	"github.com/thoughtworks/maeve-csms/manager/store/inmemory"
+	"github.com/thoughtworks/maeve-csms/manager/sync"
+	"github.com/thoughtworks/maeve-csms/manager/testutil"
+	clockTest "k8s.io/utils/clock/testing"
+	"testing"
+	"time"
+)
+
+func TestSyncCertificateCampaignsQueuesWaves(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+
+	now := time.Date(2023, 6, 15, 15, 0, 0, 0, time.UTC)
+	clock := clockTest.NewFakePassiveClock(now)
+	engine := inmemory.NewStore(clock)
+
+	err := engine.CreateCertificateCampaign(ctx, &store.CertificateCampaign{
+		CampaignId:      "campaign001",
+		CertificateType: store.CertificateTypeV2G,
+		CertificateId:   "cert001",
+		CertificateData: "pem-data",
+		WaveSize:        2,
+		WaveInterval:    time.Hour,
+		Status:          store.CertificateCampaignInProgress,
+		CreatedAt:       now,
+		NextWaveAt:      now,
+	}, []*store.CertificateCampaignStation{
+		{ChargeStationId: "cs001", Status: store.CertificateCampaignStationWaiting},
+		{ChargeStationId: "cs002", Status: store.CertificateCampaignStationWaiting},
+		{ChargeStationId: "cs003", Status: store.CertificateCampaignStationWaiting},
+	})
+	require.NoError(t, err)
+
+	tracer, _ := testutil.GetTracer()
+	notifier := &recordingSyncNotifier{}
+
+	sync.SyncCertificateCampaigns(ctx, tracer, engine, clock, notifier, 100*time.Millisecond)
+
+	// the clock does not move, so only the first wave is queued
+	assert.Equal(t, []string{"cs001", "cs002"}, notifier.pending)
+
+	campaign, err := engine.LookupCertificateCampaign(context.Background(), "campaign001")
+	require.NoError(t, err)
+	assert.Equal(t, store.CertificateCampaignInProgress, campaign.Status)
+	assert.Equal(t, 1, campaign.Waves)
+	assert.Equal(t, now.Add(time.Hour), campaign.NextWaveAt)
+	stations, err := engine.ListCertificateCampaignStations(context.Background(), "campaign001", "", 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []*store.CertificateCampaignStation{
+		{ChargeStationId: "cs001", Status: store.CertificateCampaignStationPending, Wave: 1, UpdatedAt: now},
+		{ChargeStationId: "cs002", Status: store.CertificateCampaignStationPending, Wave: 1, UpdatedAt: now},
+		{ChargeStationId: "cs003", Status: store.CertificateCampaignStationWaiting},
+	}, stations)
+
+	for _, csId := range []string{"cs001", "cs002"} {
+		installCertificates, err := engine.LookupChargeStationInstallCertificates(context.Background(), csId)
+		require.NoError(t, err)
+		require.NotNil(t, installCertificates)
+		require.Len(t, installCertificates.Certificates, 1)
+		assert.Equal(t, "cert001", installCertificates.Certificates[0].CertificateId)
+		assert.Equal(t, store.CertificateTypeV2G, installCertificates.Certificates[0].CertificateType)
+		assert.Equal(t, store.CertificateInstallationPending, installCertificates.Certificates[0].CertificateInstallationStatus)
+
+		operations, err := engine.ListChargeStationOperations(context.Background(), csId, 0, 10)
+		require.NoError(t, err)
+		require.Len(t, operations, 1)
+		assert.Equal(t, store.ChargeStationOperationRequested, operations[0].Event)
+		assert.Equal(t, store.ChargeStationOperationTypeCertificate, operations[0].Type)
+		assert.Equal(t, "cert001", operations[0].Subject)
+		assert.Equal(t, "certificate-campaign", operations[0].RequestedBy)
+	}
+
+	installCertificates, err := engine.LookupChargeStationInstallCertificates(context.Background(), "cs003")
+	require.NoError(t, err)
+	assert.Nil(t, installCertificates)
+}
+
+func TestSyncCertificateCampaignsRecordsResults(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+
+	now := time.Date(2023, 6, 15, 15, 0, 0, 0, time.UTC)
+	clock := clockTest.NewFakePassiveClock(now)
+	engine := inmemory.NewStore(clock)
+
+	err := engine.UpdateChargeStationInstallCertificates(ctx, "cs001", &store.ChargeStationInstallCertificates{
+		Certificates: []*store.ChargeStationInstallCertificate{
+			{CertificateId: "cert001", CertificateInstallationStatus: store.CertificateInstallationFailed},
+			{CertificateId: "cert002", CertificateInstallationStatus: store.CertificateInstallationFailed},
+		},
+	})
+	require.NoError(t, err)
+	err = engine.UpdateChargeStationInstallCertificates(ctx, "cs002", &store.ChargeStationInstallCertificates{
+		Certificates: []*store.ChargeStationInstallCertificate{
+			{CertificateId: "cert001", CertificateInstallationStatus: store.CertificateInstallationAccepted},
+		},
+	})
+	require.NoError(t, err)
+
+	// every charge station has a final result once the failures have been recorded
+	err = engine.CreateCertificateCampaign(ctx, &store.CertificateCampaign{
+		CampaignId:    "campaign001",
+		CertificateId: "cert001",
+		WaveSize:      2,
+		Status:        store.CertificateCampaignInProgress,
+		CreatedAt:     now,
+		Waves:         1,
+	}, []*store.CertificateCampaignStation{
+		{ChargeStationId: "cs001", Status: store.CertificateCampaignStationPending, Wave: 1},
+		{ChargeStationId: "cs002", Status: store.CertificateCampaignStationRejected, Wave: 1},
+	})
+	require.NoError(t, err)
+	// more than one charge station has failed (cs003 has been deleted)
+	err = engine.CreateCertificateCampaign(ctx, &store.CertificateCampaign{
+		CampaignId:    "campaign002",
+		CertificateId: "cert002",
+		WaveSize:      2,
+		MaxFailures:   1,
+		Status:        store.CertificateCampaignInProgress,
+		CreatedAt:     now.Add(time.Minute),
+		Waves:         1,
+	}, []*store.CertificateCampaignStation{
+		{ChargeStationId: "cs001", Status: store.CertificateCampaignStationPending, Wave: 1},
+		{ChargeStationId: "cs003", Status: store.CertificateCampaignStationPending, Wave: 1},
+		{ChargeStationId: "cs004", Status: store.CertificateCampaignStationWaiting},
+	})
+	require.NoError(t, err)
+
+	tracer, _ := testutil.GetTracer()
+
+	sync.SyncCertificateCampaigns(ctx, tracer, engine, clock, nil, 100*time.Millisecond)
+
+	campaign, err := engine.LookupCertificateCampaign(context.Background(), "campaign001")
+	require.NoError(t, err)
+	assert.Equal(t, store.CertificateCampaignCompleted, campaign.Status)
+	stations, err := engine.ListCertificateCampaignStations(context.Background(), "campaign001", "", 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []*store.CertificateCampaignStation{
+		{ChargeStationId: "cs001", Status: store.CertificateCampaignStationFailed, Wave: 1, UpdatedAt: now},
+		{ChargeStationId: "cs002", Status: store.CertificateCampaignStationAccepted, Wave: 1, UpdatedAt: now},
+	}, stations)
+
+	campaign, err = engine.LookupCertificateCampaign(context.Background(), "campaign002")
+	require.NoError(t, err)
+	assert.Equal(t, store.CertificateCampaignHalted, campaign.Status)
+	assert.Equal(t, 1, campaign.Waves)
+	stations, err = engine.ListCertificateCampaignStations(context.Background(), "campaign002", "", 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []*store.CertificateCampaignStation{
+		{ChargeStationId: "cs001", Status: store.CertificateCampaignStationFailed, Wave: 1, UpdatedAt: now},
+		{ChargeStationId: "cs003", Status: store.CertificateCampaignStationFailed, Wave: 1, UpdatedAt: now},
+		{ChargeStationId: "cs004", Status: store.CertificateCampaignStationWaiting},
+	}, stations)
+
+	installCertificates, err := engine.LookupChargeStationInstallCertificates(context.Background(), "cs004")
+	require.NoError(t, err)
+	assert.Nil(t, installCertificates)
+}
@@ -12,7 +12,7 @@ import (
 	"time"
 )
 
-func SyncCertificates(ctx context.Context, engine store.Engine, clock clock.PassiveClock, v16CallMaker, v201CallMaker handlers.CallMaker, runEvery, retryAfter time.Duration) {
+func SyncCertificates(ctx context.Context, engine store.Engine, clock clock.PassiveClock, dataTransferCallMaker, v201CallMaker, v21CallMaker handlers.CallMaker, runEvery time.Duration, retryPolicy RetryPolicy) {
 	var previousChargeStationId string
 	for {
 		select {
@@ -37,71 +37,109 @@ func SyncCertificates(ctx context.Context, engine store.Engine, clock clock.Pass
 				if err != nil {
 					slog.Error("lookup charge station runtime details", slog.String("err", err.Error()),
 						slog.String("chargeStationId", pendingCertificateInstallation.ChargeStationId))
+					continue
 				}
-				var callMaker handlers.CallMaker
-				if details.OcppVersion == "1.6" {
-					callMaker = v16CallMaker
-				} else {
-					callMaker = v201CallMaker
+				if details == nil {
+					continue
 				}
+				syncChargeStationCertificates(ctx, engine, clock, dataTransferCallMaker, v201CallMaker, v21CallMaker,
+					pendingCertificateInstallation.ChargeStationId, details, pendingCertificateInstallation, retryPolicy, false)
+			}
+		}
+	}
+}
 
-				csId := pendingCertificateInstallation.ChargeStationId
-				for _, certificate := range pendingCertificateInstallation.Certificates {
-					if certificate.CertificateInstallationStatus != store.CertificateInstallationAccepted && clock.Now().After(certificate.SendAfter) {
-						slog.Info("updating charge station certificates", slog.String("chargeStationId", csId),
-							slog.String("certificate", certificate.CertificateId),
-							slog.String("OcppVersion", details.OcppVersion))
-						certificate.SendAfter = clock.Now().Add(retryAfter)
-						err = engine.UpdateChargeStationInstallCertificates(ctx, csId, &store.ChargeStationInstallCertificates{
-							Certificates: []*store.ChargeStationInstallCertificate{
-								certificate,
-							},
-						})
-						if err != nil {
-							slog.Error("update charge station certificates", slog.String("err", err.Error()))
-							continue
-						}
+// syncChargeStationCertificates sends the pending certificates for a single charge station.
+// Certificates are only sent once their SendAfter time has passed unless force is set:
+// certificates that have exhausted the retry policy are marked as Failed instead. OCPP 1.6
+// charge stations are sent the OCPP 2.0.1 messages wrapped in a DataTransfer.
+func syncChargeStationCertificates(ctx context.Context,
+	engine store.Engine,
+	clock clock.PassiveClock,
+	dataTransferCallMaker,
+	v201CallMaker,
+	v21CallMaker handlers.CallMaker,
+	csId string,
+	details *store.ChargeStationRuntimeDetails,
+	pendingCertificateInstallation *store.ChargeStationInstallCertificates,
+	retryPolicy RetryPolicy,
+	force bool) {
+	callMaker := handlers.CallMakerForVersion(details.OcppVersion, dataTransferCallMaker, v201CallMaker, v21CallMaker)
 
-						if certificate.CertificateType == store.CertificateTypeChargeStation ||
-							certificate.CertificateType == store.CertificateTypeEVCC {
-							var certType ocpp201.CertificateSigningUseEnumType
-							if certificate.CertificateType == store.CertificateTypeChargeStation {
-								certType = ocpp201.CertificateSigningUseEnumTypeChargingStationCertificate
-							} else {
-								certType = ocpp201.CertificateSigningUseEnumTypeV2GCertificate
-							}
-							req := &ocpp201.CertificateSignedRequestJson{
-								CertificateChain: certificate.CertificateData,
-								CertificateType:  &certType,
-							}
-							err = callMaker.Send(ctx, csId, req)
-							if err != nil {
-								slog.Error("send certificate signed request", slog.String("err", err.Error()),
-									slog.String("chargeStationId", csId), slog.String("certificate", certificate.CertificateId))
-							}
-						} else {
-							var certType ocpp201.InstallCertificateUseEnumType
-							switch certificate.CertificateType {
-							case store.CertificateTypeCSMS:
-								certType = ocpp201.InstallCertificateUseEnumTypeCSMSRootCertificate
-							case store.CertificateTypeV2G:
-								certType = ocpp201.InstallCertificateUseEnumTypeV2GRootCertificate
-							case store.CertificateTypeMO:
-								certType = ocpp201.InstallCertificateUseEnumTypeMORootCertificate
-							case store.CertificateTypeMF:
-								certType = ocpp201.InstallCertificateUseEnumTypeManufacturerRootCertificate
-							}
-							req := &ocpp201.InstallCertificateRequestJson{
-								CertificateType: certType,
-								Certificate:     certificate.CertificateData,
-							}
-							err = callMaker.Send(ctx, csId, req)
-							if err != nil {
-								slog.Error("send install certificate request", slog.String("err", err.Error()),
-									slog.String("chargeStationId", csId), slog.String("certificate", certificate.CertificateId))
-							}
-						}
-					}
+	for _, certificate := range pendingCertificateInstallation.Certificates {
+		if isPendingCertificateInstallation(certificate) && (force || clock.Now().After(certificate.SendAfter)) {
+			if retryPolicy.Exhausted(certificate.Attempts) {
+				slog.Warn("charge station certificate failed", slog.String("chargeStationId", csId),
+					slog.String("certificate", certificate.CertificateId),
+					slog.Int("attempts", certificate.Attempts))
+				certificate.CertificateInstallationStatus = store.CertificateInstallationFailed
+				err := engine.UpdateChargeStationInstallCertificates(ctx, csId, &store.ChargeStationInstallCertificates{
+					Certificates: []*store.ChargeStationInstallCertificate{
+						certificate,
+					},
+				})
+				if err != nil {
+					slog.Error("update charge station certificates", slog.String("err", err.Error()))
+				}
+				recordAttempt(ctx, engine, clock, csId, store.ChargeStationOperationFailed,
+					store.ChargeStationOperationTypeCertificate, certificate.CertificateId, certificate.Attempts)
+				continue
+			}
+
+			slog.Info("updating charge station certificates", slog.String("chargeStationId", csId),
+				slog.String("certificate", certificate.CertificateId),
+				slog.String("OcppVersion", details.OcppVersion))
+			certificate.SendAfter = clock.Now().Add(retryPolicy.Backoff(certificate.Attempts))
+			certificate.Attempts++
+			err := engine.UpdateChargeStationInstallCertificates(ctx, csId, &store.ChargeStationInstallCertificates{
+				Certificates: []*store.ChargeStationInstallCertificate{
+					certificate,
+				},
+			})
+			if err != nil {
+				slog.Error("update charge station certificates", slog.String("err", err.Error()))
+				continue
+			}
+			recordAttempt(ctx, engine, clock, csId, store.ChargeStationOperationAttempted,
+				store.ChargeStationOperationTypeCertificate, certificate.CertificateId, certificate.Attempts)
+
+			if certificate.CertificateType == store.CertificateTypeChargeStation ||
+				certificate.CertificateType == store.CertificateTypeEVCC {
+				var certType ocpp201.CertificateSigningUseEnumType
+				if certificate.CertificateType == store.CertificateTypeChargeStation {
+					certType = ocpp201.CertificateSigningUseEnumTypeChargingStationCertificate
+				} else {
+					certType = ocpp201.CertificateSigningUseEnumTypeV2GCertificate
+				}
+				req := &ocpp201.CertificateSignedRequestJson{
+					CertificateChain: certificate.CertificateData,
+					CertificateType:  &certType,
+				}
+				err = callMaker.Send(ctx, csId, req)
+				if err != nil {
+					slog.Error("send certificate signed request", slog.String("err", err.Error()),
+						slog.String("chargeStationId", csId), slog.String("certificate", certificate.CertificateId))
+				}
+			} else {
+				var certType ocpp201.InstallCertificateUseEnumType
+				switch certificate.CertificateType {
+				case store.CertificateTypeCSMS:
+					certType = ocpp201.InstallCertificateUseEnumTypeCSMSRootCertificate
+				case store.CertificateTypeV2G:
+					certType = ocpp201.InstallCertificateUseEnumTypeV2GRootCertificate
+				case store.CertificateTypeMO:
+					certType = ocpp201.InstallCertificateUseEnumTypeMORootCertificate
+				case store.CertificateTypeMF:
+					certType = ocpp201.InstallCertificateUseEnumTypeManufacturerRootCertificate
+				}
+				req := &ocpp201.InstallCertificateRequestJson{
+					CertificateType: certType,
+					Certificate:     certificate.CertificateData,
+				}
+				err = callMaker.Send(ctx, csId, req)
+				if err != nil {
+					slog.Error("send install certificate request", slog.String("err", err.Error()),
+						slog.String("chargeStationId", csId), slog.String("certificate", certificate.CertificateId))
 				}
 			}
 		}
@@ -112,7 +150,7 @@ func filterPendingCertificatesInstallations(certificateInstallations []*store.Ch
 	var pendingCertificateInstallations []*store.ChargeStationInstallCertificates
 	for _, certificateInstallation := range certificateInstallations {
 		for _, certificate := range certificateInstallation.Certificates {
-			if certificate.CertificateInstallationStatus != store.CertificateInstallationAccepted {
+			if isPendingCertificateInstallation(certificate) {
 				pendingCertificateInstallations = append(pendingCertificateInstallations, certificateInstallation)
 				break
 			}
@@ -120,3 +158,8 @@ func filterPendingCertificatesInstallations(certificateInstallations []*store.Ch
 	}
 	return pendingCertificateInstallations
 }
+
+func isPendingCertificateInstallation(certificate *store.ChargeStationInstallCertificate) bool {
+	return certificate.CertificateInstallationStatus != store.CertificateInstallationAccepted &&
+		certificate.CertificateInstallationStatus != store.CertificateInstallationFailed
+}
@@ -172,7 +172,7 @@ func TestSyncCertificates(t *testing.T) {
 		updateFn: updater.update,
 	}
 
-	sync.SyncCertificates(ctx, engine, clock.RealClock{}, v16CallMaker, v201CallMaker, 100*time.Millisecond, 100*time.Millisecond)
+	sync.SyncCertificates(ctx, engine, clock.RealClock{}, v16CallMaker, v201CallMaker, nil, 100*time.Millisecond, sync.FixedRetryPolicy(100*time.Millisecond))
 
 	require.Len(t, v16CallMaker.callEvents, 2)
 	assert.Equal(t, v16CallMaker.callEvents[0].chargeStationId, "cs001")
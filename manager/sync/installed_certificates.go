@@ -0,0 +1,212 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package sync
+
+import (
+	"context"
+	"github.com/thoughtworks/maeve-csms/manager/handlers"
+	"github.com/thoughtworks/maeve-csms/manager/ocpp/ocpp201"
+	"github.com/thoughtworks/maeve-csms/manager/store"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/exp/slog"
+	"k8s.io/utils/clock"
+	"time"
+)
+
+// SyncInstalledCertificates asks each connected charge station for its installed certificates
+// when it has not been asked within refreshEvery and sends the pending certificate deletions.
+func SyncInstalledCertificates(ctx context.Context,
+	tracer trace.Tracer,
+	engine store.Engine,
+	clock clock.PassiveClock,
+	dataTransferCallMaker,
+	v201CallMaker,
+	v21CallMaker handlers.CallMaker,
+	runEvery time.Duration,
+	refreshEvery time.Duration,
+	retryPolicy RetryPolicy) {
+	for {
+		select {
+		case <-ctx.Done():
+			slog.Info("shutting down sync installed certificates")
+			return
+		case <-time.After(runEvery):
+			func() {
+				ctx, span := tracer.Start(ctx, "sync installed certificates", trace.WithSpanKind(trace.SpanKindInternal))
+				defer span.End()
+				refreshes := 0
+				for offset := 0; ; offset += 50 {
+					connections, err := engine.ListConnectedChargeStations(ctx, offset, 50)
+					if err != nil {
+						span.RecordError(err)
+						return
+					}
+					for _, connection := range connections {
+						refreshed, err := syncChargeStationInstalledCertificates(ctx, engine, clock, dataTransferCallMaker,
+							v201CallMaker, v21CallMaker, connection.ChargeStationId, refreshEvery, retryPolicy)
+						if err != nil {
+							span.RecordError(err)
+							continue
+						}
+						if refreshed {
+							refreshes++
+						}
+					}
+					if len(connections) < 50 {
+						break
+					}
+				}
+				span.SetAttributes(attribute.Int("sync.installed_certificates.refresh_count", refreshes))
+			}()
+		}
+	}
+}
+
+// syncChargeStationInstalledCertificates asks a single charge station for its installed
+// certificates if they are due to be refreshed and sends its pending certificate deletions.
+// It returns true if the installed certificates were requested.
+func syncChargeStationInstalledCertificates(ctx context.Context,
+	engine store.Engine,
+	clock clock.PassiveClock,
+	dataTransferCallMaker,
+	v201CallMaker,
+	v21CallMaker handlers.CallMaker,
+	csId string,
+	refreshEvery time.Duration,
+	retryPolicy RetryPolicy) (bool, error) {
+	details, err := engine.LookupChargeStationRuntimeDetails(ctx, csId)
+	if err != nil {
+		return false, err
+	}
+	if details == nil {
+		return false, nil
+	}
+
+	installed, err := engine.LookupChargeStationInstalledCertificates(ctx, csId)
+	if err != nil {
+		return false, err
+	}
+	if installed == nil {
+		installed = &store.ChargeStationInstalledCertificates{
+			ChargeStationId: csId,
+		}
+	}
+
+	syncChargeStationCertificateDeletions(ctx, engine, clock, dataTransferCallMaker, v201CallMaker, v21CallMaker, csId, details,
+		installed, retryPolicy, false)
+
+	requested := false
+	_, err = engine.UpdateChargeStationInstalledCertificates(ctx, csId, func(installed *store.ChargeStationInstalledCertificates) bool {
+		requested = installed.RequestedAt.IsZero() || !clock.Now().Before(installed.RequestedAt.Add(refreshEvery))
+		if requested {
+			installed.RequestedAt = clock.Now().UTC()
+		}
+		return requested
+	})
+	if err != nil {
+		return false, err
+	}
+	if !requested {
+		return false, nil
+	}
+
+	slog.Info("requesting installed certificates", slog.String("chargeStationId", csId),
+		slog.String("OcppVersion", details.OcppVersion))
+	err = handlers.CallMakerForVersion(details.OcppVersion, dataTransferCallMaker, v201CallMaker, v21CallMaker).
+		Send(ctx, csId, &ocpp201.GetInstalledCertificateIdsRequestJson{})
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// syncChargeStationCertificateDeletions sends the pending certificate deletions for a single
+// charge station. Deletions are only sent once their SendAfter time has passed unless force is
+// set: deletions that have exhausted the retry policy are marked as Failed instead. Each deletion
+// is re-checked against the stored installed certificates when it is updated, so a deletion that
+// has been completed or re-armed concurrently is not overwritten.
+func syncChargeStationCertificateDeletions(ctx context.Context,
+	engine store.Engine,
+	clock clock.PassiveClock,
+	dataTransferCallMaker,
+	v201CallMaker,
+	v21CallMaker handlers.CallMaker,
+	csId string,
+	details *store.ChargeStationRuntimeDetails,
+	installed *store.ChargeStationInstalledCertificates,
+	retryPolicy RetryPolicy,
+	force bool) {
+	callMaker := handlers.CallMakerForVersion(details.OcppVersion, dataTransferCallMaker, v201CallMaker, v21CallMaker)
+
+	due := func(certificate *store.ChargeStationInstalledCertificate) bool {
+		return certificate.DeletionStatus == store.CertificateDeletionPending && (force || clock.Now().After(certificate.SendAfter))
+	}
+
+	for _, pending := range installed.Certificates {
+		if !due(pending) {
+			continue
+		}
+
+		var certificate store.ChargeStationInstalledCertificate
+		var send, failed bool
+		_, err := engine.UpdateChargeStationInstalledCertificates(ctx, csId, func(installed *store.ChargeStationInstalledCertificates) bool {
+			send, failed = false, false
+			for _, current := range installed.Certificates {
+				if !current.SameCertificate(pending) {
+					continue
+				}
+				if !due(current) {
+					return false
+				}
+				if retryPolicy.Exhausted(current.Attempts) {
+					current.DeletionStatus = store.CertificateDeletionFailed
+					failed = true
+				} else {
+					current.SendAfter = clock.Now().Add(retryPolicy.Backoff(current.Attempts))
+					current.Attempts++
+					send = true
+				}
+				certificate = *current
+				return true
+			}
+			return false
+		})
+		if err != nil {
+			slog.Error("update charge station installed certificates", slog.String("err", err.Error()))
+			continue
+		}
+
+		if failed {
+			slog.Warn("charge station certificate deletion failed", slog.String("chargeStationId", csId),
+				slog.String("serialNumber", certificate.SerialNumber),
+				slog.Int("attempts", certificate.Attempts))
+			recordAttempt(ctx, engine, clock, csId, store.ChargeStationOperationFailed,
+				store.ChargeStationOperationTypeCertificateDeletion, certificate.SerialNumber, certificate.Attempts)
+			continue
+		}
+		if !send {
+			continue
+		}
+
+		slog.Info("deleting charge station certificate", slog.String("chargeStationId", csId),
+			slog.String("serialNumber", certificate.SerialNumber),
+			slog.String("OcppVersion", details.OcppVersion))
+		recordAttempt(ctx, engine, clock, csId, store.ChargeStationOperationAttempted,
+			store.ChargeStationOperationTypeCertificateDeletion, certificate.SerialNumber, certificate.Attempts)
+
+		req := &ocpp201.DeleteCertificateRequestJson{
+			CertificateHashData: ocpp201.CertificateHashDataType{
+				HashAlgorithm:  ocpp201.HashAlgorithmEnumType(certificate.HashAlgorithm),
+				IssuerNameHash: certificate.IssuerNameHash,
+				IssuerKeyHash:  certificate.IssuerKeyHash,
+				SerialNumber:   certificate.SerialNumber,
+			},
+		}
+		err = callMaker.Send(ctx, csId, req)
+		if err != nil {
+			slog.Error("send delete certificate request", slog.String("err", err.Error()),
+				slog.String("chargeStationId", csId), slog.String("serialNumber", certificate.SerialNumber))
+		}
+	}
+}
@@ -0,0 +1,153 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package sync_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thoughtworks/maeve-csms/manager/ocpp/ocpp201"
+	"github.com/thoughtworks/maeve-csms/manager/store"
+	"github.com/thoughtworks/maeve-csms/manager/store/inmemory"
+	"github.com/thoughtworks/maeve-csms/manager/sync"
+	"github.com/thoughtworks/maeve-csms/manager/testutil"
+	clockTest "k8s.io/utils/clock/testing"
+	"testing"
+	"time"
+)
+
+func TestSyncInstalledCertificatesRequestsInstalledCertificates(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+
+	now := time.Date(2023, 6, 15, 15, 0, 0, 0, time.UTC)
+	clock := clockTest.NewFakePassiveClock(now)
+	engine := inmemory.NewStore(clock)
+
+	chargeStations := map[string]struct {
+		ocppVersion string
+		connected   bool
+		requestedAt time.Time
+	}{
+		"cs001": {ocppVersion: "2.0.1", connected: true},
+		"cs002": {ocppVersion: "1.6", connected: true, requestedAt: now.Add(-25 * time.Hour)},
+		"cs003": {ocppVersion: "2.0.1", connected: true, requestedAt: now.Add(-time.Hour)},
+		"cs004": {ocppVersion: "2.0.1", connected: false},
+	}
+	for csId, cs := range chargeStations {
+		err := engine.SetChargeStationRuntimeDetails(ctx, csId, &store.ChargeStationRuntimeDetails{OcppVersion: cs.ocppVersion})
+		require.NoError(t, err)
+		err = engine.SetChargeStationConnection(ctx, csId, &store.ChargeStationConnection{Connected: cs.connected})
+		require.NoError(t, err)
+		if !cs.requestedAt.IsZero() {
+			err = engine.SetChargeStationInstalledCertificates(ctx, csId, &store.ChargeStationInstalledCertificates{
+				RequestedAt: cs.requestedAt,
+			})
+			require.NoError(t, err)
+		}
+	}
+
+	tracer, _ := testutil.GetTracer()
+	dataTransferCallMaker := &mockCallMaker{engine: engine}
+	v201CallMaker := &mockCallMaker{engine: engine}
+
+	sync.SyncInstalledCertificates(ctx, tracer, engine, clock, dataTransferCallMaker, v201CallMaker, nil,
+		100*time.Millisecond, 24*time.Hour, sync.DefaultRetryPolicy)
+
+	// the clock does not move, so each charge station is only asked once
+	require.Len(t, v201CallMaker.callEvents, 1)
+	assert.Equal(t, "cs001", v201CallMaker.callEvents[0].chargeStationId)
+	assert.Equal(t, &ocpp201.GetInstalledCertificateIdsRequestJson{}, v201CallMaker.callEvents[0].request)
+	require.Len(t, dataTransferCallMaker.callEvents, 1)
+	assert.Equal(t, "cs002", dataTransferCallMaker.callEvents[0].chargeStationId)
+
+	for _, csId := range []string{"cs001", "cs002"} {
+		installed, err := engine.LookupChargeStationInstalledCertificates(context.Background(), csId)
+		require.NoError(t, err)
+		assert.Equal(t, now, installed.RequestedAt)
+	}
+}
+
+func TestSyncInstalledCertificatesDeletesCertificates(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+
+	now := time.Date(2023, 6, 15, 15, 0, 0, 0, time.UTC)
+	clock := clockTest.NewFakePassiveClock(now)
+	engine := inmemory.NewStore(clock)
+
+	err := engine.SetChargeStationRuntimeDetails(ctx, "cs001", &store.ChargeStationRuntimeDetails{OcppVersion: "2.0.1"})
+	require.NoError(t, err)
+	err = engine.SetChargeStationConnection(ctx, "cs001", &store.ChargeStationConnection{Connected: true})
+	require.NoError(t, err)
+	err = engine.SetChargeStationInstalledCertificates(ctx, "cs001", &store.ChargeStationInstalledCertificates{
+		Certificates: []*store.ChargeStationInstalledCertificate{
+			{
+				CertificateType: store.CertificateTypeV2G,
+				HashAlgorithm:   "SHA256",
+				IssuerNameHash:  "name001",
+				IssuerKeyHash:   "key001",
+				SerialNumber:    "01",
+				DeletionStatus:  store.CertificateDeletionPending,
+			},
+			{
+				CertificateType: store.CertificateTypeMO,
+				HashAlgorithm:   "SHA256",
+				IssuerNameHash:  "name002",
+				IssuerKeyHash:   "key002",
+				SerialNumber:    "02",
+				DeletionStatus:  store.CertificateDeletionPending,
+				Attempts:        3,
+			},
+			{
+				CertificateType: store.CertificateTypeCSMS,
+				HashAlgorithm:   "SHA256",
+				IssuerNameHash:  "name003",
+				IssuerKeyHash:   "key003",
+				SerialNumber:    "03",
+			},
+		},
+		RequestedAt: now,
+	})
+	require.NoError(t, err)
+
+	tracer, _ := testutil.GetTracer()
+	v201CallMaker := &mockCallMaker{engine: engine}
+
+	retryPolicy := sync.RetryPolicy{
+		InitialInterval: time.Hour,
+		MaxInterval:     time.Hour,
+		Multiplier:      1,
+		MaxAttempts:     3,
+	}
+	sync.SyncInstalledCertificates(ctx, tracer, engine, clock, nil, v201CallMaker, nil,
+		100*time.Millisecond, 24*time.Hour, retryPolicy)
+
+	require.Len(t, v201CallMaker.callEvents, 1)
+	assert.Equal(t, &ocpp201.DeleteCertificateRequestJson{
+		CertificateHashData: ocpp201.CertificateHashDataType{
+			HashAlgorithm:  ocpp201.HashAlgorithmEnumTypeSHA256,
+			IssuerNameHash: "name001",
+			IssuerKeyHash:  "key001",
+			SerialNumber:   "01",
+		},
+	}, v201CallMaker.callEvents[0].request)
+
+	installed, err := engine.LookupChargeStationInstalledCertificates(context.Background(), "cs001")
+	require.NoError(t, err)
+	require.Len(t, installed.Certificates, 3)
+	assert.Equal(t, store.CertificateDeletionPending, installed.Certificates[0].DeletionStatus)
+	assert.Equal(t, 1, installed.Certificates[0].Attempts)
+	assert.Equal(t, now.Add(time.Hour), installed.Certificates[0].SendAfter)
+	assert.Equal(t, store.CertificateDeletionFailed, installed.Certificates[1].DeletionStatus)
+	assert.Equal(t, store.CertificateDeletionStatus(""), installed.Certificates[2].DeletionStatus)
+
+	operations, err := engine.ListChargeStationOperations(context.Background(), "cs001", 0, 10)
+	require.NoError(t, err)
+	require.Len(t, operations, 2)
+	assert.Equal(t, store.ChargeStationOperationAttempted, operations[0].Event)
+	assert.Equal(t, store.ChargeStationOperationTypeCertificateDeletion, operations[0].Type)
+	assert.Equal(t, "01", operations[0].Subject)
+	assert.Equal(t, store.ChargeStationOperationFailed, operations[1].Event)
+	assert.Equal(t, "02", operations[1].Subject)
+}
@@ -0,0 +1,108 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package sync
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"github.com/thoughtworks/maeve-csms/manager/store"
+	"golang.org/x/exp/slog"
+	"os"
+	"time"
+)
+
+// LeaseRunner runs tasks that must only run in a single manager instance at a time.
+// Each task is associated with a named lease in the store: the task only runs while
+// this instance holds the lease and is stopped (by cancelling its context) if the
+// lease is lost.
+type LeaseRunner struct {
+	leaseStore    store.LeaseStore
+	holder        string
+	leaseDuration time.Duration
+	renewEvery    time.Duration
+}
+
+// NewLeaseRunner creates a LeaseRunner that acquires leases on behalf of holder, which
+// must uniquely identify this instance. Leases are held for leaseDuration and renewed
+// every third of that duration.
+func NewLeaseRunner(leaseStore store.LeaseStore, holder string, leaseDuration time.Duration) *LeaseRunner {
+	return &LeaseRunner{
+		leaseStore:    leaseStore,
+		holder:        holder,
+		leaseDuration: leaseDuration,
+		renewEvery:    leaseDuration / 3,
+	}
+}
+
+// Run repeatedly tries to acquire the named lease and runs fn while it is held. It
+// returns (after releasing the lease) once ctx is cancelled.
+func (l *LeaseRunner) Run(ctx context.Context, name string, fn func(ctx context.Context)) {
+	for {
+		if l.acquire(ctx, name) {
+			slog.Info("acquired lease", slog.String("lease", name), slog.String("holder", l.holder))
+			l.runWhileHeld(ctx, name, fn)
+		}
+
+		select {
+		case <-ctx.Done():
+			err := l.leaseStore.ReleaseLease(context.Background(), name, l.holder)
+			if err != nil {
+				slog.Warn("release lease", slog.String("lease", name), slog.String("err", err.Error()))
+			}
+			return
+		case <-time.After(l.renewEvery):
+		}
+	}
+}
+
+func (l *LeaseRunner) runWhileHeld(ctx context.Context, name string, fn func(ctx context.Context)) {
+	taskCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		fn(taskCtx)
+	}()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-time.After(l.renewEvery):
+			if !l.acquire(ctx, name) {
+				slog.Warn("lost lease", slog.String("lease", name), slog.String("holder", l.holder))
+				cancel()
+				<-done
+				return
+			}
+		}
+	}
+}
+
+func (l *LeaseRunner) acquire(ctx context.Context, name string) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	acquired, err := l.leaseStore.AcquireLease(ctx, name, l.holder, l.leaseDuration)
+	if err != nil {
+		slog.Error("acquire lease", slog.String("lease", name), slog.String("err", err.Error()))
+		return false
+	}
+	return acquired
+}
+
+// NewLeaseHolder returns an identifier for this instance that can be used as the
+// holder of a lease: it combines the hostname with a random suffix so that it is
+// unique even if several instances share a hostname.
+func NewLeaseHolder() string {
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "manager"
+	}
+	suffix := make([]byte, 4)
+	_, _ = rand.Read(suffix)
+	return fmt.Sprintf("%s-%s", hostname, hex.EncodeToString(suffix))
+}
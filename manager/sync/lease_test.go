@@ -0,0 +1,91 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package sync_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thoughtworks/maeve-csms/manager/store/inmemory"
+	"github.com/thoughtworks/maeve-csms/manager/sync"
+	"k8s.io/utils/clock"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestLeaseRunnerOnlyRunsTaskInOneInstance(t *testing.T) {
+	engine := inmemory.NewStore(clock.RealClock{})
+
+	var running atomic.Int32
+	var replica1Ran, replica2Ran atomic.Bool
+	task := func(ran *atomic.Bool) func(ctx context.Context) {
+		return func(ctx context.Context) {
+			ran.Store(true)
+			running.Add(1)
+			defer running.Add(-1)
+			<-ctx.Done()
+		}
+	}
+
+	ctx1, cancel1 := context.WithCancel(context.Background())
+	defer cancel1()
+	ctx2, cancel2 := context.WithCancel(context.Background())
+	defer cancel2()
+
+	replica1 := sync.NewLeaseRunner(engine, "replica1", 150*time.Millisecond)
+	replica2 := sync.NewLeaseRunner(engine, "replica2", 150*time.Millisecond)
+
+	replica1Done := make(chan struct{})
+	go func() {
+		defer close(replica1Done)
+		replica1.Run(ctx1, "sync-settings", task(&replica1Ran))
+	}()
+	require.Eventually(t, replica1Ran.Load, time.Second, 10*time.Millisecond)
+	go replica2.Run(ctx2, "sync-settings", task(&replica2Ran))
+
+	time.Sleep(300 * time.Millisecond)
+	assert.Equal(t, int32(1), running.Load())
+	assert.False(t, replica2Ran.Load())
+
+	// when the first instance stops the second takes over
+	cancel1()
+	<-replica1Done
+	require.Eventually(t, replica2Ran.Load, time.Second, 10*time.Millisecond)
+	assert.Equal(t, int32(1), running.Load())
+
+	lease, err := engine.LookupLease(context.Background(), "sync-settings")
+	require.NoError(t, err)
+	require.NotNil(t, lease)
+	assert.Equal(t, "replica2", lease.Holder)
+}
+
+func TestLeaseRunnerStopsTaskWhenLeaseIsLost(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	engine := inmemory.NewStore(clock.RealClock{})
+
+	stopped := make(chan struct{})
+	replica1 := sync.NewLeaseRunner(engine, "replica1", 150*time.Millisecond)
+	go replica1.Run(ctx, "sync-settings", func(ctx context.Context) {
+		<-ctx.Done()
+		close(stopped)
+	})
+
+	require.Eventually(t, func() bool {
+		lease, err := engine.LookupLease(ctx, "sync-settings")
+		return err == nil && lease != nil && lease.Holder == "replica1"
+	}, time.Second, 10*time.Millisecond)
+
+	// simulate another instance taking the lease (e.g. after a long pause)
+	require.NoError(t, engine.ReleaseLease(ctx, "sync-settings", "replica1"))
+	acquired, err := engine.AcquireLease(ctx, "sync-settings", "replica2", time.Minute)
+	require.NoError(t, err)
+	require.True(t, acquired)
+
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("task was not stopped when the lease was lost")
+	}
+}
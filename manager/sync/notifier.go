@@ -0,0 +1,197 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package sync
+
+import (
+	"context"
+	"github.com/thoughtworks/maeve-csms/manager/handlers"
+	"github.com/thoughtworks/maeve-csms/manager/handlers/ocpp16"
+	"github.com/thoughtworks/maeve-csms/manager/handlers/ocpp201"
+	"github.com/thoughtworks/maeve-csms/manager/handlers/ocpp21"
+	"github.com/thoughtworks/maeve-csms/manager/store"
+	"github.com/thoughtworks/maeve-csms/manager/transport"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/exp/slog"
+	"k8s.io/utils/clock"
+	"time"
+)
+
+// Notifier is an implementation of handlers.SyncNotifier that delivers pending settings,
+// certificates, certificate deletions and trigger messages to a charge station as soon as it is notified of them,
+// rather than waiting for the polling loops to find them.
+type Notifier struct {
+	engine                store.Engine
+	clock                 clock.PassiveClock
+	tracer                trace.Tracer
+	v16CallMaker          handlers.CallMaker
+	dataTransferCallMaker handlers.CallMaker
+	v201CallMaker         handlers.CallMaker
+	v21CallMaker          handlers.CallMaker
+	retryPolicies         RetryPolicies
+	connectedDelay        time.Duration
+	notifications         chan notification
+}
+
+type notification struct {
+	chargeStationId string
+	connected       bool
+}
+
+type NotifierOpt func(*Notifier)
+
+// WithNotifierCallMakers overrides the call makers that are created from the emitter.
+func WithNotifierCallMakers(v16CallMaker, dataTransferCallMaker, v201CallMaker, v21CallMaker handlers.CallMaker) NotifierOpt {
+	return func(n *Notifier) {
+		n.v16CallMaker = v16CallMaker
+		n.dataTransferCallMaker = dataTransferCallMaker
+		n.v201CallMaker = v201CallMaker
+		n.v21CallMaker = v21CallMaker
+	}
+}
+
+// WithNotifierRetryPolicies sets the policies that determine when the polling loops will
+// resend a change that has not been responded to: defaults to DefaultRetryPolicies.
+func WithNotifierRetryPolicies(retryPolicies RetryPolicies) NotifierOpt {
+	return func(n *Notifier) {
+		n.retryPolicies = retryPolicies
+	}
+}
+
+// WithNotifierConnectedDelay sets how long to wait after a charge station connects
+// before sending any changes, which gives it time to complete its boot: defaults to
+// 5 seconds.
+func WithNotifierConnectedDelay(connectedDelay time.Duration) NotifierOpt {
+	return func(n *Notifier) {
+		n.connectedDelay = connectedDelay
+	}
+}
+
+// WithNotifierBufferSize sets the number of notifications that can be queued before
+// further notifications are dropped (and left to the polling loops): defaults to 1000.
+func WithNotifierBufferSize(size int) NotifierOpt {
+	return func(n *Notifier) {
+		n.notifications = make(chan notification, size)
+	}
+}
+
+func NewNotifier(engine store.Engine, clock clock.PassiveClock, tracer trace.Tracer, emitter transport.Emitter, opts ...NotifierOpt) *Notifier {
+	n := &Notifier{
+		engine:         engine,
+		clock:          clock,
+		tracer:         tracer,
+		retryPolicies:  DefaultRetryPolicies,
+		connectedDelay: 5 * time.Second,
+		notifications:  make(chan notification, 1000),
+	}
+	if emitter != nil {
+		n.v16CallMaker = ocpp16.NewCallMaker(emitter)
+		n.dataTransferCallMaker = ocpp16.NewDataTransferCallMaker(emitter)
+		n.v201CallMaker = ocpp201.NewCallMaker(emitter)
+		n.v21CallMaker = ocpp21.NewCallMaker(emitter)
+	}
+	for _, opt := range opts {
+		opt(n)
+	}
+	return n
+}
+
+func (n *Notifier) NotifyPending(_ context.Context, chargeStationId string) {
+	n.enqueue(notification{chargeStationId: chargeStationId})
+}
+
+func (n *Notifier) NotifyConnected(_ context.Context, chargeStationId string) {
+	if n.connectedDelay > 0 {
+		time.AfterFunc(n.connectedDelay, func() {
+			n.enqueue(notification{chargeStationId: chargeStationId, connected: true})
+		})
+		return
+	}
+	n.enqueue(notification{chargeStationId: chargeStationId, connected: true})
+}
+
+func (n *Notifier) enqueue(notification notification) {
+	select {
+	case n.notifications <- notification:
+	default:
+		slog.Warn("sync notification queue is full: leaving changes to be polled",
+			slog.String("chargeStationId", notification.chargeStationId))
+	}
+}
+
+// Run processes notifications until the context is cancelled.
+func (n *Notifier) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			slog.Info("shutting down sync notifier")
+			return
+		case notification := <-n.notifications:
+			n.syncChargeStation(ctx, notification.chargeStationId, notification.connected)
+		}
+	}
+}
+
+func (n *Notifier) syncChargeStation(ctx context.Context, chargeStationId string, connected bool) {
+	ctx, span := n.tracer.Start(ctx, "sync charge station", trace.WithSpanKind(trace.SpanKindInternal),
+		trace.WithAttributes(
+			attribute.String("chargeStationId", chargeStationId),
+			attribute.Bool("sync.connected", connected),
+		))
+	defer span.End()
+
+	details, err := n.engine.LookupChargeStationRuntimeDetails(ctx, chargeStationId)
+	if err != nil {
+		span.RecordError(err)
+		return
+	}
+	if details == nil {
+		// the charge station has never booted: changes will be sent once it does
+		return
+	}
+
+	if !connected {
+		connection, err := n.engine.LookupChargeStationConnection(ctx, chargeStationId)
+		if err != nil {
+			span.RecordError(err)
+			return
+		}
+		if connection != nil && !connection.Connected {
+			// changes will be sent when the charge station reconnects
+			span.SetAttributes(attribute.Bool("sync.skipped", true))
+			return
+		}
+	}
+
+	settings, err := n.engine.LookupChargeStationSettings(ctx, chargeStationId)
+	if err != nil {
+		span.RecordError(err)
+	} else if settings != nil {
+		syncChargeStationSettings(ctx, n.engine, n.clock, n.v16CallMaker, n.v201CallMaker, n.v21CallMaker, chargeStationId,
+			details, settings, n.retryPolicies.Settings, connected)
+	}
+
+	certificates, err := n.engine.LookupChargeStationInstallCertificates(ctx, chargeStationId)
+	if err != nil {
+		span.RecordError(err)
+	} else if certificates != nil {
+		syncChargeStationCertificates(ctx, n.engine, n.clock, n.dataTransferCallMaker, n.v201CallMaker, n.v21CallMaker, chargeStationId,
+			details, certificates, n.retryPolicies.Certificates, connected)
+	}
+
+	installedCertificates, err := n.engine.LookupChargeStationInstalledCertificates(ctx, chargeStationId)
+	if err != nil {
+		span.RecordError(err)
+	} else if installedCertificates != nil {
+		syncChargeStationCertificateDeletions(ctx, n.engine, n.clock, n.dataTransferCallMaker, n.v201CallMaker,
+			n.v21CallMaker, chargeStationId, details, installedCertificates, n.retryPolicies.Certificates, connected)
+	}
+
+	triggerMessages, err := n.engine.LookupChargeStationTriggerMessages(ctx, chargeStationId)
+	if err != nil {
+		span.RecordError(err)
+	} else if triggerMessages != nil {
+		syncChargeStationTriggers(ctx, n.engine, n.clock, n.v16CallMaker, n.dataTransferCallMaker, n.v201CallMaker,
+			n.v21CallMaker, chargeStationId, details, triggerMessages, n.retryPolicies.Triggers, connected)
+	}
+}
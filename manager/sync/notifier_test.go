@@ -0,0 +1,316 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package sync_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thoughtworks/maeve-csms/manager/handlers"
+	"github.com/thoughtworks/maeve-csms/manager/ocpp"
+	"github.com/thoughtworks/maeve-csms/manager/ocpp/ocpp16"
+	"github.com/thoughtworks/maeve-csms/manager/ocpp/ocpp201"
+	"github.com/thoughtworks/maeve-csms/manager/store"
+	"github.com/thoughtworks/maeve-csms/manager/store/inmemory"
+	"github.com/thoughtworks/maeve-csms/manager/sync"
+	"github.com/thoughtworks/maeve-csms/manager/testutil"
+	"github.com/thoughtworks/maeve-csms/manager/transport"
+	"k8s.io/utils/clock"
+	"testing"
+	"time"
+)
+
+func newTestNotifier(engine store.Engine, v16CallMaker, dataTransferCallMaker, v201CallMaker *mockCallMaker) *sync.Notifier {
+	tracer, _ := testutil.GetTracer()
+	return sync.NewNotifier(engine, clock.RealClock{}, tracer, nil,
+		sync.WithNotifierCallMakers(v16CallMaker, dataTransferCallMaker, v201CallMaker, nil),
+		sync.WithNotifierConnectedDelay(0))
+}
+
+func TestNotifierSendsPendingChangesImmediately(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	engine := inmemory.NewStore(clock.RealClock{})
+
+	err := engine.SetChargeStationRuntimeDetails(ctx, "cs001", &store.ChargeStationRuntimeDetails{
+		OcppVersion: "1.6",
+	})
+	require.NoError(t, err)
+	err = engine.UpdateChargeStationSettings(ctx, "cs001", &store.ChargeStationSettings{
+		Settings: map[string]*store.ChargeStationSetting{
+			"foo": {Value: "bar", Status: store.ChargeStationSettingStatusPending},
+		},
+	})
+	require.NoError(t, err)
+	err = engine.UpdateChargeStationTriggerMessages(ctx, "cs001", &store.ChargeStationTriggerMessages{
+		TriggerMessages: []*store.ChargeStationTriggerMessage{
+			{TriggerMessage: store.TriggerMessageBootNotification, TriggerStatus: store.TriggerStatusPending},
+		},
+	})
+	require.NoError(t, err)
+
+	v16CallMaker := &mockCallMaker{engine: engine}
+	notifier := newTestNotifier(engine, v16CallMaker, &mockCallMaker{engine: engine}, nil)
+
+	notifier.NotifyPending(ctx, "cs001")
+	notifier.Run(ctx)
+
+	require.Len(t, v16CallMaker.callEvents, 2)
+	assert.Equal(t, &ocpp16.ChangeConfigurationJson{Key: "foo", Value: "bar"}, v16CallMaker.callEvents[0].request)
+	assert.Equal(t, &ocpp16.TriggerMessageJson{RequestedMessage: ocpp16.TriggerMessageJsonRequestedMessageBootNotification},
+		v16CallMaker.callEvents[1].request)
+}
+
+func TestNotifierSendsCertificatesToOcpp16ChargeStationsUsingDataTransfer(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	engine := inmemory.NewStore(clock.RealClock{})
+
+	err := engine.SetChargeStationRuntimeDetails(ctx, "cs001", &store.ChargeStationRuntimeDetails{
+		OcppVersion: "1.6",
+	})
+	require.NoError(t, err)
+	err = engine.UpdateChargeStationInstallCertificates(ctx, "cs001", &store.ChargeStationInstallCertificates{
+		Certificates: []*store.ChargeStationInstallCertificate{
+			{
+				CertificateType:               store.CertificateTypeV2G,
+				CertificateId:                 "v2g001",
+				CertificateData:               "v2g-pem-data",
+				CertificateInstallationStatus: store.CertificateInstallationPending,
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	v16CallMaker := &mockCallMaker{engine: engine}
+	dataTransferCallMaker := &mockCallMaker{engine: engine}
+	notifier := newTestNotifier(engine, v16CallMaker, dataTransferCallMaker, nil)
+
+	notifier.NotifyPending(ctx, "cs001")
+	notifier.Run(ctx)
+
+	// the plain OCPP 1.6 call maker cannot send an InstallCertificate request
+	assert.Empty(t, v16CallMaker.callEvents)
+	require.Len(t, dataTransferCallMaker.callEvents, 1)
+	assert.Equal(t, &ocpp201.InstallCertificateRequestJson{
+		Certificate:     "v2g-pem-data",
+		CertificateType: ocpp201.InstallCertificateUseEnumTypeV2GRootCertificate,
+	}, dataTransferCallMaker.callEvents[0].request)
+}
+
+func TestNotifierSendsPendingCertificateDeletions(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	engine := inmemory.NewStore(clock.RealClock{})
+
+	err := engine.SetChargeStationRuntimeDetails(ctx, "cs001", &store.ChargeStationRuntimeDetails{
+		OcppVersion: "2.0.1",
+	})
+	require.NoError(t, err)
+	err = engine.SetChargeStationInstalledCertificates(ctx, "cs001", &store.ChargeStationInstalledCertificates{
+		Certificates: []*store.ChargeStationInstalledCertificate{
+			{
+				CertificateType: store.CertificateTypeV2G,
+				HashAlgorithm:   "SHA256",
+				IssuerNameHash:  "name001",
+				IssuerKeyHash:   "key001",
+				SerialNumber:    "01",
+				DeletionStatus:  store.CertificateDeletionPending,
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	v201CallMaker := &mockCallMaker{engine: engine}
+	notifier := newTestNotifier(engine, nil, nil, v201CallMaker)
+
+	notifier.NotifyPending(ctx, "cs001")
+	notifier.Run(ctx)
+
+	require.Len(t, v201CallMaker.callEvents, 1)
+	assert.Equal(t, "01", v201CallMaker.callEvents[0].request.(*ocpp201.DeleteCertificateRequestJson).CertificateHashData.SerialNumber)
+}
+
+func TestNotifierDoesNotSendToDisconnectedChargeStation(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	engine := inmemory.NewStore(clock.RealClock{})
+
+	err := engine.SetChargeStationRuntimeDetails(ctx, "cs001", &store.ChargeStationRuntimeDetails{
+		OcppVersion: "2.0.1",
+	})
+	require.NoError(t, err)
+	err = engine.SetChargeStationConnection(ctx, "cs001", &store.ChargeStationConnection{
+		Connected: false,
+	})
+	require.NoError(t, err)
+	err = engine.UpdateChargeStationSettings(ctx, "cs001", &store.ChargeStationSettings{
+		Settings: map[string]*store.ChargeStationSetting{
+			"foo/fam": {Value: "bar", Status: store.ChargeStationSettingStatusPending},
+		},
+	})
+	require.NoError(t, err)
+
+	v201CallMaker := &mockCallMaker{engine: engine}
+	notifier := newTestNotifier(engine, nil, nil, v201CallMaker)
+
+	notifier.NotifyPending(ctx, "cs001")
+	notifier.Run(ctx)
+
+	assert.Empty(t, v201CallMaker.callEvents)
+}
+
+func TestNotifierResendsOutstandingChangesWhenConnected(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	engine := inmemory.NewStore(clock.RealClock{})
+
+	err := engine.SetChargeStationRuntimeDetails(ctx, "cs001", &store.ChargeStationRuntimeDetails{
+		OcppVersion: "2.0.1",
+	})
+	require.NoError(t, err)
+	err = engine.UpdateChargeStationSettings(ctx, "cs001", &store.ChargeStationSettings{
+		Settings: map[string]*store.ChargeStationSetting{
+			"foo/fam": {Value: "bar", Status: store.ChargeStationSettingStatusPending, SendAfter: time.Now().Add(time.Hour)},
+		},
+	})
+	require.NoError(t, err)
+
+	v201CallMaker := &mockCallMaker{engine: engine}
+	notifier := newTestNotifier(engine, nil, nil, v201CallMaker)
+
+	// a pending notification respects the retry delay of changes that have already been sent...
+	notifier.NotifyPending(ctx, "cs001")
+	// ...but a connected notification does not
+	notifier.NotifyConnected(ctx, "cs001")
+	notifier.Run(ctx)
+
+	require.Len(t, v201CallMaker.callEvents, 1)
+	req, ok := v201CallMaker.callEvents[0].request.(*ocpp201.SetVariablesRequestJson)
+	require.True(t, ok)
+	require.Len(t, req.SetVariableData, 1)
+	assert.Equal(t, "foo", req.SetVariableData[0].Component.Name)
+	assert.Equal(t, "fam", req.SetVariableData[0].Variable.Name)
+	assert.Equal(t, "bar", req.SetVariableData[0].AttributeValue)
+}
+
+func TestNotifierSendsQueuedTriggersInOrder(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	engine := inmemory.NewStore(clock.RealClock{})
+
+	err := engine.SetChargeStationRuntimeDetails(ctx, "cs001", &store.ChargeStationRuntimeDetails{
+		OcppVersion: "2.0.1",
+	})
+	require.NoError(t, err)
+	evseId := 1
+	err = engine.UpdateChargeStationTriggerMessages(ctx, "cs001", &store.ChargeStationTriggerMessages{
+		TriggerMessages: []*store.ChargeStationTriggerMessage{
+			{TriggerMessage: store.TriggerMessageBootNotification, TriggerStatus: store.TriggerStatusPending},
+			{TriggerMessage: store.TriggerMessageStatusNotification, EvseId: &evseId, TriggerStatus: store.TriggerStatusPending},
+		},
+	})
+	require.NoError(t, err)
+
+	var notifier *sync.Notifier
+	v201CallMaker := &mockCallMaker{engine: engine}
+	v201CallMaker.updateFn = func(ctx context.Context, engine store.Engine, chargeStationId string, request ocpp.Request) error {
+		// the charge station accepts each trigger message when it is sent
+		req := request.(*ocpp201.TriggerMessageRequestJson)
+		result := &store.ChargeStationTriggerMessage{
+			TriggerMessage: store.TriggerMessage(req.RequestedMessage),
+			TriggerStatus:  store.TriggerStatusAccepted,
+		}
+		if req.Evse != nil {
+			result.EvseId = &req.Evse.Id
+		}
+		return handlers.RecordTriggerMessageResult(ctx, engine, notifier, chargeStationId, result)
+	}
+	notifier = newTestNotifier(engine, nil, nil, v201CallMaker)
+
+	notifier.NotifyPending(ctx, "cs001")
+	notifier.Run(ctx)
+
+	require.Len(t, v201CallMaker.callEvents, 2)
+	assert.Equal(t, &ocpp201.TriggerMessageRequestJson{
+		RequestedMessage: ocpp201.MessageTriggerEnumTypeBootNotification,
+	}, v201CallMaker.callEvents[0].request)
+	assert.Equal(t, &ocpp201.TriggerMessageRequestJson{
+		RequestedMessage: ocpp201.MessageTriggerEnumTypeStatusNotification,
+		Evse:             &ocpp201.EVSEType{Id: 1},
+	}, v201CallMaker.callEvents[1].request)
+
+	triggerMessages, err := engine.LookupChargeStationTriggerMessages(context.Background(), "cs001")
+	require.NoError(t, err)
+	assert.Nil(t, triggerMessages)
+}
+
+func TestNotifierWaitsForResponseBeforeSendingNextTrigger(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	engine := inmemory.NewStore(clock.RealClock{})
+
+	err := engine.SetChargeStationRuntimeDetails(ctx, "cs001", &store.ChargeStationRuntimeDetails{
+		OcppVersion: "2.0.1",
+	})
+	require.NoError(t, err)
+	err = engine.UpdateChargeStationTriggerMessages(ctx, "cs001", &store.ChargeStationTriggerMessages{
+		TriggerMessages: []*store.ChargeStationTriggerMessage{
+			{TriggerMessage: store.TriggerMessageBootNotification, TriggerStatus: store.TriggerStatusPending},
+			{TriggerMessage: store.TriggerMessageHeartbeat, TriggerStatus: store.TriggerStatusPending},
+		},
+	})
+	require.NoError(t, err)
+
+	v201CallMaker := &mockCallMaker{engine: engine}
+	notifier := newTestNotifier(engine, nil, nil, v201CallMaker)
+
+	notifier.NotifyPending(ctx, "cs001")
+	notifier.NotifyPending(ctx, "cs001")
+	notifier.Run(ctx)
+
+	require.Len(t, v201CallMaker.callEvents, 1)
+	assert.Equal(t, &ocpp201.TriggerMessageRequestJson{
+		RequestedMessage: ocpp201.MessageTriggerEnumTypeBootNotification,
+	}, v201CallMaker.callEvents[0].request)
+}
+
+func TestNotifierSendsCallsToOcpp21ChargeStationUsingOcpp21(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	engine := inmemory.NewStore(clock.RealClock{})
+
+	err := engine.SetChargeStationRuntimeDetails(ctx, "cs001", &store.ChargeStationRuntimeDetails{
+		OcppVersion: "2.1",
+	})
+	require.NoError(t, err)
+	err = engine.UpdateChargeStationSettings(ctx, "cs001", &store.ChargeStationSettings{
+		Settings: map[string]*store.ChargeStationSetting{
+			"foo/fam": {Value: "bar", Status: store.ChargeStationSettingStatusPending},
+		},
+	})
+	require.NoError(t, err)
+	evseId := 1
+	err = engine.UpdateChargeStationTriggerMessages(ctx, "cs001", &store.ChargeStationTriggerMessages{
+		TriggerMessages: []*store.ChargeStationTriggerMessage{
+			{TriggerMessage: store.TriggerMessageStatusNotification, EvseId: &evseId, TriggerStatus: store.TriggerStatusPending},
+		},
+	})
+	require.NoError(t, err)
+
+	var versions []transport.OcppVersion
+	var actions []string
+	emitter := transport.EmitterFunc(func(ctx context.Context, ocppVersion transport.OcppVersion, chargeStationId string, message *transport.Message) error {
+		versions = append(versions, ocppVersion)
+		actions = append(actions, message.Action)
+		return nil
+	})
+	tracer, _ := testutil.GetTracer()
+	notifier := sync.NewNotifier(engine, clock.RealClock{}, tracer, emitter, sync.WithNotifierConnectedDelay(0))
+
+	notifier.NotifyPending(ctx, "cs001")
+	notifier.Run(ctx)
+
+	assert.Equal(t, []transport.OcppVersion{transport.OcppVersion21, transport.OcppVersion21}, versions)
+	assert.Equal(t, []string{"SetVariables", "TriggerMessage"}, actions)
+}
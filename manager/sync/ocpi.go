@@ -0,0 +1,34 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package sync
+
+import (
+	"context"
+	"github.com/thoughtworks/maeve-csms/manager/ocpi"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/exp/slog"
+	"time"
+)
+
+// SyncOcpiDeliveries retries outbound OCPI requests that could not be delivered
+func SyncOcpiDeliveries(ctx context.Context,
+	tracer trace.Tracer,
+	ocpiApi ocpi.Api,
+	runEvery time.Duration) {
+	for {
+		select {
+		case <-ctx.Done():
+			slog.Info("shutting down sync ocpi deliveries")
+			return
+		case <-time.After(runEvery):
+			func() {
+				ctx, span := tracer.Start(ctx, "sync ocpi deliveries", trace.WithSpanKind(trace.SpanKindInternal))
+				defer span.End()
+				err := ocpiApi.ProcessDeliveries(ctx)
+				if err != nil {
+					span.RecordError(err)
+				}
+			}()
+		}
+	}
+}
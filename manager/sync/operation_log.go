@@ -0,0 +1,41 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package sync
+
+import (
+	"context"
+	"github.com/thoughtworks/maeve-csms/manager/store"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/exp/slog"
+	"k8s.io/utils/clock"
+	"time"
+)
+
+// SyncOperationLogRetention removes entries from the charge station operation logs once they
+// are older than retainFor.
+func SyncOperationLogRetention(ctx context.Context,
+	tracer trace.Tracer,
+	engine store.Engine,
+	clock clock.PassiveClock,
+	runEvery,
+	retainFor time.Duration) {
+	for {
+		select {
+		case <-ctx.Done():
+			slog.Info("shutting down sync operation log retention")
+			return
+		case <-time.After(runEvery):
+			func() {
+				before := clock.Now().Add(-retainFor)
+				ctx, span := tracer.Start(ctx, "sync operation log retention", trace.WithSpanKind(trace.SpanKindInternal),
+					trace.WithAttributes(attribute.String("sync.operation_log.before", before.Format(time.RFC3339))))
+				defer span.End()
+				err := engine.DeleteChargeStationOperationsBefore(ctx, before)
+				if err != nil {
+					span.RecordError(err)
+				}
+			}()
+		}
+	}
+}
@@ -0,0 +1,42 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package sync_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thoughtworks/maeve-csms/manager/store"
+	"github.com/thoughtworks/maeve-csms/manager/store/inmemory"
+	"github.com/thoughtworks/maeve-csms/manager/sync"
+	"github.com/thoughtworks/maeve-csms/manager/testutil"
+	clockTest "k8s.io/utils/clock/testing"
+	"testing"
+	"time"
+)
+
+func TestSyncOperationLogRetention(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	now := time.Date(2023, 6, 15, 15, 0, 0, 0, time.UTC)
+	clock := clockTest.NewFakePassiveClock(now)
+	engine := inmemory.NewStore(clock)
+
+	for _, op := range []*store.ChargeStationOperation{
+		{ChargeStationId: "cs001", Timestamp: now.Add(-25 * time.Hour), Event: store.ChargeStationOperationSent, Action: "Reset"},
+		{ChargeStationId: "cs001", Timestamp: now.Add(-time.Hour), Event: store.ChargeStationOperationSent, Action: "TriggerMessage"},
+	} {
+		err := engine.AppendChargeStationOperation(ctx, op)
+		require.NoError(t, err)
+	}
+
+	tracer, _ := testutil.GetTracer()
+
+	sync.SyncOperationLogRetention(ctx, tracer, engine, clock, 100*time.Millisecond, 24*time.Hour)
+
+	got, err := engine.ListChargeStationOperations(context.Background(), "cs001", 0, 10)
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, "TriggerMessage", got[0].Action)
+}
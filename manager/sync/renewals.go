@@ -0,0 +1,134 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package sync
+
+import (
+	"context"
+	"github.com/thoughtworks/maeve-csms/manager/handlers"
+	"github.com/thoughtworks/maeve-csms/manager/store"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/exp/slog"
+	"k8s.io/utils/clock"
+	"time"
+)
+
+// CertificateRenewalPolicy determines when a charge station is asked to renew a certificate that
+// has been issued to it. The charge station is first asked LeadTime before the certificate expires
+// and is asked again every RetryInterval until a new certificate has been issued.
+type CertificateRenewalPolicy struct {
+	LeadTime      time.Duration
+	RetryInterval time.Duration
+}
+
+// DefaultCertificateRenewalPolicy asks for renewal 30 days before expiry and repeats the
+// request daily.
+var DefaultCertificateRenewalPolicy = CertificateRenewalPolicy{
+	LeadTime:      30 * 24 * time.Hour,
+	RetryInterval: 24 * time.Hour,
+}
+
+// certificateRenewalRequester identifies renewals in the charge station's operation log
+const certificateRenewalRequester = "certificate-renewal"
+
+// SyncCertificateRenewals queues a SignChargingStationCertificate or SignV2GCertificate trigger
+// message for each charge station that holds an issued certificate that is due for renewal. The
+// notifier (which is optional) is told so that the trigger message is sent immediately.
+func SyncCertificateRenewals(ctx context.Context,
+	tracer trace.Tracer,
+	engine store.Engine,
+	clock clock.PassiveClock,
+	notifier handlers.SyncNotifier,
+	runEvery time.Duration,
+	policy CertificateRenewalPolicy) {
+	for {
+		select {
+		case <-ctx.Done():
+			slog.Info("shutting down sync certificate renewals")
+			return
+		case <-time.After(runEvery):
+			func() {
+				ctx, span := tracer.Start(ctx, "sync certificate renewals", trace.WithSpanKind(trace.SpanKindInternal))
+				defer span.End()
+				now := clock.Now()
+				renewals := 0
+				for offset := 0; ; offset += 50 {
+					certificates, err := engine.ListExpiringIssuedCertificates(ctx, now.Add(policy.LeadTime), offset, 50)
+					if err != nil {
+						span.RecordError(err)
+						return
+					}
+					for _, certificate := range certificates {
+						if !certificate.RenewalRequestedAt.IsZero() && now.Before(certificate.RenewalRequestedAt.Add(policy.RetryInterval)) {
+							continue
+						}
+						requested, err := requestCertificateRenewal(ctx, engine, clock, certificate)
+						if err != nil {
+							span.RecordError(err)
+							continue
+						}
+						if requested {
+							renewals++
+							if notifier != nil {
+								notifier.NotifyPending(ctx, certificate.ChargeStationId)
+							}
+						}
+					}
+					if len(certificates) < 50 {
+						break
+					}
+				}
+				span.SetAttributes(attribute.Int("sync.renewal.count", renewals))
+			}()
+		}
+	}
+}
+
+// requestCertificateRenewal queues the trigger message that asks the charge station to renew the
+// certificate. It returns false if the charge station is not known to the manager.
+func requestCertificateRenewal(ctx context.Context,
+	engine store.Engine,
+	clock clock.PassiveClock,
+	certificate *store.IssuedCertificate) (bool, error) {
+	details, err := engine.LookupChargeStationRuntimeDetails(ctx, certificate.ChargeStationId)
+	if err != nil {
+		return false, err
+	}
+	if details == nil {
+		// the charge station has been deleted
+		return false, nil
+	}
+
+	triggerMessage := &store.ChargeStationTriggerMessage{
+		TriggerMessage: store.TriggerMessageSignV2GCertificate,
+		TriggerStatus:  store.TriggerStatusPending,
+	}
+	if certificate.CertificateType == store.CertificateTypeChargeStation {
+		triggerMessage.TriggerMessage = store.TriggerMessageSignChargingStationCertificate
+	}
+	err = engine.UpdateChargeStationTriggerMessages(ctx, certificate.ChargeStationId, &store.ChargeStationTriggerMessages{
+		TriggerMessages: []*store.ChargeStationTriggerMessage{triggerMessage},
+	})
+	if err != nil {
+		return false, err
+	}
+
+	certificate.RenewalRequestedAt = clock.Now().UTC()
+	err = engine.SetIssuedCertificate(ctx, certificate)
+	if err != nil {
+		return false, err
+	}
+
+	handlers.RecordOperation(ctx, engine, &store.ChargeStationOperation{
+		ChargeStationId: certificate.ChargeStationId,
+		Timestamp:       certificate.RenewalRequestedAt,
+		Event:           store.ChargeStationOperationRequested,
+		Type:            store.ChargeStationOperationTypeTrigger,
+		Subject:         triggerMessage.Name(),
+		RequestedBy:     certificateRenewalRequester,
+	})
+
+	slog.Info("requested certificate renewal", "chargeStationId", certificate.ChargeStationId,
+		"certificateId", certificate.CertificateId, "notAfter", certificate.NotAfter)
+	return true, nil
+}
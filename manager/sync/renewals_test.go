@@ -0,0 +1,126 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package sync_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thoughtworks/maeve-csms/manager/store"
+	"github.com/thoughtworks/maeve-csms/manager/store/inmemory"
+	"github.com/thoughtworks/maeve-csms/manager/sync"
+	"github.com/thoughtworks/maeve-csms/manager/testutil"
+	clockTest "k8s.io/utils/clock/testing"
+	"testing"
+	"time"
+)
+
+type recordingSyncNotifier struct {
+	pending []string
+}
+
+func (r *recordingSyncNotifier) NotifyPending(_ context.Context, chargeStationId string) {
+	r.pending = append(r.pending, chargeStationId)
+}
+
+func (r *recordingSyncNotifier) NotifyConnected(context.Context, string) {}
+
+func TestSyncCertificateRenewals(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+
+	now := time.Date(2023, 6, 15, 15, 0, 0, 0, time.UTC)
+	clock := clockTest.NewFakePassiveClock(now)
+	engine := inmemory.NewStore(clock)
+
+	for _, csId := range []string{"cs001", "cs002", "cs003"} {
+		err := engine.SetChargeStationRuntimeDetails(ctx, csId, &store.ChargeStationRuntimeDetails{OcppVersion: "2.0.1"})
+		require.NoError(t, err)
+	}
+
+	certificates := []*store.IssuedCertificate{
+		{
+			// due for renewal
+			CertificateId:   "cert001",
+			ChargeStationId: "cs001",
+			CertificateType: store.CertificateTypeChargeStation,
+			NotAfter:        now.Add(7 * 24 * time.Hour),
+		},
+		{
+			// due for renewal again
+			CertificateId:      "cert002",
+			ChargeStationId:    "cs002",
+			CertificateType:    store.CertificateTypeEVCC,
+			NotAfter:           now.Add(7 * 24 * time.Hour),
+			RenewalRequestedAt: now.Add(-25 * time.Hour),
+		},
+		{
+			// renewal recently requested
+			CertificateId:      "cert003",
+			ChargeStationId:    "cs003",
+			CertificateType:    store.CertificateTypeChargeStation,
+			NotAfter:           now.Add(7 * 24 * time.Hour),
+			RenewalRequestedAt: now.Add(-time.Hour),
+		},
+		{
+			// not yet due
+			CertificateId:   "cert004",
+			ChargeStationId: "cs003",
+			CertificateType: store.CertificateTypeEVCC,
+			NotAfter:        now.Add(60 * 24 * time.Hour),
+		},
+		{
+			// superseded
+			CertificateId:   "cert005",
+			ChargeStationId: "cs003",
+			CertificateType: store.CertificateTypeEVCC,
+			NotAfter:        now.Add(24 * time.Hour),
+			Superseded:      true,
+		},
+		{
+			// charge station has been deleted
+			CertificateId:   "cert006",
+			ChargeStationId: "cs004",
+			CertificateType: store.CertificateTypeChargeStation,
+			NotAfter:        now.Add(24 * time.Hour),
+		},
+	}
+	for _, certificate := range certificates {
+		err := engine.SetIssuedCertificate(ctx, certificate)
+		require.NoError(t, err)
+	}
+
+	tracer, _ := testutil.GetTracer()
+	notifier := &recordingSyncNotifier{}
+
+	sync.SyncCertificateRenewals(ctx, tracer, engine, clock, notifier, 100*time.Millisecond, sync.DefaultCertificateRenewalPolicy)
+
+	// the clock does not move, so each certificate is only renewed once
+	assert.ElementsMatch(t, []string{"cs001", "cs002"}, notifier.pending)
+
+	triggers, err := engine.LookupChargeStationTriggerMessages(context.Background(), "cs001")
+	require.NoError(t, err)
+	require.Len(t, triggers.TriggerMessages, 1)
+	assert.Equal(t, store.TriggerMessageSignChargingStationCertificate, triggers.TriggerMessages[0].TriggerMessage)
+	assert.Equal(t, store.TriggerStatusPending, triggers.TriggerMessages[0].TriggerStatus)
+
+	triggers, err = engine.LookupChargeStationTriggerMessages(context.Background(), "cs002")
+	require.NoError(t, err)
+	require.Len(t, triggers.TriggerMessages, 1)
+	assert.Equal(t, store.TriggerMessageSignV2GCertificate, triggers.TriggerMessages[0].TriggerMessage)
+
+	triggers, err = engine.LookupChargeStationTriggerMessages(context.Background(), "cs003")
+	require.NoError(t, err)
+	assert.Nil(t, triggers)
+
+	certificate, err := engine.LookupIssuedCertificate(context.Background(), "cert001")
+	require.NoError(t, err)
+	assert.Equal(t, now, certificate.RenewalRequestedAt)
+
+	operations, err := engine.ListChargeStationOperations(context.Background(), "cs001", 0, 10)
+	require.NoError(t, err)
+	require.Len(t, operations, 1)
+	assert.Equal(t, store.ChargeStationOperationRequested, operations[0].Event)
+	assert.Equal(t, "SignChargingStationCertificate", operations[0].Subject)
+	assert.Equal(t, "certificate-renewal", operations[0].RequestedBy)
+}
@@ -0,0 +1,68 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package sync
+
+import (
+	"context"
+	"github.com/thoughtworks/maeve-csms/manager/store"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/exp/slog"
+	"k8s.io/utils/clock"
+	"time"
+)
+
+// SyncReservations expires reservations that have passed their expiry date without being
+// used and removes reservations that have been finished with for longer than retainFor.
+// Charge stations expire reservations themselves, so no messages are sent.
+func SyncReservations(ctx context.Context,
+	tracer trace.Tracer,
+	engine store.Engine,
+	clock clock.PassiveClock,
+	runEvery,
+	retainFor time.Duration) {
+	var previousReservationId int
+	for {
+		select {
+		case <-ctx.Done():
+			slog.Info("shutting down sync reservations")
+			return
+		case <-time.After(runEvery):
+			func() {
+				ctx, span := tracer.Start(ctx, "sync reservations", trace.WithSpanKind(trace.SpanKindInternal),
+					trace.WithAttributes(attribute.Int("sync.reservation.previous", previousReservationId)))
+				defer span.End()
+				reservations, err := engine.ListReservations(ctx, 50, previousReservationId)
+				if err != nil {
+					span.RecordError(err)
+					return
+				}
+				if len(reservations) > 0 {
+					previousReservationId = reservations[len(reservations)-1].ReservationId
+				} else {
+					previousReservationId = 0
+				}
+				span.SetAttributes(attribute.Int("sync.reservation.count", len(reservations)))
+				now := clock.Now()
+				for _, reservation := range reservations {
+					if now.Before(reservation.ExpiryDate) {
+						continue
+					}
+					var err error
+					switch reservation.Status {
+					case store.ReservationStatusPending, store.ReservationStatusAccepted:
+						reservation.Status = store.ReservationStatusExpired
+						err = engine.SetReservation(ctx, reservation)
+					default:
+						if now.After(reservation.ExpiryDate.Add(retainFor)) {
+							err = engine.DeleteReservation(ctx, reservation.ReservationId)
+						}
+					}
+					if err != nil {
+						span.RecordError(err)
+					}
+				}
+			}()
+		}
+	}
+}
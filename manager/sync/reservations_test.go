@@ -0,0 +1,76 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package sync_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thoughtworks/maeve-csms/manager/store"
+	"github.com/thoughtworks/maeve-csms/manager/store/inmemory"
+	"github.com/thoughtworks/maeve-csms/manager/sync"
+	"github.com/thoughtworks/maeve-csms/manager/testutil"
+	clockTest "k8s.io/utils/clock/testing"
+	"testing"
+	"time"
+)
+
+func TestSyncReservations(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	now := time.Date(2023, 6, 15, 15, 0, 0, 0, time.UTC)
+	clock := clockTest.NewFakePassiveClock(now)
+	engine := inmemory.NewStore(clock)
+
+	reservations := []*store.Reservation{
+		{
+			ReservationId:   1,
+			ChargeStationId: "cs001",
+			ExpiryDate:      now.Add(time.Hour),
+			Status:          store.ReservationStatusAccepted,
+		},
+		{
+			ReservationId:   2,
+			ChargeStationId: "cs001",
+			ExpiryDate:      now.Add(-time.Minute),
+			Status:          store.ReservationStatusAccepted,
+		},
+		{
+			ReservationId:   3,
+			ChargeStationId: "cs001",
+			ExpiryDate:      now.Add(-time.Minute),
+			Status:          store.ReservationStatusUsed,
+		},
+		{
+			ReservationId:   4,
+			ChargeStationId: "cs001",
+			ExpiryDate:      now.Add(-2 * time.Hour),
+			Status:          store.ReservationStatusCancelled,
+		},
+	}
+	for _, reservation := range reservations {
+		err := engine.SetReservation(ctx, reservation)
+		require.NoError(t, err)
+	}
+
+	tracer, _ := testutil.GetTracer()
+
+	sync.SyncReservations(ctx, tracer, engine, clock, 100*time.Millisecond, time.Hour)
+
+	got, err := engine.LookupReservation(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, store.ReservationStatusAccepted, got.Status)
+
+	got, err = engine.LookupReservation(context.Background(), 2)
+	require.NoError(t, err)
+	assert.Equal(t, store.ReservationStatusExpired, got.Status)
+
+	got, err = engine.LookupReservation(context.Background(), 3)
+	require.NoError(t, err)
+	assert.Equal(t, store.ReservationStatusUsed, got.Status)
+
+	got, err = engine.LookupReservation(context.Background(), 4)
+	require.NoError(t, err)
+	assert.Nil(t, got)
+}
@@ -0,0 +1,107 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package sync
+
+import (
+	"context"
+	"github.com/thoughtworks/maeve-csms/manager/handlers"
+	"github.com/thoughtworks/maeve-csms/manager/store"
+	"k8s.io/utils/clock"
+	"math"
+	"time"
+)
+
+// RetryPolicy determines how often a change is resent to a charge station that has not
+// responded to it. The delay after the first attempt is InitialInterval and it grows by
+// Multiplier after each subsequent attempt up to MaxInterval. Once MaxAttempts attempts
+// have been made (and the delay after the final attempt has passed) the change is marked
+// as Failed: a MaxAttempts of 0 means that the change is retried forever.
+type RetryPolicy struct {
+	InitialInterval time.Duration
+	MaxInterval     time.Duration
+	Multiplier      float64
+	MaxAttempts     int
+}
+
+// RetryPolicies holds the retry policy for each type of operation.
+type RetryPolicies struct {
+	Settings     RetryPolicy
+	Certificates RetryPolicy
+	Triggers     RetryPolicy
+}
+
+// DefaultRetryPolicy retries after 2 minutes, doubling the delay each time up to an
+// hour, and gives up after 10 attempts.
+var DefaultRetryPolicy = RetryPolicy{
+	InitialInterval: 2 * time.Minute,
+	MaxInterval:     time.Hour,
+	Multiplier:      2,
+	MaxAttempts:     10,
+}
+
+var DefaultRetryPolicies = RetryPolicies{
+	Settings:     DefaultRetryPolicy,
+	Certificates: DefaultRetryPolicy,
+	Triggers:     DefaultRetryPolicy,
+}
+
+// FixedRetryPolicy returns a policy that retries every interval forever.
+func FixedRetryPolicy(interval time.Duration) RetryPolicy {
+	return RetryPolicy{
+		InitialInterval: interval,
+		MaxInterval:     interval,
+		Multiplier:      1,
+	}
+}
+
+// Backoff returns how long to wait for a response to an attempt when attempts
+// previous attempts have already been made.
+func (p RetryPolicy) Backoff(attempts int) time.Duration {
+	backoff := float64(p.InitialInterval)
+	if p.Multiplier > 1 {
+		backoff *= math.Pow(p.Multiplier, float64(attempts))
+	}
+	if p.MaxInterval > 0 && backoff > float64(p.MaxInterval) {
+		return p.MaxInterval
+	}
+	if backoff > math.MaxInt64 {
+		return time.Duration(math.MaxInt64)
+	}
+	return time.Duration(backoff)
+}
+
+// PollInterval returns how often the safety-net loop that resends changes using this
+// policy should run: the configured interval, but never longer than InitialInterval so
+// that a change whose notification was lost is retried when the policy says it should be.
+func (p RetryPolicy) PollInterval(interval time.Duration) time.Duration {
+	if p.InitialInterval > 0 && (interval <= 0 || interval > p.InitialInterval) {
+		return p.InitialInterval
+	}
+	return interval
+}
+
+// Exhausted returns true if no further attempts should be made after attempts
+// attempts have been made.
+func (p RetryPolicy) Exhausted(attempts int) bool {
+	return p.MaxAttempts > 0 && attempts >= p.MaxAttempts
+}
+
+// recordAttempt records an attempt to deliver a change to a charge station, or the decision to
+// give up on it, in the charge station's operation log.
+func recordAttempt(ctx context.Context,
+	operationStore store.ChargeStationOperationStore,
+	clock clock.PassiveClock,
+	csId string,
+	event store.ChargeStationOperationEvent,
+	operationType store.ChargeStationOperationType,
+	subject string,
+	attempt int) {
+	handlers.RecordOperation(ctx, operationStore, &store.ChargeStationOperation{
+		ChargeStationId: csId,
+		Timestamp:       clock.Now().UTC(),
+		Event:           event,
+		Type:            operationType,
+		Subject:         subject,
+		Attempt:         attempt,
+	})
+}
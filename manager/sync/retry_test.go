@@ -0,0 +1,43 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package sync_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/thoughtworks/maeve-csms/manager/sync"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := sync.RetryPolicy{
+		InitialInterval: 2 * time.Minute,
+		MaxInterval:     time.Hour,
+		Multiplier:      2,
+		MaxAttempts:     10,
+	}
+
+	assert.Equal(t, 2*time.Minute, policy.Backoff(0))
+	assert.Equal(t, 4*time.Minute, policy.Backoff(1))
+	assert.Equal(t, 32*time.Minute, policy.Backoff(4))
+	assert.Equal(t, time.Hour, policy.Backoff(5))
+	assert.Equal(t, time.Hour, policy.Backoff(1000))
+}
+
+func TestRetryPolicyExhausted(t *testing.T) {
+	policy := sync.RetryPolicy{InitialInterval: time.Minute, MaxAttempts: 3}
+	assert.False(t, policy.Exhausted(0))
+	assert.False(t, policy.Exhausted(2))
+	assert.True(t, policy.Exhausted(3))
+
+	fixed := sync.FixedRetryPolicy(time.Minute)
+	assert.False(t, fixed.Exhausted(1000))
+	assert.Equal(t, time.Minute, fixed.Backoff(1000))
+}
+
+func TestRetryPolicyPollInterval(t *testing.T) {
+	policy := sync.RetryPolicy{InitialInterval: 2 * time.Minute}
+	assert.Equal(t, time.Minute, policy.PollInterval(time.Minute))
+	assert.Equal(t, 2*time.Minute, policy.PollInterval(10*time.Minute))
+	assert.Equal(t, 2*time.Minute, policy.PollInterval(0))
+}
@@ -16,7 +16,7 @@ import (
 	"time"
 )
 
-func SyncSettings(ctx context.Context, engine store.Engine, clock clock.PassiveClock, v16CallMaker, v201CallMaker handlers.CallMaker, runEvery time.Duration, retryAfter time.Duration) {
+func SyncSettings(ctx context.Context, engine store.Engine, clock clock.PassiveClock, v16CallMaker, v201CallMaker, v21CallMaker handlers.CallMaker, runEvery time.Duration, retryPolicy RetryPolicy) {
 	var previousChargeStationId string
 	for {
 		select {
@@ -41,74 +41,81 @@ func SyncSettings(ctx context.Context, engine store.Engine, clock clock.PassiveC
 				if err != nil {
 					slog.Error("lookup charge station runtime details", slog.String("err", err.Error()),
 						slog.String("chargeStationId", pendingSetting.ChargeStationId))
+					continue
 				}
-				csId := pendingSetting.ChargeStationId
-				switch details.OcppVersion {
-				case "1.6":
-					for name, setting := range pendingSetting.Settings {
-						if setting.Status == store.ChargeStationSettingStatusPending && clock.Now().After(setting.SendAfter) {
-							slog.Info("updating charge station settings", slog.String("chargeStationId", csId),
-								slog.String("key", name),
-								slog.String("value", setting.Value),
-								slog.String("OcppVersion", details.OcppVersion))
-							err = engine.UpdateChargeStationSettings(ctx, csId, &store.ChargeStationSettings{
-								Settings: map[string]*store.ChargeStationSetting{
-									name: {Status: setting.Status, Value: setting.Value, SendAfter: clock.Now().Add(retryAfter)},
-								},
-							})
-							if err != nil {
-								slog.Error("update charge station settings", slog.String("err", err.Error()))
-								continue
-							}
-							req := &ocpp16.ChangeConfigurationJson{
-								Key:   name,
-								Value: setting.Value,
-							}
-							err := v16CallMaker.Send(ctx, csId, req)
-							if err != nil {
-								slog.Error("send change configuration request", slog.String("err", err.Error()),
-									slog.String("chargeStationId", csId), slog.String("key", name), slog.String("value", setting.Value))
-							}
-						}
-					}
-				case "2.0.1":
-					var variables []ocpp201.SetVariableDataType
-					for name, setting := range pendingSetting.Settings {
-						slog.Info("updating charge station settings", slog.String("chargeStationId", csId),
-							slog.String("key", name),
-							slog.String("value", setting.Value),
-							slog.String("OcppVersion", details.OcppVersion))
-						if setting.Status == store.ChargeStationSettingStatusPending && clock.Now().After(setting.SendAfter) {
-							err = engine.UpdateChargeStationSettings(ctx, csId, &store.ChargeStationSettings{
-								Settings: map[string]*store.ChargeStationSetting{
-									name: {Status: setting.Status, Value: setting.Value, SendAfter: clock.Now().Add(retryAfter)},
-								},
-							})
-							if err != nil {
-								slog.Error("update charge station settings", slog.String("err", err.Error()))
-								continue
-							}
-							var variable ocpp201.SetVariableDataType
-							err = parseOcpp201Name(name, &variable)
-							if err != nil {
-								slog.Error("parse ocpp 2.0.1 name", slog.String("err", err.Error()))
-								continue
-							}
-							variable.AttributeValue = setting.Value
-							variables = append(variables, variable)
-						}
-					}
-					if len(variables) > 0 {
-						req := &ocpp201.SetVariablesRequestJson{
-							SetVariableData: variables,
-						}
-						err = v201CallMaker.Send(ctx, csId, req)
-						if err != nil {
-							slog.Error("send set variables request", slog.String("err", err.Error()),
-								slog.String("chargeStationId", csId))
-						}
-					}
+				if details == nil {
+					continue
 				}
+				syncChargeStationSettings(ctx, engine, clock, v16CallMaker, v201CallMaker, v21CallMaker, pendingSetting.ChargeStationId,
+					details, pendingSetting, retryPolicy, false)
+			}
+		}
+	}
+}
+
+// syncChargeStationSettings sends the pending settings for a single charge station. Settings
+// are only sent once their SendAfter time has passed unless force is set: settings that have
+// exhausted the retry policy are marked as Failed instead.
+func syncChargeStationSettings(ctx context.Context,
+	engine store.Engine,
+	clock clock.PassiveClock,
+	v16CallMaker,
+	v201CallMaker,
+	v21CallMaker handlers.CallMaker,
+	csId string,
+	details *store.ChargeStationRuntimeDetails,
+	pendingSetting *store.ChargeStationSettings,
+	retryPolicy RetryPolicy,
+	force bool) {
+	var err error
+	switch details.OcppVersion {
+	case "1.6":
+		for name, setting := range pendingSetting.Settings {
+			if setting.Status == store.ChargeStationSettingStatusPending && (force || clock.Now().After(setting.SendAfter)) {
+				if !startSettingAttempt(ctx, engine, clock, csId, name, setting, details, retryPolicy) {
+					continue
+				}
+				req := &ocpp16.ChangeConfigurationJson{
+					Key:   name,
+					Value: setting.Value,
+				}
+				err := v16CallMaker.Send(ctx, csId, req)
+				if err != nil {
+					slog.Error("send change configuration request", slog.String("err", err.Error()),
+						slog.String("chargeStationId", csId), slog.String("key", name), slog.String("value", setting.Value))
+				}
+			}
+		}
+	case "2.0.1", "2.1":
+		// SetVariables is unchanged in OCPP 2.1
+		var variables []ocpp201.SetVariableDataType
+		for name, setting := range pendingSetting.Settings {
+			if setting.Status == store.ChargeStationSettingStatusPending && (force || clock.Now().After(setting.SendAfter)) {
+				if !startSettingAttempt(ctx, engine, clock, csId, name, setting, details, retryPolicy) {
+					continue
+				}
+				var variable ocpp201.SetVariableDataType
+				err = parseOcpp201Name(name, &variable)
+				if err != nil {
+					slog.Error("parse ocpp 2.0.1 name", slog.String("err", err.Error()))
+					continue
+				}
+				variable.AttributeValue = setting.Value
+				variables = append(variables, variable)
+			}
+		}
+		if len(variables) > 0 {
+			req := &ocpp201.SetVariablesRequestJson{
+				SetVariableData: variables,
+			}
+			callMaker := v201CallMaker
+			if details.OcppVersion == "2.1" {
+				callMaker = v21CallMaker
+			}
+			err = callMaker.Send(ctx, csId, req)
+			if err != nil {
+				slog.Error("send set variables request", slog.String("err", err.Error()),
+					slog.String("chargeStationId", csId))
 			}
 		}
 	}
@@ -157,6 +164,61 @@ func parseOcpp201Name(name string, set *ocpp201.SetVariableDataType) error {
 	return nil
 }
 
+// startSettingAttempt records that the setting is about to be sent to the charge station.
+// If the retry policy has been exhausted the setting is marked as Failed and false is
+// returned to indicate that it should not be sent.
+func startSettingAttempt(ctx context.Context,
+	engine store.Engine,
+	clock clock.PassiveClock,
+	csId, name string,
+	setting *store.ChargeStationSetting,
+	details *store.ChargeStationRuntimeDetails,
+	retryPolicy RetryPolicy) bool {
+	if retryPolicy.Exhausted(setting.Attempts) {
+		slog.Warn("charge station setting failed", slog.String("chargeStationId", csId),
+			slog.String("key", name),
+			slog.Int("attempts", setting.Attempts))
+		err := engine.UpdateChargeStationSettings(ctx, csId, &store.ChargeStationSettings{
+			Settings: map[string]*store.ChargeStationSetting{
+				name: {
+					Status:    store.ChargeStationSettingStatusFailed,
+					Value:     setting.Value,
+					SendAfter: setting.SendAfter,
+					Attempts:  setting.Attempts,
+				},
+			},
+		})
+		if err != nil {
+			slog.Error("update charge station settings", slog.String("err", err.Error()))
+		}
+		recordAttempt(ctx, engine, clock, csId, store.ChargeStationOperationFailed,
+			store.ChargeStationOperationTypeSetting, name, setting.Attempts)
+		return false
+	}
+
+	slog.Info("updating charge station settings", slog.String("chargeStationId", csId),
+		slog.String("key", name),
+		slog.String("value", setting.Value),
+		slog.String("OcppVersion", details.OcppVersion))
+	err := engine.UpdateChargeStationSettings(ctx, csId, &store.ChargeStationSettings{
+		Settings: map[string]*store.ChargeStationSetting{
+			name: {
+				Status:    setting.Status,
+				Value:     setting.Value,
+				SendAfter: clock.Now().Add(retryPolicy.Backoff(setting.Attempts)),
+				Attempts:  setting.Attempts + 1,
+			},
+		},
+	})
+	if err != nil {
+		slog.Error("update charge station settings", slog.String("err", err.Error()))
+		return false
+	}
+	recordAttempt(ctx, engine, clock, csId, store.ChargeStationOperationAttempted,
+		store.ChargeStationOperationTypeSetting, name, setting.Attempts+1)
+	return true
+}
+
 func filterPendingSettings(settings []*store.ChargeStationSettings) []*store.ChargeStationSettings {
 	var pendingSettings []*store.ChargeStationSettings
 	for _, setting := range settings {
@@ -127,7 +127,7 @@ func TestSyncV16Settings(t *testing.T) {
 	require.NoError(t, err)
 
 	v16CallMaker := &mockCallMaker{engine: engine, updateFn: updateV16StatusToAccepted}
-	sync.SyncSettings(ctx, engine, clock.RealClock{}, v16CallMaker, nil, 100*time.Millisecond, 500*time.Millisecond)
+	sync.SyncSettings(ctx, engine, clock.RealClock{}, v16CallMaker, nil, nil, 100*time.Millisecond, sync.FixedRetryPolicy(500*time.Millisecond))
 
 	settings, err := engine.LookupChargeStationSettings(ctx, "cs001")
 	require.NoError(t, err)
@@ -163,7 +163,7 @@ func TestSyncV16SettingsRetryAfterDelay(t *testing.T) {
 
 	updater := updateWithNoResponse{}
 	v16CallMaker := &mockCallMaker{engine: engine, updateFn: updater.update}
-	sync.SyncSettings(ctx, engine, clock.RealClock{}, v16CallMaker, nil, 100*time.Millisecond, 400*time.Millisecond)
+	sync.SyncSettings(ctx, engine, clock.RealClock{}, v16CallMaker, nil, nil, 100*time.Millisecond, sync.FixedRetryPolicy(400*time.Millisecond))
 
 	require.Equal(t, 3, len(updater.updateAttempts))
 	assert.True(t, updater.updateAttempts[1].After(updater.updateAttempts[0].Add(400*time.Millisecond)))
@@ -198,7 +198,7 @@ func TestSyncV201Variables(t *testing.T) {
 	require.NoError(t, err)
 
 	v201CallMaker := &mockCallMaker{engine: engine, updateFn: updateV201StatusToAccepted}
-	sync.SyncSettings(ctx, engine, clock.RealClock{}, nil, v201CallMaker, 100*time.Millisecond, 500*time.Millisecond)
+	sync.SyncSettings(ctx, engine, clock.RealClock{}, nil, v201CallMaker, nil, 100*time.Millisecond, sync.FixedRetryPolicy(500*time.Millisecond))
 
 	settings, err := engine.LookupChargeStationSettings(ctx, "cs001")
 	require.NoError(t, err)
@@ -215,6 +215,33 @@ func TestSyncV201Variables(t *testing.T) {
 	}
 }
 
+func TestSyncV21Variables(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+	engine := inmemory.NewStore(clock.RealClock{})
+
+	err := engine.SetChargeStationRuntimeDetails(ctx, "cs001", &store.ChargeStationRuntimeDetails{
+		OcppVersion: "2.1",
+	})
+	require.NoError(t, err)
+	err = engine.UpdateChargeStationSettings(ctx, "cs001", &store.ChargeStationSettings{
+		Settings: map[string]*store.ChargeStationSetting{
+			"foo/fam": {Value: "bar", Status: "Pending"},
+		},
+	})
+	require.NoError(t, err)
+
+	v201CallMaker := &mockCallMaker{engine: engine}
+	v21CallMaker := &mockCallMaker{engine: engine, updateFn: updateV201StatusToAccepted}
+	sync.SyncSettings(ctx, engine, clock.RealClock{}, nil, v201CallMaker, v21CallMaker, 100*time.Millisecond, sync.FixedRetryPolicy(500*time.Millisecond))
+
+	assert.Empty(t, v201CallMaker.callEvents)
+	require.Len(t, v21CallMaker.callEvents, 1)
+	settings, err := engine.LookupChargeStationSettings(ctx, "cs001")
+	require.NoError(t, err)
+	assert.Equal(t, store.ChargeStationSettingStatusAccepted, settings.Settings["foo/fam"].Status)
+}
+
 func TestSyncV201SettingsRetryAfterDelay(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
 	defer cancel()
@@ -234,7 +261,7 @@ func TestSyncV201SettingsRetryAfterDelay(t *testing.T) {
 
 	updater := updateWithNoResponse{}
 	v201CallMaker := &mockCallMaker{engine: engine, updateFn: updater.update}
-	sync.SyncSettings(ctx, engine, clock.RealClock{}, nil, v201CallMaker, 100*time.Millisecond, 400*time.Millisecond)
+	sync.SyncSettings(ctx, engine, clock.RealClock{}, nil, v201CallMaker, nil, 100*time.Millisecond, sync.FixedRetryPolicy(400*time.Millisecond))
 
 	require.Equal(t, 3, len(updater.updateAttempts))
 	assert.True(t, updater.updateAttempts[1].After(updater.updateAttempts[0].Add(400*time.Millisecond)))
@@ -289,7 +316,7 @@ func TestSyncSettingsWithManyChargeStations(t *testing.T) {
 
 	v16CallMaker := &mockCallMaker{engine: engine, updateFn: updateV16StatusToAccepted}
 	v201CallMaker := &mockCallMaker{engine: engine, updateFn: updateV201StatusToAccepted}
-	sync.SyncSettings(ctx, engine, clock.RealClock{}, v16CallMaker, v201CallMaker, 100*time.Millisecond, 500*time.Millisecond)
+	sync.SyncSettings(ctx, engine, clock.RealClock{}, v16CallMaker, v201CallMaker, nil, 100*time.Millisecond, sync.FixedRetryPolicy(500*time.Millisecond))
 
 	settings, err := engine.LookupChargeStationSettings(ctx, "cs133")
 	require.NoError(t, err)
@@ -305,3 +332,57 @@ func TestSyncSettingsWithManyChargeStations(t *testing.T) {
 		assert.Equal(t, store.ChargeStationSettingStatusAccepted, v.Status)
 	}
 }
+
+func TestSyncSettingsFailsAfterMaxAttempts(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+	engine := inmemory.NewStore(clock.RealClock{})
+
+	err := engine.SetChargeStationRuntimeDetails(ctx, "cs001", &store.ChargeStationRuntimeDetails{
+		OcppVersion: "1.6",
+	})
+	require.NoError(t, err)
+
+	err = engine.UpdateChargeStationSettings(ctx, "cs001", &store.ChargeStationSettings{
+		Settings: map[string]*store.ChargeStationSetting{
+			"foo": {Value: "bar", Status: "Pending"},
+		},
+	})
+	require.NoError(t, err)
+
+	updater := updateWithNoResponse{}
+	v16CallMaker := &mockCallMaker{engine: engine, updateFn: updater.update}
+	retryPolicy := sync.RetryPolicy{
+		InitialInterval: 100 * time.Millisecond,
+		MaxInterval:     200 * time.Millisecond,
+		Multiplier:      2,
+		MaxAttempts:     2,
+	}
+	sync.SyncSettings(ctx, engine, clock.RealClock{}, v16CallMaker, nil, nil, 50*time.Millisecond, retryPolicy)
+
+	assert.Equal(t, 2, len(updater.updateAttempts))
+	assert.True(t, updater.updateAttempts[1].After(updater.updateAttempts[0].Add(100*time.Millisecond)))
+
+	settings, err := engine.LookupChargeStationSettings(ctx, "cs001")
+	require.NoError(t, err)
+	require.Contains(t, settings.Settings, "foo")
+	assert.Equal(t, store.ChargeStationSettingStatusFailed, settings.Settings["foo"].Status)
+	assert.Equal(t, 2, settings.Settings["foo"].Attempts)
+
+	operations, err := engine.ListChargeStationOperations(ctx, "cs001", 0, 10)
+	require.NoError(t, err)
+	var events []store.ChargeStationOperationEvent
+	var attempts []int
+	for _, operation := range operations {
+		assert.Equal(t, store.ChargeStationOperationTypeSetting, operation.Type)
+		assert.Equal(t, "foo", operation.Subject)
+		events = append(events, operation.Event)
+		attempts = append(attempts, operation.Attempt)
+	}
+	assert.Equal(t, []store.ChargeStationOperationEvent{
+		store.ChargeStationOperationAttempted,
+		store.ChargeStationOperationAttempted,
+		store.ChargeStationOperationFailed,
+	}, events)
+	assert.Equal(t, []int{1, 2, 2}, attempts)
+}
@@ -4,8 +4,11 @@ package sync
 
 import (
 	"context"
+	"github.com/thoughtworks/maeve-csms/manager/handlers"
 	"github.com/thoughtworks/maeve-csms/manager/handlers/ocpp16"
 	"github.com/thoughtworks/maeve-csms/manager/handlers/ocpp201"
+	"github.com/thoughtworks/maeve-csms/manager/handlers/ocpp21"
+	"github.com/thoughtworks/maeve-csms/manager/ocpi"
 	"github.com/thoughtworks/maeve-csms/manager/store"
 	"github.com/thoughtworks/maeve-csms/manager/transport"
 	"go.opentelemetry.io/otel/trace"
@@ -13,32 +16,116 @@ import (
 	"time"
 )
 
-func Sync(storageEngine store.Engine, clock clock.PassiveClock, tracer trace.Tracer, emitter transport.Emitter) {
+// Sync starts the background processes that deliver pending changes to charge stations.
+// Settings, certificates and trigger messages are sent by the notifier as soon as they are
+// queued (or when the charge station connects): the polling loops are only a safety net
+// for notifications that are lost. They run every pollInterval, or more often if a retry
+// policy's initial interval is shorter. Each polling loop only runs in the manager instance
+// that holds its lease.
+func Sync(storageEngine store.Engine, clock clock.PassiveClock, tracer trace.Tracer, emitter transport.Emitter, ocpiApi ocpi.Api, notifier *Notifier, retryPolicies RetryPolicies, renewalPolicy CertificateRenewalPolicy, pollInterval time.Duration, installedCertificatesRefresh time.Duration, operationLogRetention time.Duration) {
+	if notifier != nil {
+		go notifier.Run(context.Background())
+	}
+
 	v16SyncCallMaker := ocpp16.NewCallMaker(emitter)
 	dataTransferCallMaker := ocpp16.NewDataTransferCallMaker(emitter)
 	v201SyncCallMaker := ocpp201.NewCallMaker(emitter)
+	v21SyncCallMaker := ocpp21.NewCallMaker(emitter)
+
+	// with several manager instances each loop is only run by the instance that holds its lease
+	leases := NewLeaseRunner(storageEngine, NewLeaseHolder(), 1*time.Minute)
 
-	go SyncSettings(context.Background(),
-		storageEngine,
-		clock,
-		v16SyncCallMaker,
-		v201SyncCallMaker,
-		1*time.Minute,
-		2*time.Minute)
-	go SyncCertificates(context.Background(),
-		storageEngine,
-		clock,
-		dataTransferCallMaker,
-		v201SyncCallMaker,
-		1*time.Minute,
-		2*time.Minute)
-	go SyncTriggers(context.Background(),
-		tracer,
-		storageEngine,
-		clock,
-		v16SyncCallMaker,
-		dataTransferCallMaker,
-		v201SyncCallMaker,
-		1*time.Minute,
-		2*time.Minute)
+	go leases.Run(context.Background(), "sync-settings", func(ctx context.Context) {
+		SyncSettings(ctx,
+			storageEngine,
+			clock,
+			v16SyncCallMaker,
+			v201SyncCallMaker,
+			v21SyncCallMaker,
+			retryPolicies.Settings.PollInterval(pollInterval),
+			retryPolicies.Settings)
+	})
+	go leases.Run(context.Background(), "sync-certificates", func(ctx context.Context) {
+		SyncCertificates(ctx,
+			storageEngine,
+			clock,
+			dataTransferCallMaker,
+			v201SyncCallMaker,
+			v21SyncCallMaker,
+			retryPolicies.Certificates.PollInterval(pollInterval),
+			retryPolicies.Certificates)
+	})
+	go leases.Run(context.Background(), "sync-triggers", func(ctx context.Context) {
+		SyncTriggers(ctx,
+			tracer,
+			storageEngine,
+			clock,
+			v16SyncCallMaker,
+			dataTransferCallMaker,
+			v201SyncCallMaker,
+			v21SyncCallMaker,
+			retryPolicies.Triggers.PollInterval(pollInterval),
+			retryPolicies.Triggers)
+	})
+	go leases.Run(context.Background(), "sync-installed-certificates", func(ctx context.Context) {
+		SyncInstalledCertificates(ctx,
+			tracer,
+			storageEngine,
+			clock,
+			dataTransferCallMaker,
+			v201SyncCallMaker,
+			v21SyncCallMaker,
+			retryPolicies.Certificates.PollInterval(pollInterval),
+			installedCertificatesRefresh,
+			retryPolicies.Certificates)
+	})
+	go leases.Run(context.Background(), "sync-certificate-renewals", func(ctx context.Context) {
+		var syncNotifier handlers.SyncNotifier
+		if notifier != nil {
+			syncNotifier = notifier
+		}
+		SyncCertificateRenewals(ctx,
+			tracer,
+			storageEngine,
+			clock,
+			syncNotifier,
+			1*time.Hour,
+			renewalPolicy)
+	})
+	go leases.Run(context.Background(), "sync-certificate-campaigns", func(ctx context.Context) {
+		var syncNotifier handlers.SyncNotifier
+		if notifier != nil {
+			syncNotifier = notifier
+		}
+		SyncCertificateCampaigns(ctx,
+			tracer,
+			storageEngine,
+			clock,
+			syncNotifier,
+			1*time.Minute)
+	})
+	go leases.Run(context.Background(), "sync-reservations", func(ctx context.Context) {
+		SyncReservations(ctx,
+			tracer,
+			storageEngine,
+			clock,
+			1*time.Minute,
+			24*time.Hour)
+	})
+	go leases.Run(context.Background(), "sync-operation-log-retention", func(ctx context.Context) {
+		SyncOperationLogRetention(ctx,
+			tracer,
+			storageEngine,
+			clock,
+			1*time.Hour,
+			operationLogRetention)
+	})
+	if ocpiApi != nil {
+		go leases.Run(context.Background(), "sync-ocpi-deliveries", func(ctx context.Context) {
+			SyncOcpiDeliveries(ctx,
+				tracer,
+				ocpiApi,
+				30*time.Second)
+		})
+	}
 }
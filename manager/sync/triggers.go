@@ -8,6 +8,7 @@ import (
 	"github.com/thoughtworks/maeve-csms/manager/handlers"
 	"github.com/thoughtworks/maeve-csms/manager/ocpp/ocpp16"
 	"github.com/thoughtworks/maeve-csms/manager/ocpp/ocpp201"
+	"github.com/thoughtworks/maeve-csms/manager/ocpp/ocpp21"
 	"github.com/thoughtworks/maeve-csms/manager/store"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
@@ -22,9 +23,10 @@ func SyncTriggers(ctx context.Context,
 	clock clock.PassiveClock,
 	v16CallMaker,
 	dataTransferCallMaker,
-	v201CallMaker handlers.CallMaker,
-	runEvery,
-	retryAfter time.Duration) {
+	v201CallMaker,
+	v21CallMaker handlers.CallMaker,
+	runEvery time.Duration,
+	retryPolicy RetryPolicy) {
 	var previousChargeStationId string
 	for {
 		select {
@@ -47,17 +49,15 @@ func SyncTriggers(ctx context.Context,
 					previousChargeStationId = ""
 				}
 				span.SetAttributes(attribute.Int("sync.trigger.count", len(triggerMessages)))
-				for _, pendingTriggerMessage := range triggerMessages {
+				for _, queuedTriggerMessages := range triggerMessages {
 					func() {
 						ctx, span := tracer.Start(ctx, "sync trigger", trace.WithSpanKind(trace.SpanKindInternal),
 							trace.WithAttributes(
-								attribute.String("chargeStationId", pendingTriggerMessage.ChargeStationId),
-								attribute.String("sync.trigger.status", string(pendingTriggerMessage.TriggerStatus)),
-								attribute.String("sync.trigger.message", string(pendingTriggerMessage.TriggerMessage)),
-								attribute.String("sync.trigger.after", pendingTriggerMessage.SendAfter.Format(time.RFC3339)),
+								attribute.String("chargeStationId", queuedTriggerMessages.ChargeStationId),
+								attribute.Int("sync.trigger.queued", len(queuedTriggerMessages.TriggerMessages)),
 							))
 						defer span.End()
-						details, err := engine.LookupChargeStationRuntimeDetails(ctx, pendingTriggerMessage.ChargeStationId)
+						details, err := engine.LookupChargeStationRuntimeDetails(ctx, queuedTriggerMessages.ChargeStationId)
 						if err != nil {
 							span.RecordError(err)
 							return
@@ -67,47 +67,134 @@ func SyncTriggers(ctx context.Context,
 							return
 						}
 
-						csId := pendingTriggerMessage.ChargeStationId
-						if clock.Now().After(pendingTriggerMessage.SendAfter) {
-							span.SetAttributes(attribute.String("sync.trigger.ocpp_version", details.OcppVersion))
-							err = engine.SetChargeStationTriggerMessage(ctx, csId, &store.ChargeStationTriggerMessage{
-								TriggerMessage: pendingTriggerMessage.TriggerMessage,
-								TriggerStatus:  store.TriggerStatusPending,
-								SendAfter:      clock.Now().Add(retryAfter),
-							})
-							if err != nil {
-								span.RecordError(err)
-								return
-							}
-
-							if details.OcppVersion == "1.6" {
-								if pendingTriggerMessage.TriggerMessage == store.TriggerMessageBootNotification ||
-									pendingTriggerMessage.TriggerMessage == store.TriggerMessageDiagnosticStatusNotification ||
-									pendingTriggerMessage.TriggerMessage == store.TriggerMessageFirmwareStatusNotification ||
-									pendingTriggerMessage.TriggerMessage == store.TriggerMessageHeartbeat ||
-									pendingTriggerMessage.TriggerMessage == store.TriggerMessageMeterValues ||
-									pendingTriggerMessage.TriggerMessage == store.TriggerMessageStatusNotification {
-									err = v16CallMaker.Send(ctx, csId, &ocpp16.TriggerMessageJson{
-										RequestedMessage: ocpp16.TriggerMessageJsonRequestedMessage(pendingTriggerMessage.TriggerMessage),
-									})
-								} else {
-									err = dataTransferCallMaker.Send(ctx, csId, &ocpp201.TriggerMessageRequestJson{
-										RequestedMessage: ocpp201.MessageTriggerEnumType(pendingTriggerMessage.TriggerMessage),
-									})
-								}
-							} else {
-								err = v201CallMaker.Send(ctx, csId, &ocpp201.TriggerMessageRequestJson{
-									RequestedMessage: ocpp201.MessageTriggerEnumType(pendingTriggerMessage.TriggerMessage),
-								})
-							}
-
-							if err != nil {
-								span.RecordError(err)
-							}
-						}
+						syncChargeStationTriggers(ctx, engine, clock, v16CallMaker, dataTransferCallMaker, v201CallMaker, v21CallMaker,
+							queuedTriggerMessages.ChargeStationId, details, queuedTriggerMessages, retryPolicy, false)
 					}()
 				}
 			}()
 		}
 	}
 }
+
+// syncChargeStationTriggers sends the next trigger message in the queue for a single charge
+// station, recording any error on the span in ctx. Trigger messages are sent one at a time in
+// the order that they were queued: the next is only sent once the charge station has responded
+// to the previous one or it has exhausted the retry policy (and been marked as Failed). A trigger
+// message is only sent once its SendAfter time has passed unless force is set.
+func syncChargeStationTriggers(ctx context.Context,
+	engine store.Engine,
+	clock clock.PassiveClock,
+	v16CallMaker,
+	dataTransferCallMaker,
+	v201CallMaker,
+	v21CallMaker handlers.CallMaker,
+	csId string,
+	details *store.ChargeStationRuntimeDetails,
+	queuedTriggerMessages *store.ChargeStationTriggerMessages,
+	retryPolicy RetryPolicy,
+	force bool) {
+	span := trace.SpanFromContext(ctx)
+	for _, triggerMessage := range queuedTriggerMessages.TriggerMessages {
+		if triggerMessage.TriggerStatus != store.TriggerStatusPending {
+			continue
+		}
+
+		span.SetAttributes(
+			attribute.String("sync.trigger.message", triggerMessage.Name()),
+			attribute.String("sync.trigger.after", triggerMessage.SendAfter.Format(time.RFC3339)))
+		if !force && !clock.Now().After(triggerMessage.SendAfter) {
+			// waiting for a response to the trigger message at the head of the queue
+			return
+		}
+
+		if retryPolicy.Exhausted(triggerMessage.Attempts) {
+			span.SetAttributes(attribute.Bool("sync.trigger.failed", true))
+			triggerMessage.TriggerStatus = store.TriggerStatusFailed
+			err := engine.UpdateChargeStationTriggerMessages(ctx, csId, &store.ChargeStationTriggerMessages{
+				TriggerMessages: []*store.ChargeStationTriggerMessage{triggerMessage},
+			})
+			if err != nil {
+				span.RecordError(err)
+				return
+			}
+			recordAttempt(ctx, engine, clock, csId, store.ChargeStationOperationFailed,
+				store.ChargeStationOperationTypeTrigger, triggerMessage.Name(), triggerMessage.Attempts)
+			continue
+		}
+
+		syncChargeStationTrigger(ctx, engine, clock, v16CallMaker, dataTransferCallMaker, v201CallMaker, v21CallMaker,
+			csId, details, triggerMessage, retryPolicy)
+		return
+	}
+}
+
+func syncChargeStationTrigger(ctx context.Context,
+	engine store.Engine,
+	clock clock.PassiveClock,
+	v16CallMaker,
+	dataTransferCallMaker,
+	v201CallMaker,
+	v21CallMaker handlers.CallMaker,
+	csId string,
+	details *store.ChargeStationRuntimeDetails,
+	pendingTriggerMessage *store.ChargeStationTriggerMessage,
+	retryPolicy RetryPolicy) {
+	span := trace.SpanFromContext(ctx)
+
+	span.SetAttributes(attribute.String("sync.trigger.ocpp_version", details.OcppVersion))
+	attempt := *pendingTriggerMessage
+	attempt.SendAfter = clock.Now().Add(retryPolicy.Backoff(pendingTriggerMessage.Attempts))
+	attempt.Attempts = pendingTriggerMessage.Attempts + 1
+	err := engine.UpdateChargeStationTriggerMessages(ctx, csId, &store.ChargeStationTriggerMessages{
+		TriggerMessages: []*store.ChargeStationTriggerMessage{&attempt},
+	})
+	if err != nil {
+		span.RecordError(err)
+		return
+	}
+	recordAttempt(ctx, engine, clock, csId, store.ChargeStationOperationAttempted,
+		store.ChargeStationOperationTypeTrigger, attempt.Name(), attempt.Attempts)
+
+	if details.OcppVersion == "1.6" {
+		if pendingTriggerMessage.TriggerMessage == store.TriggerMessageBootNotification ||
+			pendingTriggerMessage.TriggerMessage == store.TriggerMessageDiagnosticStatusNotification ||
+			pendingTriggerMessage.TriggerMessage == store.TriggerMessageFirmwareStatusNotification ||
+			pendingTriggerMessage.TriggerMessage == store.TriggerMessageHeartbeat ||
+			pendingTriggerMessage.TriggerMessage == store.TriggerMessageMeterValues ||
+			pendingTriggerMessage.TriggerMessage == store.TriggerMessageStatusNotification {
+			err = v16CallMaker.Send(ctx, csId, &ocpp16.TriggerMessageJson{
+				RequestedMessage: ocpp16.TriggerMessageJsonRequestedMessage(pendingTriggerMessage.TriggerMessage),
+			})
+		} else {
+			err = dataTransferCallMaker.Send(ctx, csId, &ocpp201.TriggerMessageRequestJson{
+				RequestedMessage: ocpp201.MessageTriggerEnumType(pendingTriggerMessage.TriggerMessage),
+			})
+		}
+	} else if details.OcppVersion == "2.1" {
+		req := &ocpp21.TriggerMessageRequestJson{
+			RequestedMessage: ocpp21.MessageTriggerEnumType(pendingTriggerMessage.TriggerMessage),
+		}
+		if pendingTriggerMessage.EvseId != nil {
+			req.Evse = &ocpp21.EVSEType{
+				Id:          *pendingTriggerMessage.EvseId,
+				ConnectorId: pendingTriggerMessage.ConnectorId,
+			}
+		}
+		err = v21CallMaker.Send(ctx, csId, req)
+	} else {
+		req := &ocpp201.TriggerMessageRequestJson{
+			RequestedMessage: ocpp201.MessageTriggerEnumType(pendingTriggerMessage.TriggerMessage),
+		}
+		if pendingTriggerMessage.EvseId != nil {
+			req.Evse = &ocpp201.EVSEType{
+				Id:          *pendingTriggerMessage.EvseId,
+				ConnectorId: pendingTriggerMessage.ConnectorId,
+			}
+		}
+		err = v201CallMaker.Send(ctx, csId, req)
+	}
+
+	if err != nil {
+		span.RecordError(err)
+	}
+}
@@ -0,0 +1,35 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package transport
+
+import "context"
+
+// ControlMessageType identifies the action that the gateway should take in
+// response to a ControlMessage.
+type ControlMessageType string
+
+const (
+	// ControlDisconnect requests that the gateway closes the charge station's websocket.
+	ControlDisconnect ControlMessageType = "Disconnect"
+)
+
+// ControlMessage is sent by the CSMS to the gateway to manage the connection of a
+// charge station rather than to deliver an OCPP message to it.
+type ControlMessage struct {
+	Type   ControlMessageType `json:"type"`
+	Reason string             `json:"reason,omitempty"`
+}
+
+// ControlEmitter defines the contract for sending control messages to the gateway.
+type ControlEmitter interface {
+	// EmitControl sends a ControlMessage, which relates to the charge station identified by
+	// the chargeStationId, to the gateway.
+	EmitControl(ctx context.Context, chargeStationId string, message *ControlMessage) error
+}
+
+// ControlEmitterFunc allows a plain function to be used as a ControlEmitter
+type ControlEmitterFunc func(ctx context.Context, chargeStationId string, message *ControlMessage) error
+
+func (e ControlEmitterFunc) EmitControl(ctx context.Context, chargeStationId string, message *ControlMessage) error {
+	return e(ctx, chargeStationId, message)
+}
@@ -0,0 +1,162 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package inmemory
+
+import (
+	"context"
+	"fmt"
+	"github.com/thoughtworks/maeve-csms/manager/transport"
+	"go.opentelemetry.io/otel/trace"
+	"strings"
+	"sync"
+)
+
+// InTopic returns the topic used for messages from the charge station to the CSMS
+func InTopic(ocppVersion transport.OcppVersion, chargeStationId string) string {
+	return fmt.Sprintf("in/%s/%s", ocppVersion, chargeStationId)
+}
+
+// OutTopic returns the topic used for messages from the CSMS to the charge station
+func OutTopic(ocppVersion transport.OcppVersion, chargeStationId string) string {
+	return fmt.Sprintf("out/%s/%s", ocppVersion, chargeStationId)
+}
+
+// PresenceTopic returns the topic used for the charge station presence events
+func PresenceTopic(chargeStationId string) string {
+	return fmt.Sprintf("presence/%s", chargeStationId)
+}
+
+// ControlTopic returns the topic used for control messages from the CSMS to the gateway
+func ControlTopic(chargeStationId string) string {
+	return fmt.Sprintf("control/%s", chargeStationId)
+}
+
+// Handler is called for each message that is published on a topic that
+// matches the subscription.
+type Handler func(ctx context.Context, topic string, payload []byte)
+
+// Broker routes messages between publishers and subscribers in the same process.
+// Topics are composed of levels separated by '/' and a subscription may use '+' to
+// match any value for a single level (as with MQTT).
+//
+// Each subscription has its own queue and goroutine so messages are delivered in
+// the order they were published and a slow subscriber never blocks a publisher.
+// Only the trace context is propagated from the publisher to the subscriber.
+type Broker struct {
+	sync.RWMutex
+	subscriptions map[*Subscription]struct{}
+}
+
+func NewBroker() *Broker {
+	return &Broker{
+		subscriptions: make(map[*Subscription]struct{}),
+	}
+}
+
+// Publish delivers the payload to all the subscriptions that match the topic.
+func (b *Broker) Publish(ctx context.Context, topic string, payload []byte) {
+	// the subscriber must not be affected by the publisher's deadline or cancellation
+	msgCtx := trace.ContextWithSpanContext(context.Background(), trace.SpanContextFromContext(ctx))
+	topicLevels := strings.Split(topic, "/")
+
+	b.RLock()
+	defer b.RUnlock()
+	for sub := range b.subscriptions {
+		if sub.matches(topicLevels) {
+			sub.enqueue(message{ctx: msgCtx, topic: topic, payload: payload})
+		}
+	}
+}
+
+// Subscribe registers the handler to receive all messages published on topics
+// that match the pattern until the Subscription is disconnected.
+func (b *Broker) Subscribe(pattern string, handler Handler) *Subscription {
+	sub := &Subscription{
+		broker:  b,
+		pattern: strings.Split(pattern, "/"),
+		handler: handler,
+		notify:  make(chan struct{}, 1),
+		done:    make(chan struct{}),
+	}
+	go sub.run()
+
+	b.Lock()
+	defer b.Unlock()
+	b.subscriptions[sub] = struct{}{}
+
+	return sub
+}
+
+type message struct {
+	ctx     context.Context
+	topic   string
+	payload []byte
+}
+
+// Subscription is an implementation of transport.Connection that represents the
+// registration of a Handler with the Broker.
+type Subscription struct {
+	sync.Mutex
+	broker  *Broker
+	pattern []string
+	handler Handler
+	queue   []message
+	notify  chan struct{}
+	done    chan struct{}
+	once    sync.Once
+}
+
+func (s *Subscription) Disconnect(_ context.Context) error {
+	s.once.Do(func() {
+		s.broker.Lock()
+		delete(s.broker.subscriptions, s)
+		s.broker.Unlock()
+		close(s.done)
+	})
+	return nil
+}
+
+func (s *Subscription) matches(topicLevels []string) bool {
+	if len(topicLevels) != len(s.pattern) {
+		return false
+	}
+	for i, level := range s.pattern {
+		if level != "+" && level != topicLevels[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *Subscription) enqueue(msg message) {
+	s.Lock()
+	s.queue = append(s.queue, msg)
+	s.Unlock()
+
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+}
+
+func (s *Subscription) run() {
+	for {
+		select {
+		case <-s.notify:
+			for {
+				s.Lock()
+				if len(s.queue) == 0 {
+					s.Unlock()
+					break
+				}
+				msg := s.queue[0]
+				s.queue = s.queue[1:]
+				s.Unlock()
+
+				s.handler(msg.ctx, msg.topic, msg.payload)
+			}
+		case <-s.done:
+			return
+		}
+	}
+}
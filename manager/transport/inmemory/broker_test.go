@@ -0,0 +1,102 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package inmemory_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thoughtworks/maeve-csms/manager/testutil"
+	"github.com/thoughtworks/maeve-csms/manager/transport/inmemory"
+	"go.opentelemetry.io/otel/trace"
+	"testing"
+	"time"
+)
+
+type received struct {
+	ctx     context.Context
+	topic   string
+	payload string
+}
+
+func receiveOne(t *testing.T, ch chan received) received {
+	select {
+	case got := <-ch:
+		return got
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for message")
+	}
+	return received{}
+}
+
+func TestBrokerDeliversToMatchingSubscriptions(t *testing.T) {
+	broker := inmemory.NewBroker()
+
+	allCh := make(chan received, 10)
+	all := broker.Subscribe("in/+/+", func(ctx context.Context, topic string, payload []byte) {
+		allCh <- received{ctx: ctx, topic: topic, payload: string(payload)}
+	})
+	defer func() {
+		_ = all.Disconnect(context.Background())
+	}()
+
+	oneCh := make(chan received, 10)
+	one := broker.Subscribe("in/ocpp1.6/cs002", func(ctx context.Context, topic string, payload []byte) {
+		oneCh <- received{ctx: ctx, topic: topic, payload: string(payload)}
+	})
+	defer func() {
+		_ = one.Disconnect(context.Background())
+	}()
+
+	broker.Publish(context.Background(), "in/ocpp1.6/cs001", []byte("one"))
+	broker.Publish(context.Background(), "in/ocpp1.6/cs002", []byte("two"))
+	broker.Publish(context.Background(), "out/ocpp1.6/cs002", []byte("three"))
+
+	assert.Equal(t, "one", receiveOne(t, allCh).payload)
+	assert.Equal(t, "two", receiveOne(t, allCh).payload)
+
+	got := receiveOne(t, oneCh)
+	assert.Equal(t, "in/ocpp1.6/cs002", got.topic)
+	assert.Equal(t, "two", got.payload)
+
+	assert.Never(t, func() bool { return len(allCh) > 0 || len(oneCh) > 0 }, 50*time.Millisecond, 10*time.Millisecond)
+}
+
+func TestBrokerDoesNotDeliverAfterDisconnect(t *testing.T) {
+	broker := inmemory.NewBroker()
+
+	ch := make(chan received, 10)
+	sub := broker.Subscribe("presence/+", func(ctx context.Context, topic string, payload []byte) {
+		ch <- received{ctx: ctx, topic: topic, payload: string(payload)}
+	})
+
+	err := sub.Disconnect(context.Background())
+	require.NoError(t, err)
+
+	broker.Publish(context.Background(), "presence/cs001", []byte("event"))
+
+	assert.Never(t, func() bool { return len(ch) > 0 }, 50*time.Millisecond, 10*time.Millisecond)
+}
+
+func TestBrokerPropagatesTraceContextOnly(t *testing.T) {
+	tracer, _ := testutil.GetTracer()
+	broker := inmemory.NewBroker()
+
+	ch := make(chan received, 1)
+	sub := broker.Subscribe("out/+/+", func(ctx context.Context, topic string, payload []byte) {
+		ch <- received{ctx: ctx, topic: topic, payload: string(payload)}
+	})
+	defer func() {
+		_ = sub.Disconnect(context.Background())
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ctx, span := tracer.Start(ctx, "test")
+	broker.Publish(ctx, "out/ocpp2.0.1/cs001", []byte("msg"))
+	span.End()
+	cancel()
+
+	got := receiveOne(t, ch)
+	assert.Equal(t, span.SpanContext().TraceID(), trace.SpanContextFromContext(got.ctx).TraceID())
+	assert.NoError(t, got.ctx.Err())
+}
@@ -0,0 +1,6 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package inmemory provides support for handling messages from the
+// gateway and emitting messages to the gateway when both are running
+// in the same process
+package inmemory
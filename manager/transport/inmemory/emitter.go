@@ -0,0 +1,43 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package inmemory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/thoughtworks/maeve-csms/manager/transport"
+)
+
+// Emitter is an implementation of transport.Emitter that publishes messages
+// on the out/<ocpp-version>/<cs-id> topic of a Broker. Control messages are
+// published on the control/<cs-id> topic.
+type Emitter struct {
+	broker *Broker
+}
+
+func NewEmitter(broker *Broker) *Emitter {
+	return &Emitter{
+		broker: broker,
+	}
+}
+
+func (e *Emitter) Emit(ctx context.Context, ocppVersion transport.OcppVersion, chargeStationId string, message *transport.Message) error {
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("marshalling response of type %s: %v", message.Action, err)
+	}
+
+	e.broker.Publish(ctx, OutTopic(ocppVersion, chargeStationId), payload)
+	return nil
+}
+
+func (e *Emitter) EmitControl(ctx context.Context, chargeStationId string, message *transport.ControlMessage) error {
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("marshalling control message of type %s: %v", message.Type, err)
+	}
+
+	e.broker.Publish(ctx, ControlTopic(chargeStationId), payload)
+	return nil
+}
@@ -0,0 +1,58 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package inmemory
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/thoughtworks/maeve-csms/manager/transport"
+	"golang.org/x/exp/slog"
+	"strings"
+)
+
+// Listener is an implementation of transport.Listener (and transport.PresenceListener)
+// that subscribes to the in/<ocpp-version>/<cs-id> and presence/<cs-id> topics of a Broker.
+type Listener struct {
+	broker *Broker
+}
+
+func NewListener(broker *Broker) *Listener {
+	return &Listener{
+		broker: broker,
+	}
+}
+
+func (l *Listener) Connect(_ context.Context, ocppVersion transport.OcppVersion, chargeStationId *string, handler transport.MessageHandler) (transport.Connection, error) {
+	pattern := InTopic(ocppVersion, "+")
+	if chargeStationId != nil {
+		pattern = InTopic(ocppVersion, *chargeStationId)
+	}
+
+	return l.broker.Subscribe(pattern, func(ctx context.Context, topic string, payload []byte) {
+		var msg transport.Message
+		err := json.Unmarshal(payload, &msg)
+		if err != nil {
+			slog.Warn("unable to unmarshal message", "err", err)
+			return
+		}
+
+		handler.Handle(ctx, chargeStationIdFromTopic(topic), &msg)
+	}), nil
+}
+
+func (l *Listener) ConnectPresence(_ context.Context, handler transport.PresenceHandler) (transport.Connection, error) {
+	return l.broker.Subscribe(PresenceTopic("+"), func(ctx context.Context, topic string, payload []byte) {
+		var event transport.PresenceEvent
+		err := json.Unmarshal(payload, &event)
+		if err != nil {
+			slog.Warn("unable to unmarshal presence event", "err", err)
+			return
+		}
+
+		handler.HandlePresence(ctx, chargeStationIdFromTopic(topic), &event)
+	}), nil
+}
+
+func chargeStationIdFromTopic(topic string) string {
+	return topic[strings.LastIndex(topic, "/")+1:]
+}
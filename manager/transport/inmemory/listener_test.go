@@ -0,0 +1,134 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package inmemory_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thoughtworks/maeve-csms/manager/transport"
+	"github.com/thoughtworks/maeve-csms/manager/transport/inmemory"
+	"testing"
+	"time"
+)
+
+func TestListenerReceivesEmittedMessages(t *testing.T) {
+	broker := inmemory.NewBroker()
+
+	type receivedMessage struct {
+		chargeStationId string
+		msg             *transport.Message
+	}
+
+	rcvCh := make(chan receivedMessage, 1)
+	listener := inmemory.NewListener(broker)
+	conn, err := listener.Connect(context.Background(), transport.OcppVersion201, nil,
+		transport.MessageHandlerFunc(func(ctx context.Context, chargeStationId string, message *transport.Message) {
+			rcvCh <- receivedMessage{chargeStationId: chargeStationId, msg: message}
+		}))
+	require.NoError(t, err)
+	defer func() {
+		_ = conn.Disconnect(context.Background())
+	}()
+
+	// simulate the gateway publishing a message from the charge station
+	broker.Publish(context.Background(), inmemory.InTopic(transport.OcppVersion201, "cs001"),
+		[]byte(`{"type":2,"action":"Heartbeat","id":"1234","request":{}}`))
+
+	select {
+	case got := <-rcvCh:
+		assert.Equal(t, "cs001", got.chargeStationId)
+		assert.Equal(t, transport.MessageTypeCall, got.msg.MessageType)
+		assert.Equal(t, "Heartbeat", got.msg.Action)
+		assert.Equal(t, "1234", got.msg.MessageId)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for message")
+	}
+}
+
+func TestEmitterPublishesOnOutTopic(t *testing.T) {
+	broker := inmemory.NewBroker()
+
+	ch := make(chan string, 1)
+	sub := broker.Subscribe(inmemory.OutTopic(transport.OcppVersion16, "cs001"), func(ctx context.Context, topic string, payload []byte) {
+		ch <- string(payload)
+	})
+	defer func() {
+		_ = sub.Disconnect(context.Background())
+	}()
+
+	emitter := inmemory.NewEmitter(broker)
+	err := emitter.Emit(context.Background(), transport.OcppVersion16, "cs001", &transport.Message{
+		MessageType:    transport.MessageTypeCall,
+		Action:         "Reset",
+		MessageId:      "1234",
+		RequestPayload: []byte(`{"type":"Soft"}`),
+	})
+	require.NoError(t, err)
+
+	select {
+	case got := <-ch:
+		assert.JSONEq(t, `{"type":2,"action":"Reset","id":"1234","request":{"type":"Soft"}}`, got)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for message")
+	}
+}
+
+func TestEmitterPublishesOnControlTopic(t *testing.T) {
+	broker := inmemory.NewBroker()
+
+	ch := make(chan string, 1)
+	sub := broker.Subscribe(inmemory.ControlTopic("cs001"), func(ctx context.Context, topic string, payload []byte) {
+		ch <- string(payload)
+	})
+	defer func() {
+		_ = sub.Disconnect(context.Background())
+	}()
+
+	emitter := inmemory.NewEmitter(broker)
+	err := emitter.EmitControl(context.Background(), "cs001", &transport.ControlMessage{
+		Type:   transport.ControlDisconnect,
+		Reason: "banned",
+	})
+	require.NoError(t, err)
+
+	select {
+	case got := <-ch:
+		assert.JSONEq(t, `{"type":"Disconnect","reason":"banned"}`, got)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for message")
+	}
+}
+
+func TestListenerReceivesPresenceEvents(t *testing.T) {
+	broker := inmemory.NewBroker()
+
+	type receivedEvent struct {
+		chargeStationId string
+		event           *transport.PresenceEvent
+	}
+
+	rcvCh := make(chan receivedEvent, 1)
+	listener := inmemory.NewListener(broker)
+	conn, err := listener.ConnectPresence(context.Background(),
+		transport.PresenceHandlerFunc(func(ctx context.Context, chargeStationId string, event *transport.PresenceEvent) {
+			rcvCh <- receivedEvent{chargeStationId: chargeStationId, event: event}
+		}))
+	require.NoError(t, err)
+	defer func() {
+		_ = conn.Disconnect(context.Background())
+	}()
+
+	broker.Publish(context.Background(), inmemory.PresenceTopic("cs001"),
+		[]byte(`{"type":"Connected","gateway_id":"gw1","connection_id":"abc"}`))
+
+	select {
+	case got := <-rcvCh:
+		assert.Equal(t, "cs001", got.chargeStationId)
+		assert.Equal(t, transport.PresenceConnected, got.event.Type)
+		assert.Equal(t, "gw1", got.event.GatewayId)
+		assert.Equal(t, "abc", got.event.ConnectionId)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for presence event")
+	}
+}
@@ -91,6 +91,55 @@ func (e *Emitter) Emit(ctx context.Context, ocppVersion transport.OcppVersion, c
 	return nil
 }
 
+// EmitControl publishes a control message on the <prefix>/control/<cs-id> topic. Control
+// messages are published with QoS 1 so that they are not lost if the gateway's connection
+// to the broker is interrupted.
+func (e *Emitter) EmitControl(ctx context.Context, chargeStationId string, message *transport.ControlMessage) error {
+	topic := fmt.Sprintf("%s/control/%s", e.mqttPrefix, chargeStationId)
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("marshalling control message of type %s: %v", message.Type, err)
+	}
+
+	newCtx, span := e.tracer.Start(ctx,
+		fmt.Sprintf("%s/control/# publish", e.mqttPrefix),
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			semconv.MessagingSystem("mqtt"),
+			semconv.MessagingMessagePayloadSizeBytes(len(payload)),
+			semconv.MessagingOperationKey.String("publish"),
+			attribute.String("csId", chargeStationId),
+			attribute.String("control.type", string(message.Type)),
+		))
+	defer span.End()
+
+	correlationMap := make(map[string]string)
+	otel.GetTextMapPropagator().Inject(newCtx, propagation.MapCarrier(correlationMap))
+
+	correlationData, err := json.Marshal(correlationMap)
+	if err != nil {
+		return fmt.Errorf("marshalling correlation map: %v", err)
+	}
+
+	err = e.ensureConnection(ctx)
+	if err != nil {
+		return fmt.Errorf("connecting to MQTT: %v", err)
+	}
+
+	_, err = e.conn.Publish(newCtx, &paho.Publish{
+		Topic:   topic,
+		QoS:     1,
+		Payload: payload,
+		Properties: &paho.PublishProperties{
+			CorrelationData: correlationData,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("publishing to %s: %v", topic, err)
+	}
+	return nil
+}
+
 func getActionName(msg *transport.Message) string {
 	switch msg.MessageType {
 	case transport.MessageTypeCall:
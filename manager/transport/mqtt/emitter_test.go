@@ -199,6 +199,53 @@ func TestEmitterAddsCorrelationData(t *testing.T) {
 	}
 }
 
+func TestEmitterSendsControlMessage(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// start the broker
+	broker, clientUrl := mqtt2.NewBroker(t)
+	defer func() {
+		err := broker.Close()
+		assert.NoError(t, err)
+	}()
+
+	err := broker.Serve()
+	require.NoError(t, err)
+
+	emitter := mqtt2.NewEmitter(
+		mqtt2.WithMqttBrokerUrl[mqtt2.Emitter](clientUrl),
+		mqtt2.WithMqttPrefix[mqtt2.Emitter]("cs"))
+
+	rcvdCh := make(chan struct{})
+
+	mqttClient := listenForMessageSentByManager(t, ctx, clientUrl, paho.NewSingleHandlerRouter(func(publish *paho.Publish) {
+		assert.Equal(t, "cs/control/cs001", publish.Topic)
+		assert.JSONEq(t, `{"type":"Disconnect","reason":"credentials changed"}`, string(publish.Payload))
+		rcvdCh <- struct{}{}
+	}))
+
+	defer func() {
+		_ = mqttClient.Disconnect(ctx)
+	}()
+
+	controlEmitter, ok := emitter.(transport.ControlEmitter)
+	require.True(t, ok)
+
+	err = controlEmitter.EmitControl(context.Background(), "cs001", &transport.ControlMessage{
+		Type:   transport.ControlDisconnect,
+		Reason: "credentials changed",
+	})
+	require.NoError(t, err)
+
+	select {
+	case <-rcvdCh:
+		// success
+	case <-ctx.Done():
+		assert.Fail(t, "timeout waiting for test")
+	}
+}
+
 func listenForMessageSentByManager(t *testing.T, ctx context.Context, clientUrl *url.URL, router paho.Router) *autopaho.ConnectionManager {
 	mqttClient, err := autopaho.NewConnection(context.Background(), autopaho.ClientConfig{
 		BrokerUrls:        []*url.URL{clientUrl},
@@ -209,6 +256,7 @@ func listenForMessageSentByManager(t *testing.T, ctx context.Context, clientUrl
 				Subscriptions: map[string]paho.SubscribeOptions{
 					"cs/out/ocpp1.6/cs001":   {},
 					"cs/out/ocpp2.0.1/cs001": {},
+					"cs/control/cs001":       {QoS: 1},
 				},
 			})
 			require.NoError(t, err)
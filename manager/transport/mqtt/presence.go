@@ -0,0 +1,101 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package mqtt
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/eclipse/paho.golang/autopaho"
+	"github.com/eclipse/paho.golang/paho"
+	"github.com/thoughtworks/maeve-csms/manager/transport"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/exp/slog"
+	"strings"
+)
+
+// ConnectPresence subscribes to the presence events published by the gateway on
+// <prefix>/presence/<cs-id>. The subscription is shared by all listeners in the
+// same group so each event is only handled once.
+func (l *Listener) ConnectPresence(ctx context.Context, handler transport.PresenceHandler) (transport.Connection, error) {
+	var err error
+
+	ctx, cancel := context.WithTimeout(ctx, l.mqttConnectTimeout)
+	defer cancel()
+
+	clientId := fmt.Sprintf("%s-presence-%s", l.mqttGroup, randSeq(5))
+
+	readyCh := make(chan struct{})
+
+	topic := fmt.Sprintf("$share/%s/%s/presence/+", l.mqttGroup, l.mqttPrefix)
+
+	conn := new(connection)
+	mqttRouter := paho.NewStandardRouter()
+	conn.mqttConn, err = autopaho.NewConnection(context.Background(), autopaho.ClientConfig{
+		BrokerUrls:        l.mqttBrokerUrls,
+		KeepAlive:         l.mqttKeepAliveInterval,
+		ConnectRetryDelay: l.mqttConnectRetryDelay,
+		OnConnectionUp: func(manager *autopaho.ConnectionManager, connack *paho.Connack) {
+			_, err := manager.Subscribe(ctx, &paho.Subscribe{
+				Subscriptions: map[string]paho.SubscribeOptions{
+					topic: {QoS: 1},
+				},
+			})
+			if err != nil {
+				slog.Error("failed to subscribe to topic", "topic", topic)
+				return
+			}
+			mqttRouter.UnregisterHandler(topic)
+			mqttRouter.RegisterHandler(topic, func(mqttMsg *paho.Publish) {
+				newCtx, span := l.tracer.Start(context.Background(),
+					fmt.Sprintf("%s receive", getTopicPattern(mqttMsg.Topic)),
+					trace.WithSpanKind(trace.SpanKindConsumer),
+					trace.WithAttributes(
+						semconv.MessagingSystem("mqtt"),
+						semconv.MessagingConsumerID(clientId),
+						semconv.MessagingMessagePayloadSizeBytes(len(mqttMsg.Payload)),
+						semconv.MessagingOperationKey.String("receive"),
+					))
+				defer span.End()
+
+				topicParts := strings.Split(mqttMsg.Topic, "/")
+				var chargeStationId = topicParts[len(topicParts)-1]
+
+				var event transport.PresenceEvent
+				err := json.Unmarshal(mqttMsg.Payload, &event)
+				if err != nil {
+					span.RecordError(err)
+					span.SetStatus(codes.Error, "unable to unmarshal presence event")
+					slog.Warn("unable to unmarshal presence event", "err", err)
+					return
+				}
+
+				span.SetAttributes(
+					attribute.String("csId", chargeStationId),
+					attribute.String("presence.type", string(event.Type)),
+					attribute.String("presence.gateway_id", event.GatewayId))
+
+				handler.HandlePresence(newCtx, chargeStationId, &event)
+			})
+			readyCh <- struct{}{}
+		},
+		ClientConfig: paho.ClientConfig{
+			ClientID: clientId,
+			Router:   mqttRouter,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil, errors.New("timeout waiting for mqtt connectionDetails setup")
+	case <-readyCh:
+		return conn, nil
+	}
+}
@@ -0,0 +1,72 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package mqtt_test
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thoughtworks/maeve-csms/manager/transport"
+	"github.com/thoughtworks/maeve-csms/manager/transport/mqtt"
+	"testing"
+	"time"
+)
+
+func TestListenerProcessesPresenceEventsReceivedFromTheBroker(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// start the broker
+	broker, clientUrl := mqtt.NewBroker(t)
+	defer func() {
+		err := broker.Close()
+		assert.NoError(t, err)
+	}()
+	err := broker.Serve()
+	require.NoError(t, err)
+
+	// setup the handler
+	receivedEventCh := make(chan struct{})
+	handler := func(ctx context.Context, chargeStationId string, event *transport.PresenceEvent) {
+		assert.Equal(t, "cs001", chargeStationId)
+		assert.Equal(t, transport.PresenceConnected, event.Type)
+		assert.Equal(t, "gateway1", event.GatewayId)
+		assert.Equal(t, "abc123", event.ConnectionId)
+		assert.Equal(t, "ocpp2.0.1", event.Protocol)
+		assert.Equal(t, 2, event.SecurityProfile)
+		receivedEventCh <- struct{}{}
+	}
+
+	// connect the listener to the broker
+	listener := mqtt.NewListener(mqtt.WithMqttBrokerUrl[mqtt.Listener](clientUrl))
+	conn, err := listener.ConnectPresence(ctx, transport.PresenceHandlerFunc(handler))
+	require.NoError(t, err)
+	defer func() {
+		if conn != nil {
+			err := conn.Disconnect(ctx)
+			require.NoError(t, err)
+		}
+	}()
+
+	// publish event
+	payload, err := json.Marshal(transport.PresenceEvent{
+		Type:            transport.PresenceConnected,
+		GatewayId:       "gateway1",
+		ConnectionId:    "abc123",
+		Protocol:        "ocpp2.0.1",
+		RemoteAddr:      "127.0.0.1:1234",
+		SecurityProfile: 2,
+	})
+	require.NoError(t, err)
+	err = broker.Publish("cs/presence/cs001", payload, false, 0)
+	require.NoError(t, err)
+
+	// wait for event to be received / timeout
+	select {
+	case <-ctx.Done():
+		assert.Fail(t, "timeout waiting for test to complete")
+	case <-receivedEventCh:
+		// do nothing
+	}
+}
@@ -0,0 +1,49 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package nats
+
+import (
+	"fmt"
+	"github.com/nats-io/nats.go"
+	"github.com/thoughtworks/maeve-csms/manager/transport"
+	"strings"
+	"time"
+)
+
+// versionToken converts the OCPP version into a form that can be used as a single
+// NATS subject token (the subject token separator is '.'): e.g. ocpp1.6 becomes ocpp16
+func versionToken(ocppVersion transport.OcppVersion) string {
+	return strings.ReplaceAll(string(ocppVersion), ".", "")
+}
+
+// checkChargeStationId ensures that the charge station identifier can be used as
+// a single NATS subject token
+func checkChargeStationId(chargeStationId string) error {
+	if chargeStationId == "" || strings.ContainsAny(chargeStationId, ".*> \t\r\n") {
+		return fmt.Errorf("charge station id %q cannot be used in a nats subject", chargeStationId)
+	}
+	return nil
+}
+
+func connect(details connectionDetails, name string) (*nats.Conn, error) {
+	return nats.Connect(strings.Join(details.natsUrls, ","),
+		nats.Name(name),
+		nats.Timeout(details.natsConnectTimeout),
+		nats.ReconnectWait(details.natsReconnectWait),
+		nats.MaxReconnects(-1))
+}
+
+func ensureConnectionDefaults(details *connectionDetails) {
+	if details.natsUrls == nil {
+		details.natsUrls = []string{nats.DefaultURL}
+	}
+	if details.natsPrefix == "" {
+		details.natsPrefix = "cs"
+	}
+	if details.natsConnectTimeout == 0 {
+		details.natsConnectTimeout = 10 * time.Second
+	}
+	if details.natsReconnectWait == 0 {
+		details.natsReconnectWait = 1 * time.Second
+	}
+}
@@ -0,0 +1,5 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package nats provides support for handling messages from the
+// gateway and emitting messages to the gateway using NATS
+package nats
@@ -0,0 +1,161 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package nats
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/nats-io/nats.go"
+	"github.com/thoughtworks/maeve-csms/manager/transport"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+	"net/http"
+	"sync"
+)
+
+// Emitter is an implementation of transport.Emitter that uses NATS
+// as the transport.
+//
+// Messages are published on a subject that is composed of a number of
+// tokens: <prefix>.out.<ocpp-version>.<cs-id>. The prefix is configured,
+// the ocpp-version (with the '.' removed, e.g. ocpp16) and cs-id are provided
+// to the Emit function. If not configured the default prefix is `cs`.
+//
+// The trace context is propagated in the message headers.
+//
+// The Emitter defaults to connecting to a server on 127.0.0.1:4222.
+type Emitter struct {
+	sync.Mutex
+	connectionDetails
+	tracer trace.Tracer
+	conn   *nats.Conn
+}
+
+func NewEmitter(opts ...Opt[Emitter]) transport.Emitter {
+	e := new(Emitter)
+	for _, opt := range opts {
+		opt(e)
+	}
+	ensureConnectionDefaults(&e.connectionDetails)
+	if e.tracer == nil {
+		e.tracer = noop.NewTracerProvider().Tracer("")
+	}
+	return e
+}
+
+func (e *Emitter) Emit(ctx context.Context, ocppVersion transport.OcppVersion, chargeStationId string, message *transport.Message) error {
+	err := checkChargeStationId(chargeStationId)
+	if err != nil {
+		return err
+	}
+
+	subject := fmt.Sprintf("%s.out.%s.%s", e.natsPrefix, versionToken(ocppVersion), chargeStationId)
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("marshalling response of type %s: %v", message.Action, err)
+	}
+
+	newCtx, span := e.tracer.Start(ctx,
+		fmt.Sprintf("%s.out.%s.* publish", e.natsPrefix, versionToken(ocppVersion)),
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			semconv.MessagingSystem("nats"),
+			semconv.MessagingMessagePayloadSizeBytes(len(payload)),
+			semconv.MessagingOperationKey.String("publish"),
+			semconv.MessagingMessageConversationID(message.MessageId),
+			attribute.String("csId", chargeStationId),
+			attribute.String(getActionName(message), message.Action),
+		))
+	defer span.End()
+
+	header := make(nats.Header)
+	otel.GetTextMapPropagator().Inject(newCtx, propagation.HeaderCarrier(http.Header(header)))
+
+	conn, err := e.ensureConnection()
+	if err != nil {
+		return fmt.Errorf("connecting to NATS: %v", err)
+	}
+
+	err = conn.PublishMsg(&nats.Msg{
+		Subject: subject,
+		Header:  header,
+		Data:    payload,
+	})
+	if err != nil {
+		return fmt.Errorf("publishing to %s: %v", subject, err)
+	}
+	return nil
+}
+
+// EmitControl publishes a control message on the <prefix>.control.<cs-id> subject.
+func (e *Emitter) EmitControl(ctx context.Context, chargeStationId string, message *transport.ControlMessage) error {
+	err := checkChargeStationId(chargeStationId)
+	if err != nil {
+		return err
+	}
+
+	subject := fmt.Sprintf("%s.control.%s", e.natsPrefix, chargeStationId)
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("marshalling control message of type %s: %v", message.Type, err)
+	}
+
+	newCtx, span := e.tracer.Start(ctx,
+		fmt.Sprintf("%s.control.* publish", e.natsPrefix),
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			semconv.MessagingSystem("nats"),
+			semconv.MessagingMessagePayloadSizeBytes(len(payload)),
+			semconv.MessagingOperationKey.String("publish"),
+			attribute.String("csId", chargeStationId),
+			attribute.String("control.type", string(message.Type)),
+		))
+	defer span.End()
+
+	header := make(nats.Header)
+	otel.GetTextMapPropagator().Inject(newCtx, propagation.HeaderCarrier(http.Header(header)))
+
+	conn, err := e.ensureConnection()
+	if err != nil {
+		return fmt.Errorf("connecting to NATS: %v", err)
+	}
+
+	err = conn.PublishMsg(&nats.Msg{
+		Subject: subject,
+		Header:  header,
+		Data:    payload,
+	})
+	if err != nil {
+		return fmt.Errorf("publishing to %s: %v", subject, err)
+	}
+	return nil
+}
+
+func getActionName(msg *transport.Message) string {
+	switch msg.MessageType {
+	case transport.MessageTypeCall:
+		return "call.action"
+	case transport.MessageTypeCallResult:
+		return "call_result.action"
+	default:
+		return "call_error.action"
+	}
+}
+
+func (e *Emitter) ensureConnection() (*nats.Conn, error) {
+	e.Lock()
+	defer e.Unlock()
+	if e.conn == nil {
+		conn, err := connect(e.connectionDetails, "manager-emit")
+		if err != nil {
+			return nil, err
+		}
+		e.conn = conn
+	}
+	return e.conn, nil
+}
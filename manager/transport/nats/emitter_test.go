@@ -0,0 +1,121 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package nats_test
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thoughtworks/maeve-csms/manager/testutil"
+	"github.com/thoughtworks/maeve-csms/manager/transport"
+	natsTransport "github.com/thoughtworks/maeve-csms/manager/transport/nats"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestEmitterSendsOcpp16Message(t *testing.T) {
+	_, url := natsTransport.NewServer(t)
+
+	conn, err := nats.Connect(url)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	sub, err := conn.SubscribeSync("cs.out.ocpp16.cs001")
+	require.NoError(t, err)
+
+	emitter := natsTransport.NewEmitter(natsTransport.WithNatsUrl[natsTransport.Emitter](url))
+
+	msg := transport.Message{
+		MessageType:    transport.MessageTypeCall,
+		Action:         "TriggerMessage",
+		MessageId:      "1234",
+		RequestPayload: []byte(`{"requestedMessage":"Heartbeat"}`),
+	}
+	err = emitter.Emit(context.Background(), transport.OcppVersion16, "cs001", &msg)
+	require.NoError(t, err)
+
+	natsMsg, err := sub.NextMsg(5 * time.Second)
+	require.NoError(t, err)
+
+	var got transport.Message
+	err = json.Unmarshal(natsMsg.Data, &got)
+	require.NoError(t, err)
+	assert.Equal(t, transport.MessageTypeCall, got.MessageType)
+	assert.Equal(t, "TriggerMessage", got.Action)
+	assert.Equal(t, "1234", got.MessageId)
+	assert.JSONEq(t, `{"requestedMessage":"Heartbeat"}`, string(got.RequestPayload))
+}
+
+func TestEmitterSendsControlMessage(t *testing.T) {
+	_, url := natsTransport.NewServer(t)
+
+	conn, err := nats.Connect(url)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	sub, err := conn.SubscribeSync("cs.control.cs001")
+	require.NoError(t, err)
+
+	emitter := natsTransport.NewEmitter(natsTransport.WithNatsUrl[natsTransport.Emitter](url))
+	controlEmitter, ok := emitter.(transport.ControlEmitter)
+	require.True(t, ok)
+
+	err = controlEmitter.EmitControl(context.Background(), "cs001", &transport.ControlMessage{
+		Type: transport.ControlDisconnect,
+	})
+	require.NoError(t, err)
+
+	natsMsg, err := sub.NextMsg(5 * time.Second)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"type":"Disconnect"}`, string(natsMsg.Data))
+}
+
+func TestEmitterPropagatesTraceContext(t *testing.T) {
+	_, url := natsTransport.NewServer(t)
+
+	tracer, _ := testutil.GetTracer()
+
+	conn, err := nats.Connect(url)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	sub, err := conn.SubscribeSync("cs.out.ocpp201.cs001")
+	require.NoError(t, err)
+
+	emitter := natsTransport.NewEmitter(
+		natsTransport.WithNatsUrl[natsTransport.Emitter](url),
+		natsTransport.WithOtelTracer[natsTransport.Emitter](tracer))
+
+	ctx, span := tracer.Start(context.Background(), "test span")
+	defer span.End()
+
+	err = emitter.Emit(ctx, transport.OcppVersion201, "cs001", &transport.Message{
+		MessageType: transport.MessageTypeCall,
+		Action:      "Reset",
+		MessageId:   "1234",
+	})
+	require.NoError(t, err)
+
+	natsMsg, err := sub.NextMsg(5 * time.Second)
+	require.NoError(t, err)
+
+	msgCtx := otel.GetTextMapPropagator().Extract(context.Background(), propagation.HeaderCarrier(http.Header(natsMsg.Header)))
+	assert.Equal(t, span.SpanContext().TraceID(), trace.SpanContextFromContext(msgCtx).TraceID())
+}
+
+func TestEmitterRejectsInvalidChargeStationId(t *testing.T) {
+	emitter := natsTransport.NewEmitter()
+
+	err := emitter.Emit(context.Background(), transport.OcppVersion201, "cs.001", &transport.Message{
+		MessageType: transport.MessageTypeCall,
+		Action:      "Reset",
+		MessageId:   "1234",
+	})
+	assert.Error(t, err)
+}
@@ -0,0 +1,185 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package nats
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/nats-io/nats.go"
+	"github.com/thoughtworks/maeve-csms/manager/transport"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+	"golang.org/x/exp/slog"
+	"net/http"
+	"strings"
+)
+
+// Listener is an implementation of transport.Listener (and transport.PresenceListener)
+// that uses NATS as the transport. When subscribing to messages for all charge stations
+// the Listener joins a queue group so that each message is only delivered to one member
+// of the group.
+type Listener struct {
+	connectionDetails
+	natsGroup string
+	tracer    trace.Tracer
+}
+
+func NewListener(opts ...Opt[Listener]) *Listener {
+	l := new(Listener)
+	for _, opt := range opts {
+		opt(l)
+	}
+	ensureConnectionDefaults(&l.connectionDetails)
+	if l.natsGroup == "" {
+		l.natsGroup = "manager"
+	}
+	if l.tracer == nil {
+		l.tracer = noop.NewTracerProvider().Tracer("")
+	}
+	return l
+}
+
+func (l *Listener) Connect(_ context.Context, ocppVersion transport.OcppVersion, chargeStationId *string, handler transport.MessageHandler) (transport.Connection, error) {
+	var subject string
+	if chargeStationId != nil {
+		err := checkChargeStationId(*chargeStationId)
+		if err != nil {
+			return nil, err
+		}
+		subject = fmt.Sprintf("%s.in.%s.%s", l.natsPrefix, versionToken(ocppVersion), *chargeStationId)
+	} else {
+		subject = fmt.Sprintf("%s.in.%s.*", l.natsPrefix, versionToken(ocppVersion))
+	}
+
+	return l.subscribe(subject, chargeStationId == nil, func(ctx context.Context, span trace.Span, chargeStationId string, natsMsg *nats.Msg) {
+		var msg transport.Message
+		err := json.Unmarshal(natsMsg.Data, &msg)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "unable to unmarshal message")
+			slog.Warn("unable to unmarshal message", "err", err)
+			return
+		}
+
+		version, _ := strings.CutPrefix(string(ocppVersion), "ocpp")
+		span.SetAttributes(
+			attribute.String("ocpp.version", version),
+			attribute.String(fmt.Sprintf("%s.action", msg.MessageType), msg.Action),
+			semconv.MessagingMessageConversationID(msg.MessageId),
+		)
+
+		if msg.MessageType == transport.MessageTypeCallError {
+			span.SetAttributes(
+				attribute.String(fmt.Sprintf("%s.code", msg.MessageType), string(msg.ErrorCode)),
+				attribute.String(fmt.Sprintf("%s.description", msg.MessageType), msg.ErrorDescription))
+		}
+
+		handler.Handle(ctx, chargeStationId, &msg)
+	})
+}
+
+// ConnectPresence subscribes to the presence events published by the gateway on
+// <prefix>.presence.<cs-id>.
+func (l *Listener) ConnectPresence(_ context.Context, handler transport.PresenceHandler) (transport.Connection, error) {
+	subject := fmt.Sprintf("%s.presence.*", l.natsPrefix)
+
+	return l.subscribe(subject, true, func(ctx context.Context, span trace.Span, chargeStationId string, natsMsg *nats.Msg) {
+		var event transport.PresenceEvent
+		err := json.Unmarshal(natsMsg.Data, &event)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "unable to unmarshal presence event")
+			slog.Warn("unable to unmarshal presence event", "err", err)
+			return
+		}
+
+		span.SetAttributes(
+			attribute.String("presence.type", string(event.Type)),
+			attribute.String("presence.gateway_id", event.GatewayId))
+
+		handler.HandlePresence(ctx, chargeStationId, &event)
+	})
+}
+
+type msgHandler func(ctx context.Context, span trace.Span, chargeStationId string, natsMsg *nats.Msg)
+
+func (l *Listener) subscribe(subject string, shared bool, handler msgHandler) (transport.Connection, error) {
+	conn, err := connect(l.connectionDetails, fmt.Sprintf("%s-%s", l.natsGroup, subject))
+	if err != nil {
+		return nil, fmt.Errorf("connecting to NATS: %w", err)
+	}
+
+	cb := func(natsMsg *nats.Msg) {
+		ctx := context.Background()
+
+		// extract trace id
+		if natsMsg.Header != nil {
+			ctx = otel.GetTextMapPropagator().Extract(ctx, propagation.HeaderCarrier(http.Header(natsMsg.Header)))
+		}
+
+		// determine charge station id
+		subjectTokens := strings.Split(natsMsg.Subject, ".")
+		var chargeStationId = subjectTokens[len(subjectTokens)-1]
+
+		newCtx, span := l.tracer.Start(ctx,
+			fmt.Sprintf("%s receive", getSubjectPattern(natsMsg.Subject)),
+			trace.WithSpanKind(trace.SpanKindConsumer),
+			trace.WithAttributes(
+				semconv.MessagingSystem("nats"),
+				semconv.MessagingConsumerID(l.natsGroup),
+				semconv.MessagingMessagePayloadSizeBytes(len(natsMsg.Data)),
+				semconv.MessagingOperationKey.String("receive"),
+				attribute.String("csId", chargeStationId),
+			))
+		defer span.End()
+
+		handler(newCtx, span, chargeStationId, natsMsg)
+	}
+
+	if shared {
+		_, err = conn.QueueSubscribe(subject, l.natsGroup, cb)
+	} else {
+		_, err = conn.Subscribe(subject, cb)
+	}
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("subscribing to %s: %w", subject, err)
+	}
+
+	// ensure the subscription has been processed by the server before returning
+	err = conn.Flush()
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("subscribing to %s: %w", subject, err)
+	}
+
+	return &connection{conn: conn}, nil
+}
+
+type connection struct {
+	conn *nats.Conn
+}
+
+func (c *connection) Disconnect(_ context.Context) error {
+	if c.conn != nil {
+		// drain processes any messages that have already been received before closing
+		err := c.conn.Drain()
+		if err != nil {
+			return err
+		}
+		c.conn = nil
+	}
+	return nil
+}
+
+func getSubjectPattern(subject string) string {
+	tokens := strings.Split(subject, ".")
+	tokens[len(tokens)-1] = "*"
+	return strings.Join(tokens, ".")
+}
@@ -0,0 +1,245 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package nats_test
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thoughtworks/maeve-csms/manager/testutil"
+	"github.com/thoughtworks/maeve-csms/manager/transport"
+	natsTransport "github.com/thoughtworks/maeve-csms/manager/transport/nats"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type receivedMessage struct {
+	chargeStationId string
+	msg             *transport.Message
+	ctx             context.Context
+}
+
+func publish(t *testing.T, conn *nats.Conn, subject string, header nats.Header, msg *transport.Message) {
+	payload, err := json.Marshal(msg)
+	require.NoError(t, err)
+	err = conn.PublishMsg(&nats.Msg{Subject: subject, Header: header, Data: payload})
+	require.NoError(t, err)
+	require.NoError(t, conn.Flush())
+}
+
+func TestListenerReceivesMessagesForAllChargeStations(t *testing.T) {
+	_, url := natsTransport.NewServer(t)
+
+	conn, err := nats.Connect(url)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	rcvCh := make(chan receivedMessage, 1)
+	listener := natsTransport.NewListener(natsTransport.WithNatsUrl[natsTransport.Listener](url))
+	lConn, err := listener.Connect(context.Background(), transport.OcppVersion16, nil,
+		transport.MessageHandlerFunc(func(ctx context.Context, chargeStationId string, message *transport.Message) {
+			rcvCh <- receivedMessage{chargeStationId: chargeStationId, msg: message}
+		}))
+	require.NoError(t, err)
+	defer func() {
+		_ = lConn.Disconnect(context.Background())
+	}()
+
+	publish(t, conn, "cs.in.ocpp16.cs001", nil, &transport.Message{
+		MessageType:    transport.MessageTypeCall,
+		Action:         "Heartbeat",
+		MessageId:      "1234",
+		RequestPayload: []byte(`{}`),
+	})
+
+	select {
+	case got := <-rcvCh:
+		assert.Equal(t, "cs001", got.chargeStationId)
+		assert.Equal(t, transport.MessageTypeCall, got.msg.MessageType)
+		assert.Equal(t, "Heartbeat", got.msg.Action)
+		assert.Equal(t, "1234", got.msg.MessageId)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for message")
+	}
+}
+
+func TestListenerReceivesMessagesForSpecificChargeStation(t *testing.T) {
+	_, url := natsTransport.NewServer(t)
+
+	conn, err := nats.Connect(url)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	rcvCh := make(chan receivedMessage, 2)
+	listener := natsTransport.NewListener(natsTransport.WithNatsUrl[natsTransport.Listener](url))
+	csId := "cs002"
+	lConn, err := listener.Connect(context.Background(), transport.OcppVersion201, &csId,
+		transport.MessageHandlerFunc(func(ctx context.Context, chargeStationId string, message *transport.Message) {
+			rcvCh <- receivedMessage{chargeStationId: chargeStationId, msg: message}
+		}))
+	require.NoError(t, err)
+	defer func() {
+		_ = lConn.Disconnect(context.Background())
+	}()
+
+	publish(t, conn, "cs.in.ocpp201.cs001", nil, &transport.Message{
+		MessageType: transport.MessageTypeCall,
+		Action:      "Heartbeat",
+		MessageId:   "1",
+	})
+	publish(t, conn, "cs.in.ocpp201.cs002", nil, &transport.Message{
+		MessageType: transport.MessageTypeCall,
+		Action:      "Heartbeat",
+		MessageId:   "2",
+	})
+
+	select {
+	case got := <-rcvCh:
+		assert.Equal(t, "cs002", got.chargeStationId)
+		assert.Equal(t, "2", got.msg.MessageId)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for message")
+	}
+}
+
+func TestListenersInSameGroupShareMessages(t *testing.T) {
+	_, url := natsTransport.NewServer(t)
+
+	conn, err := nats.Connect(url)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	var count atomic.Int32
+	handler := transport.MessageHandlerFunc(func(ctx context.Context, chargeStationId string, message *transport.Message) {
+		count.Add(1)
+	})
+
+	for i := 0; i < 2; i++ {
+		listener := natsTransport.NewListener(natsTransport.WithNatsUrl[natsTransport.Listener](url))
+		lConn, err := listener.Connect(context.Background(), transport.OcppVersion16, nil, handler)
+		require.NoError(t, err)
+		defer func() {
+			_ = lConn.Disconnect(context.Background())
+		}()
+	}
+
+	for i := 0; i < 10; i++ {
+		publish(t, conn, "cs.in.ocpp16.cs001", nil, &transport.Message{
+			MessageType: transport.MessageTypeCall,
+			Action:      "Heartbeat",
+			MessageId:   "1234",
+		})
+	}
+
+	assert.Eventually(t, func() bool { return count.Load() == 10 }, 5*time.Second, 10*time.Millisecond)
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, int32(10), count.Load())
+}
+
+func TestListenerExtractsTraceContext(t *testing.T) {
+	_, url := natsTransport.NewServer(t)
+
+	tracer, exporter := testutil.GetTracer()
+
+	conn, err := nats.Connect(url)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	rcvCh := make(chan receivedMessage, 1)
+	listener := natsTransport.NewListener(
+		natsTransport.WithNatsUrl[natsTransport.Listener](url),
+		natsTransport.WithOtelTracer[natsTransport.Listener](tracer))
+	lConn, err := listener.Connect(context.Background(), transport.OcppVersion201, nil,
+		transport.MessageHandlerFunc(func(ctx context.Context, chargeStationId string, message *transport.Message) {
+			rcvCh <- receivedMessage{chargeStationId: chargeStationId, msg: message, ctx: ctx}
+		}))
+	require.NoError(t, err)
+	defer func() {
+		_ = lConn.Disconnect(context.Background())
+	}()
+
+	ctx, span := tracer.Start(context.Background(), "test span")
+	header := make(nats.Header)
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(http.Header(header)))
+	span.End()
+
+	publish(t, conn, "cs.in.ocpp201.cs001", header, &transport.Message{
+		MessageType: transport.MessageTypeCall,
+		Action:      "Heartbeat",
+		MessageId:   "1234",
+	})
+
+	select {
+	case got := <-rcvCh:
+		assert.Equal(t, span.SpanContext().TraceID(), trace.SpanContextFromContext(got.ctx).TraceID())
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for message")
+	}
+
+	assert.Eventually(t, func() bool { return len(exporter.GetSpans()) == 2 }, 5*time.Second, 10*time.Millisecond)
+	receiveSpan := exporter.GetSpans()[1]
+	testutil.AssertSpan(t, &receiveSpan, "cs.in.ocpp201.* receive", map[string]any{
+		"messaging.system":                     "nats",
+		"messaging.consumer.id":                "manager",
+		"messaging.message.payload_size_bytes": 43,
+		"messaging.operation":                  "receive",
+		"messaging.message.conversation_id":    "1234",
+		"csId":                                 "cs001",
+		"ocpp.version":                         "2.0.1",
+		"call.action":                          "Heartbeat",
+	})
+}
+
+func TestListenerReceivesPresenceEvents(t *testing.T) {
+	_, url := natsTransport.NewServer(t)
+
+	conn, err := nats.Connect(url)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	type receivedEvent struct {
+		chargeStationId string
+		event           *transport.PresenceEvent
+	}
+
+	rcvCh := make(chan receivedEvent, 1)
+	listener := natsTransport.NewListener(natsTransport.WithNatsUrl[natsTransport.Listener](url))
+	lConn, err := listener.ConnectPresence(context.Background(),
+		transport.PresenceHandlerFunc(func(ctx context.Context, chargeStationId string, event *transport.PresenceEvent) {
+			rcvCh <- receivedEvent{chargeStationId: chargeStationId, event: event}
+		}))
+	require.NoError(t, err)
+	defer func() {
+		_ = lConn.Disconnect(context.Background())
+	}()
+
+	payload, err := json.Marshal(transport.PresenceEvent{
+		Type:            transport.PresenceConnected,
+		GatewayId:       "gw001",
+		ConnectionId:    "abcdef",
+		Protocol:        "ocpp2.0.1",
+		RemoteAddr:      "127.0.0.1:1234",
+		SecurityProfile: 2,
+	})
+	require.NoError(t, err)
+	require.NoError(t, conn.Publish("cs.presence.cs001", payload))
+	require.NoError(t, conn.Flush())
+
+	select {
+	case got := <-rcvCh:
+		assert.Equal(t, "cs001", got.chargeStationId)
+		assert.Equal(t, transport.PresenceConnected, got.event.Type)
+		assert.Equal(t, "gw001", got.event.GatewayId)
+		assert.Equal(t, "abcdef", got.event.ConnectionId)
+		assert.Equal(t, 2, got.event.SecurityProfile)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for presence event")
+	}
+}
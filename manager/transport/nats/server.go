@@ -0,0 +1,31 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package nats
+
+import (
+	"github.com/nats-io/nats-server/v2/server"
+	"testing"
+	"time"
+)
+
+// NewServer starts an embedded NATS server, listening on a random port, that
+// can be used for testing. The server is shutdown when the test completes.
+func NewServer(t *testing.T) (*server.Server, string) {
+	ns, err := server.NewServer(&server.Options{
+		Host:   "127.0.0.1",
+		Port:   server.RANDOM_PORT,
+		NoLog:  true,
+		NoSigs: true,
+	})
+	if err != nil {
+		t.Fatalf("creating nats server: %v", err)
+	}
+
+	go ns.Start()
+	if !ns.ReadyForConnections(10 * time.Second) {
+		t.Fatalf("nats server not ready for connections")
+	}
+	t.Cleanup(ns.Shutdown)
+
+	return ns, ns.ClientURL()
+}
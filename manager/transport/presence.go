@@ -0,0 +1,44 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package transport
+
+import "context"
+
+// PresenceEventType identifies whether a charge station has connected to, or
+// disconnected from, a gateway.
+type PresenceEventType string
+
+const (
+	PresenceConnected    PresenceEventType = "Connected"
+	PresenceDisconnected PresenceEventType = "Disconnected"
+)
+
+// PresenceEvent is produced by the gateway when a charge station connects or disconnects.
+// The ConnectionId is unique to each websocket connection.
+type PresenceEvent struct {
+	Type            PresenceEventType `json:"type"`
+	GatewayId       string            `json:"gateway_id"`
+	ConnectionId    string            `json:"connection_id"`
+	Protocol        string            `json:"protocol"`
+	RemoteAddr      string            `json:"remote_addr"`
+	SecurityProfile int               `json:"security_profile"`
+}
+
+type PresenceHandler interface {
+	// HandlePresence handles a PresenceEvent for the charge station identified by the chargeStationId.
+	HandlePresence(ctx context.Context, chargeStationId string, event *PresenceEvent)
+}
+
+type PresenceHandlerFunc func(ctx context.Context, chargeStationId string, event *PresenceEvent)
+
+func (h PresenceHandlerFunc) HandlePresence(ctx context.Context, chargeStationId string, event *PresenceEvent) {
+	h(ctx, chargeStationId, event)
+}
+
+type PresenceListener interface {
+	// ConnectPresence establishes a connection to the broker and subscribes to receive presence
+	// events for all charge stations. The events are delivered to the provided PresenceHandler.
+	//
+	// Returns either a Connection on success or an error.
+	ConnectPresence(ctx context.Context, handler PresenceHandler) (Connection, error)
+}
@@ -4,7 +4,7 @@ function usage() {
     echo "Wrapper to extract Go code from JSON schema"
     echo
     echo "Usage: json-to-go.sh <ocpp-version> <type-name>"
-    echo "  where <ocpp-version>" is either ocpp16 or ocpp201
+    echo "  where <ocpp-version>" is one of ocpp16, ocpp201 or ocpp21
     echo "  where <type-name> is the name of the type you want to extract from the json schema"
     echo
     echo "NOTE: this requires gojsonschema to be installed:"
@@ -0,0 +1,96 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package adapter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/thoughtworks/maeve-csms/gateway/pipe"
+	"github.com/thoughtworks/maeve-csms/gateway/server"
+	"github.com/thoughtworks/maeve-csms/manager/transport"
+	"github.com/thoughtworks/maeve-csms/manager/transport/inmemory"
+	"golang.org/x/exp/slog"
+)
+
+// Bus is an implementation of the gateway's server.Bus that uses an in-memory
+// broker shared with the manager.
+type Bus struct {
+	broker *inmemory.Broker
+}
+
+func NewBus(broker *inmemory.Broker) *Bus {
+	return &Bus{
+		broker: broker,
+	}
+}
+
+func (b *Bus) Connect(_ context.Context, clientId, protocol string, _ server.PresenceEvent, handler server.BusHandler) (server.BusConnection, error) {
+	ocppVersion := transport.OcppVersion(protocol)
+
+	sub := b.broker.Subscribe(inmemory.OutTopic(ocppVersion, clientId), func(ctx context.Context, topic string, payload []byte) {
+		// route requests from the CSMS
+		var msg pipe.GatewayMessage
+		err := json.Unmarshal(payload, &msg)
+		if err != nil {
+			slog.Error("unmarshalling CSMS message", "err", err)
+			return
+		}
+		msg.Context = ctx
+
+		handler.HandleMessage(&msg)
+	})
+
+	controlSub := b.broker.Subscribe(inmemory.ControlTopic(clientId), func(ctx context.Context, topic string, payload []byte) {
+		var msg server.ControlMessage
+		err := json.Unmarshal(payload, &msg)
+		if err != nil {
+			slog.Error("unmarshalling CSMS control message", "err", err)
+			return
+		}
+
+		handler.HandleControl(&msg)
+	})
+
+	return &busConnection{
+		broker:      b.broker,
+		sub:         sub,
+		controlSub:  controlSub,
+		ocppVersion: ocppVersion,
+		clientId:    clientId,
+	}, nil
+}
+
+type busConnection struct {
+	broker      *inmemory.Broker
+	sub         *inmemory.Subscription
+	controlSub  *inmemory.Subscription
+	ocppVersion transport.OcppVersion
+	clientId    string
+}
+
+func (c *busConnection) Publish(ctx context.Context, msg *pipe.GatewayMessage) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("marshaling message for publication: %w", err)
+	}
+	c.broker.Publish(ctx, inmemory.InTopic(c.ocppVersion, c.clientId), data)
+	return nil
+}
+
+func (c *busConnection) PublishPresence(ctx context.Context, event server.PresenceEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("marshaling presence event: %w", err)
+	}
+	c.broker.Publish(ctx, inmemory.PresenceTopic(c.clientId), data)
+	return nil
+}
+
+func (c *busConnection) Disconnect(ctx context.Context) error {
+	err := c.controlSub.Disconnect(ctx)
+	if err != nil {
+		return err
+	}
+	return c.sub.Disconnect(ctx)
+}
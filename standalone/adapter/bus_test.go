@@ -0,0 +1,141 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package adapter_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thoughtworks/maeve-csms/gateway/ocpp"
+	"github.com/thoughtworks/maeve-csms/gateway/pipe"
+	"github.com/thoughtworks/maeve-csms/gateway/server"
+	"github.com/thoughtworks/maeve-csms/manager/transport"
+	"github.com/thoughtworks/maeve-csms/manager/transport/inmemory"
+	"github.com/thoughtworks/maeve-csms/standalone/adapter"
+	"testing"
+	"time"
+)
+
+type busHandler struct {
+	msgCh     chan *pipe.GatewayMessage
+	controlCh chan *server.ControlMessage
+}
+
+func (h busHandler) HandleMessage(msg *pipe.GatewayMessage) {
+	h.msgCh <- msg
+}
+
+func (h busHandler) HandleControl(msg *server.ControlMessage) {
+	h.controlCh <- msg
+}
+
+func (h busHandler) HandleError(error) {}
+
+func TestBusRoundTripWithManager(t *testing.T) {
+	broker := inmemory.NewBroker()
+
+	// the manager echoes each call back as a call result
+	emitter := inmemory.NewEmitter(broker)
+	conn, err := inmemory.NewListener(broker).Connect(context.Background(), transport.OcppVersion16, nil,
+		transport.MessageHandlerFunc(func(ctx context.Context, chargeStationId string, message *transport.Message) {
+			err := emitter.Emit(ctx, transport.OcppVersion16, chargeStationId, &transport.Message{
+				MessageType:     transport.MessageTypeCallResult,
+				Action:          message.Action,
+				MessageId:       message.MessageId,
+				ResponsePayload: message.RequestPayload,
+			})
+			require.NoError(t, err)
+		}))
+	require.NoError(t, err)
+	defer func() {
+		_ = conn.Disconnect(context.Background())
+	}()
+
+	handler := busHandler{msgCh: make(chan *pipe.GatewayMessage, 1)}
+	busConn, err := adapter.NewBus(broker).Connect(context.Background(), "cs001", "ocpp1.6", server.PresenceEvent{}, handler)
+	require.NoError(t, err)
+	defer func() {
+		_ = busConn.Disconnect(context.Background())
+	}()
+
+	err = busConn.Publish(context.Background(), &pipe.GatewayMessage{
+		MessageType:    ocpp.MessageTypeCall,
+		Action:         "Heartbeat",
+		MessageId:      "1234",
+		RequestPayload: []byte(`{}`),
+	})
+	require.NoError(t, err)
+
+	select {
+	case got := <-handler.msgCh:
+		assert.Equal(t, ocpp.MessageTypeCallResult, got.MessageType)
+		assert.Equal(t, "1234", got.MessageId)
+		assert.JSONEq(t, `{}`, string(got.ResponsePayload))
+		assert.NotNil(t, got.Context)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for response")
+	}
+}
+
+func TestBusPublishesPresence(t *testing.T) {
+	broker := inmemory.NewBroker()
+
+	eventCh := make(chan *transport.PresenceEvent, 1)
+	conn, err := inmemory.NewListener(broker).ConnectPresence(context.Background(),
+		transport.PresenceHandlerFunc(func(ctx context.Context, chargeStationId string, event *transport.PresenceEvent) {
+			assert.Equal(t, "cs001", chargeStationId)
+			eventCh <- event
+		}))
+	require.NoError(t, err)
+	defer func() {
+		_ = conn.Disconnect(context.Background())
+	}()
+
+	busConn, err := adapter.NewBus(broker).Connect(context.Background(), "cs001", "ocpp2.0.1", server.PresenceEvent{}, busHandler{})
+	require.NoError(t, err)
+	defer func() {
+		_ = busConn.Disconnect(context.Background())
+	}()
+
+	err = busConn.PublishPresence(context.Background(), server.PresenceEvent{
+		Type:         server.PresenceConnected,
+		GatewayId:    "standalone",
+		ConnectionId: "abc",
+		Protocol:     "ocpp2.0.1",
+	})
+	require.NoError(t, err)
+
+	select {
+	case got := <-eventCh:
+		assert.Equal(t, transport.PresenceConnected, got.Type)
+		assert.Equal(t, "standalone", got.GatewayId)
+		assert.Equal(t, "abc", got.ConnectionId)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for presence event")
+	}
+}
+
+func TestBusReceivesControlMessages(t *testing.T) {
+	broker := inmemory.NewBroker()
+
+	handler := busHandler{controlCh: make(chan *server.ControlMessage, 1)}
+	busConn, err := adapter.NewBus(broker).Connect(context.Background(), "cs001", "ocpp2.0.1", server.PresenceEvent{}, handler)
+	require.NoError(t, err)
+	defer func() {
+		_ = busConn.Disconnect(context.Background())
+	}()
+
+	err = inmemory.NewEmitter(broker).EmitControl(context.Background(), "cs001", &transport.ControlMessage{
+		Type:   transport.ControlDisconnect,
+		Reason: "credentials changed",
+	})
+	require.NoError(t, err)
+
+	select {
+	case got := <-handler.controlCh:
+		assert.Equal(t, server.ControlDisconnect, got.Type)
+		assert.Equal(t, "credentials changed", got.Reason)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for control message")
+	}
+}
@@ -0,0 +1,7 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package adapter connects the gateway to the manager when both are
+// running in the same process: the gateway's message bus is provided by
+// the manager's in-memory broker and the gateway's device registry reads
+// directly from the manager's store.
+package adapter
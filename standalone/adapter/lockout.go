@@ -0,0 +1,38 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package adapter
+
+import (
+	"context"
+	"github.com/thoughtworks/maeve-csms/gateway/registry"
+	"github.com/thoughtworks/maeve-csms/manager/services"
+)
+
+// AuthLockoutRegistry is an implementation of the gateway's registry.AuthLockoutRegistry
+// that uses the manager's AuthLockoutService rather than calling the manager API.
+type AuthLockoutRegistry struct {
+	Service services.AuthLockoutService
+}
+
+func (r AuthLockoutRegistry) RecordAuthFailure(clientId, remoteAddr string) (*registry.AuthLockout, error) {
+	status, err := r.Service.RecordFailure(context.Background(), clientId, remoteAddr)
+	if err != nil {
+		return nil, err
+	}
+	return newAuthLockout(status), nil
+}
+
+func (r AuthLockoutRegistry) LookupAuthLockout(clientId, remoteAddr string) (*registry.AuthLockout, error) {
+	status, err := r.Service.LookupStatus(context.Background(), clientId, remoteAddr)
+	if err != nil {
+		return nil, err
+	}
+	return newAuthLockout(status), nil
+}
+
+func newAuthLockout(status *services.AuthLockoutStatus) *registry.AuthLockout {
+	return &registry.AuthLockout{
+		ChargeStationLockedUntil: status.ChargeStationLockedUntil,
+		RemoteAddrLockedUntil:    status.RemoteAddrLockedUntil,
+	}
+}
@@ -0,0 +1,40 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package adapter_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thoughtworks/maeve-csms/manager/services"
+	"github.com/thoughtworks/maeve-csms/manager/store/inmemory"
+	"github.com/thoughtworks/maeve-csms/standalone/adapter"
+	clockTest "k8s.io/utils/clock/testing"
+	"testing"
+	"time"
+)
+
+func TestAuthLockoutRegistry(t *testing.T) {
+	now := time.Date(2023, 6, 15, 15, 0, 0, 0, time.UTC)
+	clock := clockTest.NewFakePassiveClock(now)
+	engine := inmemory.NewStore(clock)
+
+	reg := adapter.AuthLockoutRegistry{
+		Service: &services.StoreAuthLockoutService{
+			Store:                    engine,
+			Clock:                    clock,
+			MaxChargeStationFailures: 2,
+		},
+	}
+
+	got, err := reg.RecordAuthFailure("cs001", "192.168.1.1")
+	require.NoError(t, err)
+	assert.True(t, got.LockedUntil().IsZero())
+
+	got, err = reg.RecordAuthFailure("cs001", "192.168.1.1")
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(time.Minute), got.ChargeStationLockedUntil)
+
+	got, err = reg.LookupAuthLockout("cs001", "")
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(time.Minute), got.LockedUntil())
+}
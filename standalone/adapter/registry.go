@@ -0,0 +1,77 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package adapter
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"github.com/thoughtworks/maeve-csms/gateway/registry"
+	"github.com/thoughtworks/maeve-csms/manager/store"
+	"strings"
+)
+
+// Registry is an implementation of the gateway's registry.DeviceRegistry that
+// reads from the manager's store rather than calling the manager API.
+type Registry struct {
+	Engine store.Engine
+}
+
+func (r Registry) LookupChargeStation(clientId string) (*registry.ChargeStation, error) {
+	auth, err := r.Engine.LookupChargeStationAuth(context.Background(), clientId)
+	if err != nil {
+		return nil, fmt.Errorf("lookup charge station auth %s: %w", clientId, err)
+	}
+	if auth == nil {
+		return nil, nil
+	}
+	return &registry.ChargeStation{
+		ClientId:               clientId,
+		SecurityProfile:        registry.SecurityProfile(auth.SecurityProfile),
+		Base64SHA256Password:   auth.Base64SHA256Password,
+		InvalidUsernameAllowed: auth.InvalidUsernameAllowed,
+	}, nil
+}
+
+func (r Registry) LookupCertificate(certHash string) (*x509.Certificate, error) {
+	// the hash is stored in the URL-safe form used by the manager API
+	certHash = strings.Replace(certHash, "/", "_", -1)
+	certHash = strings.Replace(certHash, "+", "-", -1)
+
+	pemData, err := r.Engine.LookupCertificate(context.Background(), certHash)
+	if err != nil {
+		return nil, fmt.Errorf("lookup certificate %s: %w", certHash, err)
+	}
+	if pemData == "" {
+		return nil, nil
+	}
+
+	block, _ := pem.Decode([]byte(pemData))
+	if block == nil {
+		return nil, fmt.Errorf("no pem data found")
+	}
+	if block.Type != "CERTIFICATE" {
+		return nil, fmt.Errorf("no certificate found in PEM data")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+func (r Registry) LookupCertificateRevocation(certHash string) (*registry.CertificateRevocation, error) {
+	certHash = strings.TrimRight(certHash, "=")
+	certHash = strings.Replace(certHash, "/", "_", -1)
+	certHash = strings.Replace(certHash, "+", "-", -1)
+
+	revocation, err := r.Engine.LookupCertificateRevocation(context.Background(), certHash)
+	if err != nil {
+		return nil, fmt.Errorf("lookup certificate revocation %s: %w", certHash, err)
+	}
+	if revocation == nil {
+		return nil, nil
+	}
+	return &registry.CertificateRevocation{
+		ChargeStationId: revocation.ChargeStationId,
+		Reason:          revocation.Reason,
+		RevokedAt:       revocation.RevokedAt,
+	}, nil
+}
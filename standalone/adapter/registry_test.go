@@ -0,0 +1,115 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package adapter_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thoughtworks/maeve-csms/gateway/registry"
+	"github.com/thoughtworks/maeve-csms/manager/store"
+	"github.com/thoughtworks/maeve-csms/manager/store/inmemory"
+	"github.com/thoughtworks/maeve-csms/standalone/adapter"
+	"k8s.io/utils/clock"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func TestRegistryLookupChargeStation(t *testing.T) {
+	engine := inmemory.NewStore(clock.RealClock{})
+	err := engine.SetChargeStationAuth(context.Background(), "cs001", &store.ChargeStationAuth{
+		SecurityProfile:        store.TLSWithBasicAuth,
+		Base64SHA256Password:   "DEADBEEF",
+		InvalidUsernameAllowed: true,
+	})
+	require.NoError(t, err)
+
+	reg := adapter.Registry{Engine: engine}
+
+	got, err := reg.LookupChargeStation("cs001")
+	require.NoError(t, err)
+
+	want := &registry.ChargeStation{
+		ClientId:               "cs001",
+		SecurityProfile:        registry.TLSWithBasicAuth,
+		Base64SHA256Password:   "DEADBEEF",
+		InvalidUsernameAllowed: true,
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestRegistryLookupUnknownChargeStation(t *testing.T) {
+	reg := adapter.Registry{Engine: inmemory.NewStore(clock.RealClock{})}
+
+	got, err := reg.LookupChargeStation("unknown")
+	require.NoError(t, err)
+	assert.Nil(t, got)
+}
+
+func TestRegistryLookupCertificate(t *testing.T) {
+	engine := inmemory.NewStore(clock.RealClock{})
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "cs001"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	pemData := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+
+	err = engine.SetCertificate(context.Background(), string(pemData))
+	require.NoError(t, err)
+
+	reg := adapter.Registry{Engine: engine}
+
+	hash := sha256.Sum256(der)
+	got, err := reg.LookupCertificate(base64.RawStdEncoding.EncodeToString(hash[:]))
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, der, got.Raw)
+
+	got, err = reg.LookupCertificate("unknown")
+	require.NoError(t, err)
+	assert.Nil(t, got)
+}
+
+func TestRegistryLookupCertificateRevocation(t *testing.T) {
+	engine := inmemory.NewStore(clock.RealClock{})
+	revokedAt := time.Date(2023, 6, 15, 15, 0, 0, 0, time.UTC)
+	err := engine.SetCertificateRevocation(context.Background(), &store.CertificateRevocation{
+		CertificateHash: "ab-c_d",
+		ChargeStationId: "cs001",
+		Reason:          "key compromised",
+		RevokedAt:       revokedAt,
+	})
+	require.NoError(t, err)
+
+	reg := adapter.Registry{Engine: engine}
+
+	got, err := reg.LookupCertificateRevocation("ab+c/d=")
+	require.NoError(t, err)
+
+	want := &registry.CertificateRevocation{
+		ChargeStationId: "cs001",
+		Reason:          "key compromised",
+		RevokedAt:       revokedAt,
+	}
+	assert.Equal(t, want, got)
+
+	got, err = reg.LookupCertificateRevocation("unknown")
+	require.NoError(t, err)
+	assert.Nil(t, got)
+}
@@ -0,0 +1,29 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// rootCmd represents the base command when called without any subcommands
+var rootCmd = &cobra.Command{
+	Use:   "csms",
+	Short: "Charge Station Management System",
+	Long: `Provides a Charge Station Management System in a single process.
+The gateway accepts connections from charge stations and forwards
+messages to/from the manager through an in-memory transport so
+no MQTT broker is required. This is intended for small sites and
+for testing: use the separate gateway and manager to scale.`,
+}
+
+// Execute adds all child commands to the root command and sets flags appropriately.
+// This is called by main.main(). It only needs to happen once to the rootCmd.
+func Execute() {
+	err := rootCmd.Execute()
+	if err != nil {
+		os.Exit(1)
+	}
+}
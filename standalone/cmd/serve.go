@@ -0,0 +1,134 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"github.com/spf13/cobra"
+	gatewayServer "github.com/thoughtworks/maeve-csms/gateway/server"
+	"github.com/thoughtworks/maeve-csms/manager/config"
+	managerServer "github.com/thoughtworks/maeve-csms/manager/server"
+	"github.com/thoughtworks/maeve-csms/manager/services"
+	"github.com/thoughtworks/maeve-csms/standalone/adapter"
+	"golang.org/x/exp/slog"
+	"k8s.io/utils/clock"
+)
+
+var (
+	configFile        string
+	wsAddr            string
+	wssAddr           string
+	statusAddr        string
+	tlsServerCert     string
+	tlsServerKey      string
+	tlsTrustCert      []string
+	orgNames          []string
+	trustProxyHeaders bool
+	gatewayId         string
+	revocationCheck   string
+	crlFiles          []string
+)
+
+// serveCmd represents the serve command
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Start the gateway and manager servers",
+	Long: `Starts the gateway websocket server and the manager in the same
+process. The manager is configured using the same configuration file
+as the standalone manager, but the transport is always in-memory.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := config.DefaultConfig
+		if configFile != "" {
+			err := cfg.LoadFromFile(configFile)
+			if err != nil {
+				return err
+			}
+		}
+		cfg.Transport = config.TransportConfig{
+			Type: "in_memory",
+		}
+
+		settings, err := config.Configure(context.Background(), &cfg)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			err := settings.TracerProvider.Shutdown(context.Background())
+			if err != nil {
+				slog.Warn("shutting down tracer provider", "error", err)
+			}
+		}()
+
+		revocationPolicy, err := gatewayServer.ParseRevocationPolicy(revocationCheck)
+		if err != nil {
+			return err
+		}
+		deviceRegistry := adapter.Registry{Engine: settings.Storage}
+		revocationChecker, err := gatewayServer.NewRevocationChecker(
+			gatewayServer.WithRevocationPolicy(revocationPolicy),
+			gatewayServer.WithCRLFiles(crlFiles),
+			gatewayServer.WithRevocationRegistry(deviceRegistry))
+		if err != nil {
+			return err
+		}
+
+		websocketHandler := gatewayServer.NewWebsocketHandler(
+			gatewayServer.WithBus(adapter.NewBus(settings.MsgBroker)),
+			gatewayServer.WithDeviceRegistry(deviceRegistry),
+			gatewayServer.WithAuthLockoutRegistry(adapter.AuthLockoutRegistry{
+				Service: &services.StoreAuthLockoutService{
+					Store: settings.Storage,
+					Clock: clock.RealClock{},
+				},
+			}),
+			gatewayServer.WithOrgNames(orgNames),
+			gatewayServer.WithTrustProxyHeaders(trustProxyHeaders),
+			gatewayServer.WithGatewayId(gatewayId),
+			gatewayServer.WithRevocationChecker(revocationChecker),
+			gatewayServer.WithOtelTracer(settings.TracerProvider.Tracer("gateway")))
+
+		errCh := make(chan error, 1)
+
+		gatewayServer.New("ws", wsAddr, nil, websocketHandler).Start(errCh)
+		if wssAddr != "" {
+			tlsConfig, err := gatewayServer.LoadTLSConfig(tlsServerCert, tlsServerKey, tlsTrustCert)
+			if err != nil {
+				return err
+			}
+			tlsConfig.VerifyConnection = revocationChecker.VerifyConnection
+			gatewayServer.New("wss", wssAddr, tlsConfig, websocketHandler).Start(errCh)
+		}
+		gatewayServer.New("status", statusAddr, nil, gatewayServer.NewStatusHandler(nil)).Start(errCh)
+
+		return managerServer.Run(&cfg, settings, errCh)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(serveCmd)
+
+	serveCmd.Flags().StringVarP(&configFile, "config-file", "c", "/config/config.toml",
+		"The manager config file to use")
+	serveCmd.Flags().StringVarP(&wsAddr, "ws-addr", "a", "127.0.0.1:9310",
+		"The address that the insecure websocket server will listen on for connections, e.g. 127.0.0.1:9310")
+	serveCmd.Flags().StringVarP(&wssAddr, "wss-addr", "w", "",
+		"The address that the secure websocket server will listen on for connections, e.g. 127.0.0.1:9311")
+	serveCmd.Flags().StringVarP(&statusAddr, "status-addr", "s", "127.0.0.1:9312",
+		"The address that the status server will listen on for connections, e.g. 127.0.0.1:9312")
+	serveCmd.Flags().StringVar(&tlsServerCert, "tls-server-cert", "",
+		"A file that contains a PEM encoded certificate to use as the TLS server cert")
+	serveCmd.Flags().StringVarP(&tlsServerKey, "tls-server-key", "k", "",
+		"A file that contains a PEM encoded private key to use as the TLS server key")
+	serveCmd.Flags().StringArrayVarP(&tlsTrustCert, "tls-trust-cert", "t", []string{},
+		"A file that contains a PEM encoded certificate to add to the TLS trust store")
+	serveCmd.Flags().StringSliceVarP(&orgNames, "org-name", "o", []string{"Thoughtworks"},
+		"A comma-separated list of organisation names that are valid in client certificates")
+	serveCmd.Flags().StringVar(&revocationCheck, "revocation-check", "soft-fail",
+		"What happens when the revocation status of a client certificate cannot be determined, one of [off, soft-fail, hard-fail]")
+	serveCmd.Flags().StringArrayVar(&crlFiles, "crl-file", []string{},
+		"A file that contains a DER or PEM encoded CRL to check client certificates against, reloaded when it changes")
+	serveCmd.Flags().BoolVar(&trustProxyHeaders, "trust-proxy", false,
+		"Trust proxy headers when determining the client's TLS status")
+	serveCmd.Flags().StringVar(&gatewayId, "gateway-id", "",
+		"The identifier of this gateway instance that is included in presence events, defaults to the hostname")
+}